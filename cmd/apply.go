@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func applyCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "apply",
+		Short: "converge a lab to its topology and config templates",
+		Long: "treat the topology and the config templates as the intended state of the lab:\n" +
+			"deploy the missing and changed nodes, remove the nodes not in the topology\n" +
+			"and commit the config of the nodes whose rendered config changed or whose running config drifted\n" +
+			"reference: https://containerlab.dev/cmd/apply/",
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return applyFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().StringSliceVarP(&o.Config.TemplatePaths, "template-path", "p", o.Config.TemplatePaths,
+		"comma separated list of paths to search for templates, prefix a path with jinja2: to render its Jinja2 templates "+
+			"or with openconfig: to render its OpenConfig intent files")
+	c.Flags().StringSliceVarP(&o.Config.TemplateNames, "template-list", "l", o.Config.TemplateNames,
+		"comma separated list of template names to render")
+	c.Flags().UintVarP(&o.Deploy.MaxWorkers, "max-workers", "", o.Deploy.MaxWorkers,
+		"limit the maximum number of workers creating nodes and virtual wires")
+	c.Flags().BoolVarP(&o.Config.NetNS, "netns", "", o.Config.NetNS,
+		"connect to the nodes from the network namespace of the management network")
+
+	return c, nil
+}
+
+func applyFn(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	log.Info("Containerlab started", "version", Version)
+
+	// the nodes and links are converged by the incremental deployment
+	o.Deploy.Diff = true
+
+	c, containers, err := deployLab(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	removed, err := c.RemoveExtraNodes(ctx)
+	if err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		log.Info("Removed the nodes not defined in the topology", "nodes", strings.Join(removed, ", "))
+	}
+
+	nodes, err := pendingConfigNodes(ctx, o)
+	if err != nil {
+		return err
+	}
+
+	if len(nodes) == 0 {
+		log.Info("The node configs are in the intended state")
+	} else {
+		// only the nodes not in the intended state are committed, the nodes without
+		// config support are not part of the intended config state
+		o.Filter.LabelFilter = nodes
+		o.Config.SkipUnsupported = true
+
+		if err := configRun(cobraCmd, []string{"commit"}, o); err != nil {
+			return err
+		}
+	}
+
+	return PrintContainerInspect(containers, o)
+}
+
+// pendingConfigNodes returns the nodes whose config is not in the state declared by their templates,
+// that is the nodes the rendered config was never committed to, changed since it was committed,
+// or drifted from on the node.
+func pendingConfigNodes(ctx context.Context, o *Options) ([]string, error) {
+	c, err := clabcore.NewContainerLab(
+		append(netnsOptions(o),
+			clabcore.WithTimeout(o.Global.Timeout),
+			clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+			clabcore.WithDebug(o.Global.DebugCount > 0),
+		)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// the node filter is left untouched for the config run
+	ro := *o
+	ro.Filter = &FilterOptions{}
+	cfg := *o.Config
+	ro.Config = &cfg
+
+	err = validateFilter(c, &ro)
+	if err != nil {
+		return nil, err
+	}
+
+	allConfig, err := prepareConfig(c, &ro, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = clabcoreconfig.DialFromNetNS(ctx, c, allConfig, o.Config.NetNS)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := clabcoreconfig.ReadHistory(clabcoreconfig.HistoryPath(c.TopoPaths.TopologyLabDir()))
+	if err != nil {
+		return nil, err
+	}
+	applied := clabcoreconfig.AppliedHashes(history)
+
+	var nodes []string
+	for _, n := range ro.Filter.LabelFilter {
+		cs, ok := allConfig[n]
+		if !ok || len(cs.Data) == 0 || cs.Unsupported() != "" || cs.TargetNode.Maintenance {
+			continue
+		}
+
+		reason, err := clabcoreconfig.ApplyReason(cs, applied[n])
+		if err != nil {
+			// the commit reports the node failure when the node is not reachable
+			log.Warnf("%s: %s", n, cs.Redactor.Redact(err.Error()))
+			reason = "running config unknown"
+		}
+		if reason == "" {
+			log.Debugf("%s: config in the intended state", n)
+			continue
+		}
+
+		log.Info("Config not in the intended state", "node", n, "reason", reason)
+		nodes = append(nodes, n)
+	}
+
+	return nodes, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcorehostcheck "github.com/srl-labs/containerlab/core/hostcheck"
+	clabruntimedocker "github.com/srl-labs/containerlab/runtime/docker"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func checkHostCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "checkhost",
+		Short: "check the host is ready to deploy labs",
+		Long: "validate the docker engine, kernel modules, /dev/kvm, memory, hugepages, inotify limits\n" +
+			"and IP forwarding of the host against the requirements of the topology when one is given,\n" +
+			"printing the fixes for the failed checks\n" +
+			"reference: https://containerlab.dev/cmd/checkhost/",
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return checkHostFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().StringVarP(&o.CheckHost.Format, "format", "f", o.CheckHost.Format,
+		"output format. One of [table, json]")
+
+	return c, nil
+}
+
+func checkHostFn(cobraCmd *cobra.Command, o *Options) error {
+	if o.CheckHost.Format != "table" && o.CheckHost.Format != "json" {
+		return fmt.Errorf("output format %q is not supported, use 'table' or 'json'", o.CheckHost.Format)
+	}
+
+	var req *clabcorehostcheck.Requirements
+
+	if o.Global.TopologyFile != "" {
+		c, err := clabcore.NewContainerLab(
+			clabcore.WithTimeout(o.Global.Timeout),
+			clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+			clabcore.WithDebug(o.Global.DebugCount > 0),
+		)
+		if err != nil {
+			return err
+		}
+
+		err = c.ResolveLinks()
+		if err != nil {
+			return err
+		}
+
+		req = c.HostRequirements()
+	}
+
+	var opts []clabcorehostcheck.Option
+
+	runtime := o.Global.Runtime
+	if runtime == "" {
+		runtime = os.Getenv("CLAB_RUNTIME")
+	}
+	if runtime == "" || runtime == clabruntimedocker.RuntimeName {
+		opts = append(opts, clabcorehostcheck.WithDockerVersion(clabcorehostcheck.DockerEngineVersion))
+	}
+
+	results := clabcorehostcheck.NewChecker(opts...).Run(cobraCmd.Context(), req)
+
+	if o.CheckHost.Format == "json" {
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		printHostChecks(results)
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Status == clabcorehostcheck.StatusFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d host checks failed", failed, len(results))
+	}
+
+	return nil
+}
+
+func printHostChecks(results []*clabcorehostcheck.Result) {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Check", "Status", "Detail", "Fix"})
+
+	for _, r := range results {
+		status := string(r.Status)
+		switch r.Status {
+		case clabcorehostcheck.StatusOK:
+			status = text.FgGreen.Sprint(status)
+		case clabcorehostcheck.StatusWarn:
+			status = text.FgYellow.Sprint(status)
+		case clabcorehostcheck.StatusFail:
+			status = text.FgRed.Sprint(status)
+		}
+
+		table.AppendRow(tableWriter.Row{r.Check, status, r.Detail, r.Fix})
+	}
+
+	table.Render()
+}
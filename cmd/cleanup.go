@@ -0,0 +1,84 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"os"
+
+	"github.com/charmbracelet/log"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+func cleanupCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "cleanup",
+		Short: "remove the resources left behind by crashed runs",
+		Long: "find and remove the lab containers that never started or lost their lab directory,\n" +
+			"the veth interfaces of interrupted deployments and the unused management networks and bridges,\n" +
+			"leaving the labs being deployed alone\n" +
+			"reference: https://containerlab.dev/cmd/cleanup/",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return cleanupFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().BoolVarP(&o.Cleanup.DryRun, "dry-run", "", o.Cleanup.DryRun,
+		"list the orphaned resources without removing them")
+
+	return c, nil
+}
+
+func cleanupFn(cobraCmd *cobra.Command, o *Options) error {
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithRuntime(
+			o.Global.Runtime,
+			&clabruntime.RuntimeConfig{
+				Debug:   o.Global.DebugCount > 0,
+				Timeout: o.Global.Timeout,
+			},
+		),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	res, err := c.Cleanup(cobraCmd.Context(), o.Cleanup.DryRun)
+	if err != nil {
+		return err
+	}
+
+	if len(res) == 0 {
+		log.Info("No orphaned resources found")
+		return nil
+	}
+
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Type", "Name", "Reason"})
+	for _, r := range res {
+		table.AppendRow(tableWriter.Row{r.Type, r.Name, r.Reason})
+	}
+
+	table.Render()
+
+	if o.Cleanup.DryRun {
+		log.Info("Dry run, the orphaned resources were not removed", "count", len(res))
+	}
+
+	return nil
+}
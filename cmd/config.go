@@ -1,15 +1,27 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
 	clabcore "github.com/srl-labs/containerlab/core"
 	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
 	"github.com/srl-labs/containerlab/core/config/transport"
-	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabcoreconsole "github.com/srl-labs/containerlab/core/console"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabutils "github.com/srl-labs/containerlab/utils"
 
 	"github.com/charmbracelet/log"
 )
@@ -20,7 +32,7 @@ func configCmd(o *Options) (*cobra.Command, error) {
 		Short:        "configure a lab",
 		Long:         "configure a lab based on templates and variables from the topology definition file\nreference: https://containerlab.dev/cmd/config/",
 		Aliases:      []string{"conf"},
-		ValidArgs:    []string{"commit", "send", "compare", "template"},
+		ValidArgs:    []string{"commit", "send", "compare", "template", "verify", "run"},
 		SilenceUsage: true,
 		RunE: func(cobraCmd *cobra.Command, args []string) error {
 			return configRun(cobraCmd, args, o)
@@ -28,21 +40,28 @@ func configCmd(o *Options) (*cobra.Command, error) {
 	}
 
 	c.Flags().StringSliceVarP(
-		&clabcoreconfig.TemplatePaths,
+		&o.Config.TemplatePaths,
 		"template-path",
 		"p",
-		[]string{},
-		"comma separated list of paths to search for templates",
+		o.Config.TemplatePaths,
+		"comma separated list of paths to search for templates, prefix a path with jinja2: to render its Jinja2 templates "+
+			"or with openconfig: to render its OpenConfig intent files",
 	)
 
 	c.Flags().StringSliceVarP(
-		&clabcoreconfig.TemplateNames,
+		&o.Config.TemplateNames,
 		"template-list",
 		"l",
-		[]string{},
+		o.Config.TemplateNames,
 		"comma separated list of template names to render",
 	)
 
+	c.Flags().StringVarP(&o.Config.Profile, "profile", "", o.Config.Profile,
+		"variables profile, declared in the profiles variable of the nodes, merged over the node variables")
+
+	c.Flags().IntVarP(&o.Config.RenderWorkers, "render-workers", "", o.Config.RenderWorkers,
+		"number of nodes rendered in parallel. 0 means the number of CPUs")
+
 	c.Flags().StringSliceVarP(
 		&o.Filter.LabelFilter,
 		"filter",
@@ -59,6 +78,55 @@ func configCmd(o *Options) (*cobra.Command, error) {
 		"comma separated list of nodes to include",
 	)
 
+	c.Flags().BoolVarP(&o.Config.PromptCredentials, "prompt-credentials", "", o.Config.PromptCredentials,
+		"prompt for the credentials of node kinds without known credentials, asked once per kind")
+
+	c.Flags().BoolVarP(&o.Config.SkipUnsupported, "skip-unsupported", "", o.Config.SkipUnsupported,
+		"skip nodes of kinds without templates or config transport instead of failing")
+
+	c.Flags().BoolVarP(&o.Config.Provenance, "provenance", "", o.Config.Provenance,
+		"add provenance comments (template, vars hash and render time) to the rendered config and the commits")
+
+	c.Flags().StringSliceVarP(&o.Config.RedactPatterns, "redact", "", o.Config.RedactPatterns,
+		"regular expressions of the secrets masked in the logs, transcripts and reports, in addition to "+
+			"passwords, communities and keys. The first group of a match is masked, or the whole match")
+
+	c.Flags().DurationVarP(&o.Config.NodeTimeout, "node-timeout", "", o.Config.NodeTimeout,
+		"time to apply the config to a single node, nodes exceeding it are marked failed. 0 means no limit")
+
+	c.Flags().DurationVarP(&o.Config.Deadline, "deadline", "", o.Config.Deadline,
+		"time to apply the config to all nodes, nodes not done by then are marked failed. 0 means no limit")
+
+	c.Flags().BoolVarP(&o.Config.Verify, "verify", "", o.Config.Verify,
+		"verify the applied config via gNMI subscriptions to the paths listed in the node's config.verify")
+
+	c.Flags().DurationVarP(&o.Config.VerifyTimeout, "verify-timeout", "", o.Config.VerifyTimeout,
+		"time to wait for the verified paths to converge to the expected values")
+
+	c.Flags().BoolVarP(&o.Config.RollbackOnFailure, "rollback-on-failure", "", o.Config.RollbackOnFailure,
+		"save a config checkpoint on the nodes before the commit, verify the applied config and revert "+
+			"all nodes to their checkpoint when a node fails the commit or the verification")
+
+	c.Flags().StringVarP(&o.Config.JUnitFile, "junit", "", o.Config.JUnitFile,
+		"write the verification results as a JUnit XML report to the given file")
+
+	c.Flags().StringVarP(&o.Config.ArtifactsDir, "artifacts-dir", "", o.Config.ArtifactsDir,
+		"write the rendered config, session transcripts, config diffs and reports of the run to the given dir")
+
+	c.Flags().BoolVarP(&o.Config.NetNS, "netns", "", o.Config.NetNS,
+		"connect to the nodes from their network namespaces, for hosts without a route to the management network")
+
+	c.Flags().StringVarP(&o.Config.Dialer, "dialer", "", o.Config.Dialer,
+		"connect to the nodes through a socks5://[user:password@]host:port proxy, from a source:<interface> "+
+			"or from a netns:<name> network namespace, for labs reachable only over a VPN or a jump host")
+
+	c.Flags().BoolVarP(&o.Config.SkipVersionCheck, "skip-version-check", "", o.Config.SkipVersionCheck,
+		"configure the nodes without checking their software version against the min-version/version of the templates")
+
+	c.Flags().StringArrayVarP(&o.Config.Hooks, "hook", "", o.Config.Hooks,
+		"plugin hook run for every node as <stage>=<command>, with the stage one of pre-render, post-render "+
+			"and pre-push. The command gets the node as JSON on stdin and may reply with JSON on stdout. Repeatable")
+
 	c.Flags().SortFlags = false
 
 	err := c.MarkFlagDirname("template-path")
@@ -104,6 +172,135 @@ func configSubCmds(c *cobra.Command, o *Options) {
 	c.AddCommand(compareC)
 	compareC.Flags().AddFlagSet(c.Flags())
 
+	verifyC := &cobra.Command{
+		Use:          "verify",
+		Short:        "verify the configuration state of a running lab via gNMI",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected arguments: %s", args)
+			}
+
+			return configRun(cobraCmd, []string{"verify"}, o)
+		},
+	}
+
+	c.AddCommand(verifyC)
+	verifyC.Flags().AddFlagSet(c.Flags())
+
+	driftC := &cobra.Command{
+		Use:   "drift",
+		Short: "detect out-of-band configuration changes",
+		Long: "periodically compare the running configs of the nodes with the configs\n" +
+			"captured after the last 'config commit' and report the nodes changed out-of-band",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected arguments: %s", args)
+			}
+
+			return configDrift(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(driftC)
+	driftC.Flags().AddFlagSet(c.Flags())
+	driftC.Flags().DurationVarP(&o.Config.DriftInterval, "interval", "", o.Config.DriftInterval,
+		"interval between the drift checks, 0 runs a single check")
+
+	exportC := &cobra.Command{
+		Use:   "export",
+		Short: "export the lab configs for offline analysis",
+		Long: "export the rendered or saved node configs together with the lab topology\n" +
+			"in the layout expected by offline analysis tools, e.g. a Batfish snapshot,\n" +
+			"or the rendered configs as an Ansible playbook for the existing automation tooling",
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected arguments: %s", args)
+			}
+
+			return configExport(o)
+		},
+	}
+
+	c.AddCommand(exportC)
+	exportC.Flags().AddFlagSet(c.Flags())
+	exportC.Flags().StringVarP(&o.Config.ExportFormat, "format", "", o.Config.ExportFormat,
+		"export format, one of: batfish, ansible")
+	exportC.Flags().StringVarP(&o.Config.ExportPath, "output", "o", o.Config.ExportPath,
+		"output directory, defaults to the <format> directory in the lab directory")
+	exportC.Flags().BoolVarP(&o.Config.ExportSaved, "saved", "", o.Config.ExportSaved,
+		"export the configs saved with 'containerlab save' instead of the rendered templates")
+	exportC.Flags().SortFlags = false
+
+	c.AddCommand(configHistoryCmd(o))
+	c.AddCommand(configReplayCmd(o))
+
+	diffC := configDiffCmd(o)
+	c.AddCommand(diffC)
+	diffC.Flags().AddFlagSet(c.Flags())
+
+	collectC := &cobra.Command{
+		Use:   "collect",
+		Short: "collect troubleshooting show commands from the lab nodes",
+		Long: "run the curated show commands of a bundle on every node of the lab\n" +
+			"and store their outputs in a timestamped archive, a show tech of the whole lab",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected arguments: %s", args)
+			}
+
+			return configCollect(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(collectC)
+	collectC.Flags().AddFlagSet(c.Flags())
+	collectC.Flags().StringVarP(&o.Config.CollectBundle, "bundle", "", o.Config.CollectBundle,
+		"bundle of show commands collected, one of: support")
+	collectC.Flags().StringVarP(&o.Config.CollectPath, "output", "o", o.Config.CollectPath,
+		"directory of the archive, defaults to the lab directory")
+	collectC.Flags().SortFlags = false
+
+	runC := &cobra.Command{
+		Use:   "run",
+		Short: "run a show command on the lab nodes",
+		Long: "run a show command on all nodes of the lab concurrently and compare\n" +
+			"the key fields of their outputs, parsed per kind, in a single table",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected arguments: %s", args)
+			}
+
+			return configShow(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(runC)
+	runC.Flags().AddFlagSet(c.Flags())
+	runC.Flags().StringVarP(&o.Config.ShowCommand, "show", "", o.Config.ShowCommand,
+		"show command run on the nodes, e.g. \"show version\"")
+	runC.Flags().SortFlags = false
+	_ = runC.MarkFlagRequired("show")
+
+	shellC := &cobra.Command{
+		Use:   "shell <node>",
+		Short: "open an interactive config session to a node",
+		Long: "open the config session of a node and send the commands typed in,\n" +
+			"using the same kind-aware transport as 'config commit', to try out the template commands",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return configShell(cobraCmd, args[0], o)
+		},
+	}
+
+	c.AddCommand(shellC)
+	shellC.Flags().AddFlagSet(c.Flags())
+
 	templateC := &cobra.Command{
 		Use:          "template",
 		Short:        "render a template",
@@ -122,30 +319,51 @@ func configSubCmds(c *cobra.Command, o *Options) {
 	templateC.Flags().SortFlags = false
 }
 
-func configRun(_ *cobra.Command, args []string, o *Options) error {
+func configRun(cobraCmd *cobra.Command, args []string, o *Options) error {
 	var err error
 
-	transport.DebugCount = o.Global.DebugCount
-	clabcoreconfig.DebugCount = o.Global.DebugCount
+	ctx := cobraCmd.Context()
 
 	c, err := clabcore.NewContainerLab(
-		clabcore.WithTimeout(o.Global.Timeout),
-		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
-		clabcore.WithNodeFilter(o.Filter.NodeFilter),
-		clabcore.WithDebug(o.Global.DebugCount > 0),
+		append(netnsOptions(o),
+			clabcore.WithTimeout(o.Global.Timeout),
+			clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+			clabcore.WithNodeFilter(o.Filter.NodeFilter),
+			clabcore.WithDebug(o.Global.DebugCount > 0),
+		)...,
 	)
 	if err != nil {
 		return err
 	}
 
-	err = validateFilter(c.Nodes, o)
+	err = validateFilter(c, o)
+	if err != nil {
+		return err
+	}
+
+	events, err := clabcoreevents.NewEmitter(o.Global.EventsURL)
+	if err != nil {
+		return err
+	}
+
+	hooks, err := clabcoreconfig.ParseHooks(o.Config.Hooks)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, true)
+	if err != nil {
+		return err
+	}
+
+	err = clabcoreconfig.DialFromNetNS(ctx, c, allConfig, o.Config.NetNS)
 	if err != nil {
 		return err
 	}
 
-	allConfig := clabcoreconfig.PrepareVars(c)
+	addProvenance(allConfig, o)
 
-	err = clabcoreconfig.RenderAll(allConfig)
+	artifacts, err := clabcoreconfig.NewArtifacts(o.Config.ArtifactsDir)
 	if err != nil {
 		return err
 	}
@@ -160,6 +378,8 @@ func configRun(_ *cobra.Command, args []string, o *Options) error {
 		switch action {
 		case "commit":
 
+		case "verify":
+			o.Config.Verify = true
 		case "compare", "send":
 			return fmt.Errorf("%s not implemented yet", action)
 		default:
@@ -167,9 +387,55 @@ func configRun(_ *cobra.Command, args []string, o *Options) error {
 		}
 	}
 
-	var wg sync.WaitGroup
+	active, maintenance := activeNodes(allConfig, o.Filter.LabelFilter)
+
+	nodes, skipped, err := supportedNodes(allConfig, active, action, o.Config.SkipUnsupported)
+	if err != nil {
+		return err
+	}
+
+	if o.Config.PromptCredentials {
+		err = promptCredentials(allConfig, nodes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if action != "verify" && !o.Config.SkipVersionCheck {
+		err = checkVersions(allConfig, nodes)
+		if err != nil {
+			return err
+		}
+	}
+
+	// the checkpoints are saved before the deadline starts
+	var rollback *clabcoreconfig.Rollback
+	if o.Config.RollbackOnFailure && action == "commit" {
+		o.Config.Verify = true
+
+		rollback, err = saveCheckpoints(c, allConfig, nodes)
+		if err != nil {
+			return err
+		}
+	}
+
+	if o.Config.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, o.Config.Deadline)
+		defer cancel()
+	}
+
+	var (
+		wg        sync.WaitGroup
+		m         sync.Mutex
+		results   []*clabcoreconfig.VerifyResult
+		history   = newHistoryEntry(c, action, o.Config.TemplatePaths)
+		durations = map[string]time.Duration{}
+		sendErrs  = map[string]error{}
+	)
 	deploy := func(n string) {
 		defer wg.Done()
+		defer clabcoreconsole.Flush(n)
 
 		cs, ok := allConfig[n]
 		if !ok {
@@ -177,13 +443,74 @@ func configRun(_ *cobra.Command, args []string, o *Options) error {
 			return
 		}
 
-		err = clabcoreconfig.Send(cs, action)
-		if err != nil {
-			log.Warnf("%s: %s", cs.TargetNode.ShortName, err)
+		if action != "verify" {
+			ev := &clabcoreevents.Event{
+				Type:   clabcoreevents.ConfigResult,
+				Lab:    c.Config.Name,
+				Node:   cs.TargetNode.ShortName,
+				Status: clabcoreevents.StatusOK,
+			}
+
+			nodeCtx := ctx
+			if o.Config.NodeTimeout > 0 {
+				var cancel context.CancelFunc
+				nodeCtx, cancel = context.WithTimeout(ctx, o.Config.NodeTimeout)
+				defer cancel()
+			}
+
+			start := time.Now()
+
+			// the pre-push hooks change the config saved in the artifacts and sent to the node
+			err := hooks.Run(nodeCtx, clabcoreconfig.HookPrePush, cs)
+			if err == nil {
+				if err := artifacts.SaveRendered(cs); err != nil {
+					log.Warnf("%s: failed to save the rendered config artifacts: %s", cs.TargetNode.ShortName, err)
+				}
+				closeTranscript, terr := artifacts.OpenTranscript(cs)
+				if terr != nil {
+					log.Warnf("%s: failed to open the transcript artifact: %s", cs.TargetNode.ShortName, terr)
+					closeTranscript = func() {}
+				}
+
+				err = clabcoreconfig.Send(nodeCtx, cs, action)
+				closeTranscript()
+			}
+			cs.Session.Error(err)
+			m.Lock()
+			durations[n] = time.Since(start)
+			addHistoryNode(history, cs, err)
+			if err != nil {
+				sendErrs[n] = err
+			}
+			m.Unlock()
+			if err != nil {
+				msg := cs.Redactor.Redact(err.Error())
+				log.Warn("Failed to send the config", "node", cs.TargetNode.ShortName, "phase", action, "err", msg)
+				ev.Status = clabcoreevents.StatusFailed
+				ev.Message = msg
+			} else if action == "commit" && len(cs.Data) > 0 && rollback == nil {
+				recordCommit(c, cs, artifacts)
+			}
+
+			events.Emit(ctx, ev)
+		}
+
+		if !o.Config.Verify || (len(cs.TargetNode.Config.GetVerify()) == 0 && len(cs.TargetNode.Config.GetTests()) == 0) {
+			return
+		}
+
+		res := clabcoreconfig.Verify(ctx, cs, o.Config.VerifyTimeout)
+		m.Lock()
+		results = append(results, res)
+		if !res.Passed() {
+			addHistoryNode(history, cs, fmt.Errorf("verification failed"))
+		} else if action == "verify" {
+			addHistoryNode(history, cs, nil)
 		}
+		m.Unlock()
 	}
-	wg.Add(len(o.Filter.LabelFilter))
-	for _, node := range o.Filter.LabelFilter {
+	wg.Add(len(nodes))
+	for _, node := range nodes {
 		// On debug this will not be executed concurrently
 		if log.GetLevel() == (log.DebugLevel) {
 			deploy(node)
@@ -193,54 +520,960 @@ func configRun(_ *cobra.Command, args []string, o *Options) error {
 	}
 	wg.Wait()
 
-	return nil
-}
+	if len(skipped) > 0 {
+		log.Warnf("Skipped nodes without config support: %s", strings.Join(skipped, ", "))
+	}
 
-func configTemplate(o *Options) error {
-	var err error
+	for _, n := range skipped {
+		history.Nodes = append(history.Nodes, &clabcoreconfig.HistoryNode{
+			Node:   n,
+			Status: clabcoreconfig.HistoryStatusSkipped,
+		})
+	}
+	for _, n := range maintenance {
+		history.Nodes = append(history.Nodes, &clabcoreconfig.HistoryNode{
+			Node:    n,
+			Status:  clabcoreconfig.HistoryStatusSkipped,
+			Message: "node in maintenance",
+		})
+	}
 
-	clabcoreconfig.DebugCount = o.Global.DebugCount
+	if action != "verify" {
+		logConfigResults(history, durations)
+	}
 
-	c, err := clabcore.NewContainerLab(
-		clabcore.WithTimeout(o.Global.Timeout),
-		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
-		clabcore.WithDebug(o.Global.DebugCount > 0),
-	)
-	if err != nil {
-		return err
+	var rollbackErr error
+	if rollback != nil {
+		rollbackErr = finishRollback(c, rollback, history, allConfig, artifacts)
 	}
 
-	err = validateFilter(c.Nodes, o)
+	err = clabcoreconfig.AppendHistory(clabcoreconfig.HistoryPath(c.TopoPaths.TopologyLabDir()), history)
 	if err != nil {
-		return err
+		log.Warnf("failed to record the config run in the lab history: %v", err)
 	}
 
-	allConfig := clabcoreconfig.PrepareVars(c)
-	if o.Config.TemplateVarOnly {
-		for _, n := range o.Filter.LabelFilter {
-			conf := allConfig[n]
-			conf.Print(true, false)
+	if err := artifacts.SaveReport(history); err != nil {
+		log.Warnf("failed to write the run report to the artifacts dir: %v", err)
+	}
+
+	var verifyErr error
+	if o.Config.Verify {
+		junitFiles := []string{o.Config.JUnitFile}
+		if artifacts != nil {
+			junitFiles = append(junitFiles, artifacts.JUnitPath())
+		}
+		verifyErr = verifySummary(results, c.Config.Name, junitFiles...)
+	}
+
+	// the failures to send the config take precedence over the verification failures
+	sendErr := sendFailure(sendErrs)
+	switch {
+	case rollbackErr != nil:
+		code := ExitConfigVerify
+		if sendErr != nil {
+			code = sendErr.Code
+		}
+		return &ExitError{Code: code, Err: rollbackErr}
+	case sendErr != nil:
+		return sendErr
+	}
+
+	return verifyErr
+}
+
+// recordCommit records the config committed to the node: the applied state, the config diff artifact
+// and the undo config.
+func recordCommit(c *clabcore.CLab, cs *clabcoreconfig.NodeConfig, artifacts *clabcoreconfig.Artifacts) {
+	// keep the applied state as the reference for the drift detection
+	previous, _ := os.ReadFile(clabcoreconfig.AppliedConfigPath(cs))
+	if err := clabcoreconfig.SaveAppliedState(cs); err != nil {
+		log.Warnf("%s: failed to save the applied config: %s", cs.TargetNode.ShortName, err)
+	} else if err := artifacts.SaveDiff(cs, string(previous)); err != nil {
+		log.Warnf("%s: failed to save the config diff artifact: %s", cs.TargetNode.ShortName, err)
+	}
+	if err := clabcoreconfig.RecordUndo(c.TopoPaths.TopologyLabDir(), cs); err != nil {
+		log.Warnf("%s: failed to record the undo config: %s", cs.TargetNode.ShortName, err)
+	}
+}
+
+// saveCheckpoints saves the rollback checkpoint on the nodes of the run,
+// all nodes must support the config checkpoints.
+func saveCheckpoints(c *clabcore.CLab, allConfig map[string]*clabcoreconfig.NodeConfig,
+	nodes []string,
+) (*clabcoreconfig.Rollback, error) {
+	configs := make([]*clabcoreconfig.NodeConfig, 0, len(nodes))
+	for _, n := range nodes {
+		if cs, ok := allConfig[n]; ok {
+			configs = append(configs, cs)
+		}
+	}
+
+	if err := clabcoreconfig.CheckRollback(configs); err != nil {
+		return nil, err
+	}
+
+	r := clabcoreconfig.NewRollback(c.Config.Name, time.Now())
+	if err := r.Save(configs); err != nil {
+		return nil, fmt.Errorf("failed to save the config checkpoints, no config was sent: %w", err)
+	}
+
+	return r, nil
+}
+
+// finishRollback reverts all nodes to their checkpoint when a node failed the commit or the verification,
+// the commit is recorded and the checkpoints are deleted otherwise.
+func finishRollback(c *clabcore.CLab, r *clabcoreconfig.Rollback, history *clabcoreconfig.HistoryEntry,
+	allConfig map[string]*clabcoreconfig.NodeConfig, artifacts *clabcoreconfig.Artifacts,
+) error {
+	var failed []string
+	for _, n := range history.Nodes {
+		if n.Status == clabcoreconfig.HistoryStatusFailed {
+			failed = append(failed, n.Node)
+		}
+	}
+	sort.Strings(failed)
+
+	if len(failed) == 0 {
+		for _, n := range history.Nodes {
+			if cs, ok := allConfig[n.Node]; ok && n.Status == clabcoreconfig.HistoryStatusOK && len(cs.Data) > 0 {
+				recordCommit(c, cs, artifacts)
+			}
 		}
+		r.Release()
+
 		return nil
 	}
 
-	err = clabcoreconfig.RenderAll(allConfig)
-	if err != nil {
+	log.Error("Rolling back the config of all nodes", "failed", strings.Join(failed, ", "), "checkpoint", r.Name)
+
+	if err := r.Revert(); err != nil {
 		return err
 	}
 
-	for _, n := range o.Filter.LabelFilter {
-		allConfig[n].Print(false, true)
+	history.RolledBack("rolled back after the failure of " + strings.Join(failed, ", "))
+
+	return fmt.Errorf("the config failed on nodes %s and was rolled back on all nodes", strings.Join(failed, ", "))
+}
+
+// logConfigResults logs the result of every node of the config run in the order of the node names,
+// the logs of the nodes configured concurrently are interleaved.
+// durations holds the time it took to send the config to the nodes.
+func logConfigResults(e *clabcoreconfig.HistoryEntry, durations map[string]time.Duration) {
+	sort.Slice(e.Nodes, func(i, j int) bool { return e.Nodes[i].Node < e.Nodes[j].Node })
+
+	for _, n := range e.Nodes {
+		kvs := []any{"node", n.Node, "phase", e.Action}
+		if d, ok := durations[n.Node]; ok {
+			kvs = append(kvs, "duration", d.Round(time.Millisecond))
+		}
+
+		switch n.Status {
+		case clabcoreconfig.HistoryStatusFailed:
+			log.Error("Config failed", append(kvs, "err", n.Message)...)
+		case clabcoreconfig.HistoryStatusSkipped:
+			log.Warn("Config skipped", kvs...)
+		default:
+			log.Info("Config succeeded", append(kvs, "templates", strings.Join(n.Templates, ", "))...)
+		}
+	}
+}
+
+// newHistoryEntry returns the history entry of a config run of the lab.
+func newHistoryEntry(c *clabcore.CLab, action string, templatePaths []string) *clabcoreconfig.HistoryEntry {
+	return &clabcoreconfig.HistoryEntry{
+		Time:          time.Now().UTC(),
+		User:          clabutils.GetOwner(),
+		Action:        action,
+		TopologyHash:  clabcoreconfig.FileHash(c.TopoPaths.TopologyFilenameAbsPath()),
+		TemplatesHash: clabcoreconfig.TemplatesHash(templatePaths),
+		Git:           clabcoreconfig.LabRevision(c.TopoPaths.TopologyFilenameAbsPath(), templatePaths),
+	}
+}
+
+// addHistoryNode records the result of the node in the history entry,
+// a failure replaces an earlier result of the node.
+func addHistoryNode(e *clabcoreconfig.HistoryEntry, cs *clabcoreconfig.NodeConfig, err error) {
+	r := &clabcoreconfig.HistoryNode{
+		Node:       cs.TargetNode.ShortName,
+		Status:     clabcoreconfig.HistoryStatusOK,
+		Templates:  cs.Info,
+		Transport:  cs.Transport,
+		ConfigHash: clabcoreconfig.RenderedHash(cs),
+		Banner:     cs.Banner,
+	}
+	if err != nil {
+		r.Status = clabcoreconfig.HistoryStatusFailed
+		r.Message = cs.Redactor.Redact(err.Error())
+	}
+
+	for i, n := range e.Nodes {
+		if n.Node == r.Node {
+			if err != nil {
+				e.Nodes[i] = r
+			}
+			return
+		}
+	}
+
+	e.Nodes = append(e.Nodes, r)
+}
+
+// promptCredentials asks the user for the credentials of the nodes that have none.
+// The credentials are asked once per kind and used for all nodes of that kind.
+func promptCredentials(allConfig map[string]*clabcoreconfig.NodeConfig, nodes []string) error {
+	sorted := slices.Clone(nodes)
+	sort.Strings(sorted)
+
+	cache := map[string][]string{}
+	reader := bufio.NewReader(os.Stdin)
+
+	for _, n := range sorted {
+		cs, ok := allConfig[n]
+		if !ok || len(cs.Credentials) >= 2 {
+			continue
+		}
+
+		kind := cs.TargetNode.Kind
+		creds, ok := cache[kind]
+		if !ok {
+			if !clabutils.IsTerminal(os.Stdin.Fd()) {
+				return fmt.Errorf("%s: no credentials for kind %s and stdin is not a terminal to prompt for them", n, kind)
+			}
+
+			fmt.Printf("Credentials for %s nodes (first node %s)\nusername: ", kind, n)
+			user, err := reader.ReadString('\n')
+			if err != nil {
+				return fmt.Errorf("failed to read username: %w", err)
+			}
+
+			pass, err := clabutils.ReadPasswordFromTerminal()
+			if err != nil {
+				return fmt.Errorf("failed to read password: %w", err)
+			}
+
+			creds = []string{strings.TrimSpace(user), pass}
+			cache[kind] = creds
+		}
+
+		cs.Credentials = creds
 	}
 
 	return nil
 }
 
-func validateFilter(nodes map[string]clabnodes.Node, o *Options) error {
+// activeNodes returns the nodes that are not in maintenance and the nodes in maintenance,
+// the nodes in maintenance keep running but are not configured nor verified.
+func activeNodes(allConfig map[string]*clabcoreconfig.NodeConfig, nodes []string) (active, maintenance []string) {
+	for _, n := range nodes {
+		if cs, ok := allConfig[n]; ok && cs.TargetNode.Maintenance {
+			maintenance = append(maintenance, n)
+			continue
+		}
+		active = append(active, n)
+	}
+
+	if len(maintenance) > 0 {
+		log.Warnf("Skipped nodes in maintenance: %s", strings.Join(maintenance, ", "))
+	}
+
+	return active, maintenance
+}
+
+// supportedNodes returns the nodes that can be configured and the skipped nodes.
+// Nodes without templates or a transport for their kind fail the run, unless skipUnsupported is set.
+// The verify action only needs the node's verification paths and checks no templates.
+func supportedNodes(allConfig map[string]*clabcoreconfig.NodeConfig, nodes []string,
+	action string, skipUnsupported bool,
+) (supported, skipped []string, err error) {
+	if action == "verify" {
+		return nodes, nil, nil
+	}
+
+	for _, n := range nodes {
+		cs, ok := allConfig[n]
+		if !ok {
+			// reported when the node is configured
+			supported = append(supported, n)
+			continue
+		}
+
+		reason := cs.Unsupported()
+		if reason == "" {
+			supported = append(supported, n)
+			continue
+		}
+
+		if !skipUnsupported {
+			return nil, nil, fmt.Errorf("%s: %s, use --skip-unsupported to skip such nodes", n, reason)
+		}
+
+		log.Warnf("%s: skipping, %s", n, reason)
+		skipped = append(skipped, n)
+	}
+
+	sort.Strings(skipped)
+
+	return supported, skipped, nil
+}
+
+// checkVersions queries the software version of the nodes with version constraints in their
+// templates and returns an error listing the nodes running a release the templates do not target.
+func checkVersions(allConfig map[string]*clabcoreconfig.NodeConfig, nodes []string) error {
+	var (
+		wg     sync.WaitGroup
+		m      sync.Mutex
+		failed []string
+	)
+	for _, n := range nodes {
+		cs, ok := allConfig[n]
+		if !ok || !cs.HasVersionConstraints() {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := clabcoreconfig.CheckVersion(cs)
+			if err != nil {
+				m.Lock()
+				failed = append(failed, fmt.Sprintf("%s: %v", n, err))
+				m.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(failed) == 0 {
+		return nil
+	}
+
+	sort.Strings(failed)
+
+	return fmt.Errorf("version check failed, use --skip-version-check to configure the nodes anyway:\n%s",
+		strings.Join(failed, "\n"))
+}
+
+// verifySummary logs the per-node verification results, optionally writes them
+// to a JUnit XML file and returns an error if any node failed the verification.
+func verifySummary(results []*clabcoreconfig.VerifyResult, labName string, junitFiles ...string) error {
+	if len(results) == 0 {
+		log.Warn("No verification checks defined for the selected nodes")
+		return nil
+	}
+
+	sort.Slice(results, func(i, j int) bool { return results[i].Node < results[j].Node })
+
+	for _, f := range junitFiles {
+		if f == "" {
+			continue
+		}
+		err := writeJUnitFile(f, labName, results)
+		if err != nil {
+			return err
+		}
+	}
+
+	var failed []string
+	for _, r := range results {
+		if r.Passed() {
+			log.Info(r.String())
+			continue
+		}
+		log.Error(r.String())
+		failed = append(failed, r.Node)
+	}
+
+	if len(failed) > 0 {
+		return &ExitError{
+			Code: ExitConfigVerify,
+			Err:  fmt.Errorf("verification failed on nodes: %s", strings.Join(failed, ", ")),
+		}
+	}
+
+	return nil
+}
+
+func writeJUnitFile(path, labName string, results []*clabcoreconfig.VerifyResult) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	err = clabcoreconfig.WriteJUnit(f, labName, results)
+	if err != nil {
+		return err
+	}
+
+	log.Info("JUnit report written", "path", path)
+
+	return nil
+}
+
+// addProvenance adds the provenance comments to the rendered config when enabled.
+func addProvenance(allConfig map[string]*clabcoreconfig.NodeConfig, o *Options) {
+	if !o.Config.Provenance {
+		return
+	}
+	for _, cs := range allConfig {
+		cs.AddProvenance()
+	}
+}
+
+func configTemplate(o *Options) error {
+	var err error
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = validateFilter(c, o)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, !o.Config.TemplateVarOnly)
+	if err != nil {
+		return err
+	}
+	if o.Config.TemplateVarOnly {
+		for _, n := range o.Filter.LabelFilter {
+			conf := allConfig[n]
+			conf.Print(true, false)
+		}
+		return nil
+	}
+
+	addProvenance(allConfig, o)
+
+	for _, n := range o.Filter.LabelFilter {
+		allConfig[n].Print(false, true)
+	}
+
+	return nil
+}
+
+func configDrift(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	c, err := clabcore.NewContainerLab(
+		append(netnsOptions(o),
+			clabcore.WithTimeout(o.Global.Timeout),
+			clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+			clabcore.WithNodeFilter(o.Filter.NodeFilter),
+			clabcore.WithDebug(o.Global.DebugCount > 0),
+		)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = validateFilter(c, o)
+	if err != nil {
+		return err
+	}
+
+	events, err := clabcoreevents.NewEmitter(o.Global.EventsURL)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, false)
+	if err != nil {
+		return err
+	}
+
+	err = clabcoreconfig.DialFromNetNS(ctx, c, allConfig, o.Config.NetNS)
+	if err != nil {
+		return err
+	}
+
+	// drifted tracks the nodes already reported to only alert on changes
+	drifted := map[string]bool{}
+
+	check := func() {
+		var (
+			wg      sync.WaitGroup
+			m       sync.Mutex
+			results []*clabcoreconfig.DriftResult
+		)
+
+		for _, n := range o.Filter.LabelFilter {
+			cs, ok := allConfig[n]
+			if !ok {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				res := clabcoreconfig.CheckDrift(cs)
+				m.Lock()
+				results = append(results, res)
+				m.Unlock()
+			}()
+		}
+		wg.Wait()
+
+		sort.Slice(results, func(i, j int) bool { return results[i].Node < results[j].Node })
+
+		for _, r := range results {
+			switch {
+			case r.Err != nil:
+				log.Warn(r.String())
+			case r.Drifted():
+				log.Warn(r.String())
+				if !drifted[r.Node] {
+					events.Emit(ctx, &clabcoreevents.Event{
+						Type:    clabcoreevents.ConfigDrift,
+						Lab:     c.Config.Name,
+						Node:    r.Node,
+						Status:  clabcoreevents.StatusFailed,
+						Message: fmt.Sprintf("%d lines added, %d lines removed", len(r.Added), len(r.Removed)),
+					})
+				}
+			default:
+				log.Info(r.String())
+				if drifted[r.Node] {
+					events.Emit(ctx, &clabcoreevents.Event{
+						Type:   clabcoreevents.ConfigDrift,
+						Lab:    c.Config.Name,
+						Node:   r.Node,
+						Status: clabcoreevents.StatusOK,
+					})
+				}
+			}
+			drifted[r.Node] = r.Err == nil && r.Drifted()
+		}
+	}
+
+	check()
+
+	if o.Config.DriftInterval <= 0 {
+		return nil
+	}
+
+	ticker := time.NewTicker(o.Config.DriftInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			check()
+		}
+	}
+}
+
+func configExport(o *Options) error {
+	switch o.Config.ExportFormat {
+	case clabcoreconfig.ExportFormatBatfish:
+	case clabcoreconfig.ExportFormatAnsible:
+		if o.Config.ExportSaved {
+			return fmt.Errorf("the %s export pushes the rendered templates, --saved is not supported",
+				o.Config.ExportFormat)
+		}
+	default:
+		return fmt.Errorf("unsupported export format %q", o.Config.ExportFormat)
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithNodeFilter(o.Filter.NodeFilter),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.ResolveLinks()
+	if err != nil {
+		return err
+	}
+
+	err = validateFilter(c, o)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, !o.Config.ExportSaved)
+	if err != nil {
+		return err
+	}
+
+	// only export the nodes selected by the filter
+	selected := make(map[string]*clabcoreconfig.NodeConfig, len(o.Filter.LabelFilter))
+	for _, n := range o.Filter.LabelFilter {
+		selected[n] = allConfig[n]
+	}
+
+	dir := o.Config.ExportPath
+	if dir == "" {
+		dir = filepath.Join(c.TopoPaths.TopologyLabDir(), o.Config.ExportFormat)
+	}
+
+	if o.Config.ExportFormat == clabcoreconfig.ExportFormatAnsible {
+		err = clabcoreconfig.ExportAnsible(selected, dir)
+		if err == nil {
+			log.Infof("Run the playbook with the lab inventory: ansible-playbook -i %s %s",
+				c.TopoPaths.AnsibleInventoryFileAbsPath(), filepath.Join(dir, clabcoreconfig.AnsiblePlaybookFile))
+		}
+		return err
+	}
+
+	return clabcoreconfig.ExportBatfish(c, selected, dir, o.Config.ExportSaved)
+}
+
+func configCollect(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	c, err := clabcore.NewContainerLab(
+		append(netnsOptions(o),
+			clabcore.WithTimeout(o.Global.Timeout),
+			clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+			clabcore.WithNodeFilter(o.Filter.NodeFilter),
+			clabcore.WithDebug(o.Global.DebugCount > 0),
+		)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = validateFilter(c, o)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, false)
+	if err != nil {
+		return err
+	}
+
+	err = clabcoreconfig.DialFromNetNS(ctx, c, allConfig, o.Config.NetNS)
+	if err != nil {
+		return err
+	}
+
+	if o.Config.PromptCredentials {
+		err = promptCredentials(allConfig, o.Filter.LabelFilter)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		m       sync.Mutex
+		outputs = map[string][]*clabcoreconfig.CollectOutput{}
+		failed  []string
+	)
+	for _, n := range o.Filter.LabelFilter {
+		cs, ok := allConfig[n]
+		if !ok {
+			continue
+		}
+
+		cmds, err := clabcoreconfig.BundleCommands(o.Config.CollectBundle, cs.TargetNode.Kind)
+		if err != nil {
+			return err
+		}
+		if len(cmds) == 0 {
+			log.Warnf("%s: no %s commands for kind %s, skipping", n, o.Config.CollectBundle, cs.TargetNode.Kind)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			res, err := clabcoreconfig.Collect(cs, cmds)
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				log.Errorf("%s: %v", n, err)
+				failed = append(failed, n)
+				return
+			}
+			outputs[n] = res
+			log.Infof("%s: collected %d commands", n, len(res))
+		}()
+	}
+	wg.Wait()
+
+	if len(outputs) == 0 {
+		return fmt.Errorf("no outputs collected")
+	}
+
+	dir := o.Config.CollectPath
+	if dir == "" {
+		dir = c.TopoPaths.TopologyLabDir()
+	}
+	clabutils.CreateDirectory(dir, 0o755)
+
+	path := filepath.Join(dir, fmt.Sprintf("%s-%s-%s.tar.gz",
+		o.Config.CollectBundle, c.Config.Name, time.Now().UTC().Format("20060102-150405")))
+
+	err = clabcoreconfig.WriteBundle(path, outputs)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Wrote the %s bundle of %d nodes to %s", o.Config.CollectBundle, len(outputs), path)
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("failed to collect from %s", strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+func configShow(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	c, err := clabcore.NewContainerLab(
+		append(netnsOptions(o),
+			clabcore.WithTimeout(o.Global.Timeout),
+			clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+			clabcore.WithNodeFilter(o.Filter.NodeFilter),
+			clabcore.WithDebug(o.Global.DebugCount > 0),
+		)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	err = validateFilter(c, o)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, false)
+	if err != nil {
+		return err
+	}
+
+	err = clabcoreconfig.DialFromNetNS(ctx, c, allConfig, o.Config.NetNS)
+	if err != nil {
+		return err
+	}
+
+	if o.Config.PromptCredentials {
+		err = promptCredentials(allConfig, o.Filter.LabelFilter)
+		if err != nil {
+			return err
+		}
+	}
+
+	var (
+		wg      sync.WaitGroup
+		results []*clabcoreconfig.ShowResult
+	)
+	for _, n := range o.Filter.LabelFilter {
+		cs, ok := allConfig[n]
+		if !ok {
+			continue
+		}
+		if !transport.SSHSupportsKind(cs.TargetNode.Kind) {
+			log.Warnf("%s: no transport implemented for kind %s, skipping", n, cs.TargetNode.Kind)
+			continue
+		}
+
+		res := &clabcoreconfig.ShowResult{Node: n}
+		results = append(results, res)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			*res = *clabcoreconfig.Show(cs, o.Config.ShowCommand)
+		}()
+	}
+	wg.Wait()
+
+	if len(results) == 0 {
+		return fmt.Errorf("no nodes to run %q on", o.Config.ShowCommand)
+	}
+
+	printShowTable(results)
+
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			log.Errorf("%s: %v", r.Node, r.Err)
+			failed = append(failed, r.Node)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to run %q on %s", o.Config.ShowCommand, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// printShowTable prints the key fields of the show command outputs of the nodes, a row per node.
+func printShowTable(results []*clabcoreconfig.ShowResult) {
+	cols := clabcoreconfig.ShowColumns(results)
+
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	header := tableWriter.Row{"Node"}
+	for _, col := range cols {
+		header = append(header, col)
+	}
+	table.AppendHeader(header)
+
+	for _, r := range results {
+		row := tableWriter.Row{r.Node}
+		for _, col := range cols {
+			v := r.Values[col]
+			if r.Err != nil {
+				v = "error"
+			}
+			row = append(row, v)
+		}
+		table.AppendRow(row)
+	}
+
+	table.Render()
+}
+
+func configShell(cobraCmd *cobra.Command, node string, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	c, err := clabcore.NewContainerLab(
+		append(netnsOptions(o),
+			clabcore.WithTimeout(o.Global.Timeout),
+			clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+			clabcore.WithDebug(o.Global.DebugCount > 0),
+		)...,
+	)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, false)
+	if err != nil {
+		return err
+	}
+
+	cs, ok := allConfig[node]
+	if !ok {
+		return fmt.Errorf("node %q not found in the lab", node)
+	}
+
+	err = clabcoreconfig.DialFromNetNS(ctx, c, map[string]*clabcoreconfig.NodeConfig{node: cs}, o.Config.NetNS)
+	if err != nil {
+		return err
+	}
+
+	if o.Config.PromptCredentials {
+		err = promptCredentials(allConfig, []string{node})
+		if err != nil {
+			return err
+		}
+	}
+
+	return clabcoreconfig.Shell(ctx, cs, os.Stdin, os.Stdout)
+}
+
+// netnsOptions returns the runtime option needed to find the network namespaces of the nodes
+// when the nodes are dialed from their namespaces, the config commands run without a runtime otherwise.
+func netnsOptions(o *Options) []clabcore.ClabOption {
+	if !o.Config.NetNS {
+		return nil
+	}
+
+	return []clabcore.ClabOption{
+		clabcore.WithRuntime(o.Global.Runtime, &clabruntime.RuntimeConfig{
+			Debug:   o.Global.DebugCount > 0,
+			Timeout: o.Global.Timeout,
+		}),
+	}
+}
+
+// prepareConfig prepares the variables of the lab nodes and renders their templates when render is set.
+// The template paths and names of the options are updated with the defaults used by the renderer.
+func prepareConfig(c *clabcore.CLab, o *Options, render bool) (map[string]*clabcoreconfig.NodeConfig, error) {
+	allConfig, err := clabcoreconfig.PrepareVars(c, clabcoreconfig.WithProfile(o.Config.Profile))
+	if err != nil {
+		return nil, err
+	}
+
+	redactor, err := clabcoreconfig.NewRedactor(o.Config.RedactPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, cs := range allConfig {
+		cs.DebugCount = o.Global.DebugCount
+		cs.Redactor = redactor
+	}
+
+	err = clabcoreconfig.SetDialers(allConfig, o.Config.Dialer)
+	if err != nil {
+		return nil, err
+	}
+
+	if !render {
+		return allConfig, nil
+	}
+
+	hooks, err := clabcoreconfig.ParseHooks(o.Config.Hooks)
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := clabcoreconfig.NewRenderer(
+		clabcoreconfig.WithTemplatePaths(o.Config.TemplatePaths),
+		clabcoreconfig.WithTemplateNames(o.Config.TemplateNames),
+		clabcoreconfig.WithRenderWorkers(o.Config.RenderWorkers),
+		clabcoreconfig.WithRenderHooks(hooks),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	o.Config.TemplatePaths, o.Config.TemplateNames = r.Paths, r.Names
+
+	err = r.Render(allConfig)
+	if err != nil {
+		return nil, &ExitError{Code: ExitConfigRender, Err: err}
+	}
+
+	return allConfig, nil
+}
+
+func validateFilter(c *clabcore.CLab, o *Options) error {
+	nodes := make(map[string]struct{}, len(c.Nodes)+len(c.Config.Topology.External))
+	for n := range c.Nodes {
+		nodes[n] = struct{}{}
+	}
+	// external nodes are not filtered by the node filter
+	for n := range c.Config.Topology.External {
+		nodes[n] = struct{}{}
+	}
+
+	// the nodes are configured and reported in the order of their names
 	if len(o.Filter.LabelFilter) == 0 {
 		for n := range nodes {
 			o.Filter.LabelFilter = append(o.Filter.LabelFilter, n)
 		}
+		sort.Strings(o.Filter.LabelFilter)
 		return nil
 	}
 
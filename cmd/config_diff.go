@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clabgit "github.com/srl-labs/containerlab/git"
+)
+
+func configDiffCmd(o *Options) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "diff",
+		Short: "show the rendered config changes between two git revisions of the lab",
+		Long: "render the node configs at two git revisions of the topology and templates\n" +
+			"and show the lines added and removed, the working tree being compared by default",
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				return fmt.Errorf("unexpected arguments: %s", args)
+			}
+
+			return configDiff(o)
+		},
+	}
+
+	c.Flags().StringVarP(&o.Config.DiffSince, "since", "", o.Config.DiffSince,
+		"git revision to compare from, a commit, a branch, a tag or an expression such as HEAD~1")
+	c.Flags().StringVarP(&o.Config.DiffUntil, "until", "", o.Config.DiffUntil,
+		"git revision to compare to, defaults to the working tree")
+	c.Flags().StringVarP(&o.Config.DiffFormat, "format", "", o.Config.DiffFormat,
+		"output format, one of: text, json")
+	_ = c.MarkFlagRequired("since")
+
+	return c
+}
+
+func configDiff(o *Options) error {
+	topo, err := filepath.Abs(o.Global.TopologyFile)
+	if err != nil {
+		return err
+	}
+
+	// the template paths and names are updated by the renderer, each revision starts from the flags
+	paths, names := o.Config.TemplatePaths, o.Config.TemplateNames
+
+	old, err := renderRevision(o, topo, o.Config.DiffSince, paths, names)
+	if err != nil {
+		return err
+	}
+
+	cur, err := renderRevision(o, topo, o.Config.DiffUntil, paths, names)
+	if err != nil {
+		return err
+	}
+
+	// the nodes of the filter may exist in one of the revisions only
+	var mis []string
+	for _, n := range o.Filter.LabelFilter {
+		if old[n] == nil && cur[n] == nil {
+			mis = append(mis, n)
+		}
+	}
+	if len(mis) > 0 {
+		return fmt.Errorf("invalid nodes in filter: %s", strings.Join(mis, ", "))
+	}
+
+	delta := clabcoreconfig.DiffRendered(old, cur, o.Filter.LabelFilter)
+
+	switch o.Config.DiffFormat {
+	case "json":
+		b, err := json.MarshalIndent(delta, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+
+		return nil
+	case "text":
+	default:
+		return fmt.Errorf("unsupported output format %q", o.Config.DiffFormat)
+	}
+
+	until := o.Config.DiffUntil
+	if until == "" {
+		until = "the working tree"
+	}
+
+	if len(delta) == 0 {
+		fmt.Printf("no config changes between %s and %s\n", o.Config.DiffSince, until)
+		return nil
+	}
+
+	for _, d := range delta {
+		fmt.Println(text.Bold.Sprintf("%s: %s..%s", d.Node, o.Config.DiffSince, until))
+		for _, l := range d.Removed {
+			fmt.Println(text.FgRed.Sprint("- " + l))
+		}
+		for _, l := range d.Added {
+			fmt.Println(text.FgGreen.Sprint("+ " + l))
+		}
+	}
+
+	return nil
+}
+
+// renderRevision renders the node configs of the lab at the git revision ref,
+// the working tree being rendered when ref is empty.
+func renderRevision(o *Options, topo, ref string, paths, names []string) (map[string]*clabcoreconfig.NodeConfig, error) {
+	vars := o.Global.VarsFile
+
+	ro := *o
+	cfg := *o.Config
+	ro.Config = &cfg
+	cfg.TemplatePaths, cfg.TemplateNames = paths, names
+
+	if ref != "" {
+		dir, err := os.MkdirTemp("", "clab-config-diff-")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		root, err := clabgit.ExtractRevision(topo, ref, dir)
+		if err != nil {
+			return nil, err
+		}
+
+		rel, err := filepath.Rel(root, topo)
+		if err != nil {
+			return nil, err
+		}
+
+		topo = filepath.Join(dir, rel)
+		if _, err := os.Stat(topo); err != nil {
+			return nil, fmt.Errorf("topology file %s does not exist at revision %s", rel, ref)
+		}
+
+		if vars != "" {
+			if p := clabcoreconfig.TemplatePathsAt([]string{vars}, root, dir); len(p) > 0 {
+				vars = p[0]
+			}
+		}
+
+		cfg.TemplatePaths = clabcoreconfig.TemplatePathsAt(paths, root, dir)
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(topo, vars),
+		clabcore.WithNodeFilter(o.Filter.NodeFilter),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return prepareConfig(c, &ro, true)
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// Exit codes of the config command, one per failure type so that the CI pipelines branch on the failure
+// instead of parsing the logs. The codes don't change, new failure types get new codes.
+// The other errors of the command, e.g. an invalid topology or flag, exit with code 1.
+const (
+	// ExitConfigRender is the exit code of the runs failing to render the templates, no node is configured
+	ExitConfigRender = 10
+	// ExitConfigConnect is the exit code of the runs with nodes failing to connect,
+	// the config of the failed nodes is unchanged
+	ExitConfigConnect = 11
+	// ExitConfigCommit is the exit code of the runs with nodes failing to write or commit the config
+	ExitConfigCommit = 12
+	// ExitConfigVerify is the exit code of the runs with nodes failing the verification
+	ExitConfigVerify = 13
+)
+
+// ExitError is an error with the exit code of containerlab.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode returns the exit code of the error returned by a command, 1 for the errors without an exit code.
+func ExitCode(err error) int {
+	var ee *ExitError
+	if errors.As(err, &ee) {
+		return ee.Code
+	}
+	return 1
+}
+
+// sendFailure returns the error of the nodes failing to send the config, nil if no node failed.
+// The exit code is the connect failure code when all nodes failed to connect, the commit failure code otherwise.
+func sendFailure(errs map[string]error) *ExitError {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	code := ExitConfigConnect
+	nodes := make([]string, 0, len(errs))
+	for n, err := range errs {
+		nodes = append(nodes, n)
+		var ce *transport.ConnectError
+		if !errors.As(err, &ce) {
+			code = ExitConfigCommit
+		}
+	}
+	sort.Strings(nodes)
+
+	return &ExitError{
+		Code: code,
+		Err:  fmt.Errorf("the config failed on nodes %s", strings.Join(nodes, ", ")),
+	}
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+func TestSendFailure(t *testing.T) {
+	connectErr := &transport.ConnectError{Err: errors.New("cannot connect to srl1:22: connection refused")}
+
+	tests := map[string]struct {
+		errs     map[string]error
+		wantCode int
+		wantMsg  string
+	}{
+		"no failure": {},
+		"connect": {
+			errs: map[string]error{
+				"srl2": connectErr,
+				"srl1": fmt.Errorf("no usable config transport: %w", connectErr),
+			},
+			wantCode: ExitConfigConnect,
+			wantMsg:  "the config failed on nodes srl1, srl2",
+		},
+		"commit": {
+			errs: map[string]error{
+				"srl1": connectErr,
+				"srl2": errors.New("could not write config: commit failed"),
+			},
+			wantCode: ExitConfigCommit,
+			wantMsg:  "the config failed on nodes srl1, srl2",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := sendFailure(tt.errs)
+			if tt.wantCode == 0 {
+				if err != nil {
+					t.Fatalf("want no error, got %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("want an error, got nil")
+			}
+			if d := cmp.Diff(tt.wantCode, ExitCode(err)); d != "" {
+				t.Errorf("exit code mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.wantMsg, err.Error()); d != "" {
+				t.Errorf("error mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want int
+	}{
+		"plain error": {err: errors.New("invalid topology"), want: 1},
+		"exit error":  {err: &ExitError{Code: ExitConfigRender, Err: errors.New("template: base__srl.tmpl")}, want: 10},
+		"wrapped": {
+			err:  fmt.Errorf("config: %w", &ExitError{Code: ExitConfigVerify, Err: errors.New("verification failed")}),
+			want: 13,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if d := cmp.Diff(tt.want, ExitCode(tt.err)); d != "" {
+				t.Errorf("exit code mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+)
+
+// shortHashLen is the length of the hashes shown in the history table.
+const shortHashLen = 12
+
+func configHistoryCmd(o *Options) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "history [run-id]",
+		Short: "list the config runs of a lab",
+		Long: "list the config runs recorded in the lab history or show the node results of a run\n" +
+			"every config commit and verify run is recorded with the user, the topology and templates hashes",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return configHistory(o, args)
+		},
+	}
+
+	c.Flags().StringVarP(&o.Config.HistoryFormat, "format", "f", o.Config.HistoryFormat,
+		"output format, one of: table, json")
+
+	return c
+}
+
+func configHistory(o *Options, args []string) error {
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	entries, err := clabcoreconfig.ReadHistory(clabcoreconfig.HistoryPath(c.TopoPaths.TopologyLabDir()))
+	if err != nil {
+		return err
+	}
+
+	var show interface{} = entries
+	var run *clabcoreconfig.HistoryEntry
+
+	if len(args) > 0 {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid run id %q", args[0])
+		}
+		for _, e := range entries {
+			if e.ID == id {
+				run = e
+			}
+		}
+		if run == nil {
+			return fmt.Errorf("config run %d not found in the history of lab %s", id, c.Config.Name)
+		}
+		show = run
+	}
+
+	switch o.Config.HistoryFormat {
+	case "json":
+		b, err := json.MarshalIndent(show, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	case "table":
+		if run != nil {
+			printHistoryRun(run)
+			return nil
+		}
+		if len(entries) == 0 {
+			fmt.Printf("no config runs recorded for lab %s\n", c.Config.Name)
+			return nil
+		}
+		printHistory(entries)
+	default:
+		return fmt.Errorf("unsupported format %q, expected one of: table, json", o.Config.HistoryFormat)
+	}
+
+	return nil
+}
+
+func newHistoryTable() tableWriter.Writer {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.Header = text.FormatTitle
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+	return table
+}
+
+func printHistory(entries []*clabcoreconfig.HistoryEntry) {
+	table := newHistoryTable()
+	table.AppendHeader(tableWriter.Row{"ID", "Time", "User", "Action", "Nodes", "Failed", "Topology", "Templates", "Commit"})
+
+	for _, e := range entries {
+		table.AppendRow(tableWriter.Row{
+			e.ID,
+			e.Time.Local().Format(time.DateTime),
+			e.User,
+			e.Action,
+			len(e.Nodes),
+			e.Failed(),
+			shortHash(e.TopologyHash),
+			shortHash(e.TemplatesHash),
+			e.Git.String(),
+		})
+	}
+
+	table.Render()
+}
+
+func printHistoryRun(e *clabcoreconfig.HistoryEntry) {
+	fmt.Printf("config %s run %d by %s at %s\ntopology %s, templates %s\n",
+		e.Action, e.ID, e.User, e.Time.Local().Format(time.DateTime), e.TopologyHash, e.TemplatesHash)
+	if e.Git != nil {
+		fmt.Printf("git commit %s", e.Git.Commit)
+		if e.Git.Branch != "" {
+			fmt.Printf(" on branch %s", e.Git.Branch)
+		}
+		if e.Git.Dirty {
+			fmt.Print(", with uncommitted changes")
+		}
+		fmt.Println()
+	}
+
+	table := newHistoryTable()
+	table.AppendHeader(tableWriter.Row{"Node", "Status", "Templates", "Message"})
+
+	for _, n := range e.Nodes {
+		table.AppendRow(tableWriter.Row{n.Node, n.Status, strings.Join(n.Templates, "\n"), n.Message})
+	}
+
+	table.Render()
+}
+
+func shortHash(h string) string {
+	if len(h) > shortHashLen {
+		return h[:shortHashLen]
+	}
+	return h
+}
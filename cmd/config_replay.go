@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+)
+
+func configReplayCmd(o *Options) *cobra.Command {
+	c := &cobra.Command{
+		Use:   "replay <session-log>",
+		Short: "replay the config sessions recorded in the artifacts of a config run",
+		Long: "render the session logs recorded with --artifacts-dir as a timeline of the commands sent,\n" +
+			"the outputs received and the errors of the nodes, interleaved by time\n" +
+			"the session log is the artifacts dir of the run, the dir of a node or its session.jsonl file",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return configReplay(o, args[0])
+		},
+	}
+
+	c.Flags().StringSliceVarP(&o.Config.ReplayNodes, "node", "", o.Config.ReplayNodes,
+		"comma separated list of nodes to replay the sessions of")
+
+	return c
+}
+
+func configReplay(o *Options, path string) error {
+	events, err := clabcoreconfig.LoadSessions(path)
+	if err != nil {
+		return err
+	}
+
+	if len(o.Config.ReplayNodes) > 0 {
+		events = slices.DeleteFunc(events, func(ev *clabcoreconfig.SessionEvent) bool {
+			return !slices.Contains(o.Config.ReplayNodes, ev.Node)
+		})
+	}
+
+	if len(events) == 0 {
+		fmt.Println("no session events to replay")
+		return nil
+	}
+
+	printSessionTimeline(events)
+
+	return nil
+}
+
+// nodeSession is the summary of the session of a node in the replay.
+type nodeSession struct {
+	start, end time.Time
+	commands   int
+	errors     int
+	// sent is the time the last command was sent, the outputs are timed from it
+	sent time.Time
+}
+
+// printSessionTimeline prints the events with their time from the start of the first session,
+// the outputs with the time they took to arrive after their command, and a summary per node.
+func printSessionTimeline(events []*clabcoreconfig.SessionEvent) {
+	start := events[0].Time
+
+	width := 0
+	for _, ev := range events {
+		width = max(width, len(ev.Node))
+	}
+
+	sessions := map[string]*nodeSession{}
+	var nodes []string
+
+	for _, ev := range events {
+		s, ok := sessions[ev.Node]
+		if !ok {
+			s = &nodeSession{start: ev.Time}
+			sessions[ev.Node] = s
+			nodes = append(nodes, ev.Node)
+		}
+		s.end = ev.Time
+
+		prefix := fmt.Sprintf("%10s  %-*s  ", "+"+formatOffset(ev.Time.Sub(start)), width, ev.Node)
+		indent := strings.Repeat(" ", len(prefix)+2)
+
+		switch ev.Kind {
+		case clabcoreconfig.SessionSent:
+			s.commands++
+			s.sent = ev.Time
+			fmt.Println(prefix + text.Bold.Sprint("> "+ev.Data))
+		case clabcoreconfig.SessionReceived:
+			lines := strings.Split(strings.Trim(strings.ReplaceAll(ev.Data, "\r", ""), "\n"), "\n")
+			took := ""
+			if !s.sent.IsZero() {
+				took = text.FgCyan.Sprintf(" (%s)", formatOffset(ev.Time.Sub(s.sent)))
+			}
+			fmt.Println(prefix + "< " + lines[0] + took)
+			for _, l := range lines[1:] {
+				fmt.Println(indent + l)
+			}
+		case clabcoreconfig.SessionError:
+			s.errors++
+			fmt.Println(prefix + text.FgRed.Sprint("! "+ev.Data))
+		}
+	}
+
+	fmt.Println()
+	for _, n := range nodes {
+		s := sessions[n]
+		status := text.FgGreen.Sprint("ok")
+		if s.errors > 0 {
+			status = text.FgRed.Sprintf("%d errors", s.errors)
+		}
+		fmt.Printf("%-*s  %d commands in %s, %s\n", width, n, s.commands, formatOffset(s.end.Sub(s.start)), status)
+	}
+}
+
+// formatOffset formats the duration in seconds with milliseconds.
+func formatOffset(d time.Duration) string {
+	return fmt.Sprintf("%.3fs", d.Seconds())
+}
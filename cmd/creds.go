@@ -0,0 +1,216 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/charmbracelet/log"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcorecredstore "github.com/srl-labs/containerlab/core/credstore"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func credsCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "creds",
+		Short: "manage the credentials store",
+		Long: "manage the named credential profiles of the encrypted credentials store of the user,\n" +
+			"the config commands log into the nodes with the profile matching the node by kind, lab and name\n" +
+			"reference: https://containerlab.dev/cmd/creds/",
+	}
+
+	addCmd := &cobra.Command{
+		Use:   "add <name>",
+		Short: "add a credential profile or replace the one with the same name",
+		Example: "# use the ops user for all SR Linux nodes\n" +
+			"containerlab creds add srl --kind nokia_srlinux --username ops\n" +
+			"# read the password of the leaves of the dc lab from the DC_PASSWORD env var\n" +
+			"containerlab creds add dc-leaves --lab dc --node 'leaf*' --username admin --password-env DC_PASSWORD",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return credsAddFn(args[0], o)
+		},
+	}
+	addCmd.Flags().StringVarP(&o.Creds.Kind, "kind", "k", o.Creds.Kind,
+		"kind of the nodes using the profile, any kind when not set")
+	addCmd.Flags().StringVarP(&o.Creds.Lab, "lab", "", o.Creds.Lab,
+		"name of the lab of the nodes using the profile, any lab when not set")
+	addCmd.Flags().StringVarP(&o.Creds.Node, "node", "", o.Creds.Node,
+		"shell pattern of the names of the nodes using the profile, e.g. 'leaf*', any node when not set")
+	addCmd.Flags().StringVarP(&o.Creds.Username, "username", "u", o.Creds.Username, "username")
+	addCmd.Flags().StringVarP(&o.Creds.Password, "password", "p", o.Creds.Password,
+		"password, prompted for when neither --password nor --password-env is set")
+	addCmd.Flags().StringVarP(&o.Creds.PasswordEnv, "password-env", "", o.Creds.PasswordEnv,
+		"environment variable with the password, read when the profile is used")
+	_ = addCmd.MarkFlagRequired("username")
+	addCmd.MarkFlagsMutuallyExclusive("password", "password-env")
+
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Short:        "list the credential profiles, without their passwords",
+		Aliases:      []string{"ls"},
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return credsListFn(o)
+		},
+	}
+	listCmd.Flags().StringVarP(&o.Creds.Format, "format", "f", o.Creds.Format,
+		"output format. One of [table, json]")
+
+	removeCmd := &cobra.Command{
+		Use:          "remove <name>",
+		Short:        "remove a credential profile",
+		Aliases:      []string{"rm"},
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return credsRemoveFn(args[0])
+		},
+	}
+
+	c.AddCommand(addCmd, listCmd, removeCmd)
+
+	return c, nil
+}
+
+func credsAddFn(name string, o *Options) error {
+	p := &clabcorecredstore.Profile{
+		Kind:        o.Creds.Kind,
+		Lab:         o.Creds.Lab,
+		Node:        o.Creds.Node,
+		Username:    o.Creds.Username,
+		Password:    o.Creds.Password,
+		PasswordEnv: o.Creds.PasswordEnv,
+	}
+
+	if p.Password == "" && p.PasswordEnv == "" {
+		if !clabutils.IsTerminal(os.Stdin.Fd()) {
+			return fmt.Errorf("no --password or --password-env set and stdin is not a terminal to prompt for it")
+		}
+
+		fmt.Print("Password: ")
+		var err error
+		p.Password, err = clabutils.ReadPasswordFromTerminal()
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+	}
+
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("credential profile %s: %w", name, err)
+	}
+
+	s, err := clabcorecredstore.Load(clabcorecredstore.Path())
+	if err != nil {
+		return err
+	}
+
+	s.Set(name, p)
+
+	if err := s.Save(); err != nil {
+		return err
+	}
+
+	log.Info("Saved the credential profile", "profile", name, "store", clabcorecredstore.Path())
+
+	return nil
+}
+
+type credsProfileJSON struct {
+	Name        string `json:"name"`
+	Kind        string `json:"kind,omitempty"`
+	Lab         string `json:"lab,omitempty"`
+	Node        string `json:"node,omitempty"`
+	Username    string `json:"username"`
+	PasswordEnv string `json:"password-env,omitempty"`
+}
+
+func credsListFn(o *Options) error {
+	if o.Creds.Format != "table" && o.Creds.Format != "json" {
+		return fmt.Errorf("output format %q is not supported, use 'table' or 'json'", o.Creds.Format)
+	}
+
+	s, err := clabcorecredstore.Load(clabcorecredstore.Path())
+	if err != nil {
+		return err
+	}
+
+	if o.Creds.Format == "json" {
+		res := make([]credsProfileJSON, 0, len(s.Profiles))
+		for _, n := range s.Names() {
+			p := s.Profiles[n]
+			res = append(res, credsProfileJSON{
+				Name:        n,
+				Kind:        p.Kind,
+				Lab:         p.Lab,
+				Node:        p.Node,
+				Username:    p.Username,
+				PasswordEnv: p.PasswordEnv,
+			})
+		}
+
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	if len(s.Profiles) == 0 {
+		log.Info("No credential profiles found, add one with 'containerlab creds add <name> --username <username>'")
+		return nil
+	}
+
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Profile", "Kind", "Lab", "Node", "Username", "Password"})
+	for _, n := range s.Names() {
+		p := s.Profiles[n]
+
+		password := "********"
+		if p.PasswordEnv != "" {
+			password = "$" + p.PasswordEnv
+		}
+
+		table.AppendRow(tableWriter.Row{n, p.Kind, p.Lab, p.Node, p.Username, password})
+	}
+
+	table.Render()
+
+	return nil
+}
+
+func credsRemoveFn(name string) error {
+	s, err := clabcorecredstore.Load(clabcorecredstore.Path())
+	if err != nil {
+		return err
+	}
+
+	if !s.Remove(name) {
+		return fmt.Errorf("credential profile %q not found", name)
+	}
+
+	if err := s.Save(); err != nil {
+		return err
+	}
+
+	log.Info("Removed the credential profile", "profile", name)
+
+	return nil
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoredaemon "github.com/srl-labs/containerlab/core/daemon"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clabcorequota "github.com/srl-labs/containerlab/core/quota"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func daemonCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "daemon",
+		Short: "deploy the labs of a directory of topology files",
+		Long: "watch a directory of topology files and deploy, update and destroy the labs\n" +
+			"as the files appear, change and disappear, for shared self-service lab servers\n" +
+			"reference: https://containerlab.dev/cmd/daemon/",
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return daemonFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().StringVarP(&o.Daemon.WatchDir, "watch", "w", o.Daemon.WatchDir,
+		"directory of the topology files to deploy")
+	c.Flags().DurationVarP(&o.Daemon.Interval, "interval", "", o.Daemon.Interval,
+		"interval between the rescans of the directory, in addition to the file change events, 0 disables them")
+	c.Flags().UintVarP(&o.Deploy.MaxWorkers, "max-workers", "", o.Deploy.MaxWorkers,
+		"limit the maximum number of workers creating nodes and virtual wires")
+	c.Flags().StringVarP(&o.Daemon.QuotasFile, "quotas", "", o.Daemon.QuotasFile,
+		"file with the per-user quotas of nodes and memory, the owners of the topology files being the users")
+	c.Flags().BoolVarP(&o.Destroy.Cleanup, "cleanup", "", o.Destroy.Cleanup,
+		"delete the lab directory when the lab of a removed or expired topology file is destroyed")
+	c.Flags().DurationVarP(&o.Deploy.TTL, "ttl", "", o.Deploy.TTL,
+		"time to live of the labs deployed by the daemon, the labs of all users deployed with a TTL "+
+			"are destroyed once they expire")
+	c.Flags().DurationVarP(&o.Daemon.TTLWarning, "ttl-warning", "", o.Daemon.TTLWarning,
+		"time before the expiry of a lab its users are warned at")
+	_ = c.MarkFlagRequired("watch")
+
+	return c, nil
+}
+
+func daemonFn(cobraCmd *cobra.Command, o *Options) error {
+	dir, err := filepath.Abs(o.Daemon.WatchDir)
+	if err != nil {
+		return err
+	}
+
+	if fi, err := os.Stat(dir); err != nil || !fi.IsDir() {
+		return fmt.Errorf("watch directory %s does not exist", o.Daemon.WatchDir)
+	}
+
+	deploy := func(ctx context.Context, topo string) (string, error) {
+		ro := *o
+		g := *o.Global
+		g.TopologyFile, g.VarsFile, g.TopologyName = topo, "", ""
+		d := *o.Deploy
+		// the labs are updated in place, the unchanged nodes keep running
+		d.Diff = true
+		d.QuotasFile = o.Daemon.QuotasFile
+
+		// the labs belong to the owners of their topology files
+		owner, err := clabutils.FileOwner(topo)
+		if err != nil {
+			return "", err
+		}
+		d.LabOwner = owner
+		ro.Global, ro.Deploy, ro.Filter = &g, &d, &FilterOptions{}
+
+		c, _, err := deployLab(ctx, &ro)
+		if c == nil {
+			return "", err
+		}
+
+		return c.Config.Name, err
+	}
+
+	destroy := func(_ context.Context, lab string) error {
+		ro := *o
+		g := *o.Global
+		g.TopologyFile, g.VarsFile, g.TopologyName = "", "", lab
+		ro.Global, ro.Filter = &g, &FilterOptions{}
+
+		return destroyFn(cobraCmd, &ro)
+	}
+
+	// the quotas file is checked on start, as it is read on every deployment
+	if o.Daemon.QuotasFile != "" {
+		if _, err := clabcorequota.Load(o.Daemon.QuotasFile); err != nil {
+			return err
+		}
+	}
+
+	host, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithRuntime(
+			o.Global.Runtime,
+			&clabruntime.RuntimeConfig{
+				Debug:   o.Global.DebugCount > 0,
+				Timeout: o.Global.Timeout,
+			},
+		),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	events, err := clabcoreevents.NewEmitter(o.Global.EventsURL)
+	if err != nil {
+		return err
+	}
+
+	// the labs of all users expire, not only the labs of the watched directory
+	expiries := func(ctx context.Context) (map[string]time.Time, error) {
+		labs, err := host.LabExpiries(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		res := make(map[string]time.Time, len(labs))
+		for _, l := range labs {
+			res[l.Lab] = l.Expires
+		}
+
+		return res, nil
+	}
+
+	notify := func(ctx context.Context, lab string, expires time.Time) {
+		ev := &clabcoreevents.Event{
+			Type:    clabcoreevents.LabExpired,
+			Lab:     lab,
+			Message: "the lab TTL expired at " + expires.Format(time.RFC3339) + ", destroying the lab",
+		}
+
+		if left := time.Until(expires); left > 0 {
+			log.Warn("Lab TTL expires soon, the lab will be destroyed", "lab", lab,
+				"expires", expires.Local().Format(time.DateTime), "left", left.Round(time.Second))
+			ev.Type = clabcoreevents.LabExpiring
+			ev.Message = "the lab TTL expires at " + expires.Format(time.RFC3339) + ", the lab will be destroyed"
+		}
+
+		events.Emit(ctx, ev)
+	}
+
+	log.Info("Containerlab daemon started", "version", Version, "dir", dir)
+
+	return clabcoredaemon.NewReconciler(dir, deploy, destroy).
+		WithExpiry(expiries, notify, o.Daemon.TTLWarning).
+		Run(cobraCmd.Context(), o.Daemon.Interval)
+}
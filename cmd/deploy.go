@@ -12,7 +12,10 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
 	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
 	clabcoredependency_manager "github.com/srl-labs/containerlab/core/dependency_manager"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clabcorequota "github.com/srl-labs/containerlab/core/quota"
 	clabruntime "github.com/srl-labs/containerlab/runtime"
 	clabutils "github.com/srl-labs/containerlab/utils"
 )
@@ -42,6 +45,9 @@ func deployCmd(o *Options) (*cobra.Command, error) {
 	c.Flags().StringVarP(&o.Deploy.Format, "format", "f", o.Deploy.Format, "output format. One of [table, json]")
 	c.Flags().BoolVarP(&o.Deploy.Reconfigure, "reconfigure", "c", o.Deploy.Reconfigure,
 		"regenerate configuration artifacts and overwrite previous ones if any")
+	c.Flags().BoolVarP(&o.Deploy.Diff, "diff", "", o.Deploy.Diff,
+		"deploy only the nodes and links changed since the lab was deployed")
+	c.MarkFlagsMutuallyExclusive("reconfigure", "diff")
 	c.Flags().UintVarP(&o.Deploy.MaxWorkers, "max-workers", "", o.Deploy.MaxWorkers,
 		"limit the maximum number of workers creating nodes and virtual wires")
 	c.Flags().BoolVarP(&o.Deploy.SkipPostDeploy, "skip-post-deploy", "",
@@ -54,16 +60,42 @@ func deployCmd(o *Options) (*cobra.Command, error) {
 		"skip the lab directory extended ACLs provisioning")
 	c.Flags().StringVarP(&o.Deploy.LabOwner, "owner", "", o.Deploy.LabOwner,
 		"lab owner name (only for users in clab_admins group)")
+	c.Flags().DurationVarP(&o.Deploy.TTL, "ttl", "", o.Deploy.TTL,
+		"time to live of the lab, e.g. 4h, the containerlab daemon destroys the lab once it expires")
+	c.Flags().BoolVarP(&o.Deploy.StartupFromTemplates, "config-startup", "", o.Deploy.StartupFromTemplates,
+		"render the config templates into the startup-config of the nodes instead of configuring them after boot")
+	c.Flags().StringSliceVarP(&o.Config.TemplatePaths, "config-template-path", "", o.Config.TemplatePaths,
+		"comma separated list of paths to search for the config templates rendered with --config-startup")
+	c.Flags().StringSliceVarP(&o.Config.TemplateNames, "config-template-list", "", o.Config.TemplateNames,
+		"comma separated list of config template names rendered with --config-startup")
 
 	return c, nil
 }
 
 // deployFn function runs deploy sub command.
 func deployFn(cobraCmd *cobra.Command, o *Options) error {
-	var err error
-
 	log.Info("Containerlab started", "version", Version)
 
+	_, containers, err := deployLab(cobraCmd.Context(), o)
+	if err != nil {
+		return err
+	}
+
+	// historically i think this was 5s, but we will already have had at least some time for
+	// the manager to have gone off and fetched the version, so 3s max to wrap that up and print
+	// seems reasonable
+	versionCheckContext, cancel := context.WithTimeout(cobraCmd.Context(), 3*time.Second)
+	defer cancel()
+
+	m := getVersionManager()
+	m.DisplayNewVersionAvailable(versionCheckContext)
+
+	// print table summary
+	return PrintContainerInspect(containers, o)
+}
+
+// deployLab deploys the lab with the deploy options and returns it with its containers.
+func deployLab(ctx context.Context, o *Options) (*clabcore.CLab, []clabruntime.GenericContainer, error) {
 	// Check for owner from environment (set by generate command)
 	if o.Deploy.LabOwner == "" && os.Getenv("CLAB_OWNER") != "" {
 		o.Deploy.LabOwner = os.Getenv("CLAB_OWNER")
@@ -86,6 +118,12 @@ func deployFn(cobraCmd *cobra.Command, o *Options) error {
 		clabcore.WithDebug(o.Global.DebugCount > 0),
 	}
 
+	events, err := clabcoreevents.NewEmitter(o.Global.EventsURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	opts = append(opts, clabcore.WithEventEmitter(events))
+
 	// process optional settings
 	if o.Global.TopologyName != "" {
 		opts = append(opts, clabcore.WithLabName(o.Global.TopologyName))
@@ -93,6 +131,9 @@ func deployFn(cobraCmd *cobra.Command, o *Options) error {
 	if o.Deploy.LabOwner != "" {
 		opts = append(opts, clabcore.WithLabOwner(o.Deploy.LabOwner))
 	}
+	if o.Deploy.TTL != 0 {
+		opts = append(opts, clabcore.WithTTL(o.Deploy.TTL))
+	}
 	if o.Deploy.ManagementNetworkName != "" {
 		opts = append(opts, clabcore.WithManagementNetworkName(o.Deploy.ManagementNetworkName))
 	}
@@ -105,34 +146,52 @@ func deployFn(cobraCmd *cobra.Command, o *Options) error {
 
 	c, err := clabcore.NewContainerLab(opts...)
 	if err != nil {
-		return err
+		return nil, nil, err
+	}
+
+	// the quotas are read on every deployment for their changes to apply without a restart
+	if o.Deploy.QuotasFile != "" {
+		q, err := clabcorequota.Load(o.Deploy.QuotasFile)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		err = c.CheckQuota(ctx, q)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	deploymentOptions, err := clabcore.NewDeployOptions(o.Deploy.MaxWorkers)
 	if err != nil {
-		return err
+		return nil, nil, err
 	}
 
 	deploymentOptions.SetExportTemplate(o.Deploy.ExportTemplate).
 		SetReconfigure(o.Deploy.Reconfigure).
+		SetDiff(o.Deploy.Diff).
 		SetGraph(o.Deploy.GenerateGraph).
 		SetSkipPostDeploy(o.Deploy.SkipPostDeploy).
 		SetSkipLabDirFileACLs(o.Deploy.SkipLabDirectoryFileACLs)
 
-	containers, err := c.Deploy(cobraCmd.Context(), deploymentOptions)
-	if err != nil {
-		return err
+	if o.Deploy.StartupFromTemplates {
+		allConfig, err := prepareConfig(c, o, true)
+		if err != nil {
+			return nil, nil, err
+		}
+		deploymentOptions.SetStartupConfigs(clabcoreconfig.StartupConfigs(allConfig))
 	}
 
-	// historically i think this was 5s, but we will already have had at least some time for
-	// the manager to have gone off and fetched the version, so 3s max to wrap that up and print
-	// seems reasonable
-	versionCheckContext, cancel := context.WithTimeout(cobraCmd.Context(), 3*time.Second)
-	defer cancel()
+	// the lab is returned with the deployment error, as the deployment may be partial
+	containers, err := c.Deploy(ctx, deploymentOptions)
+	if err != nil {
+		return c, nil, err
+	}
 
-	m := getVersionManager()
-	m.DisplayNewVersionAvailable(versionCheckContext)
+	if exp := c.Expires(); !exp.IsZero() {
+		log.Info("The lab expires, the containerlab daemon destroys it then", "lab", c.Config.Name,
+			"expires", exp.Local().Format(time.DateTime))
+	}
 
-	// print table summary
-	return PrintContainerInspect(containers, o)
+	return c, containers, nil
 }
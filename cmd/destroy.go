@@ -9,6 +9,7 @@ import (
 
 	"github.com/spf13/cobra"
 	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
 	clabruntime "github.com/srl-labs/containerlab/runtime"
 	clabutils "github.com/srl-labs/containerlab/utils"
 )
@@ -86,6 +87,8 @@ func destroyFn(cobraCmd *cobra.Command, o *Options) error {
 	destroyOptions := []clabcore.DestroyOption{
 		clabcore.WithDestroyMaxWorkers(o.Deploy.MaxWorkers),
 		clabcore.WithDestroyNodeFilter(o.Filter.NodeFilter),
+		// reverse the config the lab applied to devices outliving it
+		clabcore.WithDestroyHook(clabcoreconfig.UndoConfig),
 	}
 
 	if o.Destroy.KeepManagementNetwork {
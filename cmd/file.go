@@ -0,0 +1,222 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+func fileCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "file",
+		Short: "copy files to and from the lab nodes",
+		Long: "copy files to and from the filesystems of the lab nodes,\n" +
+			"the files of the container nodes are copied by the container runtime,\n" +
+			"the files of the VM based and SR OS nodes are copied over SFTP with the credentials of the node\n" +
+			"reference: https://containerlab.dev/cmd/file/",
+	}
+
+	pushCmd := &cobra.Command{
+		Use:   "push <src> <dst>",
+		Short: "copy a local file to the path of the lab nodes",
+		Example: "# copy a license file to all nodes of the lab\n" +
+			"containerlab file push -t lab.clab.yml license.key /opt/srlinux/etc/license.key\n" +
+			"# copy a script to the flash of the SR OS nodes\n" +
+			"containerlab file push -t lab.clab.yml --nodes pe1,pe2 setup.py cf3:/setup.py",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return filePushFn(cobraCmd.Context(), args[0], args[1], o)
+		},
+	}
+
+	pullCmd := &cobra.Command{
+		Use:   "pull <src> <dst-dir>",
+		Short: "copy a file of the lab nodes to a local directory, one subdirectory per node",
+		Example: "# save the config of all nodes to the ./configs/<node>/config.json files\n" +
+			"containerlab file pull -t lab.clab.yml /etc/opt/srlinux/config.json ./configs",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return filePullFn(cobraCmd.Context(), args[0], args[1], o)
+		},
+	}
+
+	for _, cmd := range []*cobra.Command{pushCmd, pullCmd} {
+		cmd.Flags().StringSliceVarP(&o.Filter.NodeFilter, "nodes", "", o.Filter.NodeFilter,
+			"comma separated list of nodes to copy the file to or from, all nodes when not set")
+		cmd.Flags().StringVarP(&o.Config.Dialer, "dialer", "", o.Config.Dialer,
+			"connect to the SFTP nodes through a socks5://[user:password@]host:port proxy, "+
+				"from a source:<interface> or from a netns:<name> network namespace")
+	}
+
+	c.AddCommand(pushCmd, pullCmd)
+
+	return c, nil
+}
+
+// fileNodes returns the configs of the filtered lab nodes in the order of their names.
+func fileNodes(o *Options) (*clabcore.CLab, []*clabcoreconfig.NodeConfig, error) {
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithNodeFilter(o.Filter.NodeFilter),
+		clabcore.WithRuntime(o.Global.Runtime, &clabruntime.RuntimeConfig{
+			Debug:   o.Global.DebugCount > 0,
+			Timeout: o.Global.Timeout,
+		}),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	configs, err := clabcoreconfig.PrepareVars(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = clabcoreconfig.SetDialers(configs, o.Config.Dialer)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := make([]string, 0, len(c.Nodes))
+	for n := range c.Nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	res := make([]*clabcoreconfig.NodeConfig, 0, len(names))
+	for _, n := range names {
+		res = append(res, configs[n])
+	}
+
+	return c, res, nil
+}
+
+func filePushFn(ctx context.Context, src, dst string, o *Options) error {
+	fi, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if fi.IsDir() {
+		return fmt.Errorf("%s is a directory, only files can be copied", src)
+	}
+
+	c, nodes, err := fileNodes(o)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+
+	for _, cs := range nodes {
+		if err := pushNodeFile(ctx, c, cs, src, dst); err != nil {
+			log.Error("Failed to copy the file", "node", cs.TargetNode.ShortName, "error", err)
+			failed = append(failed, cs.TargetNode.ShortName)
+			continue
+		}
+
+		log.Info("Copied the file", "node", cs.TargetNode.ShortName, "src", src, "dst", dst)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to copy %s to the nodes: %s", src, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// pushNodeFile copies the local file to the path of the node, with the method of the node kind.
+func pushNodeFile(ctx context.Context, c *clabcore.CLab, cs *clabcoreconfig.NodeConfig, src, dst string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+
+	node := c.Nodes[cs.TargetNode.ShortName]
+
+	if fn, ok := node.(clabnodes.FileCopyNode); ok {
+		return clabcoreconfig.PushFile(ctx, cs, fn.FileCopyPath(dst), f, fi.Mode().Perm())
+	}
+
+	return node.GetRuntime().CopyToContainer(ctx, cs.TargetNode.LongName, dst, f, fi.Size(), fi.Mode().Perm())
+}
+
+func filePullFn(ctx context.Context, src, dst string, o *Options) error {
+	c, nodes, err := fileNodes(o)
+	if err != nil {
+		return err
+	}
+
+	var failed []string
+
+	for _, cs := range nodes {
+		p := filepath.Join(dst, cs.TargetNode.ShortName, filepath.Base(src))
+
+		if err := pullNodeFile(ctx, c, cs, src, p); err != nil {
+			log.Error("Failed to copy the file", "node", cs.TargetNode.ShortName, "error", err)
+			failed = append(failed, cs.TargetNode.ShortName)
+			continue
+		}
+
+		log.Info("Copied the file", "node", cs.TargetNode.ShortName, "src", src, "dst", p)
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to copy %s from the nodes: %s", src, strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// pullNodeFile copies the file at the path of the node to the local file p, with the method of the node kind.
+// The local file is removed when the copy fails.
+func pullNodeFile(ctx context.Context, c *clabcore.CLab, cs *clabcoreconfig.NodeConfig, src, p string) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+
+	node := c.Nodes[cs.TargetNode.ShortName]
+
+	if fn, ok := node.(clabnodes.FileCopyNode); ok {
+		err = clabcoreconfig.PullFile(ctx, cs, fn.FileCopyPath(src), f)
+	} else {
+		err = node.GetRuntime().CopyFromContainer(ctx, cs.TargetNode.LongName, src, f)
+	}
+
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(p)
+	}
+
+	return err
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreimagelock "github.com/srl-labs/containerlab/core/imagelock"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+func imageCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "image",
+		Short: "pin the lab images to exact image builds",
+	}
+
+	lockCmd := &cobra.Command{
+		Use:   "lock",
+		Short: "record the identity of the lab images in the lock file of the lab",
+		Long: "pull the images of the lab nodes and record their image IDs and digests in the lock file\n" +
+			"kept next to the topology file, the deployments of the lab failing on images not matching it\n" +
+			"reference: https://containerlab.dev/cmd/image/",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return imageLockFn(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(lockCmd)
+
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "verify the local lab images against their pinned digests and the lock file",
+		Long: "verify the local images of the lab nodes match the digests they are pinned to\n" +
+			"with the repo@sha256:<digest> references and the image IDs recorded in the lock file\n" +
+			"reference: https://containerlab.dev/cmd/image/",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return imageVerifyFn(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(verifyCmd)
+	verifyCmd.Flags().StringVarP(&o.Image.Format, "format", "f", o.Image.Format,
+		"output format. One of [table, json]")
+
+	return c, nil
+}
+
+func imageLab(o *Options) (*clabcore.CLab, error) {
+	return clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithRuntime(
+			o.Global.Runtime,
+			&clabruntime.RuntimeConfig{
+				Debug:   o.Global.DebugCount > 0,
+				Timeout: o.Global.Timeout,
+			},
+		),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+}
+
+func imageLockFn(cobraCmd *cobra.Command, o *Options) error {
+	c, err := imageLab(o)
+	if err != nil {
+		return err
+	}
+
+	lock, err := c.LockImages(cobraCmd.Context())
+	if err != nil {
+		return err
+	}
+
+	path := c.TopoPaths.ImageLockFileAbsPath()
+	if err := lock.Save(path); err != nil {
+		return err
+	}
+
+	log.Info("Locked the lab images", "images", len(lock.Images), "file", path)
+
+	return nil
+}
+
+func imageVerifyFn(cobraCmd *cobra.Command, o *Options) error {
+	if o.Image.Format != "table" && o.Image.Format != "json" {
+		return fmt.Errorf("output format %q is not supported, use 'table' or 'json'", o.Image.Format)
+	}
+
+	c, err := imageLab(o)
+	if err != nil {
+		return err
+	}
+
+	path := c.TopoPaths.ImageLockFileAbsPath()
+
+	lock, err := clabcoreimagelock.Load(path)
+	if err != nil {
+		return err
+	}
+	if lock == nil {
+		log.Warn("The lab has no lock file, verifying the pinned digests only", "file", path)
+	}
+
+	results, err := c.VerifyImages(cobraCmd.Context(), lock)
+	if err != nil {
+		return err
+	}
+
+	if o.Image.Format == "json" {
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		printImageVerify(results)
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Status == clabcoreimagelock.StatusMismatch || r.Status == clabcoreimagelock.StatusMissing {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d images failed the verification", failed, len(results))
+	}
+
+	return nil
+}
+
+func printImageVerify(results []*clabcoreimagelock.Result) {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Image", "Nodes", "Status", "Detail"})
+
+	for _, r := range results {
+		var status string
+		switch r.Status {
+		case clabcoreimagelock.StatusOK:
+			status = text.FgGreen.Sprint(r.Status)
+		case clabcoreimagelock.StatusUnlocked:
+			status = text.FgYellow.Sprint(r.Status)
+		default:
+			status = text.FgRed.Sprint(r.Status)
+		}
+
+		table.AppendRow(tableWriter.Row{r.Image, strings.Join(r.Nodes, "\n"), status, r.Detail})
+	}
+
+	table.Render()
+}
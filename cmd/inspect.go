@@ -43,6 +43,8 @@ func inspectCmd(o *Options) (*cobra.Command, error) {
 	c.Flags().BoolVarP(&o.Destroy.All, "all", "a", o.Destroy.All, "show all deployed containerlab labs")
 	c.Flags().BoolVarP(&o.Inspect.Wide, "wide", "w", o.Inspect.Wide,
 		"also more details about a lab and its nodes")
+	c.Flags().BoolVarP(&o.Inspect.Resources, "resources", "", o.Inspect.Resources,
+		"report the CPU, memory, disk and PIDs usage of the lab nodes and the lab totals")
 
 	interfacesC := &cobra.Command{
 		Use:     "interfaces",
@@ -72,6 +74,10 @@ func inspectFn(cobraCmd *cobra.Command, o *Options) error {
 		return fmt.Errorf("provide either a lab name (--name) or a topology file path (--topo) or the --all flag")
 	}
 
+	if o.Inspect.Resources && o.Deploy.Format == "csv" {
+		return fmt.Errorf("output format %q is not supported with --resources, use 'table' or 'json'", o.Deploy.Format)
+	}
+
 	// Format validation (only relevant if --details is NOT used)
 	if !o.Inspect.Details && o.Deploy.Format != "table" && o.Deploy.Format != "json" && o.Deploy.Format != "csv" {
 		return fmt.Errorf("output format %q is not supported when --details is not used, use 'table', 'json' or 'csv'", o.Deploy.Format)
@@ -129,6 +135,10 @@ func inspectFn(cobraCmd *cobra.Command, o *Options) error {
 		return printContainerDetailsJSON(containers)
 	}
 
+	if o.Inspect.Resources {
+		return printContainerResources(cobraCmd.Context(), containers, o)
+	}
+
 	// Handle non-details cases (table or grouped JSON summary)
 	err = PrintContainerInspect(containers, o)
 	return err
@@ -12,10 +12,16 @@ import (
 	"github.com/spf13/cobra"
 
 	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clablabels "github.com/srl-labs/containerlab/labels"
 	clabruntime "github.com/srl-labs/containerlab/runtime"
 	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
 )
 
+// mgmtInterface is the interface of the containers connected to the management network.
+const mgmtInterface = "eth0"
+
 func inspectInterfacesFn(cobraCmd *cobra.Command, o *Options) error {
 	if o.Global.TopologyName == "" && o.Global.TopologyFile == "" {
 		fmt.Println("provide either a lab name (--name) or a topology file path (--topo)")
@@ -87,10 +93,101 @@ func inspectInterfacesFn(cobraCmd *cobra.Command, o *Options) error {
 		return fmt.Errorf("failed to list container interfaces: %s", err)
 	}
 
+	annotateInterfaces(c, containers, containerInterfaces, o)
+
 	err = printContainerInterfaces(containerInterfaces, o.Inspect.InterfacesFormat)
 	return err
 }
 
+// annotateInterfaces sets the peers of the interfaces from the links of the lab topology
+// and their addresses, the management addresses of the containers and the link IPs
+// allocated by the config engine.
+func annotateInterfaces(c *clabcore.CLab, containers []clabruntime.GenericContainer,
+	contInterfaces []*clabtypes.ContainerInterfaces, o *Options,
+) {
+	links := map[string]map[string]*clabcoreconfig.Adjacency{}
+
+	if topo := interfacesTopology(c, containers, o); topo != nil {
+		configs, err := clabcoreconfig.PrepareVars(topo)
+		if err != nil {
+			log.Warn("Failed to resolve the links of the lab, the peers of the interfaces are not shown", "err", err)
+		}
+
+		for _, a := range clabcoreconfig.NodeLinks(configs) {
+			if links[a.Node] == nil {
+				links[a.Node] = map[string]*clabcoreconfig.Adjacency{}
+			}
+			links[a.Node][a.Port] = a
+		}
+	}
+
+	byName := make(map[string]*clabruntime.GenericContainer, len(containers))
+	for idx := range containers {
+		byName[containers[idx].Names[0]] = &containers[idx]
+	}
+
+	for _, ci := range contInterfaces {
+		cnt, ok := byName[ci.ContainerName]
+		if !ok {
+			continue
+		}
+		nodeLinks := links[cnt.Labels[clablabels.NodeName]]
+
+		for _, iface := range ci.Interfaces {
+			if iface.InterfaceName == mgmtInterface {
+				if cnt.NetworkSettings.IPv4addr != "" {
+					iface.Addresses = append(iface.Addresses, cnt.GetContainerIPv4())
+				}
+				if cnt.NetworkSettings.IPv6addr != "" {
+					iface.Addresses = append(iface.Addresses, cnt.GetContainerIPv6())
+				}
+			}
+
+			// the links of the topology refer to the interfaces by their name or alias
+			a, ok := nodeLinks[iface.InterfaceName]
+			if !ok {
+				a, ok = nodeLinks[iface.InterfaceAlias]
+			}
+			if !ok {
+				continue
+			}
+
+			iface.PeerNode, iface.PeerInterface = a.Peer, a.PeerPort
+			if a.IP != "" {
+				iface.Addresses = append(iface.Addresses, a.IP)
+			}
+		}
+	}
+}
+
+// interfacesTopology returns the lab with the topology of the inspected containers,
+// the topology file of the containers being parsed when the lab is inspected by its name.
+// Nil is returned when the topology file is not available.
+func interfacesTopology(c *clabcore.CLab, containers []clabruntime.GenericContainer, o *Options) *clabcore.CLab {
+	if c.TopoPaths.TopologyFileIsSet() {
+		return c
+	}
+
+	topoFile := containers[0].Labels[clablabels.TopoFile]
+	if topoFile == "" || !clabutils.FileOrDirExists(topoFile) {
+		log.Debug("Topology file of the lab not found, the peers of the interfaces are not shown", "file", topoFile)
+		return nil
+	}
+
+	topo, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(topoFile, ""),
+		clabcore.WithSkippedBindsPathsCheck(),
+	)
+	if err != nil {
+		log.Warn("Failed to parse the topology of the lab, the peers of the interfaces are not shown",
+			"file", topoFile, "err", err)
+		return nil
+	}
+
+	return topo
+}
+
 func interfacesToTableData(contInterfaces []*clabtypes.ContainerInterfaces) *[]tableWriter.Row {
 	tabData := make([]tableWriter.Row, 0)
 	for _, container := range contInterfaces {
@@ -101,6 +198,11 @@ func interfacesToTableData(contInterfaces []*clabtypes.ContainerInterfaces) *[]t
 				ifaceAlias = iface.InterfaceAlias
 			}
 
+			peer := ""
+			if iface.PeerNode != "" {
+				peer = iface.PeerNode + ":" + iface.PeerInterface
+			}
+
 			tabRow = append(tabRow,
 				container.ContainerName,
 				iface.InterfaceName,
@@ -110,6 +212,8 @@ func interfacesToTableData(contInterfaces []*clabtypes.ContainerInterfaces) *[]t
 				iface.InterfaceMTU,
 				iface.InterfaceType,
 				iface.InterfaceState,
+				peer,
+				strings.Join(iface.Addresses, "\n"),
 			)
 
 			tabData = append(tabData, tabRow)
@@ -151,6 +255,8 @@ func printContainerInterfaces(
 			"MTU",
 			"Type",
 			"State",
+			"Peer",
+			"Addresses",
 		}
 
 		table.AppendHeader(append(tableWriter.Row{}, header...))
@@ -0,0 +1,87 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+// printContainerResources prints the resource usage of the lab containers with the lab totals.
+func printContainerResources(ctx context.Context, containers []clabruntime.GenericContainer, o *Options) error {
+	labs := clabcore.ContainersResources(ctx, containers)
+
+	if o.Deploy.Format == "json" {
+		b, err := json.MarshalIndent(labs, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	printResourcesTable(labs)
+
+	return nil
+}
+
+// printResourcesTable prints the resource usage of the lab nodes as a table with a total row per lab.
+func printResourcesTable(labs []*clabcore.LabResources) {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.Header = text.FormatTitle
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Lab Name", "Name", "Kind", "State", "CPU %", "Memory", "Disk", "PIDs"})
+	table.SetColumnConfigs([]tableWriter.ColumnConfig{
+		{Number: 1, AutoMerge: true, VAlign: text.VAlignMiddle},
+		{Number: 5, Align: text.AlignRight},
+		{Number: 6, Align: text.AlignRight},
+		{Number: 7, Align: text.AlignRight},
+		{Number: 8, Align: text.AlignRight},
+	})
+
+	for _, lab := range labs {
+		for _, n := range lab.Nodes {
+			table.AppendRow(append(tableWriter.Row{lab.Name, n.Name, n.Kind, n.State}, statsCells(n.Stats)...))
+		}
+
+		total := append(tableWriter.Row{lab.Name, text.Bold.Sprint("total"), "", ""}, statsCells(lab.Total)...)
+		for i := 4; i < len(total); i++ {
+			total[i] = text.Bold.Sprint(total[i])
+		}
+		table.AppendRow(total)
+		table.AppendSeparator()
+	}
+
+	table.Render()
+}
+
+// statsCells returns the table cells of the container stats, empty for a container not running.
+func statsCells(s *clabruntime.ContainerStats) tableWriter.Row {
+	if s == nil {
+		return tableWriter.Row{"", "", "", ""}
+	}
+
+	return tableWriter.Row{
+		fmt.Sprintf("%.1f", s.CPUPercent),
+		fmt.Sprintf("%s / %s", humanize.IBytes(s.MemoryUsage), humanize.IBytes(s.MemoryLimit)),
+		humanize.IBytes(uint64(max(s.DiskUsage, 0))),
+		fmt.Sprintf("%d", s.PIDs),
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/log"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreworkspace "github.com/srl-labs/containerlab/core/workspace"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func labCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "lab",
+		Short: "manage the lab workspaces",
+		Long: "manage the named lab workspaces of the user, the topology and vars files of the current\n" +
+			"workspace are used by the commands run without the --topo flag\n" +
+			"reference: https://containerlab.dev/cmd/lab/",
+	}
+
+	listCmd := &cobra.Command{
+		Use:          "list",
+		Short:        "list the lab workspaces",
+		Aliases:      []string{"ls"},
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return labListFn(o)
+		},
+	}
+	listCmd.Flags().StringVarP(&o.Lab.Format, "format", "f", o.Lab.Format,
+		"output format. One of [table, json]")
+
+	switchCmd := &cobra.Command{
+		Use:   "switch [name]",
+		Short: "switch to a lab workspace, adding it with the --topo flag",
+		Example: "# add the dc lab workspace and switch to it\n" +
+			"containerlab lab switch dc -t ~/labs/dc/dc.clab.yml --vars ~/labs/dc/vars.yml\n" +
+			"# switch back to the dc lab workspace\n" +
+			"containerlab lab switch dc",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, args []string) error {
+			return labSwitchFn(args, o)
+		},
+	}
+	switchCmd.Flags().BoolVarP(&o.Lab.Unset, "unset", "", o.Lab.Unset,
+		"unset the current lab workspace")
+
+	cleanCmd := &cobra.Command{
+		Use:          "clean [name]",
+		Short:        "remove the lab directory of a lab workspace, the current one by default",
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return labCleanFn(cobraCmd, args, o)
+		},
+	}
+	cleanCmd.Flags().BoolVarP(&o.Lab.Forget, "forget", "", o.Lab.Forget,
+		"remove the lab workspace as well")
+
+	c.AddCommand(listCmd, switchCmd, cleanCmd)
+
+	return c, nil
+}
+
+type labWorkspaceJSON struct {
+	Name     string `json:"name"`
+	Topology string `json:"topology"`
+	Vars     string `json:"vars,omitempty"`
+	Current  bool   `json:"current"`
+}
+
+func labListFn(o *Options) error {
+	if o.Lab.Format != "table" && o.Lab.Format != "json" {
+		return fmt.Errorf("output format %q is not supported, use 'table' or 'json'", o.Lab.Format)
+	}
+
+	r, err := clabcoreworkspace.Load(clabcoreworkspace.DefaultPath)
+	if err != nil {
+		return err
+	}
+
+	if o.Lab.Format == "json" {
+		res := make([]labWorkspaceJSON, 0, len(r.Labs))
+		for _, n := range r.Names() {
+			res = append(res, labWorkspaceJSON{
+				Name:     n,
+				Topology: r.Labs[n].Topology,
+				Vars:     r.Labs[n].Vars,
+				Current:  n == r.Current,
+			})
+		}
+
+		b, err := json.MarshalIndent(res, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+
+		return nil
+	}
+
+	if len(r.Labs) == 0 {
+		log.Info("No lab workspaces found, add one with 'containerlab lab switch <name> --topo <file>'")
+		return nil
+	}
+
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"", "Lab", "Topology", "Vars"})
+	for _, n := range r.Names() {
+		current := ""
+		if n == r.Current {
+			current = "*"
+		}
+		table.AppendRow(tableWriter.Row{current, n, r.Labs[n].Topology, r.Labs[n].Vars})
+	}
+
+	table.Render()
+
+	return nil
+}
+
+func labSwitchFn(args []string, o *Options) error {
+	r, err := clabcoreworkspace.Load(clabcoreworkspace.DefaultPath)
+	if err != nil {
+		return err
+	}
+
+	if o.Lab.Unset {
+		r.Current = ""
+		log.Info("Unset the current lab workspace")
+		return r.Save()
+	}
+
+	if len(args) == 0 {
+		return fmt.Errorf("provide the name of the lab workspace to switch to")
+	}
+	name := args[0]
+
+	if o.Global.TopologyFile != "" {
+		w := &clabcoreworkspace.Workspace{}
+
+		w.Topology, err = filepath.Abs(o.Global.TopologyFile)
+		if err != nil {
+			return err
+		}
+		if !clabutils.FileOrDirExists(w.Topology) {
+			return fmt.Errorf("topology file %s not found", w.Topology)
+		}
+
+		if o.Global.VarsFile != "" {
+			w.Vars, err = filepath.Abs(o.Global.VarsFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		r.Set(name, w)
+	}
+
+	if err := r.Switch(name); err != nil {
+		return err
+	}
+
+	log.Info("Switched to the lab workspace", "lab", name, "topology", r.Labs[name].Topology)
+
+	return r.Save()
+}
+
+func labCleanFn(cobraCmd *cobra.Command, args []string, o *Options) error {
+	r, err := clabcoreworkspace.Load(clabcoreworkspace.DefaultPath)
+	if err != nil {
+		return err
+	}
+
+	name := r.Current
+	if len(args) > 0 {
+		name = args[0]
+	}
+	if name == "" {
+		return fmt.Errorf("provide the name of the lab workspace to clean, no current lab workspace is set")
+	}
+
+	w, ok := r.Labs[name]
+	if !ok {
+		return fmt.Errorf("lab workspace %q not found", name)
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(w.Topology, w.Vars),
+		clabcore.WithRuntime(
+			o.Global.Runtime,
+			&clabruntime.RuntimeConfig{
+				Debug:   o.Global.DebugCount > 0,
+				Timeout: o.Global.Timeout,
+			},
+		),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+		clabcore.WithSkippedBindsPathsCheck(),
+	)
+	if err != nil {
+		return err
+	}
+
+	containers, err := c.ListNodesContainersIgnoreNotFound(cobraCmd.Context())
+	if err != nil {
+		return err
+	}
+	if len(containers) != 0 {
+		return fmt.Errorf("the lab of the %q workspace is running, destroy it before cleaning the workspace", name)
+	}
+
+	labDir := c.TopoPaths.TopologyLabDir()
+	log.Info("Removing lab directory", "path", labDir)
+	if err := os.RemoveAll(labDir); err != nil {
+		return err
+	}
+
+	if o.Lab.Forget {
+		r.Remove(name)
+		log.Info("Removed the lab workspace", "lab", name)
+		return r.Save()
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+)
+
+func logsCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "logs <node>",
+		Short: "show logs of a lab node",
+		Long: "show the container logs of a lab node\n" +
+			"for VM-based nodes the serial console output is streamed next to the container logs with --console",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return logsFn(cobraCmd.Context(), o, args[0])
+		},
+	}
+
+	c.Flags().BoolVarP(&o.Logs.Follow, "follow", "f", o.Logs.Follow, "follow log output")
+	c.Flags().BoolVar(&o.Logs.Console, "console", o.Logs.Console,
+		"stream the serial console output of VM-based nodes next to the logs, implies --follow")
+
+	return c, nil
+}
+
+func logsFn(ctx context.Context, o *Options, nodeName string) error {
+	ln, err := findLabNode(ctx, o, nodeName)
+	if err != nil {
+		return err
+	}
+
+	if !o.Logs.Console {
+		return ln.container.Runtime.StreamContainerLogs(ctx, ln.container.Names[0], o.Logs.Follow, os.Stdout)
+	}
+
+	cn, ok := ln.node.(clabnodes.ConsoleNode)
+	if !ok {
+		return fmt.Errorf("node %q of kind %s does not provide a serial console", nodeName, ln.kind)
+	}
+
+	// the console has no history, both streams are followed until the container stops
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	w := &lockedWriter{w: os.Stdout}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if err := streamConsole(ctx, ln, cn, w); err != nil && ctx.Err() == nil {
+			log.Warnf("serial console of node %s: %v", nodeName, err)
+		}
+	}()
+
+	err = ln.container.Runtime.StreamContainerLogs(ctx, ln.container.Names[0], true, w)
+
+	cancel()
+	wg.Wait()
+
+	return err
+}
+
+// streamConsole writes the output of the serial console of the node to w until the context is done.
+// The console command is executed in the container of the node with the runtime CLI,
+// its stdin is kept open without input so that the console client doesn't exit.
+func streamConsole(ctx context.Context, ln *labNode, cn clabnodes.ConsoleNode, w io.Writer) error {
+	args := append([]string{"exec", "-i", ln.container.Names[0]}, cn.ConsoleCmd()...)
+
+	stdin, stdinW := io.Pipe()
+	defer stdinW.Close()
+
+	cmd := exec.CommandContext(ctx, ln.container.Runtime.GetName(), args...) // skipcq: GSC-G204
+	cmd.Stdin = stdin
+	cmd.Stdout = w
+	cmd.Stderr = os.Stderr
+
+	log.Debugf("running %s %v", cmd.Path, args)
+
+	err := cmd.Run()
+	if err != nil && errors.Is(ctx.Err(), context.Canceled) {
+		return nil
+	}
+
+	return err
+}
+
+// lockedWriter serializes the writes of the container logs and the serial console.
+type lockedWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lockedWriter) Write(p []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.w.Write(p)
+}
@@ -2,8 +2,14 @@ package cmd
 
 import (
 	"net"
+	"os"
 	"time"
 
+	clabcoreaaa "github.com/srl-labs/containerlab/core/aaa"
+	clabcoreconsole "github.com/srl-labs/containerlab/core/console"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clabcorenetsvc "github.com/srl-labs/containerlab/core/netsvc"
+	clabcoreztp "github.com/srl-labs/containerlab/core/ztp"
 	clablinks "github.com/srl-labs/containerlab/links"
 )
 
@@ -19,18 +25,40 @@ func GetOptions() *Options {
 	if optionsInstance == nil {
 		optionsInstance = &Options{
 			Global: &GlobalOptions{
-				Timeout:  120 * time.Second,
-				LogLevel: "info",
+				Timeout:   120 * time.Second,
+				LogLevel:  "info",
+				LogStyle:  clabcoreconsole.ModePrefixed,
+				LogFormat: "text",
+				EventsURL: os.Getenv(clabcoreevents.EnvVar),
 			},
 			Filter: &FilterOptions{},
 			Deploy: &DeployOptions{
 				Format: "table",
 			},
 			Destroy: &DestroyOptions{},
-			Config:  &ConfigOptions{},
+			Cleanup: &CleanupOptions{},
+			CheckHost: &CheckHostOptions{
+				Format: "table",
+			},
+			Daemon: &DaemonOptions{
+				Interval:   time.Minute,
+				TTLWarning: 15 * time.Minute,
+			},
+			Config: &ConfigOptions{
+				VerifyTimeout: 2 * time.Minute,
+				DriftInterval: 5 * time.Minute,
+				ExportFormat:  "batfish",
+				HistoryFormat: "table",
+				DiffFormat:    "text",
+				CollectBundle: "support",
+			},
 			Exec: &ExecOptions{
 				Format: "plain",
 			},
+			Logs: &LogsOptions{},
+			SSH: &SSHOptions{
+				Port: 22,
+			},
 			Inspect: &InspectOptions{
 				InterfacesFormat: "table",
 			},
@@ -39,6 +67,15 @@ func GetOptions() *Options {
 				MermaidDirection: "TD",
 				DrawIOVersion:    "latest",
 			},
+			Lab: &LabOptions{
+				Format: "table",
+			},
+			Creds: &CredsOptions{
+				Format: "table",
+			},
+			ToolsAAA: &ToolsAAAOptions{
+				Type: clabcoreaaa.TypeRADIUS,
+			},
 			ToolsAPI: &ToolsApiOptions{
 				Image:          "ghcr.io/srl-labs/clab-api-server/clab-api-server:latest",
 				Name:           "clab-api-server",
@@ -54,6 +91,24 @@ func GetOptions() *Options {
 				SSHMaxPort:     2322,
 				OutputFormat:   "table",
 			},
+			ToolsConnectivity: &ToolsConnectivityOptions{
+				Count:  3,
+				Format: "table",
+			},
+			ToolsConvergence: &ToolsConvergenceOptions{
+				LinkState: "down",
+				Interval:  time.Second,
+				Settle:    10 * time.Second,
+				Timeout:   5 * time.Minute,
+				Format:    "table",
+			},
+			ToolsRotateCreds: &ToolsRotateCredsOptions{},
+			ToolsChaos:       &ToolsChaosOptions{},
+			ToolsGNOI: &ToolsGNOIOptions{
+				RebootMethod: "cold",
+				PingCount:    5,
+				Permissions:  "644",
+			},
 			ToolsCert: &ToolsCertOptions{
 				CommonName:       "containerlab.dev",
 				Country:          "Internet",
@@ -74,13 +129,24 @@ func GetOptions() *Options {
 				Image:    multiToolImage,
 				Format:   "table",
 			},
+			ToolsLink: &ToolsLinkOptions{
+				Count:    1,
+				Interval: time.Second,
+				Latency:  50 * time.Millisecond,
+			},
 			ToolsNetem: &ToolsNetemOptions{
 				Format: "table",
 			},
+			ToolsNetSvc: &ToolsNetSvcOptions{
+				Services: clabcorenetsvc.Services,
+			},
 			ToolsSSHX: &ToolsSSHXOptions{
 				Image:  multiToolImage,
 				Format: "table",
 			},
+			ToolsSuzieq: &ToolsSuzieqOptions{
+				Image: suzieqImage,
+			},
 			ToolsVeth: &ToolsVethOptions{
 				MTU: clablinks.DefaultLinkMTU,
 			},
@@ -89,6 +155,18 @@ func GetOptions() *Options {
 				Port:           14789,
 				DeletionPrefix: "vx-",
 			},
+			ToolsZTP: &ToolsZTPOptions{
+				HTTPPort: clabcoreztp.DefaultHTTPPort,
+				Lease:    clabcoreztp.DefaultLease,
+			},
+			Verify: &VerifyOptions{
+				Format: "table",
+			},
+			Image: &ImageOptions{
+				Format: "table",
+			},
+			Pack:   &PackOptions{},
+			Unpack: &UnpackOptions{},
 		}
 	}
 
@@ -96,22 +174,43 @@ func GetOptions() *Options {
 }
 
 type Options struct {
-	Global         *GlobalOptions
-	Filter         *FilterOptions
-	Deploy         *DeployOptions
-	Destroy        *DestroyOptions
-	Config         *ConfigOptions
-	Exec           *ExecOptions
-	Inspect        *InspectOptions
-	Graph          *GraphOptions
-	ToolsAPI       *ToolsApiOptions
-	ToolsCert      *ToolsCertOptions
-	ToolsTxOffload *ToolsDisableTxOffloadOptions
-	ToolsGoTTY     *ToolsGoTTYOptions
-	ToolsNetem     *ToolsNetemOptions
-	ToolsSSHX      *ToolsSSHXOptions
-	ToolsVeth      *ToolsVethOptions
-	ToolsVxlan     *ToolsVxlanOptions
+	Global            *GlobalOptions
+	Filter            *FilterOptions
+	Deploy            *DeployOptions
+	Destroy           *DestroyOptions
+	Cleanup           *CleanupOptions
+	CheckHost         *CheckHostOptions
+	Daemon            *DaemonOptions
+	Config            *ConfigOptions
+	Exec              *ExecOptions
+	Image             *ImageOptions
+	Pack              *PackOptions
+	Unpack            *UnpackOptions
+	Logs              *LogsOptions
+	SSH               *SSHOptions
+	Inspect           *InspectOptions
+	Graph             *GraphOptions
+	Lab               *LabOptions
+	Creds             *CredsOptions
+	ToolsAAA          *ToolsAAAOptions
+	ToolsAPI          *ToolsApiOptions
+	ToolsCert         *ToolsCertOptions
+	ToolsChaos        *ToolsChaosOptions
+	ToolsConnectivity *ToolsConnectivityOptions
+	ToolsConvergence  *ToolsConvergenceOptions
+	ToolsTxOffload    *ToolsDisableTxOffloadOptions
+	ToolsGoTTY        *ToolsGoTTYOptions
+	ToolsGNOI         *ToolsGNOIOptions
+	ToolsLink         *ToolsLinkOptions
+	ToolsNetem        *ToolsNetemOptions
+	ToolsNetSvc       *ToolsNetSvcOptions
+	ToolsRotateCreds  *ToolsRotateCredsOptions
+	ToolsSSHX         *ToolsSSHXOptions
+	ToolsSuzieq       *ToolsSuzieqOptions
+	ToolsVeth         *ToolsVethOptions
+	ToolsVxlan        *ToolsVxlanOptions
+	ToolsZTP          *ToolsZTPOptions
+	Verify            *VerifyOptions
 }
 
 type GlobalOptions struct {
@@ -121,7 +220,11 @@ type GlobalOptions struct {
 	Timeout      time.Duration
 	Runtime      string
 	LogLevel     string
+	LogStyle     string
+	LogFormat    string
 	DebugCount   int
+	EventsURL    string
+	Rootless     bool
 }
 
 type FilterOptions struct {
@@ -141,6 +244,10 @@ type DeployOptions struct {
 	SkipLabDirectoryFileACLs bool
 	ExportTemplate           string
 	LabOwner                 string
+	StartupFromTemplates     bool
+	Diff                     bool
+	QuotasFile               string
+	TTL                      time.Duration
 }
 
 type DestroyOptions struct {
@@ -151,8 +258,54 @@ type DestroyOptions struct {
 	AutoApprove           bool
 }
 
+type CleanupOptions struct {
+	DryRun bool
+}
+
+type DaemonOptions struct {
+	WatchDir   string
+	Interval   time.Duration
+	QuotasFile string
+	TTLWarning time.Duration
+}
+
+type CheckHostOptions struct {
+	Format string
+}
+
 type ConfigOptions struct {
-	TemplateVarOnly bool
+	TemplatePaths     []string
+	TemplateNames     []string
+	Profile           string
+	RenderWorkers     int
+	TemplateVarOnly   bool
+	SkipUnsupported   bool
+	PromptCredentials bool
+	Provenance        bool
+	RedactPatterns    []string
+	NodeTimeout       time.Duration
+	Deadline          time.Duration
+	Verify            bool
+	VerifyTimeout     time.Duration
+	RollbackOnFailure bool
+	JUnitFile         string
+	NetNS             bool
+	Dialer            string
+	ArtifactsDir      string
+	SkipVersionCheck  bool
+	Hooks             []string
+	DriftInterval     time.Duration
+	ExportFormat      string
+	ExportPath        string
+	ExportSaved       bool
+	HistoryFormat     string
+	DiffSince         string
+	DiffUntil         string
+	DiffFormat        string
+	ReplayNodes       []string
+	CollectBundle     string
+	CollectPath       string
+	ShowCommand       string
 }
 
 type ExecOptions struct {
@@ -160,9 +313,20 @@ type ExecOptions struct {
 	Commands []string
 }
 
+type LogsOptions struct {
+	Follow  bool
+	Console bool
+}
+
+type SSHOptions struct {
+	Username string
+	Port     uint
+}
+
 type InspectOptions struct {
 	Details          bool
 	Wide             bool
+	Resources        bool
 	InterfacesFormat string
 	InterfacesNode   string
 }
@@ -180,6 +344,31 @@ type GraphOptions struct {
 	StaticDirectory  string
 }
 
+type LabOptions struct {
+	Format string
+	Unset  bool
+	Forget bool
+}
+
+type CredsOptions struct {
+	Kind        string
+	Lab         string
+	Node        string
+	Username    string
+	Password    string
+	PasswordEnv string
+	Format      string
+}
+
+type ToolsAAAOptions struct {
+	Type          string
+	ContainerName string
+	Image         string
+	Secret        string
+	Users         []string
+	Owner         string
+}
+
 type ToolsApiOptions struct {
 	Image          string
 	Name           string
@@ -234,6 +423,70 @@ type ToolsGoTTYOptions struct {
 	Owner         string
 }
 
+type ToolsChaosOptions struct {
+	Scenario string
+	EventLog string
+}
+
+type ToolsConnectivityOptions struct {
+	Mesh       bool
+	Traceroute bool
+	Count      int
+	Format     string
+}
+
+type ToolsConvergenceOptions struct {
+	Link      string
+	LinkState string
+	Exec      string
+	Nodes     []string
+	Interval  time.Duration
+	Settle    time.Duration
+	Timeout   time.Duration
+	Format    string
+	Output    string
+}
+
+type ToolsGNOIOptions struct {
+	Node            string
+	LabCA           bool
+	RebootMethod    string
+	RebootDelay     time.Duration
+	RebootMessage   string
+	Force           bool
+	PingCount       int
+	PingSource      string
+	NetworkInstance string
+	Permissions     string
+	CertID          string
+	CertFile        string
+	KeyFile         string
+}
+
+type ToolsRotateCredsOptions struct {
+	Username string
+	Password string
+	Nodes    []string
+}
+
+type ToolsLinkOptions struct {
+	Count    int
+	Interval time.Duration
+	Rate     uint64
+	Burst    uint32
+	Latency  time.Duration
+	Reset    bool
+}
+
+type ToolsNetSvcOptions struct {
+	ContainerName string
+	Image         string
+	Services      []string
+	Domain        string
+	Upstream      []string
+	Owner         string
+}
+
 type ToolsNetemOptions struct {
 	ContainerName string
 	Interface     string
@@ -254,6 +507,12 @@ type ToolsSSHXOptions struct {
 	Format        string
 }
 
+type ToolsSuzieqOptions struct {
+	ContainerName string
+	Image         string
+	Owner         string
+}
+
 type ToolsVethOptions struct {
 	AEndpoint string
 	BEndpoint string
@@ -269,3 +528,26 @@ type ToolsVxlanOptions struct {
 	ParentDevice   string
 	DeletionPrefix string
 }
+
+type ToolsZTPOptions struct {
+	HTTPPort int
+	Lease    time.Duration
+}
+
+type VerifyOptions struct {
+	Format string
+}
+
+type ImageOptions struct {
+	Format string
+}
+
+type PackOptions struct {
+	Output  string
+	Push    string
+	Include []string
+}
+
+type UnpackOptions struct {
+	Dir string
+}
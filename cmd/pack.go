@@ -0,0 +1,148 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcorelabpack "github.com/srl-labs/containerlab/core/labpack"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func packCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "pack",
+		Short: "pack a lab into a tarball or an OCI artifact",
+		Long: "bundle the topology, its variables and image lock files and the files the nodes reference,\n" +
+			"such as the startup-configs and the licenses, into a tarball or an OCI artifact pushed to a registry\n" +
+			"reference: https://containerlab.dev/cmd/pack/",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return packFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().StringVarP(&o.Pack.Output, "output", "o", o.Pack.Output,
+		"path of the lab tarball, <topology name>.tar.gz by default")
+	c.Flags().StringVarP(&o.Pack.Push, "push", "", o.Pack.Push,
+		"push the lab as an OCI artifact to the registry reference, e.g. ghcr.io/org/lab:v1")
+	c.Flags().StringSliceVarP(&o.Pack.Include, "include", "i", o.Pack.Include,
+		"extra files, directories or glob patterns relative to the topology file to pack, e.g. the config templates")
+
+	return c, nil
+}
+
+func unpackCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "unpack SOURCE",
+		Short: "unpack a lab from a tarball or an OCI artifact",
+		Long: "extract a lab packed by the pack command from a tarball or an OCI artifact (oci://<reference>)\n" +
+			"reference: https://containerlab.dev/cmd/pack/",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return unpackFn(cobraCmd, args[0], o)
+		},
+	}
+
+	c.Flags().StringVarP(&o.Unpack.Dir, "dir", "d", o.Unpack.Dir,
+		"directory to extract the lab to, ./<lab name> by default")
+
+	return c, nil
+}
+
+func packFn(cobraCmd *cobra.Command, o *Options) error {
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	m, err := c.PackManifest(o.Pack.Include)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range m.Files {
+		log.Debug("Packing file", "path", f.Path)
+	}
+
+	b, err := clabcore.Pack(m)
+	if err != nil {
+		return err
+	}
+
+	if o.Pack.Output != "" || o.Pack.Push == "" {
+		out := o.Pack.Output
+		if out == "" {
+			out = c.TopoPaths.TopologyFilenameWithoutExt() + ".tar.gz"
+		}
+
+		if err := os.WriteFile(out, b, 0o644); err != nil { // skipcq: GSC-G306
+			return err
+		}
+
+		log.Info("Packed lab", "lab", m.Name, "files", len(m.Files), "file", out)
+	}
+
+	if o.Pack.Push != "" {
+		d, err := clabcorelabpack.Push(cobraCmd.Context(), o.Pack.Push, b, m)
+		if err != nil {
+			return fmt.Errorf("failed to push lab to %s: %w", o.Pack.Push, err)
+		}
+
+		log.Info("Pushed lab", "lab", m.Name, "files", len(m.Files), "ref", o.Pack.Push, "digest", d)
+	}
+
+	return nil
+}
+
+func unpackFn(cobraCmd *cobra.Command, src string, o *Options) error {
+	var (
+		b   []byte
+		err error
+	)
+
+	if clabutils.FileExists(src) {
+		b, err = os.ReadFile(src)
+	} else {
+		b, err = clabcorelabpack.Pull(cobraCmd.Context(), src)
+	}
+	if err != nil {
+		return err
+	}
+
+	m, err := clabcorelabpack.ReadManifest(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	dir := o.Unpack.Dir
+	if dir == "" {
+		dir = m.Name
+	}
+
+	if clabutils.FileOrDirExists(dir) {
+		return fmt.Errorf("directory %s already exists", dir)
+	}
+
+	if _, err := clabcorelabpack.Extract(bytes.NewReader(b), dir); err != nil {
+		return err
+	}
+
+	log.Info("Unpacked lab", "lab", m.Name, "dir", dir,
+		"deploy with", "containerlab deploy -t "+filepath.Join(dir, m.Topology))
+
+	return nil
+}
@@ -14,25 +14,46 @@ import (
 
 	"github.com/charmbracelet/log"
 	"github.com/spf13/cobra"
+	clabcoreconsole "github.com/srl-labs/containerlab/core/console"
+	clabcorelabpack "github.com/srl-labs/containerlab/core/labpack"
+	clabcoreworkspace "github.com/srl-labs/containerlab/core/workspace"
 	clabgit "github.com/srl-labs/containerlab/git"
 	clabruntimedocker "github.com/srl-labs/containerlab/runtime/docker"
 	clabutils "github.com/srl-labs/containerlab/utils"
 )
 
+// rootlessRuntime is the container runtime of the rootless mode. The podman runtime
+// is excluded from the default build, so its name is not referenced from its package.
+const rootlessRuntime = "podman"
+
 func subcommandRegisterFuncs() []func(*Options) (*cobra.Command, error) {
 	return []func(*Options) (*cobra.Command, error){
 		versionCmd,
 		completionCmd,
+		applyCmd,
+		checkHostCmd,
+		cleanupCmd,
 		configCmd,
+		consoleCmd,
+		credsCmd,
+		daemonCmd,
 		deployCmd,
 		destroyCmd,
 		execCmd,
+		fileCmd,
 		generateCmd,
 		graphCmd,
+		imageCmd,
 		inspectCmd,
+		labCmd,
+		logsCmd,
+		packCmd,
 		redeployCmd,
 		saveCmd,
+		sshCmd,
 		toolsCmd,
+		unpackCmd,
+		verifyCmd,
 	}
 }
 
@@ -61,6 +82,14 @@ func Entrypoint() (*cobra.Command, error) {
 	c.PersistentFlags().StringVarP(&o.Global.Runtime, "runtime", "r", "", "container runtime")
 	c.PersistentFlags().StringVarP(&o.Global.LogLevel, "log-level", "", o.Global.LogLevel,
 		"logging level; one of [trace, debug, info, warning, error, fatal]")
+	c.PersistentFlags().StringVarP(&o.Global.LogStyle, "log-style", "", o.Global.LogStyle,
+		"style of the node log records; one of [prefixed, grouped, plain]")
+	c.PersistentFlags().StringVarP(&o.Global.LogFormat, "log-format", "", o.Global.LogFormat,
+		"format of the log records; one of [text, json]")
+	c.PersistentFlags().StringVarP(&o.Global.EventsURL, "events-url", "", o.Global.EventsURL,
+		"webhook URL or unix:///path socket to send the lab lifecycle events to as JSON")
+	c.PersistentFlags().BoolVarP(&o.Global.Rootless, "rootless", "", false,
+		"deploy and manage labs without root privileges using rootless podman")
 
 	err := c.MarkPersistentFlagFilename("topo", "*.yaml", "*.yml")
 	if err != nil {
@@ -80,6 +109,14 @@ func Entrypoint() (*cobra.Command, error) {
 }
 
 func preRunFn(cobraCmd *cobra.Command, o *Options) error {
+	// the rootless mode is checked before any state is changed
+	rootless, rootlessSrc := rootlessMode(o)
+	if rootless {
+		if err := checkRootless(o, rootlessSrc); err != nil {
+			return err
+		}
+	}
+
 	// setting log level
 	switch {
 	case o.Global.DebugCount > 0:
@@ -98,14 +135,33 @@ func preRunFn(cobraCmd *cobra.Command, o *Options) error {
 	initVersionManager(cobraCmd.Context())
 
 	// setting output to stderr, so that json outputs can be parsed
-	log.SetOutput(os.Stderr)
+	switch o.Global.LogFormat {
+	case "text":
+		if err := clabcoreconsole.Setup(os.Stderr, o.Global.LogStyle); err != nil {
+			return err
+		}
 
-	log.SetTimeFormat(time.TimeOnly)
+		log.SetTimeFormat(time.TimeOnly)
+	case "json":
+		log.SetFormatter(log.JSONFormatter)
+		log.SetOutput(os.Stderr)
+		log.SetTimeFormat(time.RFC3339Nano)
+		// every record carries the command it was logged by
+		log.SetDefault(log.With("command", cobraCmd.CommandPath()))
+	default:
+		return fmt.Errorf("unsupported log format %q, use one of text, json", o.Global.LogFormat)
+	}
 
 	err := clabutils.DropRootPrivs()
 	if err != nil {
 		return err
 	}
+
+	if rootless {
+		if err := setupRootless(o); err != nil {
+			return err
+		}
+	}
 	// Rootless operations only supported for Docker runtime
 	if o.Global.Runtime != "" && o.Global.Runtime != clabruntimedocker.RuntimeName {
 		err := clabutils.CheckAndGetRootPrivs()
@@ -117,20 +173,78 @@ func preRunFn(cobraCmd *cobra.Command, o *Options) error {
 	return getTopoFilePath(cobraCmd, o)
 }
 
+// rootlessMode returns true if the rootless mode is enabled and the source enabling it,
+// the --rootless flag or the CLAB_ROOTLESS env.
+func rootlessMode(o *Options) (bool, string) {
+	switch {
+	case o.Global.Rootless:
+		return true, "the --rootless flag"
+	case clabutils.RootlessFromEnv():
+		return true, "the " + clabutils.RootlessEnv + " env"
+	}
+
+	return false, ""
+}
+
+// rootlessRuntimeName returns the runtime of the rootless mode and the source selecting it,
+// the --runtime flag or the CLAB_RUNTIME env, the podman runtime when none is set.
+func rootlessRuntimeName(o *Options) (string, string) {
+	switch {
+	case o.Global.Runtime != "":
+		return o.Global.Runtime, "the --runtime flag"
+	case os.Getenv("CLAB_RUNTIME") != "":
+		return os.Getenv("CLAB_RUNTIME"), "the CLAB_RUNTIME env"
+	}
+
+	return rootlessRuntime, ""
+}
+
+// checkRootless returns an error when the rootless mode enabled by src can't be used:
+// containerlab runs as root, e.g. with sudo, or another runtime than podman is selected.
+func checkRootless(o *Options, src string) error {
+	if !clabutils.InRootlessNamespace() && os.Getuid() == 0 {
+		return fmt.Errorf("the rootless mode enabled by %s is meant for users without root privileges, "+
+			"run containerlab without sudo", src)
+	}
+
+	if rt, rtSrc := rootlessRuntimeName(o); rt != rootlessRuntime {
+		return fmt.Errorf("the rootless mode enabled by %s requires the %s runtime, %s selects %s",
+			src, rootlessRuntime, rtSrc, rt)
+	}
+
+	return nil
+}
+
+// setupRootless enables the rootless mode with the podman runtime and re-executes containerlab
+// in the namespaces of rootless podman, unless it already runs in them.
+func setupRootless(o *Options) error {
+	clabutils.SetRootless(true)
+	o.Global.Runtime = rootlessRuntime
+
+	if clabutils.InRootlessNamespace() {
+		return nil
+	}
+
+	return clabutils.ReexecInRootlessNamespace(rootlessRuntime)
+}
+
 // getTopoFilePath finds *.clab.y*ml file in the current working directory
-// if the file was not specified.
+// if the file was not specified, falling back to the topology of the current lab workspace.
 // If the topology file refers to a git repository, it will be cloned to the current directory.
 // Errors if more than one file is found by the glob path.
 func getTopoFilePath(cobraCmd *cobra.Command, o *Options) error { // skipcq: GO-R1005
 	// set commands which may use topo file find functionality, the rest don't need it
 	if cobraCmd.Name() != "deploy" &&
+		cobraCmd.Name() != "apply" &&
 		cobraCmd.Name() != "destroy" &&
 		cobraCmd.Name() != "redeploy" &&
 		cobraCmd.Name() != "inspect" &&
 		cobraCmd.Name() != "save" &&
 		cobraCmd.Name() != "graph" &&
+		cobraCmd.Name() != "pack" &&
 		cobraCmd.Name() != "interfaces" {
-		return nil
+		_, err := useLabWorkspace(cobraCmd, o)
+		return err
 	}
 
 	// inspect and destroy commands with --all flag don't use file find functionality
@@ -143,6 +257,8 @@ func getTopoFilePath(cobraCmd *cobra.Command, o *Options) error { // skipcq: GO-
 	// perform topology clone/fetch if the topo file is not available locally
 	if !clabutils.FileOrDirExists(o.Global.TopologyFile) {
 		switch {
+		// the lab OCI artifacts are pulled when the topology is loaded
+		case strings.HasPrefix(o.Global.TopologyFile, clabcorelabpack.OCIScheme):
 		case clabgit.IsGitHubOrGitLabURL(o.Global.TopologyFile) ||
 			clabgit.IsGitHubShortURL(o.Global.TopologyFile):
 			o.Global.TopologyFile, err = processGitTopoFile(o.Global.TopologyFile)
@@ -168,6 +284,9 @@ func getTopoFilePath(cobraCmd *cobra.Command, o *Options) error { // skipcq: GO-
 	files, err := filepath.Glob("*.clab.y*ml")
 
 	if len(files) == 0 {
+		if ok, err := useLabWorkspace(cobraCmd, o); ok || err != nil {
+			return err
+		}
 		return errors.New("no topology files matching the pattern *.clab.yml or *.clab.yaml found")
 	}
 
@@ -182,6 +301,48 @@ func getTopoFilePath(cobraCmd *cobra.Command, o *Options) error { // skipcq: GO-
 	return err
 }
 
+// useLabWorkspace sets the topology and vars files of the current lab workspace
+// when neither the topology file nor the lab name are given.
+// It returns true if the lab workspace is used.
+func useLabWorkspace(cobraCmd *cobra.Command, o *Options) (bool, error) {
+	if o.Global.TopologyFile != "" || o.Global.TopologyName != "" {
+		return false, nil
+	}
+
+	// the commands not reading a topology, or writing one, don't use the lab workspace
+	top := cobraCmd
+	for top.HasParent() && top.Parent().HasParent() {
+		top = top.Parent()
+	}
+	switch top.Name() {
+	case "lab", "generate", "version", "completion":
+		return false, nil
+	}
+
+	if f := cobraCmd.Flag("all"); f != nil && f.Value.String() == "true" {
+		return false, nil
+	}
+
+	r, err := clabcoreworkspace.Load(clabcoreworkspace.DefaultPath)
+	if err != nil {
+		return false, err
+	}
+
+	w := r.CurrentWorkspace()
+	if w == nil {
+		return false, nil
+	}
+
+	log.Info("Using the current lab workspace", "lab", r.Current, "topology", w.Topology)
+
+	o.Global.TopologyFile = w.Topology
+	if o.Global.VarsFile == "" {
+		o.Global.VarsFile = w.Vars
+	}
+
+	return true, nil
+}
+
 func processGitTopoFile(topo string) (string, error) {
 	// for short github urls, prepend https://github.com
 	// note that short notation only works for github links
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"testing"
+)
+
+func TestCheckRootless(t *testing.T) {
+	tests := map[string]struct {
+		runtime    string
+		runtimeEnv string
+		// namespace runs the check in the re-executed process
+		namespace bool
+		want      string
+	}{
+		"podman by default": {
+			namespace: true,
+		},
+		"podman flag": {
+			runtime:   "podman",
+			namespace: true,
+		},
+		"docker flag": {
+			runtime:   "docker",
+			namespace: true,
+			want: "the rootless mode enabled by the --rootless flag requires the podman runtime, " +
+				"the --runtime flag selects docker",
+		},
+		"docker env": {
+			runtimeEnv: "docker",
+			namespace:  true,
+			want: "the rootless mode enabled by the --rootless flag requires the podman runtime, " +
+				"the CLAB_RUNTIME env selects docker",
+		},
+		"root": {
+			want: "the rootless mode enabled by the --rootless flag is meant for users without root privileges, " +
+				"run containerlab without sudo",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if !tt.namespace && os.Getuid() != 0 {
+				t.Skip("requires root")
+			}
+
+			t.Setenv("CLAB_RUNTIME", tt.runtimeEnv)
+			t.Setenv("CLAB_ROOTLESS_NAMESPACE", "")
+			if tt.namespace {
+				t.Setenv("CLAB_ROOTLESS_NAMESPACE", "1")
+			}
+
+			o := &Options{Global: &GlobalOptions{Rootless: true, Runtime: tt.runtime}}
+
+			var got string
+			if err := checkRootless(o, "the --rootless flag"); err != nil {
+				got = err.Error()
+			}
+			if got != tt.want {
+				t.Errorf("checkRootless() = %q, want %q", got, tt.want)
+			}
+			if o.Global.Runtime != tt.runtime {
+				t.Errorf("checkRootless() changed the runtime to %q", o.Global.Runtime)
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+func sshCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "ssh <node>",
+		Short: "open an SSH session to a lab node",
+		Long: "open an interactive SSH session to a lab node using its management address\n" +
+			"and the default credentials of the node kind",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return sshFn(cobraCmd.Context(), o, args[0])
+		},
+	}
+
+	c.Flags().StringVarP(&o.SSH.Username, "username", "u", o.SSH.Username,
+		"username to use instead of the kind's default username")
+	c.Flags().UintVarP(&o.SSH.Port, "port", "p", o.SSH.Port, "SSH port of the node")
+
+	return c, nil
+}
+
+func consoleCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "console <node>",
+		Short: "attach to the serial console of a VM-based lab node",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return consoleFn(cobraCmd.Context(), o, args[0])
+		},
+	}
+
+	return c, nil
+}
+
+// labNode contains the details of a deployed lab node required to open an interactive session.
+type labNode struct {
+	container *clabruntime.GenericContainer
+	kind      string
+	creds     *clabnodes.Credentials
+	node      clabnodes.Node
+}
+
+// findLabNode looks up the container of the node by its short name in the lab
+// identified by the topology file or lab name.
+func findLabNode(ctx context.Context, o *Options, nodeName string) (*labNode, error) {
+	c, err := clabcore.NewclabFromTopologyFileOrLabName(ctx, o.Global.TopologyFile,
+		o.Global.TopologyName, o.Global.VarsFile, o.Global.Runtime, o.Global.DebugCount > 0,
+		o.Global.Timeout, o.Destroy.GracefulShutdown)
+	if err != nil {
+		return nil, err
+	}
+
+	containers, err := c.ListContainers(ctx,
+		clabcore.WithListLabName(c.Config.Name),
+		clabcore.WithListFromCliArgs([]string{clablabels.NodeName + "=" + nodeName}),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(containers) == 0 {
+		return nil, fmt.Errorf("node %q is not found in lab %q", nodeName, c.Config.Name)
+	}
+
+	kind := containers[0].Labels[clablabels.NodeKind]
+
+	n, err := c.Reg.NewNodeOfKind(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	return &labNode{
+		container: &containers[0],
+		kind:      kind,
+		creds:     c.Reg.Kind(kind).GetCredentials(),
+		node:      n,
+	}, nil
+}
+
+func sshFn(ctx context.Context, o *Options, nodeName string) error {
+	ln, err := findLabNode(ctx, o, nodeName)
+	if err != nil {
+		return err
+	}
+
+	addr := ln.container.NetworkSettings.IPv4addr
+	if addr == "" {
+		addr = ln.container.NetworkSettings.IPv6addr
+	}
+
+	if addr == "" {
+		return fmt.Errorf("node %q has no management address", nodeName)
+	}
+
+	username := o.SSH.Username
+	if username == "" {
+		username = ln.creds.GetUsername()
+	}
+
+	args := []string{
+		"-o", "StrictHostKeyChecking=no",
+		"-o", "UserKnownHostsFile=/dev/null",
+		"-p", strconv.Itoa(int(o.SSH.Port)),
+	}
+
+	target := addr
+	if username != "" {
+		target = username + "@" + addr
+	}
+
+	args = append(args, target)
+
+	if ln.creds.GetPassword() != "" && o.SSH.Username == "" {
+		log.Debugf("logging in with the default credentials of kind %s", ln.kind)
+	}
+
+	log.Debugf("running ssh %v", args)
+
+	return runInteractive("ssh", args...)
+}
+
+func consoleFn(ctx context.Context, o *Options, nodeName string) error {
+	ln, err := findLabNode(ctx, o, nodeName)
+	if err != nil {
+		return err
+	}
+
+	cn, ok := ln.node.(clabnodes.ConsoleNode)
+	if !ok {
+		return fmt.Errorf("node %q of kind %s does not provide a serial console, use 'ssh' instead",
+			nodeName, ln.kind)
+	}
+
+	runtimeName := ln.container.Runtime.GetName()
+
+	args := append([]string{"exec", "-it", ln.container.Names[0]}, cn.ConsoleCmd()...)
+
+	log.Info("Attaching to the serial console, use 'Ctrl+]' and 'quit' to detach", "node", nodeName)
+
+	return runInteractive(runtimeName, args...)
+}
+
+// runInteractive runs the command attached to the current terminal.
+func runInteractive(name string, args ...string) error {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
@@ -14,14 +14,24 @@ import (
 
 func toolsSubcommandRegisterFuncs() []func(*Options) (*cobra.Command, error) {
 	return []func(*Options) (*cobra.Command, error){
+		aaaCmd,
 		apiServerCmd,
 		certCmd,
+		chaosCmd,
+		connectivityCmd,
+		convergenceCmd,
 		disableTxOffloadCmd,
+		gnoiCmd,
 		gottyCmd,
+		linkCmd,
 		netemCmd,
+		netSvcCmd,
+		rotateCredsCmd,
 		sshxCmd,
+		suzieqCmd,
 		vethCmd,
 		vxlanCmd,
+		ztpCmd,
 	}
 }
 
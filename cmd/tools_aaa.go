@@ -0,0 +1,305 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreaaa "github.com/srl-labs/containerlab/core/aaa"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	aaa             = "aaa"
+	aaaLabDirSuffix = "aaa"
+)
+
+// AAANode implements runtime.Node interface for the AAA server container.
+type AAANode struct {
+	config *clabtypes.NodeConfig
+}
+
+func aaaCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   aaa,
+		Short: "RADIUS and TACACS+ server operations",
+		Long: "Start or stop a RADIUS or TACACS+ server container authenticating the users of the lab nodes,\n" +
+			"with its address and secret available to the config templates of the nodes",
+	}
+
+	aaaStartCmd := &cobra.Command{
+		Use:   "start",
+		Short: "start an AAA server for a lab",
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return aaaStart(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(aaaStartCmd)
+
+	aaaStartCmd.Flags().StringVarP(&o.Global.TopologyName, "lab", "l", o.Global.TopologyName,
+		"name of the lab to start the AAA server for")
+	aaaStartCmd.Flags().StringVarP(&o.ToolsAAA.Type, "type", "", o.ToolsAAA.Type,
+		"type of the AAA server, one of: "+strings.Join(clabcoreaaa.Types, ", "))
+	aaaStartCmd.Flags().StringVarP(&o.ToolsAAA.ContainerName, "name", "", o.ToolsAAA.ContainerName,
+		"name of the AAA server container (defaults to clab-<labname>-aaa)")
+	aaaStartCmd.Flags().StringVarP(&o.ToolsAAA.Image, "image", "i", o.ToolsAAA.Image,
+		"container image to use for the AAA server (defaults to the image of the server type)")
+	aaaStartCmd.Flags().StringVarP(&o.ToolsAAA.Secret, "secret", "s", o.ToolsAAA.Secret,
+		"shared secret of the AAA server (defaults to a random secret)")
+	aaaStartCmd.Flags().StringSliceVarP(&o.ToolsAAA.Users, "user", "u", o.ToolsAAA.Users,
+		"user of the AAA server as name:password, can be repeated (defaults to the credentials of the node kinds)")
+	aaaStartCmd.Flags().StringVarP(&o.ToolsAAA.Owner, "owner", "o", o.ToolsAAA.Owner,
+		"lab owner name for the AAA server container")
+
+	aaaStopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "stop the AAA server of a lab",
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return aaaStop(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(aaaStopCmd)
+
+	aaaStopCmd.Flags().StringVarP(&o.Global.TopologyName, "lab", "l", o.Global.TopologyName,
+		"name of the lab where the AAA server is running")
+
+	return c, nil
+}
+
+// NewAAANode creates a new AAA server node configuration
+// with the config files of the configDir mounted at their paths in the container.
+func NewAAANode(name, image, network, configDir string, files []string, labels map[string]string) *AAANode {
+	log.Debugf("Creating AAANode: name=%s, image=%s, network=%s, configDir=%s",
+		name, image, network, configDir)
+
+	nodeConfig := &clabtypes.NodeConfig{
+		LongName:  name,
+		ShortName: name,
+		Image:     image,
+		MgmtNet:   network,
+		Labels:    labels,
+	}
+
+	for _, f := range files {
+		nodeConfig.Binds = append(nodeConfig.Binds,
+			filepath.Join(configDir, f)+":"+clabcoreaaa.ContainerPaths[f]+":ro")
+	}
+
+	return &AAANode{
+		config: nodeConfig,
+	}
+}
+
+func (n *AAANode) Config() *clabtypes.NodeConfig {
+	return n.config
+}
+
+func (*AAANode) GetEndpoints() []clablinks.Endpoint {
+	return nil
+}
+
+func aaaStart(cobraCmd *cobra.Command, o *Options) error { //nolint: funlen
+	ctx := cobraCmd.Context()
+
+	server, err := clabcoreaaa.NewServer(o.ToolsAAA.Type, o.ToolsAAA.Secret)
+	if err != nil {
+		return err
+	}
+
+	users, err := clabcoreaaa.ParseUsers(o.ToolsAAA.Users)
+	if err != nil {
+		return err
+	}
+
+	clabInstance, err := clabcore.NewclabFromTopologyFileOrLabName(ctx, o.Global.TopologyFile,
+		o.Global.TopologyName, o.Global.VarsFile, o.Global.Runtime, o.Global.DebugCount > 0,
+		o.Global.Timeout, o.Destroy.GracefulShutdown)
+	if err != nil {
+		return err
+	}
+
+	labName := clabInstance.Config.Name
+
+	if len(users) == 0 {
+		users = clabcoreaaa.TopologyUsers(clabInstance)
+	}
+	if len(users) == 0 {
+		return fmt.Errorf("the node kinds of the lab have no default credentials, set the users with --user")
+	}
+	server.Users = users
+
+	for _, s := range []string{clabInstance.Config.Mgmt.IPv4Subnet, clabInstance.Config.Mgmt.IPv6Subnet} {
+		if s != "" {
+			server.Clients = append(server.Clients, s)
+		}
+	}
+
+	networkName := clabInstance.Config.Mgmt.Network
+	if networkName == "" {
+		networkName = "clab-" + labName
+	}
+
+	if o.ToolsAAA.ContainerName == "" {
+		o.ToolsAAA.ContainerName = fmt.Sprintf("clab-%s-%s", labName, aaa)
+		log.Debugf("Container name not provided, generated name: %s", o.ToolsAAA.ContainerName)
+	}
+	server.Container = o.ToolsAAA.ContainerName
+
+	image := o.ToolsAAA.Image
+	if image == "" {
+		image = server.Image()
+	}
+
+	_, rinit, err := clabcore.RuntimeInitializer(o.Global.Runtime)
+	if err != nil {
+		return fmt.Errorf("failed to get runtime initializer for '%s': %w", o.Global.Runtime, err)
+	}
+
+	rt := rinit()
+
+	err = rt.Init(
+		clabruntime.WithConfig(&clabruntime.RuntimeConfig{Timeout: o.Global.Timeout}),
+		clabruntime.WithMgmtNet(&clabtypes.MgmtNet{Network: networkName}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize runtime: %w", err)
+	}
+
+	filter := []*clabtypes.GenericFilter{{FilterType: "name", Match: o.ToolsAAA.ContainerName}}
+
+	containers, err := rt.ListContainers(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	if len(containers) > 0 {
+		return fmt.Errorf("container %s already exists", o.ToolsAAA.ContainerName)
+	}
+
+	log.Infof("Pulling image %s...", image)
+	if err := rt.PullImage(ctx, image, clabtypes.PullPolicyIfNotPresent); err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
+
+	configDir := filepath.Join(clabInstance.TopoPaths.TopologyLabDir(), aaaLabDirSuffix)
+	clabutils.CreateDirectory(configDir, 0o755)
+
+	files := server.ConfigFiles()
+	names := make([]string, 0, len(files))
+	for name, b := range files {
+		if err := os.WriteFile(filepath.Join(configDir, name), b, 0o644); err != nil { // skipcq: GSC-G306
+			return err
+		}
+		names = append(names, name)
+	}
+
+	owner := o.ToolsAAA.Owner
+	if owner == "" {
+		owner = clabutils.GetOwner()
+	}
+
+	labelsMap := createLabelsMap(
+		clabInstance.TopoPaths.TopologyFilenameAbsPath(),
+		labName,
+		o.ToolsAAA.ContainerName,
+		owner,
+		aaa,
+	)
+
+	log.Infof("Creating %s server container %s on network '%s'", server.Type, o.ToolsAAA.ContainerName, networkName)
+	aaaNode := NewAAANode(o.ToolsAAA.ContainerName, image, networkName, configDir, names, labelsMap)
+
+	id, err := rt.CreateContainer(ctx, aaaNode.Config())
+	if err != nil {
+		return fmt.Errorf("failed to create AAA server container: %w", err)
+	}
+
+	if _, err := rt.StartContainer(ctx, id, aaaNode); err != nil {
+		// Clean up on failure
+		rt.DeleteContainer(ctx, o.ToolsAAA.ContainerName)
+		return fmt.Errorf("failed to start AAA server container: %w", err)
+	}
+
+	containers, err = rt.ListContainers(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+	if len(containers) > 0 {
+		server.Address = containers[0].NetworkSettings.IPv4addr
+	}
+	if server.Address == "" {
+		log.Warnf("failed to get the management address of the AAA server container %s", o.ToolsAAA.ContainerName)
+	}
+
+	if err := server.Save(clabInstance.TopoPaths.AAAServerFileAbsPath()); err != nil {
+		return err
+	}
+
+	log.Info("AAA server started", "type", server.Type, "container", o.ToolsAAA.ContainerName,
+		"address", server.Address, "port", server.Port, "users", len(server.Users), "note",
+		"The server address and secret are available to the config templates as .clab_aaa")
+
+	return nil
+}
+
+func aaaStop(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	clabInstance, err := clabcore.NewclabFromTopologyFileOrLabName(ctx, o.Global.TopologyFile,
+		o.Global.TopologyName, o.Global.VarsFile, o.Global.Runtime, o.Global.DebugCount > 0,
+		o.Global.Timeout, o.Destroy.GracefulShutdown)
+	if err != nil {
+		return err
+	}
+
+	path := clabInstance.TopoPaths.AAAServerFileAbsPath()
+
+	server, err := clabcoreaaa.LoadServer(path)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return fmt.Errorf("lab %s has no AAA server", clabInstance.Config.Name)
+	}
+
+	_, rinit, err := clabcore.RuntimeInitializer(o.Global.Runtime)
+	if err != nil {
+		return fmt.Errorf("failed to get runtime initializer: %w", err)
+	}
+
+	rt := rinit()
+	err = rt.Init(clabruntime.WithConfig(&clabruntime.RuntimeConfig{Timeout: o.Global.Timeout}))
+	if err != nil {
+		return fmt.Errorf("failed to initialize runtime: %w", err)
+	}
+
+	log.Infof("Removing AAA server container %s", server.Container)
+	if err := rt.DeleteContainer(ctx, server.Container); err != nil {
+		return fmt.Errorf("failed to remove AAA server container: %w", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(clabInstance.TopoPaths.TopologyLabDir(), aaaLabDirSuffix)); err != nil {
+		return err
+	}
+
+	log.Infof("AAA server container %s removed", server.Container)
+	return nil
+}
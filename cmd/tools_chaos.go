@@ -0,0 +1,102 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcorechaos "github.com/srl-labs/containerlab/core/chaos"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func chaosCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "chaos",
+		Short: "failure injection scenarios",
+	}
+
+	chaosRunCmd := &cobra.Command{
+		Use:   "run",
+		Short: "run a failure injection scenario against a running lab",
+		Long: "run the steps of a scenario file flapping links and pausing nodes of a running lab\n" +
+			"at scheduled times, and record them in an event log\n" +
+			"reference: https://containerlab.dev/cmd/tools/chaos/run/",
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			clabutils.CheckAndGetRootPrivs()
+			return nil
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return chaosRunFn(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(chaosRunCmd)
+	chaosRunCmd.Flags().StringVarP(&o.ToolsChaos.Scenario, "scenario", "s", o.ToolsChaos.Scenario,
+		"path to the scenario file")
+	chaosRunCmd.Flags().StringVarP(&o.ToolsChaos.EventLog, "event-log", "", o.ToolsChaos.EventLog,
+		"path to the event log file, defaults to a file in the chaos directory of the lab directory")
+	_ = chaosRunCmd.MarkFlagRequired("scenario")
+
+	return c, nil
+}
+
+func chaosRunFn(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	s, err := clabcorechaos.LoadScenario(o.ToolsChaos.Scenario)
+	if err != nil {
+		return err
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.ResolveLinks()
+	if err != nil {
+		return err
+	}
+
+	emitter, err := clabcoreevents.NewEmitter(o.Global.EventsURL)
+	if err != nil {
+		return err
+	}
+
+	path := o.ToolsChaos.EventLog
+	if path == "" {
+		name := s.Name
+		if name == "" {
+			name = strings.TrimSuffix(filepath.Base(o.ToolsChaos.Scenario), filepath.Ext(o.ToolsChaos.Scenario))
+		}
+		path = filepath.Join(c.TopoPaths.TopologyLabDir(), "chaos",
+			fmt.Sprintf("%s-%s.jsonl", name, time.Now().UTC().Format("20060102T150405Z")))
+	}
+
+	clabutils.CreateDirectory(filepath.Dir(path), 0o755)
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644) // skipcq: GSC-G302
+	if err != nil {
+		return fmt.Errorf("failed to open the event log: %w", err)
+	}
+	defer f.Close()
+
+	log.Info("Running chaos scenario", "scenario", o.ToolsChaos.Scenario, "steps", len(s.Steps), "event-log", path)
+
+	return clabcorechaos.Run(ctx, c, s, clabcorechaos.NewEventLog(f, emitter, c.Config.Name))
+}
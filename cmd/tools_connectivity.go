@@ -0,0 +1,244 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clabexec "github.com/srl-labs/containerlab/exec"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+// connectivityProbe is a ping from a node to an IP of another node.
+type connectivityProbe struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	IP     string `json:"ip"`
+	Passed bool   `json:"passed"`
+	Output string `json:"output,omitempty"`
+	Trace  string `json:"traceroute,omitempty"`
+}
+
+func connectivityCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "connectivity",
+		Short: "test the dataplane connectivity of a lab",
+		Long: "ping the link IPs allocated by the config engine between the nodes of a lab\n" +
+			"and report a pass/fail matrix of the sources and targets\n" +
+			"reference: https://containerlab.dev/cmd/tools/connectivity/",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return connectivityFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().BoolVarP(&o.ToolsConnectivity.Mesh, "mesh", "", o.ToolsConnectivity.Mesh,
+		"ping every node from every other node instead of only the directly connected peers")
+	c.Flags().BoolVarP(&o.ToolsConnectivity.Traceroute, "traceroute", "", o.ToolsConnectivity.Traceroute,
+		"run a traceroute along every ping")
+	c.Flags().IntVarP(&o.ToolsConnectivity.Count, "count", "c", o.ToolsConnectivity.Count,
+		"number of pings sent per probe")
+	c.Flags().StringVarP(&o.ToolsConnectivity.Format, "format", "f", o.ToolsConnectivity.Format,
+		"output format (table, json)")
+
+	return c, nil
+}
+
+func connectivityFn(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	if o.ToolsConnectivity.Format != "table" && o.ToolsConnectivity.Format != "json" {
+		return fmt.Errorf("unsupported output format %q", o.ToolsConnectivity.Format)
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithRuntime(o.Global.Runtime, &clabruntime.RuntimeConfig{
+			Debug:   o.Global.DebugCount > 0,
+			Timeout: o.Global.Timeout,
+		}),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := clabcoreconfig.PrepareVars(c)
+	if err != nil {
+		return err
+	}
+
+	probes := connectivityProbes(clabcoreconfig.LinkAdjacencies(allConfig), o.ToolsConnectivity.Mesh)
+	if len(probes) == 0 {
+		return fmt.Errorf("no link IPs found in the topology, set clab_link_ip or clab_system_ip for the links to test")
+	}
+
+	var wg sync.WaitGroup
+	for _, p := range probes {
+		node, ok := c.Nodes[p.Source]
+		if !ok {
+			// external nodes are only pinged
+			p.Output = "not a lab node"
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			p.Passed, p.Output = connectivityExec(ctx, node,
+				fmt.Sprintf("ping -c %d -W 1 %s", o.ToolsConnectivity.Count, p.IP))
+			if o.ToolsConnectivity.Traceroute {
+				_, p.Trace = connectivityExec(ctx, node, "traceroute -n -w 1 "+p.IP)
+			}
+		}()
+	}
+	wg.Wait()
+
+	var failed []string
+	for _, p := range probes {
+		if !p.Passed {
+			failed = append(failed, fmt.Sprintf("%s->%s (%s)", p.Source, p.Target, p.IP))
+		}
+	}
+
+	switch o.ToolsConnectivity.Format {
+	case "json":
+		b, err := json.MarshalIndent(probes, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	default:
+		printConnectivityMatrix(probes)
+		for _, p := range probes {
+			if !p.Passed {
+				log.Errorf("%s -> %s (%s) failed:\n%s%s", p.Source, p.Target, p.IP, p.Output, p.Trace)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("connectivity failed for %d of %d probes: %s",
+			len(failed), len(probes), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
+
+// connectivityProbes returns the probes of the adjacencies, a ping from every node to the link IPs
+// of its peers. With mesh set, every node pings every other node on the first link IP of the node.
+func connectivityProbes(adj []*clabcoreconfig.Adjacency, mesh bool) []*connectivityProbe {
+	var probes []*connectivityProbe
+
+	if !mesh {
+		for _, a := range adj {
+			probes = append(probes, &connectivityProbe{Source: a.Node, Target: a.Peer, IP: a.PeerIP})
+		}
+		return probes
+	}
+
+	// adjacencies are sorted by node and port, the first IP of a node is its lowest port
+	ips := map[string]string{}
+	var nodes []string
+	for _, a := range adj {
+		if _, ok := ips[a.Node]; !ok {
+			ips[a.Node] = a.IP
+			nodes = append(nodes, a.Node)
+		}
+	}
+	sort.Strings(nodes)
+
+	for _, src := range nodes {
+		for _, dst := range nodes {
+			if src == dst {
+				continue
+			}
+			probes = append(probes, &connectivityProbe{Source: src, Target: dst, IP: ips[dst]})
+		}
+	}
+
+	return probes
+}
+
+// connectivityExec runs a command on the node, returning whether it succeeded and its output.
+func connectivityExec(ctx context.Context, node clabnodes.Node, cmd string) (bool, string) {
+	execCmd, err := clabexec.NewExecCmdFromString(cmd)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	res, err := node.RunExec(ctx, execCmd)
+	if err != nil {
+		return false, err.Error()
+	}
+
+	return res.GetReturnCode() == 0, res.GetStdOutString() + res.GetStdErrString()
+}
+
+// printConnectivityMatrix prints the probes as a matrix of the sources and targets.
+// A cell is ok when all the probes from the source to the target passed.
+func printConnectivityMatrix(probes []*connectivityProbe) {
+	var sources, targets []string
+	cells := map[string]map[string]string{}
+	seen := map[string]bool{}
+	for _, p := range probes {
+		if cells[p.Source] == nil {
+			cells[p.Source] = map[string]string{}
+			sources = append(sources, p.Source)
+		}
+		if !seen[p.Target] {
+			seen[p.Target] = true
+			targets = append(targets, p.Target)
+		}
+
+		switch {
+		case !p.Passed:
+			cells[p.Source][p.Target] = "FAIL"
+		case cells[p.Source][p.Target] == "":
+			cells[p.Source][p.Target] = "ok"
+		}
+	}
+	sort.Strings(sources)
+	sort.Strings(targets)
+
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	header := tableWriter.Row{"Source"}
+	for _, t := range targets {
+		header = append(header, t)
+	}
+	table.AppendHeader(header)
+
+	for _, s := range sources {
+		row := tableWriter.Row{s}
+		for _, t := range targets {
+			row = append(row, cells[s][t])
+		}
+		table.AppendRow(row)
+	}
+
+	table.Render()
+}
@@ -0,0 +1,323 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func convergenceCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "convergence",
+		Short: "measure the convergence of the lab routing after an event",
+		Long: "snapshot the route tables of the lab nodes before and after an event, a link set up or down\n" +
+			"or a shell command, and report the convergence time and the route deltas of the nodes\n" +
+			"reference: https://containerlab.dev/cmd/tools/convergence/",
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return validateConvergence(o)
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return convergenceFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().StringVarP(&o.ToolsConvergence.Link, "link", "", o.ToolsConvergence.Link,
+		"link set up or down as the event, given as one or both of its endpoints <node>:<interface>")
+	c.Flags().StringVarP(&o.ToolsConvergence.LinkState, "link-state", "", o.ToolsConvergence.LinkState,
+		"state the link is set to, up or down")
+	c.Flags().StringVarP(&o.ToolsConvergence.Exec, "exec", "", o.ToolsConvergence.Exec,
+		"shell command run as the event")
+	c.Flags().StringSliceVarP(&o.ToolsConvergence.Nodes, "nodes", "", o.ToolsConvergence.Nodes,
+		"comma separated list of nodes to snapshot, defaults to all nodes with a supported kind")
+	c.Flags().DurationVarP(&o.ToolsConvergence.Interval, "interval", "", o.ToolsConvergence.Interval,
+		"time between the route table snapshots")
+	c.Flags().DurationVarP(&o.ToolsConvergence.Settle, "settle", "", o.ToolsConvergence.Settle,
+		"time the route tables must stay unchanged for the lab to be converged")
+	c.Flags().DurationVarP(&o.ToolsConvergence.Timeout, "wait-timeout", "", o.ToolsConvergence.Timeout,
+		"maximum time to wait for the lab to converge")
+	c.Flags().StringVarP(&o.ToolsConvergence.Format, "format", "f", o.ToolsConvergence.Format,
+		"output format. One of [table, json]")
+	c.Flags().StringVarP(&o.ToolsConvergence.Output, "output", "o", o.ToolsConvergence.Output,
+		"path to write the report with the route table snapshots as JSON")
+
+	return c, nil
+}
+
+func validateConvergence(o *Options) error {
+	co := o.ToolsConvergence
+
+	if (co.Link == "") == (co.Exec == "") {
+		return fmt.Errorf("either --link or --exec must be set as the event")
+	}
+	if co.Link != "" && co.LinkState != string(clablinks.LinkStateUp) &&
+		co.LinkState != string(clablinks.LinkStateDown) {
+		return fmt.Errorf("--link-state must be up or down")
+	}
+	if co.Interval <= 0 || co.Settle <= 0 || co.Timeout <= 0 {
+		return fmt.Errorf("--interval, --settle and --wait-timeout must be positive")
+	}
+	if co.Format != "table" && co.Format != "json" {
+		return fmt.Errorf("output format %q is not supported, use 'table' or 'json'", co.Format)
+	}
+
+	if co.Link != "" {
+		clabutils.CheckAndGetRootPrivs()
+	}
+
+	return nil
+}
+
+func convergenceFn(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+	co := o.ToolsConvergence
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, false)
+	if err != nil {
+		return err
+	}
+
+	event := func() error {
+		log.Info("Running event", "command", co.Exec)
+		cmd := exec.CommandContext(ctx, "sh", "-c", co.Exec)
+		cmd.Stdout, cmd.Stderr = os.Stderr, os.Stderr
+		return cmd.Run()
+	}
+	if co.Link != "" {
+		if err := c.ResolveLinks(); err != nil {
+			return err
+		}
+
+		l, err := clablinks.FindLink(c.Links, co.Link)
+		if err != nil {
+			return err
+		}
+
+		event = func() error {
+			log.Info("Setting link", "link", co.Link, "state", co.LinkState)
+			return clablinks.SetLinkState(ctx, l, clablinks.LinkState(co.LinkState))
+		}
+	}
+
+	nodes := co.Nodes
+	if len(nodes) == 0 {
+		for n, cs := range allConfig {
+			if clabcoreconfig.RoutesSupportKind(cs.TargetNode.Kind) {
+				nodes = append(nodes, n)
+			}
+		}
+	}
+	if len(nodes) == 0 {
+		return fmt.Errorf("no nodes with a kind supporting route table snapshots")
+	}
+
+	var (
+		wg      sync.WaitGroup
+		m       sync.Mutex
+		readers = map[string]*clabcoreconfig.RouteReader{}
+		errs    []string
+	)
+	for _, n := range nodes {
+		cs, ok := allConfig[n]
+		if !ok {
+			return fmt.Errorf("node %q not found in the lab", n)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			r, err := clabcoreconfig.NewRouteReader(cs)
+
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %v", n, err))
+				return
+			}
+			readers[n] = r
+		}()
+	}
+	wg.Wait()
+
+	defer func() {
+		for _, r := range readers {
+			r.Close()
+		}
+	}()
+
+	if len(errs) > 0 {
+		sort.Strings(errs)
+		return fmt.Errorf("failed to connect to the nodes: %s", strings.Join(errs, "; "))
+	}
+
+	rep, err := clabcoreconfig.MeasureConvergence(ctx, readers, event, &clabcoreconfig.ConvergenceOptions{
+		Interval: co.Interval,
+		Settle:   co.Settle,
+		Timeout:  co.Timeout,
+	})
+	if err != nil {
+		return err
+	}
+
+	if co.Output != "" {
+		b, err := json.MarshalIndent(rep, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(co.Output, b, 0o644); err != nil { // skipcq: GSC-G306
+			return err
+		}
+		log.Info("Convergence report written", "path", co.Output)
+	}
+
+	if co.Format == "json" {
+		printConvergenceJSON(rep)
+	} else {
+		printConvergence(rep)
+	}
+
+	var failed []string
+	for n, cv := range rep.Nodes {
+		if cv.Err != nil {
+			failed = append(failed, n)
+		}
+	}
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("failed to snapshot the route tables of %s", strings.Join(failed, ", "))
+	}
+	if !rep.Converged {
+		return fmt.Errorf("the lab did not converge within %s", co.Timeout)
+	}
+
+	return nil
+}
+
+type convergenceJSON struct {
+	Node    string                     `json:"node"`
+	Time    string                     `json:"time,omitempty"`
+	Before  map[string]int             `json:"before,omitempty"`
+	After   map[string]int             `json:"after,omitempty"`
+	Delta   *clabcoreconfig.RouteDelta `json:"delta,omitempty"`
+	Error   string                     `json:"error,omitempty"`
+	Changed bool                       `json:"changed"`
+}
+
+func convergenceRows(rep *clabcoreconfig.ConvergenceReport) []*convergenceJSON {
+	var rows []*convergenceJSON
+	for _, cv := range rep.Nodes {
+		r := &convergenceJSON{Node: cv.Node, Changed: cv.Changed, Delta: cv.Delta}
+		if cv.Changed {
+			r.Time = cv.Time.String()
+		}
+		if cv.Before != nil {
+			r.Before = cv.Before.Summary()
+		}
+		if cv.After != nil {
+			r.After = cv.After.Summary()
+		}
+		if cv.Err != nil {
+			r.Error = cv.Err.Error()
+		}
+		rows = append(rows, r)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Node < rows[j].Node })
+
+	return rows
+}
+
+func printConvergenceJSON(rep *clabcoreconfig.ConvergenceReport) {
+	b, err := json.MarshalIndent(struct {
+		Time      string             `json:"time"`
+		Converged bool               `json:"converged"`
+		Nodes     []*convergenceJSON `json:"nodes"`
+	}{rep.Time.String(), rep.Converged, convergenceRows(rep)}, "", "  ")
+	if err != nil {
+		log.Errorf("failed to marshal the convergence report: %v", err)
+		return
+	}
+
+	fmt.Println(string(b))
+}
+
+// routeSummary formats the number of routes by protocol, e.g. "bgp:10 local:2".
+func routeSummary(s map[string]int) string {
+	protos := make([]string, 0, len(s))
+	for p := range s {
+		protos = append(protos, p)
+	}
+	sort.Strings(protos)
+
+	parts := make([]string, 0, len(protos))
+	for _, p := range protos {
+		parts = append(parts, fmt.Sprintf("%s:%d", p, s[p]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func printConvergence(rep *clabcoreconfig.ConvergenceReport) {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Node", "Routes Before", "Routes After", "Added", "Removed", "Changed", "Time"})
+
+	for _, r := range convergenceRows(rep) {
+		if r.Error != "" {
+			table.AppendRow(tableWriter.Row{r.Node, routeSummary(r.Before), "", "", "", "", "error: " + r.Error})
+			continue
+		}
+
+		t := "-"
+		if r.Changed {
+			t = r.Time
+		}
+		var added, removed, changed int
+		if r.Delta != nil {
+			added, removed, changed = len(r.Delta.Added), len(r.Delta.Removed), len(r.Delta.Changed)
+		}
+		table.AppendRow(tableWriter.Row{
+			r.Node, routeSummary(r.Before), routeSummary(r.After), added, removed, changed, t,
+		})
+	}
+
+	table.Render()
+
+	status := "converged"
+	if !rep.Converged {
+		status = "not converged"
+	}
+	fmt.Printf("Lab %s, convergence time %s\n", status, rep.Time)
+}
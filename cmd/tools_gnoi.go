@@ -0,0 +1,268 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+func gnoiCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "gnoi",
+		Short: "gNOI operations on the lab nodes",
+		Long: "run gNOI operations on a lab node using the node's credentials\n" +
+			"and the connection labels of the config engine gNMI transport\n" +
+			"reference: https://containerlab.dev/cmd/tools/gnoi/",
+	}
+
+	c.PersistentFlags().StringVarP(&o.ToolsGNOI.Node, "node", "n", o.ToolsGNOI.Node,
+		"name of the node as defined in the topology")
+	c.PersistentFlags().BoolVarP(&o.ToolsGNOI.LabCA, "lab-ca", "", o.ToolsGNOI.LabCA,
+		"verify the node certificate with the lab CA instead of skipping the verification")
+	c.MarkPersistentFlagRequired("node")
+
+	rebootC := &cobra.Command{
+		Use:          "reboot",
+		Short:        "reboot a node",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return gnoiReboot(cobraCmd, o)
+		},
+	}
+	c.AddCommand(rebootC)
+	rebootC.Flags().StringVarP(&o.ToolsGNOI.RebootMethod, "method", "", o.ToolsGNOI.RebootMethod,
+		"reboot method, one of: cold, warm")
+	rebootC.Flags().DurationVarP(&o.ToolsGNOI.RebootDelay, "delay", "", o.ToolsGNOI.RebootDelay,
+		"delay before the node reboots")
+	rebootC.Flags().StringVarP(&o.ToolsGNOI.RebootMessage, "message", "", o.ToolsGNOI.RebootMessage,
+		"reason of the reboot")
+	rebootC.Flags().BoolVarP(&o.ToolsGNOI.Force, "force", "", o.ToolsGNOI.Force,
+		"reboot without the node's sanity checks")
+
+	pingC := &cobra.Command{
+		Use:          "ping <destination>",
+		Short:        "ping from a node",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return gnoiPing(cobraCmd, args[0], o)
+		},
+	}
+	c.AddCommand(pingC)
+	pingC.Flags().IntVarP(&o.ToolsGNOI.PingCount, "count", "c", o.ToolsGNOI.PingCount,
+		"number of pings sent")
+	pingC.Flags().StringVarP(&o.ToolsGNOI.PingSource, "source", "", o.ToolsGNOI.PingSource,
+		"source address of the pings")
+	pingC.Flags().StringVarP(&o.ToolsGNOI.NetworkInstance, "network-instance", "", o.ToolsGNOI.NetworkInstance,
+		"network instance the pings are sent from")
+
+	putC := &cobra.Command{
+		Use:          "put <local-file> <remote-file>",
+		Short:        "transfer a file to a node",
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return gnoiPut(cobraCmd, args[0], args[1], o)
+		},
+	}
+	c.AddCommand(putC)
+	putC.Flags().StringVarP(&o.ToolsGNOI.Permissions, "permissions", "", o.ToolsGNOI.Permissions,
+		"octal permissions of the remote file")
+
+	certC := &cobra.Command{
+		Use:   "cert-install",
+		Short: "install a certificate on a node",
+		Long: "install a certificate and its key on a node together with the lab CA certificate,\n" +
+			"defaults to the node certificate issued by the lab CA on deploy",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return gnoiCertInstall(cobraCmd, o)
+		},
+	}
+	c.AddCommand(certC)
+	certC.Flags().StringVarP(&o.ToolsGNOI.CertID, "id", "", o.ToolsGNOI.CertID,
+		"id of the certificate on the node")
+	certC.Flags().StringVarP(&o.ToolsGNOI.CertFile, "cert", "", o.ToolsGNOI.CertFile,
+		"PEM certificate file, defaults to the node certificate in the lab directory")
+	certC.Flags().StringVarP(&o.ToolsGNOI.KeyFile, "key", "", o.ToolsGNOI.KeyFile,
+		"PEM key file, defaults to the node key in the lab directory")
+
+	return c, nil
+}
+
+// gnoiConnect connects to the gRPC server of the node selected with the --node flag.
+func gnoiConnect(o *Options) (*clabcore.CLab, *transport.GNMITransport, error) {
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	allConfig, err := clabcoreconfig.PrepareVars(c)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	cs, ok := allConfig[o.ToolsGNOI.Node]
+	if !ok {
+		return nil, nil, fmt.Errorf("node %q not found in the lab", o.ToolsGNOI.Node)
+	}
+	cs.DebugCount = o.Global.DebugCount
+
+	var caPEM []byte
+	if o.ToolsGNOI.LabCA {
+		caPEM, err = os.ReadFile(c.TopoPaths.CaCertAbsFilename())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read the lab CA certificate: %w", err)
+		}
+	}
+
+	tx, err := clabcoreconfig.ConnectGNOI(cs, caPEM)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return c, tx, nil
+}
+
+func gnoiReboot(cobraCmd *cobra.Command, o *Options) error {
+	method, err := transport.ParseGNOIRebootMethod(o.ToolsGNOI.RebootMethod)
+	if err != nil {
+		return err
+	}
+
+	_, tx, err := gnoiConnect(o)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	err = tx.Reboot(cobraCmd.Context(), method, o.ToolsGNOI.RebootDelay, o.ToolsGNOI.RebootMessage, o.ToolsGNOI.Force)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("%s: reboot requested", o.ToolsGNOI.Node)
+
+	return nil
+}
+
+func gnoiPing(cobraCmd *cobra.Command, destination string, o *Options) error {
+	_, tx, err := gnoiConnect(o)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	p := &transport.GNOIPing{
+		Destination:     destination,
+		Source:          o.ToolsGNOI.PingSource,
+		Count:           o.ToolsGNOI.PingCount,
+		NetworkInstance: o.ToolsGNOI.NetworkInstance,
+	}
+
+	var summary *transport.GNOIPingResponse
+	err = tx.Ping(cobraCmd.Context(), p, func(r *transport.GNOIPingResponse) {
+		if r.Sent > 0 {
+			summary = r
+		}
+		fmt.Println(r.String())
+	})
+	if err != nil {
+		return err
+	}
+
+	if summary != nil && summary.Received == 0 {
+		return fmt.Errorf("%s: no replies from %s", o.ToolsGNOI.Node, destination)
+	}
+
+	return nil
+}
+
+func gnoiPut(cobraCmd *cobra.Command, localFile, remoteFile string, o *Options) error {
+	perms, err := strconv.ParseUint(o.ToolsGNOI.Permissions, 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid permissions %q: %w", o.ToolsGNOI.Permissions, err)
+	}
+
+	f, err := os.Open(localFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, tx, err := gnoiConnect(o)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	err = tx.PutFile(cobraCmd.Context(), f, remoteFile, uint32(perms))
+	if err != nil {
+		return err
+	}
+
+	log.Infof("%s: transferred %s to %s", o.ToolsGNOI.Node, filepath.Base(localFile), remoteFile)
+
+	return nil
+}
+
+func gnoiCertInstall(cobraCmd *cobra.Command, o *Options) error {
+	c, tx, err := gnoiConnect(o)
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	certFile, keyFile := o.ToolsGNOI.CertFile, o.ToolsGNOI.KeyFile
+	if certFile == "" {
+		certFile = c.TopoPaths.NodeCertAbsFilename(o.ToolsGNOI.Node)
+	}
+	if keyFile == "" {
+		keyFile = c.TopoPaths.NodeCertKeyAbsFilename(o.ToolsGNOI.Node)
+	}
+
+	cert, err := os.ReadFile(certFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the certificate: %w", err)
+	}
+	key, err := os.ReadFile(keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read the certificate key: %w", err)
+	}
+
+	var caCerts [][]byte
+	if ca, err := os.ReadFile(c.TopoPaths.CaCertAbsFilename()); err == nil {
+		caCerts = append(caCerts, ca)
+	} else {
+		log.Warnf("lab CA certificate not installed: %v", err)
+	}
+
+	id := o.ToolsGNOI.CertID
+	if id == "" {
+		id = o.ToolsGNOI.Node
+	}
+
+	err = tx.InstallCertificate(cobraCmd.Context(), id, cert, key, caCerts...)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("%s: installed certificate %s", o.ToolsGNOI.Node, id)
+
+	return nil
+}
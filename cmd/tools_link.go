@@ -0,0 +1,171 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+const linkActionFlap = "flap"
+
+func linkCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "link",
+		Short: "runtime operations on the lab links",
+	}
+
+	linkSetCmd := &cobra.Command{
+		Use:   "set <link> up|down|flap",
+		Short: "bring a link up or down",
+		Long: "set the interfaces of both ends of a deployed veth link up or down, or flap them,\n" +
+			"the link is given as one of its endpoints <node>:<interface> or both of them\n" +
+			"<node>:<interface>,<node>:<interface>\n" +
+			"reference: https://containerlab.dev/cmd/tools/link/set/",
+		Args: cobra.ExactArgs(2), //nolint: mnd
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 1 {
+				return []string{
+					string(clablinks.LinkStateUp), string(clablinks.LinkStateDown), linkActionFlap,
+				}, cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			clabutils.CheckAndGetRootPrivs()
+			return nil
+		},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return linkSetFn(cobraCmd, o, args[0], args[1])
+		},
+	}
+
+	c.AddCommand(linkSetCmd)
+	linkSetCmd.Flags().IntVarP(&o.ToolsLink.Count, "count", "", o.ToolsLink.Count,
+		"number of times the link is flapped")
+	linkSetCmd.Flags().DurationVarP(&o.ToolsLink.Interval, "interval", "", o.ToolsLink.Interval,
+		"time the link stays down, and up between the flaps")
+
+	linkShapeCmd := &cobra.Command{
+		Use:   "shape <link>",
+		Short: "shape the rate of a link",
+		Long: "set or change the egress rate shaping of the interfaces of both ends of a deployed veth link\n" +
+			"with a token bucket filter while the lab runs, the link is given as for link set\n" +
+			"reference: https://containerlab.dev/cmd/tools/link/shape/",
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			if !o.ToolsLink.Reset && o.ToolsLink.Rate == 0 {
+				return fmt.Errorf("either --rate or --reset must be set")
+			}
+			if o.ToolsLink.Reset && o.ToolsLink.Rate != 0 {
+				return fmt.Errorf("--rate and --reset are mutually exclusive")
+			}
+			clabutils.CheckAndGetRootPrivs()
+			return nil
+		},
+		RunE: func(cobraCmd *cobra.Command, args []string) error {
+			return linkShapeFn(cobraCmd, o, args[0])
+		},
+	}
+
+	c.AddCommand(linkShapeCmd)
+	linkShapeCmd.Flags().Uint64VarP(&o.ToolsLink.Rate, "rate", "", o.ToolsLink.Rate, "link rate limit in kbit")
+	linkShapeCmd.Flags().Uint32VarP(&o.ToolsLink.Burst, "burst", "", o.ToolsLink.Burst,
+		"size of the token bucket in bytes, defaults to 10ms of the rate and at least 32KiB")
+	linkShapeCmd.Flags().DurationVarP(&o.ToolsLink.Latency, "latency", "", o.ToolsLink.Latency,
+		"maximum time a packet waits in the queue before it is dropped")
+	linkShapeCmd.Flags().BoolVarP(&o.ToolsLink.Reset, "reset", "", o.ToolsLink.Reset,
+		"remove the rate shaping of the link")
+
+	return c, nil
+}
+
+// resolveLink returns the deployed veth link of the lab given as one or both of its endpoints.
+func resolveLink(o *Options, ref string) (*clablinks.LinkVEth, error) {
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = c.ResolveLinks()
+	if err != nil {
+		return nil, err
+	}
+
+	return clablinks.FindLink(c.Links, ref)
+}
+
+func linkSetFn(cobraCmd *cobra.Command, o *Options, ref, action string) error {
+	ctx := cobraCmd.Context()
+
+	switch action {
+	case string(clablinks.LinkStateUp), string(clablinks.LinkStateDown):
+	case linkActionFlap:
+		if o.ToolsLink.Count < 1 {
+			return fmt.Errorf("the flap count must be at least 1")
+		}
+	default:
+		return fmt.Errorf("invalid link action %q, expected up, down or flap", action)
+	}
+
+	l, err := resolveLink(o, ref)
+	if err != nil {
+		return err
+	}
+
+	eps := l.GetEndpoints()
+
+	if action != linkActionFlap {
+		if err := clablinks.SetLinkState(ctx, l, clablinks.LinkState(action)); err != nil {
+			return err
+		}
+		log.Infof("Link %s ▪┄┄▪ %s set %s", eps[0], eps[1], action)
+
+		return nil
+	}
+
+	return clablinks.FlapLink(ctx, l, o.ToolsLink.Count, o.ToolsLink.Interval)
+}
+
+func linkShapeFn(cobraCmd *cobra.Command, o *Options, ref string) error {
+	l, err := resolveLink(o, ref)
+	if err != nil {
+		return err
+	}
+
+	eps := l.GetEndpoints()
+
+	if o.ToolsLink.Reset {
+		if err := clablinks.ShapeLink(cobraCmd.Context(), l, nil); err != nil {
+			return err
+		}
+		log.Infof("Removed the rate shaping of link %s ▪┄┄▪ %s", eps[0], eps[1])
+
+		return nil
+	}
+
+	err = clablinks.ShapeLink(cobraCmd.Context(), l, &clablinks.LinkShaping{
+		Rate:    o.ToolsLink.Rate,
+		Burst:   o.ToolsLink.Burst,
+		Latency: o.ToolsLink.Latency,
+	})
+	if err != nil {
+		return err
+	}
+	log.Infof("Shaped link %s ▪┄┄▪ %s to %d kbit", eps[0], eps[1], o.ToolsLink.Rate)
+
+	return nil
+}
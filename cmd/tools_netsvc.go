@@ -0,0 +1,312 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcorenetsvc "github.com/srl-labs/containerlab/core/netsvc"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	netsvc             = "netsvc"
+	netsvcLabDirSuffix = "netsvc"
+)
+
+// NetSvcNode implements runtime.Node interface for the NTP and DNS service container.
+type NetSvcNode struct {
+	config *clabtypes.NodeConfig
+}
+
+func netSvcCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   netsvc,
+		Short: "NTP and DNS service operations",
+		Long: "Start or stop an NTP and DNS service container for a lab, serving the clock of the host\n" +
+			"and the names of the lab nodes, with its address available to the config templates of the nodes",
+	}
+
+	netsvcStartCmd := &cobra.Command{
+		Use:   "start",
+		Short: "start an NTP and DNS service for a lab",
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return netsvcStart(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(netsvcStartCmd)
+
+	netsvcStartCmd.Flags().StringVarP(&o.Global.TopologyName, "lab", "l", o.Global.TopologyName,
+		"name of the lab to start the service for")
+	netsvcStartCmd.Flags().StringSliceVarP(&o.ToolsNetSvc.Services, "service", "", o.ToolsNetSvc.Services,
+		"services of the container, any of: "+strings.Join(clabcorenetsvc.Services, ", "))
+	netsvcStartCmd.Flags().StringVarP(&o.ToolsNetSvc.ContainerName, "name", "", o.ToolsNetSvc.ContainerName,
+		"name of the service container (defaults to clab-<labname>-netsvc)")
+	netsvcStartCmd.Flags().StringVarP(&o.ToolsNetSvc.Image, "image", "i", o.ToolsNetSvc.Image,
+		"container image to use for the service (defaults to "+clabcorenetsvc.Image+
+			", chrony and dnsmasq are installed when missing from the image)")
+	netsvcStartCmd.Flags().StringVarP(&o.ToolsNetSvc.Domain, "domain", "d", o.ToolsNetSvc.Domain,
+		"DNS domain of the lab nodes (defaults to <labname>.clab)")
+	netsvcStartCmd.Flags().StringSliceVarP(&o.ToolsNetSvc.Upstream, "upstream", "", o.ToolsNetSvc.Upstream,
+		"upstream DNS servers the other names are forwarded to (defaults to the DNS servers of the host)")
+	netsvcStartCmd.Flags().StringVarP(&o.ToolsNetSvc.Owner, "owner", "o", o.ToolsNetSvc.Owner,
+		"lab owner name for the service container")
+
+	netsvcStopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "stop the NTP and DNS service of a lab",
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return netsvcStop(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(netsvcStopCmd)
+
+	netsvcStopCmd.Flags().StringVarP(&o.Global.TopologyName, "lab", "l", o.Global.TopologyName,
+		"name of the lab where the service is running")
+
+	return c, nil
+}
+
+// NewNetSvcNode creates a new NTP and DNS service node configuration
+// with the config dir mounted in the container and running its entrypoint script.
+func NewNetSvcNode(name, image, network, configDir string, labels map[string]string) *NetSvcNode {
+	log.Debugf("Creating NetSvcNode: name=%s, image=%s, network=%s, configDir=%s",
+		name, image, network, configDir)
+
+	nodeConfig := &clabtypes.NodeConfig{
+		LongName:   name,
+		ShortName:  name,
+		Image:      image,
+		MgmtNet:    network,
+		Labels:     labels,
+		Entrypoint: "sh",
+		Cmd:        clabcorenetsvc.ConfigDir + "/" + clabcorenetsvc.EntrypointFile,
+		Binds:      []string{configDir + ":" + clabcorenetsvc.ConfigDir + ":ro"},
+	}
+
+	return &NetSvcNode{
+		config: nodeConfig,
+	}
+}
+
+func (n *NetSvcNode) Config() *clabtypes.NodeConfig {
+	return n.config
+}
+
+func (*NetSvcNode) GetEndpoints() []clablinks.Endpoint {
+	return nil
+}
+
+func netsvcStart(cobraCmd *cobra.Command, o *Options) error { //nolint: funlen
+	ctx := cobraCmd.Context()
+
+	clabInstance, err := clabcore.NewclabFromTopologyFileOrLabName(ctx, o.Global.TopologyFile,
+		o.Global.TopologyName, o.Global.VarsFile, o.Global.Runtime, o.Global.DebugCount > 0,
+		o.Global.Timeout, o.Destroy.GracefulShutdown)
+	if err != nil {
+		return err
+	}
+
+	labName := clabInstance.Config.Name
+
+	server, err := clabcorenetsvc.NewServer(labName, o.ToolsNetSvc.Domain, o.ToolsNetSvc.Services)
+	if err != nil {
+		return err
+	}
+
+	for _, s := range []string{clabInstance.Config.Mgmt.IPv4Subnet, clabInstance.Config.Mgmt.IPv6Subnet} {
+		if s != "" {
+			server.Clients = append(server.Clients, s)
+		}
+	}
+
+	server.Upstream = o.ToolsNetSvc.Upstream
+	if server.DNS && len(server.Upstream) == 0 {
+		server.Upstream, err = clabutils.ExtractDNSServersFromResolvConf(os.DirFS("/"),
+			[]string{"etc/resolv.conf", "run/systemd/resolve/resolv.conf"})
+		if err != nil {
+			return err
+		}
+	}
+
+	// the nodes of the lab are resolved by their short and long names
+	labContainers, err := clabInstance.ListContainers(ctx, clabcore.WithListLabName(labName))
+	if err != nil {
+		return err
+	}
+	for idx := range labContainers {
+		cnt := &labContainers[idx]
+		addrs := []string{cnt.NetworkSettings.IPv4addr, cnt.NetworkSettings.IPv6addr}
+		server.AddHost(cnt.Labels[clablabels.NodeName], addrs...)
+		server.AddHost(cnt.Labels[clablabels.LongName], addrs...)
+	}
+
+	networkName := clabInstance.Config.Mgmt.Network
+	if networkName == "" {
+		networkName = "clab-" + labName
+	}
+
+	if o.ToolsNetSvc.ContainerName == "" {
+		o.ToolsNetSvc.ContainerName = fmt.Sprintf("clab-%s-%s", labName, netsvc)
+		log.Debugf("Container name not provided, generated name: %s", o.ToolsNetSvc.ContainerName)
+	}
+	server.Container = o.ToolsNetSvc.ContainerName
+
+	image := o.ToolsNetSvc.Image
+	if image == "" {
+		image = clabcorenetsvc.Image
+	}
+
+	_, rinit, err := clabcore.RuntimeInitializer(o.Global.Runtime)
+	if err != nil {
+		return fmt.Errorf("failed to get runtime initializer for '%s': %w", o.Global.Runtime, err)
+	}
+
+	rt := rinit()
+
+	err = rt.Init(
+		clabruntime.WithConfig(&clabruntime.RuntimeConfig{Timeout: o.Global.Timeout}),
+		clabruntime.WithMgmtNet(&clabtypes.MgmtNet{Network: networkName}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize runtime: %w", err)
+	}
+
+	filter := []*clabtypes.GenericFilter{{FilterType: "name", Match: o.ToolsNetSvc.ContainerName}}
+
+	containers, err := rt.ListContainers(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	if len(containers) > 0 {
+		return fmt.Errorf("container %s already exists", o.ToolsNetSvc.ContainerName)
+	}
+
+	log.Infof("Pulling image %s...", image)
+	if err := rt.PullImage(ctx, image, clabtypes.PullPolicyIfNotPresent); err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", image, err)
+	}
+
+	configDir := filepath.Join(clabInstance.TopoPaths.TopologyLabDir(), netsvcLabDirSuffix)
+	clabutils.CreateDirectory(configDir, 0o755)
+
+	for name, b := range server.ConfigFiles() {
+		if err := os.WriteFile(filepath.Join(configDir, name), b, 0o644); err != nil { // skipcq: GSC-G306
+			return err
+		}
+	}
+
+	owner := o.ToolsNetSvc.Owner
+	if owner == "" {
+		owner = clabutils.GetOwner()
+	}
+
+	labelsMap := createLabelsMap(
+		clabInstance.TopoPaths.TopologyFilenameAbsPath(),
+		labName,
+		o.ToolsNetSvc.ContainerName,
+		owner,
+		netsvc,
+	)
+
+	log.Infof("Creating NTP and DNS service container %s on network '%s'", o.ToolsNetSvc.ContainerName, networkName)
+	netsvcNode := NewNetSvcNode(o.ToolsNetSvc.ContainerName, image, networkName, configDir, labelsMap)
+
+	id, err := rt.CreateContainer(ctx, netsvcNode.Config())
+	if err != nil {
+		return fmt.Errorf("failed to create NTP and DNS service container: %w", err)
+	}
+
+	if _, err := rt.StartContainer(ctx, id, netsvcNode); err != nil {
+		// Clean up on failure
+		rt.DeleteContainer(ctx, o.ToolsNetSvc.ContainerName)
+		return fmt.Errorf("failed to start NTP and DNS service container: %w", err)
+	}
+
+	containers, err = rt.ListContainers(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+	if len(containers) > 0 {
+		server.Address = containers[0].NetworkSettings.IPv4addr
+	}
+	if server.Address == "" {
+		log.Warnf("failed to get the management address of the NTP and DNS service container %s",
+			o.ToolsNetSvc.ContainerName)
+	}
+
+	if err := server.Save(clabInstance.TopoPaths.NetSvcFileAbsPath()); err != nil {
+		return err
+	}
+
+	log.Info("NTP and DNS service started", "container", o.ToolsNetSvc.ContainerName,
+		"address", server.Address, "ntp", server.NTP, "dns", server.DNS, "domain", server.Domain,
+		"hosts", len(server.Hosts), "note",
+		"The client config of the nodes is rendered by the netsvc templates with 'containerlab config'")
+
+	return nil
+}
+
+func netsvcStop(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	clabInstance, err := clabcore.NewclabFromTopologyFileOrLabName(ctx, o.Global.TopologyFile,
+		o.Global.TopologyName, o.Global.VarsFile, o.Global.Runtime, o.Global.DebugCount > 0,
+		o.Global.Timeout, o.Destroy.GracefulShutdown)
+	if err != nil {
+		return err
+	}
+
+	path := clabInstance.TopoPaths.NetSvcFileAbsPath()
+
+	server, err := clabcorenetsvc.LoadServer(path)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return fmt.Errorf("lab %s has no NTP and DNS service", clabInstance.Config.Name)
+	}
+
+	_, rinit, err := clabcore.RuntimeInitializer(o.Global.Runtime)
+	if err != nil {
+		return fmt.Errorf("failed to get runtime initializer: %w", err)
+	}
+
+	rt := rinit()
+	err = rt.Init(clabruntime.WithConfig(&clabruntime.RuntimeConfig{Timeout: o.Global.Timeout}))
+	if err != nil {
+		return fmt.Errorf("failed to initialize runtime: %w", err)
+	}
+
+	log.Infof("Removing NTP and DNS service container %s", server.Container)
+	if err := rt.DeleteContainer(ctx, server.Container); err != nil {
+		return fmt.Errorf("failed to remove NTP and DNS service container: %w", err)
+	}
+
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(filepath.Join(clabInstance.TopoPaths.TopologyLabDir(), netsvcLabDirSuffix)); err != nil {
+		return err
+	}
+
+	log.Infof("NTP and DNS service container %s removed", server.Container)
+	return nil
+}
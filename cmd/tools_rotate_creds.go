@@ -0,0 +1,141 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func rotateCredsCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "rotate-creds",
+		Short: "change the credentials of the lab nodes",
+		Long: "change the default credentials of the lab nodes to the given username and password\n" +
+			"with the config commands of their kind, and store them in the lab directory\n" +
+			"so that the following config runs log in with the new credentials\n" +
+			"reference: https://containerlab.dev/cmd/tools/rotate-creds/",
+		SilenceUsage: true,
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return rotateCredsFn(cobraCmd, o)
+		},
+	}
+
+	c.Flags().StringVarP(&o.ToolsRotateCreds.Username, "username", "u", o.ToolsRotateCreds.Username,
+		"new username, defaults to the current username of every node")
+	c.Flags().StringVarP(&o.ToolsRotateCreds.Password, "password", "p", o.ToolsRotateCreds.Password,
+		"new password, prompted for when not set")
+	c.Flags().StringSliceVarP(&o.ToolsRotateCreds.Nodes, "nodes", "", o.ToolsRotateCreds.Nodes,
+		"comma separated list of nodes to change the credentials of, defaults to all nodes")
+
+	return c, nil
+}
+
+func rotateCredsFn(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	password := o.ToolsRotateCreds.Password
+	if password == "" {
+		if !clabutils.IsTerminal(os.Stdin.Fd()) {
+			return fmt.Errorf("no --password set and stdin is not a terminal to prompt for it")
+		}
+
+		fmt.Print("New password: ")
+		var err error
+		password, err = clabutils.ReadPasswordFromTerminal()
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, false)
+	if err != nil {
+		return err
+	}
+
+	nodes := o.ToolsRotateCreds.Nodes
+	if len(nodes) == 0 {
+		for n := range allConfig {
+			nodes = append(nodes, n)
+		}
+	}
+	sort.Strings(nodes)
+
+	var (
+		wg      sync.WaitGroup
+		m       sync.Mutex
+		rotated = map[string][]string{}
+		failed  []string
+	)
+	for _, n := range nodes {
+		cs, ok := allConfig[n]
+		if !ok {
+			return fmt.Errorf("node %q not found in the lab", n)
+		}
+
+		username := o.ToolsRotateCreds.Username
+		if username == "" && len(cs.Credentials) > 0 {
+			username = cs.Credentials[0]
+		}
+
+		if _, err := clabcoreconfig.CredentialCommands(cs.TargetNode.Kind, username, password); err != nil {
+			log.Warnf("%s: skipped: %s", n, err)
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := clabcoreconfig.RotateCredentials(ctx, cs, username, password)
+
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				log.Errorf("%s: failed to change the credentials: %s", n, cs.Redactor.Redact(err.Error()))
+				failed = append(failed, n)
+				return
+			}
+			log.Infof("%s: changed the credentials of user %s", n, username)
+			rotated[n] = cs.Credentials
+		}()
+	}
+	wg.Wait()
+
+	if len(rotated) > 0 {
+		clabutils.CreateDirectory(c.TopoPaths.TopologyLabDir(), 0o755)
+
+		path := clabcoreconfig.CredentialsPath(c.TopoPaths.TopologyLabDir())
+		if err := clabcoreconfig.SaveCredentials(path, rotated); err != nil {
+			return fmt.Errorf("failed to store the new credentials: %w", err)
+		}
+		log.Info("New credentials stored", "path", path)
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return fmt.Errorf("failed to change the credentials of %d nodes: %s", len(failed), strings.Join(failed, ", "))
+	}
+
+	return nil
+}
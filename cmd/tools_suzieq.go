@@ -0,0 +1,253 @@
+// Copyright 2025
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	suzieq             = "suzieq"
+	suzieqImage        = "netenglabs/suzieq:latest"
+	suzieqHomeDir      = "/home/suzieq"
+	suzieqInventory    = suzieqHomeDir + "/inventory.yml"
+	suzieqParquetDir   = suzieqHomeDir + "/parquet"
+	suzieqLabDirSuffix = "suzieq"
+)
+
+// SuzieqNode implements runtime.Node interface for the SuzieQ poller container.
+type SuzieqNode struct {
+	config *clabtypes.NodeConfig
+}
+
+func suzieqCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   suzieq,
+		Short: "SuzieQ poller operations",
+		Long: "Start or stop a SuzieQ poller container collecting the operational state of the lab nodes\n" +
+			"using the SuzieQ inventory generated for the lab",
+	}
+
+	suzieqStartCmd := &cobra.Command{
+		Use:   "start",
+		Short: "start SuzieQ poller for a lab",
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return suzieqStart(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(suzieqStartCmd)
+
+	suzieqStartCmd.Flags().StringVarP(&o.Global.TopologyName, "lab", "l", o.Global.TopologyName,
+		"name of the lab to start SuzieQ poller for")
+	suzieqStartCmd.Flags().StringVarP(&o.ToolsSuzieq.ContainerName, "name", "", o.ToolsSuzieq.ContainerName,
+		"name of the SuzieQ container (defaults to clab-<labname>-suzieq)")
+	suzieqStartCmd.Flags().StringVarP(&o.ToolsSuzieq.Image, "image", "i", o.ToolsSuzieq.Image,
+		"container image to use for SuzieQ")
+	suzieqStartCmd.Flags().StringVarP(&o.ToolsSuzieq.Owner, "owner", "o", o.ToolsSuzieq.Owner,
+		"lab owner name for the SuzieQ container")
+
+	suzieqStopCmd := &cobra.Command{
+		Use:   "stop",
+		Short: "stop SuzieQ poller of a lab",
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return suzieqStop(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(suzieqStopCmd)
+
+	suzieqStopCmd.Flags().StringVarP(&o.Global.TopologyName, "lab", "l", o.Global.TopologyName,
+		"name of the lab where SuzieQ poller is running")
+	suzieqStopCmd.Flags().StringVarP(&o.ToolsSuzieq.ContainerName, "name", "", o.ToolsSuzieq.ContainerName,
+		"name of the SuzieQ container (defaults to clab-<labname>-suzieq)")
+
+	return c, nil
+}
+
+// NewSuzieqNode creates a new SuzieQ poller node configuration.
+// The poller uses the lab's SuzieQ inventory and stores the collected data in the dataDir.
+func NewSuzieqNode(name, image, network, inventory, dataDir string, labels map[string]string) *SuzieqNode {
+	log.Debugf("Creating SuzieqNode: name=%s, image=%s, network=%s, inventory=%s, dataDir=%s",
+		name, image, network, inventory, dataDir)
+
+	nodeConfig := &clabtypes.NodeConfig{
+		LongName:   name,
+		ShortName:  name,
+		Image:      image,
+		Entrypoint: "sq-poller",
+		Cmd:        "-I " + suzieqInventory,
+		MgmtNet:    network,
+		Labels:     labels,
+		Binds: []string{
+			inventory + ":" + suzieqInventory + ":ro",
+			dataDir + ":" + suzieqParquetDir,
+		},
+	}
+
+	return &SuzieqNode{
+		config: nodeConfig,
+	}
+}
+
+func (n *SuzieqNode) Config() *clabtypes.NodeConfig {
+	return n.config
+}
+
+func (*SuzieqNode) GetEndpoints() []clablinks.Endpoint {
+	return nil
+}
+
+func suzieqStart(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	clabInstance, err := clabcore.NewclabFromTopologyFileOrLabName(ctx, o.Global.TopologyFile,
+		o.Global.TopologyName, o.Global.VarsFile, o.Global.Runtime, o.Global.DebugCount > 0,
+		o.Global.Timeout, o.Destroy.GracefulShutdown)
+	if err != nil {
+		return err
+	}
+
+	labName := clabInstance.Config.Name
+
+	inventory := clabInstance.TopoPaths.SuzieqInventoryFileAbsPath()
+	if !clabutils.FileExists(inventory) {
+		return fmt.Errorf("SuzieQ inventory %s not found, make sure the lab is deployed", inventory)
+	}
+
+	networkName := clabInstance.Config.Mgmt.Network
+	if networkName == "" {
+		networkName = "clab-" + labName
+	}
+
+	if o.ToolsSuzieq.ContainerName == "" {
+		o.ToolsSuzieq.ContainerName = fmt.Sprintf("clab-%s-%s", labName, suzieq)
+		log.Debugf("Container name not provided, generated name: %s", o.ToolsSuzieq.ContainerName)
+	}
+
+	_, rinit, err := clabcore.RuntimeInitializer(o.Global.Runtime)
+	if err != nil {
+		return fmt.Errorf("failed to get runtime initializer for '%s': %w", o.Global.Runtime, err)
+	}
+
+	rt := rinit()
+
+	err = rt.Init(
+		clabruntime.WithConfig(&clabruntime.RuntimeConfig{Timeout: o.Global.Timeout}),
+		clabruntime.WithMgmtNet(&clabtypes.MgmtNet{Network: networkName}),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to initialize runtime: %w", err)
+	}
+
+	filter := []*clabtypes.GenericFilter{{FilterType: "name", Match: o.ToolsSuzieq.ContainerName}}
+
+	containers, err := rt.ListContainers(ctx, filter)
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	if len(containers) > 0 {
+		return fmt.Errorf("container %s already exists", o.ToolsSuzieq.ContainerName)
+	}
+
+	log.Infof("Pulling image %s...", o.ToolsSuzieq.Image)
+	if err := rt.PullImage(ctx, o.ToolsSuzieq.Image, clabtypes.PullPolicyIfNotPresent); err != nil {
+		return fmt.Errorf("failed to pull image %s: %w", o.ToolsSuzieq.Image, err)
+	}
+
+	// collected data is kept in the lab directory to survive poller restarts
+	dataDir := filepath.Join(clabInstance.TopoPaths.TopologyLabDir(), suzieqLabDirSuffix)
+	clabutils.CreateDirectory(dataDir, 0o777)
+	// the poller runs as a non-root user
+	if err := os.Chmod(dataDir, 0o777); err != nil { // skipcq: GSC-G302
+		return err
+	}
+
+	owner := o.ToolsSuzieq.Owner
+	if owner == "" {
+		owner = clabutils.GetOwner()
+	}
+
+	labelsMap := createLabelsMap(
+		clabInstance.TopoPaths.TopologyFilenameAbsPath(),
+		labName,
+		o.ToolsSuzieq.ContainerName,
+		owner,
+		suzieq,
+	)
+
+	log.Infof("Creating SuzieQ container %s on network '%s'", o.ToolsSuzieq.ContainerName, networkName)
+	sqNode := NewSuzieqNode(o.ToolsSuzieq.ContainerName, o.ToolsSuzieq.Image, networkName,
+		inventory, dataDir, labelsMap)
+
+	id, err := rt.CreateContainer(ctx, sqNode.Config())
+	if err != nil {
+		return fmt.Errorf("failed to create SuzieQ container: %w", err)
+	}
+
+	if _, err := rt.StartContainer(ctx, id, sqNode); err != nil {
+		// Clean up on failure
+		rt.DeleteContainer(ctx, o.ToolsSuzieq.ContainerName)
+		return fmt.Errorf("failed to start SuzieQ container: %w", err)
+	}
+
+	log.Info("SuzieQ poller started", "container", o.ToolsSuzieq.ContainerName, "data", dataDir, "note",
+		fmt.Sprintf("Query the collected state with:\ndocker run -it --rm -v %s:%s %s -c 'suzieq-cli'",
+			dataDir, suzieqParquetDir, o.ToolsSuzieq.Image))
+
+	return nil
+}
+
+func suzieqStop(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	clabInstance, err := clabcore.NewclabFromTopologyFileOrLabName(ctx, o.Global.TopologyFile,
+		o.Global.TopologyName, o.Global.VarsFile, o.Global.Runtime, o.Global.DebugCount > 0,
+		o.Global.Timeout, o.Destroy.GracefulShutdown)
+	if err != nil {
+		return err
+	}
+
+	containerName := o.ToolsSuzieq.ContainerName
+	if containerName == "" {
+		containerName = fmt.Sprintf("clab-%s-%s", clabInstance.Config.Name, suzieq)
+	}
+
+	_, rinit, err := clabcore.RuntimeInitializer(o.Global.Runtime)
+	if err != nil {
+		return fmt.Errorf("failed to get runtime initializer: %w", err)
+	}
+
+	rt := rinit()
+	err = rt.Init(clabruntime.WithConfig(&clabruntime.RuntimeConfig{Timeout: o.Global.Timeout}))
+	if err != nil {
+		return fmt.Errorf("failed to initialize runtime: %w", err)
+	}
+
+	log.Infof("Removing SuzieQ container %s", containerName)
+	if err := rt.DeleteContainer(ctx, containerName); err != nil {
+		return fmt.Errorf("failed to remove SuzieQ container: %w", err)
+	}
+
+	log.Infof("SuzieQ container %s removed, collected data is kept in the lab directory", containerName)
+	return nil
+}
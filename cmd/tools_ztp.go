@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"github.com/charmbracelet/log"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreztp "github.com/srl-labs/containerlab/core/ztp"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func ztpCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "ztp",
+		Short: "zero-touch provisioning of the lab nodes",
+	}
+
+	ztpServeCmd := &cobra.Command{
+		Use:   "serve",
+		Short: "serve the day-0 configs and scripts of the lab nodes",
+		Long: "run the DHCP, TFTP and HTTP servers on the management network of a deployed lab,\n" +
+			"serving the day-0 configs and scripts of the nodes identified by their MAC address or serial number\n" +
+			"reference: https://containerlab.dev/cmd/tools/ztp/serve/",
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(cobraCmd *cobra.Command, _ []string) error {
+			return ztpServeFn(cobraCmd, o)
+		},
+	}
+
+	c.AddCommand(ztpServeCmd)
+	ztpServeCmd.Flags().IntVarP(&o.ToolsZTP.HTTPPort, "http-port", "", o.ToolsZTP.HTTPPort,
+		"port of the HTTP server")
+	ztpServeCmd.Flags().DurationVarP(&o.ToolsZTP.Lease, "lease", "", o.ToolsZTP.Lease,
+		"lease time of the management addresses")
+
+	return c, nil
+}
+
+func ztpServeFn(cobraCmd *cobra.Command, o *Options) error {
+	ctx := cobraCmd.Context()
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithRuntime(o.Global.Runtime, &clabruntime.RuntimeConfig{
+			Debug:   o.Global.DebugCount > 0,
+			Timeout: o.Global.Timeout,
+		}),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	s, err := clabcoreztp.NewLabServer(ctx, c, o.ToolsZTP.HTTPPort)
+	if err != nil {
+		return err
+	}
+	s.Lease = o.ToolsZTP.Lease
+
+	for _, e := range s.Entries() {
+		log.Info("Serving node", "node", e.Node, "mac", e.MAC, "serial", e.Serial, "ip", e.IP,
+			"boot", s.URL(e, e.Boot))
+	}
+
+	log.Info("ZTP server started", "interface", s.Interface, "address", s.ServerIP, "http-port", s.HTTPPort)
+
+	return s.Serve(ctx)
+}
@@ -0,0 +1,259 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	tableWriter "github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreconfig "github.com/srl-labs/containerlab/core/config"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func verifyCmd(o *Options) (*cobra.Command, error) {
+	c := &cobra.Command{
+		Use:   "verify",
+		Short: "verify a lab against its topology and the host",
+	}
+
+	wiringCmd := &cobra.Command{
+		Use:   "wiring",
+		Short: "verify the links of a running lab with LLDP",
+		Long: "read the LLDP neighbors of the lab nodes and verify that the discovered adjacencies\n" +
+			"match the links of the topology, flagging the miscabled and down links\n" +
+			"reference: https://containerlab.dev/cmd/verify/wiring/",
+		SilenceUsage: true,
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return verifyWiringFn(o)
+		},
+	}
+
+	c.AddCommand(wiringCmd)
+	wiringCmd.Flags().StringVarP(&o.Verify.Format, "format", "f", o.Verify.Format,
+		"output format. One of [table, json]")
+
+	hostCmd := &cobra.Command{
+		Use:   "host",
+		Short: "verify the host settings allow the lab kinds to run",
+		Long: "check the devices and security options the kinds of the lab nodes require\n" +
+			"against the host, reporting the settings blocking a kind\n" +
+			"reference: https://containerlab.dev/cmd/verify/host/",
+		SilenceUsage: true,
+		PreRunE: func(_ *cobra.Command, _ []string) error {
+			return clabutils.CheckAndGetRootPrivs()
+		},
+		RunE: func(_ *cobra.Command, _ []string) error {
+			return verifyHostFn(o)
+		},
+	}
+
+	c.AddCommand(hostCmd)
+	hostCmd.Flags().StringVarP(&o.Verify.Format, "format", "f", o.Verify.Format,
+		"output format. One of [table, json]")
+
+	return c, nil
+}
+
+type wiringJSON struct {
+	Endpoint string `json:"endpoint"`
+	Peer     string `json:"peer"`
+	Status   string `json:"status"`
+	Detail   string `json:"detail,omitempty"`
+}
+
+func verifyWiringFn(o *Options) error {
+	if o.Verify.Format != "table" && o.Verify.Format != "json" {
+		return fmt.Errorf("output format %q is not supported, use 'table' or 'json'", o.Verify.Format)
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	err = c.ResolveLinks()
+	if err != nil {
+		return err
+	}
+
+	allConfig, err := prepareConfig(c, o, false)
+	if err != nil {
+		return err
+	}
+
+	links := clabcoreconfig.WiringLinks(c)
+	if len(links) == 0 {
+		return fmt.Errorf("no links between the lab nodes to verify")
+	}
+
+	var (
+		wg        sync.WaitGroup
+		m         sync.Mutex
+		neighbors = map[string][]*clabcoreconfig.LLDPNeighbor{}
+		nodeErrs  = map[string]error{}
+	)
+	for n, cs := range allConfig {
+		if !clabcoreconfig.LLDPSupportsKind(cs.TargetNode.Kind) {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			nbrs, err := clabcoreconfig.LLDPNeighbors(cs)
+
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				log.Errorf("%s: failed to read the LLDP neighbors: %v", n, err)
+				nodeErrs[n] = err
+				return
+			}
+			neighbors[n] = nbrs
+		}()
+	}
+	wg.Wait()
+
+	results := clabcoreconfig.VerifyWiring(links, neighbors, nodeErrs)
+
+	if o.Verify.Format == "json" {
+		out := make([]*wiringJSON, 0, len(results))
+		for _, r := range results {
+			out = append(out, &wiringJSON{
+				Endpoint: r.Endpoint.String(),
+				Peer:     r.Peer.String(),
+				Status:   r.Status,
+				Detail:   r.Detail,
+			})
+		}
+
+		b, err := json.MarshalIndent(out, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		printWiring(results)
+	}
+
+	var failed int
+	for _, r := range results {
+		if r.Status == clabcoreconfig.WiringDown || r.Status == clabcoreconfig.WiringMiscabled {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d link endpoints are down or miscabled", failed, len(results))
+	}
+
+	return nil
+}
+
+func printWiring(results []*clabcoreconfig.WiringResult) {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Endpoint", "Peer", "Status", "Detail"})
+
+	for _, r := range results {
+		status := r.Status
+		switch r.Status {
+		case clabcoreconfig.WiringOK:
+			status = text.FgGreen.Sprint(status)
+		case clabcoreconfig.WiringDown, clabcoreconfig.WiringMiscabled:
+			status = text.FgRed.Sprint(status)
+		}
+
+		table.AppendRow(tableWriter.Row{r.Endpoint.String(), r.Peer.String(), status, r.Detail})
+	}
+
+	table.Render()
+}
+
+func verifyHostFn(o *Options) error {
+	if o.Verify.Format != "table" && o.Verify.Format != "json" {
+		return fmt.Errorf("output format %q is not supported, use 'table' or 'json'", o.Verify.Format)
+	}
+
+	c, err := clabcore.NewContainerLab(
+		clabcore.WithTimeout(o.Global.Timeout),
+		clabcore.WithTopoPath(o.Global.TopologyFile, o.Global.VarsFile),
+		clabcore.WithDebug(o.Global.DebugCount > 0),
+	)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Host security modules", "selinux", clabtypes.SELinuxMode(),
+		"apparmor", clabtypes.AppArmorEnabled())
+
+	results := c.SecurityPreflight()
+
+	if o.Verify.Format == "json" {
+		b, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(b))
+	} else {
+		printHostPreflight(results)
+	}
+
+	var blocked int
+	for _, r := range results {
+		if len(r.Issues) > 0 {
+			blocked++
+		}
+	}
+	if blocked > 0 {
+		return fmt.Errorf("%d of %d kinds are blocked by the host settings", blocked, len(results))
+	}
+
+	return nil
+}
+
+func printHostPreflight(results []*clabcore.KindPreflight) {
+	table := tableWriter.NewWriter()
+	table.SetOutputMirror(os.Stdout)
+	table.SetStyle(tableWriter.StyleRounded)
+	table.Style().Format.HeaderAlign = text.AlignCenter
+	table.Style().Color = tableWriter.ColorOptions{
+		Header: text.Colors{text.Bold},
+	}
+
+	table.AppendHeader(tableWriter.Row{"Kind", "Nodes", "Status", "Blocking host settings"})
+
+	for _, r := range results {
+		status := text.FgGreen.Sprint("ok")
+		if len(r.Issues) > 0 {
+			status = text.FgRed.Sprint("blocked")
+		}
+
+		table.AppendRow(tableWriter.Row{
+			r.Kind, strings.Join(r.Nodes, "\n"), status, strings.Join(r.Issues, "\n"),
+		})
+	}
+
+	table.Render()
+}
@@ -0,0 +1,222 @@
+// Package aaa generates the config of the RADIUS and TACACS+ servers started for a lab
+// and keeps the address and the secret of the running server for the node config templates.
+package aaa
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	clabcore "github.com/srl-labs/containerlab/core"
+	claberrors "github.com/srl-labs/containerlab/errors"
+)
+
+// Server types.
+const (
+	TypeRADIUS = "radius"
+	TypeTACACS = "tacacs"
+)
+
+// Types are the supported server types.
+var Types = []string{TypeRADIUS, TypeTACACS} //nolint:gochecknoglobals
+
+// Default images of the server types.
+const (
+	RADIUSImage = "freeradius/freeradius-server:latest"
+	TACACSImage = "lfkeitel/tacacs_plus:latest"
+)
+
+// Config files of the server types and their paths in the server containers.
+const (
+	RADIUSClientsFile   = "clients.conf"
+	RADIUSAuthorizeFile = "authorize"
+	TACACSConfigFile    = "tac_plus.cfg"
+)
+
+// ContainerPaths are the paths of the config files in the server containers.
+var ContainerPaths = map[string]string{ //nolint:gochecknoglobals
+	RADIUSClientsFile:   "/etc/raddb/clients.conf",
+	RADIUSAuthorizeFile: "/etc/raddb/mods-config/files/authorize",
+	TACACSConfigFile:    "/etc/tac_plus/tac_plus.cfg",
+}
+
+// User is a user authenticated by the server.
+type User struct {
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+// Server is an AAA server started for the lab.
+type Server struct {
+	Type      string `json:"type"`
+	Container string `json:"container"`
+	// Address is the management IPv4 address of the server
+	Address string `json:"address"`
+	Port    int    `json:"port"`
+	Secret  string `json:"secret"`
+	// Clients are the prefixes of the nodes allowed to query the server
+	Clients []string `json:"clients"`
+	Users   []*User  `json:"users"`
+}
+
+// NewServer returns the server of the type, with a random secret when the secret is empty.
+func NewServer(typ, secret string) (*Server, error) {
+	s := &Server{Type: typ, Secret: secret}
+
+	switch typ {
+	case TypeRADIUS:
+		s.Port = 1812
+	case TypeTACACS:
+		s.Port = 49
+	default:
+		return nil, fmt.Errorf("%w: unknown AAA server type %q, supported types are %s",
+			claberrors.ErrIncorrectInput, typ, strings.Join(Types, ", "))
+	}
+
+	if s.Secret == "" {
+		b := make([]byte, 12)
+		if _, err := rand.Read(b); err != nil {
+			return nil, err
+		}
+		s.Secret = hex.EncodeToString(b)
+	}
+
+	return s, nil
+}
+
+// Image returns the default image of the server type.
+func (s *Server) Image() string {
+	if s.Type == TypeTACACS {
+		return TACACSImage
+	}
+	return RADIUSImage
+}
+
+// ParseUsers parses the users given as name:password.
+func ParseUsers(users []string) ([]*User, error) {
+	res := make([]*User, 0, len(users))
+
+	for _, u := range users {
+		name, password, ok := strings.Cut(u, ":")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("%w: invalid user %q, expected name:password", claberrors.ErrIncorrectInput, u)
+		}
+		res = append(res, &User{Name: name, Password: password})
+	}
+
+	return res, nil
+}
+
+// TopologyUsers returns the users of the default credentials of the node kinds of the lab.
+// A user with different passwords on different kinds keeps the password of the first node by name.
+func TopologyUsers(c *clabcore.CLab) []*User {
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	var res []*User
+	seen := map[string]*User{}
+
+	for _, name := range names {
+		creds := c.Reg.Kind(c.Nodes[name].Config().Kind).GetCredentials()
+		if creds.GetUsername() == "" {
+			continue
+		}
+
+		if u, ok := seen[creds.GetUsername()]; ok {
+			if u.Password != creds.GetPassword() {
+				log.Warnf("user %s of node %s has another password on the other nodes, keeping the first one",
+					u.Name, name)
+			}
+			continue
+		}
+
+		u := &User{Name: creds.GetUsername(), Password: creds.GetPassword()}
+		seen[u.Name] = u
+		res = append(res, u)
+	}
+
+	return res
+}
+
+// ConfigFiles returns the config files of the server by file name.
+func (s *Server) ConfigFiles() map[string][]byte {
+	var b strings.Builder
+
+	if s.Type == TypeTACACS {
+		fmt.Fprintf(&b, "key = %s\n", quote(s.Secret))
+		b.WriteString("accounting file = /var/log/tac_plus.acct\n")
+		for _, u := range s.Users {
+			fmt.Fprintf(&b, "\nuser = %s {\n", u.Name)
+			fmt.Fprintf(&b, "    login = cleartext %s\n", quote(u.Password))
+			b.WriteString("    service = exec {\n        priv-lvl = 15\n    }\n}\n")
+		}
+		return map[string][]byte{TACACSConfigFile: []byte(b.String())}
+	}
+
+	for idx, c := range s.Clients {
+		fmt.Fprintf(&b, "client clab-%d {\n    ipaddr = %s\n    secret = %s\n}\n\n", idx, c, quote(s.Secret))
+	}
+	clients := b.String()
+
+	b.Reset()
+	for _, u := range s.Users {
+		fmt.Fprintf(&b, "%s Cleartext-Password := %s\n    Service-Type = Administrative-User\n\n",
+			quote(u.Name), quote(u.Password))
+	}
+
+	return map[string][]byte{
+		RADIUSClientsFile:   []byte(clients),
+		RADIUSAuthorizeFile: []byte(b.String()),
+	}
+}
+
+// quote returns the string as a double-quoted string of the server configs.
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// Vars returns the template variables of the server, the users are not exposed.
+func (s *Server) Vars() map[string]interface{} {
+	return map[string]interface{}{
+		"type":   s.Type,
+		"server": s.Address,
+		"port":   s.Port,
+		"secret": s.Secret,
+	}
+}
+
+// LoadServer returns the server stored in the file, nil when the lab has no AAA server.
+func LoadServer(path string) (*Server, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("invalid AAA server file %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Save stores the server in the file, only readable by its owner as it holds the secret.
+func (s *Server) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
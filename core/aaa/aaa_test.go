@@ -0,0 +1,124 @@
+package aaa
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	claberrors "github.com/srl-labs/containerlab/errors"
+)
+
+func TestConfigFiles(t *testing.T) {
+	users := []*User{{Name: "admin", Password: `Nokia"Srl1!`}, {Name: "ops", Password: "ops"}}
+
+	tests := map[string]struct {
+		typ  string
+		want map[string]string
+	}{
+		"radius": {
+			typ: TypeRADIUS,
+			want: map[string]string{
+				RADIUSClientsFile: "client clab-0 {\n    ipaddr = 172.20.20.0/24\n    secret = \"s3cret\"\n}\n\n" +
+					"client clab-1 {\n    ipaddr = 3fff:172:20:20::/64\n    secret = \"s3cret\"\n}\n\n",
+				RADIUSAuthorizeFile: "\"admin\" Cleartext-Password := \"Nokia\\\"Srl1!\"\n    Service-Type = Administrative-User\n\n" +
+					"\"ops\" Cleartext-Password := \"ops\"\n    Service-Type = Administrative-User\n\n",
+			},
+		},
+		"tacacs": {
+			typ: TypeTACACS,
+			want: map[string]string{
+				TACACSConfigFile: "key = \"s3cret\"\naccounting file = /var/log/tac_plus.acct\n" +
+					"\nuser = admin {\n    login = cleartext \"Nokia\\\"Srl1!\"\n    service = exec {\n        priv-lvl = 15\n    }\n}\n" +
+					"\nuser = ops {\n    login = cleartext \"ops\"\n    service = exec {\n        priv-lvl = 15\n    }\n}\n",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewServer(tt.typ, "s3cret")
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Clients = []string{"172.20.20.0/24", "3fff:172:20:20::/64"}
+			s.Users = users
+
+			got := map[string]string{}
+			for f, b := range s.ConfigFiles() {
+				got[f] = string(b)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("config files mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	s, err := NewServer(TypeTACACS, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Port != 49 || len(s.Secret) != 24 || s.Image() != TACACSImage {
+		t.Errorf("unexpected TACACS+ server %+v", s)
+	}
+
+	if _, err := NewServer("diameter", ""); !errors.Is(err, claberrors.ErrIncorrectInput) {
+		t.Errorf("expected an incorrect input error for an unknown type, got %v", err)
+	}
+}
+
+func TestParseUsers(t *testing.T) {
+	got, err := ParseUsers([]string{"admin:admin", "ops:p:w"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*User{{Name: "admin", Password: "admin"}, {Name: "ops", Password: "p:w"}}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("users mismatch (-want +got):\n%s", d)
+	}
+
+	for _, u := range []string{"admin", ":admin"} {
+		if _, err := ParseUsers([]string{u}); !errors.Is(err, claberrors.ErrIncorrectInput) {
+			t.Errorf("expected an incorrect input error for %q, got %v", u, err)
+		}
+	}
+}
+
+func TestLoadServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aaa-server.json")
+
+	s, err := LoadServer(path)
+	if err != nil || s != nil {
+		t.Fatalf("expected no server without a server file, got %v, %v", s, err)
+	}
+
+	want := &Server{
+		Type:      TypeRADIUS,
+		Container: "clab-t-aaa",
+		Address:   "172.20.20.5",
+		Port:      1812,
+		Secret:    "s3cret",
+		Clients:   []string{"172.20.20.0/24"},
+		Users:     []*User{{Name: "admin", Password: "admin"}},
+	}
+	if err := want.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("server mismatch (-want +got):\n%s", d)
+	}
+
+	wantVars := map[string]interface{}{"type": TypeRADIUS, "server": "172.20.20.5", "port": 1812, "secret": "s3cret"}
+	if d := cmp.Diff(wantVars, got.Vars()); d != "" {
+		t.Errorf("vars mismatch (-want +got):\n%s", d)
+	}
+}
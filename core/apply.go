@@ -0,0 +1,70 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/log"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// RemoveExtraNodes removes the node containers of the lab whose nodes are not defined in the topology,
+// such as the nodes removed from the topology of a lab deployed without a recorded state.
+// The tool containers of the lab are kept, and nothing is removed when a node filter is set,
+// as the filtered out nodes are not extra. The names of the removed nodes are returned.
+func (c *CLab) RemoveExtraNodes(ctx context.Context) ([]string, error) {
+	if len(c.nodeFilter) > 0 {
+		return nil, nil
+	}
+
+	containers, err := c.ListContainers(ctx, WithListLabName(c.Config.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	var removed []string
+
+	for _, cnt := range extraNodeContainers(containers, c.Config.Topology.Nodes) {
+		node := cnt.Labels[clablabels.NodeName]
+
+		log.Info("Removing node not defined in the topology", "node", node)
+		if err := cnt.Runtime.DeleteContainer(ctx, cnt.Names[0]); err != nil {
+			return removed, fmt.Errorf("failed to remove node %s: %w", node, err)
+		}
+
+		removed = append(removed, node)
+	}
+
+	return removed, nil
+}
+
+// extraNodeContainers returns the node containers whose nodes are not in the topology nodes,
+// sorted by the node name. The containers without a lab directory are tool containers.
+func extraNodeContainers(containers []clabruntime.GenericContainer,
+	nodes map[string]*clabtypes.NodeDefinition,
+) []*clabruntime.GenericContainer {
+	var res []*clabruntime.GenericContainer
+
+	for i := range containers {
+		cnt := &containers[i]
+
+		if _, ok := cnt.Labels[clablabels.NodeLabDir]; !ok {
+			continue
+		}
+
+		if _, ok := nodes[cnt.Labels[clablabels.NodeName]]; ok {
+			continue
+		}
+
+		res = append(res, cnt)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Labels[clablabels.NodeName] < res[j].Labels[clablabels.NodeName]
+	})
+
+	return res
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestExtraNodeContainers(t *testing.T) {
+	cnt := func(node string, labDir bool) clabruntime.GenericContainer {
+		labels := map[string]string{clablabels.Containerlab: "lab1", clablabels.NodeName: node}
+		if labDir {
+			labels[clablabels.NodeLabDir] = "/clab-lab1/" + node
+		}
+		return clabruntime.GenericContainer{Names: []string{"clab-lab1-" + node}, Labels: labels}
+	}
+
+	containers := []clabruntime.GenericContainer{
+		cnt("srl2", true),
+		cnt("srl1", true),
+		cnt("old2", true),
+		cnt("old1", true),
+		cnt("sshx", false),
+	}
+
+	nodes := map[string]*clabtypes.NodeDefinition{
+		"srl1": {},
+		"srl2": {},
+	}
+
+	var got []string
+	for _, c := range extraNodeContainers(containers, nodes) {
+		got = append(got, c.Names[0])
+	}
+
+	if d := cmp.Diff([]string{"clab-lab1-old1", "clab-lab1-old2"}, got); d != "" {
+		t.Errorf("extra containers mismatch (-want +got):\n%s", d)
+	}
+}
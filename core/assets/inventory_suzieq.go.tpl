@@ -0,0 +1,18 @@
+sources:
+- name: {{ .Name }}
+  hosts:
+{{- range .Nodes }}
+  - url: ssh://{{ .Username }}@{{ .Address }}:22
+    {{- if .DevType }} devtype={{ .DevType }}{{ end }}
+    {{- if .Password }} password=plain:{{ .Password }}{{ end }}
+{{- end }}
+
+devices:
+- name: {{ .Name }}
+  transport: ssh
+  ignore-known-hosts: true
+
+namespaces:
+- name: {{ .Name }}
+  source: {{ .Name }}
+  device: {{ .Name }}
@@ -1,13 +1,24 @@
 # Containerlab SSH Config for the {{ .TopologyName }} lab
 
 {{- range  .Nodes }}
-Host {{ .Name }}
+Host {{ .Name }}{{ range .Aliases }} {{ . }}{{ end }}
+	{{- if ne .Address "" }}
+	HostName {{ .Address }}
+	{{- end }}
 	{{-  if ne .Username ""}}
 	User {{ .Username }}
 	{{- end }}
+	{{- if ne .ProxyJump "" }}
+	ProxyJump {{ .ProxyJump }}
+	{{- end }}
+	{{- if ne $.KnownHostsFile "" }}
+	StrictHostKeyChecking=accept-new
+	UserKnownHostsFile={{ $.KnownHostsFile }}
+	{{- else }}
 	StrictHostKeyChecking=no
 	UserKnownHostsFile=/dev/null
+	{{- end }}
 	{{- if ne .SSHConfig.PubkeyAuthentication "" }}
 	PubkeyAuthentication={{ .SSHConfig.PubkeyAuthentication.String }}
 	{{- end }}
-{{ end }}
\ No newline at end of file
+{{ end }}
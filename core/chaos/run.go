@@ -0,0 +1,257 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package chaos
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+)
+
+// EventLog records the scenario events as JSON lines with their time and the action,
+// to correlate the injected failures with the telemetry of the lab.
+// The events are also delivered to the events emitter.
+type EventLog struct {
+	m       sync.Mutex
+	w       io.Writer
+	emitter *clabcoreevents.Emitter
+	lab     string
+}
+
+// NewEventLog returns an EventLog writing to w and delivering the events to the emitter, both optional.
+func NewEventLog(w io.Writer, emitter *clabcoreevents.Emitter, lab string) *EventLog {
+	return &EventLog{w: w, emitter: emitter, lab: lab}
+}
+
+func (l *EventLog) record(ctx context.Context, ev *clabcoreevents.Event) {
+	ev.Lab = l.lab
+	ev.Time = time.Now().UTC()
+
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.w != nil {
+		b, err := json.Marshal(ev)
+		if err == nil {
+			_, err = l.w.Write(append(b, '\n'))
+		}
+		if err != nil {
+			log.Warnf("failed to write %s event to the event log: %v", ev.Type, err)
+		}
+	}
+
+	l.emitter.Emit(ctx, ev)
+}
+
+// runner holds the links and nodes of the scenario and the ones left down or paused.
+type runner struct {
+	links  map[string]*clablinks.LinkVEth
+	nodes  map[string]clabnodes.Node
+	down   map[*clablinks.LinkVEth]string
+	shaped map[*clablinks.LinkVEth]string
+	paused map[string]clabnodes.Node
+	log    *EventLog
+}
+
+// Run runs the steps of the scenario against the running lab at their times after the start.
+// The links of the lab must be resolved. A failed step is recorded and the scenario goes on,
+// the error lists the failed steps. The links and nodes are restored when the scenario ends
+// or the context is canceled, unless the scenario disables it.
+func Run(ctx context.Context, c *clabcore.CLab, s *Scenario, el *EventLog) error {
+	r := &runner{
+		links:  map[string]*clablinks.LinkVEth{},
+		nodes:  map[string]clabnodes.Node{},
+		down:   map[*clablinks.LinkVEth]string{},
+		shaped: map[*clablinks.LinkVEth]string{},
+		paused: map[string]clabnodes.Node{},
+		log:    el,
+	}
+
+	// resolve all targets before injecting the first failure
+	for _, st := range s.Steps {
+		if st.Link != "" {
+			l, err := clablinks.FindLink(c.Links, st.Link)
+			if err != nil {
+				return err
+			}
+			r.links[st.Link] = l
+			continue
+		}
+
+		n, ok := c.Nodes[st.Node]
+		if !ok {
+			return fmt.Errorf("node %q not found in the lab", st.Node)
+		}
+		r.nodes[st.Node] = n
+	}
+
+	el.record(ctx, &clabcoreevents.Event{
+		Type:    clabcoreevents.ChaosStarted,
+		Message: s.Name,
+	})
+
+	start := time.Now()
+	var errs []error
+	for _, st := range s.Steps {
+		if err := sleepUntil(ctx, start.Add(st.At)); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if late := time.Since(start) - st.At; late > time.Second {
+			log.Warnf("%s %s started %s late", st.Action, st.Target(), late.Round(time.Second))
+		}
+
+		log.Infof("T+%s: %s %s", st.At, st.Action, st.Target())
+
+		err := r.runStep(ctx, st)
+		r.recordStep(ctx, st, err)
+		if err != nil {
+			log.Errorf("%s %s failed: %v", st.Action, st.Target(), err)
+			errs = append(errs, fmt.Errorf("%s %s at T+%s: %w", st.Action, st.Target(), st.At, err))
+		}
+	}
+
+	if s.restore() {
+		errs = append(errs, r.restore(context.WithoutCancel(ctx))...)
+	}
+
+	status := clabcoreevents.StatusOK
+	if len(errs) > 0 {
+		status = clabcoreevents.StatusFailed
+	}
+	el.record(ctx, &clabcoreevents.Event{
+		Type:    clabcoreevents.ChaosFinished,
+		Status:  status,
+		Message: s.Name,
+	})
+
+	return errors.Join(errs...)
+}
+
+func (r *runner) runStep(ctx context.Context, st *Step) error {
+	switch st.Action {
+	case ActionLinkDown:
+		if err := clablinks.SetLinkState(ctx, r.links[st.Link], clablinks.LinkStateDown); err != nil {
+			return err
+		}
+		r.down[r.links[st.Link]] = st.Link
+	case ActionLinkUp:
+		if err := clablinks.SetLinkState(ctx, r.links[st.Link], clablinks.LinkStateUp); err != nil {
+			return err
+		}
+		delete(r.down, r.links[st.Link])
+	case ActionLinkFlap:
+		return clablinks.FlapLink(ctx, r.links[st.Link], st.Count, st.Interval)
+	case ActionLinkShape:
+		err := clablinks.ShapeLink(ctx, r.links[st.Link], &clablinks.LinkShaping{
+			Rate:    st.Rate,
+			Burst:   st.Burst,
+			Latency: st.Latency,
+		})
+		if err != nil {
+			return err
+		}
+		r.shaped[r.links[st.Link]] = st.Link
+	case ActionLinkUnshape:
+		if err := clablinks.ShapeLink(ctx, r.links[st.Link], nil); err != nil {
+			return err
+		}
+		delete(r.shaped, r.links[st.Link])
+	case ActionNodePause:
+		n := r.nodes[st.Node]
+		if err := n.GetRuntime().PauseContainer(ctx, n.Config().LongName); err != nil {
+			return err
+		}
+		r.paused[st.Node] = n
+	case ActionNodeUnpause:
+		n := r.nodes[st.Node]
+		if err := n.GetRuntime().UnpauseContainer(ctx, n.Config().LongName); err != nil {
+			return err
+		}
+		delete(r.paused, st.Node)
+	}
+
+	return nil
+}
+
+func (r *runner) recordStep(ctx context.Context, st *Step, err error) {
+	ev := &clabcoreevents.Event{
+		Type:   clabcoreevents.ChaosAction,
+		Node:   st.Node,
+		Action: st.Action,
+		Target: st.Target(),
+		Status: clabcoreevents.StatusOK,
+	}
+	if err != nil {
+		ev.Status = clabcoreevents.StatusFailed
+		ev.Message = err.Error()
+	}
+
+	r.log.record(ctx, ev)
+}
+
+// restore brings the links left down back up, removes the shaping of the links left shaped
+// and unpauses the nodes left paused.
+func (r *runner) restore(ctx context.Context) []error {
+	var errs []error
+
+	for l, ref := range r.down {
+		st := &Step{Action: ActionLinkUp, Link: ref}
+		err := clablinks.SetLinkState(ctx, l, clablinks.LinkStateUp)
+		r.recordStep(ctx, st, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore link %s: %w", ref, err))
+			continue
+		}
+		log.Infof("restored link %s", ref)
+	}
+
+	for l, ref := range r.shaped {
+		st := &Step{Action: ActionLinkUnshape, Link: ref}
+		err := clablinks.ShapeLink(ctx, l, nil)
+		r.recordStep(ctx, st, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to remove the shaping of link %s: %w", ref, err))
+			continue
+		}
+		log.Infof("removed the shaping of link %s", ref)
+	}
+
+	for name, n := range r.paused {
+		st := &Step{Action: ActionNodeUnpause, Node: name}
+		err := n.GetRuntime().UnpauseContainer(ctx, n.Config().LongName)
+		r.recordStep(ctx, st, err)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore node %s: %w", name, err))
+			continue
+		}
+		log.Infof("restored node %s", name)
+	}
+
+	return errs
+}
+
+// sleepUntil waits until t or until the context is done.
+func sleepUntil(ctx context.Context, t time.Time) error {
+	timer := time.NewTimer(time.Until(t))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,166 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package chaos runs the failure injection scenarios against a running lab:
+// the links are set down, up or flapped and the nodes paused and unpaused at
+// scheduled times, every action is recorded in an event log.
+package chaos
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Scenario actions.
+const (
+	ActionLinkDown    = "link-down"
+	ActionLinkUp      = "link-up"
+	ActionLinkFlap    = "link-flap"
+	ActionLinkShape   = "link-shape"
+	ActionLinkUnshape = "link-unshape"
+	ActionNodePause   = "node-pause"
+	ActionNodeUnpause = "node-unpause"
+)
+
+// defaultShapeLatency is the maximum time a packet waits in the queue of a shaped link.
+const defaultShapeLatency = 50 * time.Millisecond
+
+// Scenario is a schedule of failures injected into a running lab.
+type Scenario struct {
+	Name string `yaml:"name,omitempty"`
+	// Restore brings the links set down back up, removes the shaping of the links and
+	// unpauses the paused nodes at the end of the scenario, or when it is interrupted.
+	// Defaults to true.
+	Restore *bool   `yaml:"restore,omitempty"`
+	Steps   []*Step `yaml:"steps"`
+}
+
+// Step is an action run at a time relative to the start of the scenario.
+type Step struct {
+	At     time.Duration `yaml:"at"`
+	Action string        `yaml:"action"`
+	// Link is the link of the link actions, one or both of its endpoints as <node>:<interface>.
+	Link string `yaml:"link,omitempty"`
+	// Node is the node of the node actions.
+	Node string `yaml:"node,omitempty"`
+	// Count and Interval are the number of flaps and the time the link stays down and up of link-flap.
+	Count    int           `yaml:"count,omitempty"`
+	Interval time.Duration `yaml:"interval,omitempty"`
+	// Rate in kbit, Burst in bytes and Latency are the rate shaping of link-shape.
+	Rate    uint64        `yaml:"rate,omitempty"`
+	Burst   uint32        `yaml:"burst,omitempty"`
+	Latency time.Duration `yaml:"latency,omitempty"`
+}
+
+// Target returns the link or the node of the step.
+func (s *Step) Target() string {
+	if s.Link != "" {
+		return s.Link
+	}
+
+	return s.Node
+}
+
+// LoadScenario reads and validates the scenario file.
+func LoadScenario(path string) (*Scenario, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := ParseScenario(b)
+	if err != nil {
+		return nil, fmt.Errorf("invalid scenario %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// ParseScenario parses and validates the scenario, its steps are ordered by their time.
+func ParseScenario(b []byte) (*Scenario, error) {
+	s := &Scenario{}
+	if err := yaml.UnmarshalStrict(b, s); err != nil {
+		return nil, err
+	}
+
+	if len(s.Steps) == 0 {
+		return nil, fmt.Errorf("the scenario has no steps")
+	}
+
+	for i, st := range s.Steps {
+		if st == nil {
+			return nil, fmt.Errorf("step %d is empty", i+1)
+		}
+		if err := st.validate(); err != nil {
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, st.Action, err)
+		}
+	}
+
+	sort.SliceStable(s.Steps, func(i, j int) bool {
+		return s.Steps[i].At < s.Steps[j].At
+	})
+
+	return s, nil
+}
+
+// restore returns true if the scenario restores the links and nodes at its end.
+func (s *Scenario) restore() bool {
+	return s.Restore == nil || *s.Restore
+}
+
+func (s *Step) validate() error {
+	if s.At < 0 {
+		return fmt.Errorf("negative time %s", s.At)
+	}
+
+	switch s.Action {
+	case ActionLinkDown, ActionLinkUp, ActionLinkFlap, ActionLinkShape, ActionLinkUnshape:
+		if s.Link == "" || s.Node != "" {
+			return fmt.Errorf("the link and only the link must be set")
+		}
+	case ActionNodePause, ActionNodeUnpause:
+		if s.Node == "" || s.Link != "" {
+			return fmt.Errorf("the node and only the node must be set")
+		}
+	default:
+		return fmt.Errorf("unknown action, expected one of %s, %s, %s, %s, %s, %s, %s",
+			ActionLinkDown, ActionLinkUp, ActionLinkFlap, ActionLinkShape, ActionLinkUnshape,
+			ActionNodePause, ActionNodeUnpause)
+	}
+
+	if s.Action != ActionLinkShape && (s.Rate != 0 || s.Burst != 0 || s.Latency != 0) {
+		return fmt.Errorf("rate, burst and latency are only set for %s", ActionLinkShape)
+	}
+	if s.Action == ActionLinkShape {
+		if s.Rate == 0 {
+			return fmt.Errorf("the rate must be set")
+		}
+		if s.Latency == 0 {
+			s.Latency = defaultShapeLatency
+		}
+	}
+
+	if s.Action != ActionLinkFlap {
+		if s.Count != 0 || s.Interval != 0 {
+			return fmt.Errorf("count and interval are only set for %s", ActionLinkFlap)
+		}
+		return nil
+	}
+
+	if s.Count == 0 {
+		s.Count = 1
+	}
+	if s.Interval == 0 {
+		s.Interval = time.Second
+	}
+	if s.Count < 0 || s.Interval < 0 {
+		return fmt.Errorf("count and interval must be positive")
+	}
+
+	return nil
+}
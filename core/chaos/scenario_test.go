@@ -0,0 +1,91 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package chaos
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseScenario(t *testing.T) {
+	tests := map[string]struct {
+		in      string
+		want    *Scenario
+		wantErr bool
+	}{
+		"ordered steps with flap defaults": {
+			in: `
+name: failures
+steps:
+  - at: 120s
+    action: node-unpause
+    node: srl2
+  - at: 30s
+    action: link-flap
+    link: srl1:e1-1
+  - at: 1m
+    action: node-pause
+    node: srl2
+`,
+			want: &Scenario{
+				Name: "failures",
+				Steps: []*Step{
+					{At: 30 * time.Second, Action: ActionLinkFlap, Link: "srl1:e1-1", Count: 1, Interval: time.Second},
+					{At: time.Minute, Action: ActionNodePause, Node: "srl2"},
+					{At: 2 * time.Minute, Action: ActionNodeUnpause, Node: "srl2"},
+				},
+			},
+		},
+		"shape defaults": {
+			in: "steps:\n  - at: 10s\n    action: link-shape\n    link: srl1:e1-1\n    rate: 10000\n",
+			want: &Scenario{
+				Steps: []*Step{
+					{
+						At: 10 * time.Second, Action: ActionLinkShape, Link: "srl1:e1-1",
+						Rate: 10000, Latency: 50 * time.Millisecond,
+					},
+				},
+			},
+		},
+		"shape without rate": {
+			in:      "steps:\n  - at: 10s\n    action: link-shape\n    link: srl1:e1-1\n",
+			wantErr: true,
+		},
+		"no steps": {
+			in:      "name: empty\n",
+			wantErr: true,
+		},
+		"unknown action": {
+			in:      "steps:\n  - at: 1s\n    action: node-reboot\n    node: srl1\n",
+			wantErr: true,
+		},
+		"link action without link": {
+			in:      "steps:\n  - at: 1s\n    action: link-down\n    node: srl1\n",
+			wantErr: true,
+		},
+		"count of a node action": {
+			in:      "steps:\n  - at: 1s\n    action: node-pause\n    node: srl1\n    count: 2\n",
+			wantErr: true,
+		},
+		"unknown field": {
+			in:      "steps:\n  - at: 1s\n    action: link-down\n    link: srl1:e1-1\n    duration: 5s\n",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseScenario([]byte(tt.in))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScenario() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("ParseScenario() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
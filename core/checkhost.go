@@ -0,0 +1,72 @@
+package core
+
+import (
+	"sort"
+
+	"github.com/charmbracelet/log"
+	"github.com/dustin/go-humanize"
+	clabcorehostcheck "github.com/srl-labs/containerlab/core/hostcheck"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+)
+
+// kindKernelModules are the kernel modules required by the kinds.
+var kindKernelModules = map[string][]string{ //nolint:gochecknoglobals
+	"ovs-bridge": {"openvswitch"},
+	"bridge":     {"bridge"},
+}
+
+// linkKernelModules are the kernel modules required by the link types.
+var linkKernelModules = map[clablinks.LinkType][]string{ //nolint:gochecknoglobals
+	clablinks.LinkTypeVxlan:       {"vxlan"},
+	clablinks.LinkTypeVxlanStitch: {"vxlan"},
+	clablinks.LinkTypeMacVLan:     {"macvlan"},
+}
+
+// HostRequirements returns the host requirements of the lab nodes and links,
+// the links being resolved beforehand.
+func (c *CLab) HostRequirements() *clabcorehostcheck.Requirements {
+	req := &clabcorehostcheck.Requirements{
+		IPv6: c.Config.Mgmt.IPv6Subnet != "",
+	}
+
+	modules := map[string]struct{}{}
+
+	for name, n := range c.Nodes {
+		for _, m := range kindKernelModules[n.Config().Kind] {
+			modules[m] = struct{}{}
+		}
+
+		var minGB float64
+		if hn, ok := n.(clabnodes.HostRequirementsNode); ok {
+			hr := hn.GetHostRequirements()
+			req.KVM = req.KVM || hr.VirtRequired
+			minGB = float64(hr.MinAvailMemoryGb)
+		}
+
+		// the memory limit of the node takes precedence over the minimum of its kind
+		if mem := n.Config().Memory; mem != "" {
+			b, err := humanize.ParseBytes(mem)
+			if err != nil {
+				log.Warnf("failed to parse the memory limit %q of node %s: %v", mem, name, err)
+			} else {
+				minGB = float64(b) / (1 << 30)
+			}
+		}
+
+		req.MemoryGB += minGB
+	}
+
+	for _, l := range c.Links {
+		for _, m := range linkKernelModules[l.GetType()] {
+			modules[m] = struct{}{}
+		}
+	}
+
+	for m := range modules {
+		req.KernelModules = append(req.KernelModules, m)
+	}
+	sort.Strings(req.KernelModules)
+
+	return req
+}
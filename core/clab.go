@@ -16,7 +16,10 @@ import (
 
 	"github.com/charmbracelet/log"
 	clabcert "github.com/srl-labs/containerlab/cert"
+	clabcoreconsole "github.com/srl-labs/containerlab/core/console"
 	clabcoredependency_manager "github.com/srl-labs/containerlab/core/dependency_manager"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clabcorelabpack "github.com/srl-labs/containerlab/core/labpack"
 	claberrors "github.com/srl-labs/containerlab/errors"
 	clabexec "github.com/srl-labs/containerlab/exec"
 	clablinks "github.com/srl-labs/containerlab/links"
@@ -60,6 +63,12 @@ type CLab struct {
 	checkBindsPaths bool
 	// customOwner is the user-specified owner label for the lab
 	customOwner string
+	// expires is the time the lab is destroyed at by the daemon, zero for the labs without a TTL
+	expires time.Time
+	// events emits the lab lifecycle events, nil when events are disabled.
+	events *clabcoreevents.Emitter
+	// deployDiff is the topology diff of an incremental deploy, nil for a full deploy.
+	deployDiff *TopologyDiff
 }
 
 // NewContainerLab function defines a new container lab.
@@ -94,6 +103,10 @@ func NewContainerLab(opts ...ClabOption) (*CLab, error) {
 		err = c.parseTopology()
 	}
 
+	for name, n := range c.Nodes {
+		clabcoreconsole.RegisterNode(name, n.Config().LongName)
+	}
+
 	// Extract the host systems DNS servers and populate the
 	// Nodes DNS Config with these if not specifically provided
 	fileSystem := os.DirFS("/")
@@ -161,7 +174,7 @@ func RuntimeInitializer(name string) (string, clabruntime.Initializer, error) {
 }
 
 // ProcessTopoPath takes a topology path, which might be the path to a directory or a file
-// or stdin or a URL (HTTP/HTTPS/S3) and returns the topology file name if found.
+// or stdin or a URL (HTTP/HTTPS/S3) or a lab OCI artifact (oci://) and returns the topology file name if found.
 func (c *CLab) ProcessTopoPath(path string) (string, error) {
 	var file string
 	var err error
@@ -173,6 +186,14 @@ func (c *CLab) ProcessTopoPath(path string) (string, error) {
 		if err != nil {
 			return "", err
 		}
+	// if the path is a lab OCI artifact, pull and extract it in the tmp dir
+	case strings.HasPrefix(path, clabcorelabpack.OCIScheme):
+		log.Debugf("interpreting topo %q as lab OCI artifact", path)
+		file, err = c.pullPackedTopology(path)
+		if err != nil {
+			return "", err
+		}
+
 	// if the path is not a local file and a URL, download the file and store it in the tmp dir
 	case !clabutils.FileOrDirExists(path) &&
 		clabutils.IsHttpURL(path, true):
@@ -242,6 +263,24 @@ func (c *CLab) initMgmtNetwork() error {
 		c.Config.Mgmt.IPv6Subnet = dockerNetIPv6Addr
 	}
 
+	if c.Config.Mgmt.Mode == "" {
+		c.Config.Mgmt.Mode = clabtypes.MgmtModeNAT
+	}
+
+	if !slices.Contains(clabtypes.MgmtModes, c.Config.Mgmt.Mode) {
+		return fmt.Errorf("%w: unsupported management network mode %q, use one of %s",
+			claberrors.ErrIncorrectInput, c.Config.Mgmt.Mode, strings.Join(clabtypes.MgmtModes, ", "))
+	}
+
+	// an isolated network is not accessible from the outside
+	if c.Config.Mgmt.Mode == clabtypes.MgmtModeIsolated {
+		if c.Config.Mgmt.ExternalAccess != nil && *c.Config.Mgmt.ExternalAccess {
+			return fmt.Errorf("%w: external access can't be enabled for the isolated management network",
+				claberrors.ErrIncorrectInput)
+		}
+		c.Config.Mgmt.ExternalAccess = new(bool)
+	}
+
 	// by default external access is enabled if not set by a user
 	if c.Config.Mgmt.ExternalAccess == nil {
 		c.Config.Mgmt.ExternalAccess = new(bool)
@@ -349,6 +388,10 @@ func (c *CLab) createWaitForDependency() error {
 		for dependerStage, waitForNodes := range dependerNode.Config().Stages.GetWaitFor() {
 			for _, dependee := range waitForNodes {
 				dependeeNode, err := c.dependencyManager.GetNode(dependee.Node)
+				if _, kept := c.Nodes[dependee.Node]; err != nil && kept {
+					// the dependee is kept running by an incremental deploy
+					continue
+				}
 				if err != nil {
 					return fmt.Errorf("dependee node %s not found", dependee.Node)
 				}
@@ -364,7 +407,7 @@ func (c *CLab) createWaitForDependency() error {
 	return nil
 }
 
-func (c *CLab) scheduleNodeWorkerF( //nolint: funlen
+func (c *CLab) scheduleNodeWorkerF(
 	ctx context.Context,
 	i int,
 	input chan *clabcoredependency_manager.DependencyNode,
@@ -384,101 +427,120 @@ func (c *CLab) scheduleNodeWorkerF( //nolint: funlen
 
 			log.Debugf("Worker %d received node: %+v", i, node.Config())
 
-			delay := node.Config().StartupDelay
-			if delay > 0 {
-				log.Infof("node %q is being delayed for %d seconds", node.Config().ShortName, delay)
-				time.Sleep(time.Duration(delay) * time.Second)
-			}
+			c.deployNode(ctx, node, skipPostDeploy, execCollection)
+			clabcoreconsole.Flush(node.Config().ShortName)
 
-			err := node.PreDeploy(
-				ctx,
-				&clabnodes.PreDeployParams{
-					Cert:         c.Cert,
-					TopologyName: c.Config.Name,
-					TopoPaths:    c.TopoPaths,
-					SSHPubKeys:   c.SSHPubKeys,
-				},
-			)
-			if err != nil {
-				log.Errorf("failed pre-deploy stage for node %q: %v", node.Config().ShortName, err)
-				continue
-			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
 
-			err = node.Deploy(ctx, &clabnodes.DeployParams{Nodes: c.Nodes})
-			if err != nil {
-				log.Errorf("failed deploy stage for node %q: %v", node.Config().ShortName, err)
-				continue
-			}
+// deployNode runs the deploy stages of the node.
+func (c *CLab) deployNode( //nolint: funlen
+	ctx context.Context,
+	node *clabcoredependency_manager.DependencyNode,
+	skipPostDeploy bool,
+	execCollection *clabexec.ExecCollection,
+) {
+	name := node.Config().ShortName
+	start := time.Now()
+
+	delay := node.Config().StartupDelay
+	if delay > 0 {
+		log.Info("Delaying the node", "node", name, "delay", time.Duration(delay)*time.Second)
+		time.Sleep(time.Duration(delay) * time.Second)
+	}
+
+	err := node.PreDeploy(
+		ctx,
+		&clabnodes.PreDeployParams{
+			Cert:         c.Cert,
+			TopologyName: c.Config.Name,
+			TopoPaths:    c.TopoPaths,
+			SSHPubKeys:   c.SSHPubKeys,
+		},
+	)
+	if err != nil {
+		log.Error("Failed pre-deploy stage", "node", name, "phase", "pre-deploy", "err", err)
+		c.emitNodeFailed(ctx, node, err)
+		return
+	}
 
-			// we need to update the node's state with runtime info (e.g. the mgmt net ip addresses)
-			// before continuing with the post-deploy stage (for e.g. certificate creation)
-			err = node.UpdateConfigWithRuntimeInfo(ctx)
-			if err != nil {
-				log.Errorf("failed to update node runtime information for node %s: %v", node.Config().ShortName, err)
-			}
+	err = node.Deploy(ctx, &clabnodes.DeployParams{Nodes: c.Nodes})
+	if err != nil {
+		log.Error("Failed deploy stage", "node", name, "phase", "deploy", "err", err)
+		c.emitNodeFailed(ctx, node, err)
+		return
+	}
 
-			node.Done(ctx, clabtypes.WaitForCreate)
+	// we need to update the node's state with runtime info (e.g. the mgmt net ip addresses)
+	// before continuing with the post-deploy stage (for e.g. certificate creation)
+	err = node.UpdateConfigWithRuntimeInfo(ctx)
+	if err != nil {
+		log.Error("Failed to update the node runtime information", "node", name, "phase", "deploy", "err", err)
+	}
 
-			node.EnterStage(ctx, clabtypes.WaitForCreateLinks)
+	node.Done(ctx, clabtypes.WaitForCreate)
 
-			// Deploy the Nodes link endpoints
-			err = node.DeployEndpoints(ctx)
-			if err != nil {
-				log.Errorf("failed deploy links for node %q: %v", node.Config().ShortName, err)
-				continue
-			}
+	node.EnterStage(ctx, clabtypes.WaitForCreateLinks)
 
-			node.Done(ctx, clabtypes.WaitForCreateLinks)
-			node.EnterStage(ctx, clabtypes.WaitForConfigure)
+	// Deploy the Nodes link endpoints
+	err = node.DeployEndpoints(ctx)
+	if err != nil {
+		log.Error("Failed to deploy links", "node", name, "phase", "links", "err", err)
+		c.emitNodeFailed(ctx, node, err)
+		return
+	}
 
-			if !skipPostDeploy {
-				err = node.PostDeploy(ctx, &clabnodes.PostDeployParams{Nodes: c.Nodes})
-				if err != nil {
-					log.Errorf("failed to run postdeploy task for node %s: %v", node.Config().ShortName, err)
-				}
-			}
+	node.Done(ctx, clabtypes.WaitForCreateLinks)
+	node.EnterStage(ctx, clabtypes.WaitForConfigure)
 
-			node.Done(ctx, clabtypes.WaitForConfigure)
+	if !skipPostDeploy {
+		err = node.PostDeploy(ctx, &clabnodes.PostDeployParams{Nodes: c.Nodes})
+		if err != nil {
+			log.Error("Failed to run the post-deploy tasks", "node", name, "phase", "post-deploy", "err", err)
+		}
+	}
+
+	node.Done(ctx, clabtypes.WaitForConfigure)
+
+	log.Info("Node deployed", "node", name, "duration", time.Since(start).Round(time.Millisecond))
+
+	err = node.RunExecFromConfig(ctx, execCollection)
+	if err != nil {
+		log.Error("Failed to run exec commands", "node", name, "phase", "exec", "err", err)
+	}
 
-			err = node.RunExecFromConfig(ctx, execCollection)
+	if node.MustWait(clabtypes.WaitForHealthy) {
+		node.EnterStage(ctx, clabtypes.WaitForHealthy)
+		// if there is a dependecy on the healthy state of this node, enter the checking procedure
+		for {
+			healthy, err := node.IsHealthy(ctx)
 			if err != nil {
-				log.Errorf("failed to run exec commands for %s: %v", node.GetShortName(), err)
+				log.Error("Failed to check the node health, continuing deployment anyways", "node", name, "phase", "healthcheck", "err", err)
+				break
 			}
-
-			if node.MustWait(clabtypes.WaitForHealthy) {
-				node.EnterStage(ctx, clabtypes.WaitForHealthy)
-				// if there is a dependecy on the healthy state of this node, enter the checking procedure
-				for {
-					healthy, err := node.IsHealthy(ctx)
-					if err != nil {
-						log.Errorf("error checking for node health %v. Continuing deployment anyways", err)
-						break
-					}
-					if healthy {
-						log.Infof("node %q turned healthy, continuing", node.GetShortName())
-						node.Done(ctx, clabtypes.WaitForHealthy)
-						break
-					}
-					time.Sleep(time.Second)
-				}
+			if healthy {
+				log.Info("Node turned healthy, continuing", "node", name)
+				node.Done(ctx, clabtypes.WaitForHealthy)
+				break
 			}
+			time.Sleep(time.Second)
+		}
+	}
 
-			if node.MustWait(clabtypes.WaitForExit) {
-				node.EnterStage(ctx, clabtypes.WaitForExit)
-				// if there is a dependency on the healthy state of this node, enter the checking procedure
-				for {
-					status := node.GetContainerStatus(ctx)
-					if status == clabruntime.Stopped {
-						log.Infof("node %q stopped", node.GetShortName())
-						node.Done(ctx, clabtypes.WaitForExit)
-						break
-					}
-					time.Sleep(time.Second)
-				}
+	if node.MustWait(clabtypes.WaitForExit) {
+		node.EnterStage(ctx, clabtypes.WaitForExit)
+		// if there is a dependency on the healthy state of this node, enter the checking procedure
+		for {
+			status := node.GetContainerStatus(ctx)
+			if status == clabruntime.Stopped {
+				log.Info("Node stopped", "node", name)
+				node.Done(ctx, clabtypes.WaitForExit)
+				break
 			}
-
-		case <-ctx.Done():
-			return
+			time.Sleep(time.Second)
 		}
 	}
 }
@@ -489,7 +551,7 @@ func (c *CLab) scheduleNodes(ctx context.Context, maxWorkers int, skipPostDeploy
 
 	execCollection := clabexec.NewExecCollection()
 
-	numScheduledNodes := len(c.Nodes)
+	numScheduledNodes := len(c.dependencyManager.GetNodes())
 	if numScheduledNodes < maxWorkers {
 		maxWorkers = numScheduledNodes
 	}
@@ -605,6 +667,17 @@ func (*CLab) getSpecialLinkNodes() map[string]clablinks.Node {
 	return specialNodes
 }
 
+// emitNodeFailed emits the node-failed event for the node.
+func (c *CLab) emitNodeFailed(ctx context.Context, node clabnodes.Node, err error) {
+	c.events.Emit(ctx, &clabcoreevents.Event{
+		Type:    clabcoreevents.NodeFailed,
+		Lab:     c.Config.Name,
+		Node:    node.Config().ShortName,
+		Status:  clabcoreevents.StatusFailed,
+		Message: err.Error(),
+	})
+}
+
 // ResolveLinks resolves raw links to the actual link types and stores them in the CLab.Links map.
 func (c *CLab) ResolveLinks() error {
 	resolveParams := &clablinks.ResolveParams{
@@ -17,6 +17,7 @@ import (
 	clabruntime "github.com/srl-labs/containerlab/runtime"
 	_ "github.com/srl-labs/containerlab/runtime/all"
 	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
 	"go.uber.org/mock/gomock"
 	"golang.org/x/exp/slices"
 )
@@ -396,3 +397,59 @@ func TestWithTopo(t *testing.T) {
 		})
 	}
 }
+
+func TestInitMgmtNetworkMode(t *testing.T) {
+	tests := map[string]struct {
+		mgmt     *clabtypes.MgmtNet
+		want     string
+		external bool
+		err      bool
+	}{
+		"default": {
+			mgmt:     &clabtypes.MgmtNet{},
+			want:     clabtypes.MgmtModeNAT,
+			external: true,
+		},
+		"routed": {
+			mgmt:     &clabtypes.MgmtNet{Mode: clabtypes.MgmtModeRouted},
+			want:     clabtypes.MgmtModeRouted,
+			external: true,
+		},
+		"isolated": {
+			mgmt: &clabtypes.MgmtNet{Mode: clabtypes.MgmtModeIsolated},
+			want: clabtypes.MgmtModeIsolated,
+		},
+		"isolated with external access": {
+			mgmt: &clabtypes.MgmtNet{Mode: clabtypes.MgmtModeIsolated, ExternalAccess: clabutils.Pointer(true)},
+			err:  true,
+		},
+		"unknown": {
+			mgmt: &clabtypes.MgmtNet{Mode: "bridged"},
+			err:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CLab{Config: &Config{Mgmt: tc.mgmt}}
+
+			err := c.initMgmtNetwork()
+			if tc.err {
+				if !errors.Is(err, claberrors.ErrIncorrectInput) {
+					t.Fatalf("expected an incorrect input error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if c.Config.Mgmt.Mode != tc.want {
+				t.Errorf("mode = %q, want %q", c.Config.Mgmt.Mode, tc.want)
+			}
+			if *c.Config.Mgmt.ExternalAccess != tc.external {
+				t.Errorf("external access = %v, want %v", *c.Config.Mgmt.ExternalAccess, tc.external)
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+
+	"github.com/charmbracelet/log"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabutils "github.com/srl-labs/containerlab/utils"
+	"github.com/vishvananda/netlink"
+)
+
+// labVethRe matches the random names of the veth interfaces created in the host namespace
+// before they are moved to the nodes, left behind when a deployment is interrupted.
+var labVethRe = regexp.MustCompile(`^clab-[0-9a-f]{8}$`)
+
+// Cleanup finds the resources left behind by the crashed or interrupted runs of containerlab:
+// the lab containers that never started or lost their lab directory, the veth interfaces of
+// interrupted deployments and the unused management networks and their bridges.
+// The containers of the labs being deployed are left alone, as are the veth interfaces, networks
+// and bridges while any lab is being deployed, since they can't be told apart from the ones
+// of the deployment in progress.
+// The resources are removed unless dryRun is set.
+func (c *CLab) Cleanup(ctx context.Context, dryRun bool) ([]*clabruntime.OrphanedResource, error) {
+	var res []*clabruntime.OrphanedResource
+
+	containers, err := c.ListContainers(ctx, WithListclabLabelExists())
+	if err != nil {
+		return nil, err
+	}
+
+	deploying := deploysInProgress()
+	if len(deploying) > 0 {
+		log.Info("Labs are being deployed, leaving their containers, the veth interfaces and networks alone",
+			"labs", deploying)
+	}
+
+	for i := range containers {
+		cnt := &containers[i]
+
+		if slices.Contains(deploying, cnt.Labels[clablabels.Containerlab]) {
+			continue
+		}
+
+		reason := orphanedContainerReason(cnt)
+		if reason == "" {
+			continue
+		}
+
+		res = append(res, &clabruntime.OrphanedResource{
+			Type:   "container",
+			Name:   cnt.Names[0],
+			Reason: reason,
+		})
+
+		if dryRun {
+			continue
+		}
+
+		log.Info("Removing container", "name", cnt.Names[0])
+		if err := cnt.Runtime.DeleteContainer(ctx, cnt.Names[0]); err != nil {
+			return nil, fmt.Errorf("failed to remove container %s: %w", cnt.Names[0], err)
+		}
+	}
+
+	if len(deploying) > 0 {
+		return res, nil
+	}
+
+	veths, err := c.cleanupVeths(dryRun)
+	if err != nil {
+		return nil, err
+	}
+	res = append(res, veths...)
+
+	// the networks are pruned last, as the removed containers may have been attached to them
+	nets, err := c.globalRuntime().PruneNets(ctx, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(res, nets...), nil
+}
+
+// orphanedContainerReason returns why the lab container is orphaned, empty if it is not.
+// The tool containers, not having a lab directory, are never orphaned.
+func orphanedContainerReason(cnt *clabruntime.GenericContainer) string {
+	labDir, ok := cnt.Labels[clablabels.NodeLabDir]
+	if !ok {
+		return ""
+	}
+
+	switch {
+	case cnt.State == "created":
+		return "never started"
+	case !clabutils.DirExists(labDir):
+		return fmt.Sprintf("lab directory %s not found", labDir)
+	}
+
+	return ""
+}
+
+// cleanupVeths returns the veth interfaces of interrupted deployments in the host namespace,
+// removing them unless dryRun is set. Deleting a veth interface deletes its peer.
+func (*CLab) cleanupVeths(dryRun bool) ([]*clabruntime.OrphanedResource, error) {
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*clabruntime.OrphanedResource
+	for _, l := range orphanedVeths(links) {
+		name := l.Attrs().Name
+
+		res = append(res, &clabruntime.OrphanedResource{
+			Type:   "veth",
+			Name:   name,
+			Reason: "not moved to a node",
+		})
+
+		if dryRun {
+			continue
+		}
+
+		log.Info("Removing veth", "name", name)
+		if err := netlink.LinkDel(l); err != nil {
+			if _, lerr := netlink.LinkByName(name); lerr != nil {
+				// removed with its peer
+				continue
+			}
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+// orphanedVeths returns the veth interfaces of the host namespace that were never moved to a node:
+// both ends of the pair have the random names given on creation and are in the host namespace.
+// A veth with an end moved to a node is removed with the node and is never orphaned.
+func orphanedVeths(links []netlink.Link) []netlink.Link {
+	random := make(map[int]bool, len(links))
+	for _, l := range links {
+		if l.Type() == "veth" && labVethRe.MatchString(l.Attrs().Name) {
+			random[l.Attrs().Index] = true
+		}
+	}
+
+	var res []netlink.Link
+	for _, l := range links {
+		a := l.Attrs()
+		// the peer of a veth in another namespace is reported with the id of its namespace
+		if !random[a.Index] || a.NetNsID >= 0 || !random[a.ParentIndex] {
+			continue
+		}
+		res = append(res, l)
+	}
+
+	return res
+}
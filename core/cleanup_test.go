@@ -0,0 +1,129 @@
+package core
+
+import (
+	"os"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+func TestOrphanedContainerReason(t *testing.T) {
+	labDir := t.TempDir()
+
+	tests := map[string]struct {
+		cnt  *clabruntime.GenericContainer
+		want string
+	}{
+		"running node": {
+			cnt: &clabruntime.GenericContainer{
+				State:  "running",
+				Labels: map[string]string{clablabels.NodeLabDir: labDir},
+			},
+		},
+		"never started": {
+			cnt: &clabruntime.GenericContainer{
+				State:  "created",
+				Labels: map[string]string{clablabels.NodeLabDir: labDir},
+			},
+			want: "never started",
+		},
+		"lab directory removed": {
+			cnt: &clabruntime.GenericContainer{
+				State:  "running",
+				Labels: map[string]string{clablabels.NodeLabDir: "/nonexistent/clab-lab/srl1"},
+			},
+			want: "lab directory /nonexistent/clab-lab/srl1 not found",
+		},
+		"tool container": {
+			cnt: &clabruntime.GenericContainer{
+				State:  "created",
+				Labels: map[string]string{clablabels.ToolType: "sshx"},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := orphanedContainerReason(tt.cnt); got != tt.want {
+				t.Errorf("orphanedContainerReason() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOrphanedVeths(t *testing.T) {
+	veth := func(index int, name string, peer, netnsID int) netlink.Link {
+		return &netlink.Veth{LinkAttrs: netlink.LinkAttrs{
+			Index:       index,
+			Name:        name,
+			ParentIndex: peer,
+			NetNsID:     netnsID,
+		}}
+	}
+
+	links := []netlink.Link{
+		// a pair never moved to the nodes
+		veth(10, "clab-3f2a9c1d", 11, -1),
+		veth(11, "clab-8b7e6d5c", 10, -1),
+		// the peer is moved to a node
+		veth(12, "clab-0a1b2c3d", 5, 2),
+		// the peer is renamed in the host namespace, e.g. a host link
+		veth(13, "clab-4e5f6a7b", 14, -1),
+		veth(14, "srl1-e1-1", 13, -1),
+		// not a random name
+		veth(15, "vethab12cd3", 16, -1),
+		veth(16, "clab-9c8d7e6f", 15, -1),
+		&netlink.Bridge{LinkAttrs: netlink.LinkAttrs{Index: 17, Name: "clab-1a2b3c4d", NetNsID: -1}},
+	}
+
+	var got []string
+	for _, l := range orphanedVeths(links) {
+		got = append(got, l.Attrs().Name)
+	}
+
+	want := []string{"clab-3f2a9c1d", "clab-8b7e6d5c"}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("orphanedVeths() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestDeployLock(t *testing.T) {
+	lab := "cleanup-test-" + strconv.Itoa(os.Getpid())
+
+	unlock, err := lockDeploy(lab)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := deployInProgress(lab); !ok {
+		t.Error("want the deployment in progress")
+	}
+	if !slices.Contains(deploysInProgress(), lab) {
+		t.Errorf("want %s in the labs being deployed, got %v", lab, deploysInProgress())
+	}
+	if _, err := lockDeploy(lab); err == nil {
+		t.Error("want an error marking the deployment of a lab being deployed")
+	}
+
+	unlock()
+
+	if _, ok := deployInProgress(lab); ok {
+		t.Error("want no deployment in progress after unlock")
+	}
+
+	// the mark of a crashed deployment is stale
+	if err := os.WriteFile(clabtypes.DeployLockFileAbsPath(lab), []byte("2147483647"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(clabtypes.DeployLockFileAbsPath(lab))
+
+	if _, ok := deployInProgress(lab); ok {
+		t.Error("want the stale mark ignored")
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/log"
 	"github.com/pmorjan/kmod"
@@ -202,12 +203,14 @@ func (c *CLab) createNodeCfg(nodeName string, nodeDef *clabtypes.NodeDefinition,
 		Runtime:         c.Config.Topology.GetNodeRuntime(nodeName),
 		Devices:         c.Config.Topology.GetNodeDevices(nodeName),
 		CapAdd:          c.Config.Topology.GetNodeCapAdd(nodeName),
+		SecurityOpts:    c.Config.Topology.GetNodeSecurityOpts(nodeName),
 		ShmSize:         c.Config.Topology.GetNodeShmSize(nodeName),
 		CPU:             c.Config.Topology.GetNodeCPU(nodeName),
 		CPUSet:          c.Config.Topology.GetNodeCPUSet(nodeName),
 		Memory:          c.Config.Topology.GetNodeMemory(nodeName),
 		StartupDelay:    c.Config.Topology.GetNodeStartupDelay(nodeName),
 		AutoRemove:      c.Config.Topology.GetNodeAutoRemove(nodeName),
+		Maintenance:     c.Config.Topology.GetNodeMaintenance(nodeName),
 		RestartPolicy:   c.Config.Topology.GetRestartPolicy(nodeName),
 		Extras:          c.Config.Topology.GetNodeExtras(nodeName),
 		DNS:             c.Config.Topology.GetNodeDns(nodeName),
@@ -377,7 +380,11 @@ func (c *CLab) checkTopologyDefinition(ctx context.Context) error {
 	}
 
 	// Pull images for all nodes concurrently
-	return c.pullImagesForNodes(ctx)
+	if err := c.pullImagesForNodes(ctx); err != nil {
+		return err
+	}
+
+	return c.verifyImageLock(ctx)
 }
 
 // verifyRootNetNSLinks makes sure, that there will be no overlap in
@@ -516,13 +523,13 @@ func (c *CLab) verifyContainersUniqueness(ctx context.Context) error {
 	}
 
 	var dups []string
-	for idx := range c.Nodes {
-		if c.Nodes[idx].Config().SkipUniquenessCheck {
+	for _, n := range c.scheduledNodes() {
+		if n.Config().SkipUniquenessCheck {
 			continue
 		}
 		for cIdx := range containers {
-			if c.Nodes[idx].Config().LongName == containers[cIdx].Names[0] {
-				dups = append(dups, c.Nodes[idx].Config().LongName)
+			if n.Config().LongName == containers[cIdx].Names[0] {
+				dups = append(dups, n.Config().LongName)
 			}
 		}
 	}
@@ -530,6 +537,11 @@ func (c *CLab) verifyContainersUniqueness(ctx context.Context) error {
 		return fmt.Errorf("containers %q already exist. Add '--reconfigure' flag to the deploy command to first remove the containers and then deploy the lab", dups)
 	}
 
+	// an incremental deploy adds to the running lab
+	if c.deployDiff != nil {
+		return nil
+	}
+
 	// check that none of the existing containers has a label that matches
 	// the lab name of a currently deploying lab
 	// this ensures lab uniqueness
@@ -612,15 +624,25 @@ func (c *CLab) addDefaultLabels(cfg *clabtypes.NodeConfig) {
 	cfg.Labels[clablabels.NodeLabDir] = cfg.LabDir
 	cfg.Labels[clablabels.TopoFile] = c.TopoPaths.TopologyFilenameAbsPath()
 
-	// Use custom owner if set, otherwise use current user
-	owner := c.customOwner
+	cfg.Labels[clablabels.Owner] = c.Owner()
+
+	if !c.expires.IsZero() {
+		cfg.Labels[clablabels.Expires] = c.expires.Format(time.RFC3339)
+	}
+}
+
+// Owner returns the owner of the lab, the custom owner if set, otherwise the current user.
+func (c *CLab) Owner() string {
+	if c.customOwner != "" {
+		return c.customOwner
+	}
+
+	owner := os.Getenv("SUDO_USER")
 	if owner == "" {
-		owner = os.Getenv("SUDO_USER")
-		if owner == "" {
-			owner = os.Getenv("USER")
-		}
+		owner = os.Getenv("USER")
 	}
-	cfg.Labels[clablabels.Owner] = owner
+
+	return owner
 }
 
 // labelsToEnvVars adds labels to env vars with CLAB_LABEL_ prefix added
@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"sort"
+)
+
+// Adjacency is a link of the topology seen from one of its nodes,
+// with the link IPs of both ends allocated by the config engine.
+type Adjacency struct {
+	Node     string `json:"node"`
+	Port     string `json:"port"`
+	IP       string `json:"ip"`
+	Peer     string `json:"peer"`
+	PeerPort string `json:"peer_port"`
+	PeerIP   string `json:"peer_ip"`
+}
+
+// LinkAdjacencies returns the adjacencies of the links of the nodes with a link IP on both ends,
+// in the order of the node names and ports. The IPs are returned without their prefix length.
+func LinkAdjacencies(configs map[string]*NodeConfig) []*Adjacency {
+	var res []*Adjacency
+
+	for _, a := range NodeLinks(configs) {
+		ip, peerIP := linkAddr(a.IP), linkAddr(a.PeerIP)
+		if ip == "" || peerIP == "" {
+			continue
+		}
+
+		a.IP, a.PeerIP = ip, peerIP
+		res = append(res, a)
+	}
+
+	return res
+}
+
+// NodeLinks returns the adjacencies of all links of the nodes, in the order of the node names
+// and ports. The link IPs are returned as set or allocated by the config engine, with their prefix
+// length, and are empty when the link has no IP.
+func NodeLinks(configs map[string]*NodeConfig) []*Adjacency {
+	var res []*Adjacency
+
+	for name, cs := range configs {
+		links, _ := cs.Vars[vkLinks].([]interface{})
+		for _, l := range links {
+			vars, ok := l.(Dict)
+			if !ok {
+				continue
+			}
+			far, ok := vars[vkFarEnd].(Dict)
+			if !ok {
+				continue
+			}
+
+			res = append(res, &Adjacency{
+				Node:     name,
+				Port:     fmt.Sprintf("%v", vars[vkPort]),
+				IP:       linkVar(vars[vkLinkIP]),
+				Peer:     fmt.Sprintf("%v", far[vkNodeName]),
+				PeerPort: fmt.Sprintf("%v", far[vkPort]),
+				PeerIP:   linkVar(far[vkLinkIP]),
+			})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Node != res[j].Node {
+			return res[i].Node < res[j].Node
+		}
+		return res[i].Port < res[j].Port
+	})
+
+	return res
+}
+
+// linkVar returns the string of a link variable, empty when the variable is not set.
+func linkVar(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", v)
+}
+
+// linkAddr returns the address of a link IP variable, e.g. 10.0.0.1 for 10.0.0.1/31,
+// empty when the variable is not set or is not an IP.
+func linkAddr(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	s := fmt.Sprintf("%v", v)
+
+	if p, err := netip.ParsePrefix(s); err == nil {
+		return p.Addr().String()
+	}
+	if a, err := netip.ParseAddr(s); err == nil {
+		return a.String()
+	}
+
+	return ""
+}
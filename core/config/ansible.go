@@ -0,0 +1,215 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	"gopkg.in/yaml.v2"
+)
+
+// ExportFormatAnsible is the Ansible playbook export format.
+const ExportFormatAnsible = "ansible"
+
+// AnsiblePlaybookFile is the name of the exported playbook in the export dir.
+const AnsiblePlaybookFile = "playbook.yml"
+
+// ansibleNetworkOSLabel sets the ansible_network_os of the nodes of the kinds
+// without an Ansible connection, their config is pushed with cli_config over network_cli.
+const ansibleNetworkOSLabel = "config.ansible.network_os"
+
+const (
+	ansibleHTTPAPI    = "ansible.netcommon.httpapi"
+	ansibleNetworkCLI = "ansible.netcommon.network_cli"
+	ansibleCliConfig  = "ansible.netcommon.cli_config"
+	ansibleSrlCli     = "nokia.srlinux.cli"
+)
+
+// ansibleKind is the Ansible connection of a kind.
+type ansibleKind struct {
+	NetworkOS  string
+	Connection string
+}
+
+// ansibleKinds are the Ansible connections of the node kinds, they match the generated lab inventory.
+var ansibleKinds = map[string]*ansibleKind{ //nolint:gochecknoglobals
+	"nokia_srlinux":         {NetworkOS: "nokia.srlinux.srlinux", Connection: ansibleHTTPAPI},
+	"srl":                   {NetworkOS: "nokia.srlinux.srlinux", Connection: ansibleHTTPAPI},
+	"nokia_sros":            {NetworkOS: "nokia.sros.md", Connection: ansibleNetworkCLI},
+	"vr-sros":               {NetworkOS: "nokia.sros.md", Connection: ansibleNetworkCLI},
+	"nokia_srsim":           {NetworkOS: "nokia.sros.md", Connection: ansibleNetworkCLI},
+	"arista_ceos":           {NetworkOS: "arista.eos.eos", Connection: ansibleNetworkCLI},
+	"ceos":                  {NetworkOS: "arista.eos.eos", Connection: ansibleNetworkCLI},
+	"arista_veos":           {NetworkOS: "arista.eos.eos", Connection: ansibleNetworkCLI},
+	"vr-veos":               {NetworkOS: "arista.eos.eos", Connection: ansibleNetworkCLI},
+	"juniper_crpd":          {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"crpd":                  {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"juniper_vmx":           {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"vr-vmx":                {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"juniper_vqfx":          {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"vr-vqfx":               {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"juniper_vsrx":          {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"vr-vsrx":               {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"juniper_vjunosrouter":  {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"juniper_vjunosswitch":  {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"juniper_vjunosevolved": {NetworkOS: "junipernetworks.junos.junos", Connection: ansibleNetworkCLI},
+	"cisco_xrv":             {NetworkOS: "cisco.iosxr.iosxr", Connection: ansibleNetworkCLI},
+	"vr-xrv":                {NetworkOS: "cisco.iosxr.iosxr", Connection: ansibleNetworkCLI},
+	"cisco_xrv9k":           {NetworkOS: "cisco.iosxr.iosxr", Connection: ansibleNetworkCLI},
+	"vr-xrv9k":              {NetworkOS: "cisco.iosxr.iosxr", Connection: ansibleNetworkCLI},
+	"cisco_xrd":             {NetworkOS: "cisco.iosxr.iosxr", Connection: ansibleNetworkCLI},
+	"xrd":                   {NetworkOS: "cisco.iosxr.iosxr", Connection: ansibleNetworkCLI},
+	"cisco_csr1000v":        {NetworkOS: "cisco.ios.ios", Connection: ansibleNetworkCLI},
+	"vr-csr":                {NetworkOS: "cisco.ios.ios", Connection: ansibleNetworkCLI},
+	"cisco_c8000v":          {NetworkOS: "cisco.ios.ios", Connection: ansibleNetworkCLI},
+	"cisco_cat9kv":          {NetworkOS: "cisco.ios.ios", Connection: ansibleNetworkCLI},
+	"cisco_iol":             {NetworkOS: "cisco.ios.ios", Connection: ansibleNetworkCLI},
+	"cisco_n9kv":            {NetworkOS: "cisco.nxos.nxos", Connection: ansibleNetworkCLI},
+	"vr-n9kv":               {NetworkOS: "cisco.nxos.nxos", Connection: ansibleNetworkCLI},
+}
+
+// ansiblePlay is the play configuring a node.
+type ansiblePlay struct {
+	Name        string            `yaml:"name"`
+	Hosts       string            `yaml:"hosts"`
+	GatherFacts bool              `yaml:"gather_facts"`
+	Connection  string            `yaml:"connection"`
+	Vars        map[string]string `yaml:"vars"`
+	Tasks       []yaml.MapSlice   `yaml:"tasks"`
+}
+
+// nodeAnsibleKind returns the Ansible connection of the node, the network OS set with
+// the config.ansible.network_os label takes precedence over the kind and uses network_cli.
+func nodeAnsibleKind(cs *NodeConfig) (*ansibleKind, error) {
+	if v, ok := cs.TargetNode.Labels[ansibleNetworkOSLabel]; ok && v != "" {
+		return &ansibleKind{NetworkOS: v, Connection: ansibleNetworkCLI}, nil
+	}
+
+	if k, ok := ansibleKinds[cs.TargetNode.Kind]; ok {
+		return k, nil
+	}
+
+	return nil, fmt.Errorf("kind %s has no Ansible connection, set the %s label", cs.TargetNode.Kind, ansibleNetworkOSLabel)
+}
+
+// ansibleTasks returns the tasks pushing the rendered snippets of the node, a task per commit.
+// The snippets not committed on their own, with a "# commit: false" directive,
+// are pushed with the next snippet.
+func ansibleTasks(cs *NodeConfig, k *ansibleKind) []yaml.MapSlice {
+	var (
+		tasks []yaml.MapSlice
+		names []string
+		lines []string
+	)
+
+	for i, d := range cs.Data {
+		for _, l := range strings.Split(d, "\n") {
+			l = strings.TrimRight(l, " \t\r")
+			if strings.TrimSpace(l) == "" || strings.HasPrefix(strings.TrimSpace(l), "#") {
+				continue
+			}
+			lines = append(lines, l)
+		}
+		names = append(names, cs.Info[i])
+
+		deferred := i < len(cs.Meta) && cs.Meta[i].Commit == transport.CommitDeferred
+		if (deferred && i < len(cs.Data)-1) || len(lines) == 0 {
+			continue
+		}
+
+		tasks = append(tasks, ansibleTask(strings.Join(names, ", "), lines, k))
+		names, lines = nil, nil
+	}
+
+	return tasks
+}
+
+// ansibleTask returns the task pushing the config lines with the module of the connection.
+// SR Linux has no cli_config support, the lines are sent in a private candidate with its cli module.
+func ansibleTask(name string, lines []string, k *ansibleKind) yaml.MapSlice {
+	if k.Connection == ansibleHTTPAPI {
+		commands := make([]string, 0, len(lines)+2)
+		commands = append(commands, "enter candidate private")
+		commands = append(commands, lines...)
+		commands = append(commands, "commit now")
+
+		return yaml.MapSlice{
+			{Key: "name", Value: name},
+			{Key: ansibleSrlCli, Value: yaml.MapSlice{{Key: "commands", Value: commands}}},
+		}
+	}
+
+	return yaml.MapSlice{
+		{Key: "name", Value: name},
+		{Key: ansibleCliConfig, Value: yaml.MapSlice{{Key: "config", Value: strings.Join(lines, "\n") + "\n"}}},
+	}
+}
+
+// ExportAnsible writes an Ansible playbook pushing the rendered config of the nodes to dir.
+// The playbook has a play per node, with the connection of the node kind and a task per commit.
+// The hosts are the node names of the lab inventory, which has the addresses and credentials of the nodes.
+func ExportAnsible(allConfig map[string]*NodeConfig, dir string) error {
+	names := make([]string, 0, len(allConfig))
+	for n := range allConfig {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	plays := make([]*ansiblePlay, 0, len(names))
+
+	for _, n := range names {
+		cs := allConfig[n]
+
+		if len(cs.Data) == 0 {
+			log.Warnf("%s: no rendered config, skipping", n)
+			continue
+		}
+
+		k, err := nodeAnsibleKind(cs)
+		if err != nil {
+			log.Warnf("%s: %v, skipping", n, err)
+			continue
+		}
+
+		tasks := ansibleTasks(cs, k)
+		if len(tasks) == 0 {
+			log.Warnf("%s: no config lines, skipping", n)
+			continue
+		}
+
+		plays = append(plays, &ansiblePlay{
+			Name:       "Configure " + n,
+			Hosts:      cs.TargetNode.LongName,
+			Connection: k.Connection,
+			Vars:       map[string]string{"ansible_network_os": k.NetworkOS},
+			Tasks:      tasks,
+		})
+	}
+
+	if len(plays) == 0 {
+		return fmt.Errorf("no node configs to export")
+	}
+
+	b, err := yaml.Marshal(plays)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	p := filepath.Join(dir, AnsiblePlaybookFile)
+	err = os.WriteFile(p, append([]byte("---\n"), b...), 0o644) // skipcq: GSC-G306
+	if err != nil {
+		return err
+	}
+
+	log.Info("Exported Ansible playbook", "path", p, "nodes", len(plays))
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestExportAnsible(t *testing.T) {
+	allConfig := map[string]*NodeConfig{
+		"srl1": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "srl1", LongName: "clab-dc-srl1", Kind: "nokia_srlinux"},
+			Data: []string{
+				"# commit: false\nset / system name host-name srl1\n",
+				"set / interface ethernet-1/1 admin-state enable\n\n",
+			},
+			Info: []string{"base__srl.tmpl", "interfaces__srl.tmpl"},
+			Meta: []*SnippetMeta{
+				{Template: "base__srl.tmpl", Commit: transport.CommitDeferred},
+				{Template: "interfaces__srl.tmpl"},
+			},
+		},
+		"pe1": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "pe1", LongName: "clab-dc-pe1", Kind: "nokia_sros"},
+			Data:       []string{"/configure system name pe1\n/configure port 1/1/1 admin-state enable\n"},
+			Info:       []string{"base__vr-sros.tmpl"},
+		},
+		"host1": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "host1", LongName: "clab-dc-host1", Kind: "linux"},
+			Data:       []string{"ip link set eth1 up"},
+			Info:       []string{"base__linux.tmpl"},
+		},
+		"veos1": {
+			TargetNode: &clabtypes.NodeConfig{
+				ShortName: "veos1", LongName: "clab-dc-veos1", Kind: "vr-veos",
+				Labels: map[string]string{ansibleNetworkOSLabel: "arista.eos.eos"},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	if err := ExportAnsible(allConfig, dir); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, AnsiblePlaybookFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `---
+- name: Configure pe1
+  hosts: clab-dc-pe1
+  gather_facts: false
+  connection: ansible.netcommon.network_cli
+  vars:
+    ansible_network_os: nokia.sros.md
+  tasks:
+  - name: base__vr-sros.tmpl
+    ansible.netcommon.cli_config:
+      config: |
+        /configure system name pe1
+        /configure port 1/1/1 admin-state enable
+- name: Configure srl1
+  hosts: clab-dc-srl1
+  gather_facts: false
+  connection: ansible.netcommon.httpapi
+  vars:
+    ansible_network_os: nokia.srlinux.srlinux
+  tasks:
+  - name: base__srl.tmpl, interfaces__srl.tmpl
+    nokia.srlinux.cli:
+      commands:
+      - enter candidate private
+      - set / system name host-name srl1
+      - set / interface ethernet-1/1 admin-state enable
+      - commit now
+`
+	if d := cmp.Diff(want, string(b)); d != "" {
+		t.Errorf("playbook mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestNodeAnsibleKind(t *testing.T) {
+	tests := map[string]struct {
+		node    *clabtypes.NodeConfig
+		want    *ansibleKind
+		wantErr bool
+	}{
+		"kind": {
+			node: &clabtypes.NodeConfig{Kind: "srl"},
+			want: &ansibleKind{NetworkOS: "nokia.srlinux.srlinux", Connection: ansibleHTTPAPI},
+		},
+		"label": {
+			node: &clabtypes.NodeConfig{Kind: "linux", Labels: map[string]string{ansibleNetworkOSLabel: "vyos.vyos.vyos"}},
+			want: &ansibleKind{NetworkOS: "vyos.vyos.vyos", Connection: ansibleNetworkCLI},
+		},
+		"unknown kind": {
+			node:    &clabtypes.NodeConfig{Kind: "linux"},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := nodeAnsibleKind(&NodeConfig{TargetNode: tt.node})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("kind mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
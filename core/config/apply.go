@@ -0,0 +1,77 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Reasons of a node config not being in the intended state.
+const (
+	ApplyReasonNotApplied = "config never applied"
+	ApplyReasonChanged    = "rendered config changed"
+	ApplyReasonDrift      = "running config drifted"
+)
+
+// RenderedHash returns the hash of the config rendered for the node, empty when nothing was rendered.
+// As for the drift, the empty lines and comments, such as the provenance header, are not part of the hash.
+func RenderedHash(cs *NodeConfig) string {
+	lines := configLines(strings.Join(cs.Data, "\n"))
+	if len(lines) == 0 {
+		return ""
+	}
+
+	h := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+
+	return hex.EncodeToString(h[:])
+}
+
+// AppliedHashes returns the hashes of the rendered configs last committed to the nodes,
+// per node, from the history entries ordered oldest first.
+func AppliedHashes(entries []*HistoryEntry) map[string]string {
+	res := map[string]string{}
+
+	for _, e := range entries {
+		if e.Action != "commit" {
+			continue
+		}
+
+		for _, n := range e.Nodes {
+			if n.Status == HistoryStatusOK {
+				res[n.Node] = n.ConfigHash
+			}
+		}
+	}
+
+	return res
+}
+
+// ApplyReason returns why the config of the node must be committed for the node to be
+// in the state declared by the rendered config, empty when the node is in that state.
+// The appliedHash is the hash of the rendered config last committed to the node.
+func ApplyReason(cs *NodeConfig, appliedHash string) (string, error) {
+	if appliedHash == "" {
+		return ApplyReasonNotApplied, nil
+	}
+
+	if appliedHash != RenderedHash(cs) {
+		return ApplyReasonChanged, nil
+	}
+
+	if _, err := os.Stat(AppliedConfigPath(cs)); os.IsNotExist(err) {
+		return ApplyReasonNotApplied, nil
+	}
+
+	res := CheckDrift(cs)
+	if res.Err != nil {
+		return "", fmt.Errorf("failed to check the config drift: %w", res.Err)
+	}
+
+	if res.Drifted() {
+		return ApplyReasonDrift, nil
+	}
+
+	return "", nil
+}
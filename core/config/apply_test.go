@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestAppliedHashes(t *testing.T) {
+	entries := []*HistoryEntry{
+		{Action: "commit", Nodes: []*HistoryNode{
+			{Node: "srl1", Status: HistoryStatusOK, ConfigHash: "a1"},
+			{Node: "srl2", Status: HistoryStatusOK, ConfigHash: "b1"},
+		}},
+		{Action: "commit", Nodes: []*HistoryNode{
+			{Node: "srl1", Status: HistoryStatusOK, ConfigHash: "a2"},
+			{Node: "srl2", Status: HistoryStatusFailed, ConfigHash: "b2"},
+			{Node: "srl3", Status: HistoryStatusSkipped},
+		}},
+		{Action: "compare", Nodes: []*HistoryNode{
+			{Node: "srl1", Status: HistoryStatusOK, ConfigHash: "a3"},
+		}},
+	}
+
+	want := map[string]string{"srl1": "a2", "srl2": "b1"}
+	if d := cmp.Diff(want, AppliedHashes(entries)); d != "" {
+		t.Errorf("applied hashes mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestApplyReason(t *testing.T) {
+	cs := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "srl1", LabDir: t.TempDir()},
+		Data:       []string{"set / system name host-name srl1"},
+	}
+
+	tests := map[string]struct {
+		appliedHash string
+		want        string
+	}{
+		"never committed": {
+			want: ApplyReasonNotApplied,
+		},
+		"rendered config changed": {
+			appliedHash: "0123",
+			want:        ApplyReasonChanged,
+		},
+		"applied config missing": {
+			appliedHash: RenderedHash(cs),
+			want:        ApplyReasonNotApplied,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ApplyReason(cs, tc.appliedHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tc.want {
+				t.Errorf("got reason %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderedHash(t *testing.T) {
+	cs := &NodeConfig{Data: []string{"set / system name host-name srl1\n\nset / system ntp admin-state enable"}}
+	withComments := &NodeConfig{Data: []string{"# rendered from base__srl.tmpl\nset / system name host-name srl1\nset / system ntp admin-state enable"}}
+
+	if RenderedHash(cs) != RenderedHash(withComments) {
+		t.Error("expected the comments and empty lines not to change the hash")
+	}
+
+	if got := RenderedHash(&NodeConfig{Data: []string{"# comment only"}}); got != "" {
+		t.Errorf("expected no hash without config lines, got %q", got)
+	}
+}
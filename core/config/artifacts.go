@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Files of the artifacts directory of a config run.
+const (
+	artifactsReportFileName     = "report.json"
+	artifactsJUnitFileName      = "junit.xml"
+	artifactsNodesDirName       = "nodes"
+	artifactsRenderedDirName    = "rendered"
+	artifactsTranscriptFileName = "transcript.log"
+	artifactsSessionFileName    = "session.jsonl"
+	artifactsDiffFileName       = "diff.txt"
+)
+
+// Artifacts is the directory holding the files produced by a config run,
+// archived by CI jobs to keep what the run rendered, sent and changed:
+//
+//	<dir>/report.json                          the result of the run, as recorded in the lab history
+//	<dir>/junit.xml                            the verification results, when the run verified the config
+//	<dir>/nodes/<node>/rendered/<NN>-<info>.cfg the rendered config snippets, in the order they are sent
+//	<dir>/nodes/<node>/transcript.log          the output of the node's config session
+//	<dir>/nodes/<node>/session.jsonl           the timeline of the commands and outputs of the session
+//	<dir>/nodes/<node>/diff.txt                the changes to the config applied by the previous commit
+//
+// The secrets of the rendered config, transcripts and diffs are masked with the Redactor of the node.
+// The methods of a nil Artifacts do nothing, so callers don't check if the artifacts are enabled.
+type Artifacts struct {
+	Dir string
+}
+
+// NewArtifacts creates the artifacts directory, nil is returned when dir is empty.
+func NewArtifacts(dir string) (*Artifacts, error) {
+	if dir == "" {
+		return nil, nil
+	}
+
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil { // skipcq: GSC-G301
+		return nil, fmt.Errorf("failed to create the artifacts dir: %w", err)
+	}
+
+	return &Artifacts{Dir: dir}, nil
+}
+
+// JUnitPath returns the path of the JUnit report of the run.
+func (a *Artifacts) JUnitPath() string {
+	return filepath.Join(a.Dir, artifactsJUnitFileName)
+}
+
+// nodeDir returns the artifacts dir of the node, creating it.
+func (a *Artifacts) nodeDir(node string) (string, error) {
+	dir := filepath.Join(a.Dir, artifactsNodesDirName, node)
+
+	return dir, os.MkdirAll(dir, 0o755) // skipcq: GSC-G301
+}
+
+// SaveReport writes the history entry of the run as the report of the run.
+func (a *Artifacts) SaveReport(e *HistoryEntry) error {
+	if a == nil {
+		return nil
+	}
+
+	b, err := json.MarshalIndent(e, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(a.Dir, artifactsReportFileName), b, 0o644) // skipcq: GSC-G306
+}
+
+// SaveRendered writes the rendered config snippets of the node.
+func (a *Artifacts) SaveRendered(cs *NodeConfig) error {
+	if a == nil || len(cs.Data) == 0 {
+		return nil
+	}
+
+	dir, err := a.nodeDir(cs.TargetNode.ShortName)
+	if err != nil {
+		return err
+	}
+
+	dir = filepath.Join(dir, artifactsRenderedDirName)
+	if err := os.MkdirAll(dir, 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	for i, d := range cs.Data {
+		info := ""
+		if i < len(cs.Info) {
+			info = strings.Trim(nonFileCharsRe.ReplaceAllString(cs.Info[i], "-"), "-")
+		}
+
+		name := fmt.Sprintf("%02d-%s.cfg", i, info)
+		d = cs.Redactor.Redact(d)
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(d), 0o644); err != nil { // skipcq: GSC-G306
+			return err
+		}
+	}
+
+	return nil
+}
+
+// OpenTranscript sets the transcript and the session log of the node's config session
+// to the transcript and session log files of the node.
+// The returned function closes the files, it is a no-op when the artifacts are disabled.
+func (a *Artifacts) OpenTranscript(cs *NodeConfig) (func(), error) {
+	if a == nil {
+		return func() {}, nil
+	}
+
+	dir, err := a.nodeDir(cs.TargetNode.ShortName)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Create(filepath.Join(dir, artifactsTranscriptFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	sf, err := os.Create(filepath.Join(dir, artifactsSessionFileName))
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	w := cs.Redactor.Writer(f)
+	cs.Transcript = w
+	cs.Session = NewSessionLog(sf, cs.TargetNode.ShortName, cs.Redactor)
+
+	return func() {
+		_ = cs.Session.Flush()
+		cs.Transcript, cs.Session = nil, nil
+		_ = w.Flush()
+		f.Close()
+		sf.Close()
+	}, nil
+}
+
+// SaveDiff writes the changes between the previously applied config and the config applied by the run.
+func (a *Artifacts) SaveDiff(cs *NodeConfig, previous string) error {
+	if a == nil {
+		return nil
+	}
+
+	applied, err := os.ReadFile(AppliedConfigPath(cs))
+	if err != nil {
+		return err
+	}
+
+	dir, err := a.nodeDir(cs.TargetNode.ShortName)
+	if err != nil {
+		return err
+	}
+
+	added, removed := diffLines(previous, string(applied))
+
+	var s strings.Builder
+	for _, l := range removed {
+		fmt.Fprintf(&s, "- %s\n", cs.Redactor.Redact(l))
+	}
+	for _, l := range added {
+		fmt.Fprintf(&s, "+ %s\n", cs.Redactor.Redact(l))
+	}
+
+	return os.WriteFile(filepath.Join(dir, artifactsDiffFileName), []byte(s.String()), 0o644) // skipcq: GSC-G306
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestArtifacts(t *testing.T) {
+	labDir := t.TempDir()
+	a, err := NewArtifacts(filepath.Join(t.TempDir(), "run"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nc := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "srl1", LabDir: labDir},
+		Data:       []string{"set base", "set ifaces"},
+		Info:       []string{"base__srl.tmpl", "ifaces/srl.tmpl"},
+	}
+
+	if err := a.SaveRendered(nc); err != nil {
+		t.Fatal(err)
+	}
+
+	nodeDir := filepath.Join(a.Dir, "nodes", "srl1")
+	for name, want := range map[string]string{
+		"rendered/00-base__srl.tmpl.cfg":  "set base",
+		"rendered/01-ifaces-srl.tmpl.cfg": "set ifaces",
+	} {
+		b, err := os.ReadFile(filepath.Join(nodeDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(b) != want {
+			t.Errorf("%s = %q, want %q", name, b, want)
+		}
+	}
+
+	closeTranscript, err := a.OpenTranscript(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nc.Transcript == nil {
+		t.Fatal("transcript not set on the node config")
+	}
+	closeTranscript()
+	if _, err := os.Stat(filepath.Join(nodeDir, "transcript.log")); err != nil {
+		t.Error(err)
+	}
+
+	if err := os.WriteFile(AppliedConfigPath(nc), []byte("a\nc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := a.SaveDiff(nc, "a\nb\n"); err != nil {
+		t.Fatal(err)
+	}
+	b, err := os.ReadFile(filepath.Join(nodeDir, "diff.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "- b\n+ c\n"; string(b) != want {
+		t.Errorf("diff = %q, want %q", b, want)
+	}
+
+	if err := a.SaveReport(&HistoryEntry{Action: "commit"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(a.Dir, "report.json")); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestArtifactsDisabled(t *testing.T) {
+	a, err := NewArtifacts("")
+	if err != nil || a != nil {
+		t.Fatalf("NewArtifacts(\"\") = %v, %v, want nil, nil", a, err)
+	}
+
+	nc := &NodeConfig{TargetNode: &clabtypes.NodeConfig{ShortName: "srl1"}, Data: []string{"set base"}}
+	if err := a.SaveRendered(nc); err != nil {
+		t.Error(err)
+	}
+	closeTranscript, err := a.OpenTranscript(nc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	closeTranscript()
+	if err := a.SaveReport(&HistoryEntry{}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// defaultTestInterval is the time between the retries of a test without an interval.
+const defaultTestInterval = time.Second
+
+// jsonPathElemRe matches an element of a JSON path, a key optionally followed by list indexes.
+var jsonPathElemRe = regexp.MustCompile(`^([^\[\]]*)((?:\[\d+\])*)$`)
+
+// runTests runs the commands of the node's tests in the show session and their NAPALM getters,
+// retrying every test until its assertion holds or it runs out of retries. The results are added to res.
+func runTests(ctx context.Context, cs *NodeConfig, tests []*clabtypes.ConfigTest, res *VerifyResult) {
+	results := make([]*CheckResult, len(tests))
+	show := false
+	for i, t := range tests {
+		if (t.Command == "") == (t.Getter == "") {
+			res.Err = fmt.Errorf("test %d must have either a command or a getter", i+1)
+			return
+		}
+		if t.Getter != "" {
+			if err := validateGetter(t.Getter); err != nil {
+				res.Err = fmt.Errorf("test %q: %w", testName(t), err)
+				return
+			}
+		}
+		if err := validateExpect(t.Expect); err != nil {
+			res.Err = fmt.Errorf("test %q: %w", testName(t), err)
+			return
+		}
+		show = show || t.Command != ""
+		results[i] = &CheckResult{Path: testName(t), Expected: describeExpect(t.Expect)}
+		res.Checks = append(res.Checks, results[i])
+	}
+
+	// the SSH session is only opened for the tests with a command
+	var tx *transport.SSHTransport
+	if show {
+		var err error
+		if tx, err = newSSHTransport(cs); err != nil {
+			res.Err = err
+			return
+		}
+
+		if err := tx.ConnectShow(transport.NodeHost(cs.TargetNode)); err != nil {
+			res.Err = err
+			return
+		}
+		defer tx.Close()
+	}
+
+	for i, t := range tests {
+		interval := t.Interval
+		if interval <= 0 {
+			interval = defaultTestInterval
+		}
+
+		for attempt := 0; ; attempt++ {
+			var out string
+			var err error
+			if t.Getter != "" {
+				out, err = runGetter(ctx, cs, t.Getter)
+			} else {
+				out, err = tx.Show(t.Command, 10)
+			}
+			if err != nil {
+				results[i].Got = err.Error()
+			} else {
+				results[i].Got, results[i].Passed = checkExpect(t.Expect, out)
+			}
+
+			if results[i].Passed || attempt >= t.Retries {
+				break
+			}
+
+			log.Debugf("%s: test %q failed on attempt %d: %s", res.Node, results[i].Path, attempt+1, results[i].Got)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+// testName returns the name of the test in the reports.
+func testName(t *clabtypes.ConfigTest) string {
+	if t.Name != "" {
+		return t.Name
+	}
+	if t.Getter != "" {
+		return t.Getter
+	}
+	return t.Command
+}
+
+// validateExpect checks that the assertion of a test sets an expectation and its regexp is valid.
+func validateExpect(e *clabtypes.TestExpect) error {
+	if e.Contains == "" && e.Regex == "" && e.JSONPath == "" {
+		return fmt.Errorf("expect sets none of contains, regex or json-path")
+	}
+	if e.Value != "" && e.JSONPath == "" {
+		return fmt.Errorf("expect sets a value without a json-path")
+	}
+	if _, err := regexp.Compile(e.Regex); err != nil {
+		return fmt.Errorf("invalid expect regex: %w", err)
+	}
+	return nil
+}
+
+// describeExpect returns the assertion of a test as shown in the reports.
+func describeExpect(e *clabtypes.TestExpect) string {
+	var s []string
+	if e.Contains != "" {
+		s = append(s, fmt.Sprintf("contains %q", e.Contains))
+	}
+	if e.Regex != "" {
+		s = append(s, fmt.Sprintf("matches %q", e.Regex))
+	}
+	if e.JSONPath != "" {
+		if e.Value != "" {
+			s = append(s, fmt.Sprintf("%s is %q", e.JSONPath, e.Value))
+		} else {
+			s = append(s, fmt.Sprintf("%s exists", e.JSONPath))
+		}
+	}
+	return strings.Join(s, ", ")
+}
+
+// checkExpect checks the assertion of a test on the command output.
+// It returns whether the assertion holds and what was found in the output.
+func checkExpect(e *clabtypes.TestExpect, out string) (string, bool) {
+	if e.Contains != "" && !strings.Contains(out, e.Contains) {
+		return fmt.Sprintf("output without %q", e.Contains), false
+	}
+
+	if e.Regex != "" {
+		// the regexp is validated before the test runs
+		if !regexp.MustCompile(e.Regex).MatchString(out) {
+			return fmt.Sprintf("output not matching %q", e.Regex), false
+		}
+	}
+
+	if e.JSONPath != "" {
+		var doc any
+		if err := json.Unmarshal([]byte(out), &doc); err != nil {
+			return fmt.Sprintf("invalid JSON output: %v", err), false
+		}
+		v, err := lookupJSONPath(doc, e.JSONPath)
+		if err != nil {
+			return err.Error(), false
+		}
+		got := jsonValueString(v)
+		if e.Value != "" && got != e.Value {
+			return got, false
+		}
+		return got, true
+	}
+
+	return "ok", true
+}
+
+// lookupJSONPath returns the value selected by a dot-separated path of keys and list indexes,
+// e.g. interfaces[0].oper-state. A leading $ is ignored.
+func lookupJSONPath(doc any, path string) (any, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	if path == "" {
+		return doc, nil
+	}
+
+	v := doc
+	for _, elem := range strings.Split(path, ".") {
+		m := jsonPathElemRe.FindStringSubmatch(elem)
+		if m == nil {
+			return nil, fmt.Errorf("invalid json-path element %q", elem)
+		}
+
+		if m[1] != "" {
+			obj, ok := v.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("%s: not an object", m[1])
+			}
+			if v, ok = obj[m[1]]; !ok {
+				return nil, fmt.Errorf("%s: not found", m[1])
+			}
+		}
+
+		for _, idx := range strings.Split(strings.Trim(m[2], "[]"), "][") {
+			if idx == "" {
+				continue
+			}
+			list, ok := v.([]any)
+			if !ok {
+				return nil, fmt.Errorf("%s[%s]: not a list", m[1], idx)
+			}
+			n, _ := strconv.Atoi(idx)
+			if n >= len(list) {
+				return nil, fmt.Errorf("%s[%s]: index out of range", m[1], idx)
+			}
+			v = list[n]
+		}
+	}
+
+	return v, nil
+}
+
+// jsonValueString returns a JSON value as compared with the expected value,
+// scalars as they are written and objects and lists as JSON.
+func jsonValueString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		b, _ := json.Marshal(v)
+		return string(b)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,91 @@
+package config
+
+import (
+	"testing"
+
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestCheckExpect(t *testing.T) {
+	const jsonOut = `{"interfaces": [{"name": "ethernet-1/1", "oper-state": "up", "mtu": 9232}]}`
+
+	tests := map[string]struct {
+		expect *clabtypes.TestExpect
+		out    string
+		got    string
+		passed bool
+	}{
+		"contains": {
+			expect: &clabtypes.TestExpect{Contains: "0% packet loss"},
+			out:    "3 packets transmitted, 3 received, 0% packet loss",
+			got:    "ok",
+			passed: true,
+		},
+		"contains missing": {
+			expect: &clabtypes.TestExpect{Contains: "0% packet loss"},
+			out:    "3 packets transmitted, 1 received, 67% packet loss",
+			got:    `output without "0% packet loss"`,
+		},
+		"regex": {
+			expect: &clabtypes.TestExpect{Regex: `(?m)^ethernet-1/1\s+up`},
+			out:    "ethernet-1/1   up\nethernet-1/2   down",
+			got:    "ok",
+			passed: true,
+		},
+		"json-path value": {
+			expect: &clabtypes.TestExpect{JSONPath: "interfaces[0].oper-state", Value: "up"},
+			out:    jsonOut,
+			got:    "up",
+			passed: true,
+		},
+		"json-path number": {
+			expect: &clabtypes.TestExpect{JSONPath: "$.interfaces[0].mtu", Value: "9232"},
+			out:    jsonOut,
+			got:    "9232",
+			passed: true,
+		},
+		"json-path wrong value": {
+			expect: &clabtypes.TestExpect{JSONPath: "interfaces[0].oper-state", Value: "down"},
+			out:    jsonOut,
+			got:    "up",
+		},
+		"json-path out of range": {
+			expect: &clabtypes.TestExpect{JSONPath: "interfaces[1].name"},
+			out:    jsonOut,
+			got:    "interfaces[1]: index out of range",
+		},
+		"json-path exists": {
+			expect: &clabtypes.TestExpect{JSONPath: "interfaces[0]"},
+			out:    jsonOut,
+			got:    `{"mtu":9232,"name":"ethernet-1/1","oper-state":"up"}`,
+			passed: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, passed := checkExpect(tt.expect, tt.out)
+			if got != tt.got || passed != tt.passed {
+				t.Errorf("checkExpect() = %q, %v, want %q, %v", got, passed, tt.got, tt.passed)
+			}
+		})
+	}
+}
+
+func TestValidateExpect(t *testing.T) {
+	invalid := map[string]*clabtypes.TestExpect{
+		"empty":              {},
+		"value without path": {Contains: "up", Value: "up"},
+		"invalid regexp":     {Regex: "(up"},
+	}
+
+	for name, e := range invalid {
+		if err := validateExpect(e); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+
+	if err := validateExpect(&clabtypes.TestExpect{JSONPath: "a.b", Value: "1"}); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// bootstrapTemplate is the name of the bootstrap templates. bootstrap__<role>.tmpl renders the commands
+// sent once per session before the config is written, e.g. to disable paging, in place of the
+// terminal setup commands of the SSH kind. The commands are not part of the committed config.
+const bootstrapTemplate = "bootstrap"
+
+// renderBootstrap renders the bootstrap template of the node with the first engine having one for its role.
+func (r *Renderer) renderBootstrap(nc *NodeConfig) error {
+	role := fmt.Sprintf("%s", nc.Vars[vkRole])
+
+	for _, e := range r.engines {
+		tmplN, err := e.Lookup(bootstrapTemplate, role)
+		if err != nil {
+			return err
+		}
+		if tmplN == "" {
+			continue
+		}
+
+		var res strings.Builder
+		if err := e.Render(&res, tmplN, role, nc.Vars); err != nil {
+			return fmt.Errorf("%s: %w", tmplN, err)
+		}
+
+		nc.Bootstrap = bootstrapCommands(res.String())
+
+		return nil
+	}
+
+	return nil
+}
+
+// bootstrapCommands returns the commands of a rendered bootstrap template, one per line.
+// Empty lines and comments are skipped, an empty template results in no commands
+// rather than nil, so that it disables the terminal setup commands of the kind.
+func bootstrapCommands(s string) []string {
+	cmds := []string{}
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		cmds = append(cmds, l)
+	}
+
+	return cmds
+}
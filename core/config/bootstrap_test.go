@@ -0,0 +1,72 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestRenderBootstrap(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, tmpl := range map[string]string{
+		"base__vr-sros.tmpl":      "/configure system name {{ .clab_node }}",
+		"bootstrap__vr-sros.tmpl": "# paging off\n/environment more false\n\n/environment console width {{ .width }}\n",
+		"bootstrap__srl.tmpl":     "",
+		"base__srl.tmpl":          "set / system name host-name {{ .clab_node }}",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(tmpl), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRenderer(WithTemplatePaths([]string{dir}), WithRenderWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the bootstrap templates are not config snippets
+	if d := cmp.Diff([]string{"base"}, r.Names); d != "" {
+		t.Errorf("template names mismatch (-want +got):\n%s", d)
+	}
+
+	tests := map[string]struct {
+		role string
+		want []string
+	}{
+		"commands": {
+			role: "vr-sros",
+			want: []string{"/environment more false", "/environment console width 512"},
+		},
+		"empty template disables the kind setup": {
+			role: "srl",
+			want: []string{},
+		},
+		"no template": {
+			role: "nokia_srsim",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			nc := &NodeConfig{
+				TargetNode: &clabtypes.NodeConfig{ShortName: "r1"},
+				Vars:       map[string]interface{}{vkNodeName: "r1", vkRole: tc.role, "width": 512},
+			}
+
+			if err := r.RenderNode(nc); err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tc.want, nc.Bootstrap); d != "" {
+				t.Errorf("bootstrap mismatch (-want +got):\n%s", d)
+			}
+			if (tc.want == nil) != (nc.Bootstrap == nil) {
+				t.Errorf("bootstrap = %#v, want %#v", nc.Bootstrap, tc.want)
+			}
+		})
+	}
+}
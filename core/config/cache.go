@@ -0,0 +1,38 @@
+package config
+
+import "sync"
+
+// parseCache holds the values parsed once per key, e.g. the template set of a role.
+// It is safe for concurrent use: the first lookup of a key parses the value while the other
+// lookups of the key wait for it, the lookups of the other keys are not blocked.
+// A parse error is cached as well, the key is not parsed again.
+type parseCache[T any] struct {
+	mu      sync.Mutex
+	entries map[string]*parseEntry[T]
+}
+
+type parseEntry[T any] struct {
+	once sync.Once
+	val  T
+	err  error
+}
+
+// get returns the value of the key, parsed with parse on the first lookup of the key.
+func (c *parseCache[T]) get(key string, parse func() (T, error)) (T, error) {
+	c.mu.Lock()
+	if c.entries == nil {
+		c.entries = make(map[string]*parseEntry[T])
+	}
+	e, ok := c.entries[key]
+	if !ok {
+		e = &parseEntry[T]{}
+		c.entries[key] = e
+	}
+	c.mu.Unlock()
+
+	e.once.Do(func() {
+		e.val, e.err = parse()
+	})
+
+	return e.val, e.err
+}
@@ -0,0 +1,66 @@
+package config
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCache(t *testing.T) {
+	var c parseCache[string]
+	var parsed atomic.Int32
+
+	// the lookups of a key wait for a single parse
+	var wg sync.WaitGroup
+	got := make([]string, 20)
+	for i := range got {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			key := "srl"
+			if i%2 == 1 {
+				key = "sros"
+			}
+			v, err := c.get(key, func() (string, error) {
+				parsed.Add(1)
+				return key + " templates", nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+			got[i] = v
+		}()
+	}
+	wg.Wait()
+
+	if n := parsed.Load(); n != 2 {
+		t.Errorf("want 2 parses, got %d", n)
+	}
+	for i, v := range got {
+		want := "srl templates"
+		if i%2 == 1 {
+			want = "sros templates"
+		}
+		if d := cmp.Diff(want, v); d != "" {
+			t.Errorf("lookup %d mismatch (-want +got):\n%s", i, d)
+		}
+	}
+
+	// the parse errors are cached
+	errParse := errors.New("template: base__linux.tmpl: unexpected EOF")
+	for range 2 {
+		_, err := c.get("linux", func() (string, error) {
+			parsed.Add(1)
+			return "", errParse
+		})
+		if !errors.Is(err, errParse) {
+			t.Errorf("want error %v, got %v", errParse, err)
+		}
+	}
+	if n := parsed.Load(); n != 3 {
+		t.Errorf("want 3 parses, got %d", n)
+	}
+}
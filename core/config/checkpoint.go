@@ -0,0 +1,115 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// checkpointFileName is the file in the node's lab dir recording the config chunks
+// committed by a config commit that did not complete.
+const checkpointFileName = "config-checkpoint.json"
+
+// checkpoint records the number of committed chunks of the config snippets of a node,
+// so that a failed commit resumes after the last committed chunk.
+type checkpoint struct {
+	path string
+	// Snippets maps the template name to the committed chunks of the rendered snippet.
+	Snippets map[string]*snippetCheckpoint `json:"snippets"`
+}
+
+type snippetCheckpoint struct {
+	// Hash of the rendered snippet, a changed snippet is committed from the start.
+	Hash      string `json:"hash"`
+	ChunkSize int    `json:"chunk-size"`
+	Chunks    int    `json:"chunks"`
+}
+
+// loadCheckpoint loads the checkpoint of the node, an empty checkpoint is returned
+// when the node has none.
+func loadCheckpoint(cs *NodeConfig) *checkpoint {
+	cp := &checkpoint{
+		path:     filepath.Join(cs.TargetNode.LabDir, checkpointFileName),
+		Snippets: map[string]*snippetCheckpoint{},
+	}
+
+	b, err := os.ReadFile(cp.path)
+	if err != nil {
+		return cp
+	}
+
+	if err := json.Unmarshal(b, cp); err != nil || cp.Snippets == nil {
+		log.Warnf("%s: ignoring invalid config checkpoint %s: %v", cs.TargetNode.ShortName, cp.path, err)
+		cp.Snippets = map[string]*snippetCheckpoint{}
+	}
+
+	return cp
+}
+
+// resume returns the number of committed chunks of the snippets
+// that did not change since the checkpoint was recorded.
+func (cp *checkpoint) resume(cs *NodeConfig, chunkSize int) map[string]int {
+	res := map[string]int{}
+	for i, info := range cs.Info {
+		s, ok := cp.Snippets[info]
+		if ok && s.ChunkSize == chunkSize && s.Hash == snippetHash(cs.Data[i]) {
+			res[info] = s.Chunks
+		}
+	}
+	return res
+}
+
+// commit records the number of committed chunks of a snippet.
+func (cp *checkpoint) commit(cs *NodeConfig, info string, chunkSize, chunks int) {
+	i := slices.Index(cs.Info, info)
+	if i < 0 {
+		return
+	}
+
+	cp.Snippets[info] = &snippetCheckpoint{
+		Hash:      snippetHash(cs.Data[i]),
+		ChunkSize: chunkSize,
+		Chunks:    chunks,
+	}
+
+	b, err := json.Marshal(cp)
+	if err == nil {
+		err = os.WriteFile(cp.path, b, 0o644) // skipcq: GSC-G306
+	}
+	if err != nil {
+		log.Warnf("%s: failed to save config checkpoint: %v", cs.TargetNode.ShortName, err)
+	}
+}
+
+// clear removes the checkpoint after all snippets were committed.
+func (cp *checkpoint) clear() {
+	err := os.Remove(cp.path)
+	if err != nil && !os.IsNotExist(err) {
+		log.Warnf("failed to remove config checkpoint %s: %v", cp.path, err)
+	}
+}
+
+func snippetHash(data string) string {
+	// comments are not sent, they don't change the snippet.
+	// The lines are hashed one at a time, big snippets are not copied.
+	h := sha256.New()
+	sep := ""
+	for data != "" {
+		var l string
+		l, data, _ = strings.Cut(data, "\n")
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		h.Write([]byte(sep))
+		h.Write([]byte(l))
+		sep = "\n"
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
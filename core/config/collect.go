@@ -0,0 +1,156 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// BundleSupport is the bundle of troubleshooting show commands collected for a support case.
+const BundleSupport = "support"
+
+// collectTimeout is the time in seconds to wait for the output of a collected show command.
+const collectTimeout = 30
+
+var (
+	srlSupportCommands = []string{ //nolint:gochecknoglobals
+		"show version",
+		"show platform chassis",
+		"show system application",
+		"show interface brief",
+		"show interface detail",
+		"show network-instance summary",
+		"show network-instance default route-table",
+		"show network-instance default protocols bgp neighbor",
+		"show system lldp neighbor",
+		"info from running /",
+	}
+	srosSupportCommands = []string{ //nolint:gochecknoglobals
+		"show version",
+		"show system information",
+		"show card state",
+		"show port",
+		"show router interface",
+		"show router route-table",
+		"show router bgp summary",
+		"show router isis adjacency",
+		"show log log-id 99",
+		"admin show configuration",
+	}
+)
+
+// bundleCommands maps the bundles to the show commands collected per kind.
+var bundleCommands = map[string]map[string][]string{ //nolint:gochecknoglobals
+	BundleSupport: {
+		"srl":           srlSupportCommands,
+		"nokia_srlinux": srlSupportCommands,
+		"vr-sros":       srosSupportCommands,
+		"nokia_sros":    srosSupportCommands,
+		"nokia_srsim":   srosSupportCommands,
+		"srsim":         srosSupportCommands,
+	},
+}
+
+// BundleCommands returns the show commands of the bundle for the kind,
+// an empty list if the bundle has no commands for the kind.
+func BundleCommands(bundle, kind string) ([]string, error) {
+	kinds, ok := bundleCommands[bundle]
+	if !ok {
+		names := make([]string, 0, len(bundleCommands))
+		for n := range bundleCommands {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown bundle %q, expected one of: %s", bundle, strings.Join(names, ", "))
+	}
+	return kinds[kind], nil
+}
+
+// CollectOutput is the output of a show command collected from a node.
+type CollectOutput struct {
+	Command string
+	Output  string
+	Err     error
+}
+
+// Collect runs the show commands on the node, a failed command doesn't stop the collection.
+// An error is returned only when the node can't be connected to.
+func Collect(cs *NodeConfig, commands []string) ([]*CollectOutput, error) {
+	tx, err := newSSHTransport(cs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.ConnectShow(transport.NodeHost(cs.TargetNode))
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Close()
+
+	res := make([]*CollectOutput, 0, len(commands))
+	for _, cmd := range commands {
+		out, err := tx.Show(cmd, collectTimeout)
+		res = append(res, &CollectOutput{Command: cmd, Output: out, Err: err})
+	}
+
+	return res, nil
+}
+
+// WriteBundle writes the collected outputs of the nodes to a gzipped tar archive at path,
+// with a <node>/<NN>-<command>.txt file per command. The failed commands have their error written.
+func WriteBundle(path string, outputs map[string][]*CollectOutput) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gw := gzip.NewWriter(f)
+	tw := tar.NewWriter(gw)
+
+	nodes := make([]string, 0, len(outputs))
+	for n := range outputs {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	now := time.Now()
+	for _, n := range nodes {
+		for i, o := range outputs[n] {
+			content := o.Output
+			if o.Err != nil {
+				content = fmt.Sprintf("error: %v\n%s", o.Err, o.Output)
+			}
+
+			name := fmt.Sprintf("%02d-%s.txt", i+1, strings.Trim(nonFileCharsRe.ReplaceAllString(o.Command, "-"), "-"))
+			hdr := &tar.Header{
+				Name:    filepath.ToSlash(filepath.Join(n, name)),
+				Mode:    0o644,
+				Size:    int64(len(content)),
+				ModTime: now,
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			if _, err := tw.Write([]byte(content)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := gw.Close(); err != nil {
+		return err
+	}
+
+	return f.Close()
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBundleCommands(t *testing.T) {
+	cmds, err := BundleCommands(BundleSupport, "nokia_srlinux")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmds) == 0 {
+		t.Error("expected support commands for nokia_srlinux")
+	}
+
+	cmds, err = BundleCommands(BundleSupport, "linux")
+	if err != nil || len(cmds) != 0 {
+		t.Errorf("expected no support commands for linux, got %v, %v", cmds, err)
+	}
+
+	if _, err := BundleCommands("everything", "nokia_srlinux"); err == nil {
+		t.Error("expected an error for an unknown bundle")
+	}
+}
+
+func TestWriteBundle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "support.tar.gz")
+
+	err := WriteBundle(path, map[string][]*CollectOutput{
+		"srl2": {{Command: "show version", Output: "v24.3.1"}},
+		"srl1": {
+			{Command: "show version", Output: "v24.3.1"},
+			{Command: "show interface brief", Err: errors.New("timeout")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gr)
+
+	got := map[string]string{}
+	var names []string
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := io.ReadAll(tr)
+		got[hdr.Name] = string(b)
+		names = append(names, hdr.Name)
+	}
+
+	want := []string{"srl1/01-show-version.txt", "srl1/02-show-interface-brief.txt", "srl2/01-show-version.txt"}
+	if d := cmp.Diff(want, names); d != "" {
+		t.Errorf("unexpected archive files (-want +got):\n%s", d)
+	}
+	if got["srl1/02-show-interface-brief.txt"] != "error: timeout\n" {
+		t.Errorf("unexpected failed command output %q", got["srl1/02-show-interface-brief.txt"])
+	}
+}
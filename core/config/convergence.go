@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// ConvergenceOptions are the timings of a convergence measurement.
+type ConvergenceOptions struct {
+	// Interval is the time between the snapshots of the route tables.
+	Interval time.Duration
+	// Settle is the time the route tables of all nodes must stay unchanged for the lab to be converged.
+	Settle time.Duration
+	// Timeout is the maximum time to wait for the lab to converge after the event.
+	Timeout time.Duration
+}
+
+// Convergence is the convergence of the route table of a node after an event.
+type Convergence struct {
+	Node   string         `json:"node"`
+	Before *RouteSnapshot `json:"before,omitempty"`
+	After  *RouteSnapshot `json:"after,omitempty"`
+	Delta  *RouteDelta    `json:"delta,omitempty"`
+	// Changed is true if the route table changed after the event, Time is the time from the event
+	// to the snapshot showing the last change, with the resolution of the snapshot interval.
+	Changed bool          `json:"changed"`
+	Time    time.Duration `json:"time"`
+	Err     error         `json:"-"`
+}
+
+// ConvergenceReport is the convergence of the nodes of a lab after an event.
+type ConvergenceReport struct {
+	Event time.Time `json:"event"`
+	// Time is the convergence time of the lab, the longest convergence time of the nodes.
+	Time      time.Duration           `json:"time"`
+	Converged bool                    `json:"converged"`
+	Nodes     map[string]*Convergence `json:"nodes"`
+}
+
+// MeasureConvergence snapshots the route tables of the nodes before the event, runs the event and
+// snapshots them at the interval until none of them changes for the settle time or the timeout expires.
+// A node failing a snapshot is left out of the following snapshots with its error.
+func MeasureConvergence(ctx context.Context, readers map[string]*RouteReader, event func() error,
+	o *ConvergenceOptions,
+) (*ConvergenceReport, error) {
+	rep := &ConvergenceReport{Nodes: make(map[string]*Convergence, len(readers))}
+	for n := range readers {
+		rep.Nodes[n] = &Convergence{Node: n}
+	}
+
+	for n, s := range snapshotAll(readers, rep) {
+		rep.Nodes[n].Before = s
+		rep.Nodes[n].After = s
+	}
+
+	rep.Event = time.Now().UTC()
+	if err := event(); err != nil {
+		return nil, fmt.Errorf("event failed: %w", err)
+	}
+	log.Info("Event triggered, waiting for the route tables to settle", "settle", o.Settle, "timeout", o.Timeout)
+
+	lastChange := rep.Event
+	ticker := time.NewTicker(o.Interval)
+	defer ticker.Stop()
+
+	for time.Since(lastChange) < o.Settle {
+		if time.Since(rep.Event) > o.Timeout {
+			log.Warnf("the route tables did not settle within %s", o.Timeout)
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+
+		for n, s := range snapshotAll(readers, rep) {
+			c := rep.Nodes[n]
+			if c.After != nil && !DiffRoutes(c.After, s).Empty() {
+				c.Changed = true
+				c.Time = s.Time.Sub(rep.Event)
+				lastChange = time.Now()
+				log.Debugf("%s: route table changed at T+%s", n, c.Time)
+			}
+			c.After = s
+		}
+	}
+
+	rep.Converged = time.Since(lastChange) >= o.Settle
+	for _, c := range rep.Nodes {
+		if c.Before != nil && c.After != nil {
+			c.Delta = DiffRoutes(c.Before, c.After)
+		}
+		rep.Time = max(rep.Time, c.Time)
+	}
+
+	return rep, nil
+}
+
+// snapshotAll snapshots the route tables of the nodes without an error in the report concurrently.
+func snapshotAll(readers map[string]*RouteReader, rep *ConvergenceReport) map[string]*RouteSnapshot {
+	var (
+		wg  sync.WaitGroup
+		m   sync.Mutex
+		res = make(map[string]*RouteSnapshot, len(readers))
+	)
+
+	for n, r := range readers {
+		if rep.Nodes[n].Err != nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s, err := r.Snapshot()
+
+			m.Lock()
+			defer m.Unlock()
+			if err != nil {
+				log.Errorf("%s: failed to read the route table: %v", n, err)
+				rep.Nodes[n].Err = err
+				return
+			}
+			res[n] = s
+		}()
+	}
+	wg.Wait()
+
+	return res
+}
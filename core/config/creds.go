@@ -0,0 +1,161 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/log"
+)
+
+// credentialsFileName is the file of the lab dir holding the credentials of the nodes
+// changed with tools rotate-creds, used by the config runs instead of the kind credentials.
+const credentialsFileName = "credentials.json"
+
+// credentialCommands are the config lines changing the credentials of a node by kind,
+// templates of the Username and Password of the new credentials.
+var credentialCommands = map[string]string{ //nolint:gochecknoglobals
+	"srl": `{{- if eq .Username "admin" -}}
+set / system aaa authentication admin-user password {{ .Password }}
+{{- else -}}
+set / system aaa authentication user {{ .Username }} password {{ .Password }}
+set / system aaa authentication user {{ .Username }} superuser true
+{{- end }}`,
+	"sros": `/configure system security user-params local-user user "{{ .Username }}"
+    password "{{ .Password }}"
+    access console true
+    access netconf true
+    access grpc true
+    console member ["administrative"]`,
+}
+
+// credentialKinds maps the node kinds to their credential commands.
+var credentialKinds = map[string]string{ //nolint:gochecknoglobals
+	"srl":           "srl",
+	"nokia_srlinux": "srl",
+	"vr-sros":       "sros",
+	"nokia_sros":    "sros",
+	"nokia_srsim":   "sros",
+	"srsim":         "sros",
+}
+
+// CredentialsPath returns the path of the credentials file of the lab dir.
+func CredentialsPath(labDir string) string {
+	return filepath.Join(labDir, credentialsFileName)
+}
+
+// LoadCredentials returns the username and password of the nodes stored in the credentials file by node name.
+// A lab without rotated credentials has no credentials file.
+func LoadCredentials(path string) (map[string][]string, error) {
+	res := map[string][]string{}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("invalid credentials file %s: %w", path, err)
+	}
+
+	return res, nil
+}
+
+// SaveCredentials stores the credentials of the nodes in the credentials file,
+// keeping the credentials of the other nodes. The file is only readable by its owner.
+func SaveCredentials(path string, creds map[string][]string) error {
+	all, err := LoadCredentials(path)
+	if err != nil {
+		return err
+	}
+
+	for n, c := range creds {
+		all[n] = c
+	}
+
+	b, err := json.MarshalIndent(all, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o600)
+}
+
+// CredentialCommands returns the config lines changing the credentials of a node of the kind.
+func CredentialCommands(kind, username, password string) (string, error) {
+	k, ok := credentialKinds[kind]
+	if !ok {
+		return "", fmt.Errorf("credential rotation is not implemented for kind %s", kind)
+	}
+
+	if username == "" || password == "" {
+		return "", fmt.Errorf("the username and password must be set")
+	}
+	if strings.ContainsAny(username+password, "\"\n") {
+		return "", fmt.Errorf("the username and password must not contain quotes or newlines")
+	}
+
+	t, err := template.New(k).Parse(credentialCommands[k])
+	if err != nil {
+		return "", err
+	}
+
+	var b bytes.Buffer
+	err = t.Execute(&b, struct{ Username, Password string }{username, password})
+
+	return b.String(), err
+}
+
+// RotateCredentials changes the credentials of the node to the username and password
+// with the config commands of its kind, sent with the current credentials of the node.
+// The node config uses the new credentials once they are changed.
+func RotateCredentials(ctx context.Context, cs *NodeConfig, username, password string) error {
+	cmds, err := CredentialCommands(cs.TargetNode.Kind, username, password)
+	if err != nil {
+		return err
+	}
+
+	cs.Data = []string{cmds}
+	cs.Info = []string{"rotate-creds"}
+	cs.Meta = nil
+
+	if err := Send(ctx, cs, "commit"); err != nil {
+		return err
+	}
+
+	cs.Credentials = []string{username, password}
+	cs.rotated = true
+
+	return nil
+}
+
+// sshCredentials returns the username and password the node is logged into with.
+// The rotated credentials of the node come first, then the credentials of the profile
+// of the credentials store matching the node, and the kind credentials last.
+func (cs *NodeConfig) sshCredentials() ([]string, error) {
+	if cs.rotated || cs.credStore == nil {
+		return cs.Credentials, nil
+	}
+
+	name, p := cs.credStore.Lookup(cs.lab, cs.TargetNode.ShortName, cs.TargetNode.Kind)
+	if p == nil {
+		return cs.Credentials, nil
+	}
+
+	username, password, err := p.Credentials()
+	if err != nil {
+		return nil, fmt.Errorf("credentials profile %s of node %s: %w", name, cs.TargetNode.ShortName, err)
+	}
+
+	log.Debug("Using the credentials profile", "node", cs.TargetNode.ShortName, "profile", name)
+
+	return []string{username, password}, nil
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabcorecredstore "github.com/srl-labs/containerlab/core/credstore"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestCredentialCommands(t *testing.T) {
+	tests := map[string]struct {
+		kind, username string
+		want           string
+		err            bool
+	}{
+		"srl admin": {
+			kind:     "nokia_srlinux",
+			username: "admin",
+			want:     "set / system aaa authentication admin-user password n3w",
+		},
+		"srl user": {
+			kind:     "srl",
+			username: "ops",
+			want: "set / system aaa authentication user ops password n3w\n" +
+				"set / system aaa authentication user ops superuser true",
+		},
+		"sros": {
+			kind:     "nokia_sros",
+			username: "admin",
+			want: `/configure system security user-params local-user user "admin"
+    password "n3w"
+    access console true
+    access netconf true
+    access grpc true
+    console member ["administrative"]`,
+		},
+		"unsupported kind": {
+			kind:     "linux",
+			username: "root",
+			err:      true,
+		},
+		"no username": {
+			kind: "srl",
+			err:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := CredentialCommands(tc.kind, tc.username, "n3w")
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %q", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("commands mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSaveCredentials(t *testing.T) {
+	path := CredentialsPath(t.TempDir())
+
+	creds, err := LoadCredentials(path)
+	if err != nil || len(creds) != 0 {
+		t.Fatalf("expected no credentials, got %v, %v", creds, err)
+	}
+
+	if err := SaveCredentials(path, map[string][]string{"srl1": {"admin", "one"}, "srl2": {"admin", "one"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := SaveCredentials(path, map[string][]string{"srl2": {"ops", "two"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err = LoadCredentials(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]string{"srl1": {"admin", "one"}, "srl2": {"ops", "two"}}
+	if d := cmp.Diff(want, creds); d != "" {
+		t.Errorf("credentials mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestSSHCredentials(t *testing.T) {
+	t.Setenv("CLAB_TEST_PASSWORD", "from-env")
+
+	store := &clabcorecredstore.Store{Profiles: map[string]*clabcorecredstore.Profile{
+		"srl":   {Kind: "nokia_srlinux", Username: "ops", Password: "srl"},
+		"ci":    {Lab: "ci", Node: "leaf*", Username: "ci", PasswordEnv: "CLAB_TEST_PASSWORD"},
+		"unset": {Node: "spine*", Username: "ci", PasswordEnv: "CLAB_TEST_UNSET"},
+	}}
+
+	tests := map[string]struct {
+		lab, node, kind string
+		rotated         bool
+		store           *clabcorecredstore.Store
+		want            []string
+		wantErr         bool
+	}{
+		"kind credentials": {lab: "dc", node: "r1", kind: "linux", store: store, want: []string{"admin", "admin"}},
+		"no store":         {lab: "dc", node: "srl1", kind: "nokia_srlinux", want: []string{"admin", "admin"}},
+		"kind profile":     {lab: "dc", node: "srl1", kind: "nokia_srlinux", store: store, want: []string{"ops", "srl"}},
+		"env profile":      {lab: "ci", node: "leaf1", kind: "nokia_srlinux", store: store, want: []string{"ci", "from-env"}},
+		"rotated": {
+			lab: "dc", node: "srl1", kind: "nokia_srlinux", rotated: true, store: store,
+			want: []string{"admin", "admin"},
+		},
+		"env unset": {lab: "dc", node: "spine1", kind: "linux", store: store, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cs := &NodeConfig{
+				TargetNode:  &clabtypes.NodeConfig{ShortName: tt.node, Kind: tt.kind},
+				Credentials: []string{"admin", "admin"},
+				credStore:   tt.store,
+				lab:         tt.lab,
+				rotated:     tt.rotated,
+			}
+
+			got, err := cs.sshCredentials()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("credentials mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// dialerLabel sets the dialer of the node's transports, overriding the dialer of the config run,
+// e.g. socks5://127.0.0.1:1080, source:wg0 or netns:vpn.
+const dialerLabel = "config.dialer"
+
+// SetDialers sets the dialer of the transports of the nodes to the dialer of their config.dialer label,
+// or to the dialer of spec for the nodes without the label. An empty spec keeps the default dialer.
+// The nodes dialed from their network namespace with DialFromNetNS don't use it.
+func SetDialers(configs map[string]*NodeConfig, spec string) error {
+	dialers := map[string]transport.Dialer{}
+
+	for name, cs := range configs {
+		s := spec
+		if v, ok := cs.TargetNode.Labels[dialerLabel]; ok {
+			s = v
+		}
+		if s == "" {
+			continue
+		}
+
+		d, ok := dialers[s]
+		if !ok {
+			var err error
+			d, err = transport.ParseDialer(s)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			dialers[s] = d
+		}
+
+		cs.Dialer = d
+	}
+
+	return nil
+}
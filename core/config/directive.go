@@ -0,0 +1,116 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// Snippets declare how they are sent with directive comment lines, e.g. "# commit: false".
+// The comment lines are not sent to the node.
+const (
+	directiveCommit  = "commit"
+	directiveSection = "section"
+	directiveWeight  = "weight"
+)
+
+// sectionWeights are the weights of the config sections a snippet can declare,
+// the snippets are sent in a device-friendly order of the sections.
+var sectionWeights = map[string]int{ //nolint:gochecknoglobals
+	"system":     100,
+	"interfaces": 200,
+	"routing":    300,
+	"services":   400,
+}
+
+// directiveRe matches the directive comment lines of a snippet.
+var directiveRe = regexp.MustCompile(`(?m)^[ \t]*#[ \t]*(commit|section|weight):[ \t]*(\S+)[ \t]*$`)
+
+// snippetDirective returns the value of the first directive of the snippet with the name.
+func snippetDirective(data, name string) (string, bool) {
+	for _, m := range directiveRe.FindAllStringSubmatch(data, -1) {
+		if m[1] == name {
+			return m[2], true
+		}
+	}
+	return "", false
+}
+
+// commitMode returns the commit mode declared by the snippet, the chunked commit by default.
+//   - false: the changes are committed with the next committed snippet, or at the end of the node
+//   - per-snippet: the snippet is committed at once, regardless of the chunk size
+//   - true: the snippet is committed in chunks of the chunk size of the node, the default
+func commitMode(data string) (string, error) {
+	v, ok := snippetDirective(data, directiveCommit)
+	if !ok {
+		return transport.CommitChunks, nil
+	}
+
+	switch v {
+	case "true":
+		return transport.CommitChunks, nil
+	case transport.CommitDeferred, transport.CommitPerSnippet:
+		return v, nil
+	}
+
+	return "", fmt.Errorf("invalid commit mode %q, expected true, false or per-snippet", v)
+}
+
+// snippetWeight returns the weight of the snippet, declared with a section or a weight directive.
+// The snippets without any are weighted 0 and sent first.
+func snippetWeight(data string) (int, error) {
+	if v, ok := snippetDirective(data, directiveWeight); ok {
+		w, err := strconv.Atoi(v)
+		if err != nil {
+			return 0, fmt.Errorf("invalid weight %q", v)
+		}
+		return w, nil
+	}
+
+	if v, ok := snippetDirective(data, directiveSection); ok {
+		w, ok := sectionWeights[v]
+		if !ok {
+			return 0, fmt.Errorf("unknown section %q, expected system, interfaces, routing or services", v)
+		}
+		return w, nil
+	}
+
+	return 0, nil
+}
+
+// sortSnippets orders the snippets of the node by their weight,
+// the snippets of the same weight keep the order of the template names.
+func (c *NodeConfig) sortSnippets() {
+	if len(c.Meta) != len(c.Data) {
+		return
+	}
+
+	idx := make([]int, len(c.Data))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return c.Meta[idx[i]].Weight < c.Meta[idx[j]].Weight })
+
+	data := make([]string, len(idx))
+	info := make([]string, len(idx))
+	meta := make([]*SnippetMeta, len(idx))
+	for i, j := range idx {
+		data[i], info[i], meta[i] = c.Data[j], c.Info[j], c.Meta[j]
+	}
+	c.Data, c.Info, c.Meta = data, info, meta
+}
+
+// commitModes returns the commit modes of the snippets by their info,
+// the snippets committed in chunks are not listed.
+func (c *NodeConfig) commitModes() map[string]string {
+	res := map[string]string{}
+	for i, m := range c.Meta {
+		if m.Commit != transport.CommitChunks && i < len(c.Info) {
+			res[c.Info[i]] = m.Commit
+		}
+	}
+	return res
+}
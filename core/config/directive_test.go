@@ -0,0 +1,105 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+func TestCommitMode(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		want    string
+		wantErr bool
+	}{
+		"no directive":  {data: "set / system name host-name srl1", want: transport.CommitChunks},
+		"deferred":      {data: "# commit: false\n/configure port 1/1/1 admin-state enable", want: transport.CommitDeferred},
+		"per-snippet":   {data: "set a\n  #commit:per-snippet  \nset b", want: transport.CommitPerSnippet},
+		"explicit true": {data: "# commit: true\nset a", want: transport.CommitChunks},
+		"other comment": {data: "# commit the interfaces\nset a", want: transport.CommitChunks},
+		"invalid":       {data: "# commit: later\nset a", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := commitMode(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("commitMode() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("commitMode() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCommitModes(t *testing.T) {
+	nc := &NodeConfig{
+		Info: []string{"base__srl.tmpl", "link__srl.tmpl", "bgp__srl.tmpl"},
+		Meta: []*SnippetMeta{
+			{Template: "base__srl.tmpl"},
+			{Template: "link__srl.tmpl", Commit: transport.CommitDeferred},
+			{Template: "bgp__srl.tmpl", Commit: transport.CommitPerSnippet},
+		},
+	}
+
+	want := map[string]string{
+		"link__srl.tmpl": transport.CommitDeferred,
+		"bgp__srl.tmpl":  transport.CommitPerSnippet,
+	}
+	if d := cmp.Diff(want, nc.commitModes()); d != "" {
+		t.Errorf("commitModes() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestSnippetWeight(t *testing.T) {
+	tests := map[string]struct {
+		data    string
+		want    int
+		wantErr bool
+	}{
+		"no directive":     {data: "set a", want: 0},
+		"section":          {data: "# section: routing\nset a", want: 300},
+		"weight":           {data: "# weight: 250\nset a", want: 250},
+		"weight wins":      {data: "# section: services\n# weight: 50\nset a", want: 50},
+		"unknown section":  {data: "# section: qos\nset a", wantErr: true},
+		"invalid weight":   {data: "# weight: high\nset a", wantErr: true},
+		"with commit mode": {data: "# commit: false\n# section: interfaces\nset a", want: 200},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := snippetWeight(tc.data)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("snippetWeight() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("snippetWeight() = %d, want %d", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSortSnippets(t *testing.T) {
+	nc := &NodeConfig{
+		Data: []string{"bgp", "base", "links", "isis"},
+		Info: []string{"bgp__srl.tmpl", "base__srl.tmpl", "links__srl.tmpl", "isis__srl.tmpl"},
+		Meta: []*SnippetMeta{
+			{Weight: sectionWeights["routing"]},
+			{},
+			{Weight: sectionWeights["interfaces"]},
+			{Weight: sectionWeights["routing"]},
+		},
+	}
+
+	nc.sortSnippets()
+
+	want := []string{"base", "links", "bgp", "isis"}
+	if d := cmp.Diff(want, nc.Data); d != "" {
+		t.Errorf("sortSnippets() mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"base__srl.tmpl", "links__srl.tmpl", "bgp__srl.tmpl", "isis__srl.tmpl"}, nc.Info); d != "" {
+		t.Errorf("sortSnippets() info mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,203 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// appliedConfigFileName is the file in the node's lab dir holding the running
+// configuration captured after the last successful config commit.
+const appliedConfigFileName = "applied-config.txt"
+
+// appliedTransportHeader starts the comment line of the applied config file
+// with the transport the configuration was retrieved with.
+const appliedTransportHeader = "# transport: "
+
+// DriftResult is the result of a drift check of a node.
+type DriftResult struct {
+	Node    string
+	Added   []string
+	Removed []string
+	Err     error
+}
+
+// Drifted returns true when the running config differs from the applied one.
+func (r *DriftResult) Drifted() bool {
+	return len(r.Added) > 0 || len(r.Removed) > 0
+}
+
+// String implements stringer interface for DriftResult.
+func (r *DriftResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s: %s", r.Node, r.Err)
+	}
+	if !r.Drifted() {
+		return fmt.Sprintf("%s: no drift", r.Node)
+	}
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "%s: config drift detected, %d lines added, %d lines removed",
+		r.Node, len(r.Added), len(r.Removed))
+	for _, l := range r.Removed {
+		fmt.Fprintf(&s, "\n  - %s", l)
+	}
+	for _, l := range r.Added {
+		fmt.Fprintf(&s, "\n  + %s", l)
+	}
+	return s.String()
+}
+
+// AppliedConfigPath returns the path of the applied config file of the node.
+func AppliedConfigPath(cs *NodeConfig) string {
+	return filepath.Join(cs.TargetNode.LabDir, appliedConfigFileName)
+}
+
+// FetchRunning returns the running configuration of the node and the transport it was retrieved with,
+// the transports are tried in the order of the config.transport label.
+// The configuration retrieved with gNMI is normalized to path = value lines.
+func FetchRunning(cs *NodeConfig) (string, string, error) {
+	chain := configTransports(cs)
+
+	var errs []string
+	for _, ct := range chain {
+		cfg, err := fetchRunning(cs, ct)
+		if err == nil {
+			return cfg, ct, nil
+		}
+		if len(chain) == 1 {
+			return "", ct, err
+		}
+		log.Debugf("%s: failed to fetch the running config with the %s transport: %s", cs.TargetNode.ShortName, ct, err)
+		errs = append(errs, fmt.Sprintf("%s: %s", ct, err))
+	}
+
+	return "", "", fmt.Errorf("no transport retrieved the running config, %s", strings.Join(errs, "; "))
+}
+
+// fetchRunning returns the running configuration of the node retrieved with the transport.
+func fetchRunning(cs *NodeConfig, ct string) (string, error) {
+	switch ct {
+	case transportSSH:
+		return fetchRunningSSH(cs)
+	case transportGNMI, transportGRPC:
+		return fetchRunningGNMI(cs)
+	}
+	return "", fmt.Errorf("config retrieval over the %s transport is not implemented", ct)
+}
+
+// fetchRunningSSH returns the running configuration of the node shown by the CLI.
+func fetchRunningSSH(cs *NodeConfig) (string, error) {
+	tx, err := newSSHTransport(cs)
+	if err != nil {
+		return "", err
+	}
+
+	// no config is written, only the show session is needed
+	err = tx.ConnectShow(transport.NodeHost(cs.TargetNode))
+	if err != nil {
+		return "", err
+	}
+	defer tx.Close()
+
+	return tx.RunningConfig()
+}
+
+// SaveAppliedState captures the running configuration of the node
+// as the reference for the drift detection.
+func SaveAppliedState(cs *NodeConfig) error {
+	cfg, ct, err := FetchRunning(cs)
+	if err != nil {
+		return err
+	}
+
+	// the drift check retrieves the running config with the same transport
+	cfg = appliedTransportHeader + ct + "\n" + cfg
+
+	err = os.WriteFile(AppliedConfigPath(cs), []byte(cfg), 0o644) // skipcq: GSC-G306
+	if err != nil {
+		return err
+	}
+
+	log.Debugf("%s: saved applied config to %s", cs.TargetNode.ShortName, AppliedConfigPath(cs))
+
+	return nil
+}
+
+// CheckDrift compares the running configuration of the node
+// with the configuration captured after the last config commit.
+func CheckDrift(cs *NodeConfig) *DriftResult {
+	res := &DriftResult{Node: cs.TargetNode.ShortName}
+
+	applied, err := os.ReadFile(AppliedConfigPath(cs))
+	if err != nil {
+		if os.IsNotExist(err) {
+			err = fmt.Errorf("no applied config found, run config commit first")
+		}
+		res.Err = err
+		return res
+	}
+
+	running, err := fetchRunning(cs, appliedTransport(string(applied)))
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	res.Added, res.Removed = diffLines(string(applied), running)
+
+	return res
+}
+
+// appliedTransport returns the transport the applied config was retrieved with,
+// ssh for the applied configs saved without the transport.
+func appliedTransport(applied string) string {
+	first, _, _ := strings.Cut(applied, "\n")
+	if ct, ok := strings.CutPrefix(first, appliedTransportHeader); ok && ct != "" {
+		return strings.TrimSpace(ct)
+	}
+	return transportSSH
+}
+
+// diffLines returns the lines added to and removed from the old config.
+// Empty lines, comments and surrounding whitespace are ignored
+// as they contain volatile data, e.g. the time the config was generated.
+func diffLines(old, cur string) (added, removed []string) {
+	count := map[string]int{}
+	for _, l := range configLines(old) {
+		count[l]++
+	}
+
+	for _, l := range configLines(cur) {
+		if count[l] > 0 {
+			count[l]--
+			continue
+		}
+		added = append(added, l)
+	}
+
+	for _, l := range configLines(old) {
+		if count[l] > 0 {
+			count[l]--
+			removed = append(removed, l)
+		}
+	}
+
+	return added, removed
+}
+
+func configLines(cfg string) []string {
+	var res []string
+	for _, l := range strings.Split(cfg, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		res = append(res, l)
+	}
+	return res
+}
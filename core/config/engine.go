@@ -0,0 +1,195 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/log"
+	jT "github.com/kellerza/template"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// Render engines, selected per template path with the <engine>: prefix, e.g. jinja2:./templates.
+const (
+	engineGo         = "go"
+	engineJinja2     = "jinja2"
+	engineOpenConfig = "openconfig"
+)
+
+// Jinja2RendererEnv is the environment variable with the command rendering the Jinja2 templates.
+// The command receives the template dir and the template file name as arguments,
+// the variables as JSON on stdin and writes the rendered template to stdout.
+const Jinja2RendererEnv = "CLAB_JINJA2_RENDERER"
+
+// jinja2Script renders a Jinja2 template with python, used when no renderer command is set.
+const jinja2Script = `import json, sys
+from jinja2 import Environment, FileSystemLoader, StrictUndefined
+env = Environment(loader=FileSystemLoader(sys.argv[1]), undefined=StrictUndefined,
+                  trim_blocks=True, lstrip_blocks=True)
+sys.stdout.write(env.get_template(sys.argv[2]).render(json.load(sys.stdin)))
+`
+
+// renderEngine renders the templates of its template paths.
+type renderEngine interface {
+	// Name of the engine.
+	Name() string
+	// Lookup returns the name of the template for the role, empty if not found.
+	Lookup(name, role string) (string, error)
+	// Render writes the template found by Lookup for the role to w.
+	Render(w io.Writer, tmplN, role string, vars map[string]interface{}) error
+}
+
+// splitTemplatePath returns the render engine and the path of a template path.
+func splitTemplatePath(p string) (engine, path string) {
+	for _, e := range []string{engineGo, engineJinja2, engineOpenConfig} {
+		if strings.HasPrefix(p, e+":") {
+			return e, strings.TrimPrefix(p, e+":")
+		}
+	}
+	return engineGo, p
+}
+
+// goEngine renders the native Go templates (*.tmpl) of all Go template paths.
+// The templates of a role are parsed once into a template set shared by all nodes of the role,
+// the nodes of other roles look up and render their templates without waiting for the parse.
+type goEngine struct {
+	paths []string
+
+	// roles caches the template sets of the roles, parsed on the first lookup of the role
+	roles parseCache[*template.Template]
+}
+
+func (*goEngine) Name() string {
+	return engineGo
+}
+
+// templates returns the template set of the role.
+func (e *goEngine) templates(role string) (*template.Template, error) {
+	return e.roles.get(role, func() (*template.Template, error) {
+		tmpl := template.New("").Funcs(clabutils.CreateFuncs()).Funcs(jT.Funcs)
+		return tmpl, LoadTemplates(tmpl, e.paths, role)
+	})
+}
+
+func (e *goEngine) Lookup(name, role string) (string, error) {
+	tmplN := fmt.Sprintf("%s__%s.tmpl", name, role)
+	log.Debugf("Looking up template %v", tmplN)
+
+	tmpl, err := e.templates(role)
+	if err != nil {
+		return "", err
+	}
+
+	if tmpl.Lookup(tmplN) == nil {
+		return "", nil
+	}
+
+	return tmplN, nil
+}
+
+// Render renders the template found by Lookup from the template set of the role,
+// the template set is not modified once parsed and is executed concurrently.
+func (e *goEngine) Render(w io.Writer, tmplN, role string, vars map[string]interface{}) error {
+	tmpl, err := e.templates(role)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.ExecuteTemplate(w, tmplN, vars)
+}
+
+// jinja2Engine renders the Jinja2 templates (*.j2) of a template path with an external renderer.
+type jinja2Engine struct {
+	dir string
+
+	// found caches the template lookups, nodes of the same role look up the same files
+	found parseCache[bool]
+}
+
+func (*jinja2Engine) Name() string {
+	return engineJinja2
+}
+
+func (e *jinja2Engine) Lookup(name, role string) (string, error) {
+	tmplN := fmt.Sprintf("%s__%s.j2", name, role)
+	log.Debugf("Looking up template %v in %s", tmplN, e.dir)
+
+	found, err := e.found.get(tmplN, func() (bool, error) {
+		return fileExists(filepath.Join(e.dir, tmplN))
+	})
+	if err != nil || !found {
+		return "", err
+	}
+
+	return tmplN, nil
+}
+
+func (e *jinja2Engine) Render(w io.Writer, tmplN, _ string, vars map[string]interface{}) error {
+	in, err := json.Marshal(jsonVars(vars))
+	if err != nil {
+		return fmt.Errorf("could not pass the variables to the Jinja2 renderer: %w", err)
+	}
+
+	var cmd *exec.Cmd
+	if r := os.Getenv(Jinja2RendererEnv); r != "" {
+		cmd = exec.Command(r, e.dir, tmplN) // skipcq: GSC-G204
+	} else {
+		cmd = exec.Command("python3", "-c", jinja2Script, e.dir, tmplN)
+	}
+
+	var stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = w
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("could not render %s with %s: %w: %s",
+			tmplN, cmd.Args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// fileExists returns true when the file exists.
+func fileExists(p string) (bool, error) {
+	_, err := os.Stat(p)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// jsonVars converts the variables to types supported by JSON,
+// the maps decoded from YAML have interface{} keys.
+func jsonVars(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			res[k] = jsonVars(val)
+		}
+		return res
+	case Dict:
+		return jsonVars(map[string]interface{}(v))
+	case map[interface{}]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for k, val := range v {
+			res[fmt.Sprint(k)] = jsonVars(val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for i, val := range v {
+			res[i] = jsonVars(val)
+		}
+		return res
+	}
+	return v
+}
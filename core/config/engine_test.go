@@ -0,0 +1,151 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestSplitTemplatePath(t *testing.T) {
+	tests := map[string]struct {
+		engine, path string
+	}{
+		"./templates":         {engineGo, "./templates"},
+		"go:./templates":      {engineGo, "./templates"},
+		"jinja2:./templates":  {engineJinja2, "./templates"},
+		"openconfig:./intent": {engineOpenConfig, "./intent"},
+		"@":                   {engineGo, "@"},
+	}
+
+	for p, tc := range tests {
+		engine, path := splitTemplatePath(p)
+		if engine != tc.engine || path != tc.path {
+			t.Errorf("splitTemplatePath(%q) = %q, %q, want %q, %q", p, engine, path, tc.engine, tc.path)
+		}
+	}
+}
+
+func TestJinja2Engine(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "base__srl.j2"), []byte("hostname {{ clab_node }}"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the renderer echoes the variables passed on stdin
+	renderer := filepath.Join(dir, "render.sh")
+	err = os.WriteFile(renderer, []byte("#!/bin/sh\ncat\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(Jinja2RendererEnv, renderer)
+
+	e := &jinja2Engine{dir: dir}
+
+	tmplN, err := e.Lookup("base", "srl")
+	if err != nil || tmplN != "base__srl.j2" {
+		t.Fatalf("Lookup() = %q, %v, want base__srl.j2", tmplN, err)
+	}
+
+	tmplN, err = e.Lookup("base", "nokia_sros")
+	if err != nil || tmplN != "" {
+		t.Fatalf("Lookup() = %q, %v, want no template", tmplN, err)
+	}
+
+	vars := map[string]interface{}{
+		"clab_node": "srl1",
+		"yaml":      map[interface{}]interface{}{1: "one"},
+	}
+
+	var got strings.Builder
+	if err := e.Render(&got, "base__srl.j2", "srl", vars); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"clab_node":"srl1","yaml":{"1":"one"}}`
+	if d := cmp.Diff(want, got.String()); d != "" {
+		t.Errorf("Render() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRenderParallel(t *testing.T) {
+	dir := t.TempDir()
+
+	for role, tmpl := range map[string]string{
+		"srl":  "set / system name host-name {{ .clab_node }}",
+		"sros": "/configure system name {{ .clab_node }}",
+	} {
+		err := os.WriteFile(filepath.Join(dir, "base__"+role+".tmpl"), []byte(tmpl), 0o644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRenderer(
+		WithTemplatePaths([]string{dir}),
+		WithTemplateNames([]string{"base"}),
+		WithRenderWorkers(4),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := make(map[string]*NodeConfig)
+	for i := 0; i < 40; i++ {
+		role := "srl"
+		if i%2 == 1 {
+			role = "sros"
+		}
+		name := fmt.Sprintf("n%d", i)
+		nodes[name] = &NodeConfig{
+			TargetNode: &clabtypes.NodeConfig{ShortName: name},
+			Vars:       map[string]interface{}{vkNodeName: name, vkRole: role},
+		}
+	}
+
+	if err := r.Render(nodes); err != nil {
+		t.Fatal(err)
+	}
+
+	for name, nc := range nodes {
+		want := "set / system name host-name " + name
+		if nc.Vars[vkRole] == "sros" {
+			want = "/configure system name " + name
+		}
+		if d := cmp.Diff([]string{want}, nc.Data); d != "" {
+			t.Errorf("%s: rendered config mismatch (-want +got):\n%s", name, d)
+		}
+	}
+}
+
+func TestGoEngineSeparatorInName(t *testing.T) {
+	dir := t.TempDir()
+
+	// the separator of the template name and the role is part of the template name
+	err := os.WriteFile(filepath.Join(dir, "sys__base__srl.tmpl"), []byte("set / system name host-name {{ .clab_node }}"), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e := &goEngine{paths: []string{dir}}
+
+	tmplN, err := e.Lookup("sys__base", "srl")
+	if err != nil || tmplN != "sys__base__srl.tmpl" {
+		t.Fatalf("Lookup() = %q, %v, want sys__base__srl.tmpl", tmplN, err)
+	}
+
+	var got strings.Builder
+	if err := e.Render(&got, tmplN, "srl", map[string]interface{}{"clab_node": "srl1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff("set / system name host-name srl1", got.String()); d != "" {
+		t.Errorf("Render() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// expectScripts returns the expect scripts run before and after the config of the node is written.
+func expectScripts(cs *NodeConfig) (before, after []transport.ExpectStep, err error) {
+	e := cs.TargetNode.Config.GetExpect()
+	if e == nil {
+		return nil, nil, nil
+	}
+
+	before, err = expectSteps(e.Before)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: expect script before the config: %w", cs.TargetNode.ShortName, err)
+	}
+
+	after, err = expectSteps(e.After)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: expect script after the config: %w", cs.TargetNode.ShortName, err)
+	}
+
+	return before, after, nil
+}
+
+// expectSteps parses the steps of an expect script of the topology.
+func expectSteps(steps []*clabtypes.ExpectStep) ([]transport.ExpectStep, error) {
+	var res []transport.ExpectStep
+
+	for i, s := range steps {
+		if s.Send == "" && s.Expect == "" {
+			return nil, fmt.Errorf("step %d: neither send nor expect set", i+1)
+		}
+
+		step := transport.ExpectStep{Send: s.Send}
+
+		if s.Expect != "" {
+			re, err := regexp.Compile(s.Expect)
+			if err != nil {
+				return nil, fmt.Errorf("step %d: invalid expect regexp: %w", i+1, err)
+			}
+			step.Expect = re
+		}
+
+		if s.Timeout != "" {
+			d, err := time.ParseDuration(s.Timeout)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("step %d: invalid timeout %q", i+1, s.Timeout)
+			}
+			step.Timeout = d
+		}
+
+		res = append(res, step)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,70 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestExpectScripts(t *testing.T) {
+	tests := map[string]struct {
+		expect  *clabtypes.ExpectScripts
+		before  int
+		after   int
+		wantErr bool
+	}{
+		"none": {},
+		"before and after": {
+			expect: &clabtypes.ExpectScripts{
+				Before: []*clabtypes.ExpectStep{
+					{Expect: `\[yes/no\]`, Timeout: "30s"},
+					{Send: "yes", Expect: `#\s*$`},
+				},
+				After: []*clabtypes.ExpectStep{{Send: "write memory"}},
+			},
+			before: 2,
+			after:  1,
+		},
+		"empty step": {
+			expect:  &clabtypes.ExpectScripts{Before: []*clabtypes.ExpectStep{{Timeout: "5s"}}},
+			wantErr: true,
+		},
+		"invalid regexp": {
+			expect:  &clabtypes.ExpectScripts{After: []*clabtypes.ExpectStep{{Expect: "(["}}},
+			wantErr: true,
+		},
+		"invalid timeout": {
+			expect:  &clabtypes.ExpectScripts{Before: []*clabtypes.ExpectStep{{Send: "y", Timeout: "soon"}}},
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cs := &NodeConfig{TargetNode: &clabtypes.NodeConfig{
+				ShortName: "r1",
+				Config:    &clabtypes.ConfigDispatcher{Expect: tc.expect},
+			}}
+
+			before, after, err := expectScripts(cs)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("expectScripts() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if len(before) != tc.before || len(after) != tc.after {
+				t.Errorf("expectScripts() = %d, %d steps, want %d, %d", len(before), len(after), tc.before, tc.after)
+			}
+		})
+	}
+}
+
+func TestExpectStepsTimeout(t *testing.T) {
+	steps, err := expectSteps([]*clabtypes.ExpectStep{{Expect: "login:", Timeout: "1m"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if steps[0].Timeout != time.Minute || steps[0].Expect.String() != "login:" {
+		t.Errorf("unexpected step %+v", steps[0])
+	}
+}
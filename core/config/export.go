@@ -0,0 +1,161 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	clabcore "github.com/srl-labs/containerlab/core"
+)
+
+// ExportFormatBatfish is the Batfish snapshot export format.
+const ExportFormatBatfish = "batfish"
+
+// savedConfigPaths maps the kinds to the location of the saved configuration in the node's lab dir.
+// Nodes of kinds not listed here use the vrnetlab config/startup-config.cfg location.
+var savedConfigPaths = map[string]string{
+	"ceos":              "flash/startup-config",
+	"arista_ceos":       "flash/startup-config",
+	"srl":               "config/config.json",
+	"nokia_srlinux":     "config/config.json",
+	"crpd":              "config/juniper.conf",
+	"juniper_crpd":      "config/juniper.conf",
+	"xrd":               "first-boot.cfg",
+	"cisco_xrd":         "first-boot.cfg",
+	"vyosnetworks_vyos": "config/config.boot",
+	"vr-sros":           "tftpboot/config.txt",
+	"nokia_sros":        "tftpboot/config.txt",
+}
+
+const defaultSavedConfigPath = "config/startup-config.cfg"
+
+// batfishInterface is an interface reference in the Batfish layer1 topology.
+type batfishInterface struct {
+	Hostname      string `json:"hostname"`
+	InterfaceName string `json:"interfaceName"`
+}
+
+type batfishEdge struct {
+	Node1 batfishInterface `json:"node1"`
+	Node2 batfishInterface `json:"node2"`
+}
+
+// batfishLayer1Topology is the content of the batfish/layer1_topology.json snapshot file.
+type batfishLayer1Topology struct {
+	Edges []batfishEdge `json:"edges"`
+}
+
+// ExportBatfish writes a Batfish snapshot to dir. The snapshot contains
+// a configs/<node>.cfg file per node with the rendered configuration or,
+// when saved is true, the last saved configuration found in the node's lab dir.
+// The links between the exported nodes are written to batfish/layer1_topology.json.
+func ExportBatfish(c *clabcore.CLab, allConfig map[string]*NodeConfig, dir string, saved bool) error {
+	configsDir := filepath.Join(dir, "configs")
+	if err := os.MkdirAll(configsDir, 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	exported := map[string]struct{}{}
+
+	names := make([]string, 0, len(allConfig))
+	for n := range allConfig {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, n := range names {
+		cs := allConfig[n]
+
+		var cfg []byte
+		if saved {
+			p := defaultSavedConfigPath
+			if kp, ok := savedConfigPaths[cs.TargetNode.Kind]; ok {
+				p = kp
+			}
+			p = filepath.Join(cs.TargetNode.LabDir, p)
+
+			var err error
+			cfg, err = os.ReadFile(p)
+			if err != nil {
+				log.Warnf("%s: no saved config found, skipping: %v", n, err)
+				continue
+			}
+		} else {
+			if len(cs.Data) == 0 {
+				log.Warnf("%s: no rendered config, skipping", n)
+				continue
+			}
+			cfg = []byte(strings.Join(cs.Data, "\n\n") + "\n")
+		}
+
+		err := os.WriteFile(filepath.Join(configsDir, n+".cfg"), cfg, 0o644) // skipcq: GSC-G306
+		if err != nil {
+			return err
+		}
+
+		exported[n] = struct{}{}
+	}
+
+	if len(exported) == 0 {
+		return fmt.Errorf("no node configs to export")
+	}
+
+	topo := batfishLayer1Topology{Edges: []batfishEdge{}}
+
+	linkIdx := make([]int, 0, len(c.Links))
+	for i := range c.Links {
+		linkIdx = append(linkIdx, i)
+	}
+	sort.Ints(linkIdx)
+
+	for _, i := range linkIdx {
+		eps := c.Links[i].GetEndpoints()
+		if len(eps) != 2 {
+			continue
+		}
+
+		a := batfishInterface{
+			Hostname:      eps[0].GetNode().GetShortName(),
+			InterfaceName: eps[0].GetIfaceDisplayName(),
+		}
+		b := batfishInterface{
+			Hostname:      eps[1].GetNode().GetShortName(),
+			InterfaceName: eps[1].GetIfaceDisplayName(),
+		}
+
+		// links to the host or nodes without configs are not part of the snapshot
+		_, okA := exported[a.Hostname]
+		_, okB := exported[b.Hostname]
+		if !okA || !okB {
+			continue
+		}
+
+		// Batfish layer1 edges are directional
+		topo.Edges = append(topo.Edges,
+			batfishEdge{Node1: a, Node2: b},
+			batfishEdge{Node1: b, Node2: a})
+	}
+
+	l1, err := json.MarshalIndent(topo, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	bfDir := filepath.Join(dir, "batfish")
+	if err := os.MkdirAll(bfDir, 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(bfDir, "layer1_topology.json"), l1, 0o644) // skipcq: GSC-G306
+	if err != nil {
+		return err
+	}
+
+	log.Info("Exported Batfish snapshot", "path", dir, "nodes", len(exported), "edges", len(topo.Edges))
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"encoding/json"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabcore "github.com/srl-labs/containerlab/core"
+)
+
+const exportTopo = `name: bf
+topology:
+  nodes:
+    srl1:
+      kind: nokia_srlinux
+    srl2:
+      kind: nokia_srlinux
+    client:
+      kind: linux
+  links:
+    - endpoints: ["srl1:e1-1", "srl2:e1-1"]
+    - endpoints: ["srl1:e1-2", "client:eth1"]
+`
+
+// exportLab returns the lab of the export topology and the config of its nodes,
+// rendered for the SR Linux nodes only.
+func exportLab(t *testing.T) (*clabcore.CLab, map[string]*NodeConfig) {
+	t.Helper()
+
+	dir := t.TempDir()
+	topo := filepath.Join(dir, "bf.clab.yml")
+	if err := os.WriteFile(topo, []byte(exportTopo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := clabcore.NewContainerLab(clabcore.WithTopoPath(topo, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ResolveLinks(); err != nil {
+		t.Fatal(err)
+	}
+
+	allConfig, err := PrepareVars(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	allConfig["srl1"].Data = []string{"set / system name host-name srl1", "set / interface ethernet-1/1 admin-state enable"}
+	allConfig["srl2"].Data = []string{"set / system name host-name srl2"}
+
+	return c, allConfig
+}
+
+// snapshotFiles returns the files of the snapshot directory relative to it.
+func snapshotFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	var files []string
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, p)
+		files = append(files, rel)
+		return err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sort.Strings(files)
+
+	return files
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b)
+}
+
+func TestExportBatfish(t *testing.T) {
+	c, allConfig := exportLab(t)
+	dir := t.TempDir()
+
+	if err := ExportBatfish(c, allConfig, dir, false); err != nil {
+		t.Fatal(err)
+	}
+
+	// the client has no rendered config, it is left out with its link
+	want := []string{"batfish/layer1_topology.json", "configs/srl1.cfg", "configs/srl2.cfg"}
+	if d := cmp.Diff(want, snapshotFiles(t, dir)); d != "" {
+		t.Errorf("snapshot files mismatch (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff("set / system name host-name srl1\n\nset / interface ethernet-1/1 admin-state enable\n",
+		readFile(t, filepath.Join(dir, "configs", "srl1.cfg"))); d != "" {
+		t.Errorf("srl1 config mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff("set / system name host-name srl2\n",
+		readFile(t, filepath.Join(dir, "configs", "srl2.cfg"))); d != "" {
+		t.Errorf("srl2 config mismatch (-want +got):\n%s", d)
+	}
+
+	var topo batfishLayer1Topology
+	if err := json.Unmarshal([]byte(readFile(t, filepath.Join(dir, "batfish", "layer1_topology.json"))), &topo); err != nil {
+		t.Fatal(err)
+	}
+
+	srl1 := batfishInterface{Hostname: "srl1", InterfaceName: "ethernet-1/1"}
+	srl2 := batfishInterface{Hostname: "srl2", InterfaceName: "ethernet-1/1"}
+	wantTopo := batfishLayer1Topology{Edges: []batfishEdge{
+		{Node1: srl1, Node2: srl2},
+		{Node1: srl2, Node2: srl1},
+	}}
+	if d := cmp.Diff(wantTopo, topo); d != "" {
+		t.Errorf("layer1 topology mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestExportBatfishSaved(t *testing.T) {
+	c, allConfig := exportLab(t)
+	dir := t.TempDir()
+
+	// only srl1 has a saved config in its lab dir
+	saved := filepath.Join(allConfig["srl1"].TargetNode.LabDir, savedConfigPaths["nokia_srlinux"])
+	if err := os.MkdirAll(filepath.Dir(saved), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(saved, []byte(`{"srl_nokia-system:system": {}}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ExportBatfish(c, allConfig, dir, true); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"batfish/layer1_topology.json", "configs/srl1.cfg"}
+	if d := cmp.Diff(want, snapshotFiles(t, dir)); d != "" {
+		t.Errorf("snapshot files mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(`{"srl_nokia-system:system": {}}`, readFile(t, filepath.Join(dir, "configs", "srl1.cfg"))); d != "" {
+		t.Errorf("srl1 config mismatch (-want +got):\n%s", d)
+	}
+	if got := readFile(t, filepath.Join(dir, "batfish", "layer1_topology.json")); got != "{\n  \"edges\": []\n}" {
+		t.Errorf("want no edges with a single exported node, got %s", got)
+	}
+}
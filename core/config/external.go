@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"sort"
+
+	"github.com/charmbracelet/log"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// externalTarget returns the target node and the credentials of an external node of the topology.
+// The node is reached at its address, the credentials default to the credentials of its kind.
+func externalTarget(c *clabcore.CLab, name string, ext *clabtypes.ExternalNode) (*clabtypes.NodeConfig, []string) {
+	node := &clabtypes.NodeConfig{
+		ShortName: name,
+		LongName:  ext.Address,
+		Kind:      ext.Kind,
+		Labels:    ext.Labels,
+		Config:    ext.Config,
+		LabDir:    filepath.Join(c.TopoPaths.TopologyLabDir(), name),
+	}
+
+	if a, err := netip.ParseAddr(ext.Address); err == nil {
+		if a.Is4() {
+			node.MgmtIPv4Address = a.String()
+		} else {
+			node.MgmtIPv6Address = a.String()
+		}
+	}
+
+	var creds []string
+	if k := c.Reg.Kind(ext.Kind); k != nil {
+		creds = k.GetCredentials().Slice()
+	}
+	if len(creds) < 2 {
+		creds = make([]string, 2)
+	}
+	if ext.Username != "" {
+		creds[0] = ext.Username
+	}
+	if ext.Password != "" {
+		creds[1] = ext.Password
+	}
+
+	return node, creds
+}
+
+// prepareExternal adds the external nodes of the topology to the lab nodes.
+// External nodes have no links, they are configured with the vars set in the topology.
+func prepareExternal(c *clabcore.CLab, res map[string]*NodeConfig) error {
+	names := make([]string, 0, len(c.Config.Topology.External))
+	for name := range c.Config.Topology.External {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		ext := c.Config.Topology.External[name]
+		if ext == nil || ext.Address == "" {
+			return fmt.Errorf("external node %s has no address", name)
+		}
+		if _, ok := res[name]; ok {
+			return fmt.Errorf("external node %s has the name of a lab node", name)
+		}
+		if _, ok := c.Config.Topology.Nodes[name]; ok {
+			return fmt.Errorf("external node %s has the name of a lab node", name)
+		}
+
+		node, creds := externalTarget(c, name, ext)
+
+		vars := map[string]interface{}{
+			vkNodeName:       name,
+			vkKind:           node.Kind,
+			vkManagementIPv4: node.MgmtIPv4Address,
+			vkManagementIPv6: node.MgmtIPv6Address,
+			vkType:           "",
+			vkExternal:       true,
+		}
+
+		for key, val := range ext.Config.GetVars() {
+			if isReservedVar(key) {
+				log.Warnf("the variable %s on %s will be ignored, it is reserved", key, name)
+				continue
+			}
+			vars[key] = val
+		}
+
+		vars[vkLinks] = []interface{}{}
+
+		if _, ok := vars[vkRole]; !ok {
+			vars[vkRole] = node.Kind
+		}
+
+		res[name] = &NodeConfig{
+			TargetNode:  node,
+			Vars:        vars,
+			Credentials: creds,
+		}
+	}
+
+	return nil
+}
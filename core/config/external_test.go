@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabcore "github.com/srl-labs/containerlab/core"
+)
+
+const externalTopo = `name: ext
+topology:
+  nodes:
+    srl1:
+      kind: nokia_srlinux
+  external:
+    tor1:
+      kind: nokia_srlinux
+      address: 192.0.2.10
+      password: secret
+      config:
+        vars:
+          asn: 65001
+          clab_external: false
+`
+
+func TestPrepareExternal(t *testing.T) {
+	dir := t.TempDir()
+	topo := filepath.Join(dir, "ext.clab.yml")
+	if err := os.WriteFile(topo, []byte(externalTopo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := clabcore.NewContainerLab(clabcore.WithTopoPath(topo, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := PrepareVars(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tor := res["tor1"]
+	if tor == nil {
+		t.Fatalf("external node not prepared, got nodes %v", res)
+	}
+
+	if tor.TargetNode.LongName != "192.0.2.10" || tor.TargetNode.MgmtIPv4Address != "192.0.2.10" {
+		t.Errorf("unexpected target %+v", tor.TargetNode)
+	}
+	// the username defaults to the kind credentials
+	if d := cmp.Diff([]string{"admin", "secret"}, tor.Credentials); d != "" {
+		t.Errorf("credentials mismatch (-want +got):\n%s", d)
+	}
+
+	if tor.Vars[vkExternal] != true || tor.Vars["asn"] != 65001 || tor.Vars[vkRole] != "nokia_srlinux" {
+		t.Errorf("unexpected vars %v", tor.Vars)
+	}
+	if _, ok := res["srl1"].Vars[vkNodes].(Dict)["tor1"]; !ok {
+		t.Errorf("external node missing from %s of the lab nodes", vkNodes)
+	}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/log"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// Keys of the facts added by the config engine to the facts recorded on deploy.
+const (
+	factLongName = "long_name"
+	factKind     = "kind"
+	factMgmtIPv4 = "mgmt_ipv4"
+	factMgmtIPv6 = "mgmt_ipv6"
+	factLinkIPs  = "link_ips" // link IPs of the node keyed by port
+)
+
+// loadFacts returns the runtime facts of the nodes recorded in the facts file on deploy.
+// A lab that was not deployed has no facts.
+func loadFacts(path string) (Dict, error) {
+	res := make(Dict)
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		log.Debugf("no lab facts found in %s", path)
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var facts map[string]map[string]interface{}
+	if err := json.Unmarshal(b, &facts); err != nil {
+		return nil, fmt.Errorf("invalid lab facts file %s: %w", path, err)
+	}
+
+	for n, f := range facts {
+		res[n] = Dict(f)
+	}
+
+	return res, nil
+}
+
+// addNodeFacts adds the facts known to the config engine to the facts of the nodes,
+// the nodes missing from the facts file get the facts of their node config.
+func addNodeFacts(facts Dict, res map[string]*NodeConfig) {
+	for name, nc := range res {
+		f, ok := facts[name].(Dict)
+		if !ok {
+			f = Dict{
+				factLongName: nc.TargetNode.LongName,
+				factKind:     nc.TargetNode.Kind,
+				factMgmtIPv4: nc.TargetNode.MgmtIPv4Address,
+				factMgmtIPv6: nc.TargetNode.MgmtIPv6Address,
+			}
+			facts[name] = f
+		}
+
+		linkIPs := make(Dict)
+		for _, l := range nc.Vars[vkLinks].([]interface{}) {
+			vars, ok := l.(Dict)
+			if !ok {
+				continue
+			}
+			if ip, ok := vars[vkLinkIP]; ok {
+				linkIPs[fmt.Sprintf("%v", vars[vkPort])] = ip
+			}
+		}
+		f[factLinkIPs] = linkIPs
+	}
+}
+
+// verifyChecks returns the verification checks of the node with the templates in the check
+// fields rendered with the node's variables, e.g. {{ .clab_facts.srl2.mgmt_ipv4 }}.
+func (c *NodeConfig) verifyChecks() ([]*clabtypes.VerifyCheck, error) {
+	checks := c.TargetNode.Config.GetVerify()
+	res := make([]*clabtypes.VerifyCheck, 0, len(checks))
+
+	for _, chk := range checks {
+		r := *chk
+		for _, f := range []*string{&r.Path, &r.Value, &r.Command} {
+			v, err := c.renderCheck(*f)
+			if err != nil {
+				return nil, err
+			}
+			*f = v
+		}
+		res = append(res, &r)
+	}
+
+	return res, nil
+}
+
+// configTests returns the tests of the node with the templates in the command
+// and the expected values rendered with the node's variables.
+func (c *NodeConfig) configTests() ([]*clabtypes.ConfigTest, error) {
+	tests := c.TargetNode.Config.GetTests()
+	res := make([]*clabtypes.ConfigTest, 0, len(tests))
+
+	for _, tst := range tests {
+		r := *tst
+		e := clabtypes.TestExpect{}
+		if r.Expect != nil {
+			e = *r.Expect
+		}
+		r.Expect = &e
+		for _, f := range []*string{&r.Command, &e.Contains, &e.Regex, &e.Value} {
+			v, err := c.renderCheck(*f)
+			if err != nil {
+				return nil, err
+			}
+			*f = v
+		}
+		res = append(res, &r)
+	}
+
+	return res, nil
+}
+
+// renderCheck renders the templates in a field of a check with the node's variables.
+func (c *NodeConfig) renderCheck(s string) (string, error) {
+	if !strings.Contains(s, "{{") {
+		return s, nil
+	}
+	t, err := template.New("check").Option("missingkey=error").Parse(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid verification check %q: %w", s, err)
+	}
+	var buf strings.Builder
+	if err := t.Execute(&buf, c.Vars); err != nil {
+		return "", fmt.Errorf("invalid verification check %q: %w", s, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,82 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestFacts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "facts.json")
+
+	facts, err := loadFacts(path)
+	if err != nil || len(facts) != 0 {
+		t.Fatalf("expected no facts without a facts file, got %v, %v", facts, err)
+	}
+
+	err = os.WriteFile(path, []byte(`{"srl1": {"long_name": "clab-t-srl1", "mgmt_ipv4": "172.20.20.2"}}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	facts, err = loadFacts(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := map[string]*NodeConfig{
+		"srl1": {
+			TargetNode: &clabtypes.NodeConfig{
+				ShortName: "srl1",
+				Config: &clabtypes.ConfigDispatcher{
+					Verify: []*clabtypes.VerifyCheck{
+						{Path: "/system/name/host-name", Value: "srl1"},
+						{Path: "/interface[name=ethernet-1/1]/ipv4/address", Value: `{{ index .clab_facts.srl1.link_ips "e1-1" }}`},
+						{Path: "/bgp/neighbor[address={{ .clab_facts.tor1.mgmt_ipv4 }}]/state", Value: "up"},
+					},
+				},
+			},
+			Vars: map[string]interface{}{
+				vkLinks: []interface{}{Dict{vkPort: "e1-1", vkLinkIP: "10.0.0.1/31"}},
+			},
+		},
+		"tor1": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "tor1", LongName: "192.0.2.10", MgmtIPv4Address: "192.0.2.10"},
+			Vars:       map[string]interface{}{vkLinks: []interface{}{}},
+		},
+	}
+
+	addNodeFacts(facts, res)
+	for _, nc := range res {
+		nc.Vars[vkFacts] = facts
+	}
+
+	if got := facts["srl1"].(Dict)[factMgmtIPv4]; got != "172.20.20.2" {
+		t.Errorf("deploy facts not kept, got mgmt_ipv4 %v", got)
+	}
+
+	checks, err := res["srl1"].verifyChecks()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"srl1", "10.0.0.1/31", "/bgp/neighbor[address=192.0.2.10]/state"}
+	got := []string{checks[0].Value, checks[1].Value, checks[2].Path}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("check %d: want %q, got %q", i, want[i], got[i])
+		}
+	}
+
+	// the topology checks are not modified
+	if res["srl1"].TargetNode.Config.Verify[2].Path == checks[2].Path {
+		t.Error("verification check of the topology was modified")
+	}
+
+	res["srl1"].TargetNode.Config.Verify[0].Value = "{{ .clab_facts.nope.mgmt_ipv4 }}"
+	if _, err := res["srl1"].verifyChecks(); err == nil {
+		t.Error("expected an error for a missing fact")
+	}
+}
@@ -0,0 +1,46 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// sftpClient connects to the SFTP subsystem of the node with its SSH credentials and dialer.
+func sftpClient(ctx context.Context, cs *NodeConfig) (*transport.SFTPClient, error) {
+	creds, err := cs.sshCredentials()
+	if err != nil {
+		return nil, err
+	}
+	if len(creds) < 2 {
+		return nil, fmt.Errorf("SSH credentials for node %s of type %s not found, cannot copy files",
+			cs.TargetNode.ShortName, cs.TargetNode.Kind)
+	}
+
+	return transport.NewSFTPClient(ctx, transport.NodeHost(cs.TargetNode), creds[0], creds[1], cs.Dialer)
+}
+
+// PushFile writes the content read from r to the file at the path of the node over SFTP.
+func PushFile(ctx context.Context, cs *NodeConfig, p string, r io.Reader, mode fs.FileMode) error {
+	c, err := sftpClient(ctx, cs)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return c.Put(p, r, mode)
+}
+
+// PullFile writes the content of the file at the path of the node to w over SFTP.
+func PullFile(ctx context.Context, cs *NodeConfig, p string, w io.Writer) error {
+	c, err := sftpClient(ctx, cs)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return c.Get(p, w)
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// gnmiGetTimeout limits the time waiting for the configuration retrieved with gNMI.
+const gnmiGetTimeout = 30 * time.Second
+
+// gnmiConfigPaths are the paths of the configuration retrieved with gNMI by kind, the root by default.
+var gnmiConfigPaths = map[string]string{ //nolint:gochecknoglobals
+	"vr-sros":     "/configure",
+	"nokia_sros":  "/configure",
+	"nokia_srsim": "/configure",
+	"srsim":       "/configure",
+}
+
+// sendGNMI applies the rendered config of the node with gNMI. The operations of all the snippets
+// are pushed with a single Set request, see transport.ParseGNMISet for the snippet format.
+func sendGNMI(ctx context.Context, cs *NodeConfig) error {
+	var ops []*transport.GNMISetOp
+	for i, d := range cs.Data {
+		o, err := transport.ParseGNMISet(d)
+		if err != nil {
+			return fmt.Errorf("invalid gNMI snippet %s: %w", cs.Info[i], err)
+		}
+		ops = append(ops, o...)
+	}
+
+	if len(ops) == 0 {
+		return nil
+	}
+
+	tx, err := newGNMITransport(cs)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Connect(); err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	err = tx.Set(ctx, ops)
+	if err != nil && ctx.Err() != nil {
+		return fmt.Errorf("config not applied in time: %w", ctx.Err())
+	}
+
+	return err
+}
+
+// listKeys are the fields identifying the elements of a JSON list in the normalized configuration,
+// the first field found in an element is used, the position of the element otherwise.
+var listKeys = []string{"name", "id", "index", "address", "prefix", "ip-prefix"} //nolint:gochecknoglobals
+
+// fetchRunningGNMI returns the configuration of the node retrieved with a gNMI Get of the CONFIG data,
+// normalized to a sorted list of path = value lines.
+func fetchRunningGNMI(cs *NodeConfig) (string, error) {
+	p := "/"
+	if kp, ok := gnmiConfigPaths[cs.TargetNode.Kind]; ok {
+		p = kp
+	}
+	path, err := transport.ParseGNMIPath(p)
+	if err != nil {
+		return "", err
+	}
+
+	tx, err := newGNMITransport(cs)
+	if err != nil {
+		return "", err
+	}
+	if err := tx.Connect(); err != nil {
+		return "", err
+	}
+	defer tx.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), gnmiGetTimeout)
+	defer cancel()
+
+	updates, err := tx.Get(ctx, []*transport.GNMIPath{path}, transport.GNMIDataTypeConfig)
+	if err != nil {
+		return "", err
+	}
+
+	return normalizeGNMIConfig(updates), nil
+}
+
+// normalizeGNMIConfig flattens the JSON values of the updates into path = value lines,
+// sorted so that the configurations retrieved by different requests compare line by line.
+func normalizeGNMIConfig(updates []*transport.GNMIUpdate) string {
+	var lines []string
+	for _, u := range updates {
+		prefix := strings.TrimSuffix(u.Path.String(), "/")
+
+		var v any
+		if err := json.Unmarshal([]byte(u.Value), &v); err != nil {
+			lines = append(lines, fmt.Sprintf("%s = %s", prefix, u.Value))
+			continue
+		}
+		lines = flattenJSON(prefix, v, lines)
+	}
+	sort.Strings(lines)
+
+	return strings.Join(lines, "\n")
+}
+
+// flattenJSON appends a path = value line for every leaf of the JSON value v under the prefix.
+func flattenJSON(prefix string, v any, lines []string) []string {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			lines = flattenJSON(prefix+"/"+k, e, lines)
+		}
+	case []any:
+		scalars := true
+		for _, e := range v {
+			if _, ok := e.(map[string]any); ok {
+				scalars = false
+			}
+		}
+		if scalars {
+			// a leaf-list, its order is kept in a single line
+			return append(lines, fmt.Sprintf("%s = %s", prefix, jsonValueString(v)))
+		}
+		for i, e := range v {
+			lines = flattenJSON(prefix+listElemKey(e, i), e, lines)
+		}
+	default:
+		lines = append(lines, fmt.Sprintf("%s = %s", prefix, jsonValueString(v)))
+	}
+	return lines
+}
+
+// listElemKey returns the key of a JSON list element in the path, e.g. [name=ethernet-1/1].
+func listElemKey(e any, i int) string {
+	if m, ok := e.(map[string]any); ok {
+		for _, k := range listKeys {
+			if kv, ok := m[k]; ok {
+				if _, nested := kv.(map[string]any); !nested {
+					return fmt.Sprintf("[%s=%s]", k, jsonValueString(kv))
+				}
+			}
+		}
+	}
+	return fmt.Sprintf("[%d]", i)
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestNormalizeGNMIConfig(t *testing.T) {
+	path, _ := transport.ParseGNMIPath("/")
+	updates := []*transport.GNMIUpdate{{
+		Path: path,
+		Value: `{"system":{"name":{"host-name":"srl1"}},
+			"interface":[{"name":"ethernet-1/2","admin-state":"enable"},{"name":"ethernet-1/1","admin-state":"disable"}],
+			"dns":{"server-list":["1.1.1.1","8.8.8.8"]}}`,
+	}}
+
+	want := `/dns/server-list = ["1.1.1.1","8.8.8.8"]
+/interface[name=ethernet-1/1]/admin-state = disable
+/interface[name=ethernet-1/1]/name = ethernet-1/1
+/interface[name=ethernet-1/2]/admin-state = enable
+/interface[name=ethernet-1/2]/name = ethernet-1/2
+/system/name/host-name = srl1`
+
+	if d := cmp.Diff(want, normalizeGNMIConfig(updates)); d != "" {
+		t.Errorf("normalized config mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestAppliedTransport(t *testing.T) {
+	tests := map[string]string{
+		"# transport: gnmi\n/system/name/host-name = srl1": transportGNMI,
+		"set / system name host-name srl1":                 transportSSH,
+		"":                                                 transportSSH,
+	}
+	for applied, want := range tests {
+		if got := appliedTransport(applied); got != want {
+			t.Errorf("appliedTransport(%q) = %s, want %s", applied, got, want)
+		}
+	}
+}
+
+func TestSendGNMIInvalidSnippet(t *testing.T) {
+	cs := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "srl1", Kind: "nokia_srlinux"},
+		Data:       []string{"/system/name/host-name = srl1", "delete /system/name = srl1"},
+		Info:       []string{"base", "system"},
+	}
+
+	err := sendGNMI(context.Background(), cs)
+	if err == nil || !strings.Contains(err.Error(), "invalid gNMI snippet system") {
+		t.Errorf("expected an invalid snippet error, got %v", err)
+	}
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// newGNMITransport creates the gNMI transport for the node using the node's credentials.
+func newGNMITransport(cs *NodeConfig, options ...transport.GNMITransportOption) (*transport.GNMITransport, error) {
+	opts := []transport.GNMITransportOption{transport.WithGNMIDebug(cs.DebugCount)}
+	if len(cs.Credentials) > 1 {
+		password := cs.Credentials[1]
+		if pw, ok := cs.TargetNode.Labels[passwordLabel]; ok && !cs.rotated {
+			password = pw
+		}
+		opts = append(opts, transport.WithGNMICredentials(cs.Credentials[0], password))
+	}
+	if cs.Dialer != nil {
+		opts = append(opts, transport.WithGNMIDialer(cs.Dialer))
+	}
+
+	return transport.NewGNMITransport(cs.TargetNode, append(opts, options...)...)
+}
+
+// ConnectGNOI connects to the gRPC server of the node for the gNOI operations,
+// using the node's credentials and the connection labels of the gNMI transport.
+// When caPEM is set, e.g. with the lab CA certificate, the server certificate is verified with it.
+func ConnectGNOI(cs *NodeConfig, caPEM []byte) (*transport.GNMITransport, error) {
+	var opts []transport.GNMITransportOption
+	if len(caPEM) > 0 {
+		opts = append(opts, transport.WithGNMIRootCA(caPEM))
+	}
+
+	tx, err := newGNMITransport(cs, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Connect(); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
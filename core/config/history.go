@@ -0,0 +1,186 @@
+package config
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	clabgit "github.com/srl-labs/containerlab/git"
+)
+
+// historyFileName is the file in the lab dir recording the config runs, one JSON entry per line.
+const historyFileName = "config-history.jsonl"
+
+// Node statuses of a config run.
+const (
+	HistoryStatusOK      = "ok"
+	HistoryStatusFailed  = "failed"
+	HistoryStatusSkipped = "skipped"
+	// HistoryStatusRolledBack is the status of the nodes configured and reverted
+	// to their checkpoint after the run failed on other nodes
+	HistoryStatusRolledBack = "rolled-back"
+)
+
+// HistoryEntry is a config run recorded in the lab history.
+type HistoryEntry struct {
+	ID     int       `json:"id"`
+	Time   time.Time `json:"time"`
+	User   string    `json:"user"`
+	Action string    `json:"action"`
+	// TopologyHash is the hash of the topology file the config was rendered from
+	TopologyHash string `json:"topology-hash"`
+	// TemplatesHash is the hash of the template files found in the template paths
+	TemplatesHash string `json:"templates-hash"`
+	// Git is the git revision of the topology and the templates, when they are in a git repository
+	Git   *clabgit.Revision `json:"git,omitempty"`
+	Nodes []*HistoryNode    `json:"nodes"`
+}
+
+// HistoryNode is the result of a node in a config run.
+type HistoryNode struct {
+	Node      string   `json:"node"`
+	Status    string   `json:"status"`
+	Message   string   `json:"message,omitempty"`
+	Templates []string `json:"templates,omitempty"`
+	// Transport is the config transport used for the node, when the config was sent
+	Transport string `json:"transport,omitempty"`
+	// ConfigHash is the hash of the config rendered for the node
+	ConfigHash string `json:"config-hash,omitempty"`
+	// Banner is the login banner of the node, kept apart from the output of the config commands
+	Banner string `json:"banner,omitempty"`
+}
+
+// Failed returns the number of nodes that failed in the run.
+func (e *HistoryEntry) Failed() int {
+	n := 0
+	for _, r := range e.Nodes {
+		if r.Status == HistoryStatusFailed {
+			n++
+		}
+	}
+	return n
+}
+
+// RolledBack marks the nodes configured in the run as reverted to their checkpoint.
+func (e *HistoryEntry) RolledBack(message string) {
+	for _, r := range e.Nodes {
+		if r.Status == HistoryStatusOK {
+			r.Status = HistoryStatusRolledBack
+			r.Message = message
+		}
+	}
+}
+
+// HistoryPath returns the path of the config history file of the lab.
+func HistoryPath(labDir string) string {
+	return filepath.Join(labDir, historyFileName)
+}
+
+// AppendHistory appends the entry to the history file, numbering it after the last entry.
+func AppendHistory(path string, e *HistoryEntry) error {
+	entries, err := ReadHistory(path)
+	if err != nil {
+		return err
+	}
+
+	e.ID = 1
+	if len(entries) > 0 {
+		e.ID = entries[len(entries)-1].ID + 1
+	}
+
+	sort.Slice(e.Nodes, func(i, j int) bool { return e.Nodes[i].Node < e.Nodes[j].Node })
+
+	b, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644) // skipcq: GSC-G302
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(b, '\n'))
+
+	return err
+}
+
+// ReadHistory returns the entries of the history file, oldest first.
+// A missing history file has no entries.
+func ReadHistory(path string) ([]*HistoryEntry, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []*HistoryEntry
+
+	s := bufio.NewScanner(f)
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; s.Scan(); line++ {
+		if len(s.Bytes()) == 0 {
+			continue
+		}
+		e := &HistoryEntry{}
+		if err := json.Unmarshal(s.Bytes(), e); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid history entry: %w", path, line, err)
+		}
+		res = append(res, e)
+	}
+
+	return res, s.Err()
+}
+
+// FileHash returns the hash of the file content, empty if the file can't be read.
+func FileHash(path string) string {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])
+}
+
+// TemplatesHash returns the hash of the names and contents of the template files
+// found in the template paths.
+func TemplatesHash(paths []string) string {
+	h := sha256.New()
+
+	for _, v := range paths {
+		_, p := splitTemplatePath(v)
+
+		var dir fs.FS = embeddedTemplates
+		if p != "@" {
+			dir = os.DirFS(p)
+		}
+
+		for _, pattern := range []string{"*__*.tmpl", "*__*.j2"} {
+			files, err := fs.Glob(dir, pattern)
+			if err != nil {
+				continue
+			}
+			for _, fn := range files {
+				b, err := fs.ReadFile(dir, fn)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(h, "%s/%s\n", v, fn)
+				h.Write(b)
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	path := HistoryPath(t.TempDir())
+
+	entries, err := ReadHistory(path)
+	if err != nil || len(entries) != 0 {
+		t.Fatalf("ReadHistory() of a missing file = %v, %v, want no entries", entries, err)
+	}
+
+	for _, action := range []string{"commit", "verify"} {
+		err := AppendHistory(path, &HistoryEntry{
+			Action: action,
+			Nodes: []*HistoryNode{
+				{Node: "srl2", Status: HistoryStatusFailed},
+				{Node: "srl1", Status: HistoryStatusOK},
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err = ReadHistory(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 || entries[0].ID != 1 || entries[1].ID != 2 || entries[1].Action != "verify" {
+		t.Fatalf("unexpected history entries: %+v", entries)
+	}
+	if entries[0].Nodes[0].Node != "srl1" || entries[0].Failed() != 1 {
+		t.Errorf("unexpected nodes of the run: %+v", entries[0].Nodes)
+	}
+
+	if FileHash(filepath.Join(t.TempDir(), "missing")) != "" {
+		t.Error("expected an empty hash of a missing file")
+	}
+}
+
+func TestHistoryRolledBack(t *testing.T) {
+	e := &HistoryEntry{Nodes: []*HistoryNode{
+		{Node: "srl1", Status: HistoryStatusOK},
+		{Node: "srl2", Status: HistoryStatusFailed, Message: "verification failed"},
+		{Node: "srl3", Status: HistoryStatusSkipped},
+	}}
+
+	e.RolledBack("rolled back after the failure of srl2")
+
+	want := []string{HistoryStatusRolledBack, HistoryStatusFailed, HistoryStatusSkipped}
+	for i, n := range e.Nodes {
+		if n.Status != want[i] {
+			t.Errorf("%s: status %q, want %q", n.Node, n.Status, want[i])
+		}
+	}
+	if e.Nodes[1].Message != "verification failed" || e.Failed() != 1 {
+		t.Errorf("unexpected failed node %+v", e.Nodes[1])
+	}
+}
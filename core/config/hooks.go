@@ -0,0 +1,226 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Stages of the config plugin hooks.
+const (
+	// HookPreRender runs before the templates of the node are rendered, the hook can set node variables
+	HookPreRender = "pre-render"
+	// HookPostRender runs after the templates of the node are rendered, the hook can change the snippets
+	HookPostRender = "post-render"
+	// HookPrePush runs before the config is sent to the node, the hook can change the snippets
+	// or stop the config of the node by failing
+	HookPrePush = "pre-push"
+)
+
+// hookEngine is the engine of the snippets added by the hooks.
+const hookEngine = "hook"
+
+// HookStages are the stages of the config plugin hooks in the order they run.
+var HookStages = []string{HookPreRender, HookPostRender, HookPrePush} //nolint:gochecknoglobals
+
+// Hook is an external program run at a stage of the config of the nodes.
+// The program receives the node as a JSON hookMessage on stdin and may reply with a message
+// on stdout. The variables of the reply are merged over the node variables before rendering,
+// the snippets of the reply replace the rendered snippets. An empty reply changes nothing,
+// and a hook exiting with an error fails the config of the node.
+type Hook struct {
+	Stage   string
+	Command []string
+}
+
+// Hooks are the config plugin hooks, run in order at their stages.
+type Hooks []*Hook
+
+// hookMessage is the node passed to the hooks and their reply.
+type hookMessage struct {
+	Stage    string                 `json:"stage,omitempty"`
+	Lab      string                 `json:"lab,omitempty"`
+	Node     string                 `json:"node,omitempty"`
+	Kind     string                 `json:"kind,omitempty"`
+	Vars     map[string]interface{} `json:"vars,omitempty"`
+	Snippets []*hookSnippet         `json:"snippets,omitempty"`
+}
+
+// hookSnippet is a rendered snippet of the node, the template is the name of the snippet.
+type hookSnippet struct {
+	Template string `json:"template"`
+	Config   string `json:"config"`
+}
+
+// ParseHooks parses the stage=command hook definitions.
+func ParseHooks(defs []string) (Hooks, error) {
+	res := make(Hooks, 0, len(defs))
+
+	for _, d := range defs {
+		stage, command, _ := strings.Cut(d, "=")
+		args := strings.Fields(command)
+
+		valid := false
+		for _, s := range HookStages {
+			valid = valid || s == stage
+		}
+		if !valid || len(args) == 0 {
+			return nil, fmt.Errorf("invalid hook %q, expected <stage>=<command> with the stage one of %s",
+				d, strings.Join(HookStages, ", "))
+		}
+
+		res = append(res, &Hook{Stage: stage, Command: args})
+	}
+
+	return res, nil
+}
+
+// Run runs the hooks of the stage on the node, each hook getting the node changed by the previous one.
+func (h Hooks) Run(ctx context.Context, stage string, nc *NodeConfig) error {
+	for _, hook := range h {
+		if hook.Stage != stage {
+			continue
+		}
+
+		if err := hook.run(ctx, nc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// run runs the hook on the node and applies its reply.
+func (h *Hook) run(ctx context.Context, nc *NodeConfig) error {
+	req := &hookMessage{
+		Stage: h.Stage,
+		Lab:   nc.lab,
+		Node:  nc.TargetNode.ShortName,
+		Kind:  nc.TargetNode.Kind,
+	}
+	if vars, ok := jsonVars(nc.Vars).(map[string]interface{}); ok {
+		req.Vars = vars
+	}
+	for i, d := range nc.Data {
+		req.Snippets = append(req.Snippets, &hookSnippet{Template: nc.Info[i], Config: d})
+	}
+
+	in, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("could not pass the node to the %s hook: %w", h.Stage, err)
+	}
+
+	cmd := exec.CommandContext(ctx, h.Command[0], h.Command[1:]...) // skipcq: GSC-G204
+
+	var out, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%s hook %s failed: %w: %s", h.Stage, h.Command[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	if len(bytes.TrimSpace(out.Bytes())) == 0 {
+		return nil
+	}
+
+	reply := &hookMessage{}
+	if err := json.Unmarshal(out.Bytes(), reply); err != nil {
+		return fmt.Errorf("invalid reply of the %s hook %s: %w", h.Stage, h.Command[0], err)
+	}
+
+	log.Debug("Applying the hook reply", "node", nc.TargetNode.ShortName, "stage", h.Stage,
+		"vars", len(reply.Vars), "snippets", len(reply.Snippets))
+
+	if h.Stage == HookPreRender {
+		for k, v := range reply.Vars {
+			if isReservedVar(k) {
+				log.Warnf("the variable %s set by the %s hook on %s will be ignored, it is reserved",
+					k, h.Stage, nc.TargetNode.ShortName)
+				continue
+			}
+			nc.Vars[k] = hookValue(v)
+		}
+		return nil
+	}
+
+	if reply.Snippets != nil {
+		return nc.replaceSnippets(reply.Snippets)
+	}
+
+	return nil
+}
+
+// replaceSnippets replaces the rendered snippets of the node with the snippets of a hook,
+// the provenance of a snippet is kept when the hook keeps its template name.
+func (nc *NodeConfig) replaceSnippets(snippets []*hookSnippet) error {
+	meta := make(map[string]*SnippetMeta, len(nc.Meta))
+	rendered := make(map[string]string, len(nc.Data))
+	if len(nc.Meta) == len(nc.Info) {
+		for i, n := range nc.Info {
+			meta[n] = nc.Meta[i]
+			rendered[n] = nc.Data[i]
+		}
+	}
+
+	data := make([]string, 0, len(snippets))
+	info := make([]string, 0, len(snippets))
+	metas := make([]*SnippetMeta, 0, len(snippets))
+
+	for _, s := range snippets {
+		commit, err := commitMode(s.Config)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Template, err)
+		}
+		weight, err := snippetWeight(s.Config)
+		if err != nil {
+			return fmt.Errorf("%s: %w", s.Template, err)
+		}
+
+		m, ok := meta[s.Template]
+		if !ok {
+			m = &SnippetMeta{Template: s.Template, Engine: hookEngine, Rendered: time.Now().UTC()}
+		}
+		if s.Config != rendered[s.Template] {
+			m.render = nil
+		}
+		m.Commit, m.Weight = commit, weight
+
+		data = append(data, s.Config)
+		info = append(info, s.Template)
+		metas = append(metas, m)
+	}
+
+	nc.Data, nc.Info, nc.Meta = data, info, metas
+
+	return nil
+}
+
+// hookValue converts the whole numbers of the JSON values to ints,
+// as the integer variables of the topology are.
+func hookValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int(v)
+		}
+	case map[string]interface{}:
+		for k, val := range v {
+			v[k] = hookValue(val)
+		}
+	case []interface{}:
+		for i, val := range v {
+			v[i] = hookValue(val)
+		}
+	}
+
+	return v
+}
@@ -0,0 +1,149 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// writeHook writes an executable hook script to the dir.
+func writeHook(t *testing.T, dir, name, script string) string {
+	t.Helper()
+
+	p := filepath.Join(dir, name)
+	if err := os.WriteFile(p, []byte("#!/bin/sh\n"+script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	return p
+}
+
+func TestParseHooks(t *testing.T) {
+	tests := map[string]struct {
+		defs    []string
+		want    Hooks
+		wantErr bool
+	}{
+		"stages": {
+			defs: []string{"pre-render=/bin/vars --lab dc", "pre-push=lint"},
+			want: Hooks{
+				{Stage: HookPreRender, Command: []string{"/bin/vars", "--lab", "dc"}},
+				{Stage: HookPrePush, Command: []string{"lint"}},
+			},
+		},
+		"unknown stage": {defs: []string{"post-push=lint"}, wantErr: true},
+		"no command":    {defs: []string{"pre-push="}, wantErr: true},
+		"no stage":      {defs: []string{"lint"}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParseHooks(tt.defs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil {
+				if d := cmp.Diff(tt.want, got); d != "" {
+					t.Errorf("hooks mismatch (-want +got):\n%s", d)
+				}
+			}
+		})
+	}
+}
+
+func TestHooksRun(t *testing.T) {
+	dir := t.TempDir()
+	reqFile := filepath.Join(dir, "request.json")
+
+	// the pre-render hook records the request and sets the asn
+	preRender := writeHook(t, dir, "vars.sh", "cat > "+reqFile+"\n"+
+		`echo '{"vars": {"asn": 65001, "ratio": 0.5, "clab_node": "x"}}'`+"\n")
+	// the post-render hook adds a snippet, keeping the rendered one
+	postRender := writeHook(t, dir, "banner.sh", `echo '{"snippets": [`+
+		`{"template": "base__srl.tmpl", "config": "set / system name host-name srl1"},`+
+		`{"template": "banner", "config": "set / system banner login-banner lab"}]}'`+"\n")
+	// the pre-push hook only checks the config
+	prePush := writeHook(t, dir, "lint.sh", "grep -q banner || { echo no banner >&2; exit 1; }\n")
+
+	hooks := Hooks{
+		{Stage: HookPreRender, Command: []string{preRender}},
+		{Stage: HookPostRender, Command: []string{postRender}},
+		{Stage: HookPrePush, Command: []string{prePush}},
+	}
+
+	nc := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "srl1", Kind: "nokia_srlinux"},
+		Vars:       map[string]interface{}{vkNodeName: "srl1", "asn": 65000},
+		lab:        "dc",
+	}
+
+	if err := hooks.Run(t.Context(), HookPreRender, nc); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(reqFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &hookMessage{}
+	if err := json.Unmarshal(b, req); err != nil {
+		t.Fatal(err)
+	}
+	wantReq := &hookMessage{
+		Stage: HookPreRender,
+		Lab:   "dc",
+		Node:  "srl1",
+		Kind:  "nokia_srlinux",
+		Vars:  map[string]interface{}{vkNodeName: "srl1", "asn": float64(65000)},
+	}
+	if d := cmp.Diff(wantReq, req); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+
+	wantVars := map[string]interface{}{vkNodeName: "srl1", "asn": 65001, "ratio": 0.5}
+	if d := cmp.Diff(wantVars, nc.Vars); d != "" {
+		t.Errorf("vars mismatch (-want +got):\n%s", d)
+	}
+
+	nc.Data = []string{"set / system name host-name srl1"}
+	nc.Info = []string{"base__srl.tmpl"}
+	nc.Meta = []*SnippetMeta{{Template: "base__srl.tmpl", Engine: engineGo, VarsHash: "abc"}}
+
+	if err := hooks.Run(t.Context(), HookPostRender, nc); err != nil {
+		t.Fatal(err)
+	}
+
+	if d := cmp.Diff([]string{"base__srl.tmpl", "banner"}, nc.Info); d != "" {
+		t.Errorf("snippets mismatch (-want +got):\n%s", d)
+	}
+	if nc.Meta[0].VarsHash != "abc" || nc.Meta[1].Engine != hookEngine {
+		t.Errorf("unexpected snippet provenance %+v, %+v", nc.Meta[0], nc.Meta[1])
+	}
+
+	if err := hooks.Run(t.Context(), HookPrePush, nc); err != nil {
+		t.Errorf("pre-push hook failed: %v", err)
+	}
+
+	nc.Data = nc.Data[:1]
+	if err := hooks.Run(t.Context(), HookPrePush, nc); err == nil {
+		t.Error("expected the pre-push hook to fail")
+	}
+}
+
+func TestHooksInvalidReply(t *testing.T) {
+	hook := writeHook(t, t.TempDir(), "bad.sh", "echo not json\n")
+
+	nc := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "srl1"},
+		Vars:       map[string]interface{}{},
+	}
+
+	err := Hooks{{Stage: HookPostRender, Command: []string{hook}}}.Run(t.Context(), HookPostRender, nc)
+	if err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// junitTestSuites is the root element of a JUnit XML report.
+type junitTestSuites struct {
+	XMLName  xml.Name          `xml:"testsuites"`
+	Name     string            `xml:"name,attr"`
+	Tests    int               `xml:"tests,attr"`
+	Failures int               `xml:"failures,attr"`
+	Errors   int               `xml:"errors,attr"`
+	Suites   []*junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Cases    []*junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes the verification results as a JUnit XML report to w.
+// Every node is reported as a test suite with a test case per verification check.
+// Nodes that failed to run the checks, e.g. due to connection errors, are reported with an error
+// and their checks are marked as skipped.
+func WriteJUnit(w io.Writer, name string, results []*VerifyResult) error {
+	report := &junitTestSuites{Name: name}
+
+	for _, r := range results {
+		suite := &junitTestSuite{
+			Name: r.Node,
+			Time: fmt.Sprintf("%.3f", r.Elapsed.Seconds()),
+		}
+
+		if r.Err != nil {
+			suite.Errors++
+			suite.Cases = append(suite.Cases, &junitTestCase{
+				Name:      "verify",
+				ClassName: r.Node,
+				Error:     &junitMessage{Message: r.Err.Error()},
+			})
+		}
+
+		for _, c := range r.Checks {
+			tc := &junitTestCase{Name: c.Path, ClassName: r.Node}
+			switch {
+			case r.Err != nil:
+				suite.Skipped++
+				tc.Skipped = &junitMessage{Message: "verification did not run"}
+			case !c.Passed:
+				suite.Failures++
+				tc.Failure = &junitMessage{
+					Message: fmt.Sprintf("expected %q, got %q", c.Expected, c.Got),
+				}
+			}
+			suite.Cases = append(suite.Cases, tc)
+		}
+
+		suite.Tests = len(suite.Cases)
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Suites = append(report.Suites, suite)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(report); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteJUnit(t *testing.T) {
+	results := []*VerifyResult{
+		{
+			Node:    "srl1",
+			Elapsed: 1500 * time.Millisecond,
+			Checks: []*CheckResult{
+				{Path: "/interface[name=ethernet-1/1]/oper-state", Expected: "up", Got: "up", Passed: true},
+				{Path: "/interface[name=ethernet-1/2]/oper-state", Expected: "up", Got: "down"},
+			},
+		},
+		{
+			Node: "srl2",
+			Err:  errors.New("connection refused"),
+			Checks: []*CheckResult{
+				{Path: "/system/name/host-name", Expected: "srl2"},
+			},
+		},
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="lab" tests="4" failures="1" errors="1">
+  <testsuite name="srl1" tests="2" failures="1" errors="0" skipped="0" time="1.500">
+    <testcase name="/interface[name=ethernet-1/1]/oper-state" classname="srl1"></testcase>
+    <testcase name="/interface[name=ethernet-1/2]/oper-state" classname="srl1">
+      <failure message="expected &#34;up&#34;, got &#34;down&#34;"></failure>
+    </testcase>
+  </testsuite>
+  <testsuite name="srl2" tests="2" failures="0" errors="1" skipped="1" time="0.000">
+    <testcase name="verify" classname="srl2">
+      <error message="connection refused"></error>
+    </testcase>
+    <testcase name="/system/name/host-name" classname="srl2">
+      <skipped message="verification did not run"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+
+	var s strings.Builder
+	if err := WriteJUnit(&s, "lab", results); err != nil {
+		t.Fatal(err)
+	}
+
+	if diff := cmp.Diff(want, s.String()); diff != "" {
+		t.Errorf("diff: (-want +got)\n%s", diff)
+	}
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"os"
+	"slices"
+	"sort"
+	"time"
+
+	clabcore "github.com/srl-labs/containerlab/core"
+)
+
+// Keys of the lab variable.
+const (
+	labName       = "name"
+	labPrefix     = "prefix"
+	labMgmt       = "mgmt"
+	labNetwork    = "network"
+	labIPv4Subnet = "ipv4_subnet"
+	labIPv4Gw     = "ipv4_gw"
+	labIPv6Subnet = "ipv6_subnet"
+	labIPv6Gw     = "ipv6_gw"
+	labDNS        = "dns"
+	labOwner      = "owner"
+	labDeployed   = "deployed"
+)
+
+// labVars returns the metadata of the lab exposed to the templates of all nodes,
+// e.g. {{ .clab_lab.mgmt.ipv4_gw }} for the NTP, DNS and syslog servers of the lab.
+// The gateways of the management network not set in the topology are taken from the facts
+// of the deployed nodes, the DNS servers not set in the topology defaults from the nodes.
+// The deploy time is the time the facts of the lab were recorded, empty when the lab is not deployed.
+func labVars(c *clabcore.CLab, res map[string]*NodeConfig, facts Dict, deployed time.Time) Dict {
+	names := make([]string, 0, len(res))
+	for n := range res {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	prefix := ""
+	if c.Config.Prefix != nil {
+		prefix = *c.Config.Prefix
+	}
+
+	mgmt := Dict{}
+	if m := c.Config.Mgmt; m != nil {
+		mgmt = Dict{
+			labNetwork:    m.Network,
+			labIPv4Subnet: m.IPv4Subnet,
+			labIPv4Gw:     m.IPv4Gw,
+			labIPv6Subnet: m.IPv6Subnet,
+			labIPv6Gw:     m.IPv6Gw,
+		}
+	}
+	for _, gw := range []struct{ key, fact string }{
+		{labIPv4Gw, "mgmt_ipv4_gateway"},
+		{labIPv6Gw, "mgmt_ipv6_gateway"},
+	} {
+		for _, n := range names {
+			if v, _ := mgmt[gw.key].(string); v != "" {
+				break
+			}
+			if f, ok := facts[n].(Dict); ok {
+				if v, ok := f[gw.fact].(string); ok {
+					mgmt[gw.key] = v
+				}
+			}
+		}
+	}
+
+	var dns []string
+	if c.Config.Topology != nil {
+		if d := c.Config.Topology.GetDefaults().GetDns(); d != nil {
+			dns = append(dns, d.Servers...)
+		}
+	}
+	if len(dns) == 0 {
+		for _, n := range names {
+			if d := res[n].TargetNode.DNS; d != nil {
+				for _, s := range d.Servers {
+					if !slices.Contains(dns, s) {
+						dns = append(dns, s)
+					}
+				}
+			}
+		}
+	}
+	dnsVar := make([]interface{}, 0, len(dns))
+	for _, s := range dns {
+		dnsVar = append(dnsVar, s)
+	}
+
+	vars := Dict{
+		labName:     c.Config.Name,
+		labPrefix:   prefix,
+		labMgmt:     mgmt,
+		labDNS:      dnsVar,
+		labOwner:    c.Owner(),
+		labDeployed: "",
+	}
+	if !deployed.IsZero() {
+		vars[labDeployed] = deployed.UTC().Format(time.RFC3339)
+	}
+
+	return vars
+}
+
+// labDeployTime returns the time the facts file of the lab was written on deploy,
+// zero when the lab was not deployed.
+func labDeployTime(factsPath string) time.Time {
+	fi, err := os.Stat(factsPath)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return fi.ModTime()
+}
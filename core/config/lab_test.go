@@ -0,0 +1,100 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestLabVars(t *testing.T) {
+	t.Setenv("SUDO_USER", "")
+	t.Setenv("USER", "alice")
+
+	prefix := "lab"
+	deployed := time.Date(2026, 3, 1, 10, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	tests := map[string]struct {
+		topology *clabtypes.Topology
+		facts    Dict
+		deployed time.Time
+		want     Dict
+	}{
+		"deployed lab": {
+			topology: &clabtypes.Topology{},
+			facts: Dict{
+				"srl1": Dict{"mgmt_ipv4_gateway": "172.20.20.1"},
+			},
+			deployed: deployed,
+			want: Dict{
+				labName:   "dc",
+				labPrefix: "lab",
+				labMgmt: Dict{
+					labNetwork:    "clab",
+					labIPv4Subnet: "172.20.20.0/24",
+					labIPv4Gw:     "172.20.20.1",
+					labIPv6Subnet: "3fff:172:20:20::/64",
+					labIPv6Gw:     "",
+				},
+				labDNS:      []interface{}{"192.0.2.53", "198.51.100.53"},
+				labOwner:    "alice",
+				labDeployed: "2026-03-01T09:30:00Z",
+			},
+		},
+		"topology dns": {
+			topology: &clabtypes.Topology{
+				Defaults: &clabtypes.NodeDefinition{
+					DNS: &clabtypes.DNSConfig{Servers: []string{"10.0.0.53"}},
+				},
+			},
+			facts: Dict{},
+			want: Dict{
+				labName:   "dc",
+				labPrefix: "lab",
+				labMgmt: Dict{
+					labNetwork:    "clab",
+					labIPv4Subnet: "172.20.20.0/24",
+					labIPv4Gw:     "",
+					labIPv6Subnet: "3fff:172:20:20::/64",
+					labIPv6Gw:     "",
+				},
+				labDNS:      []interface{}{"10.0.0.53"},
+				labOwner:    "alice",
+				labDeployed: "",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &clabcore.CLab{
+				Config: &clabcore.Config{
+					Name:   "dc",
+					Prefix: &prefix,
+					Mgmt: &clabtypes.MgmtNet{
+						Network:    "clab",
+						IPv4Subnet: "172.20.20.0/24",
+						IPv6Subnet: "3fff:172:20:20::/64",
+					},
+					Topology: tt.topology,
+				},
+			}
+
+			res := map[string]*NodeConfig{
+				"srl1": {TargetNode: &clabtypes.NodeConfig{
+					DNS: &clabtypes.DNSConfig{Servers: []string{"192.0.2.53", "198.51.100.53"}},
+				}},
+				"srl2": {TargetNode: &clabtypes.NodeConfig{
+					DNS: &clabtypes.DNSConfig{Servers: []string{"192.0.2.53"}},
+				}},
+			}
+
+			got := labVars(c, res, tt.facts, tt.deployed)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("lab vars mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
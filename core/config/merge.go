@@ -0,0 +1,100 @@
+package config
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// contextLines are the lines changing the CLI context, which are never deduplicated.
+var contextLines = map[string]bool{ //nolint:gochecknoglobals
+	"exit":        true,
+	"exit all":    true,
+	"back":        true,
+	"top":         true,
+	"commit":      true,
+	"commit now":  true,
+	"quit-config": true,
+}
+
+// stanza is a top-level config line with the indented lines and blocks following it.
+type stanza struct {
+	lines []string
+	// key identifies the stanza, ignoring indentation and empty lines
+	key string
+}
+
+// splitStanzas splits a config snippet into stanzas.
+// A stanza starts with a line without indentation outside of any {} block.
+func splitStanzas(data string) []*stanza {
+	var res []*stanza
+	var cur *stanza
+	depth := 0
+
+	for _, l := range strings.Split(data, "\n") {
+		t := strings.TrimSpace(l)
+		if cur == nil || (depth == 0 && t != "" && t != "}" && l == strings.TrimLeft(l, " \t")) {
+			cur = &stanza{}
+			res = append(res, cur)
+		}
+
+		cur.lines = append(cur.lines, l)
+		if t != "" {
+			cur.key += t + "\n"
+		}
+
+		depth += strings.Count(t, "{") - strings.Count(t, "}")
+		if depth < 0 {
+			depth = 0
+		}
+	}
+
+	return res
+}
+
+// dedupSnippets removes the stanzas of the config snippets of the node
+// that are already part of an earlier snippet, or earlier in the same snippet,
+// so that overlapping templates don't send the same config twice.
+// The order of the remaining stanzas is kept.
+func (c *NodeConfig) dedupSnippets() {
+	node := c.TargetNode.ShortName
+	seen := map[string]string{}
+	var data, info []string
+	var meta []*SnippetMeta
+
+	for i, d := range c.Data {
+		var kept []string
+		removed := false
+		for _, s := range splitStanzas(d) {
+			if s.key == "" || contextLines[strings.TrimSpace(s.key)] {
+				kept = append(kept, s.lines...)
+				continue
+			}
+			if first, ok := seen[s.key]; ok {
+				log.Debugf("%s: skipping config of %s already in %s: %s",
+					node, c.Info[i], first, strings.SplitN(s.key, "\n", 2)[0])
+				removed = true
+				continue
+			}
+			seen[s.key] = c.Info[i]
+			kept = append(kept, s.lines...)
+		}
+
+		merged := strings.Trim(strings.Join(kept, "\n"), "\n \t\r")
+		if merged == "" {
+			log.Debugf("%s: all config of %s is in earlier templates, skipping it", node, c.Info[i])
+			continue
+		}
+
+		data = append(data, merged)
+		info = append(info, c.Info[i])
+		if i < len(c.Meta) {
+			if removed {
+				c.Meta[i].render = nil
+			}
+			meta = append(meta, c.Meta[i])
+		}
+	}
+
+	c.Data, c.Info, c.Meta = data, info, meta
+}
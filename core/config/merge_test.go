@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestDedupSnippets(t *testing.T) {
+	base := `/interface lo0 {
+    admin-state enable
+}
+
+/configure router interface "system"
+    ipv4 primary address 10.0.0.1
+exit all`
+
+	ifaces := `/interface lo0 {
+  admin-state enable
+}
+/interface ethernet-1/1 {
+    admin-state enable
+}
+/configure router interface "system"
+    ipv4 primary address 10.0.0.1
+exit all
+set / system name host-name srl1
+set / system name host-name srl1`
+
+	dup := `set / system name host-name srl1`
+
+	nc := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "srl1"},
+		Data:       []string{base, ifaces, dup},
+		Info:       []string{"base__srl.tmpl", "ifaces__srl.tmpl", "dup__srl.tmpl"},
+	}
+	nc.dedupSnippets()
+	data, info := nc.Data, nc.Info
+
+	want := []string{base, `/interface ethernet-1/1 {
+    admin-state enable
+}
+exit all
+set / system name host-name srl1`}
+
+	if d := cmp.Diff(want, data); d != "" {
+		t.Errorf("dedupSnippets() data mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"base__srl.tmpl", "ifaces__srl.tmpl"}, info); d != "" {
+		t.Errorf("dedupSnippets() info mismatch (-want +got):\n%s", d)
+	}
+}
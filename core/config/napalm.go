@@ -0,0 +1,164 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// NapalmRunnerEnv is the environment variable with the command running the NAPALM getters.
+// The command receives the request as JSON on stdin, with the driver, host, username, password,
+// optional_args and getter keys, and writes the result of the getter as JSON to stdout.
+const NapalmRunnerEnv = "CLAB_NAPALM_RUNNER"
+
+// napalmScript runs a NAPALM getter with python, used when no runner command is set.
+const napalmScript = `import json, sys
+from napalm import get_network_driver
+req = json.load(sys.stdin)
+dev = get_network_driver(req["driver"])(req["host"], req["username"], req["password"],
+                                         optional_args=req["optional_args"])
+dev.open()
+try:
+    res = getattr(dev, req["getter"])()
+finally:
+    dev.close()
+json.dump(res, sys.stdout, default=str)
+`
+
+// napalmGetterRe matches the names of the NAPALM getters, the tests can't call the other driver methods.
+var napalmGetterRe = regexp.MustCompile(`^get_[a-z0-9_]+$`)
+
+// napalmDrivers are the NAPALM drivers of the node kinds, srlinux and sros are community drivers.
+var napalmDrivers = map[string]string{ //nolint:gochecknoglobals
+	"arista_ceos":           "eos",
+	"ceos":                  "eos",
+	"arista_veos":           "eos",
+	"vr-veos":               "eos",
+	"juniper_crpd":          "junos",
+	"crpd":                  "junos",
+	"juniper_vmx":           "junos",
+	"vr-vmx":                "junos",
+	"juniper_vqfx":          "junos",
+	"vr-vqfx":               "junos",
+	"juniper_vsrx":          "junos",
+	"vr-vsrx":               "junos",
+	"juniper_vjunosrouter":  "junos",
+	"juniper_vjunosswitch":  "junos",
+	"juniper_vjunosevolved": "junos",
+	"cisco_xrv":             "iosxr",
+	"vr-xrv":                "iosxr",
+	"cisco_xrv9k":           "iosxr",
+	"vr-xrv9k":              "iosxr",
+	"cisco_xrd":             "iosxr",
+	"xrd":                   "iosxr",
+	"cisco_csr1000v":        "ios",
+	"vr-csr":                "ios",
+	"cisco_c8000v":          "ios",
+	"cisco_cat9kv":          "ios",
+	"cisco_iol":             "ios",
+	"cisco_n9kv":            "nxos_ssh",
+	"vr-n9kv":               "nxos_ssh",
+	"nokia_srlinux":         "srlinux",
+	"srl":                   "srlinux",
+	"nokia_sros":            "sros",
+	"vr-sros":               "sros",
+}
+
+// napalmRequest is the request passed to the NAPALM runner.
+type napalmRequest struct {
+	Driver       string         `json:"driver"`
+	Host         string         `json:"host"`
+	Username     string         `json:"username"`
+	Password     string         `json:"password"`
+	OptionalArgs map[string]any `json:"optional_args"`
+	Getter       string         `json:"getter"`
+}
+
+// napalmDriver returns the NAPALM driver of the node, set with the config.napalm.driver label
+// or the driver of the node kind.
+func napalmDriver(cs *NodeConfig) (string, error) {
+	if d, ok := cs.TargetNode.Labels["config.napalm.driver"]; ok && d != "" {
+		return d, nil
+	}
+
+	if d, ok := napalmDrivers[cs.TargetNode.Kind]; ok {
+		return d, nil
+	}
+
+	return "", fmt.Errorf("kind %s has no NAPALM driver, set the config.napalm.driver label", cs.TargetNode.Kind)
+}
+
+// validateGetter checks the name of a NAPALM getter.
+func validateGetter(getter string) error {
+	if !napalmGetterRe.MatchString(getter) {
+		return fmt.Errorf("invalid NAPALM getter %q, expected a get_* getter, e.g. get_bgp_neighbors", getter)
+	}
+	return nil
+}
+
+// runGetter runs the NAPALM getter on the node and returns its result as JSON.
+// The port of the driver is set with the config.napalm.port label.
+func runGetter(ctx context.Context, cs *NodeConfig, getter string) (string, error) {
+	driver, err := napalmDriver(cs)
+	if err != nil {
+		return "", err
+	}
+
+	req := &napalmRequest{
+		Driver:       driver,
+		Host:         transport.NodeHost(cs.TargetNode),
+		OptionalArgs: map[string]any{},
+		Getter:       getter,
+	}
+	creds, err := cs.sshCredentials()
+	if err != nil {
+		return "", err
+	}
+	if len(creds) == 2 {
+		req.Username, req.Password = creds[0], creds[1]
+	}
+	if p, ok := cs.TargetNode.Labels["config.napalm.port"]; ok {
+		port, err := strconv.Atoi(p)
+		if err != nil {
+			return "", fmt.Errorf("invalid config.napalm.port label %q: %w", p, err)
+		}
+		req.OptionalArgs["port"] = port
+	}
+
+	in, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+
+	var cmd *exec.Cmd
+	if r := os.Getenv(NapalmRunnerEnv); r != "" {
+		cmd = exec.CommandContext(ctx, r) // skipcq: GSC-G204
+	} else {
+		cmd = exec.CommandContext(ctx, "python3", "-c", napalmScript)
+	}
+
+	var out, stderr bytes.Buffer
+	cmd.Stdin = bytes.NewReader(in)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		// the python traceback ends with the exception
+		if i := strings.LastIndex(msg, "\n"); i >= 0 {
+			msg = msg[i+1:]
+		}
+		return "", fmt.Errorf("NAPALM %s getter %s failed with %s: %w: %s",
+			driver, getter, cmd.Args[0], err, msg)
+	}
+
+	return out.String(), nil
+}
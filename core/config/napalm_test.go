@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestNapalmDriver(t *testing.T) {
+	tests := map[string]struct {
+		kind    string
+		labels  map[string]string
+		want    string
+		wantErr bool
+	}{
+		"kind":        {kind: "arista_ceos", want: "eos"},
+		"label":       {kind: "linux", labels: map[string]string{"config.napalm.driver": "ios"}, want: "ios"},
+		"unsupported": {kind: "linux", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			cs := &NodeConfig{TargetNode: &clabtypes.NodeConfig{Kind: tt.kind, Labels: tt.labels}}
+			got, err := napalmDriver(cs)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("want driver %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRunTestsGetter(t *testing.T) {
+	dir := t.TempDir()
+	reqFile := filepath.Join(dir, "request.json")
+
+	// the runner records the request and returns a get_bgp_neighbors result
+	runner := filepath.Join(dir, "napalm.sh")
+	script := "#!/bin/sh\ncat > " + reqFile + "\n" +
+		`echo '{"global": {"router_id": "10.0.0.1", "peers": {"10.0.0.2": {"is_up": true}}}}'` + "\n"
+	if err := os.WriteFile(runner, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(NapalmRunnerEnv, runner)
+
+	cs := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{
+			ShortName: "ceos1",
+			LongName:  "clab-t-ceos1",
+			Kind:      "arista_ceos",
+			Labels:    map[string]string{"config.napalm.port": "443"},
+		},
+		Credentials: []string{"admin", "admin"},
+	}
+
+	tests := []*clabtypes.ConfigTest{
+		{
+			Getter: "get_bgp_neighbors",
+			Expect: &clabtypes.TestExpect{JSONPath: "global.router_id", Value: "10.0.0.1"},
+		},
+		{
+			Name:   "peer up",
+			Getter: "get_bgp_neighbors",
+			Expect: &clabtypes.TestExpect{Contains: `"is_up": false`},
+		},
+	}
+
+	res := &VerifyResult{Node: "ceos1"}
+	runTests(t.Context(), cs, tests, res)
+	if res.Err != nil {
+		t.Fatal(res.Err)
+	}
+
+	got := make([][]any, 0, len(res.Checks))
+	for _, c := range res.Checks {
+		got = append(got, []any{c.Path, c.Got, c.Passed})
+	}
+	want := [][]any{
+		{"get_bgp_neighbors", "10.0.0.1", true},
+		{"peer up", `output without "\"is_up\": false"`, false},
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("results mismatch (-want +got):\n%s", d)
+	}
+
+	b, err := os.ReadFile(reqFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req := &napalmRequest{}
+	if err := json.Unmarshal(b, req); err != nil {
+		t.Fatal(err)
+	}
+	wantReq := &napalmRequest{
+		Driver:       "eos",
+		Host:         "clab-t-ceos1",
+		Username:     "admin",
+		Password:     "admin",
+		OptionalArgs: map[string]any{"port": float64(443)},
+		Getter:       "get_bgp_neighbors",
+	}
+	if d := cmp.Diff(wantReq, req); d != "" {
+		t.Errorf("request mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRunTestsInvalid(t *testing.T) {
+	cs := &NodeConfig{TargetNode: &clabtypes.NodeConfig{ShortName: "ceos1", Kind: "arista_ceos"}}
+
+	tests := map[string]*clabtypes.ConfigTest{
+		"no command or getter": {Expect: &clabtypes.TestExpect{Contains: "x"}},
+		"command and getter": {
+			Command: "show version", Getter: "get_facts",
+			Expect: &clabtypes.TestExpect{Contains: "x"},
+		},
+		"invalid getter": {Getter: "cli", Expect: &clabtypes.TestExpect{Contains: "x"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			res := &VerifyResult{Node: "ceos1"}
+			runTests(t.Context(), cs, []*clabtypes.ConfigTest{tt}, res)
+			if res.Err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	clabcore "github.com/srl-labs/containerlab/core"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+)
+
+// netnsLabel makes the transports of the node dial it from its network namespace.
+const netnsLabel = "config.netns"
+
+// DialFromNetNS makes the transports of the lab nodes dial them from the nodes' network namespaces,
+// so the nodes are reachable when the host has no route to the management network.
+// All the container nodes are dialed from their namespace when all is set,
+// otherwise only the nodes with the config.netns label set.
+// External nodes have no namespace and are dialed as usual.
+func DialFromNetNS(ctx context.Context, c *clabcore.CLab, configs map[string]*NodeConfig, all bool) error {
+	names := make([]string, 0, len(configs))
+	for n := range configs {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		node, ok := c.Nodes[name]
+		if !ok {
+			continue
+		}
+
+		err := setNetNSDialer(ctx, node, configs[name], all)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setNetNSDialer sets the dialer of the node config to its network namespace,
+// when all is set or the node has the config.netns label set.
+func setNetNSDialer(ctx context.Context, node clabnodes.Node, cs *NodeConfig, all bool) error {
+	name := cs.TargetNode.ShortName
+
+	use := all
+	if v, ok := cs.TargetNode.Labels[netnsLabel]; ok {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("%s: invalid %s value %q", name, netnsLabel, v)
+		}
+		use = use || b
+	}
+	if !use {
+		return nil
+	}
+
+	// the config commands only initialize the runtime when all the nodes are dialed from their namespace
+	if node.GetRuntime() == nil {
+		return fmt.Errorf("%s: %s is set but the container runtime is not initialized, run with --netns",
+			name, netnsLabel)
+	}
+
+	nsPath, err := node.GetNSPath(ctx)
+	if err != nil {
+		return fmt.Errorf("%s: cannot dial from the node network namespace: %w", name, err)
+	}
+
+	log.Debugf("%s: dialing from the network namespace %s", name, nsPath)
+	cs.Dialer = transport.NetNSDialer(nsPath)
+
+	return nil
+}
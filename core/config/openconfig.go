@@ -0,0 +1,201 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/charmbracelet/log"
+	jT "github.com/kellerza/template"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// OpenConfigTranslatorEnv is the environment variable with the command translating the OpenConfig intent
+// of the nodes configured over SSH to the CLI of their kind. The command receives the kind as argument,
+// the rendered OpenConfig JSON on stdin and writes the CLI config lines to stdout.
+const OpenConfigTranslatorEnv = "CLAB_OPENCONFIG_TRANSLATOR"
+
+// openconfigOrigin is the gNMI origin of the OpenConfig paths.
+const openconfigOrigin = "openconfig"
+
+// openconfigEngine renders the OpenConfig intent files (*.json) of a template path.
+// An intent file is the OpenConfig JSON (RFC 7951) of a role, rendered as a Go template with the node variables.
+// The rendered intent is converted to gNMI Set operations, or to CLI lines by the translator of the kind,
+// see openconfigSnippet.
+type openconfigEngine struct {
+	dir string
+
+	// found caches the intent lookups, nodes of the same role look up the same files
+	found parseCache[bool]
+	// intents caches the parsed intent files, rendered by all nodes of the role
+	intents parseCache[*template.Template]
+}
+
+func (*openconfigEngine) Name() string {
+	return engineOpenConfig
+}
+
+// Lookup returns the intent file of the role. The intent has no undo files,
+// the config it adds is not reversed on lab destroy.
+func (e *openconfigEngine) Lookup(name, role string) (string, error) {
+	if strings.HasPrefix(name, undoPrefix) {
+		return "", nil
+	}
+
+	tmplN := fmt.Sprintf("%s__%s.json", name, role)
+	log.Debugf("Looking up OpenConfig intent %v in %s", tmplN, e.dir)
+
+	found, err := e.found.get(tmplN, func() (bool, error) {
+		return fileExists(filepath.Join(e.dir, tmplN))
+	})
+	if err != nil || !found {
+		return "", err
+	}
+
+	return tmplN, nil
+}
+
+// Render renders the intent file and writes the OpenConfig JSON, indented.
+// The intent is validated as a whole, nothing is written for an invalid intent.
+func (e *openconfigEngine) Render(w io.Writer, tmplN, _ string, vars map[string]interface{}) error {
+	t, err := e.intents.get(tmplN, func() (*template.Template, error) {
+		b, err := os.ReadFile(filepath.Join(e.dir, tmplN))
+		if err != nil {
+			return nil, err
+		}
+		return template.New(tmplN).Funcs(clabutils.CreateFuncs()).Funcs(jT.Funcs).Parse(string(b))
+	})
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, vars); err != nil {
+		return err
+	}
+
+	if len(bytes.TrimSpace(buf.Bytes())) == 0 {
+		return nil
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, buf.Bytes(), "", "  "); err != nil {
+		return fmt.Errorf("%s is not valid JSON: %w", tmplN, err)
+	}
+
+	_, err = out.WriteTo(w)
+	return err
+}
+
+// openconfigSnippet converts the rendered OpenConfig intent to the snippet sent to the node.
+// The nodes configured with gNMI get the update operations of the intent, see openconfigSetOps.
+// The nodes configured over SSH get the CLI lines of the translator set with CLAB_OPENCONFIG_TRANSLATOR.
+func openconfigSnippet(nc *NodeConfig, intent string) (string, error) {
+	if configTransports(nc)[0] == transportGNMI {
+		return openconfigSetOps(intent)
+	}
+
+	t := os.Getenv(OpenConfigTranslatorEnv)
+	if t == "" {
+		return "", fmt.Errorf("the OpenConfig intent of node %s requires the gnmi config.transport "+
+			"or a translator to the CLI of kind %s set with %s", nc.TargetNode.ShortName, nc.TargetNode.Kind,
+			OpenConfigTranslatorEnv)
+	}
+
+	cmd := exec.Command(t, nc.TargetNode.Kind) // skipcq: GSC-G204
+
+	var out, stderr bytes.Buffer
+	cmd.Stdin = strings.NewReader(intent)
+	cmd.Stdout = &out
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("could not translate the OpenConfig intent to the CLI of kind %s with %s: %w: %s",
+			nc.TargetNode.Kind, t, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return out.String(), nil
+}
+
+// openconfigSetOps converts the OpenConfig JSON to the update operations of a gNMI snippet, one per line.
+// The operations are split at the children of the top-level containers and the elements of their lists,
+// so that the snippets of the nodes compare line by line, e.g.
+//
+//	update openconfig:/interfaces/interface[name=ethernet-1/1] = {"config": {...}, "name": "ethernet-1/1"}
+//
+// The module prefixes of the top-level containers are removed from the paths.
+func openconfigSetOps(intent string) (string, error) {
+	var doc map[string]any
+	if err := json.Unmarshal([]byte(intent), &doc); err != nil {
+		return "", fmt.Errorf("the OpenConfig intent must be a JSON object: %w", err)
+	}
+
+	var lines []string
+
+	for _, top := range sortedKeys(doc) {
+		prefix := openconfigOrigin + ":/" + stripModule(top)
+
+		children, ok := doc[top].(map[string]any)
+		if !ok || len(children) == 0 {
+			lines = append(lines, setOpLine(prefix, doc[top]))
+			continue
+		}
+
+		for _, k := range sortedKeys(children) {
+			p := prefix + "/" + stripModule(k)
+			v := children[k]
+
+			if list, ok := v.([]any); ok && isKeyedList(list) {
+				for i, e := range list {
+					lines = append(lines, setOpLine(p+listElemKey(e, i), e))
+				}
+				continue
+			}
+
+			lines = append(lines, setOpLine(p, v))
+		}
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// setOpLine returns the update line of the path with the JSON value.
+func setOpLine(p string, v any) string {
+	b, _ := json.Marshal(v)
+	return fmt.Sprintf("update %s = %s", p, b)
+}
+
+// stripModule removes the module prefix of a JSON member name, e.g. openconfig-interfaces:interfaces.
+func stripModule(name string) string {
+	if _, n, ok := strings.Cut(name, ":"); ok {
+		return n
+	}
+	return name
+}
+
+// isKeyedList returns true when the elements of the JSON list are objects with a key field,
+// the list is set as a whole otherwise.
+func isKeyedList(list []any) bool {
+	for i, e := range list {
+		if _, ok := e.(map[string]any); !ok || !strings.Contains(listElemKey(e, i), "=") {
+			return false
+		}
+	}
+	return len(list) > 0
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
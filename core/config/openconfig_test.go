@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+const testIntent = `{
+  "openconfig-system:system": {
+    "config": {"hostname": "{{ .clab_node }}"}
+  },
+  "openconfig-interfaces:interfaces": {
+    "interface": [
+      {"name": "ethernet-1/1", "config": {"name": "ethernet-1/1", "enabled": true}},
+      {"name": "ethernet-1/2", "config": {"name": "ethernet-1/2", "enabled": false}}
+    ]
+  }
+}`
+
+func TestOpenConfigSetOps(t *testing.T) {
+	tests := map[string]struct {
+		intent  string
+		want    string
+		wantErr bool
+	}{
+		"containers and lists": {
+			intent: `{"openconfig-system:system": {"config": {"hostname": "r1"}, "dns": {"servers": {}}},
+				"openconfig-interfaces:interfaces": {"interface": [{"name": "eth1", "config": {"mtu": 9000}}]}}`,
+			want: "update openconfig:/interfaces/interface[name=eth1] = " +
+				`{"config":{"mtu":9000},"name":"eth1"}` + "\n" +
+				`update openconfig:/system/config = {"hostname":"r1"}` + "\n" +
+				`update openconfig:/system/dns = {"servers":{}}`,
+		},
+		"unkeyed list": {
+			intent: `{"openconfig-system:system": {"servers": [{"address": "10.0.0.1"}, {"port": 53}]}}`,
+			want:   `update openconfig:/system/servers = [{"address":"10.0.0.1"},{"port":53}]`,
+		},
+		"not an object": {intent: `["system"]`, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := openconfigSetOps(tt.intent)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("operations mismatch (-want +got):\n%s", d)
+			}
+			if err == nil {
+				// the operations are a valid gNMI snippet
+				if _, err := transport.ParseGNMISet(got); err != nil {
+					t.Errorf("invalid gNMI snippet: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestRenderOpenConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "base__leaf.json"), []byte(testIntent), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the translator prints the kind and the hostname of the intent
+	translator := filepath.Join(dir, "translate.sh")
+	err = os.WriteFile(translator, []byte("#!/bin/sh\necho \"# $1\"\ngrep -o '\"hostname\": \"[a-z0-9]*\"'\n"), 0o755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRenderer(WithTemplatePaths([]string{"openconfig:" + dir}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff([]string{"base"}, r.Names); d != "" {
+		t.Errorf("template names mismatch (-want +got):\n%s", d)
+	}
+
+	tests := map[string]struct {
+		labels     map[string]string
+		translator string
+		want       []string
+		wantErr    bool
+	}{
+		"gnmi": {
+			labels: map[string]string{"config.transport": "gnmi"},
+			want: []string{"update openconfig:/interfaces/interface[name=ethernet-1/1] = " +
+				`{"config":{"enabled":true,"name":"ethernet-1/1"},"name":"ethernet-1/1"}` + "\n" +
+				"update openconfig:/interfaces/interface[name=ethernet-1/2] = " +
+				`{"config":{"enabled":false,"name":"ethernet-1/2"},"name":"ethernet-1/2"}` + "\n" +
+				`update openconfig:/system/config = {"hostname":"leaf1"}`},
+		},
+		"translator": {
+			translator: translator,
+			want:       []string{"# nokia_srlinux\n\"hostname\": \"leaf1\""},
+		},
+		"no translator": {wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(OpenConfigTranslatorEnv, tt.translator)
+
+			nc := &NodeConfig{
+				TargetNode: &clabtypes.NodeConfig{ShortName: "leaf1", Kind: "nokia_srlinux", Labels: tt.labels},
+				Vars:       map[string]interface{}{vkNodeName: "leaf1", vkRole: "leaf"},
+			}
+
+			err := r.RenderNode(nc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if d := cmp.Diff(tt.want, nc.Data); d != "" {
+				t.Errorf("rendered config mismatch (-want +got):\n%s", d)
+			}
+			if err == nil && nc.Meta[0].Engine != engineOpenConfig {
+				t.Errorf("want the %s engine, got %s", engineOpenConfig, nc.Meta[0].Engine)
+			}
+		})
+	}
+}
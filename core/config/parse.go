@@ -0,0 +1,135 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// showDirName is the dir in the node's lab dir holding the captured show command outputs.
+const showDirName = "show"
+
+var (
+	srlShowTemplates = map[string]string{ //nolint:gochecknoglobals
+		"show interface brief": `^\|\s*(?P<port>[\w/-]+)\s*\|\s*(?P<admin_state>\S+)\s*\|\s*(?P<oper_state>\S+)\s*\|`,
+		"show network-instance summary": `^\|\s*(?P<name>\S+)\s*\|\s*(?P<type>\S+)\s*\|` +
+			`\s*(?P<admin_state>\S+)\s*\|\s*(?P<oper_state>\S+)\s*\|`,
+	}
+	srosShowTemplates = map[string]string{ //nolint:gochecknoglobals
+		"show port": `^(?P<port>\d+/\d+/\S+)\s+(?P<admin_state>Up|Down)\s+(?:(?P<link>Yes|No)\s+)?` +
+			`(?P<port_state>Link Up|Up|Down|Ghost)`,
+		"show router interface": `^(?P<name>\S+)\s+(?P<admin_state>Up|Down)\s+` +
+			`(?P<oper_state>Up|Down)/(?:Up|Down)\s+(?P<mode>\S+)\s+(?P<port>\S+)`,
+	}
+
+	// showTemplates are the built-in parsing templates of the show commands by kind.
+	showTemplates = map[string]map[string]string{ //nolint:gochecknoglobals
+		"srl":           srlShowTemplates,
+		"nokia_srlinux": srlShowTemplates,
+		"vr-sros":       srosShowTemplates,
+		"nokia_sros":    srosShowTemplates,
+		"nokia_srsim":   srosShowTemplates,
+	}
+
+	// showFieldRe matches the path of a show check, a field of the first record
+	// or a field of the record with a key field set to a value, e.g. oper_state[port=ethernet-1/1].
+	showFieldRe = regexp.MustCompile(`^(?P<field>\w+)(?:\[(?P<key>\w+)=(?P<value>[^\]]*)\])?$`)
+
+	nonFileCharsRe = regexp.MustCompile(`[^\w.-]+`)
+)
+
+// ShowRecord is a record parsed from the output of a show command.
+type ShowRecord map[string]string
+
+// showTemplate returns the parsing template of the command,
+// the custom template takes precedence over the built-in template of the kind.
+func showTemplate(kind, command, custom string) (*regexp.Regexp, error) {
+	tmpl := custom
+	if tmpl == "" {
+		tmpl = showTemplates[kind][command]
+	}
+	if tmpl == "" {
+		return nil, fmt.Errorf("no parsing template for command %q of kind %s", command, kind)
+	}
+
+	re, err := regexp.Compile(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parsing template of command %q: %w", command, err)
+	}
+
+	for _, n := range re.SubexpNames() {
+		if n != "" {
+			return re, nil
+		}
+	}
+
+	return nil, fmt.Errorf("parsing template of command %q has no named groups", command)
+}
+
+// ParseShow converts the output of a show command into records.
+// Every line matching the template produces a record holding the named groups of the template.
+func ParseShow(re *regexp.Regexp, out string) []ShowRecord {
+	var res []ShowRecord
+
+	names := re.SubexpNames()
+	for _, l := range strings.Split(out, "\n") {
+		m := re.FindStringSubmatch(strings.TrimRight(l, "\r"))
+		if m == nil {
+			continue
+		}
+
+		r := ShowRecord{}
+		for i, n := range names {
+			if n != "" {
+				r[n] = m[i]
+			}
+		}
+		res = append(res, r)
+	}
+
+	return res
+}
+
+// lookupField returns the value of the field selected by the path of a show check.
+func lookupField(records []ShowRecord, path string) (string, error) {
+	m := showFieldRe.FindStringSubmatch(path)
+	if m == nil {
+		return "", fmt.Errorf("invalid show check path %q, expected field or field[key=value]", path)
+	}
+	field, key, value := m[1], m[2], m[3]
+
+	for _, r := range records {
+		if key != "" && r[key] != value {
+			continue
+		}
+		return r[field], nil
+	}
+
+	return "", nil
+}
+
+// saveShow stores the raw output of the show command and the records parsed from it
+// in the node's lab dir.
+func saveShow(cs *NodeConfig, command, out string, records []ShowRecord) error {
+	dir := filepath.Join(cs.TargetNode.LabDir, showDirName)
+	clabutils.CreateDirectory(dir, 0o755)
+
+	name := strings.Trim(nonFileCharsRe.ReplaceAllString(command, "-"), "-")
+
+	err := os.WriteFile(filepath.Join(dir, name+".txt"), []byte(out), 0o644) // skipcq: GSC-G306
+	if err != nil {
+		return err
+	}
+
+	b, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(dir, name+".json"), b, 0o644) // skipcq: GSC-G306
+}
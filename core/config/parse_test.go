@@ -0,0 +1,66 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseShow(t *testing.T) {
+	out := `+---------------------+------------+------------+------------+
+|        Port         | Admin State| Oper State |   Speed    |
++=====================+============+============+============+
+| ethernet-1/1        | enable     | up         | 25G        |
+| ethernet-1/2        | enable     | down       | 25G        |
++---------------------+------------+------------+------------+
+`
+
+	re, err := showTemplate("nokia_srlinux", "show interface brief", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := ParseShow(re, out)
+	want := []ShowRecord{
+		{"port": "ethernet-1/1", "admin_state": "enable", "oper_state": "up"},
+		{"port": "ethernet-1/2", "admin_state": "enable", "oper_state": "down"},
+	}
+	if d := cmp.Diff(want, records); d != "" {
+		t.Fatalf("ParseShow() mismatch (-want +got):\n%s", d)
+	}
+
+	tests := map[string]struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		"first record":   {path: "oper_state", want: "up"},
+		"selected":       {path: "oper_state[port=ethernet-1/2]", want: "down"},
+		"no such record": {path: "oper_state[port=ethernet-1/3]", want: ""},
+		"invalid path":   {path: "/interface/oper-state", wantErr: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := lookupField(records, tc.path)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("lookupField() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("lookupField() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestShowTemplate(t *testing.T) {
+	if _, err := showTemplate("linux", "show version", ""); err == nil {
+		t.Error("expected an error for a command without a template")
+	}
+	if _, err := showTemplate("linux", "uname -a", `^\S+`); err == nil {
+		t.Error("expected an error for a template without named groups")
+	}
+	if _, err := showTemplate("linux", "uname -a", `^(?P<os>\S+)`); err != nil {
+		t.Errorf("unexpected error for a custom template: %v", err)
+	}
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// vkProfiles holds the named variable sets of a node, e.g. demo or scale,
+// the selected profile is merged over the other variables of the node.
+const vkProfiles = "profiles"
+
+// VarsOption configures the preparation of the node variables.
+type VarsOption func(*varsOptions)
+
+type varsOptions struct {
+	profile string
+}
+
+// WithProfile selects the variables profile merged over the variables of the nodes.
+func WithProfile(profile string) VarsOption {
+	return func(o *varsOptions) {
+		o.profile = profile
+	}
+}
+
+// applyProfile merges the variables of the profile over the config variables of the lab and external nodes
+// and removes the profiles from the variables. The profile must be declared by at least one node.
+func applyProfile(c *clabcore.CLab, profile string) error {
+	dispatchers := make(map[string]*clabtypes.ConfigDispatcher, len(c.Nodes)+len(c.Config.Topology.External))
+	for name, n := range c.Nodes {
+		dispatchers[name] = n.Config().Config
+	}
+	for name, ext := range c.Config.Topology.External {
+		if ext != nil {
+			dispatchers[name] = ext.Config
+		}
+	}
+
+	names := make([]string, 0, len(dispatchers))
+	for name := range dispatchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	found := false
+	for _, name := range names {
+		cd := dispatchers[name]
+		if cd == nil {
+			continue
+		}
+
+		vars, ok, err := profileVars(cd.Vars, profile)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		cd.Vars = vars
+		found = found || ok
+	}
+
+	if profile != "" && !found {
+		return fmt.Errorf("profile %q is not declared in the %s variables of any node", profile, vkProfiles)
+	}
+
+	return nil
+}
+
+// profileVars returns the variables without the profiles, with the variables of the profile merged over them.
+// ok is false when the variables do not declare the profile.
+func profileVars(vars map[string]interface{}, profile string) (res map[string]interface{}, ok bool, err error) {
+	profiles, declared := vars[vkProfiles]
+	if !declared {
+		return vars, false, nil
+	}
+
+	res = make(map[string]interface{}, len(vars))
+	for k, v := range vars {
+		if k != vkProfiles {
+			res[k] = v
+		}
+	}
+
+	if profile == "" || profiles == nil {
+		return res, false, nil
+	}
+
+	pm, isMap := jsonVars(profiles).(map[string]interface{})
+	if !isMap {
+		return nil, false, fmt.Errorf("the %s variable must map the profile names to variables", vkProfiles)
+	}
+
+	p, ok := pm[profile]
+	if !ok {
+		return res, false, nil
+	}
+
+	pv, isMap := p.(map[string]interface{})
+	if !isMap && p != nil {
+		return nil, false, fmt.Errorf("the variables of the profile %s must be a map", profile)
+	}
+
+	return clabutils.MergeMaps(res, pv), true, nil
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	clabcore "github.com/srl-labs/containerlab/core"
+)
+
+const profileTopo = `name: profile
+topology:
+  defaults:
+    config:
+      vars:
+        bgp:
+          asn: 65000
+          peers: 2
+        profiles:
+          scale:
+            bgp:
+              peers: 200
+  nodes:
+    srl1:
+      kind: nokia_srlinux
+    srl2:
+      kind: nokia_srlinux
+      config:
+        vars:
+          profiles:
+            demo:
+              banner: demo
+`
+
+func TestPrepareVarsProfile(t *testing.T) {
+	dir := t.TempDir()
+	topo := filepath.Join(dir, "profile.clab.yml")
+	if err := os.WriteFile(topo, []byte(profileTopo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	prepare := func(profile string) (map[string]*NodeConfig, error) {
+		c, err := clabcore.NewContainerLab(clabcore.WithTopoPath(topo, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return PrepareVars(c, WithProfile(profile))
+	}
+
+	res, err := prepare("scale")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []string{"srl1", "srl2"} {
+		bgp := jsonVars(res[n].Vars["bgp"]).(map[string]interface{})
+		if bgp["peers"] != 200 || bgp["asn"] != 65000 {
+			t.Errorf("%s: unexpected bgp vars %v", n, bgp)
+		}
+		if _, ok := res[n].Vars[vkProfiles]; ok {
+			t.Errorf("%s: the profiles are not removed from the vars", n)
+		}
+	}
+
+	res, err = prepare("demo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res["srl2"].Vars["banner"] != "demo" {
+		t.Errorf("unexpected srl2 vars %v", res["srl2"].Vars)
+	}
+	if _, ok := res["srl1"].Vars["banner"]; ok {
+		t.Errorf("the demo profile of srl2 is applied to srl1")
+	}
+
+	if _, err := prepare("staging"); err == nil {
+		t.Error("expected an error for an undeclared profile")
+	}
+}
@@ -0,0 +1,53 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// varsHash returns a short hash of the variables of a node, empty if they can't be hashed.
+func varsHash(vars map[string]interface{}) string {
+	b, err := json.Marshal(jsonVars(vars))
+	if err != nil {
+		return ""
+	}
+	h := sha256.Sum256(b)
+	return hex.EncodeToString(h[:])[:12]
+}
+
+// Comment returns the provenance of the snippet as a single line.
+func (m *SnippetMeta) Comment() string {
+	return fmt.Sprintf("rendered by containerlab from %s (%s), vars %s, at %s",
+		m.Template, m.Engine, m.VarsHash, m.Rendered.Format(time.RFC3339))
+}
+
+// AddProvenance prepends a provenance comment to every config snippet of the node
+// and enables the commit comments on the kinds supporting them.
+// The comment lines are not sent to the node.
+func (c *NodeConfig) AddProvenance() {
+	if c.provenance {
+		return
+	}
+	c.provenance = true
+
+	for i, m := range c.Meta {
+		c.Data[i] = "# " + m.Comment() + "\n" + c.Data[i]
+	}
+}
+
+// commitComments returns the commit comments of the snippets by their info.
+func (c *NodeConfig) commitComments() map[string]string {
+	if !c.provenance {
+		return nil
+	}
+
+	res := make(map[string]string, len(c.Meta))
+	for i, m := range c.Meta {
+		res[c.Info[i]] = strings.ReplaceAll(m.Comment(), `"`, `'`)
+	}
+	return res
+}
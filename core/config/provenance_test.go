@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestAddProvenance(t *testing.T) {
+	nc := &NodeConfig{
+		Data: []string{"set / system name host-name srl1"},
+		Info: []string{"base__srl.tmpl"},
+		Meta: []*SnippetMeta{{
+			Template: "base__srl.tmpl",
+			Engine:   engineGo,
+			VarsHash: varsHash(map[string]interface{}{vkNodeName: "srl1"}),
+			Rendered: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		}},
+	}
+
+	if nc.commitComments() != nil {
+		t.Error("expected no commit comments without provenance")
+	}
+
+	hash := nc.Meta[0].VarsHash
+	nc.AddProvenance()
+	nc.AddProvenance()
+
+	comment := "rendered by containerlab from base__srl.tmpl (go), vars " + hash + ", at 2025-01-02T03:04:05Z"
+	want := []string{"# " + comment + "\nset / system name host-name srl1"}
+	if d := cmp.Diff(want, nc.Data); d != "" {
+		t.Errorf("AddProvenance() mismatch (-want +got):\n%s", d)
+	}
+
+	if d := cmp.Diff(map[string]string{"base__srl.tmpl": comment}, nc.commitComments()); d != "" {
+		t.Errorf("commitComments() mismatch (-want +got):\n%s", d)
+	}
+
+	// comments don't change the snippet hash used to resume commits
+	if snippetHash(nc.Data[0]) != snippetHash("set / system name host-name srl1") {
+		t.Error("provenance comment changed the snippet hash")
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// redactMask replaces the secrets of the redacted config lines.
+const redactMask = "****"
+
+// defaultRedactPatterns match the secrets rendered into the config lines:
+// passwords, SNMP communities and the BGP/IGP authentication keys.
+// The first group of a pattern is masked, the whole match for patterns without groups.
+var defaultRedactPatterns = []string{ //nolint:gochecknoglobals
+	`(?i)\b(?:password|secret|auth-password|priv-password|authentication-key|pre-shared-key|md5)` +
+		`[ \t]+(?:\d[ \t]+)?("[^"]*"|\S+)`,
+	`(?i)\bcommunity[ \t]+("[^"]*"|\S+)`,
+	`(?i)"(?:password|secret|community|auth-password|priv-password|authentication-key)"[ \t]*:[ \t]*("[^"]*")`,
+}
+
+// Redactor masks the secrets of the config lines written to the console logs,
+// the transcripts and the reports of a config run. The config sent to the nodes is not redacted.
+// The methods of a nil Redactor return the text as is.
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor returns a Redactor masking the matches of the default patterns and of the extra patterns.
+func NewRedactor(extra []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, p := range append(slices.Clone(defaultRedactPatterns), extra...) {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", p, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+
+	return r, nil
+}
+
+// Redact returns s with the secrets masked.
+func (r *Redactor) Redact(s string) string {
+	if r == nil {
+		return s
+	}
+
+	for _, re := range r.patterns {
+		s = redactMatches(re, s)
+	}
+
+	return s
+}
+
+// redactMatches masks the first group of the matches of re in s, or the whole match.
+func redactMatches(re *regexp.Regexp, s string) string {
+	matches := re.FindAllStringSubmatchIndex(s, -1)
+	if len(matches) == 0 {
+		return s
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		start, end := m[0], m[1]
+		if len(m) > 3 && m[2] >= 0 {
+			start, end = m[2], m[3]
+		}
+
+		b.WriteString(s[last:start])
+		if strings.HasPrefix(s[start:end], `"`) {
+			b.WriteString(`"` + redactMask + `"`)
+		} else {
+			b.WriteString(redactMask)
+		}
+		last = end
+	}
+	b.WriteString(s[last:])
+
+	return b.String()
+}
+
+// RedactWriter is a writer masking the secrets of the lines written to the underlying writer.
+// The lines are written once complete, Flush writes the last incomplete line.
+type RedactWriter struct {
+	w   io.Writer
+	r   *Redactor
+	buf []byte
+}
+
+// Writer returns a RedactWriter writing to w.
+func (r *Redactor) Writer(w io.Writer) *RedactWriter {
+	return &RedactWriter{w: w, r: r}
+}
+
+// Write buffers p and writes its complete lines redacted.
+func (rw *RedactWriter) Write(p []byte) (int, error) {
+	rw.buf = append(rw.buf, p...)
+
+	i := bytes.LastIndexByte(rw.buf, '\n')
+	if i < 0 {
+		return len(p), nil
+	}
+
+	_, err := io.WriteString(rw.w, rw.r.Redact(string(rw.buf[:i+1])))
+	rw.buf = rw.buf[i+1:]
+
+	return len(p), err
+}
+
+// Flush writes the buffered incomplete line redacted.
+func (rw *RedactWriter) Flush() error {
+	if len(rw.buf) == 0 {
+		return nil
+	}
+
+	_, err := io.WriteString(rw.w, rw.r.Redact(string(rw.buf)))
+	rw.buf = nil
+
+	return err
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	r, err := NewRedactor([]string{`(?i)api-token=(\w+)`})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"set / system aaa authentication user admin password NokiaSrl1!": "set / system aaa authentication user admin password ****",
+		"username admin secret 0 arista":                                 "username admin secret 0 ****",
+		`snmp-server community "public" ro`:                              `snmp-server community "****" ro`,
+		"neighbor 10.0.0.1 authentication-key s3cr3t\nexit":              "neighbor 10.0.0.1 authentication-key ****\nexit",
+		`{"password": "admin", "name": "srl1"}`:                          `{"password": "****", "name": "srl1"}`,
+		"curl -H api-token=abc123":                                       "curl -H api-token=****",
+		"interface ethernet-1/1 admin-state enable":                      "interface ethernet-1/1 admin-state enable",
+	}
+	for in, want := range tests {
+		if got := r.Redact(in); got != want {
+			t.Errorf("Redact(%q) = %q, want %q", in, got, want)
+		}
+	}
+
+	var nilRedactor *Redactor
+	if got := nilRedactor.Redact("password admin"); got != "password admin" {
+		t.Errorf("nil redactor changed the text: %q", got)
+	}
+
+	if _, err := NewRedactor([]string{"("}); err == nil {
+		t.Error("expected an error for an invalid pattern")
+	}
+}
+
+func TestRedactWriter(t *testing.T) {
+	r, err := NewRedactor(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var b strings.Builder
+	w := r.Writer(&b)
+	for _, s := range []string{"user admin pass", "word admin\nsnmp commu", "nity public"} {
+		if _, err := w.Write([]byte(s)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := b.String(); got != "user admin password ****\n" {
+		t.Errorf("unexpected output before flush %q", got)
+	}
+
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.String(); got != "user admin password ****\nsnmp community ****" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
@@ -0,0 +1,302 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// Renderer renders the config templates of the nodes.
+// It doesn't depend on a lab, tools embedding the config engine only need to set
+// the target node and the variables of the nodes they render.
+type Renderer struct {
+	// Paths are the template paths, a path prefixed with jinja2: holds Jinja2 templates
+	// and a path prefixed with openconfig: holds OpenConfig intent files.
+	// Defaults to the embedded templates
+	Paths []string
+	// Names are the names of the templates rendered for every node,
+	// defaults to the names of all templates found in the paths
+	Names []string
+	// Workers is the number of nodes rendered in parallel, defaults to the number of CPUs
+	Workers int
+	// Hooks are the plugin hooks, the pre-render and post-render hooks run for every node
+	Hooks Hooks
+
+	engines []renderEngine
+}
+
+// RendererOption is a function to configure the renderer.
+type RendererOption func(*Renderer)
+
+// WithTemplatePaths sets the template paths of the renderer.
+func WithTemplatePaths(paths []string) RendererOption {
+	return func(r *Renderer) {
+		r.Paths = paths
+	}
+}
+
+// WithTemplateNames sets the names of the templates rendered for every node.
+func WithTemplateNames(names []string) RendererOption {
+	return func(r *Renderer) {
+		r.Names = names
+	}
+}
+
+// WithRenderWorkers sets the number of nodes rendered in parallel.
+func WithRenderWorkers(n int) RendererOption {
+	return func(r *Renderer) {
+		r.Workers = n
+	}
+}
+
+// WithRenderHooks sets the plugin hooks of the renderer.
+func WithRenderHooks(h Hooks) RendererOption {
+	return func(r *Renderer) {
+		r.Hooks = h
+	}
+}
+
+// NewRenderer creates a renderer with the render engines of its template paths.
+// When no template names are set, the names of the templates found in the paths are used.
+func NewRenderer(opts ...RendererOption) (*Renderer, error) {
+	r := &Renderer{}
+	for _, o := range opts {
+		o(r)
+	}
+
+	if len(r.Paths) == 0 { // default is the install path
+		r.Paths = []string{"@"}
+	}
+
+	var templateFS []fs.FS
+
+	r.engines = []renderEngine{&goEngine{paths: r.Paths}}
+
+	for _, v := range r.Paths {
+		engine, p := splitTemplatePath(v)
+		switch {
+		case p == "@":
+			templateFS = append(templateFS, embeddedTemplates)
+		case engine == engineJinja2:
+			templateFS = append(templateFS, os.DirFS(p))
+			r.engines = append(r.engines, &jinja2Engine{dir: p})
+		case engine == engineOpenConfig:
+			templateFS = append(templateFS, os.DirFS(p))
+			r.engines = append(r.engines, &openconfigEngine{dir: p})
+		default:
+			templateFS = append(templateFS, os.DirFS(p))
+		}
+	}
+
+	if len(r.Names) == 0 {
+		var err error
+		r.Names, err = GetTemplateNamesInDirs(templateFS)
+		if err != nil {
+			return nil, err
+		}
+		log.Infof("No template names specified (-l) using: %s", strings.Join(r.Names, ", "))
+	}
+
+	return r, nil
+}
+
+// Render renders the templates of all nodes, the nodes are rendered in parallel.
+// The nodes are scheduled in the order of their names and the error of the first node
+// in that order is returned, the other nodes are rendered regardless.
+func (r *Renderer) Render(nodes map[string]*NodeConfig) error {
+	names := make([]string, 0, len(nodes))
+	for n := range nodes {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	workers := r.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(nodes) {
+		workers = len(nodes)
+	}
+
+	idxCh := make(chan int)
+	errs := make([]error, len(names))
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for idx := range idxCh {
+				if err := r.RenderNode(nodes[names[idx]]); err != nil {
+					errs[idx] = fmt.Errorf("%s: %w", names[idx], err)
+				}
+			}
+		}()
+	}
+
+	for idx := range names {
+		idxCh <- idx
+	}
+	close(idxCh)
+
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RenderNode renders the templates of the node with the first engine having a template for its role.
+// The templates of the node's config take precedence over the template names of the renderer.
+func (r *Renderer) RenderNode(nc *NodeConfig) error {
+	if err := r.Hooks.Run(context.Background(), HookPreRender, nc); err != nil {
+		return err
+	}
+
+	vh := varsHash(nc.Vars)
+
+	if err := r.renderBootstrap(nc); err != nil {
+		return err
+	}
+
+	names := r.Names
+	if t := nc.TargetNode.Config.GetTemplates(); len(t) > 0 {
+		log.Debugf("%s: using the templates of the node: %s", nc.TargetNode.ShortName, strings.Join(t, ", "))
+		names = t
+	}
+
+	for _, baseN := range names {
+		if baseN == bootstrapTemplate {
+			continue
+		}
+
+		role := fmt.Sprintf("%s", nc.Vars[vkRole])
+
+		var eng renderEngine
+		var tmplN string
+		for _, e := range r.engines {
+			var err error
+			tmplN, err = e.Lookup(baseN, role)
+			if err != nil {
+				return err
+			}
+			if tmplN != "" {
+				eng = e
+				break
+			}
+		}
+		if eng == nil {
+			log.Debugf("No template found for %s; skipping..", nc.TargetNode.ShortName)
+			continue
+		}
+
+		var buf strings.Builder
+		err := eng.Render(&buf, tmplN, role, nc.Vars)
+		log.Debugf("Executed a template %s with an error code %v", tmplN, err)
+		if err != nil {
+			nc.Print(true, true)
+			return err
+		}
+		res := buf.String()
+		if eng.Name() == engineOpenConfig && res != "" {
+			res, err = openconfigSnippet(nc, res)
+			if err != nil {
+				return fmt.Errorf("%s: %w", tmplN, err)
+			}
+		}
+
+		data := strings.ReplaceAll(strings.Trim(res, "\n \t\r"), "\n\n\n", "\n\n")
+		if data == "" {
+			log.Debugf("Template %s rendered empty for %s; skipping..", tmplN, nc.TargetNode.ShortName)
+			continue
+		}
+		commit, err := commitMode(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", tmplN, err)
+		}
+		weight, err := snippetWeight(data)
+		if err != nil {
+			return fmt.Errorf("%s: %w", tmplN, err)
+		}
+		nc.Data = append(nc.Data, data)
+		nc.Info = append(nc.Info, tmplN)
+
+		// the undo template reverses the config on lab destroy
+		undoN, err := eng.Lookup(undoPrefix+baseN, role)
+		if err != nil {
+			return err
+		}
+		if undoN != "" {
+			var undo strings.Builder
+			if err := eng.Render(&undo, undoN, role, nc.Vars); err != nil {
+				return fmt.Errorf("%s: %w", undoN, err)
+			}
+			nc.Undo = append(nc.Undo, strings.Trim(undo.String(), "\n \t\r"))
+			nc.UndoInfo = append(nc.UndoInfo, undoN)
+		}
+		meta := &SnippetMeta{
+			Template: tmplN,
+			Engine:   eng.Name(),
+			VarsHash: vh,
+			Rendered: time.Now().UTC(),
+			Commit:   commit,
+			Weight:   weight,
+		}
+		// the OpenConfig intents are converted to the snippet, they are sent from the data
+		if eng.Name() != engineOpenConfig {
+			meta.render = func(w io.Writer) error {
+				return eng.Render(w, tmplN, role, nc.Vars)
+			}
+		}
+		nc.Meta = append(nc.Meta, meta)
+	}
+
+	nc.sortSnippets()
+	nc.dedupSnippets()
+
+	return r.Hooks.Run(context.Background(), HookPostRender, nc)
+}
+
+// LoadTemplates loads the Go templates of the role from the template paths.
+// Paths of other render engines are skipped.
+func LoadTemplates(tmpl *template.Template, paths []string, role string) error {
+	for _, v := range paths {
+		engine, p := splitTemplatePath(v)
+		if engine != engineGo {
+			continue
+		}
+		if p == "@" {
+			fn := fmt.Sprintf("templates/*__%s.tmpl", role)
+			_, err := tmpl.ParseFS(embeddedTemplates, fn)
+			if err != nil && !strings.Contains(err.Error(), "pattern matches no files") {
+				return fmt.Errorf("could not load the embedded templates %s: %w", fn, err)
+			}
+			continue
+		}
+		fn := filepath.Join(p, fmt.Sprintf("*__%s.tmpl", role))
+		_, err := tmpl.ParseGlob(fn)
+		if err != nil {
+			if strings.Contains(err.Error(), "pattern matches no file") {
+				log.Debug(err)
+				continue
+			}
+			return fmt.Errorf("could not load templates from %s: %w", fn, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestRenderer(t *testing.T) {
+	dir := t.TempDir()
+
+	for name, tmpl := range map[string]string{
+		"base__srl.tmpl":      "set / system name host-name {{ .clab_node }}",
+		"ntp__srl.tmpl":       "set / system ntp server {{ .ntp }}",
+		"undo-ntp__srl.tmpl":  "delete / system ntp server {{ .ntp }}",
+		"base__nokia_sros.j2": "unused",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(tmpl), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r, err := NewRenderer(WithTemplatePaths([]string{dir}), WithRenderWorkers(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the template names default to the templates found in the paths
+	if d := cmp.Diff([]string{"base", "ntp"}, r.Names); d != "" {
+		t.Errorf("template names mismatch (-want +got):\n%s", d)
+	}
+
+	nc := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "srl1"},
+		Vars:       map[string]interface{}{vkNodeName: "srl1", vkRole: "srl", "ntp": "192.0.2.1"},
+	}
+
+	if err := r.RenderNode(nc); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"set / system name host-name srl1", "set / system ntp server 192.0.2.1"}
+	if d := cmp.Diff(want, nc.Data); d != "" {
+		t.Errorf("rendered config mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"delete / system ntp server 192.0.2.1"}, nc.Undo); d != "" {
+		t.Errorf("rendered undo config mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestRenderFirstError(t *testing.T) {
+	dir := t.TempDir()
+
+	err := os.WriteFile(filepath.Join(dir, "base__srl.tmpl"), []byte(`{{ .clab_node.missing }}`), 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewRenderer(WithTemplatePaths([]string{dir}), WithRenderWorkers(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	nodes := make(map[string]*NodeConfig)
+	for _, n := range []string{"srl3", "srl1", "srl2"} {
+		nodes[n] = &NodeConfig{
+			TargetNode: &clabtypes.NodeConfig{ShortName: n},
+			Vars:       map[string]interface{}{vkNodeName: n, vkRole: "srl"},
+		}
+	}
+
+	// the error of the first node by name is returned, whatever node fails first
+	for i := 0; i < 5; i++ {
+		for _, nc := range nodes {
+			nc.Data, nc.Info, nc.Meta = nil, nil, nil
+		}
+		err := r.Render(nodes)
+		if err == nil || !strings.HasPrefix(err.Error(), "srl1: ") {
+			t.Fatalf("expected the error of srl1, got %v", err)
+		}
+	}
+}
+
+func TestRenderNetSvc(t *testing.T) {
+	r, err := NewRenderer(WithTemplateNames([]string{"netsvc"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		role   string
+		netsvc Dict
+		want   []string
+	}{
+		"srl": {
+			role:   "srl",
+			netsvc: Dict{"ntp": "172.20.20.9", "dns": "172.20.20.9", "domain": "dc.clab"},
+			want: []string{"# section: system\n/system ntp {\n    admin-state enable\n    network-instance mgmt\n" +
+				"    server 172.20.20.9 {\n    }\n}\n/system dns {\n    network-instance mgmt\n" +
+				"    server-list [ 172.20.20.9 ]\n    search-list [ dc.clab ]\n}"},
+		},
+		"sros ntp only": {
+			role:   "vr-sros",
+			netsvc: Dict{"ntp": "172.20.20.9", "dns": "", "domain": "dc.clab"},
+			want: []string{"# section: system\n/configure system time ntp admin-state enable\n" +
+				"/configure system time ntp server 172.20.20.9 router-instance \"management\""},
+		},
+		"no service":  {role: "srl", netsvc: Dict{}},
+		"no template": {role: "linux", netsvc: Dict{"ntp": "172.20.20.9", "dns": "", "domain": "dc.clab"}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			nc := &NodeConfig{
+				TargetNode: &clabtypes.NodeConfig{ShortName: "n1"},
+				Vars:       map[string]interface{}{vkNodeName: "n1", vkRole: tt.role, vkNetSvc: tt.netsvc},
+			}
+
+			if err := r.RenderNode(nc); err != nil {
+				t.Fatal(err)
+			}
+
+			if d := cmp.Diff(tt.want, nc.Data); d != "" {
+				t.Errorf("rendered config mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
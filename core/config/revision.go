@@ -0,0 +1,119 @@
+package config
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	clabgit "github.com/srl-labs/containerlab/git"
+)
+
+// ConfigDelta is the change of the rendered config of a node between two revisions of a lab.
+type ConfigDelta struct {
+	Node    string   `json:"node"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// LabRevision returns the git revision of the topology file and the template paths of a config run,
+// nil when the topology is not in a git repository.
+func LabRevision(topology string, templatePaths []string) *clabgit.Revision {
+	paths := []string{topology}
+	for _, v := range templatePaths {
+		if _, p := splitTemplatePath(v); p != "@" {
+			paths = append(paths, p)
+		}
+	}
+
+	return clabgit.LabRevision(paths...)
+}
+
+// TemplatePathsAt returns the template paths in the git repository root as the same paths
+// in the directory a revision of the repository is extracted to.
+// The embedded templates and the paths outside of the repository are kept.
+func TemplatePathsAt(templatePaths []string, root, dir string) []string {
+	res := make([]string, 0, len(templatePaths))
+
+	for _, v := range templatePaths {
+		engine, p := splitTemplatePath(v)
+		if p == "@" {
+			res = append(res, v)
+			continue
+		}
+
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			res = append(res, v)
+			continue
+		}
+
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			res = append(res, v)
+			continue
+		}
+
+		p = filepath.Join(dir, rel)
+		if strings.HasPrefix(v, engine+":") {
+			p = engine + ":" + p
+		}
+
+		res = append(res, p)
+	}
+
+	return res
+}
+
+// DiffRendered returns the changes of the rendered configs of the nodes from the old to the current
+// revision, sorted by node, the nodes without changes being omitted. The nodes are limited to
+// the given ones, when set. Empty lines and comments are ignored.
+func DiffRendered(old, cur map[string]*NodeConfig, nodes []string) []*ConfigDelta {
+	nodes = append([]string(nil), nodes...)
+	if len(nodes) == 0 {
+		seen := map[string]struct{}{}
+		for _, cfgs := range []map[string]*NodeConfig{old, cur} {
+			for n := range cfgs {
+				if _, ok := seen[n]; !ok {
+					seen[n] = struct{}{}
+					nodes = append(nodes, n)
+				}
+			}
+		}
+	}
+
+	sort.Strings(nodes)
+
+	var res []*ConfigDelta
+	for _, n := range nodes {
+		added, removed := diffLines(renderedConfig(old[n]), renderedConfig(cur[n]))
+		if len(added) == 0 && len(removed) == 0 {
+			continue
+		}
+
+		cs := cur[n]
+		if cs == nil {
+			cs = old[n]
+		}
+		added, removed = redactLines(cs, added), redactLines(cs, removed)
+
+		res = append(res, &ConfigDelta{Node: n, Added: added, Removed: removed})
+	}
+
+	return res
+}
+
+func renderedConfig(cs *NodeConfig) string {
+	if cs == nil {
+		return ""
+	}
+
+	return strings.Join(cs.Data, "\n")
+}
+
+func redactLines(cs *NodeConfig, lines []string) []string {
+	for i, l := range lines {
+		lines[i] = cs.Redactor.Redact(l)
+	}
+
+	return lines
+}
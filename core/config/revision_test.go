@@ -0,0 +1,85 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTemplatePathsAt(t *testing.T) {
+	tests := map[string]struct {
+		paths []string
+		want  []string
+	}{
+		"embedded": {
+			paths: []string{"@"},
+			want:  []string{"@"},
+		},
+		"in the repository": {
+			paths: []string{"/repo/templates", "/repo/lab/j2"},
+			want:  []string{"/tmp/rev/templates", "/tmp/rev/lab/j2"},
+		},
+		"engine prefix": {
+			paths: []string{"jinja2:/repo/templates"},
+			want:  []string{"jinja2:/tmp/rev/templates"},
+		},
+		"outside of the repository": {
+			paths: []string{"/srv/templates", "/repository/templates"},
+			want:  []string{"/srv/templates", "/repository/templates"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := TemplatePathsAt(tc.paths, "/repo", "/tmp/rev")
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("template paths mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestDiffRendered(t *testing.T) {
+	old := map[string]*NodeConfig{
+		"srl1": {Data: []string{"set / system name host-name srl1\nset / system ntp admin-state disable"}},
+		"srl2": {Data: []string{"set / system name host-name srl2"}},
+		"srl3": {Data: []string{"set / system name host-name srl3"}},
+	}
+	cur := map[string]*NodeConfig{
+		"srl1": {Data: []string{"set / system name host-name srl1\nset / system ntp admin-state enable"}},
+		"srl2": {Data: []string{"set / system name host-name srl2"}},
+		"srl4": {Data: []string{"set / system name host-name srl4"}},
+	}
+
+	tests := map[string]struct {
+		nodes []string
+		want  []*ConfigDelta
+	}{
+		"all nodes": {
+			want: []*ConfigDelta{
+				{
+					Node:    "srl1",
+					Added:   []string{"set / system ntp admin-state enable"},
+					Removed: []string{"set / system ntp admin-state disable"},
+				},
+				{Node: "srl3", Removed: []string{"set / system name host-name srl3"}},
+				{Node: "srl4", Added: []string{"set / system name host-name srl4"}},
+			},
+		},
+		"filtered nodes": {
+			nodes: []string{"srl4", "srl2"},
+			want: []*ConfigDelta{
+				{Node: "srl4", Added: []string{"set / system name host-name srl4"}},
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := DiffRendered(old, cur, tc.nodes)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("config delta mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
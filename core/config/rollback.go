@@ -0,0 +1,173 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// Rollback is the rollback of a config run. A checkpoint of the running configuration is saved
+// on every node before the config is sent, a failed run reverts all nodes to their checkpoints,
+// so that the nodes are either all configured or left as they were.
+type Rollback struct {
+	// Name of the checkpoints saved on the nodes
+	Name string
+
+	mu sync.Mutex
+	// saved are the nodes the checkpoint is saved on
+	saved map[string]*NodeConfig
+}
+
+// NewRollback returns the rollback of a config run of the lab, the checkpoints are named
+// after the lab and the start time of the run.
+func NewRollback(lab string, t time.Time) *Rollback {
+	return &Rollback{
+		Name:  fmt.Sprintf("clab-%s-%s", lab, t.UTC().Format("20060102T150405")),
+		saved: map[string]*NodeConfig{},
+	}
+}
+
+// CheckRollback returns an error listing the nodes whose config can't be rolled back,
+// the nodes configured with gNMI or with an SSH kind without config checkpoints.
+func CheckRollback(nodes []*NodeConfig) error {
+	var unsupported []string
+
+	for _, cs := range nodes {
+		k := transport.SSHKindFor(cs.TargetNode.Kind)
+		_, ok := k.(transport.CheckpointKind)
+		if !ok || configTransports(cs)[0] != transportSSH {
+			unsupported = append(unsupported, fmt.Sprintf("%s (%s)", cs.TargetNode.ShortName, cs.TargetNode.Kind))
+		}
+	}
+
+	if len(unsupported) > 0 {
+		sort.Strings(unsupported)
+		return fmt.Errorf("the config of the nodes can't be rolled back, no config checkpoints over SSH: %s",
+			strings.Join(unsupported, ", "))
+	}
+
+	return nil
+}
+
+// Save saves the checkpoint on the nodes concurrently.
+// When a node fails, the checkpoints saved on the other nodes are deleted.
+func (r *Rollback) Save(nodes []*NodeConfig) error {
+	err := r.each(nodes, func(cs *NodeConfig) error {
+		if err := runCheckpoint(cs, transport.CheckpointSave, r.Name); err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		r.saved[cs.TargetNode.ShortName] = cs
+		r.mu.Unlock()
+
+		log.Info("Saved the config checkpoint", "node", cs.TargetNode.ShortName, "checkpoint", r.Name)
+
+		return nil
+	})
+	if err != nil {
+		r.Release()
+	}
+
+	return err
+}
+
+// Revert reverts the nodes to their checkpoint and deletes it.
+// The nodes failing to revert keep their checkpoint to be reverted to manually.
+func (r *Rollback) Revert() error {
+	err := r.each(r.nodes(), func(cs *NodeConfig) error {
+		if err := runCheckpoint(cs, transport.CheckpointRevert, r.Name); err != nil {
+			return err
+		}
+
+		log.Info("Reverted the config to the checkpoint", "node", cs.TargetNode.ShortName, "checkpoint", r.Name)
+
+		r.mu.Lock()
+		delete(r.saved, cs.TargetNode.ShortName)
+		r.mu.Unlock()
+
+		if err := runCheckpoint(cs, transport.CheckpointDelete, r.Name); err != nil {
+			log.Warnf("%s: failed to delete the config checkpoint %s: %v", cs.TargetNode.ShortName, r.Name, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("the config of the nodes was not rolled back, revert them to the checkpoint %s: %w",
+			r.Name, err)
+	}
+
+	return nil
+}
+
+// Release deletes the checkpoints of the nodes, the config of the run is kept.
+func (r *Rollback) Release() {
+	_ = r.each(r.nodes(), func(cs *NodeConfig) error {
+		if err := runCheckpoint(cs, transport.CheckpointDelete, r.Name); err != nil {
+			log.Warnf("%s: failed to delete the config checkpoint %s: %v", cs.TargetNode.ShortName, r.Name, err)
+		}
+
+		r.mu.Lock()
+		delete(r.saved, cs.TargetNode.ShortName)
+		r.mu.Unlock()
+
+		return nil
+	})
+}
+
+// nodes returns the nodes the checkpoint is saved on.
+func (r *Rollback) nodes() []*NodeConfig {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]*NodeConfig, 0, len(r.saved))
+	for _, cs := range r.saved {
+		res = append(res, cs)
+	}
+
+	return res
+}
+
+// each runs fn on the nodes concurrently and returns the errors of the nodes in the order of their names.
+func (*Rollback) each(nodes []*NodeConfig, fn func(cs *NodeConfig) error) error {
+	nodes = append([]*NodeConfig(nil), nodes...)
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].TargetNode.ShortName < nodes[j].TargetNode.ShortName })
+
+	errs := make([]error, len(nodes))
+
+	var wg sync.WaitGroup
+	wg.Add(len(nodes))
+	for i, cs := range nodes {
+		go func() {
+			defer wg.Done()
+			if err := fn(cs); err != nil {
+				errs[i] = fmt.Errorf("%s: %w", cs.TargetNode.ShortName, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// runCheckpoint runs the operation on the named checkpoint of the node.
+func runCheckpoint(cs *NodeConfig, op, name string) error {
+	tx, err := newSSHTransport(cs)
+	if err != nil {
+		return err
+	}
+
+	err = tx.ConnectShow(transport.NodeHost(cs.TargetNode))
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	return tx.Checkpoint(op, name)
+}
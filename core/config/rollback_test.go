@@ -0,0 +1,53 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestNewRollback(t *testing.T) {
+	r := NewRollback("dc", time.Date(2026, 10, 15, 2, 30, 0, 0, time.UTC))
+	if r.Name != "clab-dc-20261015T023000" {
+		t.Errorf("unexpected checkpoint name %q", r.Name)
+	}
+}
+
+func TestCheckRollback(t *testing.T) {
+	node := func(name, kind string, labels map[string]string) *NodeConfig {
+		return &NodeConfig{TargetNode: &clabtypes.NodeConfig{ShortName: name, Kind: kind, Labels: labels}}
+	}
+
+	tests := map[string]struct {
+		nodes   []*NodeConfig
+		wantErr string
+	}{
+		"srl over ssh": {
+			nodes: []*NodeConfig{node("srl1", "nokia_srlinux", nil), node("srl2", "srl", nil)},
+		},
+		"gnmi and no checkpoints": {
+			nodes: []*NodeConfig{
+				node("srl1", "nokia_srlinux", map[string]string{"config.transport": "gnmi"}),
+				node("pe1", "nokia_sros", nil),
+				node("srl2", "nokia_srlinux", nil),
+			},
+			wantErr: "the config of the nodes can't be rolled back, no config checkpoints over SSH: " +
+				"pe1 (nokia_sros), srl1 (nokia_srlinux)",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := CheckRollback(tt.nodes)
+			got := ""
+			if err != nil {
+				got = err.Error()
+			}
+			if d := cmp.Diff(tt.wantErr, got); d != "" {
+				t.Errorf("error mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,307 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// routeTimeout is the time in seconds to wait for the route table of a node.
+const routeTimeout = 60
+
+// routeTableCommand is the show command listing the active routes of the default
+// network instance by kind.
+var routeTableCommand = map[string]string{ //nolint:gochecknoglobals
+	"srl":           "show network-instance default route-table",
+	"nokia_srlinux": "show network-instance default route-table",
+	"vr-sros":       "show router route-table",
+	"nokia_sros":    "show router route-table",
+	"nokia_srsim":   "show router route-table",
+	"srsim":         "show router route-table",
+}
+
+// routeTableParser parses the output of the route table show command by kind.
+var routeTableParser = map[string]func(string) map[string]*Route{ //nolint:gochecknoglobals
+	"srl":           parseSRLRoutes,
+	"nokia_srlinux": parseSRLRoutes,
+	"vr-sros":       parseSROSRoutes,
+	"nokia_sros":    parseSROSRoutes,
+	"nokia_srsim":   parseSROSRoutes,
+	"srsim":         parseSROSRoutes,
+}
+
+var (
+	// srosRouteRe matches the first line of a route of the SR OS route table:
+	// prefix with its flags, type and protocol.
+	srosRouteRe = regexp.MustCompile(`^(\S+/\d+)(?:\s*\[\S*\])?\s+(Local|Remote|Blackh\S*)\s+(\S+)`)
+	// srosNextHopRe matches the indented next hop line following a route of the SR OS route table.
+	srosNextHopRe = regexp.MustCompile(`^\s+(\S+(?:\s*\([^)]*\))?)\s+\d+\s*$`)
+)
+
+// Route is an active route of the route table of a node.
+type Route struct {
+	Prefix   string   `json:"prefix"`
+	Protocol string   `json:"protocol"`
+	NextHops []string `json:"next-hops,omitempty"`
+}
+
+// equal returns true if the routes have the same protocol and next hops.
+func (r *Route) equal(o *Route) bool {
+	if r.Protocol != o.Protocol || len(r.NextHops) != len(o.NextHops) {
+		return false
+	}
+	for i := range r.NextHops {
+		if r.NextHops[i] != o.NextHops[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// RouteSnapshot is the route table of a node at a point in time.
+type RouteSnapshot struct {
+	Node   string            `json:"node"`
+	Time   time.Time         `json:"time"`
+	Routes map[string]*Route `json:"routes"`
+}
+
+// Summary returns the number of active routes of the snapshot by protocol.
+func (s *RouteSnapshot) Summary() map[string]int {
+	res := map[string]int{}
+	for _, r := range s.Routes {
+		res[r.Protocol]++
+	}
+	return res
+}
+
+// RouteDelta is the difference between two route tables of a node.
+type RouteDelta struct {
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+	Changed []string `json:"changed,omitempty"`
+}
+
+// Empty returns true if the route tables are the same.
+func (d *RouteDelta) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffRoutes returns the prefixes added, removed and changed from the before to the after snapshot.
+func DiffRoutes(before, after *RouteSnapshot) *RouteDelta {
+	d := &RouteDelta{}
+
+	for p, r := range after.Routes {
+		b, ok := before.Routes[p]
+		switch {
+		case !ok:
+			d.Added = append(d.Added, p)
+		case !b.equal(r):
+			d.Changed = append(d.Changed, p)
+		}
+	}
+	for p := range before.Routes {
+		if _, ok := after.Routes[p]; !ok {
+			d.Removed = append(d.Removed, p)
+		}
+	}
+
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+
+	return d
+}
+
+// RoutesSupportKind returns true if the route table of the nodes of the kind can be read.
+func RoutesSupportKind(kind string) bool {
+	_, ok := routeTableCommand[kind]
+	return ok
+}
+
+// RouteReader reads the route table of a node over an SSH session kept open
+// between the snapshots, to take them in quick succession.
+type RouteReader struct {
+	cs *NodeConfig
+	tx *transport.SSHTransport
+}
+
+// NewRouteReader connects to the node to read its route table.
+func NewRouteReader(cs *NodeConfig) (*RouteReader, error) {
+	if !RoutesSupportKind(cs.TargetNode.Kind) {
+		return nil, fmt.Errorf("reading the route table is not implemented for kind %s", cs.TargetNode.Kind)
+	}
+
+	tx, err := newSSHTransport(cs)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.ConnectShow(transport.NodeHost(cs.TargetNode))
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouteReader{cs: cs, tx: tx}, nil
+}
+
+// Snapshot reads the active routes of the node.
+func (r *RouteReader) Snapshot() (*RouteSnapshot, error) {
+	kind := r.cs.TargetNode.Kind
+
+	out, err := r.tx.Show(routeTableCommand[kind], routeTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RouteSnapshot{
+		Node:   r.cs.TargetNode.ShortName,
+		Time:   time.Now().UTC(),
+		Routes: ParseRoutes(kind, out),
+	}, nil
+}
+
+// Close closes the SSH session of the reader.
+func (r *RouteReader) Close() {
+	r.tx.Close()
+}
+
+// ParseRoutes parses the output of the route table show command of a node of the kind by prefix.
+func ParseRoutes(kind, output string) map[string]*Route {
+	p, ok := routeTableParser[kind]
+	if !ok {
+		return map[string]*Route{}
+	}
+
+	return p(output)
+}
+
+// parseSRLRoutes parses the active routes of the SR Linux route table.
+// The headers and cells of the table are wrapped over several lines, the lines of a route
+// following its first line have an empty prefix cell.
+func parseSRLRoutes(output string) map[string]*Route {
+	res := map[string]*Route{}
+
+	var (
+		header  []string
+		inBody  bool
+		cols    map[string]int
+		current []string
+	)
+
+	cell := func(name string) string {
+		i, ok := cols[name]
+		if !ok || i >= len(current) {
+			return ""
+		}
+		return current[i]
+	}
+
+	flush := func() {
+		if current == nil {
+			return
+		}
+		defer func() { current = nil }()
+
+		if !strings.EqualFold(cell("Active"), "true") {
+			return
+		}
+
+		r := &Route{
+			Prefix:   cell("Prefix"),
+			Protocol: cell("Route Type"),
+		}
+		nh := cell("Next-hop (Type)")
+		if iface := cell("Next-hop Interface"); iface != "" {
+			nh += "@" + iface
+		}
+		if nh != "" {
+			r.NextHops = []string{nh}
+		}
+		res[r.Prefix] = r
+	}
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+
+		if strings.HasPrefix(line, "+=") {
+			cols = map[string]int{}
+			for i, h := range header {
+				cols[strings.Join(strings.Fields(h), " ")] = i
+			}
+			inBody = true
+			continue
+		}
+		if strings.HasPrefix(line, "+") {
+			flush()
+			continue
+		}
+		if !strings.HasPrefix(line, "|") {
+			// a table ends with the first line out of it
+			flush()
+			header, inBody = nil, false
+			continue
+		}
+
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+
+		if !inBody {
+			// the wrapped header fragments are joined with spaces
+			for i, c := range cells {
+				if i >= len(header) {
+					header = append(header, "")
+				}
+				header[i] = strings.TrimSpace(header[i] + " " + c)
+			}
+			continue
+		}
+
+		if cells[0] != "" {
+			flush()
+			current = cells
+			continue
+		}
+
+		// the wrapped cell fragments are joined as is
+		for i, c := range cells {
+			if i < len(current) {
+				current[i] += c
+			}
+		}
+	}
+	flush()
+
+	return res
+}
+
+// parseSROSRoutes parses the routes of the SR OS route table,
+// the next hop of a route is on the line following the route.
+func parseSROSRoutes(output string) map[string]*Route {
+	res := map[string]*Route{}
+
+	var current *Route
+	for _, line := range strings.Split(output, "\n") {
+		if m := srosRouteRe.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			current = &Route{Prefix: m[1], Protocol: strings.ToLower(m[3])}
+			res[current.Prefix] = current
+			continue
+		}
+
+		if current == nil {
+			continue
+		}
+		if m := srosNextHopRe.FindStringSubmatch(line); m != nil {
+			current.NextHops = append(current.NextHops, m[1])
+			continue
+		}
+		current = nil
+	}
+
+	return res
+}
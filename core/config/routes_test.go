@@ -0,0 +1,104 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRoutes(t *testing.T) {
+	tests := map[string]struct {
+		kind, output string
+		want         map[string]*Route
+	}{
+		"srl": {
+			kind: "nokia_srlinux",
+			output: `
+--------------------------------------------------------------------------------------------------------------------------
+IPv4 unicast route table of network instance default
+--------------------------------------------------------------------------------------------------------------------------
++----------------+------+-----------+--------------+----------+----------+---------+------------+----------------+----------------+
+|     Prefix     |  ID  |   Route   | Route Owner  |  Active  |  Origin  | Metric  |    Pref    |    Next-hop    |    Next-hop    |
+|                |      |   Type    |              |          | Network  |         |            |     (Type)     |   Interface    |
+|                |      |           |              |          | Instance |         |            |                |                |
++================+======+===========+==============+==========+==========+=========+============+================+================+
+| 10.0.0.1/32    | 0    | host      | net_inst_mgr | True     | default  | 0       | 0          | None (extract) | None           |
+| 10.0.0.2/32    | 0    | bgp       | bgp_mgr      | True     | default  | 0       | 170        | 10.1.1.2       | ethernet-1/1.0 |
+|                |      |           |              |          |          |         |            | (indirect/loca |                |
+|                |      |           |              |          |          |         |            | l)             |                |
+| 10.0.0.3/32    | 0    | bgp       | bgp_mgr      | False    | default  | 0       | 170        | 10.1.2.2       | ethernet-1/2.0 |
++----------------+------+-----------+--------------+----------+----------+---------+------------+----------------+----------------+
+IPv4 routes total                    : 3
+`,
+			want: map[string]*Route{
+				"10.0.0.1/32": {Prefix: "10.0.0.1/32", Protocol: "host", NextHops: []string{"None (extract)@None"}},
+				"10.0.0.2/32": {
+					Prefix: "10.0.0.2/32", Protocol: "bgp",
+					NextHops: []string{"10.1.1.2(indirect/local)@ethernet-1/1.0"},
+				},
+			},
+		},
+		"sros": {
+			kind: "nokia_sros",
+			output: `
+===============================================================================
+Route Table (Router: Base)
+===============================================================================
+Dest Prefix[Flags]                            Type    Proto     Age        Pref
+      Next Hop[Interface Name]                                    Metric
+-------------------------------------------------------------------------------
+10.0.0.1/32                                   Local   Local     00h05m12s  0
+       system                                                       0
+10.0.0.2/32 [L]                               Remote  ISIS      00h04m10s  18
+       10.1.2.2                                                     10
+       10.1.3.2                                                     10
+-------------------------------------------------------------------------------
+No. of Routes: 2
+`,
+			want: map[string]*Route{
+				"10.0.0.1/32": {Prefix: "10.0.0.1/32", Protocol: "local", NextHops: []string{"system"}},
+				"10.0.0.2/32": {Prefix: "10.0.0.2/32", Protocol: "isis", NextHops: []string{"10.1.2.2", "10.1.3.2"}},
+			},
+		},
+		"unsupported kind": {
+			kind:   "linux",
+			output: "10.0.0.0/24 dev eth1",
+			want:   map[string]*Route{},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ParseRoutes(tt.kind, tt.output)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("ParseRoutes() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestDiffRoutes(t *testing.T) {
+	before := &RouteSnapshot{Routes: map[string]*Route{
+		"10.0.0.1/32": {Prefix: "10.0.0.1/32", Protocol: "isis", NextHops: []string{"10.1.1.2"}},
+		"10.0.0.2/32": {Prefix: "10.0.0.2/32", Protocol: "isis", NextHops: []string{"10.1.1.2", "10.1.2.2"}},
+		"10.0.0.3/32": {Prefix: "10.0.0.3/32", Protocol: "isis", NextHops: []string{"10.1.1.2"}},
+	}}
+	after := &RouteSnapshot{Routes: map[string]*Route{
+		"10.0.0.1/32": {Prefix: "10.0.0.1/32", Protocol: "isis", NextHops: []string{"10.1.1.2"}},
+		"10.0.0.2/32": {Prefix: "10.0.0.2/32", Protocol: "isis", NextHops: []string{"10.1.2.2"}},
+		"10.0.0.4/32": {Prefix: "10.0.0.4/32", Protocol: "bgp", NextHops: []string{"10.1.2.2"}},
+	}}
+
+	want := &RouteDelta{
+		Added:   []string{"10.0.0.4/32"},
+		Removed: []string{"10.0.0.3/32"},
+		Changed: []string{"10.0.0.2/32"},
+	}
+	if d := cmp.Diff(want, DiffRoutes(before, after)); d != "" {
+		t.Errorf("DiffRoutes() mismatch (-want +got):\n%s", d)
+	}
+
+	if !DiffRoutes(after, after).Empty() {
+		t.Errorf("DiffRoutes() of the same snapshot is not empty")
+	}
+}
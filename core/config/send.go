@@ -1,50 +1,317 @@
 package config
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
 
+	"github.com/charmbracelet/log"
 	"github.com/srl-labs/containerlab/core/config/transport"
+	clabutils "github.com/srl-labs/containerlab/utils"
 )
 
-func Send(cs *NodeConfig, _ string) error {
-	var tx transport.Transport
-	var err error
+// passwordLabel is the node label with the password to log in with,
+// nodes forcing a password change on the first login get their password changed to it.
+const passwordLabel = "config.password"
+
+// discardTimeout limits the time spent discarding the candidate of a timed out node.
+const discardTimeout = 30 * time.Second
+
+// Transports of the config.transport label.
+const (
+	transportSSH     = "ssh"
+	transportGNMI    = "gnmi"
+	transportGRPC    = "grpc"
+	transportNETCONF = "netconf"
+)
+
+// probeTimeout limits the time spent probing a transport of the fallback chain.
+const probeTimeout = 5 * time.Second
+
+// Send writes the rendered config to the node.
+// The node's transports are tried in the order of the config.transport label,
+// a transport that is unreachable or can't write the config falls back to the next one.
+// The transport the config is sent with is recorded in the node config.
+func Send(ctx context.Context, cs *NodeConfig, _ string) error {
+	chain := configTransports(cs)
+	if len(chain) == 1 {
+		cs.Transport = chain[0]
+		return send(ctx, cs, chain[0])
+	}
+
+	var errs []string
+	for _, ct := range chain {
+		err := probeTransport(ctx, cs, ct)
+		if err != nil {
+			log.Debugf("%s: skipping the %s transport: %s", cs.TargetNode.ShortName, ct, err)
+			errs = append(errs, fmt.Sprintf("%s: %s", ct, err))
+			continue
+		}
+
+		if ct != chain[0] {
+			log.Infof("%s: falling back to the %s transport", cs.TargetNode.ShortName, ct)
+		}
 
-	ct, ok := cs.TargetNode.Labels["config.transport"]
-	if !ok {
-		ct = "ssh"
+		cs.Transport = ct
+		return send(ctx, cs, ct)
 	}
 
+	return &transport.ConnectError{Err: fmt.Errorf("no usable config transport, %s", strings.Join(errs, "; "))}
+}
+
+// send writes the rendered config to the node with the transport.
+// When the context is done before the config is written, the node is considered failed,
+// the session writing the config is aborted and the uncommitted changes are discarded.
+func send(ctx context.Context, cs *NodeConfig, ct string) error {
+	var sshTx *transport.SSHTransport
+	var err error
+
+	// chunks committed by a previous failed commit are skipped
+	var cp *checkpoint
+
 	switch ct {
-	case "ssh":
-		ssh_cred := cs.Credentials
+	case transportSSH:
+		sshTx, err = newSSHTransport(cs, transport.WithContext(ctx))
 		if err != nil {
 			return err
 		}
 
-		if len(ssh_cred) < 2 {
-			return fmt.Errorf("SSH credentials for node %s of type %s not found, cannot configure",
-				cs.TargetNode.ShortName, cs.TargetNode.Kind)
+		// external nodes have no lab dir created on deploy
+		clabutils.CreateDirectory(cs.TargetNode.LabDir, 0o755)
+
+		cp = loadCheckpoint(cs)
+		sshTx.Resume = cp.resume(cs, sshTx.ChunkSize)
+		sshTx.OnChunkCommit = func(info string, chunks int) {
+			cp.commit(cs, info, sshTx.ChunkSize, chunks)
 		}
-		tx, err = transport.NewSSHTransport(
-			cs.TargetNode,
-			transport.WithUserNamePassword(
-				ssh_cred[0],
-				ssh_cred[1]),
-			transport.HostKeyCallback(),
-		)
+		sshTx.Comments = cs.commitComments()
+		sshTx.CommitModes = cs.commitModes()
+
+		sshTx.ExpectBefore, sshTx.ExpectAfter, err = expectScripts(cs)
 		if err != nil {
 			return err
 		}
-	case "grpc":
-		// NewGRPCTransport
+	case transportGNMI:
+		return sendGNMI(ctx, cs)
+	case transportGRPC, transportNETCONF:
+		return fmt.Errorf("config writes over the %s transport are not implemented", ct)
 	default:
 		return fmt.Errorf("unknown transport: %s", ct)
 	}
 
-	err = transport.Write(tx, cs.TargetNode.LongName, cs.Data, cs.Info)
+	// the write runs in a goroutine, so that a hung session doesn't block the caller
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- writeConfig(sshTx, transport.NodeHost(cs.TargetNode), cs)
+	}()
+
+	select {
+	case err = <-errCh:
+		if sshTx != nil {
+			cs.Banner = sshTx.Banner
+		}
+	case <-ctx.Done():
+		// the session is closed and the write is waited for, so that the timed out write
+		// can't commit after its changes are discarded with a new session
+		sshTx.Abort()
+		<-errCh
+		err = ctx.Err()
+	}
+
+	if err != nil && ctx.Err() != nil {
+		if ct == transportSSH {
+			discard(cs)
+		}
+		return fmt.Errorf("config not applied in time: %w", ctx.Err())
+	}
+
 	if err != nil {
 		return err
 	}
+
+	if cp != nil {
+		cp.clear()
+	}
+
 	return nil
 }
+
+// writeConfig writes the snippets of the node with the SSH transport.
+// The snippets are streamed to the node, see writeSnippet.
+func writeConfig(tx *transport.SSHTransport, host string, cs *NodeConfig) error {
+	if err := tx.Connect(host); err != nil {
+		return &transport.ConnectError{Err: fmt.Errorf("%s: %s", host, err)}
+	}
+	defer tx.Close()
+
+	for i := range cs.Info {
+		if err := cs.writeSnippet(tx, i); err != nil {
+			return fmt.Errorf("could not write config %s: %s", cs.Info[i], err)
+		}
+	}
+
+	if err := tx.Flush(); err != nil {
+		return fmt.Errorf("could not commit the deferred config: %s", err)
+	}
+
+	return nil
+}
+
+// writeSnippet writes the snippet i of the node with the SSH transport.
+// The template of the snippet is rendered again into a pipe read by the transport,
+// the lines are sent while the template is executed and the config is never held as a whole.
+// The snippets that differ from the output of their template are written from the rendered data.
+func (c *NodeConfig) writeSnippet(tx *transport.SSHTransport, i int) error {
+	if i >= len(c.Meta) || c.Meta[i].render == nil {
+		return tx.WriteFrom(strings.NewReader(c.Data[i]), c.Info[i])
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(c.Meta[i].render(pw))
+	}()
+
+	err := tx.WriteFrom(pr, c.Info[i])
+	// stops the template when the write fails before the template is read to the end
+	pr.CloseWithError(err)
+
+	return err
+}
+
+// configTransports returns the transports used to configure the node in the order of preference,
+// set as a comma separated list in the config.transport label, e.g. gnmi,netconf,ssh.
+func configTransports(cs *NodeConfig) []string {
+	var chain []string
+	for _, ct := range strings.Split(cs.TargetNode.Labels["config.transport"], ",") {
+		if ct = strings.TrimSpace(ct); ct != "" {
+			chain = append(chain, ct)
+		}
+	}
+	if len(chain) == 0 {
+		chain = []string{transportSSH}
+	}
+	return chain
+}
+
+// probeTransport returns an error when the transport can't configure the node,
+// either because its config writes are not implemented or its port is unreachable.
+func probeTransport(ctx context.Context, cs *NodeConfig, ct string) error {
+	var port string
+	switch ct {
+	case transportSSH:
+		if !transport.SSHSupportsKind(cs.TargetNode.Kind) {
+			return fmt.Errorf("no transport implemented for kind %s", cs.TargetNode.Kind)
+		}
+		port = "22"
+		if p, ok := cs.TargetNode.Labels["config.ssh.port"]; ok {
+			port = p
+		}
+	case transportGNMI:
+		port = strconv.Itoa(transport.DefaultGNMIPort)
+		if p, ok := cs.TargetNode.Labels["config.gnmi.port"]; ok {
+			port = p
+		}
+	default:
+		return fmt.Errorf("config writes are not implemented")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, probeTimeout)
+	defer cancel()
+
+	return cs.Dialer.Reachable(ctx, net.JoinHostPort(transport.NodeHost(cs.TargetNode), port))
+}
+
+// Unsupported returns the reason why the node can't be configured,
+// or an empty string if the node has rendered config and a transport for its kind.
+func (c *NodeConfig) Unsupported() string {
+	if len(c.Data) == 0 {
+		return fmt.Sprintf("no templates found for role %v", c.Vars[vkRole])
+	}
+
+	for _, ct := range configTransports(c) {
+		if ct != transportSSH || transport.SSHSupportsKind(c.TargetNode.Kind) {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("no transport implemented for kind %s", c.TargetNode.Kind)
+}
+
+// discard discards the uncommitted changes of the node using a new session.
+func discard(cs *NodeConfig) {
+	errCh := make(chan error, 1)
+	go func() {
+		tx, err := newSSHTransport(cs)
+		if err != nil {
+			errCh <- err
+			return
+		}
+
+		err = tx.Connect(transport.NodeHost(cs.TargetNode))
+		if err != nil {
+			errCh <- err
+			return
+		}
+		defer tx.Close()
+
+		errCh <- tx.Discard()
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			log.Warnf("%s: failed to discard the candidate config: %s", cs.TargetNode.ShortName, err)
+			return
+		}
+		log.Infof("%s: discarded the candidate config", cs.TargetNode.ShortName)
+	case <-time.After(discardTimeout):
+		log.Warnf("%s: timed out discarding the candidate config", cs.TargetNode.ShortName)
+	}
+}
+
+// newSSHTransport creates the SSH transport for the node using the node's credentials,
+// the credentials of the matching profile of the credentials store replacing the kind credentials.
+func newSSHTransport(cs *NodeConfig, options ...transport.SSHTransportOption) (*transport.SSHTransport, error) {
+	ssh_cred, err := cs.sshCredentials()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ssh_cred) < 2 {
+		return nil, fmt.Errorf("SSH credentials for node %s of type %s not found, cannot configure",
+			cs.TargetNode.ShortName, cs.TargetNode.Kind)
+	}
+
+	options = append([]transport.SSHTransportOption{
+		transport.WithUserNamePassword(
+			ssh_cred[0],
+			ssh_cred[1]),
+		transport.HostKeyCallback(),
+		transport.WithDebug(cs.DebugCount),
+	}, options...)
+
+	if pw, ok := cs.TargetNode.Labels[passwordLabel]; ok && !cs.rotated {
+		options = append(options, transport.WithNewPassword(pw))
+	}
+	if cs.Dialer != nil {
+		options = append(options, transport.WithDialer(cs.Dialer))
+	}
+	if cs.Bootstrap != nil {
+		options = append(options, transport.WithBootstrap(cs.Bootstrap))
+	}
+	if cs.Transcript != nil {
+		options = append(options, transport.WithTranscript(cs.Transcript))
+	}
+	if cs.Session != nil {
+		options = append(options, transport.WithSessionRecorder(cs.Session))
+	}
+	if cs.Redactor != nil {
+		options = append(options, transport.WithRedaction(cs.Redactor.Redact))
+	}
+
+	return transport.NewSSHTransport(cs.TargetNode, options...)
+}
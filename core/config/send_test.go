@@ -0,0 +1,325 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"golang.org/x/crypto/ssh"
+
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestUnsupported(t *testing.T) {
+	tests := map[string]struct {
+		kind   string
+		labels map[string]string
+		data   []string
+		want   string
+	}{
+		"supported": {
+			kind: "nokia_srlinux",
+			data: []string{"set / system"},
+		},
+		"no templates": {
+			kind: "nokia_srlinux",
+			want: "no templates found for role nokia_srlinux",
+		},
+		"no transport": {
+			kind: "linux",
+			data: []string{"ip link"},
+			want: "no transport implemented for kind linux",
+		},
+		"non-ssh transport": {
+			kind:   "linux",
+			labels: map[string]string{"config.transport": "grpc"},
+			data:   []string{"ip link"},
+		},
+		"ssh only fallback": {
+			kind:   "linux",
+			labels: map[string]string{"config.transport": "ssh, ssh"},
+			data:   []string{"ip link"},
+			want:   "no transport implemented for kind linux",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cs := &NodeConfig{
+				TargetNode: &clabtypes.NodeConfig{Kind: tc.kind, Labels: tc.labels},
+				Vars:       map[string]interface{}{vkRole: tc.kind},
+				Data:       tc.data,
+			}
+			if got := cs.Unsupported(); got != tc.want {
+				t.Errorf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSnippetHash(t *testing.T) {
+	data := "# provenance comment\n  set a  \n\n\tset b\n"
+
+	want := sha256.Sum256([]byte("set a\nset b"))
+	if got := snippetHash(data); got != hex.EncodeToString(want[:]) {
+		t.Errorf("snippetHash() = %s, want the hash of the config lines", got)
+	}
+
+	if snippetHash(data) != snippetHash("set a\nset b") {
+		t.Error("comments and blank lines changed the snippet hash")
+	}
+}
+
+func TestConfigTransports(t *testing.T) {
+	tests := map[string]struct {
+		label string
+		want  []string
+	}{
+		"default":  {want: []string{"ssh"}},
+		"single":   {label: "gnmi", want: []string{"gnmi"}},
+		"chain":    {label: "gnmi, netconf,ssh", want: []string{"gnmi", "netconf", "ssh"}},
+		"empty":    {label: " , ", want: []string{"ssh"}},
+		"trailing": {label: "gnmi,ssh,", want: []string{"gnmi", "ssh"}},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			cs := &NodeConfig{TargetNode: &clabtypes.NodeConfig{Labels: map[string]string{}}}
+			if tc.label != "" {
+				cs.TargetNode.Labels["config.transport"] = tc.label
+			}
+			if d := cmp.Diff(tc.want, configTransports(cs)); d != "" {
+				t.Errorf("unexpected transports (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSendFallback(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+
+	l2, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, closed, _ := net.SplitHostPort(l2.Addr().String())
+	l2.Close()
+
+	newNode := func(port string) *NodeConfig {
+		return &NodeConfig{
+			TargetNode: &clabtypes.NodeConfig{
+				ShortName: "srl1",
+				Kind:      "nokia_srlinux",
+				LabDir:    t.TempDir(),
+				Labels: map[string]string{
+					"config.transport": "gnmi,netconf,ssh",
+					"config.address":   "127.0.0.1",
+					"config.ssh.port":  port,
+					"config.gnmi.port": closed,
+				},
+			},
+			Data: []string{"set / system"},
+			Info: []string{"srl"},
+		}
+	}
+
+	// ssh is reachable, the send fails on the missing credentials
+	cs := newNode(port)
+	err = Send(context.Background(), cs, "commit")
+	if err == nil || !strings.Contains(err.Error(), "credentials") {
+		t.Errorf("expected the ssh transport to be used, got %v", err)
+	}
+	if cs.Transport != "ssh" {
+		t.Errorf("Transport = %q, want ssh", cs.Transport)
+	}
+
+	// no transport of the chain is usable
+	cs = newNode(closed)
+	err = Send(context.Background(), cs, "commit")
+	if err == nil || !strings.Contains(err.Error(), "no usable config transport") {
+		t.Errorf("expected no usable transport, got %v", err)
+	}
+	if cs.Transport != "" {
+		t.Errorf("Transport = %q, want none", cs.Transport)
+	}
+}
+
+// srlNodePrompt ends the replies of the fake SR Linux node.
+const srlNodePrompt = "\r\n--{ running }--[  ]--\r\nA:srl1# "
+
+// fakeSRLNode serves an SR Linux CLI over SSH on a local port and records the commands it receives.
+func fakeSRLNode(t *testing.T) (port string, commands func() []string) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &ssh.ServerConfig{
+		PasswordCallback: func(ssh.ConnMetadata, []byte) (*ssh.Permissions, error) { return nil, nil },
+	}
+	cfg.AddHostKey(signer)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	_, port, _ = net.SplitHostPort(l.Addr().String())
+
+	var (
+		mu   sync.Mutex
+		cmds []string
+	)
+
+	serve := func(ch ssh.Channel) {
+		defer ch.Close()
+
+		io.WriteString(ch, "Welcome"+srlNodePrompt)
+
+		r := bufio.NewReader(ch)
+		for {
+			cmd, err := r.ReadString('\r')
+			if err != nil {
+				return
+			}
+			cmd = strings.TrimSuffix(cmd, "\r")
+
+			mu.Lock()
+			cmds = append(cmds, cmd)
+			mu.Unlock()
+
+			var res string
+			switch {
+			case strings.HasPrefix(cmd, "commit now"):
+				res = "\r\nAll changes have been committed. Leaving candidate mode."
+			case cmd == "discard stay":
+				res = "\r\nNothing to discard"
+			}
+			io.WriteString(ch, cmd+res+srlNodePrompt)
+		}
+	}
+
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+				if err != nil {
+					return
+				}
+				go ssh.DiscardRequests(reqs)
+
+				for nc := range chans {
+					ch, chReqs, err := nc.Accept()
+					if err != nil {
+						return
+					}
+					go func() {
+						for req := range chReqs {
+							req.Reply(req.Type == "pty-req" || req.Type == "shell", nil)
+							if req.Type == "shell" {
+								go serve(ch)
+							}
+						}
+					}()
+				}
+			}()
+		}
+	}()
+
+	return port, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), cmds...)
+	}
+}
+
+func TestSendStreamsTemplate(t *testing.T) {
+	dir := t.TempDir()
+	tmpl := "{{ range .ifaces }}set / interface ethernet-1/{{ . }} admin-state enable\n{{ end }}"
+	if err := os.WriteFile(filepath.Join(dir, "ifaces__srl.tmpl"), []byte(tmpl), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var ifaces []int
+	var want []string
+	for i := 1; i <= 95; i++ {
+		ifaces = append(ifaces, i)
+		want = append(want, fmt.Sprintf("set / interface ethernet-1/%d admin-state enable", i))
+	}
+
+	port, commands := fakeSRLNode(t)
+
+	cs := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{
+			ShortName: "srl1",
+			Kind:      "nokia_srlinux",
+			LabDir:    t.TempDir(),
+			Labels: map[string]string{
+				"config.address":    "127.0.0.1",
+				"config.ssh.port":   port,
+				"config.chunk-size": "10",
+			},
+		},
+		Credentials: []string{"admin", "admin"},
+		Vars:        map[string]interface{}{vkNodeName: "srl1", vkRole: "srl", "ifaces": ifaces},
+	}
+
+	r, err := NewRenderer(WithTemplatePaths([]string{dir}), WithTemplateNames([]string{"ifaces"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := r.RenderNode(cs); err != nil {
+		t.Fatal(err)
+	}
+
+	// the template is rendered again into the pipe read by the transport, not sent from the data
+	cs.Data[0] = ""
+
+	if err := Send(context.Background(), cs, "commit"); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	commits := 0
+	for _, c := range commands() {
+		switch {
+		case strings.HasPrefix(c, "set / interface"):
+			got = append(got, c)
+		case strings.HasPrefix(c, "commit now"):
+			commits++
+		}
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("config lines mismatch (-want +got):\n%s", d)
+	}
+	if commits != 10 {
+		t.Errorf("want the 95 lines committed in 10 chunks of 10 lines, got %d commits", commits)
+	}
+}
@@ -0,0 +1,209 @@
+package config
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// Kinds of the session log events.
+const (
+	SessionSent     = "sent"
+	SessionReceived = "received"
+	SessionError    = "error"
+)
+
+// sessionSentinelRe matches the echo of the sentinel comments delimiting the replies of the SSH transport.
+var sessionSentinelRe = regexp.MustCompile(`(?m)^.*<clab-sentinel-\d+>.*(\r?\n|$)`)
+
+// SessionEvent is an event of the config session of a node: a command sent to the node,
+// the output received from the node until the next command, or the error ending the session.
+type SessionEvent struct {
+	Time time.Time `json:"time"`
+	Node string    `json:"node"`
+	Kind string    `json:"kind"`
+	Data string    `json:"data"`
+}
+
+// SessionLog records the config session of a node as a timeline of events written as JSON lines.
+// The output received is recorded as one event per command, timed when its first byte is received.
+// The secrets of the events are masked with the Redactor of the node.
+// The methods of a nil SessionLog do nothing.
+type SessionLog struct {
+	mu       sync.Mutex
+	node     string
+	redactor *Redactor
+	enc      *json.Encoder
+	// received is the output received since the last command, not written yet
+	received *SessionEvent
+	err      error
+}
+
+// NewSessionLog returns a SessionLog of the node writing to w.
+func NewSessionLog(w io.Writer, node string, redactor *Redactor) *SessionLog {
+	return &SessionLog{
+		node:     node,
+		redactor: redactor,
+		enc:      json.NewEncoder(w),
+	}
+}
+
+// Sent records the command sent to the node.
+func (s *SessionLog) Sent(command string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flush()
+	s.write(&SessionEvent{Time: time.Now(), Kind: SessionSent, Data: command})
+}
+
+// Received records the output received from the node.
+func (s *SessionLog) Received(output string) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.received == nil {
+		s.received = &SessionEvent{Time: time.Now(), Kind: SessionReceived}
+	}
+	s.received.Data += output
+}
+
+// Error records the error ending the session, a nil error is not recorded.
+func (s *SessionLog) Error(err error) {
+	if s == nil || err == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flush()
+	s.write(&SessionEvent{Time: time.Now(), Kind: SessionError, Data: err.Error()})
+}
+
+// Flush writes the output received since the last command and returns the first write error.
+func (s *SessionLog) Flush() error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.flush()
+
+	return s.err
+}
+
+func (s *SessionLog) flush() {
+	ev := s.received
+	s.received = nil
+
+	if ev == nil {
+		return
+	}
+
+	ev.Data = sessionSentinelRe.ReplaceAllString(ev.Data, "")
+	if strings.TrimSpace(ev.Data) == "" {
+		return
+	}
+
+	s.write(ev)
+}
+
+func (s *SessionLog) write(ev *SessionEvent) {
+	ev.Node = s.node
+	ev.Data = s.redactor.Redact(ev.Data)
+
+	if err := s.enc.Encode(ev); err != nil && s.err == nil {
+		s.err = err
+	}
+}
+
+// ReadSessionLog returns the events of the session log file.
+func ReadSessionLog(path string) ([]*SessionEvent, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var res []*SessionEvent
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+
+		ev := &SessionEvent{}
+		if err := json.Unmarshal(sc.Bytes(), ev); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid session event: %w", path, line, err)
+		}
+
+		res = append(res, ev)
+	}
+
+	return res, sc.Err()
+}
+
+// LoadSessions returns the events of the session logs of a config run ordered by time,
+// the path being a session log file, the node directory of the artifacts holding one,
+// or the artifacts directory of the run.
+func LoadSessions(path string) ([]*SessionEvent, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{path}
+	if fi.IsDir() {
+		files, err = filepath.Glob(filepath.Join(path, artifactsNodesDirName, "*", artifactsSessionFileName))
+		if err != nil {
+			return nil, err
+		}
+
+		if p := filepath.Join(path, artifactsSessionFileName); clabutils.FileExists(p) {
+			files = append(files, p)
+		}
+
+		if len(files) == 0 {
+			return nil, fmt.Errorf("%w: no session logs found in %s", fs.ErrNotExist, path)
+		}
+	}
+
+	var res []*SessionEvent
+
+	for _, f := range files {
+		evs, err := ReadSessionLog(f)
+		if err != nil {
+			return nil, err
+		}
+
+		res = append(res, evs...)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool { return res[i].Time.Before(res[j].Time) })
+
+	return res, nil
+}
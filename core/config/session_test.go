@@ -0,0 +1,123 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+)
+
+func TestSessionLog(t *testing.T) {
+	r, err := NewRedactor(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	s := NewSessionLog(&buf, "srl1", r)
+
+	s.Received("Welcome to srl1\r\n")
+	s.Received("A:srl1# ")
+	s.Sent("set / system aaa authentication user admin password NokiaSrl1!")
+	s.Received("# <clab-sentinel-1>\n")
+	s.Sent("commit now")
+	s.Received("All changes have been committed.\n")
+	s.Received("# <clab-sentinel-2>")
+	s.Error(errors.New("commit failed"))
+	s.Error(nil)
+
+	if err := s.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	p := filepath.Join(t.TempDir(), "session.jsonl")
+	if err := os.WriteFile(p, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadSessionLog(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*SessionEvent{
+		{Node: "srl1", Kind: SessionReceived, Data: "Welcome to srl1\r\nA:srl1# "},
+		{Node: "srl1", Kind: SessionSent, Data: "set / system aaa authentication user admin password ****"},
+		{Node: "srl1", Kind: SessionSent, Data: "commit now"},
+		{Node: "srl1", Kind: SessionReceived, Data: "All changes have been committed.\n"},
+		{Node: "srl1", Kind: SessionError, Data: "commit failed"},
+	}
+
+	if d := cmp.Diff(want, got, cmpopts.IgnoreFields(SessionEvent{}, "Time")); d != "" {
+		t.Errorf("session events mismatch (-want +got):\n%s", d)
+	}
+
+	var nilLog *SessionLog
+	nilLog.Sent("show version")
+	nilLog.Received("output")
+	nilLog.Error(errors.New("failed"))
+	if err := nilLog.Flush(); err != nil {
+		t.Errorf("nil session log flush error: %v", err)
+	}
+}
+
+func TestLoadSessions(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Date(2025, 5, 12, 10, 0, 0, 0, time.UTC)
+
+	write := func(node string, events ...*SessionEvent) {
+		nodeDir := filepath.Join(dir, artifactsNodesDirName, node)
+		if err := os.MkdirAll(nodeDir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		s := NewSessionLog(&buf, node, nil)
+		for _, ev := range events {
+			s.write(ev)
+		}
+
+		if err := os.WriteFile(filepath.Join(nodeDir, artifactsSessionFileName), buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("srl1",
+		&SessionEvent{Time: start, Kind: SessionSent, Data: "a"},
+		&SessionEvent{Time: start.Add(2 * time.Second), Kind: SessionSent, Data: "c"},
+	)
+	write("srl2",
+		&SessionEvent{Time: start.Add(time.Second), Kind: SessionSent, Data: "b"},
+	)
+
+	events, err := LoadSessions(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, ev := range events {
+		got = append(got, ev.Node+":"+ev.Data)
+	}
+
+	if d := cmp.Diff([]string{"srl1:a", "srl2:b", "srl1:c"}, got); d != "" {
+		t.Errorf("session events order mismatch (-want +got):\n%s", d)
+	}
+
+	events, err = LoadSessions(filepath.Join(dir, artifactsNodesDirName, "srl2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Errorf("expected the events of the node dir, got %d", len(events))
+	}
+
+	if _, err := LoadSessions(t.TempDir()); err == nil {
+		t.Error("expected an error without session logs")
+	}
+}
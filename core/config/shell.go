@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// shellTimeout is the time in seconds to wait for the reply to a shell command.
+const shellTimeout = 10
+
+// shellHelp lists the shell commands handled by containerlab instead of being sent to the node.
+const shellHelp = `!start     start a config transaction, as done before sending a config snippet
+!commit    commit the config transaction, reporting the errors detected by the kind
+!discard   discard the uncommitted changes
+!running   show the running configuration
+!help      show this help
+!exit      close the session`
+
+// Shell opens the config session of the node and sends the lines read from in to the node,
+// writing the replies to out. The commands are sent by the kind-aware transport,
+// exactly as the lines of a rendered template, so template authors can try them interactively.
+func Shell(ctx context.Context, cs *NodeConfig, in io.Reader, out io.Writer) error {
+	if !slices.Contains(configTransports(cs), transportSSH) {
+		return fmt.Errorf("%s: the shell needs the ssh config transport, got %s",
+			cs.TargetNode.ShortName, cs.TargetNode.Labels["config.transport"])
+	}
+	if !transport.SSHSupportsKind(cs.TargetNode.Kind) {
+		return fmt.Errorf("%s: no SSH transport for kind %s", cs.TargetNode.ShortName, cs.TargetNode.Kind)
+	}
+
+	tx, err := newSSHTransport(cs, transport.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	err = tx.Connect(transport.NodeHost(cs.TargetNode))
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	prompt := cs.TargetNode.ShortName + "#"
+	if tx.Banner != "" {
+		fmt.Fprintln(out, tx.Banner)
+	}
+	if tx.LoginMessage != nil {
+		if p := tx.LoginMessage.Prompt(); p != "" {
+			prompt = p
+		}
+	}
+
+	fmt.Fprintln(out, "type !help for the shell commands")
+
+	s := bufio.NewScanner(in)
+	for {
+		fmt.Fprintf(out, "%s ", prompt)
+
+		if !s.Scan() {
+			fmt.Fprintln(out)
+			return s.Err()
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		line := strings.TrimSpace(s.Text())
+
+		switch line {
+		case "":
+			continue
+		case "!exit", "!quit":
+			return nil
+		case "!help":
+			fmt.Fprintln(out, shellHelp)
+		case "!start":
+			err = tx.K.ConfigStart(tx, true)
+		case "!commit":
+			var r *transport.SSHReply
+			r, err = tx.K.ConfigCommit(tx)
+			if r != nil && r.Result() != "" {
+				fmt.Fprintln(out, r.Result())
+			}
+		case "!discard":
+			err = tx.K.ConfigDiscard(tx)
+		case "!running":
+			var cfg string
+			cfg, err = tx.RunningConfig()
+			fmt.Fprintln(out, cfg)
+		default:
+			r := tx.Run(line, shellTimeout)
+			if r.Result() != "" {
+				fmt.Fprintln(out, r.Result())
+			}
+			if r.Prompt() == "" {
+				err = fmt.Errorf("timeout waiting for the prompt")
+			} else {
+				prompt = r.Prompt()
+			}
+		}
+
+		if err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+			err = nil
+		}
+	}
+}
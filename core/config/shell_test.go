@@ -0,0 +1,35 @@
+package config
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestShellUnsupported(t *testing.T) {
+	tests := map[string]*clabtypes.NodeConfig{
+		"grpc transport": {
+			ShortName: "srl1",
+			Kind:      "nokia_srlinux",
+			Labels:    map[string]string{"config.transport": "grpc"},
+		},
+		"kind without ssh transport": {
+			ShortName: "linux1",
+			Kind:      "linux",
+		},
+	}
+
+	for name, node := range tests {
+		t.Run(name, func(t *testing.T) {
+			cs := &NodeConfig{TargetNode: node}
+
+			err := Shell(context.Background(), cs, strings.NewReader(""), io.Discard)
+			if err == nil {
+				t.Fatal("expected an error for a node the shell can't connect to")
+			}
+		})
+	}
+}
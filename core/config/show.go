@@ -0,0 +1,140 @@
+package config
+
+import (
+	"regexp"
+	"strings"
+)
+
+// showField is a key field of the output of a show command, the first group of Re.
+type showField struct {
+	Name string
+	Re   *regexp.Regexp
+}
+
+// kvField returns the field of the "key : value" line of the output with the key.
+func kvField(name, key string) showField {
+	return showField{
+		Name: name,
+		Re:   regexp.MustCompile(`(?m)^[ \t]*` + regexp.QuoteMeta(key) + `[ \t]*:[ \t]*(.*?)[ \t]*$`),
+	}
+}
+
+var (
+	srlShowFields = map[string][]showField{ //nolint:gochecknoglobals
+		"show version": {
+			kvField("Hostname", "Hostname"),
+			kvField("Chassis", "Chassis Type"),
+			kvField("Version", "Software Version"),
+			kvField("Build", "Build Number"),
+			kvField("Last Booted", "Last Booted"),
+		},
+		"show platform chassis": {
+			kvField("Type", "Type"),
+			kvField("Serial", "Serial Number"),
+			kvField("Oper State", "Oper State"),
+		},
+	}
+	srosShowFields = map[string][]showField{ //nolint:gochecknoglobals
+		"show version": {
+			{Name: "Version", Re: regexp.MustCompile(`(TiMOS-\S+)`)},
+			{Name: "Platform", Re: regexp.MustCompile(`TiMOS-\S+\s+\S+\s+(Nokia.*?)\s+Copyright`)},
+		},
+		"show system information": {
+			kvField("Name", "System Name"),
+			kvField("Type", "System Type"),
+			kvField("Version", "System Version"),
+			kvField("Up Time", "System Up Time"),
+		},
+	}
+)
+
+// showFields maps the node kinds to the key fields of the show command outputs they are compared on.
+var showFields = map[string]map[string][]showField{ //nolint:gochecknoglobals
+	"srl":           srlShowFields,
+	"nokia_srlinux": srlShowFields,
+	"vr-sros":       srosShowFields,
+	"nokia_sros":    srosShowFields,
+	"nokia_srsim":   srosShowFields,
+	"srsim":         srosShowFields,
+}
+
+// kvLineRe matches the "key : value" lines of the show command outputs without known fields.
+var kvLineRe = regexp.MustCompile(`(?m)^[ \t]*([A-Za-z][\w .()/-]*?)[ \t]*:[ \t]*(\S.*?)[ \t]*$`)
+
+// ShowResult is the output of a show command run on a node and its key fields.
+type ShowResult struct {
+	Node   string
+	Output string
+	// Fields are the names of the key fields in the order of the output, Values their values
+	Fields []string
+	Values map[string]string
+	Err    error
+}
+
+// Show runs the show command on the node and parses the key fields of its output.
+func Show(cs *NodeConfig, command string) *ShowResult {
+	res := &ShowResult{Node: cs.TargetNode.ShortName, Values: map[string]string{}}
+
+	outs, err := Collect(cs, []string{command})
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	if outs[0].Err != nil {
+		res.Err = outs[0].Err
+		return res
+	}
+
+	res.Output = outs[0].Output
+	res.Fields, res.Values = ShowFields(cs.TargetNode.Kind, command, res.Output)
+
+	return res
+}
+
+// ShowFields returns the key fields of the show command output of a node of the kind.
+// The outputs of the commands without known fields for the kind are compared on all
+// their "key : value" lines, or on the first line of the output.
+func ShowFields(kind, command, output string) ([]string, map[string]string) {
+	values := map[string]string{}
+	var fields []string
+
+	command = strings.Join(strings.Fields(command), " ")
+	if known, ok := showFields[kind][command]; ok {
+		for _, f := range known {
+			fields = append(fields, f.Name)
+			if m := f.Re.FindStringSubmatch(output); m != nil {
+				values[f.Name] = m[1]
+			}
+		}
+		return fields, values
+	}
+
+	for _, m := range kvLineRe.FindAllStringSubmatch(output, -1) {
+		if _, ok := values[m[1]]; ok {
+			continue
+		}
+		fields = append(fields, m[1])
+		values[m[1]] = m[2]
+	}
+	if len(fields) > 0 {
+		return fields, values
+	}
+
+	first, _, _ := strings.Cut(strings.TrimSpace(output), "\n")
+	return []string{"Output"}, map[string]string{"Output": strings.TrimSpace(first)}
+}
+
+// ShowColumns returns the key fields of the results in the order they are found in the outputs of the nodes.
+func ShowColumns(results []*ShowResult) []string {
+	var cols []string
+	seen := map[string]bool{}
+	for _, r := range results {
+		for _, f := range r.Fields {
+			if !seen[f] {
+				seen[f] = true
+				cols = append(cols, f)
+			}
+		}
+	}
+	return cols
+}
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestShowFields(t *testing.T) {
+	tests := map[string]struct {
+		kind, command, output string
+		fields                []string
+		values                map[string]string
+	}{
+		"srl show version": {
+			kind:    "nokia_srlinux",
+			command: "show  version",
+			output: `--------------------------------------------
+Hostname             : srl1
+Chassis Type         : 7220 IXR-D2L
+Software Version     : v24.10.1
+Build Number         : 492-gf8858c5836
+--------------------------------------------`,
+			fields: []string{"Hostname", "Chassis", "Version", "Build", "Last Booted"},
+			values: map[string]string{
+				"Hostname": "srl1",
+				"Chassis":  "7220 IXR-D2L",
+				"Version":  "v24.10.1",
+				"Build":    "492-gf8858c5836",
+			},
+		},
+		"key value lines": {
+			kind:    "linux",
+			command: "uname -a",
+			output:  "Name : a\nUp Time: 10 days\nName : b\n",
+			fields:  []string{"Name", "Up Time"},
+			values:  map[string]string{"Name": "a", "Up Time": "10 days"},
+		},
+		"first line": {
+			kind:    "linux",
+			command: "uname -r",
+			output:  "\n6.8.0-45-generic\nmore\n",
+			fields:  []string{"Output"},
+			values:  map[string]string{"Output": "6.8.0-45-generic"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			fields, values := ShowFields(tt.kind, tt.command, tt.output)
+			if d := cmp.Diff(tt.fields, fields); d != "" {
+				t.Errorf("fields mismatch (-want +got):\n%s", d)
+			}
+			if d := cmp.Diff(tt.values, values); d != "" {
+				t.Errorf("values mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestShowColumns(t *testing.T) {
+	results := []*ShowResult{
+		{Node: "a", Fields: []string{"Name", "Version"}},
+		{Node: "b", Err: errors.New("timeout")},
+		{Node: "c", Fields: []string{"Version", "Up Time"}},
+	}
+
+	want := []string{"Name", "Version", "Up Time"}
+	if d := cmp.Diff(want, ShowColumns(results)); d != "" {
+		t.Errorf("columns mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+)
+
+// startupConfigKinds are the kinds booting from a full startup-config file,
+// the rendered configuration of their nodes replaces the kind's default startup-config.
+var startupConfigKinds = map[string]struct{}{ //nolint:gochecknoglobals
+	"ceos":              {},
+	"arista_ceos":       {},
+	"crpd":              {},
+	"juniper_crpd":      {},
+	"xrd":               {},
+	"cisco_xrd":         {},
+	"vyosnetworks_vyos": {},
+	"vr-sros":           {},
+	"nokia_sros":        {},
+}
+
+// SupportsStartupConfig returns true if the nodes of the kind boot from the rendered configuration.
+func SupportsStartupConfig(kind string) bool {
+	_, ok := startupConfigKinds[kind]
+	return ok
+}
+
+// StartupConfigs returns the rendered configuration of the nodes by node name,
+// to be written as their startup-config before the containers start.
+// Nodes of kinds without a full startup-config file, nodes without rendered config
+// and nodes with a startup-config in the topology are left out.
+func StartupConfigs(allConfig map[string]*NodeConfig) map[string]string {
+	names := make([]string, 0, len(allConfig))
+	for n := range allConfig {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	res := make(map[string]string, len(allConfig))
+	for _, n := range names {
+		cs := allConfig[n]
+		switch {
+		case len(cs.Data) == 0:
+			continue
+		case !SupportsStartupConfig(cs.TargetNode.Kind):
+			log.Warnf("%s: kind %s does not boot from a full startup-config, use 'config commit' after deploy",
+				n, cs.TargetNode.Kind)
+			continue
+		case cs.TargetNode.StartupConfig != "":
+			log.Warnf("%s: the startup-config %s of the topology is kept", n, cs.TargetNode.StartupConfig)
+			continue
+		}
+
+		res[n] = strings.Join(cs.Data, "\n\n") + "\n"
+	}
+
+	return res
+}
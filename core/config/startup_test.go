@@ -0,0 +1,33 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestStartupConfigs(t *testing.T) {
+	allConfig := map[string]*NodeConfig{
+		"ceos1": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "ceos1", Kind: "arista_ceos"},
+			Data:       []string{"hostname ceos1", "interface Ethernet1"},
+		},
+		"ceos2": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "ceos2", Kind: "arista_ceos", StartupConfig: "ceos2.cfg"},
+			Data:       []string{"hostname ceos2"},
+		},
+		"ceos3": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "ceos3", Kind: "arista_ceos"},
+		},
+		"srl1": {
+			TargetNode: &clabtypes.NodeConfig{ShortName: "srl1", Kind: "nokia_srlinux"},
+			Data:       []string{"set / system name host-name srl1"},
+		},
+	}
+
+	want := map[string]string{"ceos1": "hostname ceos1\n\ninterface Ethernet1\n"}
+	if d := cmp.Diff(want, StartupConfigs(allConfig)); d != "" {
+		t.Errorf("startup configs mismatch (-want +got):\n%s", d)
+	}
+}
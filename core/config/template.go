@@ -3,29 +3,17 @@ package config
 import (
 	"embed"
 	"fmt"
-	"io/fs"
-	"os"
-	"path/filepath"
+	"io"
 	"strings"
-	"text/template"
-
-	jT "github.com/kellerza/template"
+	"time"
 
 	"github.com/charmbracelet/log"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabcorecredstore "github.com/srl-labs/containerlab/core/credstore"
 	clabtypes "github.com/srl-labs/containerlab/types"
-	clabutils "github.com/srl-labs/containerlab/utils"
 	"gopkg.in/yaml.v2"
 )
 
-// TemplateNames is templates to execute.
-var TemplateNames []string
-
-// TemplatePaths is path to additional templates.
-var TemplatePaths []string
-
-// DebugCount is a debug verbosity counter.
-var DebugCount int
-
 type NodeConfig struct {
 	TargetNode  *clabtypes.NodeConfig
 	Credentials []string // Node's credentials
@@ -34,84 +22,65 @@ type NodeConfig struct {
 	// the Rendered templates
 	Data []string
 	Info []string
+	// Meta is the provenance of the rendered templates
+	Meta []*SnippetMeta
+	// Bootstrap are the commands of the bootstrap template, sent once per session before the config,
+	// nil when the node has no bootstrap template
+	Bootstrap []string
+	// the Rendered undo templates, reversing the config on lab destroy
+	Undo     []string
+	UndoInfo []string
+
+	// DebugCount is the debug verbosity of the node's transports and printouts
+	DebugCount int
+	// Dialer opens the transport connections to the node, the default dialer when nil
+	Dialer transport.Dialer
+	// Transcript receives the output of the node's config session
+	Transcript io.Writer
+	// Session records the timeline of the node's config session
+	Session *SessionLog
+	// Redactor masks the secrets of the config in the logs, transcripts and reports, nil disables it
+	Redactor *Redactor
+	// Transport is the transport of the fallback chain the config was sent with
+	Transport string
+	// Banner is the login banner of the node received by the SSH transport the config was sent with
+	Banner string
+
+	// provenance is set when the snippets carry provenance comments
+	provenance bool
+	// rotated is set when the credentials were changed with tools rotate-creds,
+	// the password of the config.password label is outdated then
+	rotated bool
+	// credStore is the credentials store of the user consulted for the SSH credentials of the node
+	credStore *clabcorecredstore.Store
+	// lab is the name of the lab of the node
+	lab string
 }
 
-// LoadTemplates loads templates from all paths for the specific role/kind.
-func LoadTemplates(tmpl *template.Template, role string) error {
-	for _, p := range TemplatePaths {
-		fn := filepath.Join(p, fmt.Sprintf("*__%s.tmpl", role))
-		_, err := tmpl.ParseGlob(fn)
-		if err != nil {
-			if strings.Contains(err.Error(), "pattern matches no file") {
-				log.Debug(err)
-				continue
-			}
-			return fmt.Errorf("could not load templates from %s: %w", fn, err)
-		}
-	}
-	return nil
+// SnippetMeta is the provenance of a rendered template.
+type SnippetMeta struct {
+	// Template is the name of the template file
+	Template string
+	// Engine is the render engine of the template
+	Engine string
+	// VarsHash is the hash of the variables the template was rendered with
+	VarsHash string
+	// Rendered is the time the template was rendered
+	Rendered time.Time
+	// Commit is the commit mode declared by the snippet with a "# commit:" comment
+	Commit string
+	// Weight orders the snippets, declared with a "# section:" or "# weight:" comment
+	Weight int
+
+	// render writes the template of the snippet again, so that the snippet is streamed to the node
+	// while it is rendered. Nil when the snippet sent differs from the output of the template,
+	// e.g. when a hook replaced it or the config of an earlier snippet was removed from it
+	render func(w io.Writer) error
 }
 
 //go:embed templates
 var embeddedTemplates embed.FS
 
-func RenderAll(allnodes map[string]*NodeConfig) error {
-	if len(TemplatePaths) == 0 { // default is the install path
-		TemplatePaths = []string{"@"}
-	}
-
-	var TemplateFS []fs.FS
-
-	for _, v := range TemplatePaths {
-		if v == "@" {
-			TemplateFS = append(TemplateFS, embeddedTemplates)
-		} else {
-			TemplateFS = append(TemplateFS, os.DirFS(v))
-		}
-	}
-
-	if len(TemplateNames) == 0 {
-		var err error
-		TemplateNames, err = GetTemplateNamesInDirs(TemplateFS)
-		if err != nil {
-			return err
-		}
-		log.Infof("No template names specified (-l) using: %s", strings.Join(TemplateNames, ", "))
-	}
-
-	tmpl := template.New("").Funcs(clabutils.CreateFuncs()).Funcs(jT.Funcs)
-
-	for _, nc := range allnodes {
-		for _, baseN := range TemplateNames {
-			tmplN := fmt.Sprintf("%s__%s.tmpl", baseN, nc.Vars[vkRole])
-			log.Debugf("Looking up template %v", tmplN)
-			if l := tmpl.Lookup(tmplN); l == nil {
-				err := LoadTemplates(tmpl, fmt.Sprintf("%s", nc.Vars[vkRole]))
-				if err != nil {
-					return err
-				}
-				l = tmpl.Lookup(tmplN)
-				if l == nil {
-					log.Debugf("No template found for %s; skipping..", nc.TargetNode.ShortName)
-					continue
-				}
-			}
-			var buf strings.Builder
-			err := tmpl.ExecuteTemplate(&buf, tmplN, nc.Vars)
-			log.Debugf("Executed a template %s with an error code %v", tmplN, err)
-			if err != nil {
-				nc.Print(true, true)
-				return err
-			}
-
-			data := strings.ReplaceAll(strings.Trim(buf.String(), "\n \t\r"), "\n\n\n", "\n\n")
-			nc.Data = append(nc.Data, data)
-			nc.Info = append(nc.Info, tmplN)
-		}
-	}
-	return nil
-}
-
 // String implements stringer interface for NodeConfig.
 func (c *NodeConfig) String() string {
 	s := fmt.Sprintf("%s: %v", c.TargetNode.ShortName, c.Info)
@@ -128,7 +97,7 @@ func (c *NodeConfig) Print(vars, rendered bool) { // skipcq: RVV-A0005
 		s.WriteString(" vars = ")
 		var saved_nodes Dict
 		restore := false
-		if DebugCount < 3 {
+		if c.DebugCount < 3 {
 			saved_nodes, restore = c.Vars[vkNodes].(Dict)
 			if restore {
 				var n strings.Builder
@@ -154,6 +123,14 @@ func (c *NodeConfig) Print(vars, rendered bool) { // skipcq: RVV-A0005
 	}
 
 	if rendered {
+		if c.Bootstrap != nil {
+			fmt.Fprintf(&s, "\n  Bootstrap for %s = [[", c.TargetNode.ShortName)
+			for _, l := range c.Bootstrap {
+				s.WriteString("\n     ")
+				s.WriteString(l)
+			}
+			s.WriteString("\n  ]]")
+		}
 		for idx, conf := range c.Data {
 			fmt.Fprintf(&s, "\n  Template %s for %s = [[", c.Info[idx], c.TargetNode.ShortName)
 
@@ -0,0 +1,143 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	"golang.org/x/net/proxy"
+)
+
+// netnsRunDir holds the network namespaces named with ip netns.
+const netnsRunDir = "/run/netns"
+
+// Dialer opens the connections of the transports to the nodes.
+type Dialer func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// dial opens the connection with the dialer, falling back to the default net dialer.
+func (d Dialer) dial(ctx context.Context, network, addr string) (net.Conn, error) {
+	if d == nil {
+		var nd net.Dialer
+		return nd.DialContext(ctx, network, addr)
+	}
+	return d(ctx, network, addr)
+}
+
+// NetNSDialer returns a dialer opening the connections from the network namespace at nsPath.
+// The sockets stay in the namespace they were created in, the nodes are reached
+// over their management network even when the host running clab has no route to it.
+func NetNSDialer(nsPath string) Dialer {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		if nsPath == "" {
+			return nil, fmt.Errorf("no network namespace to dial %s from", addr)
+		}
+
+		netns, err := ns.GetNS(nsPath)
+		if err != nil {
+			return nil, fmt.Errorf("network namespace %s: %w", nsPath, err)
+		}
+		defer netns.Close()
+
+		var conn net.Conn
+		err = netns.Do(func(ns.NetNS) error {
+			var nd net.Dialer
+			var err error
+			conn, err = nd.DialContext(ctx, network, addr)
+			return err
+		})
+
+		return conn, err
+	}
+}
+
+// SOCKS5Dialer returns a dialer opening the connections through the SOCKS5 proxy at addr,
+// authenticated when the username is set. Labs reachable only from a jump host are
+// configured through the proxy of an SSH dynamic port forward, e.g. ssh -D 1080 jumphost.
+func SOCKS5Dialer(addr, username, password string) (Dialer, error) {
+	var auth *proxy.Auth
+	if username != "" {
+		auth = &proxy.Auth{User: username, Password: password}
+	}
+
+	d, err := proxy.SOCKS5("tcp", addr, auth, proxy.Direct)
+	if err != nil {
+		return nil, fmt.Errorf("socks5 proxy %s: %w", addr, err)
+	}
+
+	cd, ok := d.(proxy.ContextDialer)
+	if !ok {
+		return nil, fmt.Errorf("socks5 proxy %s does not support contexts", addr)
+	}
+
+	return cd.DialContext, nil
+}
+
+// SourceDialer returns a dialer opening the connections from the source address
+// or bound to the source interface, e.g. the wg0 interface of a VPN.
+func SourceDialer(source string) Dialer {
+	nd := &net.Dialer{}
+	if ip := net.ParseIP(source); ip != nil {
+		nd.LocalAddr = &net.TCPAddr{IP: ip}
+	} else {
+		nd.Control = func(_, _ string, c syscall.RawConn) error {
+			var serr error
+			err := c.Control(func(fd uintptr) {
+				serr = syscall.SetsockoptString(int(fd), syscall.SOL_SOCKET, syscall.SO_BINDTODEVICE, source)
+			})
+			if err != nil {
+				return err
+			}
+			if serr != nil {
+				return fmt.Errorf("bind to interface %s: %w", source, serr)
+			}
+			return nil
+		}
+	}
+
+	return nd.DialContext
+}
+
+// ParseDialer returns the dialer of the spec:
+//
+//	socks5://[user:password@]host:port  through a SOCKS5 proxy
+//	source:<interface or address>       from a source interface or address
+//	netns:<name or path>                from a network namespace, a name is looked up in /run/netns
+func ParseDialer(spec string) (Dialer, error) {
+	kind, v, ok := strings.Cut(spec, ":")
+	if !ok || v == "" {
+		return nil, fmt.Errorf("invalid dialer %q, expected socks5://host:port, source:<interface> or netns:<name>", spec)
+	}
+
+	switch kind {
+	case "socks5":
+		u, err := url.Parse(spec)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid socks5 proxy %q", spec)
+		}
+		password, _ := u.User.Password()
+		return SOCKS5Dialer(u.Host, u.User.Username(), password)
+	case "source":
+		return SourceDialer(v), nil
+	case "netns":
+		if !strings.Contains(v, "/") {
+			v = filepath.Join(netnsRunDir, v)
+		}
+		return NetNSDialer(v), nil
+	}
+
+	return nil, fmt.Errorf("unknown dialer %q, expected socks5, source or netns", kind)
+}
+
+// Reachable returns an error when no TCP connection can be opened to addr with the dialer.
+func (d Dialer) Reachable(ctx context.Context, addr string) error {
+	conn, err := d.dial(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
@@ -0,0 +1,73 @@
+package transport
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// defaultExpectTimeout is the time a step of an expect script waits for the expected output.
+const defaultExpectTimeout = 10 * time.Second
+
+// ExpectStep is a step of an expect script, the line sent to the node
+// and the output of the node awaited before the next step.
+type ExpectStep struct {
+	// Send is the line sent to the node, nothing is sent when empty
+	Send string
+	// Expect is the regexp the output of the node must match, the step doesn't wait when nil
+	Expect *regexp.Regexp
+	// Timeout is the time to wait for the expected output, defaultExpectTimeout when 0
+	Timeout time.Duration
+}
+
+// RunExpect runs the expect script in the config session. The output of the node is matched
+// as received, regardless of the prompt character, and the output up to a match is consumed by the step.
+func (t *SSHTransport) RunExpect(steps []ExpectStep) error {
+	// the reader emits the data as received while the script runs
+	raw := t.raw.Swap(true)
+	defer t.raw.Store(raw)
+
+	buf := ""
+	for i, s := range steps {
+		if s.Send != "" {
+			if _, err := t.ses.Writeln(s.Send); err != nil {
+				return fmt.Errorf("step %d: %w", i+1, err)
+			}
+			t.recordSent(s.Send)
+			log.Debugf("--> %s\n", redactWith(t.redact, s.Send))
+		}
+
+		if s.Expect == nil {
+			continue
+		}
+
+		timeout := s.Timeout
+		if timeout == 0 {
+			timeout = defaultExpectTimeout
+		}
+		deadline := time.After(timeout)
+
+		for !s.Expect.MatchString(buf) {
+			select {
+			case r := <-t.in:
+				buf += r.result + r.prompt
+			case <-deadline:
+				return fmt.Errorf("step %d: no output matching %q within %s, received %q",
+					i+1, s.Expect, timeout, redactWith(t.redact, lastLines(buf, 3)))
+			}
+		}
+
+		buf = buf[s.Expect.FindStringIndex(buf)[1]:]
+	}
+
+	return nil
+}
+
+// lastLines returns the last n lines of s.
+func lastLines(s string, n int) string {
+	lines := strings.Split(strings.ReplaceAll(s, "\r", ""), "\n")
+	return strings.Join(lines[max(0, len(lines)-n):], "\n")
+}
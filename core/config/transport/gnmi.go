@@ -0,0 +1,291 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/charmbracelet/log"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// DefaultGNMIPort is the default gNMI port used by the network OSes.
+	DefaultGNMIPort     = 57400
+	gnmiSubscribeMethod = "/gnmi.gNMI/Subscribe"
+	gnmiGetMethod       = "/gnmi.gNMI/Get"
+)
+
+// rawCodec is a gRPC codec passing pre-encoded protobuf messages as is.
+type rawCodec struct{}
+
+func (rawCodec) Marshal(v any) ([]byte, error) {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return nil, fmt.Errorf("rawCodec: unexpected message type %T", v)
+	}
+	return *b, nil
+}
+
+func (rawCodec) Unmarshal(data []byte, v any) error {
+	b, ok := v.(*[]byte)
+	if !ok {
+		return fmt.Errorf("rawCodec: unexpected message type %T", v)
+	}
+	*b = append((*b)[:0], data...)
+	return nil
+}
+
+// Name returns proto, the messages are protobuf encoded.
+func (rawCodec) Name() string { return "proto" }
+
+type GNMITransportOption func(*GNMITransport) error
+
+// GNMITransport is a gNMI client for a single target.
+type GNMITransport struct {
+	// Target is host:port of the gNMI server
+	Target   string
+	Username string
+	Password string
+	// Insecure disables TLS, SkipVerify disables the server certificate verification
+	Insecure   bool
+	SkipVerify bool
+	Encoding   GNMIEncoding
+	// Dialer opens the connection to the target, the default gRPC dialer when nil
+	Dialer Dialer
+	// RootCAs verify the server certificate instead of the system roots, e.g. the lab CA
+	RootCAs *x509.CertPool
+	// MaxSetSize is the size limit of a Set request in bytes, DefaultGNMIMaxSetSize when 0
+	MaxSetSize int
+
+	conn *grpc.ClientConn
+	// debug verbosity, the updates are logged from 2
+	debug int
+}
+
+// WithGNMICredentials sets the username & password used in the gNMI RPCs metadata.
+func WithGNMICredentials(username, password string) GNMITransportOption {
+	return func(t *GNMITransport) error {
+		t.Username = username
+		t.Password = password
+		return nil
+	}
+}
+
+// WithGNMIDialer sets the dialer opening the connection to the target.
+func WithGNMIDialer(d Dialer) GNMITransportOption {
+	return func(t *GNMITransport) error {
+		t.Dialer = d
+		return nil
+	}
+}
+
+// WithGNMIRootCA verifies the server certificate with the PEM encoded CA certificate,
+// e.g. the lab CA issuing the node certificates. Insecure targets are left unchanged.
+func WithGNMIRootCA(caPEM []byte) GNMITransportOption {
+	return func(t *GNMITransport) error {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caPEM) {
+			return fmt.Errorf("no CA certificate found in the PEM data")
+		}
+		t.RootCAs = pool
+		t.SkipVerify = false
+		return nil
+	}
+}
+
+// WithGNMIDebug sets the debug verbosity of the transport.
+func WithGNMIDebug(count int) GNMITransportOption {
+	return func(t *GNMITransport) error {
+		t.debug = count
+		return nil
+	}
+}
+
+// NewGNMITransport creates a gNMI transport for the node.
+// The connection parameters can be tuned with the node labels:
+// config.gnmi.port, config.gnmi.tls (tls, skip-verify, insecure), config.gnmi.encoding
+// and config.gnmi.max-set-size.
+func NewGNMITransport(node *clabtypes.NodeConfig, options ...GNMITransportOption) (*GNMITransport, error) {
+	t := &GNMITransport{SkipVerify: true}
+
+	port := strconv.Itoa(DefaultGNMIPort)
+	if p, ok := node.Labels["config.gnmi.port"]; ok {
+		port = p
+	}
+	t.Target = net.JoinHostPort(NodeHost(node), port)
+
+	tlsMode := "skip-verify"
+	switch node.Kind {
+	case "vr-sros", "nokia_sros":
+		// vrnetlab based SR OS nodes expose gRPC without TLS
+		tlsMode = "insecure"
+	}
+	if v, ok := node.Labels["config.gnmi.tls"]; ok {
+		tlsMode = v
+	}
+	switch tlsMode {
+	case "insecure":
+		t.Insecure = true
+	case "tls":
+		t.SkipVerify = false
+	case "skip-verify":
+	default:
+		return nil, fmt.Errorf("%s: unknown config.gnmi.tls value %q", node.ShortName, tlsMode)
+	}
+
+	encoding := "json_ietf"
+	switch node.Kind {
+	case "vr-sros", "nokia_sros":
+		encoding = "json"
+	}
+	if v, ok := node.Labels["config.gnmi.encoding"]; ok {
+		encoding = v
+	}
+	var err error
+	t.Encoding, err = ParseGNMIEncoding(encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	if v, ok := node.Labels["config.gnmi.max-set-size"]; ok {
+		t.MaxSetSize, err = strconv.Atoi(v)
+		if err != nil || t.MaxSetSize <= 0 {
+			return nil, fmt.Errorf("%s: invalid config.gnmi.max-set-size value %q", node.ShortName, v)
+		}
+	}
+
+	for _, opt := range options {
+		if err := opt(t); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// Connect creates the gRPC client connection.
+func (t *GNMITransport) Connect() error {
+	creds := insecure.NewCredentials()
+	if !t.Insecure {
+		creds = credentials.NewTLS(&tls.Config{
+			InsecureSkipVerify: t.SkipVerify, // skipcq: GSC-G402
+			RootCAs:            t.RootCAs,
+		})
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(creds),
+		grpc.WithDefaultCallOptions(grpc.ForceCodec(rawCodec{})),
+	}
+	if t.Dialer != nil {
+		opts = append(opts, grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return t.Dialer(ctx, "tcp", addr)
+		}))
+	}
+
+	conn, err := grpc.NewClient(t.Target, opts...)
+	if err != nil {
+		return fmt.Errorf("cannot connect to %s: %w", t.Target, err)
+	}
+	t.conn = conn
+
+	log.Debugf("gNMI client created for %s", t.Target)
+	return nil
+}
+
+// Close the gRPC connection.
+func (t *GNMITransport) Close() {
+	if t.conn != nil {
+		t.conn.Close()
+		t.conn = nil
+	}
+}
+
+// outgoingContext adds the credentials to the RPC metadata.
+func (t *GNMITransport) outgoingContext(ctx context.Context) context.Context {
+	if t.Username == "" {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, "username", t.Username, "password", t.Password)
+}
+
+// Get returns the updates of the data of the paths with the data type, e.g. the configuration.
+func (t *GNMITransport) Get(ctx context.Context, paths []*GNMIPath, dataType GNMIDataType) ([]*GNMIUpdate, error) {
+	if t.conn == nil {
+		return nil, fmt.Errorf("%s: not connected", t.Target)
+	}
+
+	req := marshalGetRequest(paths, dataType, t.Encoding)
+	var rsp []byte
+	err := t.conn.Invoke(t.outgoingContext(ctx), gnmiGetMethod, &req, &rsp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: get failed: %w", t.Target, err)
+	}
+
+	updates, err := unmarshalGetResponse(rsp)
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid get response: %w", t.Target, err)
+	}
+
+	return updates, nil
+}
+
+// Subscribe opens a STREAM subscription to the paths and calls fn for every received update.
+// Subscribe returns when the context is cancelled, the stream ends or fn returns an error.
+func (t *GNMITransport) Subscribe(ctx context.Context, paths []*GNMIPath, fn func(u *GNMIUpdate) error) error {
+	if t.conn == nil {
+		return fmt.Errorf("%s: not connected", t.Target)
+	}
+
+	ctx, cancel := context.WithCancel(t.outgoingContext(ctx))
+	defer cancel()
+
+	stream, err := t.conn.NewStream(ctx,
+		&grpc.StreamDesc{StreamName: "Subscribe", ServerStreams: true, ClientStreams: true},
+		gnmiSubscribeMethod)
+	if err != nil {
+		return err
+	}
+
+	req := marshalSubscribeRequest(paths, t.Encoding)
+	if err := stream.SendMsg(&req); err != nil {
+		return fmt.Errorf("%s: subscribe failed: %w", t.Target, err)
+	}
+
+	for {
+		var rsp []byte
+		err := stream.RecvMsg(&rsp)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("%s: %w", t.Target, err)
+		}
+
+		updates, _, err := unmarshalSubscribeResponse(rsp)
+		if err != nil {
+			return fmt.Errorf("%s: invalid subscribe response: %w", t.Target, err)
+		}
+
+		for _, u := range updates {
+			if t.debug > 1 {
+				log.Debugf("%s gNMI update %s = %s", t.Target, u.Path, u.Value)
+			}
+			if err := fn(u); err != nil {
+				return err
+			}
+		}
+	}
+}
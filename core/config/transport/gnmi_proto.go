@@ -0,0 +1,569 @@
+package transport
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// This file contains a minimal wire-level implementation of the gNMI protobuf messages
+// (github.com/openconfig/gnmi/proto/gnmi/gnmi.proto) used by the gNMI transport.
+// Only the fields required by containerlab are encoded and decoded.
+
+// gNMI field numbers.
+const (
+	// SubscribeRequest.
+	fSubscribeRequestSubscribe = 1
+	// SubscriptionList.
+	fSubscriptionListSubscription = 2
+	fSubscriptionListMode         = 5
+	fSubscriptionListEncoding     = 8
+	// Subscription.
+	fSubscriptionPath = 1
+	fSubscriptionMode = 2
+	// GetRequest.
+	fGetRequestPath     = 2
+	fGetRequestType     = 3
+	fGetRequestEncoding = 5
+	// GetResponse.
+	fGetResponseNotification = 1
+	// SetRequest.
+	fSetRequestDelete  = 2
+	fSetRequestReplace = 3
+	fSetRequestUpdate  = 4
+	// SubscribeResponse.
+	fSubscribeResponseUpdate       = 1
+	fSubscribeResponseSyncResponse = 3
+	// Notification.
+	fNotificationPrefix = 2
+	fNotificationUpdate = 4
+	// Update.
+	fUpdatePath = 1
+	fUpdateVal  = 3
+	// Path.
+	fPathOrigin = 2
+	fPathElem   = 3
+	// PathElem.
+	fPathElemName = 1
+	fPathElemKey  = 2
+	// TypedValue.
+	fTypedValueString    = 1
+	fTypedValueInt       = 2
+	fTypedValueUint      = 3
+	fTypedValueBool      = 4
+	fTypedValueBytes     = 5
+	fTypedValueFloat     = 6
+	fTypedValueDecimal   = 7
+	fTypedValueLeafList  = 8
+	fTypedValueJSON      = 10
+	fTypedValueJSONIETF  = 11
+	fTypedValueASCII     = 12
+	fTypedValueDouble    = 14
+	fScalarArrayElement  = 1
+	fDecimal64Digits     = 1
+	fDecimal64Precision  = 2
+	subscriptionListMode = 0 // STREAM
+	subscriptionMode     = 0 // TARGET_DEFINED
+)
+
+// GNMIEncoding is the gNMI data encoding requested from the target.
+type GNMIEncoding int
+
+const (
+	GNMIEncodingJSON     GNMIEncoding = 0
+	GNMIEncodingBytes    GNMIEncoding = 1
+	GNMIEncodingProto    GNMIEncoding = 2
+	GNMIEncodingASCII    GNMIEncoding = 3
+	GNMIEncodingJSONIETF GNMIEncoding = 4
+)
+
+// ParseGNMIEncoding returns the GNMIEncoding for the given name, e.g. json_ietf.
+func ParseGNMIEncoding(s string) (GNMIEncoding, error) {
+	switch strings.ReplaceAll(strings.ToLower(s), "-", "_") {
+	case "json":
+		return GNMIEncodingJSON, nil
+	case "bytes":
+		return GNMIEncodingBytes, nil
+	case "proto":
+		return GNMIEncodingProto, nil
+	case "ascii":
+		return GNMIEncodingASCII, nil
+	case "json_ietf", "":
+		return GNMIEncodingJSONIETF, nil
+	}
+	return 0, fmt.Errorf("unknown gNMI encoding %q", s)
+}
+
+// GNMIDataType is the type of the data requested with a gNMI Get.
+type GNMIDataType int
+
+const (
+	GNMIDataTypeAll    GNMIDataType = 0
+	GNMIDataTypeConfig GNMIDataType = 1
+	GNMIDataTypeState  GNMIDataType = 2
+)
+
+// GNMIPathElem is an element of a gNMI path with its keys.
+type GNMIPathElem struct {
+	Name string
+	Keys map[string]string
+}
+
+// GNMIPath is a gNMI path.
+type GNMIPath struct {
+	Origin string
+	Elems  []GNMIPathElem
+}
+
+// ParseGNMIPath parses an XPath-like string, e.g. /interface[name=ethernet-1/1]/oper-state
+// An optional origin can be specified with the origin:/path notation.
+func ParseGNMIPath(s string) (*GNMIPath, error) {
+	p := &GNMIPath{}
+
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, ":/"); i > 0 && !strings.Contains(s[:i], "[") {
+		p.Origin = s[:i]
+		s = s[i+1:]
+	}
+
+	var elems []string
+	var cur strings.Builder
+	depth := 0
+	for _, r := range s {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("invalid path %q: unbalanced brackets", s)
+			}
+		case r == '/' && depth == 0:
+			if cur.Len() > 0 {
+				elems = append(elems, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("invalid path %q: unbalanced brackets", s)
+	}
+	if cur.Len() > 0 {
+		elems = append(elems, cur.String())
+	}
+
+	for _, e := range elems {
+		pe := GNMIPathElem{}
+		i := strings.Index(e, "[")
+		if i < 0 {
+			pe.Name = e
+			p.Elems = append(p.Elems, pe)
+			continue
+		}
+		pe.Name = e[:i]
+		pe.Keys = map[string]string{}
+		for _, kv := range strings.Split(strings.TrimSuffix(e[i+1:], "]"), "][") {
+			k, v, ok := strings.Cut(kv, "=")
+			if !ok {
+				return nil, fmt.Errorf("invalid key %q in path %q", kv, s)
+			}
+			pe.Keys[k] = v
+		}
+		p.Elems = append(p.Elems, pe)
+	}
+
+	return p, nil
+}
+
+// String returns the path in the XPath-like notation with sorted keys.
+func (p *GNMIPath) String() string {
+	var s strings.Builder
+	for _, e := range p.Elems {
+		s.WriteString("/")
+		s.WriteString(e.Name)
+		keys := make([]string, 0, len(e.Keys))
+		for k := range e.Keys {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(&s, "[%s=%s]", k, e.Keys[k])
+		}
+	}
+	if s.Len() == 0 {
+		return "/"
+	}
+	return s.String()
+}
+
+// Matches returns true when the path p returned by a target matches the path pattern o.
+// The origins and the module prefixes of the element names are ignored, e.g. srl_nokia-interfaces:interface
+// matches interface. The * element name and key value of the pattern match any name or value.
+func (p *GNMIPath) Matches(o *GNMIPath) bool {
+	if len(p.Elems) != len(o.Elems) {
+		return false
+	}
+
+	for i, pe := range o.Elems {
+		e := p.Elems[i]
+		if pe.Name != "*" && stripPathModule(e.Name) != stripPathModule(pe.Name) {
+			return false
+		}
+		for k, v := range pe.Keys {
+			ev, ok := e.Keys[k]
+			if !ok || (v != "*" && ev != v) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// stripPathModule removes the module prefix of a path element name.
+func stripPathModule(name string) string {
+	if _, n, ok := strings.Cut(name, ":"); ok {
+		return n
+	}
+	return name
+}
+
+// Append returns a new path with the elements of o appended to p.
+func (p *GNMIPath) Append(o *GNMIPath) *GNMIPath {
+	res := &GNMIPath{Origin: p.Origin}
+	res.Elems = append(append(res.Elems, p.Elems...), o.Elems...)
+	if o.Origin != "" {
+		res.Origin = o.Origin
+	}
+	return res
+}
+
+func (p *GNMIPath) marshal() []byte {
+	var b []byte
+	if p.Origin != "" {
+		b = protowire.AppendTag(b, fPathOrigin, protowire.BytesType)
+		b = protowire.AppendString(b, p.Origin)
+	}
+	for _, e := range p.Elems {
+		var eb []byte
+		eb = protowire.AppendTag(eb, fPathElemName, protowire.BytesType)
+		eb = protowire.AppendString(eb, e.Name)
+		keys := make([]string, 0, len(e.Keys))
+		for k := range e.Keys {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			// map entries are encoded as messages with key=1 and value=2
+			var kb []byte
+			kb = protowire.AppendTag(kb, 1, protowire.BytesType)
+			kb = protowire.AppendString(kb, k)
+			kb = protowire.AppendTag(kb, 2, protowire.BytesType)
+			kb = protowire.AppendString(kb, e.Keys[k])
+			eb = protowire.AppendTag(eb, fPathElemKey, protowire.BytesType)
+			eb = protowire.AppendBytes(eb, kb)
+		}
+		b = protowire.AppendTag(b, fPathElem, protowire.BytesType)
+		b = protowire.AppendBytes(b, eb)
+	}
+	return b
+}
+
+func unmarshalPath(b []byte) (*GNMIPath, error) {
+	p := &GNMIPath{}
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		switch num {
+		case fPathOrigin:
+			p.Origin = string(v)
+		case fPathElem:
+			e := GNMIPathElem{}
+			err := walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+				switch num {
+				case fPathElemName:
+					e.Name = string(v)
+				case fPathElemKey:
+					var k, val string
+					err := walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+						if num == 1 {
+							k = string(v)
+						} else if num == 2 {
+							val = string(v)
+						}
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+					if e.Keys == nil {
+						e.Keys = map[string]string{}
+					}
+					e.Keys[k] = val
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			p.Elems = append(p.Elems, e)
+		}
+		return nil
+	})
+	return p, err
+}
+
+// marshalSubscribeRequest encodes a SubscribeRequest for a STREAM subscription to the paths.
+func marshalSubscribeRequest(paths []*GNMIPath, encoding GNMIEncoding) []byte {
+	var sl []byte
+	for _, p := range paths {
+		var sb []byte
+		sb = protowire.AppendTag(sb, fSubscriptionPath, protowire.BytesType)
+		sb = protowire.AppendBytes(sb, p.marshal())
+		sb = protowire.AppendTag(sb, fSubscriptionMode, protowire.VarintType)
+		sb = protowire.AppendVarint(sb, subscriptionMode)
+		sl = protowire.AppendTag(sl, fSubscriptionListSubscription, protowire.BytesType)
+		sl = protowire.AppendBytes(sl, sb)
+	}
+	sl = protowire.AppendTag(sl, fSubscriptionListMode, protowire.VarintType)
+	sl = protowire.AppendVarint(sl, subscriptionListMode)
+	sl = protowire.AppendTag(sl, fSubscriptionListEncoding, protowire.VarintType)
+	sl = protowire.AppendVarint(sl, uint64(encoding))
+
+	var b []byte
+	b = protowire.AppendTag(b, fSubscribeRequestSubscribe, protowire.BytesType)
+	b = protowire.AppendBytes(b, sl)
+	return b
+}
+
+// marshalGetRequest encodes a GetRequest of the paths.
+func marshalGetRequest(paths []*GNMIPath, dataType GNMIDataType, encoding GNMIEncoding) []byte {
+	var b []byte
+	for _, p := range paths {
+		b = protowire.AppendTag(b, fGetRequestPath, protowire.BytesType)
+		b = protowire.AppendBytes(b, p.marshal())
+	}
+	if dataType != GNMIDataTypeAll {
+		b = protowire.AppendTag(b, fGetRequestType, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(dataType))
+	}
+	b = protowire.AppendTag(b, fGetRequestEncoding, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(encoding))
+	return b
+}
+
+// marshalSetOp encodes the operation as the field of a SetRequest,
+// a Path for the deletes and an Update for the replaces and updates.
+func marshalSetOp(op *GNMISetOp, encoding GNMIEncoding) []byte {
+	var b []byte
+	if op.Op == GNMISetDelete {
+		b = protowire.AppendTag(b, fSetRequestDelete, protowire.BytesType)
+		return protowire.AppendBytes(b, op.Path.marshal())
+	}
+
+	valField := fTypedValueJSONIETF
+	if encoding == GNMIEncodingJSON {
+		valField = fTypedValueJSON
+	}
+	var val []byte
+	val = protowire.AppendTag(val, protowire.Number(valField), protowire.BytesType)
+	val = protowire.AppendString(val, op.Value)
+
+	var upd []byte
+	upd = protowire.AppendTag(upd, fUpdatePath, protowire.BytesType)
+	upd = protowire.AppendBytes(upd, op.Path.marshal())
+	upd = protowire.AppendTag(upd, fUpdateVal, protowire.BytesType)
+	upd = protowire.AppendBytes(upd, val)
+
+	num := protowire.Number(fSetRequestUpdate)
+	if op.Op == GNMISetReplace {
+		num = fSetRequestReplace
+	}
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, upd)
+}
+
+// unmarshalGetResponse decodes the updates of the notifications of a GetResponse.
+func unmarshalGetResponse(b []byte) ([]*GNMIUpdate, error) {
+	var updates []*GNMIUpdate
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		if num != fGetResponseNotification {
+			return nil
+		}
+		u, err := unmarshalNotification(v)
+		if err != nil {
+			return err
+		}
+		updates = append(updates, u...)
+		return nil
+	})
+	return updates, err
+}
+
+// GNMIUpdate is a single path/value update received from the target.
+type GNMIUpdate struct {
+	Path  *GNMIPath
+	Value string
+}
+
+// unmarshalSubscribeResponse decodes the updates of a SubscribeResponse.
+// sync is true when the response is a sync_response.
+func unmarshalSubscribeResponse(b []byte) (updates []*GNMIUpdate, sync bool, err error) {
+	err = walkFields(b, func(num protowire.Number, _ protowire.Type, v []byte, varint uint64) error {
+		switch num {
+		case fSubscribeResponseUpdate:
+			u, err := unmarshalNotification(v)
+			if err != nil {
+				return err
+			}
+			updates = append(updates, u...)
+		case fSubscribeResponseSyncResponse:
+			sync = varint != 0
+		}
+		return nil
+	})
+	return updates, sync, err
+}
+
+func unmarshalNotification(b []byte) ([]*GNMIUpdate, error) {
+	prefix := &GNMIPath{}
+	var raw [][]byte
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+		var err error
+		switch num {
+		case fNotificationPrefix:
+			prefix, err = unmarshalPath(v)
+		case fNotificationUpdate:
+			raw = append(raw, v)
+		}
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*GNMIUpdate, 0, len(raw))
+	for _, r := range raw {
+		u := &GNMIUpdate{Path: &GNMIPath{}}
+		err := walkFields(r, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+			var err error
+			switch num {
+			case fUpdatePath:
+				u.Path, err = unmarshalPath(v)
+			case fUpdateVal:
+				u.Value, err = typedValueString(v)
+			}
+			return err
+		})
+		if err != nil {
+			return nil, err
+		}
+		u.Path = prefix.Append(u.Path)
+		res = append(res, u)
+	}
+	return res, nil
+}
+
+// typedValueString decodes a TypedValue into its string representation.
+// JSON encoded strings are unquoted.
+func typedValueString(b []byte) (string, error) {
+	var res string
+	err := walkFields(b, func(num protowire.Number, typ protowire.Type, v []byte, varint uint64) error {
+		switch num {
+		case fTypedValueString, fTypedValueASCII, fTypedValueBytes:
+			res = string(v)
+		case fTypedValueInt:
+			res = strconv.FormatInt(int64(varint), 10)
+		case fTypedValueUint:
+			res = strconv.FormatUint(varint, 10)
+		case fTypedValueBool:
+			res = strconv.FormatBool(varint != 0)
+		case fTypedValueFloat:
+			res = strconv.FormatFloat(float64(math.Float32frombits(uint32(varint))), 'f', -1, 32)
+		case fTypedValueDouble:
+			res = strconv.FormatFloat(math.Float64frombits(varint), 'f', -1, 64)
+		case fTypedValueDecimal:
+			var digits int64
+			var precision uint64
+			err := walkFields(v, func(num protowire.Number, _ protowire.Type, _ []byte, varint uint64) error {
+				if num == fDecimal64Digits {
+					digits = int64(varint)
+				} else if num == fDecimal64Precision {
+					precision = varint
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			res = strconv.FormatFloat(float64(digits)/math.Pow10(int(precision)), 'f', -1, 64)
+		case fTypedValueLeafList:
+			var elems []string
+			err := walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+				if num != fScalarArrayElement {
+					return nil
+				}
+				e, err := typedValueString(v)
+				elems = append(elems, e)
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			res = "[" + strings.Join(elems, ",") + "]"
+		case fTypedValueJSON, fTypedValueJSONIETF:
+			res = string(v)
+			var s string
+			if json.Unmarshal(v, &s) == nil {
+				res = s
+			}
+		default:
+			return fmt.Errorf("unsupported gNMI value type (field %d, wire type %d)", num, typ)
+		}
+		return nil
+	})
+	return res, err
+}
+
+// walkFields calls fn for every field in the protobuf message b.
+// Length delimited fields are passed as v, varint and fixed fields as varint.
+func walkFields(b []byte, fn func(num protowire.Number, typ protowire.Type, v []byte, varint uint64) error) error {
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		var v []byte
+		var varint uint64
+		switch typ {
+		case protowire.VarintType:
+			varint, n = protowire.ConsumeVarint(b)
+		case protowire.Fixed32Type:
+			var x uint32
+			x, n = protowire.ConsumeFixed32(b)
+			varint = uint64(x)
+		case protowire.Fixed64Type:
+			varint, n = protowire.ConsumeFixed64(b)
+		case protowire.BytesType:
+			v, n = protowire.ConsumeBytes(b)
+		default:
+			n = protowire.ConsumeFieldValue(num, typ, b)
+		}
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		b = b[n:]
+
+		if err := fn(num, typ, v, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
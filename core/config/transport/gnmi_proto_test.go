@@ -0,0 +1,218 @@
+package transport
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestParseGNMIPath(t *testing.T) {
+	tests := map[string]struct {
+		path   string
+		want   string
+		origin string
+		err    bool
+	}{
+		"simple": {
+			path: "/system/name/host-name",
+			want: "/system/name/host-name",
+		},
+		"keys with slashes": {
+			path: "/interface[name=ethernet-1/1]/oper-state",
+			want: "/interface[name=ethernet-1/1]/oper-state",
+		},
+		"sorted keys": {
+			path: "network-instance[name=default]/protocols/bgp/neighbor[peer-address=10.0.0.1][afi=ipv4]/session-state",
+			want: "/network-instance[name=default]/protocols/bgp/neighbor[afi=ipv4][peer-address=10.0.0.1]/session-state",
+		},
+		"origin": {
+			path:   "openconfig:/interfaces/interface[name=1/1/c1/1]/state/oper-status",
+			want:   "/interfaces/interface[name=1/1/c1/1]/state/oper-status",
+			origin: "openconfig",
+		},
+		"unbalanced": {
+			path: "/interface[name=ethernet-1/1/oper-state",
+			err:  true,
+		},
+		"invalid key": {
+			path: "/interface[ethernet-1/1]/oper-state",
+			err:  true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseGNMIPath(tc.path)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error for %q, got path %s", tc.path, p)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p.String() != tc.want {
+				t.Errorf("got %s, want %s", p, tc.want)
+			}
+			if p.Origin != tc.origin {
+				t.Errorf("got origin %q, want %q", p.Origin, tc.origin)
+			}
+		})
+	}
+}
+
+func TestGNMIPathMatches(t *testing.T) {
+	tests := map[string]struct {
+		path    string
+		pattern string
+		want    bool
+	}{
+		"equal": {
+			path:    "/interface[name=ethernet-1/1]/oper-state",
+			pattern: "/interface[name=ethernet-1/1]/oper-state",
+			want:    true,
+		},
+		"module prefix and origin": {
+			path:    "/srl_nokia-interfaces:interface[name=ethernet-1/1]/oper-state",
+			pattern: "srl:/interface[name=ethernet-1/1]/oper-state",
+			want:    true,
+		},
+		"wildcard key": {
+			path:    "/interface[name=ethernet-1/2]/oper-state",
+			pattern: "/interface[name=*]/oper-state",
+			want:    true,
+		},
+		"wildcard element": {
+			path:    "/interface[name=ethernet-1/2]/oper-state",
+			pattern: "/*/oper-state",
+			want:    true,
+		},
+		"extra target key": {
+			path:    "/network-instance[name=default]/protocols/bgp/neighbor[afi=ipv4][peer-address=10.0.0.1]",
+			pattern: "/network-instance[name=default]/protocols/bgp/neighbor[peer-address=10.0.0.1]",
+			want:    true,
+		},
+		"other key value": {
+			path:    "/interface[name=ethernet-1/2]/oper-state",
+			pattern: "/interface[name=ethernet-1/1]/oper-state",
+		},
+		"missing key": {
+			path:    "/interface/oper-state",
+			pattern: "/interface[name=ethernet-1/1]/oper-state",
+		},
+		"longer path": {
+			path:    "/interface[name=ethernet-1/1]/oper-state/value",
+			pattern: "/interface[name=ethernet-1/1]/oper-state",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p, err := ParseGNMIPath(tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pattern, err := ParseGNMIPath(tc.pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := p.Matches(pattern); got != tc.want {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalSubscribeResponse(t *testing.T) {
+	prefix, _ := ParseGNMIPath("/interface[name=ethernet-1/1]")
+	path, _ := ParseGNMIPath("/oper-state")
+
+	var val []byte
+	val = protowire.AppendTag(val, fTypedValueJSONIETF, protowire.BytesType)
+	val = protowire.AppendString(val, `"up"`)
+
+	var upd []byte
+	upd = protowire.AppendTag(upd, fUpdatePath, protowire.BytesType)
+	upd = protowire.AppendBytes(upd, path.marshal())
+	upd = protowire.AppendTag(upd, fUpdateVal, protowire.BytesType)
+	upd = protowire.AppendBytes(upd, val)
+
+	var notif []byte
+	notif = protowire.AppendTag(notif, fNotificationPrefix, protowire.BytesType)
+	notif = protowire.AppendBytes(notif, prefix.marshal())
+	notif = protowire.AppendTag(notif, fNotificationUpdate, protowire.BytesType)
+	notif = protowire.AppendBytes(notif, upd)
+
+	var rsp []byte
+	rsp = protowire.AppendTag(rsp, fSubscribeResponseUpdate, protowire.BytesType)
+	rsp = protowire.AppendBytes(rsp, notif)
+
+	updates, sync, err := unmarshalSubscribeResponse(rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sync {
+		t.Error("unexpected sync response")
+	}
+	if len(updates) != 1 {
+		t.Fatalf("expected 1 update, got %d", len(updates))
+	}
+
+	if got := updates[0].Path.String(); got != "/interface[name=ethernet-1/1]/oper-state" {
+		t.Errorf("unexpected path %s", got)
+	}
+	if updates[0].Value != "up" {
+		t.Errorf("unexpected value %q", updates[0].Value)
+	}
+
+	var syncRsp []byte
+	syncRsp = protowire.AppendTag(syncRsp, fSubscribeResponseSyncResponse, protowire.VarintType)
+	syncRsp = protowire.AppendVarint(syncRsp, 1)
+
+	_, sync, err = unmarshalSubscribeResponse(syncRsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !sync {
+		t.Error("expected sync response")
+	}
+}
+
+func TestUnmarshalGetResponse(t *testing.T) {
+	path, _ := ParseGNMIPath("/system/name/host-name")
+
+	var val []byte
+	val = protowire.AppendTag(val, fTypedValueJSONIETF, protowire.BytesType)
+	val = protowire.AppendString(val, `"srl1"`)
+
+	var upd []byte
+	upd = protowire.AppendTag(upd, fUpdatePath, protowire.BytesType)
+	upd = protowire.AppendBytes(upd, path.marshal())
+	upd = protowire.AppendTag(upd, fUpdateVal, protowire.BytesType)
+	upd = protowire.AppendBytes(upd, val)
+
+	var notif []byte
+	notif = protowire.AppendTag(notif, fNotificationUpdate, protowire.BytesType)
+	notif = protowire.AppendBytes(notif, upd)
+
+	var rsp []byte
+	for range 2 {
+		rsp = protowire.AppendTag(rsp, fGetResponseNotification, protowire.BytesType)
+		rsp = protowire.AppendBytes(rsp, notif)
+	}
+
+	updates, err := unmarshalGetResponse(rsp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 2 {
+		t.Fatalf("expected 2 updates, got %d", len(updates))
+	}
+	if got := updates[0].Path.String(); got != "/system/name/host-name" {
+		t.Errorf("unexpected path %s", got)
+	}
+	if updates[1].Value != "srl1" {
+		t.Errorf("unexpected value %q", updates[1].Value)
+	}
+}
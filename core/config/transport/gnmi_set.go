@@ -0,0 +1,184 @@
+package transport
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	gnmiSetMethod = "/gnmi.gNMI/Set"
+	// DefaultGNMIMaxSetSize is the default size limit of a Set request,
+	// below the 4MB default message size limit of the gRPC servers.
+	DefaultGNMIMaxSetSize = 4_000_000
+)
+
+// GNMISetOpType is the type of the operation of a gNMI Set.
+type GNMISetOpType string
+
+const (
+	GNMISetDelete  GNMISetOpType = "delete"
+	GNMISetReplace GNMISetOpType = "replace"
+	GNMISetUpdate  GNMISetOpType = "update"
+)
+
+// GNMISetOp is a single operation of a gNMI Set, the value is JSON encoded.
+type GNMISetOp struct {
+	Op    GNMISetOpType
+	Path  *GNMIPath
+	Value string
+}
+
+// ParseGNMISet parses the operations of a gNMI config snippet, one per line:
+//
+//	/system/name/host-name = srl1
+//	replace /interface[name=ethernet-1/1] = {"admin-state": "enable"}
+//	delete /interface[name=ethernet-1/2]
+//
+// A line without an operation is an update. The values that aren't valid JSON are strings.
+// Blank lines and lines starting with # are skipped.
+func ParseGNMISet(s string) ([]*GNMISetOp, error) {
+	var ops []*GNMISetOp
+
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		op := &GNMISetOp{Op: GNMISetUpdate}
+		if verb, rest, ok := strings.Cut(line, " "); ok {
+			switch GNMISetOpType(verb) {
+			case GNMISetDelete, GNMISetReplace, GNMISetUpdate:
+				op.Op = GNMISetOpType(verb)
+				line = strings.TrimSpace(rest)
+			}
+		}
+
+		p, v, hasValue := strings.Cut(line, " = ")
+		switch {
+		case op.Op == GNMISetDelete && hasValue:
+			return nil, fmt.Errorf("invalid line %q: delete takes no value", line)
+		case op.Op != GNMISetDelete && !hasValue:
+			return nil, fmt.Errorf("invalid line %q: expected path = value", line)
+		}
+
+		var err error
+		op.Path, err = ParseGNMIPath(p)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasValue {
+			op.Value = strings.TrimSpace(v)
+			if !json.Valid([]byte(op.Value)) {
+				b, _ := json.Marshal(op.Value)
+				op.Value = string(b)
+			}
+		}
+
+		ops = append(ops, op)
+	}
+
+	return ops, nil
+}
+
+// orderGNMISet returns the operations in the order the target applies them,
+// the deletes, the replaces and then the updates. The deletes are ordered from the
+// deepest paths, the replaces and updates from the shallowest paths, so that
+// the containers are created before their leaves and removed after them.
+// Operations of the same type and depth keep their order.
+func orderGNMISet(ops []*GNMISetOp) []*GNMISetOp {
+	rank := map[GNMISetOpType]int{GNMISetDelete: 0, GNMISetReplace: 1, GNMISetUpdate: 2}
+
+	res := append([]*GNMISetOp(nil), ops...)
+	sort.SliceStable(res, func(i, j int) bool {
+		a, b := res[i], res[j]
+		if a.Op != b.Op {
+			return rank[a.Op] < rank[b.Op]
+		}
+		if a.Op == GNMISetDelete {
+			return len(a.Path.Elems) > len(b.Path.Elems)
+		}
+		return len(a.Path.Elems) < len(b.Path.Elems)
+	})
+
+	return res
+}
+
+// batchGNMISet encodes the ordered operations into as few Set requests as possible,
+// each one no larger than maxSize.
+func batchGNMISet(ops []*GNMISetOp, encoding GNMIEncoding, maxSize int) ([][]byte, error) {
+	var reqs [][]byte
+	var cur []byte
+
+	for _, op := range ops {
+		b := marshalSetOp(op, encoding)
+		if len(b) > maxSize {
+			return nil, fmt.Errorf("%s %s exceeds the maximum Set size of %d bytes", op.Op, op.Path, maxSize)
+		}
+		if len(cur)+len(b) > maxSize {
+			reqs = append(reqs, cur)
+			cur = nil
+		}
+		cur = append(cur, b...)
+	}
+
+	if len(cur) > 0 {
+		reqs = append(reqs, cur)
+	}
+
+	return reqs, nil
+}
+
+// Set applies the operations with a single Set request, so that they are applied atomically.
+// The operations are ordered by type and path depth and split in several requests
+// when they exceed the maximum Set size, the atomicity is then lost.
+func (t *GNMITransport) Set(ctx context.Context, ops []*GNMISetOp) error {
+	if t.conn == nil {
+		return fmt.Errorf("%s: not connected", t.Target)
+	}
+
+	maxSize := t.MaxSetSize
+	if maxSize <= 0 {
+		maxSize = DefaultGNMIMaxSetSize
+	}
+
+	ops = orderGNMISet(ops)
+	if t.debug > 1 {
+		for _, op := range ops {
+			log.Debugf("%s gNMI %s %s = %s", t.Target, op.Op, op.Path, op.Value)
+		}
+	}
+
+	reqs, err := batchGNMISet(ops, t.Encoding, maxSize)
+	if err != nil {
+		return fmt.Errorf("%s: %w", t.Target, err)
+	}
+
+	if len(reqs) > 1 {
+		log.Warnf("%s: the config exceeds %d bytes, it is applied with %d Set requests, not atomically",
+			t.Target, maxSize, len(reqs))
+	}
+
+	for i, req := range reqs {
+		var rsp []byte
+		err := t.conn.Invoke(t.outgoingContext(ctx), gnmiSetMethod, &req, &rsp)
+		if err != nil {
+			err = fmt.Errorf("%s: set %d/%d failed: %w", t.Target, i+1, len(reqs), err)
+			// the target is unreachable before any config is applied
+			if i == 0 && status.Code(err) == codes.Unavailable {
+				return &ConnectError{Err: err}
+			}
+			return err
+		}
+	}
+
+	return nil
+}
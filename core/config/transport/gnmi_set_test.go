@@ -0,0 +1,228 @@
+package transport
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// setOpStrings returns the operations as "op path = value" strings.
+func setOpStrings(ops []*GNMISetOp) []string {
+	var res []string
+	for _, op := range ops {
+		s := string(op.Op) + " " + op.Path.String()
+		if op.Op != GNMISetDelete {
+			s += " = " + op.Value
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+func TestParseGNMISet(t *testing.T) {
+	tests := map[string]struct {
+		snippet string
+		want    []string
+		err     bool
+	}{
+		"operations": {
+			snippet: `# system
+/system/name/host-name = srl1
+
+replace /interface[name=ethernet-1/1] = {"admin-state": "enable"}
+delete /interface[name=ethernet-1/2]
+update /system/banner/login-banner = "hello world"
+/interface[name=ethernet-1/1]/mtu = 9000`,
+			want: []string{
+				`update /system/name/host-name = "srl1"`,
+				`replace /interface[name=ethernet-1/1] = {"admin-state": "enable"}`,
+				`delete /interface[name=ethernet-1/2]`,
+				`update /system/banner/login-banner = "hello world"`,
+				`update /interface[name=ethernet-1/1]/mtu = 9000`,
+			},
+		},
+		"missing value": {
+			snippet: "/system/name/host-name",
+			err:     true,
+		},
+		"delete with value": {
+			snippet: "delete /system/name = srl1",
+			err:     true,
+		},
+		"invalid path": {
+			snippet: "/interface[name=ethernet-1/1/mtu = 9000",
+			err:     true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			ops, err := ParseGNMISet(tc.snippet)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected an error, got %v", setOpStrings(ops))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if d := cmp.Diff(tc.want, setOpStrings(ops)); d != "" {
+				t.Errorf("operations mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestOrderGNMISet(t *testing.T) {
+	ops, err := ParseGNMISet(`/interface[name=ethernet-1/1]/subinterface[index=0]/admin-state = enable
+/interface[name=ethernet-1/1]/admin-state = enable
+delete /network-instance[name=a]
+delete /network-instance[name=a]/interface[name=ethernet-1/2.0]
+replace /system/name = {"host-name": "srl1"}
+/interface[name=ethernet-1/1]/description = uplink`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		`delete /network-instance[name=a]/interface[name=ethernet-1/2.0]`,
+		`delete /network-instance[name=a]`,
+		`replace /system/name = {"host-name": "srl1"}`,
+		`update /interface[name=ethernet-1/1]/admin-state = "enable"`,
+		`update /interface[name=ethernet-1/1]/description = "uplink"`,
+		`update /interface[name=ethernet-1/1]/subinterface[index=0]/admin-state = "enable"`,
+	}
+	if d := cmp.Diff(want, setOpStrings(orderGNMISet(ops))); d != "" {
+		t.Errorf("order mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestBatchGNMISet(t *testing.T) {
+	ops, _ := ParseGNMISet("/a/b = 1\n/a/c = 2\n/a/d = 3")
+	size := len(marshalSetOp(ops[0], GNMIEncodingJSONIETF))
+
+	tests := map[string]struct {
+		maxSize int
+		want    int
+		err     bool
+	}{
+		"single request":  {maxSize: DefaultGNMIMaxSetSize, want: 1},
+		"split":           {maxSize: 2 * size, want: 2},
+		"one per request": {maxSize: size, want: 3},
+		"too large":       {maxSize: size - 1, err: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			reqs, err := batchGNMISet(ops, GNMIEncodingJSONIETF, tc.maxSize)
+			if tc.err {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(reqs) != tc.want {
+				t.Errorf("got %d requests, want %d", len(reqs), tc.want)
+			}
+			for _, r := range reqs {
+				if len(r) > tc.maxSize {
+					t.Errorf("request of %d bytes exceeds %d", len(r), tc.maxSize)
+				}
+			}
+		})
+	}
+}
+
+// TestSet applies the operations to a gRPC server decoding the SetRequests.
+func TestSet(t *testing.T) {
+	var reqs [][]string
+
+	srv := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+			var req []byte
+			if err := stream.RecvMsg(&req); err != nil {
+				return err
+			}
+
+			var fields []string
+			err := walkFields(req, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+				var p []byte
+				switch num {
+				case fSetRequestDelete:
+					p = v
+				case fSetRequestReplace, fSetRequestUpdate:
+					err := walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+						if num == fUpdatePath {
+							p = v
+						}
+						return nil
+					})
+					if err != nil {
+						return err
+					}
+				}
+				path, err := unmarshalPath(p)
+				fields = append(fields, path.String())
+				return err
+			})
+			if err != nil {
+				return err
+			}
+			reqs = append(reqs, fields)
+
+			rsp := []byte{}
+			return stream.SendMsg(&rsp)
+		}),
+	)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	ops, err := ParseGNMISet(strings.Join([]string{
+		"/system/name/host-name = srl1",
+		"delete /interface[name=ethernet-1/2]",
+		"/system = {}",
+	}, "\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := &GNMITransport{Target: l.Addr().String(), Insecure: true}
+	if err := tx.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Close()
+
+	if err := tx.Set(context.Background(), ops); err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"/interface[name=ethernet-1/2]", "/system", "/system/name/host-name"}}
+	if d := cmp.Diff(want, reqs); d != "" {
+		t.Errorf("set requests mismatch (-want +got):\n%s", d)
+	}
+
+	// the operations are split when exceeding the maximum size
+	reqs = nil
+	tx.MaxSetSize = len(marshalSetOp(ops[0], tx.Encoding)) + 1
+	if err := tx.Set(context.Background(), ops); err != nil {
+		t.Fatal(err)
+	}
+	if len(reqs) != 3 {
+		t.Errorf("got %d set requests, want 3", len(reqs))
+	}
+}
@@ -0,0 +1,166 @@
+package transport
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"google.golang.org/grpc"
+)
+
+// gnoiChunkSize is the size of the file chunks sent by PutFile, below the default gRPC message size limit.
+const gnoiChunkSize = 64 * 1024
+
+// The gNOI operations share the gRPC connection of the gNMI transport,
+// the network OSes serve gNMI and gNOI on the same gRPC server.
+
+// Reboot requests the target to reboot with the method after the delay.
+func (t *GNMITransport) Reboot(ctx context.Context, method GNOIRebootMethod, delay time.Duration,
+	message string, force bool,
+) error {
+	if t.conn == nil {
+		return fmt.Errorf("%s: not connected", t.Target)
+	}
+
+	req := marshalRebootRequest(method, delay, message, force)
+	var rsp []byte
+	err := t.conn.Invoke(t.outgoingContext(ctx), gnoiRebootMethod, &req, &rsp)
+	if err != nil {
+		return fmt.Errorf("%s: reboot failed: %w", t.Target, err)
+	}
+
+	log.Debugf("%s: reboot requested", t.Target)
+	return nil
+}
+
+// Ping runs a ping from the target and calls fn for every reply and the summary.
+func (t *GNMITransport) Ping(ctx context.Context, p *GNOIPing, fn func(r *GNOIPingResponse)) error {
+	if t.conn == nil {
+		return fmt.Errorf("%s: not connected", t.Target)
+	}
+
+	stream, err := t.conn.NewStream(t.outgoingContext(ctx),
+		&grpc.StreamDesc{StreamName: "Ping", ServerStreams: true}, gnoiPingMethod)
+	if err != nil {
+		return err
+	}
+
+	req := p.marshal()
+	if err := stream.SendMsg(&req); err != nil {
+		return fmt.Errorf("%s: ping failed: %w", t.Target, err)
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	for {
+		var rsp []byte
+		err := stream.RecvMsg(&rsp)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: ping failed: %w", t.Target, err)
+		}
+
+		r, err := unmarshalPingResponse(rsp)
+		if err != nil {
+			return fmt.Errorf("%s: invalid ping response: %w", t.Target, err)
+		}
+		fn(r)
+	}
+}
+
+// PutFile transfers the content of r to the remote file on the target with the permissions,
+// e.g. 0o644. The target verifies the transfer with the SHA256 hash of the content.
+func (t *GNMITransport) PutFile(ctx context.Context, r io.Reader, remoteFile string, perms uint32) error {
+	if t.conn == nil {
+		return fmt.Errorf("%s: not connected", t.Target)
+	}
+
+	stream, err := t.conn.NewStream(t.outgoingContext(ctx),
+		&grpc.StreamDesc{StreamName: "Put", ClientStreams: true}, gnoiPutMethod)
+	if err != nil {
+		return err
+	}
+
+	send := func(msg []byte) error {
+		if err := stream.SendMsg(&msg); err != nil {
+			return fmt.Errorf("%s: put %s failed: %w", t.Target, remoteFile, err)
+		}
+		return nil
+	}
+
+	if err := send(marshalPutOpen(remoteFile, perms)); err != nil {
+		return err
+	}
+
+	h := sha256.New()
+	buf := make([]byte, gnoiChunkSize)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			h.Write(buf[:n])
+			if err := send(marshalPutContents(buf[:n])); err != nil {
+				return err
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := send(marshalPutHash(h.Sum(nil))); err != nil {
+		return err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	var rsp []byte
+	if err := stream.RecvMsg(&rsp); err != nil {
+		return fmt.Errorf("%s: put %s failed: %w", t.Target, remoteFile, err)
+	}
+
+	log.Debugf("%s: transferred %s", t.Target, remoteFile)
+	return nil
+}
+
+// InstallCertificate loads the PEM encoded certificate and key on the target under the certificate id,
+// together with the CA certificates validating it, e.g. the certificate issued by the lab CA.
+func (t *GNMITransport) InstallCertificate(ctx context.Context, id string, cert, key []byte,
+	caCerts ...[]byte,
+) error {
+	if t.conn == nil {
+		return fmt.Errorf("%s: not connected", t.Target)
+	}
+
+	stream, err := t.conn.NewStream(t.outgoingContext(ctx),
+		&grpc.StreamDesc{StreamName: "Install", ServerStreams: true, ClientStreams: true}, gnoiInstallMethod)
+	if err != nil {
+		return err
+	}
+
+	req := marshalLoadCertificate(id, cert, key, caCerts...)
+	if err := stream.SendMsg(&req); err != nil {
+		return fmt.Errorf("%s: certificate install failed: %w", t.Target, err)
+	}
+
+	var rsp []byte
+	if err := stream.RecvMsg(&rsp); err != nil {
+		return fmt.Errorf("%s: certificate install failed: %w", t.Target, err)
+	}
+
+	if err := stream.CloseSend(); err != nil {
+		return err
+	}
+
+	log.Debugf("%s: installed certificate %s", t.Target, id)
+	return nil
+}
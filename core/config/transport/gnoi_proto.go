@@ -0,0 +1,243 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// This file contains a minimal wire-level implementation of the gNOI protobuf messages
+// (github.com/openconfig/gnoi system, file and cert protos) used by the gNOI operations.
+// Only the fields required by containerlab are encoded and decoded.
+
+// gNOI RPC methods.
+const (
+	gnoiRebootMethod  = "/gnoi.system.System/Reboot"
+	gnoiPingMethod    = "/gnoi.system.System/Ping"
+	gnoiPutMethod     = "/gnoi.file.File/Put"
+	gnoiInstallMethod = "/gnoi.certificate.CertificateManagement/Install"
+)
+
+// gNOI field numbers.
+const (
+	// RebootRequest.
+	fRebootRequestMethod  = 1
+	fRebootRequestDelay   = 2
+	fRebootRequestMessage = 3
+	fRebootRequestForce   = 5
+	// PingRequest.
+	fPingRequestDestination     = 1
+	fPingRequestSource          = 2
+	fPingRequestCount           = 3
+	fPingRequestNetworkInstance = 10
+	// PingResponse.
+	fPingResponseSource   = 1
+	fPingResponseTime     = 2
+	fPingResponseSent     = 3
+	fPingResponseReceived = 4
+	fPingResponseAvgTime  = 6
+	fPingResponseSequence = 12
+	// PutRequest.
+	fPutRequestOpen     = 1
+	fPutRequestContents = 2
+	fPutRequestHash     = 3
+	fPutDetailsFile     = 1
+	fPutDetailsPerms    = 2
+	fHashTypeMethod     = 1
+	fHashTypeHash       = 2
+	hashMethodSHA256    = 1
+	// InstallCertificateRequest.
+	fInstallRequestLoadCertificate = 2
+	fLoadCertificateCertificate    = 1
+	fLoadCertificateKeyPair        = 2
+	fLoadCertificateID             = 3
+	fLoadCertificateCACertificates = 4
+	fCertificateType               = 1
+	fCertificateCertificate        = 2
+	fKeyPairPrivateKey             = 1
+	certificateTypeX509            = 1
+)
+
+// GNOIRebootMethod is the reboot method requested from the target.
+type GNOIRebootMethod int
+
+const (
+	GNOIRebootCold GNOIRebootMethod = 1
+	GNOIRebootWarm GNOIRebootMethod = 4
+)
+
+// ParseGNOIRebootMethod returns the GNOIRebootMethod for the given name, e.g. cold.
+func ParseGNOIRebootMethod(s string) (GNOIRebootMethod, error) {
+	switch strings.ToLower(s) {
+	case "", "cold":
+		return GNOIRebootCold, nil
+	case "warm":
+		return GNOIRebootWarm, nil
+	}
+	return 0, fmt.Errorf("unsupported reboot method %q, expected cold or warm", s)
+}
+
+// marshalRebootRequest encodes a RebootRequest.
+func marshalRebootRequest(method GNOIRebootMethod, delay time.Duration, message string, force bool) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, fRebootRequestMethod, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(method))
+	if delay > 0 {
+		b = protowire.AppendTag(b, fRebootRequestDelay, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(delay.Nanoseconds()))
+	}
+	if message != "" {
+		b = protowire.AppendTag(b, fRebootRequestMessage, protowire.BytesType)
+		b = protowire.AppendString(b, message)
+	}
+	if force {
+		b = protowire.AppendTag(b, fRebootRequestForce, protowire.VarintType)
+		b = protowire.AppendVarint(b, 1)
+	}
+	return b
+}
+
+// GNOIPing is the request of a gNOI ping.
+type GNOIPing struct {
+	Destination     string
+	Source          string
+	Count           int
+	NetworkInstance string
+}
+
+// marshal encodes a PingRequest.
+func (p *GNOIPing) marshal() []byte {
+	var b []byte
+	b = protowire.AppendTag(b, fPingRequestDestination, protowire.BytesType)
+	b = protowire.AppendString(b, p.Destination)
+	if p.Source != "" {
+		b = protowire.AppendTag(b, fPingRequestSource, protowire.BytesType)
+		b = protowire.AppendString(b, p.Source)
+	}
+	if p.Count > 0 {
+		b = protowire.AppendTag(b, fPingRequestCount, protowire.VarintType)
+		b = protowire.AppendVarint(b, uint64(p.Count))
+	}
+	if p.NetworkInstance != "" {
+		b = protowire.AppendTag(b, fPingRequestNetworkInstance, protowire.BytesType)
+		b = protowire.AppendString(b, p.NetworkInstance)
+	}
+	return b
+}
+
+// GNOIPingResponse is a reply or the summary of a gNOI ping.
+// The summary has Sent set, the replies have Sequence set.
+type GNOIPingResponse struct {
+	Source   string
+	Time     time.Duration
+	Sequence int
+	Sent     int
+	Received int
+	AvgTime  time.Duration
+}
+
+// String implements stringer interface for GNOIPingResponse.
+func (r *GNOIPingResponse) String() string {
+	if r.Sent > 0 {
+		return fmt.Sprintf("%d packets sent, %d received, avg %s", r.Sent, r.Received, r.AvgTime)
+	}
+	return fmt.Sprintf("reply from %s: seq=%d time=%s", r.Source, r.Sequence, r.Time)
+}
+
+// unmarshalPingResponse decodes a PingResponse.
+func unmarshalPingResponse(b []byte) (*GNOIPingResponse, error) {
+	r := &GNOIPingResponse{}
+	err := walkFields(b, func(num protowire.Number, _ protowire.Type, v []byte, varint uint64) error {
+		switch num {
+		case fPingResponseSource:
+			r.Source = string(v)
+		case fPingResponseTime:
+			r.Time = time.Duration(varint)
+		case fPingResponseSent:
+			r.Sent = int(varint)
+		case fPingResponseReceived:
+			r.Received = int(varint)
+		case fPingResponseAvgTime:
+			r.AvgTime = time.Duration(varint)
+		case fPingResponseSequence:
+			r.Sequence = int(varint)
+		}
+		return nil
+	})
+	return r, err
+}
+
+// marshalPutOpen encodes the PutRequest opening the remote file.
+func marshalPutOpen(remoteFile string, perms uint32) []byte {
+	var d []byte
+	d = protowire.AppendTag(d, fPutDetailsFile, protowire.BytesType)
+	d = protowire.AppendString(d, remoteFile)
+	d = protowire.AppendTag(d, fPutDetailsPerms, protowire.VarintType)
+	d = protowire.AppendVarint(d, uint64(perms))
+
+	var b []byte
+	b = protowire.AppendTag(b, fPutRequestOpen, protowire.BytesType)
+	b = protowire.AppendBytes(b, d)
+	return b
+}
+
+// marshalPutContents encodes a PutRequest with a chunk of the file contents.
+func marshalPutContents(chunk []byte) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, fPutRequestContents, protowire.BytesType)
+	b = protowire.AppendBytes(b, chunk)
+	return b
+}
+
+// marshalPutHash encodes the PutRequest with the SHA256 hash closing the transfer.
+func marshalPutHash(sum []byte) []byte {
+	var h []byte
+	h = protowire.AppendTag(h, fHashTypeMethod, protowire.VarintType)
+	h = protowire.AppendVarint(h, hashMethodSHA256)
+	h = protowire.AppendTag(h, fHashTypeHash, protowire.BytesType)
+	h = protowire.AppendBytes(h, sum)
+
+	var b []byte
+	b = protowire.AppendTag(b, fPutRequestHash, protowire.BytesType)
+	b = protowire.AppendBytes(b, h)
+	return b
+}
+
+// marshalCertificate encodes an X.509 Certificate.
+func marshalCertificate(pem []byte) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, fCertificateType, protowire.VarintType)
+	b = protowire.AppendVarint(b, certificateTypeX509)
+	b = protowire.AppendTag(b, fCertificateCertificate, protowire.BytesType)
+	b = protowire.AppendBytes(b, pem)
+	return b
+}
+
+// marshalLoadCertificate encodes an InstallCertificateRequest loading the certificate,
+// its key and the CA certificates under the certificate id.
+func marshalLoadCertificate(id string, cert, key []byte, caCerts ...[]byte) []byte {
+	var l []byte
+	l = protowire.AppendTag(l, fLoadCertificateCertificate, protowire.BytesType)
+	l = protowire.AppendBytes(l, marshalCertificate(cert))
+
+	var kp []byte
+	kp = protowire.AppendTag(kp, fKeyPairPrivateKey, protowire.BytesType)
+	kp = protowire.AppendBytes(kp, key)
+	l = protowire.AppendTag(l, fLoadCertificateKeyPair, protowire.BytesType)
+	l = protowire.AppendBytes(l, kp)
+
+	l = protowire.AppendTag(l, fLoadCertificateID, protowire.BytesType)
+	l = protowire.AppendString(l, id)
+
+	for _, ca := range caCerts {
+		l = protowire.AppendTag(l, fLoadCertificateCACertificates, protowire.BytesType)
+		l = protowire.AppendBytes(l, marshalCertificate(ca))
+	}
+
+	var b []byte
+	b = protowire.AppendTag(b, fInstallRequestLoadCertificate, protowire.BytesType)
+	b = protowire.AppendBytes(b, l)
+	return b
+}
@@ -0,0 +1,123 @@
+package transport
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"io"
+	"net"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestParseGNOIRebootMethod(t *testing.T) {
+	for s, want := range map[string]GNOIRebootMethod{"": GNOIRebootCold, "COLD": GNOIRebootCold, "warm": GNOIRebootWarm} {
+		got, err := ParseGNOIRebootMethod(s)
+		if err != nil || got != want {
+			t.Errorf("ParseGNOIRebootMethod(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseGNOIRebootMethod("powerdown"); err == nil {
+		t.Error("expected an error for an unsupported method")
+	}
+}
+
+func TestUnmarshalPingResponse(t *testing.T) {
+	var b []byte
+	b = protowire.AppendTag(b, fPingResponseSource, protowire.BytesType)
+	b = protowire.AppendString(b, "10.0.0.1")
+	b = protowire.AppendTag(b, fPingResponseTime, protowire.VarintType)
+	b = protowire.AppendVarint(b, uint64(2*time.Millisecond))
+	b = protowire.AppendTag(b, fPingResponseSequence, protowire.VarintType)
+	b = protowire.AppendVarint(b, 3)
+
+	r, err := unmarshalPingResponse(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "reply from 10.0.0.1: seq=3 time=2ms"; r.String() != want {
+		t.Errorf("String() = %q, want %q", r.String(), want)
+	}
+}
+
+// TestPutFile transfers a file to a gRPC server decoding the PutRequest stream.
+func TestPutFile(t *testing.T) {
+	var (
+		remote   string
+		contents []byte
+		hash     []byte
+	)
+
+	srv := grpc.NewServer(
+		grpc.ForceServerCodec(rawCodec{}),
+		grpc.UnknownServiceHandler(func(_ any, stream grpc.ServerStream) error {
+			for {
+				var req []byte
+				err := stream.RecvMsg(&req)
+				if err == io.EOF {
+					rsp := []byte{}
+					return stream.SendMsg(&rsp)
+				}
+				if err != nil {
+					return err
+				}
+
+				err = walkFields(req, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+					switch num {
+					case fPutRequestOpen:
+						return walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+							if num == fPutDetailsFile {
+								remote = string(v)
+							}
+							return nil
+						})
+					case fPutRequestContents:
+						contents = append(contents, v...)
+					case fPutRequestHash:
+						return walkFields(v, func(num protowire.Number, _ protowire.Type, v []byte, _ uint64) error {
+							if num == fHashTypeHash {
+								hash = append([]byte{}, v...)
+							}
+							return nil
+						})
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+			}
+		}),
+	)
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go srv.Serve(l)
+	defer srv.Stop()
+
+	tx := &GNMITransport{Target: l.Addr().String(), Insecure: true}
+	if err := tx.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	defer tx.Close()
+
+	data := bytes.Repeat([]byte("clab"), gnoiChunkSize/2)
+	if err := tx.PutFile(context.Background(), bytes.NewReader(data), "/tmp/clab.bin", 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if remote != "/tmp/clab.bin" {
+		t.Errorf("remote file = %q", remote)
+	}
+	if !bytes.Equal(contents, data) {
+		t.Errorf("received %d bytes, want %d", len(contents), len(data))
+	}
+	if sum := sha256.Sum256(data); !bytes.Equal(hash, sum[:]) {
+		t.Error("hash mismatch")
+	}
+}
@@ -0,0 +1,90 @@
+package transport
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LineLimitKind is implemented by the SSH kinds whose CLI limits the length of a command line.
+type LineLimitKind interface {
+	// MaxLineLength returns the maximum length of a command line
+	MaxLineLength() int
+}
+
+// splitLine splits a config line longer than max characters into several lines,
+// so that the line is not truncated by the CLI of the node.
+// Only the trailing list of a line is split, e.g. `... members [ a b c ]`,
+// every line repeats the path of the list with a part of its items.
+// The items are not split inside quotes. A long line without a list is an error.
+func splitLine(line string, max int) ([]string, error) {
+	if max <= 0 || len(line) <= max {
+		return []string{line}, nil
+	}
+
+	tokens := tokenizeLine(line)
+	open := -1
+	for i, tk := range tokens {
+		if tk == "[" {
+			open = i
+		}
+	}
+	if open < 1 || tokens[len(tokens)-1] != "]" || open == len(tokens)-2 {
+		return nil, fmt.Errorf("line of %d characters exceeds the limit of %d characters and has no list to split: %.60s...",
+			len(line), max, line)
+	}
+
+	prefix := strings.Join(tokens[:open], " ") + " ["
+	items := tokens[open+1 : len(tokens)-1]
+
+	var (
+		res []string
+		cur []string
+	)
+	curLen := len(prefix) + 2 // " ]"
+	for _, item := range items {
+		if len(prefix)+len(item)+4 > max {
+			return nil, fmt.Errorf("list item of %d characters exceeds the limit of %d characters: %.60s...",
+				len(item), max, item)
+		}
+		if len(cur) > 0 && curLen+len(item)+1 > max {
+			res = append(res, prefix+" "+strings.Join(cur, " ")+" ]")
+			cur, curLen = nil, len(prefix)+2
+		}
+		cur = append(cur, item)
+		curLen += len(item) + 1
+	}
+	res = append(res, prefix+" "+strings.Join(cur, " ")+" ]")
+
+	return res, nil
+}
+
+// tokenizeLine splits the line on spaces, keeping the quoted strings in a single token.
+func tokenizeLine(line string) []string {
+	var (
+		tokens  []string
+		cur     strings.Builder
+		quoted  bool
+		escaped bool
+	)
+	for _, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\' && quoted:
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case (r == ' ' || r == '\t') && !quoted:
+			if cur.Len() > 0 {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+			}
+			continue
+		}
+		cur.WriteRune(r)
+	}
+	if cur.Len() > 0 {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens
+}
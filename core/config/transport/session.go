@@ -0,0 +1,33 @@
+package transport
+
+import "io"
+
+// SessionRecorder records the commands sent in a config session and the output received from the node,
+// the commands and the output being recorded as they are exchanged.
+type SessionRecorder interface {
+	Sent(command string)
+	Received(output string)
+}
+
+// recordingReader records the output read from the node.
+type recordingReader struct {
+	r   io.Reader
+	rec SessionRecorder
+}
+
+func (r *recordingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if n > 0 {
+		r.rec.Received(string(p[:n]))
+	}
+
+	return n, err
+}
+
+// recordSent records the command sent in the session. The password prompts are not recorded,
+// as they are answered with Writeln directly.
+func (t *SSHTransport) recordSent(command string) {
+	if t.Session != nil {
+		t.Session.Sent(command)
+	}
+}
@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"path"
+	"strconv"
+	"time"
+
+	"github.com/pkg/sftp"
+	"golang.org/x/crypto/ssh"
+)
+
+// sftpTimeout is the timeout of the SSH connection of the SFTP clients.
+const sftpTimeout = 30 * time.Second
+
+// SFTPClient copies the files to and from a node over SFTP.
+type SFTPClient struct {
+	conn *ssh.Client
+	sftp *sftp.Client
+}
+
+// NewSFTPClient connects to the SFTP subsystem of the node at host, port 22 when the host has no port,
+// logged in with the username and password.
+func NewSFTPClient(ctx context.Context, host, username, password string, dial Dialer) (*SFTPClient, error) {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, strconv.Itoa(22))
+	}
+
+	config := &ssh.ClientConfig{
+		User: username,
+		Auth: []ssh.AuthMethod{ssh.Password(password)},
+		// the host keys of the lab nodes change on every deploy
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(), // skipcq: GSC-G106
+		Timeout:         sftpTimeout,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, sftpTimeout)
+	defer cancel()
+
+	nc, err := dial.dial(ctx, "tcp", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+
+	c, chans, reqs, err := ssh.NewClientConn(nc, host, config)
+	if err != nil {
+		nc.Close()
+		return nil, fmt.Errorf("failed to connect to %s: %w", host, err)
+	}
+	conn := ssh.NewClient(c, chans, reqs)
+
+	client, err := sftp.NewClient(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("SFTP is not available on %s: %w", host, err)
+	}
+
+	return &SFTPClient{conn: conn, sftp: client}, nil
+}
+
+// Put writes the content read from r to the file at the path of the node, creating its parent directories.
+func (c *SFTPClient) Put(p string, r io.Reader, mode fs.FileMode) error {
+	if dir := path.Dir(p); dir != "." && dir != "/" {
+		// the NOS file systems may not report the existing directories, e.g. the cf3: of SR OS
+		_ = c.sftp.MkdirAll(dir)
+	}
+
+	f, err := c.sftp.Create(p)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", p, err)
+	}
+
+	if _, err := f.ReadFrom(r); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write %s: %w", p, err)
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	// not all NOS support the permissions of the files
+	_ = c.sftp.Chmod(p, mode.Perm())
+
+	return nil
+}
+
+// Get writes the content of the file at the path of the node to w.
+func (c *SFTPClient) Get(p string, w io.Writer) error {
+	f, err := c.sftp.Open(p)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", p, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteTo(w); err != nil {
+		return fmt.Errorf("failed to read %s: %w", p, err)
+	}
+
+	return nil
+}
+
+// Close closes the SFTP session and the connection to the node.
+func (c *SFTPClient) Close() error {
+	c.sftp.Close()
+	return c.conn.Close()
+}
@@ -1,11 +1,18 @@
 package transport
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net"
+	"regexp"
 	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -17,22 +24,42 @@ type SSHSession struct {
 	In      io.Reader
 	Out     io.WriteCloser
 	Session *ssh.Session
+	// Client is the connection the session runs on, closed with the session
+	Client *ssh.Client
 }
 
 type SSHTransportOption func(*SSHTransport) error
 
+var (
+	// passwordPromptRe matches the password prompts of a password change forced on the first login.
+	passwordPromptRe = regexp.MustCompile(`(?i)password:\s*$`)
+	// currentPasswordRe matches the password prompts asking for the current password.
+	currentPasswordRe = regexp.MustCompile(`(?i)current|old`)
+)
+
 // SSHReply is SSH reply, executed command and the prompt.
-type SSHReply struct{ result, prompt, command string }
+type SSHReply struct {
+	result, prompt, command string
+	// debug is the debug verbosity of the transport the reply was received on
+	debug int
+	// redact masks the secrets of the logged reply, set by the transport the reply was received on
+	redact func(string) string
+}
 
 // SSHTransport setting needs to be set before calling Connect()
 // SSHTransport implements the Transport interface.
 type SSHTransport struct {
 	// Channel used to read. Can use Expect to Write & read with timeout
 	in chan SSHReply
+	// done is closed by Close to stop the reader of the session
+	done chan struct{}
 	// SSH Session
 	ses *SSHSession
 	// Contains the first read after connecting
 	LoginMessage *SSHReply
+	// Banner is the login banner of the node: the pre-authentication banner, the MOTD
+	// and the acknowledgement prompts received before the CLI prompt
+	Banner string
 	// SSH parameters used in connect
 	// default: 22
 	Port int
@@ -50,6 +77,90 @@ type SSHTransport struct {
 
 	// Kind specific transactions & prompt checking function
 	K SSHKind
+
+	// Maximum number of lines committed in a single transaction,
+	// larger config snippets are committed in chunks. 0 disables chunking
+	// default: kind specific, can be set with the config.chunk-size label
+	ChunkSize int
+
+	// Number of chunks of a config snippet (by info) committed by a previous run,
+	// these chunks are skipped when the snippet is written
+	Resume map[string]int
+
+	// Called after every committed chunk with the number of chunks of the snippet committed so far
+	OnChunkCommit func(info string, chunks int)
+
+	// Maximum length of a config line, longer lines are split into several lines. 0 disables the limit
+	// default: kind specific, can be set with the config.line-length label
+	MaxLineLength int
+
+	// Commit comments of the config snippets (by info), used by the kinds supporting them
+	Comments map[string]string
+
+	// Commit modes of the config snippets (by info), CommitChunks when not set
+	CommitModes map[string]string
+
+	// Sentinel delimits the replies with a sentinel comment sent after every command
+	// instead of the prompt character, for nodes whose output lines can end with the prompt character.
+	// default: false, can be set with the config.sentinel label
+	Sentinel bool
+
+	// Dialer opens the connection to the node, the default net dialer when nil
+	Dialer Dialer
+
+	// Transcript receives the output of the config session as received from the node
+	Transcript io.Writer
+
+	// Session records the commands sent in the config session and the output received, nil disables it
+	Session SessionRecorder
+
+	// Bootstrap are the commands sent once per session before the config is written,
+	// e.g. to disable paging. The terminal setup commands of the kind when nil,
+	// set with the bootstrap template of the node
+	Bootstrap []string
+
+	// ExpectBefore is the expect script run after the login, before the config is written
+	ExpectBefore []ExpectStep
+
+	// ExpectAfter is the expect script run by Flush, after the config is committed
+	ExpectAfter []ExpectStep
+
+	// Password set when the node forces a password change on the first login.
+	// When set, it is also tried first when logging in
+	NewPassword string
+
+	// Context of the write, no more commands are sent once it is done
+	ctx context.Context
+	// debug verbosity, the replies are logged from 2
+	debug int
+	// redact masks the secrets of the logged commands and replies
+	redact func(string) string
+	// password used to log in, answers the current password prompt of a password change
+	password string
+	// terminalReady is set once the terminal setup commands are sent
+	terminalReady bool
+	// comment is the commit comment of the snippet being written
+	comment string
+	// pending are the snippets written with CommitDeferred whose changes are not committed yet
+	pending []string
+	// pendingLines is the number of uncommitted lines of the pending snippets
+	pendingLines int
+	// raw is set once logged in with Sentinel set, the reader emits the data as received
+	raw atomic.Bool
+	// sentinels counts the sentinels sent, every sentinel is unique in the session
+	sentinels int
+
+	// host the transport connects to
+	host string
+	// show is the session running the show commands, separate from the config session
+	// so that show commands can run while a commit is in progress
+	show *SSHTransport
+	// showMu serializes the show commands
+	showMu sync.Mutex
+	// sesMu guards the config session against Abort
+	sesMu sync.Mutex
+	// aborted is set by Abort, no new session is connected once set
+	aborted bool
 }
 
 // WithUserNamePassword adds username & password authentication.
@@ -60,6 +171,92 @@ func WithUserNamePassword(username, password string) SSHTransportOption {
 			tx.SSHConfig.Auth = []ssh.AuthMethod{}
 		}
 		tx.SSHConfig.Auth = append(tx.SSHConfig.Auth, ssh.Password(password))
+		tx.password = password
+		return nil
+	}
+}
+
+// WithNewPassword sets the password of the node. Nodes that force a password change
+// on the first login get their password changed from the initial one to this password.
+func WithNewPassword(password string) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.NewPassword = password
+		return nil
+	}
+}
+
+// WithContext sets the context of the transport.
+// Writing the config stops when the context is done.
+func WithContext(ctx context.Context) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.ctx = ctx
+		return nil
+	}
+}
+
+// WithDebug sets the debug verbosity of the transport.
+func WithDebug(count int) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.debug = count
+		return nil
+	}
+}
+
+// WithRedaction masks the secrets of the commands and replies written to the logs with fn,
+// the commands are sent to the node as is.
+func WithRedaction(fn func(string) string) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.redact = fn
+		return nil
+	}
+}
+
+// WithSentinel delimits the replies of the transport with sentinels instead of the prompt character.
+func WithSentinel() SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.Sentinel = true
+		return nil
+	}
+}
+
+// WithDialer sets the dialer opening the connection to the node.
+func WithDialer(d Dialer) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.Dialer = d
+		return nil
+	}
+}
+
+// WithTranscript copies the output of the config session to w.
+func WithTranscript(w io.Writer) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.Transcript = w
+		return nil
+	}
+}
+
+// WithSessionRecorder records the commands sent in the config session and the output received with r.
+func WithSessionRecorder(r SessionRecorder) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.Session = r
+		return nil
+	}
+}
+
+// WithBootstrap sets the commands sent once per session before the config is written,
+// replacing the terminal setup commands of the kind.
+func WithBootstrap(cmds []string) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.Bootstrap = cmds
+		return nil
+	}
+}
+
+// WithSSHKind sets the SSH kind of the transport, overriding the built-in kind of the node kind.
+// Tools embedding the transport use it to configure node kinds without a built-in SSH kind.
+func WithSSHKind(k SSHKind) SSHTransportOption {
+	return func(tx *SSHTransport) error {
+		tx.K = k
 		return nil
 	}
 }
@@ -84,90 +281,210 @@ func HostKeyCallback(callback ...ssh.HostKeyCallback) SSHTransportOption {
 	}
 }
 
+// NodeHost returns the address the transports connect to, the node's name
+// unless it is overridden with the config.address label, e.g. with a port-forwarded address.
+func NodeHost(node *clabtypes.NodeConfig) string {
+	if a, ok := node.Labels["config.address"]; ok && a != "" {
+		return a
+	}
+	return node.LongName
+}
+
+// SSHKindFor returns the built-in SSH kind of the node kind, nil if the node kind has none.
+func SSHKindFor(kind string) SSHKind {
+	switch kind {
+	case "vr-sros", "nokia_sros":
+		return &VrSrosSSHKind{}
+	case "nokia_srsim", "srsim":
+		return &SrosSSHKind{}
+	case "srl", "nokia_srlinux":
+		return &SrlSSHKind{}
+	}
+	return nil
+}
+
+// SSHSupportsKind returns true if the SSH transport can configure nodes of the kind.
+func SSHSupportsKind(kind string) bool {
+	return SSHKindFor(kind) != nil
+}
+
+// NewSSHTransport creates the SSH transport of the node.
+// The SSH kind is the built-in kind of the node kind unless it is set with WithSSHKind.
 func NewSSHTransport(node *clabtypes.NodeConfig, options ...SSHTransportOption) (*SSHTransport, error) {
-	switch node.Kind {
-	case "vr-sros", "srl", "nokia_sros", "nokia_srsim", "nokia_srlinux":
-		c := &SSHTransport{}
-		c.SSHConfig = &ssh.ClientConfig{}
-
-		// apply options
-		for _, opt := range options {
-			err := opt(c)
-			if err != nil {
-				return nil, err
-			}
+	c := &SSHTransport{ctx: context.Background()}
+	c.SSHConfig = &ssh.ClientConfig{}
+
+	// apply options
+	for _, opt := range options {
+		err := opt(c)
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		switch node.Kind {
-		case "vr-sros", "nokia_sros":
-			c.K = &VrSrosSSHKind{}
-		case "nokia_srsim", "srsim":
-			c.K = &SrosSSHKind{}
-		case "srl", "nokia_srlinux":
-			c.K = &SrlSSHKind{}
+	if c.K == nil {
+		c.K = SSHKindFor(node.Kind)
+	}
+	if c.K == nil {
+		return nil, fmt.Errorf("no transport implemented for kind: %s", node.Kind)
+	}
+
+	if v, ok := node.Labels["config.ssh.port"]; ok {
+		port, err := strconv.Atoi(v)
+		if err != nil || port <= 0 || port > 65535 {
+			return nil, fmt.Errorf("%s: invalid config.ssh.port value %q", node.ShortName, v)
+		}
+		c.Port = port
+	}
+
+	if v, ok := node.Labels["config.sentinel"]; ok {
+		sentinel, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid config.sentinel value %q", node.ShortName, v)
 		}
-		return c, nil
+		c.Sentinel = c.Sentinel || sentinel
 	}
-	return nil, fmt.Errorf("no transport implemented for kind: %s", node.Kind)
+
+	c.ChunkSize = c.K.ChunkSize()
+	if v, ok := node.Labels["config.chunk-size"]; ok {
+		size, err := strconv.Atoi(v)
+		if err != nil || size < 0 {
+			return nil, fmt.Errorf("%s: invalid config.chunk-size value %q", node.ShortName, v)
+		}
+		c.ChunkSize = size
+	}
+
+	if lk, ok := c.K.(LineLimitKind); ok {
+		c.MaxLineLength = lk.MaxLineLength()
+	}
+	if v, ok := node.Labels["config.line-length"]; ok {
+		length, err := strconv.Atoi(v)
+		if err != nil || length < 0 {
+			return nil, fmt.Errorf("%s: invalid config.line-length value %q", node.ShortName, v)
+		}
+		c.MaxLineLength = length
+	}
+
+	return c, nil
 }
 
 // InChannel creates the channel reading the SSH connection.
 //
 // # The first prompt is saved in LoginMessages
 //
-//   - The channel read the SSH session, splits on PromptChar
+//   - The channel read the SSH session, splits on the PromptChar ending the received data
 //   - Uses SSHKind's PromptParse to split the received data in *result* and *prompt* parts
 //     (if no valid prompt was found, prompt will simply be empty and result contain all the data)
 //   - Emit data.
 func (t *SSHTransport) InChannel() {
 	// Ensure we have a working channel
 	t.in = make(chan SSHReply)
+	t.done = make(chan struct{})
+
+	in, done, sin := t.in, t.done, t.ses.In
+
+	// emit hands the reply to Run, false once the transport is closed
+	emit := func(r SSHReply) bool {
+		select {
+		case in <- r:
+			return true
+		case <-done:
+			return false
+		}
+	}
 
 	// setup a buffered string channel
 	go func() {
 		buf := make([]byte, 1024)
 		tmpS := ""
-		n, err := t.ses.In.Read(buf) // this reads the ssh terminal
+		// no prompt was received yet, the node might ask for a password change
+		login := true
+		n, err := sin.Read(buf) // this reads the ssh terminal
 		if err == nil {
 			tmpS = string(buf[:n])
 		}
 		for err == nil {
-			if strings.Contains(tmpS, "#") {
-				parts := strings.Split(tmpS, "#")
-				li := len(parts) - 1
-				for i := 0; i < li; i++ {
-					r := t.K.PromptParse(t, &parts[i])
-					if r == nil {
-						r = &SSHReply{
-							result: parts[i],
-						}
+			if t.raw.Load() {
+				// the replies are delimited by the sentinels in Run
+				if tmpS != "" {
+					if !emit(SSHReply{result: tmpS}) {
+						return
 					}
-					t.in <- *r
+					tmpS = ""
 				}
-				tmpS = parts[li]
+				n, err = sin.Read(buf)
+				tmpS += string(buf[:n])
+				continue
+			}
+			if r, rest, ok := t.splitPrompt(tmpS); ok {
+				if !emit(*r) {
+					return
+				}
+				tmpS = rest
+				login = false
 			}
-			n, err = t.ses.In.Read(buf)
+			if _, ack := t.loginAnswer(tmpS); login && (ack || passwordPromptRe.MatchString(tmpS)) {
+				// emit the password or acknowledgement prompt, it doesn't end with the PromptChar
+				i := strings.LastIndex(tmpS, "\n")
+				if !emit(SSHReply{
+					result: tmpS[:i+1],
+					prompt: strings.TrimSpace(tmpS[i+1:]),
+				}) {
+					return
+				}
+				tmpS = ""
+			}
+			n, err = sin.Read(buf)
 			tmpS += string(buf[:n])
 		}
 		log.Debugf("In Channel closing: %v", err)
-		t.in <- SSHReply{
+		emit(SSHReply{
 			result: tmpS,
 			prompt: "",
-		}
+		})
 	}()
 
 	// Save first prompt
 	t.LoginMessage = t.Run("", 15)
-	if DebugCount > 1 {
+	if t.debug > 1 {
 		t.LoginMessage.Info(t.Target)
 	}
 }
 
+// splitPrompt splits the data received in the reply ending with the prompt and the rest of the data.
+// The node waits for the next command after sending the prompt, so only the last PromptChar
+// followed by nothing but whitespace ends a reply, the PromptChar in the config lines
+// echoed or the output of the command, e.g. in a description or a banner, doesn't.
+// The line of the prompt must be a prompt of the SSH kind and not be empty.
+func (t *SSHTransport) splitPrompt(data string) (*SSHReply, string, bool) {
+	i := strings.LastIndex(data, t.PromptChar)
+	if t.PromptChar == "" || i < 0 || strings.TrimSpace(data[i+len(t.PromptChar):]) != "" {
+		return nil, "", false
+	}
+
+	part := data[:i]
+	if strings.TrimSpace(part[strings.LastIndex(part, "\n")+1:]) == "" {
+		return nil, "", false
+	}
+
+	r := t.K.PromptParse(t, &part)
+	if r == nil {
+		return nil, "", false
+	}
+
+	return r, data[i+len(t.PromptChar):], true
+}
+
 // Run a single command and wait for the reply.
 func (t *SSHTransport) Run(command string, timeout int) *SSHReply {
+	if t.raw.Load() {
+		return t.runSentinel(command, timeout)
+	}
+
 	if command != "" {
 		t.ses.Writeln(command)
-		log.Debugf("--> %s\n", command)
+		t.recordSent(command)
+		log.Debugf("--> %s\n", redactWith(t.redact, command))
 	}
 
 	sHistory := ""
@@ -178,13 +495,16 @@ func (t *SSHTransport) Run(command string, timeout int) *SSHReply {
 
 		select {
 		case <-time.After(time.Duration(timeout) * time.Second):
-			log.Warnf("timeout waiting for prompt: %s", command)
+			log.Warnf("timeout waiting for prompt: %s", redactWith(t.redact, command))
 			return &SSHReply{
 				result:  sHistory,
 				command: command,
+				debug:   t.debug,
+				redact:  t.redact,
 			}
 		case ret := <-t.in:
-			if DebugCount > 1 {
+			ret.redact = t.redact
+			if t.debug > 1 {
 				ret.Debug(t.Target, command+"<--InChannel--")
 			}
 
@@ -196,7 +516,7 @@ func (t *SSHTransport) Run(command string, timeout int) *SSHReply {
 			if ret.prompt == "" && ret.result != "" {
 				// we should continue reading...
 				sHistory += ret.result
-				if DebugCount > 1 {
+				if t.debug > 1 {
 					log.Debugf("+")
 				}
 				timeout = 2 // reduce timeout, node is already sending data
@@ -214,7 +534,7 @@ func (t *SSHTransport) Run(command string, timeout int) *SSHReply {
 			if strings.HasPrefix(rr, command) {
 				rr = strings.Trim(rr[len(command):], " \n\r\t")
 			} else if !strings.Contains(rr, command) {
-				log.Debugf("read more %s:%s", command, rr)
+				log.Debugf("read more %s:%s", redactWith(t.redact, command), redactWith(t.redact, rr))
 				sHistory = rr
 				continue
 			}
@@ -222,52 +542,482 @@ func (t *SSHTransport) Run(command string, timeout int) *SSHReply {
 				result:  rr,
 				prompt:  ret.prompt,
 				command: command,
+				debug:   t.debug,
+				redact:  t.redact,
+			}
+			res.Debug(t.Target, command+"<--RUN--")
+			return res
+		}
+	}
+}
+
+// runSentinel runs the command followed by a sentinel comment and waits for the echo of the sentinel.
+// The reply is the output received between the echo of the command and the sentinel,
+// the prompt character in the output doesn't end the reply.
+func (t *SSHTransport) runSentinel(command string, timeout int) *SSHReply {
+	t.sentinels++
+	sentinel := fmt.Sprintf("<clab-sentinel-%d>", t.sentinels)
+
+	if command != "" {
+		t.ses.Writeln(command)
+		t.recordSent(command)
+		log.Debugf("--> %s\n", redactWith(t.redact, command))
+	}
+	t.ses.Writeln("# " + sentinel)
+
+	buf := ""
+	for {
+		select {
+		case <-time.After(time.Duration(timeout) * time.Second):
+			log.Warnf("timeout waiting for sentinel: %s", redactWith(t.redact, command))
+			return &SSHReply{
+				result:  buf,
+				command: command,
+				debug:   t.debug,
+				redact:  t.redact,
+			}
+		case ret := <-t.in:
+			ret.redact = t.redact
+			if t.debug > 1 {
+				ret.Debug(t.Target, command+"<--InChannel--")
+			}
+
+			buf += ret.result
+			i := strings.Index(buf, sentinel)
+			if i < 0 {
+				timeout = 2 // reduce timeout, node is already sending data
+				continue
 			}
+
+			res := &SSHReply{command: command, debug: t.debug, redact: t.redact}
+			res.result, res.prompt = splitSentinelReply(buf[:i], command)
 			res.Debug(t.Target, command+"<--RUN--")
+
 			return res
 		}
 	}
 }
 
+// splitSentinelReply splits the data received before the sentinel in the output of the command
+// and the prompt, the sentinel comment is echoed on the line of the prompt.
+// The data before the echo of the command, e.g. the prompt of a previous command, is dropped.
+func splitSentinelReply(data, command string) (result, prompt string) {
+	nl := strings.LastIndex(data, "\n")
+	prompt = strings.TrimSpace(data[nl+1:])
+	prompt = strings.TrimSpace(strings.TrimSuffix(prompt, "#"))
+	if nl < 0 {
+		return "", prompt
+	}
+
+	result = data[:nl]
+	if command != "" {
+		if i := strings.Index(result, command); i >= 0 {
+			result = result[i+len(command):]
+		}
+	}
+
+	return strings.Trim(result, " \n\r\t"), prompt
+}
+
 // Write a config snippet (a set of commands)
 // Session NEEDS to be configurable for other kinds
+// Snippets larger than ChunkSize are committed in multiple transactions.
 // Part of the Transport interface.
 func (t *SSHTransport) Write(data, info *string) error {
 	if *data == "" {
 		return nil
 	}
 
-	transaction := !strings.HasPrefix(*info, "show-")
+	return t.WriteFrom(strings.NewReader(*data), *info)
+}
+
+// WriteFrom streams the config lines read from r to the node.
+// The lines are sent as they are read, only the line count and section depth of the current chunk
+// are tracked. The config is not held as a whole unless r holds it, e.g. a strings.Reader,
+// a template rendered into a pipe is sent while it is executed.
+// A chunk is committed once the next config line is read, so that a single chunk is not numbered.
+func (t *SSHTransport) WriteFrom(r io.Reader, info string) error {
+	transaction := !strings.HasPrefix(info, "show-")
+	t.comment = t.Comments[info]
 
-	err := t.K.ConfigStart(t, transaction)
+	mode := t.CommitModes[info]
+	deferred := transaction && mode == CommitDeferred
+
+	ch := &chunker{}
+	if transaction && mode == CommitChunks {
+		ch.size = t.ChunkSize
+	}
+	if k, ok := t.K.(ChunkKind); ok {
+		ch.start = k.ChunkStart
+	}
+
+	skip := t.Resume[info]
+	if skip > 0 {
+		log.Infof("%s %s: resuming after %d committed chunks", t.Target, info, skip)
+	}
+
+	var (
+		chunk   int  // number of the current chunk, from 0
+		lines   int  // lines of the current chunk
+		started bool // the current chunk is not skipped and its config session is started
+	)
+
+	commit := func(last bool) error {
+		msg := info
+		if chunk > 0 || !last {
+			msg = fmt.Sprintf("%s (chunk %d)", info, chunk+1)
+		}
+		switch {
+		case started && deferred:
+			log.Infof("%s %s: commit deferred - %d lines", t.Target, msg, lines)
+			t.pending = append(t.pending, info)
+			t.pendingLines += lines
+		case started:
+			if err := t.commitChunk(msg, lines, transaction); err != nil {
+				return err
+			}
+			if transaction {
+				t.pendingCommitted()
+				if t.OnChunkCommit != nil {
+					t.OnChunkCommit(info, chunk+1)
+				}
+			}
+		}
+		chunk++
+		lines, started = 0, false
+		return nil
+	}
+
+	s := newLineScanner(r)
+	for s.Scan() {
+		l := s.Text()
+
+		if ch.split(l) {
+			if err := commit(false); err != nil {
+				return err
+			}
+		}
+
+		if chunk >= skip {
+			if !started {
+				// the config session holding deferred changes is kept, starting it discards them
+				if !transaction || len(t.pending) == 0 {
+					if err := t.K.ConfigStart(t, transaction); err != nil {
+						return err
+					}
+				}
+				started = true
+			}
+			if err := t.ctx.Err(); err != nil {
+				return err
+			}
+			cmds, err := splitLine(l, t.MaxLineLength)
+			if err != nil {
+				return fmt.Errorf("%s %s: %w", t.Target, info, err)
+			}
+			for _, cmd := range cmds {
+				t.Run(cmd, 5).Info(t.Target)
+			}
+		}
+
+		lines++
+	}
+
+	if err := s.Err(); err != nil {
+		return fmt.Errorf("%s: could not read config %s: %w", t.Target, info, err)
+	}
+
+	if lines > 0 {
+		return commit(true)
+	}
+
+	return nil
+}
+
+// commitChunk commits the lines of a chunk sent to the started config session if transaction is set.
+func (t *SSHTransport) commitChunk(info string, lines int, transaction bool) error {
+	if !transaction {
+		return nil
+	}
+
+	if err := t.ctx.Err(); err != nil {
+		return err
+	}
+
+	commit, err := t.K.ConfigCommit(t)
+	msg := fmt.Sprintf("%s COMMIT - %d lines", info, lines+t.pendingLines)
+	if commit.result != "" {
+		msg += commit.LogString(t.Target, true, false)
+	}
 	if err != nil {
+		log.Error(msg)
 		return err
 	}
+	log.Info(msg)
+
+	return nil
+}
+
+// Flush commits the changes of the snippets written with CommitDeferred that no later snippet committed
+// and runs the ExpectAfter script.
+// Part of the Flusher interface.
+func (t *SSHTransport) Flush() error {
+	if len(t.pending) > 0 {
+		t.comment = ""
+		if err := t.commitChunk(strings.Join(t.pending, ", "), 0, true); err != nil {
+			return err
+		}
+		t.pendingCommitted()
+	}
 
-	c := 0
+	if len(t.ExpectAfter) > 0 {
+		if err := t.RunExpect(t.ExpectAfter); err != nil {
+			return fmt.Errorf("expect script after the config: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// pendingCommitted records the deferred snippets as committed.
+func (t *SSHTransport) pendingCommitted() {
+	if t.OnChunkCommit != nil {
+		for _, info := range t.pending {
+			t.OnChunkCommit(info, 1)
+		}
+	}
+	t.pending, t.pendingLines = nil, 0
+}
+
+// lineScanner reads the config lines of a snippet one at a time,
+// skipping empty lines and comments.
+type lineScanner struct {
+	s    *bufio.Scanner
+	line string
+}
+
+func newLineScanner(r io.Reader) *lineScanner {
+	s := bufio.NewScanner(r)
+	// long lines, e.g. big prefix lists on a single line, are allowed up to 16MB
+	s.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	return &lineScanner{s: s}
+}
 
-	for _, l := range strings.Split(*data, "\n") {
-		l = strings.TrimSpace(l)
-		if l == "" || strings.HasPrefix(l, "#") {
+// Scan advances to the next config line.
+func (l *lineScanner) Scan() bool {
+	for l.s.Scan() {
+		l.line = strings.TrimSpace(l.s.Text())
+		if l.line == "" || strings.HasPrefix(l.line, "#") {
 			continue
 		}
-		c += 1
-		t.Run(l, 5).Info(t.Target)
+		return true
 	}
+	return false
+}
+
+// Text returns the current config line.
+func (l *lineScanner) Text() string {
+	return l.line
+}
+
+// Err returns the error of the underlying reader.
+func (l *lineScanner) Err() error {
+	return l.s.Err()
+}
+
+// configLines returns the config lines of the snippet without empty lines and comments.
+func configLines(data string) []string {
+	var res []string
+	s := newLineScanner(strings.NewReader(data))
+	for s.Scan() {
+		res = append(res, s.Text())
+	}
+	return res
+}
+
+// chunker finds the chunk boundaries of the config lines, chunks have at least size lines
+// and are only split outside of {} blocks, so hierarchical config sections are never split.
+// When start is set, a chunk only starts at the lines it accepts, see ChunkKind.
+// A size of 0 never splits.
+type chunker struct {
+	size  int
+	start func(line string) bool
+	lines int
+	depth int
+}
+
+// split adds the line and returns true when the line starts a new chunk,
+// the lines added before it are then a complete chunk.
+func (c *chunker) split(l string) bool {
+	split := c.size > 0 && c.lines >= c.size && c.depth <= 0 && (c.start == nil || c.start(l))
+	if split {
+		c.lines, c.depth = 0, 0
+	}
+	c.lines++
+	c.depth += strings.Count(l, "{") - strings.Count(l, "}")
+	return split
+}
 
-	if transaction {
-		commit, err := t.K.ConfigCommit(t)
-		msg := fmt.Sprintf("%s COMMIT - %d lines", *info, c)
-		if commit.result != "" {
-			msg += commit.LogString(t.Target, true, false)
+// splitChunks splits the config lines in chunks of at least size lines,
+// at the boundaries found by the chunker with the start function.
+// A size of 0 returns all lines in a single chunk.
+func splitChunks(lines []string, size int, start func(line string) bool) [][]string {
+	var res [][]string
+	var cur []string
+
+	ch := &chunker{size: size, start: start}
+	for _, l := range lines {
+		if ch.split(l) {
+			res = append(res, cur)
+			cur = nil
 		}
-		if err != nil {
-			log.Error(msg)
-			return err
+		cur = append(cur, l)
+	}
+
+	if len(cur) > 0 || len(res) == 0 {
+		res = append(res, cur)
+	}
+
+	return res
+}
+
+// Discard discards the uncommitted changes of the candidate configuration.
+func (t *SSHTransport) Discard() error {
+	err := t.K.ConfigStart(t, false)
+	if err != nil {
+		return err
+	}
+
+	return t.K.ConfigDiscard(t)
+}
+
+// RunningConfig returns the running configuration of the node.
+func (t *SSHTransport) RunningConfig() (string, error) {
+	res, err := t.Show(t.K.RunningConfigCmd(), 30)
+	if err != nil {
+		return "", err
+	}
+
+	if res == "" {
+		return "", fmt.Errorf("%s: empty running configuration", t.Target)
+	}
+
+	return res, nil
+}
+
+// Version returns the software version of the node, using the show session.
+func (t *SSHTransport) Version() (string, error) {
+	vk, ok := t.K.(VersionKind)
+	if !ok {
+		return "", fmt.Errorf("%s: the SSH kind does not report the node version", t.Target)
+	}
+
+	out, err := t.Show(vk.VersionCmd(), 10)
+	if err != nil {
+		return "", err
+	}
+
+	v := vk.ParseVersion(out)
+	if v == "" {
+		return "", fmt.Errorf("%s: no version found in the %q output", t.Target, vk.VersionCmd())
+	}
+
+	return v, nil
+}
+
+// checkpointErrRe matches the errors reported by the checkpoint commands.
+var checkpointErrRe = regexp.MustCompile(`(?im)^\s*error\b.*$`)
+
+// Checkpoint runs the operation on the named checkpoint of the running configuration, using the show session.
+func (t *SSHTransport) Checkpoint(op, name string) error {
+	ck, ok := t.K.(CheckpointKind)
+	if !ok {
+		return fmt.Errorf("%s: the SSH kind does not support config checkpoints", t.Target)
+	}
+
+	out, err := t.Show(ck.CheckpointCmd(op, name), 60)
+	if err != nil {
+		return err
+	}
+
+	if e := checkpointErrRe.FindString(out); e != "" {
+		return fmt.Errorf("%s: could not %s the checkpoint %s: %s", t.Target, op, name, strings.TrimSpace(e))
+	}
+
+	return nil
+}
+
+// ConnectShow opens only the show session to the host,
+// used when no config is written to the node.
+func (t *SSHTransport) ConnectShow(host string) error {
+	t.host = host
+
+	t.showMu.Lock()
+	defer t.showMu.Unlock()
+
+	return t.connectShow()
+}
+
+// Show runs a show command in the show session and returns its output.
+// The show session is opened on first use and is separate from the config session,
+// a command timing out closes the show session, leaving the config session intact.
+// Show is safe to call while the config is written.
+func (t *SSHTransport) Show(command string, timeout int) (string, error) {
+	t.showMu.Lock()
+	defer t.showMu.Unlock()
+
+	if t.show == nil {
+		if err := t.connectShow(); err != nil {
+			return "", err
 		}
-		log.Info(msg)
 	}
 
+	r := t.show.Run(command, timeout)
+	if r.prompt == "" {
+		t.show.Close()
+		t.show = nil
+		return "", fmt.Errorf("%s: timeout running %q", t.Target, command)
+	}
+
+	return r.result, nil
+}
+
+// connectShow opens the show session using the parameters of the transport.
+func (t *SSHTransport) connectShow() error {
+	if t.host == "" {
+		return fmt.Errorf("no host to open the show session to, connect first")
+	}
+
+	s := &SSHTransport{
+		Port:        t.Port,
+		SSHConfig:   t.SSHConfig,
+		PromptChar:  t.PromptChar,
+		K:           t.K,
+		NewPassword: t.NewPassword,
+		Sentinel:    t.Sentinel,
+		Dialer:      t.Dialer,
+		Bootstrap:   t.Bootstrap,
+		ctx:         context.Background(),
+		debug:       t.debug,
+		redact:      t.redact,
+		password:    t.password,
+	}
+
+	if err := s.Connect(t.host); err != nil {
+		return fmt.Errorf("show session: %w", err)
+	}
+
+	if t.Target == "" {
+		t.Target = s.Target
+	}
+
+	if err := t.K.ConfigStart(s, false); err != nil {
+		s.Close()
+		return err
+	}
+
+	t.show = s
+
 	return nil
 }
 
@@ -285,54 +1035,337 @@ func (t *SSHTransport) Connect(host string, _ ...TransportOption) error {
 		return fmt.Errorf("require auth credentials in SSHConfig")
 	}
 
+	t.host = host
+
 	// Start some client config
-	host = fmt.Sprintf("%s:%d", host, t.Port)
+	host = net.JoinHostPort(host, strconv.Itoa(t.Port))
 
 	t.Target = host
 
-	ses_, err := NewSSHSession(host, t.SSHConfig)
+	capture := &loginCapture{}
+	cfg := *t.SSHConfig
+	cfg.BannerCallback = func(msg string) error {
+		capture.Write([]byte(msg))
+		return nil
+	}
+
+	ses_, err := t.newSession(host, &cfg)
 	if err != nil || ses_ == nil {
 		return fmt.Errorf("cannot connect to %s: %s", host, err)
 	}
+
+	t.sesMu.Lock()
+	if t.aborted {
+		t.sesMu.Unlock()
+		ses_.Close()
+		return fmt.Errorf("cannot connect to %s: %w", host, errAborted)
+	}
 	t.ses = ses_
+	t.sesMu.Unlock()
+	t.ses.In = io.TeeReader(t.ses.In, capture)
+	if t.Transcript != nil {
+		t.ses.In = io.TeeReader(t.ses.In, t.Transcript)
+	}
+	if t.Session != nil {
+		t.ses.In = &recordingReader{r: t.ses.In, rec: t.Session}
+	}
 
 	log.Infof("Connected to %s\n", host)
 	t.InChannel()
+	t.readLogin()
+	t.Banner = capture.banner(t.LoginMessage.prompt)
 	// Read to first prompt
+	if passwordPromptRe.MatchString(t.LoginMessage.prompt) {
+		if t.NewPassword == "" {
+			return fmt.Errorf("%s requires a password change on first login, set the password with the config.password label", host)
+		}
+		if err := t.K.FirstLogin(t); err != nil {
+			return err
+		}
+	}
+
+	if len(t.ExpectBefore) > 0 {
+		if err := t.RunExpect(t.ExpectBefore); err != nil {
+			return fmt.Errorf("expect script before the config: %w", err)
+		}
+	}
+
+	// the login is read up to the prompt, the replies are delimited by sentinels from here on
+	if t.Sentinel {
+		t.raw.Store(true)
+	}
+
 	return nil
 }
 
-// Close the Session and channels
+// newSession opens the session to the host with the client config. The new password is tried first,
+// falling back to the configured credentials for the first login of the node.
+func (t *SSHTransport) newSession(host string, config *ssh.ClientConfig) (*SSHSession, error) {
+	if t.NewPassword != "" && t.NewPassword != t.password {
+		cfg := *config
+		cfg.Auth = []ssh.AuthMethod{ssh.Password(t.NewPassword)}
+
+		ses, err := NewSSHSession(host, &cfg, t.terminal(), t.Dialer)
+		if err == nil {
+			return ses, nil
+		}
+		log.Debugf("%s: login with the new password failed, trying the initial password: %s", host, err)
+	}
+
+	return NewSSHSession(host, config, t.terminal(), t.Dialer)
+}
+
+// loginQuiet is the time without output after which the login is read,
+// the replies received until then are part of the banner.
+const loginQuiet = 500 * time.Millisecond
+
+// readLogin answers the acknowledgement prompts of the login and reads the login up to the CLI prompt.
+// The prompt characters of a banner split it in several replies, these are read up to the last prompt
+// so that they don't end up in the reply to the first command.
+func (t *SSHTransport) readLogin() {
+	for i := 0; i < 5; i++ {
+		answer, ok := t.loginAnswer(t.LoginMessage.prompt)
+		if !ok {
+			break
+		}
+
+		log.Infof("%s: acknowledging the login prompt %q", t.Target, t.LoginMessage.prompt)
+		if _, err := t.ses.Writeln(answer); err != nil {
+			return
+		}
+		t.LoginMessage = t.Run("", 15)
+	}
+
+	for {
+		select {
+		case r := <-t.in:
+			if r.prompt != "" {
+				r.redact = t.redact
+				t.LoginMessage = &r
+			}
+		case <-time.After(loginQuiet):
+			return
+		}
+	}
+}
+
+// loginAnswer returns the answer of the SSH kind to the acknowledgement prompt ending data,
+// false when data doesn't end with an acknowledgement prompt.
+func (t *SSHTransport) loginAnswer(data string) (string, bool) {
+	lk, ok := t.K.(LoginKind)
+	if !ok {
+		return "", false
+	}
+
+	return lk.LoginAnswer(data[strings.LastIndex(data, "\n")+1:])
+}
+
+// loginCapture captures the output of a session up to the CLI prompt.
+type loginCapture struct {
+	m    sync.Mutex
+	b    strings.Builder
+	done bool
+}
+
+func (c *loginCapture) Write(p []byte) (int, error) {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	if !c.done {
+		c.b.Write(p)
+	}
+
+	return len(p), nil
+}
+
+// banner stops the capture and returns the output captured before the prompt.
+func (c *loginCapture) banner(prompt string) string {
+	c.m.Lock()
+	defer c.m.Unlock()
+
+	c.done = true
+
+	s := strings.ReplaceAll(c.b.String(), "\r", "")
+	if p := strings.TrimSpace(prompt); p != "" {
+		if i := strings.LastIndex(s, p); i >= 0 {
+			s = s[:i]
+		}
+	}
+
+	return strings.TrimSpace(s)
+}
+
+// terminal returns the terminal of the kind, the default terminal when the kind has none.
+func (t *SSHTransport) terminal() *Terminal {
+	if term := t.K.Terminal(); term != nil {
+		return term
+	}
+	return defaultTerminal
+}
+
+// setupTerminal sends the bootstrap commands of the session, the terminal setup commands
+// of the kind unless Bootstrap is set, once per session.
+func (t *SSHTransport) setupTerminal() {
+	if t.terminalReady {
+		return
+	}
+	t.terminalReady = true
+
+	term := t.terminal()
+	cmds := term.Setup
+	if t.Bootstrap != nil {
+		cmds = t.Bootstrap
+	}
+
+	msg := "terminal setup failed"
+	if term.Hint != "" {
+		msg += ", " + term.Hint
+	}
+
+	for _, cmd := range cmds {
+		r := t.Run(cmd, 5)
+		if r.result != "" {
+			log.Warnf("%s: %s%s", t.Target, msg, r.LogString(t.Target, true, false))
+		}
+	}
+}
+
+// changePassword answers the password prompts of a password change forced on the first login.
+// The current password prompt is answered with the password used to log in, all other prompts
+// with the new password.
+func changePassword(s *SSHTransport) error {
+	r := s.LoginMessage
+	for i := 0; i < 5 && passwordPromptRe.MatchString(r.prompt); i++ {
+		password := s.NewPassword
+		if currentPasswordRe.MatchString(r.prompt) {
+			password = s.password
+		}
+		// not using Run, the password must not be logged
+		if _, err := s.ses.Writeln(password); err != nil {
+			return err
+		}
+		r = s.Run("", 15)
+	}
+
+	if r.prompt == "" || passwordPromptRe.MatchString(r.prompt) {
+		return fmt.Errorf("%s: password change on first login failed: %s", s.Target, r.result)
+	}
+
+	log.Infof("%s: changed the password on first login", s.Target)
+	s.LoginMessage = r
+
+	return nil
+}
+
+// Close discards the changes of the snippets written with CommitDeferred that are still
+// uncommitted, stops the reader of the session and closes the session and its connection.
+// Close returns within closeDiscardTimeout when the node doesn't respond.
 // Part of the Transport interface.
 func (t *SSHTransport) Close() {
-	if t.in != nil {
-		close(t.in)
-		t.in = nil
+	t.showMu.Lock()
+	if t.show != nil {
+		t.show.Close()
+		t.show = nil
+	}
+	t.showMu.Unlock()
+
+	// the changes of an aborted session are discarded by the caller with a new session
+	t.sesMu.Lock()
+	aborted := t.aborted
+	t.sesMu.Unlock()
+
+	if len(t.pending) > 0 && t.ses != nil && !aborted {
+		t.discardPending()
+	}
+
+	if t.done != nil {
+		close(t.done)
+		t.done = nil
+	}
+	if t.ses != nil {
+		t.ses.Close()
+	}
+}
+
+// errAborted is returned by the operations of a transport stopped with Abort.
+var errAborted = errors.New("session aborted")
+
+// Abort closes the connection of the config session, so that the Write in progress fails
+// before any further change is sent or committed. Unlike Close, Abort doesn't talk to the node
+// and is safe to call while Write runs in another goroutine, Close is still called by Write.
+func (t *SSHTransport) Abort() {
+	t.sesMu.Lock()
+	defer t.sesMu.Unlock()
+
+	t.aborted = true
+	if t.ses != nil {
+		t.ses.Close()
 	}
-	t.ses.Close()
+}
+
+// closeDiscardTimeout is the time Close waits for the uncommitted changes to be discarded.
+const closeDiscardTimeout = 30 * time.Second
+
+// discardPending discards the uncommitted changes of the deferred snippets.
+func (t *SSHTransport) discardPending() {
+	log.Warnf("%s: discarding the uncommitted changes of %s", t.Target, strings.Join(t.pending, ", "))
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- t.Discard()
+	}()
+
+	select {
+	case err := <-errCh:
+		if err != nil {
+			log.Warnf("%s: failed to discard the uncommitted changes: %s", t.Target, err)
+		}
+	case <-time.After(closeDiscardTimeout):
+		log.Warnf("%s: timed out discarding the uncommitted changes", t.Target)
+	}
+
+	t.pending, t.pendingLines = nil, 0
 }
 
 // NewSSHSession creates a new SSH session (Dial, open in/out pipes and start the shell)
-// pass the authentication details in sshConfig.
-func NewSSHSession(host string, sshConfig *ssh.ClientConfig) (*SSHSession, error) {
+// pass the authentication details in sshConfig and the PTY dimensions in term.
+func NewSSHSession(host string, sshConfig *ssh.ClientConfig, term *Terminal, dial Dialer) (*SSHSession, error) {
 	if !strings.Contains(host, ":") {
 		return nil, fmt.Errorf("include the port in the host: %s", host)
 	}
 
-	connection, err := ssh.Dial("tcp", host, sshConfig)
+	ctx := context.Background()
+	if sshConfig.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sshConfig.Timeout)
+		defer cancel()
+	}
+
+	conn, err := dial.dial(ctx, "tcp", host)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect: %s", err)
 	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, host, sshConfig)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to connect: %s", err)
+	}
+	connection := ssh.NewClient(c, chans, reqs)
 	session, err := connection.NewSession()
 	if err != nil {
+		connection.Close()
 		return nil, err
 	}
 	sshIn, err := session.StdoutPipe()
 	if err != nil {
+		session.Close()
+		connection.Close()
 		return nil, fmt.Errorf("session stdout: %s", err)
 	}
 	sshOut, err := session.StdinPipe()
 	if err != nil {
+		session.Close()
+		connection.Close()
 		return nil, fmt.Errorf("session stdin: %s", err)
 	}
 	// sshIn2, err := session.StderrPipe()
@@ -343,14 +1376,16 @@ func NewSSHSession(host string, sshConfig *ssh.ClientConfig) (*SSHSession, error
 	modes := ssh.TerminalModes{
 		ssh.ECHO: 1, // disable echo
 	}
-	err = session.RequestPty("dumb", 24, 1000, modes)
+	err = session.RequestPty("dumb", term.Height, term.Width, modes)
 	if err != nil {
 		session.Close()
+		connection.Close()
 		return nil, fmt.Errorf("pty request failed: %s", err)
 	}
 
 	if err := session.Shell(); err != nil {
 		session.Close()
+		connection.Close()
 		return nil, fmt.Errorf("session shell: %s", err)
 	}
 
@@ -358,6 +1393,7 @@ func NewSSHSession(host string, sshConfig *ssh.ClientConfig) (*SSHSession, error
 		Session: session,
 		In:      sshIn,
 		Out:     sshOut,
+		Client:  connection,
 	}, nil
 }
 
@@ -365,11 +1401,23 @@ func (ses *SSHSession) Writeln(command string) (int, error) {
 	return ses.Out.Write([]byte(command + "\r"))
 }
 
+// Close closes the session and the connection it runs on.
 func (ses *SSHSession) Close() {
 	log.Debugf("Closing session")
-	ses.Session.Close()
+	if ses.Session != nil {
+		ses.Session.Close()
+	}
+	if ses.Client != nil {
+		ses.Client.Close()
+	}
 }
 
+// Result returns the reply of the node to the command, without the prompt.
+func (r *SSHReply) Result() string { return r.result }
+
+// Prompt returns the prompt that ended the reply, empty when the reply timed out.
+func (r *SSHReply) Prompt() string { return r.prompt }
+
 // LogString will include the entire SSHReply
 //
 //	Each field will be prefixed by a character.
@@ -390,11 +1438,19 @@ func (r *SSHReply) LogString(node string, linefeed, debug bool) string { // skip
 		s = "" + strings.Repeat(" ", ind) + s
 		s += prefix + "? "
 		s += strings.Join(strings.Split(r.prompt, "\n"), prefix+"? ")
-		if DebugCount > 3 { // add bytestring
+		if r.debug > 3 { // add bytestring
 			s += fmt.Sprintf("%s| %v%s ? %v", prefix, []byte(r.result), prefix, []byte(r.prompt))
 		}
 	}
-	return s
+	return redactWith(r.redact, s)
+}
+
+// redactWith returns s masked with the redact function, s when it is nil.
+func redactWith(redact func(string) string, s string) string {
+	if redact == nil {
+		return s
+	}
+	return redact(s)
 }
 
 func (r *SSHReply) Info(node string) *SSHReply {
@@ -0,0 +1,739 @@
+package transport
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	"golang.org/x/crypto/ssh"
+)
+
+func TestSplitChunks(t *testing.T) {
+	tests := map[string]struct {
+		lines []string
+		size  int
+		start func(line string) bool
+		want  [][]string
+	}{
+		"no chunking": {
+			lines: []string{"a", "b", "c"},
+			size:  0,
+			want:  [][]string{{"a", "b", "c"}},
+		},
+		"smaller than chunk": {
+			lines: []string{"a", "b"},
+			size:  5,
+			want:  [][]string{{"a", "b"}},
+		},
+		"flat lines": {
+			lines: []string{"a", "b", "c", "d", "e"},
+			size:  2,
+			want:  [][]string{{"a", "b"}, {"c", "d"}, {"e"}},
+		},
+		"sections are not split": {
+			lines: []string{"a {", "b", "c", "}", "d", "e {", "f }"},
+			size:  2,
+			want:  [][]string{{"a {", "b", "c", "}"}, {"d", "e {", "f }"}},
+		},
+		"chunks start at the accepted lines": {
+			lines: []string{"/a", "b", "c", "/d", "/e", "f"},
+			size:  2,
+			start: srosChunkStart,
+			want:  [][]string{{"/a", "b", "c"}, {"/d", "/e", "f"}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := splitChunks(tc.lines, tc.size, tc.start)
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("unexpected chunks (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSplitChunksSROSFlat(t *testing.T) {
+	// flat MD-CLI lines entering the context of an absolute /configure line
+	var b strings.Builder
+	for i := range 400 {
+		fmt.Fprintf(&b, "/configure router interface \"if-%d\"\n", i)
+		fmt.Fprintf(&b, "    ipv4 primary address 10.0.%d.%d\n", i/256, i%256)
+		b.WriteString("    ipv4 primary prefix-length 32\n")
+	}
+	lines := configLines(b.String())
+
+	k := &SrosSSHKind{}
+	chunks := splitChunks(lines, k.ChunkSize(), k.ChunkStart)
+	if len(chunks) < 2 {
+		t.Fatalf("want the %d lines split in chunks of %d lines, got %d chunk", len(lines), k.ChunkSize(), len(chunks))
+	}
+
+	var got []string
+	for i, c := range chunks {
+		if !strings.HasPrefix(c[0], "/configure ") {
+			t.Errorf("chunk %d starts in the context of the previous chunk: %q", i+1, c[0])
+		}
+		if i < len(chunks)-1 && len(c) < k.ChunkSize() {
+			t.Errorf("chunk %d has %d lines, want at least %d", i+1, len(c), k.ChunkSize())
+		}
+		got = append(got, c...)
+	}
+	if d := cmp.Diff(lines, got); d != "" {
+		t.Errorf("chunked lines mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestConfigLines(t *testing.T) {
+	got := configLines("# comment\n  a  \n\n\tb\n#another\n")
+	if d := cmp.Diff([]string{"a", "b"}, got); d != "" {
+		t.Errorf("unexpected lines (-want +got):\n%s", d)
+	}
+}
+
+func TestLineScannerLongLines(t *testing.T) {
+	long := "prefix-list big " + strings.Repeat("10.0.0.0/24 ", 20000)
+
+	got := configLines("a\n" + long + "\n# comment\nb")
+	if d := cmp.Diff([]string{"a", strings.TrimSpace(long), "b"}, got); d != "" {
+		t.Errorf("unexpected lines (-want +got):\n%s", d)
+	}
+}
+
+func TestPasswordPrompts(t *testing.T) {
+	tests := map[string]struct {
+		prompt  string
+		match   bool
+		current bool
+	}{
+		"current":         {prompt: "Current password:", match: true, current: true},
+		"unix current":    {prompt: "(current) UNIX password: ", match: true, current: true},
+		"new":             {prompt: "New password:", match: true},
+		"retype":          {prompt: "Retype new password:", match: true},
+		"cli prompt":      {prompt: "A:admin@srl#", match: false},
+		"password in cli": {prompt: "password: set", match: false},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := passwordPromptRe.MatchString(tc.prompt); got != tc.match {
+				t.Errorf("password prompt match of %q = %v, want %v", tc.prompt, got, tc.match)
+			}
+			if !tc.match {
+				return
+			}
+			if got := currentPasswordRe.MatchString(tc.prompt); got != tc.current {
+				t.Errorf("current password match of %q = %v, want %v", tc.prompt, got, tc.current)
+			}
+		})
+	}
+}
+
+func TestShowRequiresHost(t *testing.T) {
+	tx := &SSHTransport{K: &SrlSSHKind{}}
+
+	if _, err := tx.Show("show version", 1); err == nil {
+		t.Fatal("expected an error running a show command before connecting")
+	}
+
+	// closing a transport without sessions is a no-op
+	tx.Close()
+}
+
+func TestNewSSHTransportKind(t *testing.T) {
+	if _, err := NewSSHTransport(&clabtypes.NodeConfig{Kind: "linux"}); err == nil {
+		t.Error("expected an error for a kind without SSH kind")
+	}
+
+	tx, err := NewSSHTransport(&clabtypes.NodeConfig{Kind: "linux"}, WithSSHKind(&SrlSSHKind{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := tx.K.(*SrlSSHKind); !ok {
+		t.Errorf("expected the SSH kind set with WithSSHKind, got %T", tx.K)
+	}
+
+	if !SSHSupportsKind("srsim") {
+		t.Error("expected srsim to be supported")
+	}
+}
+
+func TestSplitSentinelReply(t *testing.T) {
+	tests := map[string]struct {
+		data, command  string
+		result, prompt string
+	}{
+		"prompt character in the output": {
+			data: "A:admin@sr1# \r\n[gl:/configure]\r\nA:admin@sr1# description \"rack #3\"\r\n" +
+				"[gl:/configure]\r\nA:admin@sr1# # ",
+			command: "description \"rack #3\"",
+			result:  "[gl:/configure]",
+			prompt:  "A:admin@sr1#",
+		},
+		"output lines": {
+			data:    "--{ running }--[  ]--\r\nA:srl1# show version\r\nHostname : srl1\r\nChassis  : 7220 #1\r\nA:srl1# # ",
+			command: "show version",
+			result:  "Hostname : srl1\r\nChassis  : 7220 #1",
+			prompt:  "A:srl1#",
+		},
+		"no output": {
+			data:    "A:srl1# # ",
+			command: "",
+			prompt:  "A:srl1#",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			result, prompt := splitSentinelReply(tc.data, tc.command)
+			if result != tc.result || prompt != tc.prompt {
+				t.Errorf("splitSentinelReply() = %q, %q, want %q, %q", result, prompt, tc.result, tc.prompt)
+			}
+		})
+	}
+}
+
+func TestRunPromptCharInData(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	tx := &SSHTransport{
+		ses:        &SSHSession{In: r, Out: nopWriteCloser{io.Discard}},
+		K:          &SrlSSHKind{},
+		PromptChar: "#",
+	}
+	defer tx.Close()
+
+	go w.Write([]byte("Welcome\r\n--{ running }--[  ]--\r\nA:srl1# "))
+	tx.InChannel()
+
+	if !strings.HasSuffix(tx.LoginMessage.Prompt(), "A:srl1#") {
+		t.Fatalf("no login prompt received: %q", tx.LoginMessage.Result())
+	}
+
+	// the replies are received in the order of the commands
+	tests := []struct {
+		name, command, data string
+		result              string
+	}{
+		{
+			name:    "description",
+			command: "set / interface ethernet-1/1 description \"uplink #1\"",
+			data: "set / interface ethernet-1/1 description \"uplink #1\"\r\n" +
+				"--{ * candidate private private-admin }--[  ]--\r\nA:srl1# ",
+		},
+		{
+			name:    "banner",
+			command: "info system banner",
+			data: "info system banner\r\n####\r\n# lab #1\r\n####\r\n" +
+				"--{ * candidate private private-admin }--[  ]--\r\nA:srl1# ",
+			result: "####\r\n# lab #1\r\n####",
+		},
+	}
+
+	for _, tc := range tests {
+		go w.Write([]byte(tc.data))
+
+		reply := tx.Run(tc.command, 2)
+		if reply.Result() != tc.result {
+			t.Errorf("%s: result %q, want %q", tc.name, reply.Result(), tc.result)
+		}
+		if !strings.HasSuffix(reply.Prompt(), "A:srl1#") {
+			t.Errorf("%s: prompt %q, want the A:srl1# prompt", tc.name, reply.Prompt())
+		}
+	}
+}
+
+func TestConnectionOverrides(t *testing.T) {
+	node := &clabtypes.NodeConfig{
+		ShortName: "srl1",
+		LongName:  "clab-lab-srl1",
+		Kind:      "nokia_srlinux",
+		Labels:    map[string]string{"config.address": "127.0.0.1", "config.ssh.port": "2222"},
+	}
+
+	if h := NodeHost(node); h != "127.0.0.1" {
+		t.Errorf("NodeHost() = %q, want the config.address label", h)
+	}
+
+	tx, err := NewSSHTransport(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Port != 2222 {
+		t.Errorf("expected the SSH port of the config.ssh.port label, got %d", tx.Port)
+	}
+
+	g, err := NewGNMITransport(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.Target != "127.0.0.1:57400" {
+		t.Errorf("unexpected gNMI target %s", g.Target)
+	}
+
+	node.Labels["config.ssh.port"] = "ssh"
+	if _, err := NewSSHTransport(node); err == nil {
+		t.Error("expected an error for an invalid config.ssh.port")
+	}
+
+	delete(node.Labels, "config.address")
+	if h := NodeHost(node); h != "clab-lab-srl1" {
+		t.Errorf("NodeHost() = %q, want the long name", h)
+	}
+}
+
+func TestParseVersion(t *testing.T) {
+	tests := map[string]struct {
+		kind VersionKind
+		out  string
+		want string
+	}{
+		"srl": {
+			kind: &SrlSSHKind{},
+			out:  "Hostname             : srl1\nChassis Type         : 7220 IXR-D2L\nSoftware Version     : v24.3.1\nBuild Number         : 343-g9a8d4a3c1a\n",
+			want: "24.3.1",
+		},
+		"sros": {
+			kind: &SrosSSHKind{},
+			out:  "TiMOS-B-24.3.R1 both/x86_64 Nokia 7750 SR Copyright (c) 2000-2024 Nokia.\n",
+			want: "24.3.R1",
+		},
+		"no version": {
+			kind: &SrlSSHKind{},
+			out:  "Error: unknown command",
+			want: "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.kind.ParseVersion(tt.out); got != tt.want {
+				t.Errorf("ParseVersion() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckpointCmd(t *testing.T) {
+	tests := map[string]struct {
+		op   string
+		want string
+	}{
+		CheckpointSave: {
+			op:   CheckpointSave,
+			want: `tools system configuration generate-checkpoint name clab-dc comment "saved by containerlab"`,
+		},
+		CheckpointRevert: {op: CheckpointRevert, want: "tools system configuration checkpoint clab-dc revert"},
+		CheckpointDelete: {op: CheckpointDelete, want: "tools system configuration checkpoint clab-dc clear"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := (&SrlSSHKind{}).CheckpointCmd(tt.op, "clab-dc"); got != tt.want {
+				t.Errorf("CheckpointCmd() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	tests := map[string]struct {
+		line    string
+		max     int
+		want    []string
+		wantErr bool
+	}{
+		"no limit": {
+			line: "set a [ b c ]",
+			want: []string{"set a [ b c ]"},
+		},
+		"short line": {
+			line: "set a [ b c ]",
+			max:  20,
+			want: []string{"set a [ b c ]"},
+		},
+		"list split": {
+			line: "set a [ bb cc dd ee ]",
+			max:  16,
+			want: []string{"set a [ bb cc ]", "set a [ dd ee ]"},
+		},
+		"quoted items": {
+			line: `set a [ "x y" "z w" ]`,
+			max:  16,
+			want: []string{`set a [ "x y" ]`, `set a [ "z w" ]`},
+		},
+		"no list": {
+			line:    `description "` + strings.Repeat("x", 30) + `"`,
+			max:     20,
+			wantErr: true,
+		},
+		"item too long": {
+			line:    "set a [ " + strings.Repeat("x", 30) + " ]",
+			max:     20,
+			wantErr: true,
+		},
+		"empty list": {
+			line:    "set " + strings.Repeat("x", 30) + " [ ]",
+			max:     20,
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := splitLine(tt.line, tt.max)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitLine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("unexpected lines (-want +got):\n%s", d)
+			}
+			for _, l := range got {
+				if tt.max > 0 && len(l) > tt.max {
+					t.Errorf("line %q exceeds %d characters", l, tt.max)
+				}
+			}
+		})
+	}
+}
+
+func TestMaxLineLength(t *testing.T) {
+	node := &clabtypes.NodeConfig{ShortName: "sr1", Kind: "nokia_srsim", Labels: map[string]string{}}
+
+	tx, err := NewSSHTransport(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.MaxLineLength != srosMaxLineLength {
+		t.Errorf("MaxLineLength = %d, want the SR OS limit", tx.MaxLineLength)
+	}
+
+	node.Labels["config.line-length"] = "0"
+	tx, err = NewSSHTransport(node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.MaxLineLength != 0 {
+		t.Errorf("MaxLineLength = %d, want the config.line-length label", tx.MaxLineLength)
+	}
+
+	node.Labels["config.line-length"] = "long"
+	if _, err := NewSSHTransport(node); err == nil {
+		t.Error("expected an error for an invalid config.line-length")
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestCloseStopsReader(t *testing.T) {
+	start := runtime.NumGoroutine()
+
+	r, w := io.Pipe()
+	tx := &SSHTransport{
+		ses:        &SSHSession{In: r, Out: nopWriteCloser{io.Discard}},
+		K:          &SrlSSHKind{},
+		PromptChar: "#",
+	}
+
+	go w.Write([]byte("Welcome\nA:srl1#"))
+	tx.InChannel()
+
+	if tx.LoginMessage.Prompt() == "" {
+		t.Fatalf("no login prompt received: %q", tx.LoginMessage.Result())
+	}
+
+	// a reply nobody waits for blocks the reader until the transport is closed
+	if _, err := w.Write([]byte("unread\nA:srl1#")); err != nil {
+		t.Fatal(err)
+	}
+
+	tx.Close()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for runtime.NumGoroutine() > start {
+		if time.Now().After(deadline) {
+			t.Fatalf("the reader is still running after Close: %d goroutines, %d before", runtime.NumGoroutine(), start)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestCloseAborted(t *testing.T) {
+	r, w := io.Pipe()
+	defer w.Close()
+
+	tx := &SSHTransport{
+		ses:        &SSHSession{In: r, Out: nopWriteCloser{io.Discard}},
+		K:          &SrlSSHKind{},
+		PromptChar: "#",
+		pending:    []string{"snippet"},
+	}
+
+	go w.Write([]byte("Welcome\nA:srl1#"))
+	tx.InChannel()
+
+	// the node never replies, Close must not wait for the discard of the pending changes
+	tx.Abort()
+
+	closed := make(chan struct{})
+	go func() {
+		tx.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Close of an aborted transport waits for the node")
+	}
+}
+
+func TestDefaultLoginAnswer(t *testing.T) {
+	tests := map[string]struct {
+		prompt string
+		answer string
+		ok     bool
+	}{
+		"eula": {
+			prompt: "Do you accept the EULA? [yes/no]: ",
+			answer: "yes",
+			ok:     true,
+		},
+		"acknowledge": {
+			prompt: "Acknowledge the terms of use [y/n]?",
+			answer: "yes",
+			ok:     true,
+		},
+		"press enter": {
+			prompt: "Press ENTER to continue...",
+			ok:     true,
+		},
+		"cli prompt": {
+			prompt: "A:srl1#",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			answer, ok := defaultLoginAnswer(tc.prompt)
+			if answer != tc.answer || ok != tc.ok {
+				t.Errorf("defaultLoginAnswer() = %q, %v, want %q, %v", answer, ok, tc.answer, tc.ok)
+			}
+		})
+	}
+}
+
+func TestReadLogin(t *testing.T) {
+	nodeOut, in := io.Pipe()
+	out, nodeIn := io.Pipe()
+
+	capture := &loginCapture{}
+	tx := &SSHTransport{
+		ses:        &SSHSession{In: io.TeeReader(nodeOut, capture), Out: nodeIn},
+		K:          &SrlSSHKind{},
+		PromptChar: "#",
+	}
+
+	prompt := "--{ running }--[  ]--\nA:srl1# "
+
+	// the node asks to accept the EULA, then shows a banner made of prompt characters
+	go func() {
+		in.Write([]byte("License agreement\nDo you accept the EULA? [yes/no]: "))
+
+		buf := make([]byte, 64)
+		n, _ := out.Read(buf)
+		if string(buf[:n]) != "yes\r" {
+			in.Write([]byte("refused\n"))
+			return
+		}
+
+		in.Write([]byte("\n#########\n# Lab 1 #\n#########\n" + prompt))
+
+		n, _ = out.Read(buf)
+		in.Write([]byte(string(buf[:n-1]) + "\nv24.3.1\n" + prompt))
+	}()
+
+	tx.InChannel()
+	tx.readLogin()
+
+	banner := capture.banner(tx.LoginMessage.prompt)
+	want := "License agreement\nDo you accept the EULA? [yes/no]: \n#########\n# Lab 1 #\n#########"
+	if d := cmp.Diff(want, banner); d != "" {
+		t.Errorf("unexpected banner (-want +got):\n%s", d)
+	}
+
+	if r := tx.Run("show version", 5); r.Result() != "v24.3.1" {
+		t.Errorf("first command result = %q, want v24.3.1", r.Result())
+	}
+
+	tx.Close()
+}
+
+func TestRunExpect(t *testing.T) {
+	nodeOut, in := io.Pipe()
+	out, nodeIn := io.Pipe()
+
+	tx := &SSHTransport{
+		ses:        &SSHSession{In: nodeOut, Out: nodeIn},
+		K:          &SrlSSHKind{},
+		PromptChar: "#",
+	}
+
+	// a bootstrap menu without the prompt character, answered with the option number
+	go func() {
+		in.Write([]byte("--{ running }--[  ]--\nA:srl1# "))
+
+		buf := make([]byte, 64)
+		n, _ := out.Read(buf)
+		if string(buf[:n]) != "bootstrap\r" {
+			return
+		}
+		in.Write([]byte("1) factory\n2) lab\nselect: "))
+
+		n, _ = out.Read(buf)
+		if string(buf[:n]) != "2\r" {
+			return
+		}
+		in.Write([]byte("done\n--{ running }--[  ]--\nA:srl1# "))
+	}()
+
+	tx.InChannel()
+	defer tx.Close()
+
+	steps := []ExpectStep{
+		{Send: "bootstrap", Expect: regexp.MustCompile(`select: $`), Timeout: time.Second},
+		{Send: "2", Expect: regexp.MustCompile(`A:srl1#`)},
+	}
+	if err := tx.RunExpect(steps); err != nil {
+		t.Fatal(err)
+	}
+
+	if tx.raw.Load() {
+		t.Error("the reader was left emitting raw data")
+	}
+
+	err := tx.RunExpect([]ExpectStep{{Expect: regexp.MustCompile("never"), Timeout: 50 * time.Millisecond}})
+	if err == nil || !strings.Contains(err.Error(), "step 1: no output matching") {
+		t.Errorf("RunExpect() error = %v, want a timeout", err)
+	}
+}
+
+// defaultTermKind is an SSH kind without terminal of its own.
+type defaultTermKind struct{ SrlSSHKind }
+
+func (*defaultTermKind) Terminal() *Terminal { return nil }
+
+// ptyServer serves an SSH session on conn and sends the window size of its PTY request.
+func ptyServer(t *testing.T, conn net.Conn, size chan<- [2]uint32) {
+	t.Helper()
+
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	signer, err := ssh.NewSignerFromKey(key)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	cfg := &ssh.ServerConfig{NoClientAuth: true}
+	cfg.AddHostKey(signer)
+
+	_, chans, reqs, err := ssh.NewServerConn(conn, cfg)
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	go ssh.DiscardRequests(reqs)
+
+	for nc := range chans {
+		ch, chReqs, err := nc.Accept()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		go func() {
+			for req := range chReqs {
+				if req.Type == "pty-req" {
+					var pty struct {
+						Term          string
+						Columns, Rows uint32
+						Width, Height uint32
+						Modes         string
+					}
+					if err := ssh.Unmarshal(req.Payload, &pty); err != nil {
+						t.Error(err)
+					}
+					size <- [2]uint32{pty.Columns, pty.Rows}
+				}
+				req.Reply(true, nil)
+			}
+			ch.Close()
+		}()
+	}
+}
+
+func TestTerminalSize(t *testing.T) {
+	tests := map[string]struct {
+		kind SSHKind
+		want [2]uint32
+	}{
+		"kind terminal": {
+			kind: &SrlSSHKind{},
+			want: [2]uint32{512, 24},
+		},
+		"default terminal": {
+			kind: &defaultTermKind{},
+			want: [2]uint32{1000, 24},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:0")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			size := make(chan [2]uint32, 1)
+			go func() {
+				conn, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				ptyServer(t, conn, size)
+			}()
+
+			tx, err := NewSSHTransport(&clabtypes.NodeConfig{Kind: "linux"},
+				WithSSHKind(tt.kind), WithUserNamePassword("admin", "admin"),
+				HostKeyCallback(ssh.InsecureIgnoreHostKey()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ses, err := tx.newSession(ln.Addr().String(), tx.SSHConfig)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ses.Close()
+
+			if d := cmp.Diff(tt.want, <-size); d != "" {
+				t.Errorf("PTY window size mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -2,9 +2,8 @@ package transport
 
 import (
 	"fmt"
+	"regexp"
 	"strings"
-
-	"github.com/charmbracelet/log"
 )
 
 // SSHKind is an interface to implement kind specific methods for transactions and prompt checking.
@@ -13,6 +12,11 @@ type SSHKind interface {
 	ConfigStart(s *SSHTransport, transaction bool) error
 	// Commit a config transaction
 	ConfigCommit(s *SSHTransport) (*SSHReply, error)
+	// Discard the uncommitted changes of a config transaction
+	ConfigDiscard(s *SSHTransport) error
+	// Handle the password change forced on the first login,
+	// called when the login message ends with a password prompt
+	FirstLogin(s *SSHTransport) error
 	// Prompt parsing function
 	//
 	// This function receives string, split by the delimiter and should ensure this is a valid prompt
@@ -21,6 +25,104 @@ type SSHKind interface {
 	// A default implementation is promptParseNoSpaces, which simply ensures there are
 	// no spaces between the start of the line and the #
 	PromptParse(s *SSHTransport, in *string) *SSHReply
+	// Command displaying the running configuration
+	RunningConfigCmd() string
+	// Default maximum number of lines committed in a single transaction, 0 disables chunking
+	ChunkSize() int
+	// PTY dimensions and the commands setting up the terminal of a session,
+	// nil for the default terminal
+	Terminal() *Terminal
+}
+
+// VersionKind is implemented by the SSH kinds reporting the software version of the node.
+type VersionKind interface {
+	// Command displaying the software version
+	VersionCmd() string
+	// ParseVersion returns the version in the output of the version command, e.g. 24.3.1
+	ParseVersion(out string) string
+}
+
+// LoginKind is implemented by the SSH kinds whose images present prompts on login
+// that must be acknowledged before the CLI prompt, e.g. an EULA to accept.
+type LoginKind interface {
+	// LoginAnswer returns the answer to the login prompt,
+	// false when the prompt is not an acknowledgement prompt
+	LoginAnswer(prompt string) (string, bool)
+}
+
+// ChunkKind is implemented by the SSH kinds whose config lines navigate the config context,
+// e.g. the flat MD-CLI lines of SR OS entering the context of a /configure line without braces.
+// Every chunk is sent from the context set by ConfigStart, so a chunk only starts at a line
+// that doesn't depend on the context of the previous lines.
+type ChunkKind interface {
+	// ChunkStart returns true when a chunk can start at the config line
+	ChunkStart(line string) bool
+}
+
+// Operations on the checkpoints of the node configuration.
+const (
+	CheckpointSave   = "save"
+	CheckpointRevert = "revert"
+	CheckpointDelete = "delete"
+)
+
+// CheckpointKind is implemented by the SSH kinds saving named checkpoints of the running configuration,
+// the running configuration is reverted to a checkpoint when a config run fails.
+type CheckpointKind interface {
+	// CheckpointCmd returns the command of the operation on the named checkpoint,
+	// one of CheckpointSave, CheckpointRevert or CheckpointDelete
+	CheckpointCmd(op, name string) string
+}
+
+// loginAnswers are the answers to the acknowledgement prompts the images commonly present on login.
+var loginAnswers = []struct {
+	re     *regexp.Regexp
+	answer string
+}{
+	{regexp.MustCompile(`(?i)(accept|agree).*\[(y|yes)/(n|no)\]\??:?\s*$`), "yes"},
+	{regexp.MustCompile(`(?i)acknowledge.*\[(y|yes)/(n|no)\]\??:?\s*$`), "yes"},
+	{regexp.MustCompile(`(?i)press (any key|enter|return) to continue\W*$`), ""},
+}
+
+// defaultLoginAnswer answers the common acknowledgement prompts.
+func defaultLoginAnswer(prompt string) (string, bool) {
+	for _, a := range loginAnswers {
+		if a.re.MatchString(prompt) {
+			return a.answer, true
+		}
+	}
+	return "", false
+}
+
+var (
+	// srlVersionRe matches the software version in the show version output of SR Linux, e.g. v24.3.1-220-g8a1f25e.
+	srlVersionRe = regexp.MustCompile(`Software Version\s*:\s*v?([\d.]+)`)
+	// srosVersionRe matches the software version in the show version output of SR OS, e.g. TiMOS-B-24.3.R1.
+	srosVersionRe = regexp.MustCompile(`TiMOS-\w+-([\d.]+R\d+)`)
+)
+
+// srosMaxLineLength is the maximum length of an SR OS MD-CLI command line,
+// longer lines are truncated by the node.
+const srosMaxLineLength = 512
+
+// Terminal describes the terminal of an SSH session.
+// Long lines wrapped by the node break the parsing of the replies,
+// the width is set to the maximum supported by the kind.
+type Terminal struct {
+	Width  int
+	Height int
+	// Commands sent once per session by ConfigStart, e.g. to disable paging and line wrapping.
+	// A bootstrap template of the node replaces them, see SSHTransport.Bootstrap
+	Setup []string
+	// Hint is logged when a setup command fails, e.g. the CLI mode the commands require
+	Hint string
+}
+
+// defaultTerminal is the terminal of the kinds without terminal of their own,
+// wide enough for the config lines not to wrap.
+var defaultTerminal = &Terminal{ //nolint:gochecknoglobals
+	Width:  1000,
+	Height: 24,
 }
 
 // VrSrosSSHKind implements SShKind.
@@ -28,11 +130,7 @@ type VrSrosSSHKind struct{}
 
 func (*VrSrosSSHKind) ConfigStart(s *SSHTransport, transaction bool) error { // skipcq: RVV-A0005
 	s.PromptChar = "#" // ensure it's '#'
-
-	r := s.Run("/environment more false", 5)
-	if r.result != "" {
-		log.Warnf("%s Are you in MD-Mode?%s", s.Target, r.LogString(s.Target, true, false))
-	}
+	s.setupTerminal()
 
 	if transaction {
 		s.Run("/configure global", 5).Info(s.Target)
@@ -42,13 +140,31 @@ func (*VrSrosSSHKind) ConfigStart(s *SSHTransport, transaction bool) error { //
 }
 
 func (*VrSrosSSHKind) ConfigCommit(s *SSHTransport) (*SSHReply, error) {
-	res := s.Run("commit", 10)
+	res := s.Run(commitCmd("commit", s.comment), 10)
 	if res.result != "" {
 		return res, fmt.Errorf("could not commit %s", res.result)
 	}
 	return res, nil
 }
 
+func (*VrSrosSSHKind) ConfigDiscard(s *SSHTransport) error {
+	s.Run("/configure global", 5).Info(s.Target)
+	res := s.Run("discard", 5)
+	s.Run("quit-config", 5)
+	if res.result != "" {
+		return fmt.Errorf("could not discard %s", res.result)
+	}
+	return nil
+}
+
+func (*VrSrosSSHKind) FirstLogin(s *SSHTransport) error {
+	return changePassword(s)
+}
+
+func (*VrSrosSSHKind) LoginAnswer(prompt string) (string, bool) {
+	return defaultLoginAnswer(prompt)
+}
+
 func (*VrSrosSSHKind) PromptParse(s *SSHTransport, in *string) *SSHReply {
 	// SROS MD-CLI \r...prompt
 	r := strings.LastIndex(*in, "\r\n\r\n")
@@ -61,16 +177,50 @@ func (*VrSrosSSHKind) PromptParse(s *SSHTransport, in *string) *SSHReply {
 	return nil
 }
 
+func (*VrSrosSSHKind) RunningConfigCmd() string {
+	return "admin show configuration"
+}
+
+func (*VrSrosSSHKind) VersionCmd() string {
+	return "show version"
+}
+
+func (*VrSrosSSHKind) ParseVersion(out string) string {
+	return firstSubmatch(srosVersionRe, out)
+}
+
+func (*VrSrosSSHKind) ChunkSize() int {
+	return 1000
+}
+
+// ChunkStart returns true for the absolute MD-CLI commands, see srosChunkStart.
+func (*VrSrosSSHKind) ChunkStart(line string) bool {
+	return srosChunkStart(line)
+}
+
+// MaxLineLength returns the length limit of the MD-CLI command lines.
+func (*VrSrosSSHKind) MaxLineLength() int {
+	return srosMaxLineLength
+}
+
+func (*VrSrosSSHKind) Terminal() *Terminal {
+	return srosTerminal
+}
+
+// srosTerminal is the terminal of the SR OS MD-CLI sessions.
+var srosTerminal = &Terminal{ //nolint:gochecknoglobals
+	Width:  512,
+	Height: 24,
+	Setup:  []string{"/environment more false", "/environment console width 512"},
+	Hint:   "Are you in MD-Mode?",
+}
+
 // SrosSSHKind implements SShKind.
 type SrosSSHKind struct{}
 
 func (*SrosSSHKind) ConfigStart(s *SSHTransport, transaction bool) error { // skipcq: RVV-A0005
 	s.PromptChar = "#" // ensure it's '#'
-
-	r := s.Run("/environment more false", 5)
-	if r.result != "" {
-		log.Warnf("%s Are you in MD-Mode?%s", s.Target, r.LogString(s.Target, true, false))
-	}
+	s.setupTerminal()
 
 	if transaction {
 		s.Run("/configure global", 5).Info(s.Target)
@@ -80,13 +230,31 @@ func (*SrosSSHKind) ConfigStart(s *SSHTransport, transaction bool) error { // sk
 }
 
 func (*SrosSSHKind) ConfigCommit(s *SSHTransport) (*SSHReply, error) {
-	res := s.Run("commit", 10)
+	res := s.Run(commitCmd("commit", s.comment), 10)
 	if res.result != "" {
 		return res, fmt.Errorf("could not commit %s", res.result)
 	}
 	return res, nil
 }
 
+func (*SrosSSHKind) ConfigDiscard(s *SSHTransport) error {
+	s.Run("/configure global", 5).Info(s.Target)
+	res := s.Run("discard", 5)
+	s.Run("quit-config", 5)
+	if res.result != "" {
+		return fmt.Errorf("could not discard %s", res.result)
+	}
+	return nil
+}
+
+func (*SrosSSHKind) FirstLogin(s *SSHTransport) error {
+	return changePassword(s)
+}
+
+func (*SrosSSHKind) LoginAnswer(prompt string) (string, bool) {
+	return defaultLoginAnswer(prompt)
+}
+
 func (*SrosSSHKind) PromptParse(s *SSHTransport, in *string) *SSHReply {
 	// SROS MD-CLI \r...prompt
 	r := strings.LastIndex(*in, "\r\n\r\n")
@@ -99,11 +267,48 @@ func (*SrosSSHKind) PromptParse(s *SSHTransport, in *string) *SSHReply {
 	return nil
 }
 
+func (*SrosSSHKind) RunningConfigCmd() string {
+	return "admin show configuration"
+}
+
+func (*SrosSSHKind) VersionCmd() string {
+	return "show version"
+}
+
+func (*SrosSSHKind) ParseVersion(out string) string {
+	return firstSubmatch(srosVersionRe, out)
+}
+
+func (*SrosSSHKind) ChunkSize() int {
+	return 1000
+}
+
+// ChunkStart returns true for the absolute MD-CLI commands, see srosChunkStart.
+func (*SrosSSHKind) ChunkStart(line string) bool {
+	return srosChunkStart(line)
+}
+
+// srosChunkStart returns true when the MD-CLI line is an absolute command, e.g. /configure router "Base".
+// The other lines are relative to the context entered by the previous lines.
+func srosChunkStart(line string) bool {
+	return strings.HasPrefix(line, "/")
+}
+
+// MaxLineLength returns the length limit of the MD-CLI command lines.
+func (*SrosSSHKind) MaxLineLength() int {
+	return srosMaxLineLength
+}
+
+func (*SrosSSHKind) Terminal() *Terminal {
+	return srosTerminal
+}
+
 // SrlSSHKind implements SShKind.
 type SrlSSHKind struct{}
 
 func (*SrlSSHKind) ConfigStart(s *SSHTransport, transaction bool) error { // skipcq: RVV-A0005
 	s.PromptChar = "#" // ensure it's '#'
+	s.setupTerminal()
 	if transaction {
 		r0 := s.Run("enter candidate private", 5)
 		r1 := s.Run("discard stay", 2)
@@ -117,7 +322,7 @@ func (*SrlSSHKind) ConfigStart(s *SSHTransport, transaction bool) error { // ski
 }
 
 func (*SrlSSHKind) ConfigCommit(s *SSHTransport) (*SSHReply, error) {
-	r := s.Run("commit now", 10)
+	r := s.Run(commitCmd("commit now", s.comment), 10)
 	if strings.Contains(r.result, "All changes have been committed") {
 		r.result = ""
 	} else {
@@ -126,10 +331,68 @@ func (*SrlSSHKind) ConfigCommit(s *SSHTransport) (*SSHReply, error) {
 	return r, nil
 }
 
+// ConfigDiscard is a no-op, the changes are made in a private candidate
+// which is discarded when the session writing the config is closed.
+func (*SrlSSHKind) ConfigDiscard(_ *SSHTransport) error {
+	return nil
+}
+
+func (*SrlSSHKind) FirstLogin(s *SSHTransport) error {
+	return changePassword(s)
+}
+
+func (*SrlSSHKind) LoginAnswer(prompt string) (string, bool) {
+	return defaultLoginAnswer(prompt)
+}
+
 func (*SrlSSHKind) PromptParse(s *SSHTransport, in *string) *SSHReply {
 	return promptParseNoSpaces(in, s.PromptChar, 2)
 }
 
+func (*SrlSSHKind) RunningConfigCmd() string {
+	return "info flat from running /"
+}
+
+func (*SrlSSHKind) VersionCmd() string {
+	return "show version"
+}
+
+func (*SrlSSHKind) ParseVersion(out string) string {
+	return firstSubmatch(srlVersionRe, out)
+}
+
+// CheckpointCmd returns the tools command of the checkpoint operation, run from the running mode.
+func (*SrlSSHKind) CheckpointCmd(op, name string) string {
+	switch op {
+	case CheckpointSave:
+		return fmt.Sprintf("tools system configuration generate-checkpoint name %s comment \"saved by containerlab\"", name)
+	case CheckpointRevert:
+		return fmt.Sprintf("tools system configuration checkpoint %s revert", name)
+	}
+	return fmt.Sprintf("tools system configuration checkpoint %s clear", name)
+}
+
+func (*SrlSSHKind) ChunkSize() int {
+	return 0
+}
+
+// Terminal of SR Linux uses the basic CLI engine, which does not page or wrap the output.
+func (*SrlSSHKind) Terminal() *Terminal {
+	return &Terminal{
+		Width:  512,
+		Height: 24,
+		Setup:  []string{"environment cli-engine type basic"},
+	}
+}
+
+// commitCmd adds the comment to the commit command.
+func commitCmd(cmd, comment string) string {
+	if comment == "" {
+		return cmd
+	}
+	return fmt.Sprintf("%s comment \"%s\"", cmd, comment)
+}
+
 // This is a helper function to parse the prompt, and can be used by SSHKind's ParsePrompt
 // Used in SRL today.
 func promptParseNoSpaces(in *string, promptChar string, lines int) *SSHReply {
@@ -153,3 +416,12 @@ func promptParseNoSpaces(in *string, promptChar string, lines int) *SSHReply {
 		prompt: (*in)[n:] + promptChar,
 	}
 }
+
+// firstSubmatch returns the first group matched by re in s, empty when re doesn't match.
+func firstSubmatch(re *regexp.Regexp, s string) string {
+	m := re.FindStringSubmatch(s)
+	if m == nil {
+		return ""
+	}
+	return m[1]
+}
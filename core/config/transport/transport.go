@@ -4,9 +4,6 @@ import (
 	"fmt"
 )
 
-// DebugCount is a debug verbosity counter.
-var DebugCount int
-
 type TransportOption func(*Transport)
 
 type Transport interface {
@@ -17,13 +14,44 @@ type Transport interface {
 	Close()
 }
 
+// Commit modes of the config snippets.
+const (
+	// CommitChunks commits the snippet in chunks of the transport's chunk size, the default
+	CommitChunks = ""
+	// CommitPerSnippet commits the whole snippet at once, regardless of the chunk size
+	CommitPerSnippet = "per-snippet"
+	// CommitDeferred leaves the changes of the snippet uncommitted,
+	// they are committed with the next committed snippet or when the transport is flushed
+	CommitDeferred = "false"
+)
+
+// Flusher is implemented by the transports holding back the changes of the written config.
+type Flusher interface {
+	// Flush commits the changes held back
+	Flush() error
+}
+
+// ConnectError is the error of a transport failing to connect to the node,
+// no config was written to the node.
+type ConnectError struct {
+	Err error
+}
+
+func (e *ConnectError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ConnectError) Unwrap() error {
+	return e.Err
+}
+
 // Write config to a node.
 func Write(tx Transport, host string, data, info []string, options ...TransportOption) error {
 	// the Kind should configure the transport parameters before
 
 	err := tx.Connect(host, options...)
 	if err != nil {
-		return fmt.Errorf("%s: %s", host, err)
+		return &ConnectError{Err: fmt.Errorf("%s: %s", host, err)}
 	}
 
 	defer tx.Close()
@@ -35,5 +63,11 @@ func Write(tx Transport, host string, data, info []string, options ...TransportO
 		}
 	}
 
+	if f, ok := tx.(Flusher); ok {
+		if err := f.Flush(); err != nil {
+			return fmt.Errorf("could not commit the deferred config: %s", err)
+		}
+	}
+
 	return nil
 }
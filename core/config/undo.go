@@ -0,0 +1,183 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	clabcore "github.com/srl-labs/containerlab/core"
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// undoPrefix is the prefix of the undo templates, undo-<name>__<role>.tmpl reverses
+// the config of <name>__<role>.tmpl when the lab is destroyed.
+const undoPrefix = "undo-"
+
+// undoFileName is the file in the lab dir recording the config to apply on lab destroy.
+const undoFileName = "config-undo.json"
+
+// undoMu serializes the updates of the undo journal by concurrent config runs of the nodes.
+var undoMu sync.Mutex //nolint:gochecknoglobals
+
+// undoEntry is the config reversing the config committed to a node.
+type undoEntry struct {
+	Node string    `json:"node"`
+	Time time.Time `json:"time"`
+	// Data and Info are the undo snippets, in the order they are applied
+	Data []string `json:"data"`
+	Info []string `json:"info"`
+}
+
+// UndoPath returns the path of the undo journal of the lab.
+func UndoPath(labDir string) string {
+	return filepath.Join(labDir, undoFileName)
+}
+
+func readUndo(path string) (map[string]*undoEntry, error) {
+	res := map[string]*undoEntry{}
+
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return res, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, fmt.Errorf("invalid undo journal %s: %w", path, err)
+	}
+
+	return res, nil
+}
+
+func writeUndo(path string, entries map[string]*undoEntry) error {
+	if len(entries) == 0 {
+		err := os.Remove(path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	b, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644) // skipcq: GSC-G306
+}
+
+// RecordUndo records the undo config of the node in the undo journal of the lab,
+// replacing the undo config of an earlier commit. The undo snippets are applied
+// in the reverse order of the committed snippets.
+func RecordUndo(labDir string, cs *NodeConfig) error {
+	if len(cs.Undo) == 0 {
+		return nil
+	}
+
+	undoMu.Lock()
+	defer undoMu.Unlock()
+
+	path := UndoPath(labDir)
+
+	entries, err := readUndo(path)
+	if err != nil {
+		return err
+	}
+
+	e := &undoEntry{
+		Node: cs.TargetNode.ShortName,
+		Time: time.Now().UTC(),
+		Data: slices.Clone(cs.Undo),
+		Info: slices.Clone(cs.UndoInfo),
+	}
+	slices.Reverse(e.Data)
+	slices.Reverse(e.Info)
+	entries[e.Node] = e
+
+	log.Debugf("%s: recorded %d undo snippets in %s", e.Node, len(e.Data), path)
+
+	return writeUndo(path, entries)
+}
+
+// UndoConfig applies the undo config recorded for the nodes of the lab,
+// reversing the config applied to devices outliving the lab.
+// The entries of the nodes that failed are kept in the journal for the next destroy.
+func UndoConfig(ctx context.Context, c *clabcore.CLab) error {
+	undoMu.Lock()
+	defer undoMu.Unlock()
+
+	path := UndoPath(c.TopoPaths.TopologyLabDir())
+
+	entries, err := readUndo(path)
+	if err != nil || len(entries) == 0 {
+		return err
+	}
+
+	names := make([]string, 0, len(entries))
+	for n := range entries {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	var failed []string
+
+	for _, n := range names {
+		e := entries[n]
+		cs := &NodeConfig{
+			Data: e.Data,
+			Info: e.Info,
+		}
+
+		if node, ok := c.Nodes[n]; ok {
+			cs.TargetNode = node.Config()
+			cs.Credentials = c.Reg.Kind(cs.TargetNode.Kind).GetCredentials().Slice()
+			if err := setNetNSDialer(ctx, node, cs, false); err != nil {
+				log.Warnf("%s: %v", n, err)
+			}
+		} else if ext, ok := c.Config.Topology.External[n]; ok && ext != nil {
+			cs.TargetNode, cs.Credentials = externalTarget(c, n, ext)
+		} else {
+			continue
+		}
+
+		log.Infof("%s: reversing the lab config with %d undo snippets", n, len(e.Data))
+
+		err := writeUndoConfig(ctx, cs)
+		if err != nil {
+			log.Warnf("%s: failed to reverse the lab config: %v", n, err)
+			failed = append(failed, n)
+			continue
+		}
+
+		delete(entries, n)
+	}
+
+	if err := writeUndo(path, entries); err != nil {
+		return err
+	}
+
+	if len(failed) > 0 {
+		return fmt.Errorf("lab config not reversed on nodes: %v, see %s", failed, path)
+	}
+
+	return nil
+}
+
+// writeUndoConfig writes the undo snippets of the node, bounded by the context.
+func writeUndoConfig(ctx context.Context, cs *NodeConfig) error {
+	tx, err := newSSHTransport(cs, transport.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+
+	return transport.Write(tx, transport.NodeHost(cs.TargetNode), cs.Data, cs.Info)
+}
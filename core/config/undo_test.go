@@ -0,0 +1,48 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestRecordUndo(t *testing.T) {
+	dir := t.TempDir()
+
+	nc := &NodeConfig{
+		TargetNode: &clabtypes.NodeConfig{ShortName: "tor1"},
+		Undo:       []string{"delete base", "delete ifaces"},
+		UndoInfo:   []string{"undo-base__srl.tmpl", "undo-ifaces__srl.tmpl"},
+	}
+
+	if err := RecordUndo(dir, nc); err != nil {
+		t.Fatal(err)
+	}
+	// nodes without undo templates are not recorded
+	if err := RecordUndo(dir, &NodeConfig{TargetNode: &clabtypes.NodeConfig{ShortName: "srl1"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := readUndo(UndoPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 1 || entries["tor1"] == nil {
+		t.Fatalf("unexpected undo entries: %v", entries)
+	}
+
+	// the undo snippets are applied in the reverse order
+	if d := cmp.Diff([]string{"delete ifaces", "delete base"}, entries["tor1"].Data); d != "" {
+		t.Errorf("undo data mismatch (-want +got):\n%s", d)
+	}
+
+	delete(entries, "tor1")
+	if err := writeUndo(UndoPath(dir), entries); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := readUndo(UndoPath(dir)); len(entries) != 0 {
+		t.Errorf("expected an empty journal, got %v", entries)
+	}
+}
@@ -5,11 +5,17 @@ import (
 	"io/fs"
 	"net/netip"
 	"reflect"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/log"
 	clabcore "github.com/srl-labs/containerlab/core"
+	clabcoreaaa "github.com/srl-labs/containerlab/core/aaa"
+	clabcorecredstore "github.com/srl-labs/containerlab/core/credstore"
+	clabcorenetsvc "github.com/srl-labs/containerlab/core/netsvc"
+	clablinks "github.com/srl-labs/containerlab/links"
 	clabtypes "github.com/srl-labs/containerlab/types"
 )
 
@@ -28,12 +34,38 @@ const (
 	vkLinkIP   = "clab_link_ip"   // optional, link IP
 	vkLinkName = "clab_link_name" // optional, from ShortNames
 	vkLinkNum  = "clab_link_num"  // optional, link number in case you have multiple, used to calculate the name
+	vkPort     = "port"           // optional, will default to the interface name of the link endpoint
+
+	vkLag        = "clab_lag"  // optional, name of the bundle (LAG) the link is a member of
+	vkLags       = "clab_lags" // reserved, bundles (LAGs) of the node
+	vkLagMembers = "members"   // reserved, member links of a bundle
+	vkLagPeers   = "peers"     // reserved, far-end nodes of a bundle, more than one for multi-homed (ESI) LAGs
+
+	vkTopologyLinks = "clab_topology_links" // reserved, all links of the topology
+	vkNeighbors     = "clab_neighbors"      // reserved, links of the node grouped by the far-end node
+	vkLinkA         = "a"                   // reserved, A-side of a topology link
+	vkLinkB         = "b"                   // reserved, B-side of a topology link
+
+	vkExternal = "clab_external" // reserved, true for the external nodes of the topology
+	vkFacts    = "clab_facts"    // reserved, runtime facts of all nodes, e.g. mgmt IPs and link IPs
+	vkAAA      = "clab_aaa"      // reserved, AAA server started with tools aaa, empty without a server
+	vkLab      = "clab_lab"      // reserved, lab metadata, e.g. name, mgmt network, DNS servers and owner
+	vkNetSvc   = "clab_netsvc"   // reserved, NTP and DNS service started with tools netsvc, empty without a service
 )
 
 type Dict map[string]interface{}
 
 // PrepareVars variables for all nodes. This will also prepare all variables for the links.
-func PrepareVars(c *clabcore.CLab) map[string]*NodeConfig {
+func PrepareVars(c *clabcore.CLab, opts ...VarsOption) (map[string]*NodeConfig, error) {
+	o := &varsOptions{}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	if err := applyProfile(c, o.profile); err != nil {
+		return nil, err
+	}
+
 	res := make(map[string]*NodeConfig)
 
 	// preparing all nodes vars
@@ -49,8 +81,8 @@ func PrepareVars(c *clabcore.CLab) map[string]*NodeConfig {
 
 		// Init array for this node
 		for key, val := range nodeCfg.Config.Vars {
-			if key == vkNodes || key == vkNodeName {
-				log.Warnf("the variable %s on %s will be ignored, it hides other nodes", vkNodes, name)
+			if isReservedVar(key) {
+				log.Warnf("the variable %s on %s will be ignored, it is reserved", key, name)
 				continue
 			}
 			vars[key] = val
@@ -73,6 +105,66 @@ func PrepareVars(c *clabcore.CLab) map[string]*NodeConfig {
 		}
 	}
 
+	topoLinks, err := prepareLinks(c, res)
+	if err != nil {
+		return nil, err
+	}
+
+	err = prepareExternal(c, res)
+	if err != nil {
+		return nil, err
+	}
+
+	store, err := clabcorecredstore.Load(clabcorecredstore.Path())
+	if err != nil {
+		return nil, err
+	}
+	for _, nc := range res {
+		nc.credStore = store
+		nc.lab = c.Config.Name
+	}
+
+	rotated, err := LoadCredentials(CredentialsPath(c.TopoPaths.TopologyLabDir()))
+	if err != nil {
+		return nil, err
+	}
+	for name, creds := range rotated {
+		if nc, ok := res[name]; ok && len(creds) == 2 {
+			nc.Credentials = creds
+			nc.rotated = true
+		}
+	}
+
+	facts, err := loadFacts(c.TopoPaths.FactsFileAbsPath())
+	if err != nil {
+		return nil, err
+	}
+	addNodeFacts(facts, res)
+
+	aaaVars := Dict{}
+	aaaServer, err := clabcoreaaa.LoadServer(c.TopoPaths.AAAServerFileAbsPath())
+	if err != nil {
+		return nil, err
+	}
+	if aaaServer != nil {
+		aaaVars = aaaServer.Vars()
+	}
+
+	labMeta := labVars(c, res, facts, labDeployTime(c.TopoPaths.FactsFileAbsPath()))
+
+	netsvcVars := Dict{}
+	netsvcServer, err := clabcorenetsvc.LoadServer(c.TopoPaths.NetSvcFileAbsPath())
+	if err != nil {
+		return nil, err
+	}
+	if netsvcServer != nil {
+		netsvcVars = netsvcServer.Vars()
+	}
+
+	for _, nc := range res {
+		nc.Vars[vkLags] = linkBundles(nc.Vars[vkLinks].([]interface{}))
+	}
+
 	// Prepare top-level map of nodes
 	// copy 1-level deep
 	all_nodes := make(Dict)
@@ -84,6 +176,149 @@ func PrepareVars(c *clabcore.CLab) map[string]*NodeConfig {
 		}
 		nc.Vars[vkNodes] = all_nodes
 	}
+
+	// topology-wide data is added after the copy, it is the same for all nodes
+	for _, nc := range res {
+		nc.Vars[vkTopologyLinks] = topoLinks
+		nc.Vars[vkFacts] = facts
+		nc.Vars[vkAAA] = aaaVars
+		nc.Vars[vkLab] = labMeta
+		nc.Vars[vkNetSvc] = netsvcVars
+		nc.Vars[vkNeighbors] = linkNeighbors(nc.Vars[vkLinks].([]interface{}))
+	}
+	return res, nil
+}
+
+// isReservedVar returns true for the variables that can't be set in the topology.
+func isReservedVar(key string) bool {
+	switch key {
+	case vkNodes, vkNodeName, vkTopologyLinks, vkNeighbors, vkExternal, vkFacts, vkAAA, vkLab, vkNetSvc:
+		return true
+	}
+	return false
+}
+
+// prepareLinks adds the variables of the links between the nodes to the links of both nodes
+// and returns all links of the topology with the variables of both sides.
+// Links to the host, the management network and the filtered out nodes are skipped.
+func prepareLinks(c *clabcore.CLab, res map[string]*NodeConfig) ([]interface{}, error) {
+	if len(c.Links) == 0 {
+		err := c.ResolveLinks()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	topoLinks := []interface{}{}
+
+	ids := make([]int, 0, len(c.Links))
+	for id := range c.Links {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		l, ok := c.Links[id].(*clablinks.LinkVEth)
+		if !ok || len(l.Endpoints) != 2 {
+			continue
+		}
+
+		epA, epB := l.Endpoints[0], l.Endpoints[1]
+		ncA, okA := res[epA.GetNode().GetShortName()]
+		ncB, okB := res[epB.GetNode().GetShortName()]
+		if !okA || !okB {
+			continue
+		}
+
+		link := &clabtypes.Link{
+			A:      &clabtypes.Endpoint{Node: ncA.TargetNode, EndpointName: epA.GetIfaceDisplayName()},
+			B:      &clabtypes.Endpoint{Node: ncB.TargetNode, EndpointName: epB.GetIfaceDisplayName()},
+			MTU:    l.MTU,
+			Labels: l.Labels,
+			Vars:   l.Vars,
+		}
+
+		varsA, varsB := make(Dict), make(Dict)
+		err := prepareLinkVars(link, varsA, varsB)
+		if err != nil {
+			return nil, err
+		}
+
+		ncA.Vars[vkLinks] = append(ncA.Vars[vkLinks].([]interface{}), varsA)
+		ncB.Vars[vkLinks] = append(ncB.Vars[vkLinks].([]interface{}), varsB)
+
+		topoLinks = append(topoLinks, Dict{
+			vkLinkA: linkSide(ncA.TargetNode.ShortName, varsA),
+			vkLinkB: linkSide(ncB.TargetNode.ShortName, varsB),
+		})
+	}
+
+	return topoLinks, nil
+}
+
+// linkSide returns the variables of one side of a link without the far-end variables.
+func linkSide(node string, vars Dict) Dict {
+	res := Dict{vkNodeName: node}
+	for k, v := range vars {
+		if k != vkFarEnd {
+			res[k] = v
+		}
+	}
+	return res
+}
+
+// linkNeighbors groups the links of a node by the far-end node,
+// parallel links to the same node are kept in the order of the links.
+func linkNeighbors(links []interface{}) Dict {
+	res := make(Dict)
+
+	for _, l := range links {
+		vars, ok := l.(Dict)
+		if !ok {
+			continue
+		}
+
+		peer := fmt.Sprintf("%v", vars[vkFarEnd].(Dict)[vkNodeName])
+		nl, _ := res[peer].([]interface{})
+		res[peer] = append(nl, vars)
+	}
+
+	return res
+}
+
+// linkBundles groups the links of a node with the clab_lag variable into bundles by the bundle name.
+// Every bundle contains its member links and the far-end nodes of the members.
+func linkBundles(links []interface{}) Dict {
+	res := make(Dict)
+
+	for _, l := range links {
+		vars, ok := l.(Dict)
+		if !ok {
+			continue
+		}
+		name, ok := vars[vkLag]
+		if !ok {
+			continue
+		}
+
+		key := fmt.Sprintf("%v", name)
+		lag, ok := res[key].(Dict)
+		if !ok {
+			lag = Dict{vkLagMembers: []interface{}{}, vkLagPeers: []string{}}
+			res[key] = lag
+		}
+
+		lag[vkLagMembers] = append(lag[vkLagMembers].([]interface{}), vars)
+
+		peer := fmt.Sprintf("%v", vars[vkFarEnd].(Dict)[vkNodeName])
+		peers := lag[vkLagPeers].([]string)
+		if !slices.Contains(peers, peer) {
+			peers = append(peers, peer)
+			sort.Strings(peers)
+			lag[vkLagPeers] = peers
+		}
+	}
+
 	return res
 }
 
@@ -136,6 +371,7 @@ func prepareLinkVars(link *clabtypes.Link, varsA, varsB Dict) error {
 	add := map[string]func(link *clabtypes.Link) (string, string, error){
 		vkLinkIP:   linkIP,
 		vkLinkName: linkName,
+		vkPort:     linkPort,
 	}
 
 	for k, f := range add {
@@ -164,6 +400,11 @@ func linkName(link *clabtypes.Link) (string, string, error) { //nolint: unparam,
 		fmt.Sprintf("to_%s%s", link.A.Node.ShortName, linkNo), nil
 }
 
+// Use the interface names of the endpoints as link ports.
+func linkPort(link *clabtypes.Link) (string, string, error) { //nolint: unparam
+	return link.A.EndpointName, link.B.EndpointName, nil
+}
+
 // Calculate link IP from the system IPs at both ends.
 func linkIP(link *clabtypes.Link) (string, string, error) { //nolint: gocritic
 	var ipA netip.Prefix
@@ -259,7 +500,8 @@ func ipFarEnd(in netip.Prefix) netip.Prefix {
 
 // GetTemplateNamesInDirs returns a list of template file names found in a list of dir `paths`
 // without traversing nested dirs
-// template names are following the pattern <some-name>__<role/kind>.tmpl.
+// template names are following the pattern <some-name>__<role/kind>.tmpl
+// or <some-name>__<role/kind>.j2 for Jinja2 templates.
 func GetTemplateNamesInDirs(dirs []fs.FS) ([]string, error) {
 	var tnames []string
 	for _, dir := range dirs {
@@ -267,8 +509,24 @@ func GetTemplateNamesInDirs(dirs []fs.FS) ([]string, error) {
 		if err != nil {
 			return nil, err
 		}
+		j2, err := fs.Glob(dir, "*__*.j2")
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, j2...)
+		intents, err := fs.Glob(dir, "*__*.json")
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, intents...)
+		sort.Strings(all)
 		for _, fn := range all {
 			tn := strings.Split(fn, "__")[0]
+			// undo templates are rendered with the templates they reverse
+			// and the bootstrap templates are not config snippets
+			if strings.HasPrefix(tn, undoPrefix) || tn == bootstrapTemplate {
+				continue
+			}
 			// skip adding templates with the same name
 			if len(tnames) > 0 && tnames[len(tnames)-1] == tn {
 				continue
@@ -277,7 +535,7 @@ func GetTemplateNamesInDirs(dirs []fs.FS) ([]string, error) {
 		}
 	}
 	if len(tnames) == 0 {
-		return nil, fmt.Errorf("no templates files were found in specified paths: %v", TemplatePaths)
+		return nil, fmt.Errorf("no templates files were found in the template paths")
 	}
 	return tnames, nil
 }
@@ -156,6 +156,40 @@ func TestPrepareLinkVars(t *testing.T) {
 	})
 }
 
+func TestLinkBundles(t *testing.T) {
+	l1 := Dict{vkPort: "e1-1", vkLag: "lag1", vkFarEnd: Dict{vkNodeName: "leaf2"}}
+	l2 := Dict{vkPort: "e1-2", vkLag: "lag1", vkFarEnd: Dict{vkNodeName: "leaf1"}}
+	l3 := Dict{vkPort: "e1-3", vkLag: "lag2", vkFarEnd: Dict{vkNodeName: "leaf1"}}
+	l4 := Dict{vkPort: "e1-4", vkFarEnd: Dict{vkNodeName: "leaf1"}}
+
+	lags := linkBundles([]interface{}{l1, l2, l3, l4})
+	assert(t, lags, Dict{
+		"lag1": Dict{
+			vkLagMembers: []interface{}{l1, l2},
+			vkLagPeers:   []string{"leaf1", "leaf2"},
+		},
+		"lag2": Dict{
+			vkLagMembers: []interface{}{l3},
+			vkLagPeers:   []string{"leaf1"},
+		},
+	})
+
+	assert(t, linkBundles([]interface{}{l4}), Dict{})
+}
+
+func TestLinkNeighbors(t *testing.T) {
+	l1 := Dict{vkPort: "e1-1", vkFarEnd: Dict{vkNodeName: "spine1"}}
+	l2 := Dict{vkPort: "e1-2", vkFarEnd: Dict{vkNodeName: "spine2"}}
+	l3 := Dict{vkPort: "e1-3", vkFarEnd: Dict{vkNodeName: "spine1"}}
+
+	assert(t, linkNeighbors([]interface{}{l1, l2, l3}), Dict{
+		"spine1": []interface{}{l1, l3},
+		"spine2": []interface{}{l2},
+	})
+
+	assert(t, linkSide("leaf1", l1), Dict{vkNodeName: "leaf1", vkPort: "e1-1"})
+}
+
 func TestIPfarEndS(t *testing.T) {
 	ipA := "10.0.3.0/31"
 	feA, err := ipFarEndS(ipA)
@@ -167,3 +201,61 @@ func TestIPfarEndS(t *testing.T) {
 	assert(t, err.Error(), "invalid ip 10.0.3.0/30 - invalid Prefix")
 	assert(t, feA, "")
 }
+
+func TestDiffLines(t *testing.T) {
+	old := `# Generated Mon Jan 1 00:00:00 2025
+set / system name host-name srl1
+set / interface ethernet-1/1 admin-state enable
+set / interface ethernet-1/1 admin-state enable
+`
+	cur := `# Generated Tue Jan 2 00:00:00 2025
+set / system name host-name srl1
+set / interface ethernet-1/1 admin-state enable
+set / interface ethernet-1/2 admin-state enable
+`
+
+	added, removed := diffLines(old, cur)
+
+	if d := cmp.Diff([]string{"set / interface ethernet-1/2 admin-state enable"}, added); d != "" {
+		t.Errorf("added lines mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff([]string{"set / interface ethernet-1/1 admin-state enable"}, removed); d != "" {
+		t.Errorf("removed lines mismatch (-want +got):\n%s", d)
+	}
+
+	added, removed = diffLines(old, old)
+	if len(added) != 0 || len(removed) != 0 {
+		t.Errorf("expected no drift, got +%v -%v", added, removed)
+	}
+}
+
+func TestLinkAdjacencies(t *testing.T) {
+	configs := map[string]*NodeConfig{
+		"leaf1": {Vars: Dict{vkLinks: []interface{}{
+			Dict{vkPort: "e1-2", vkLinkIP: "10.0.0.3/31", vkFarEnd: Dict{vkNodeName: "spine2", vkPort: "e1-1", vkLinkIP: "10.0.0.2/31"}},
+			Dict{vkPort: "e1-1", vkLinkIP: "10.0.0.1/31", vkFarEnd: Dict{vkNodeName: "spine1", vkPort: "e1-1", vkLinkIP: "10.0.0.0/31"}},
+			// links without IPs are not adjacencies
+			Dict{vkPort: "e1-3", vkFarEnd: Dict{vkNodeName: "host1", vkPort: "eth1"}},
+		}}},
+		"host1": {Vars: Dict{}},
+	}
+
+	want := []*Adjacency{
+		{Node: "leaf1", Port: "e1-1", IP: "10.0.0.1", Peer: "spine1", PeerPort: "e1-1", PeerIP: "10.0.0.0"},
+		{Node: "leaf1", Port: "e1-2", IP: "10.0.0.3", Peer: "spine2", PeerPort: "e1-1", PeerIP: "10.0.0.2"},
+	}
+
+	if d := cmp.Diff(want, LinkAdjacencies(configs)); d != "" {
+		t.Errorf("LinkAdjacencies() mismatch (-want +got):\n%s", d)
+	}
+
+	wantLinks := []*Adjacency{
+		{Node: "leaf1", Port: "e1-1", IP: "10.0.0.1/31", Peer: "spine1", PeerPort: "e1-1", PeerIP: "10.0.0.0/31"},
+		{Node: "leaf1", Port: "e1-2", IP: "10.0.0.3/31", Peer: "spine2", PeerPort: "e1-1", PeerIP: "10.0.0.2/31"},
+		{Node: "leaf1", Port: "e1-3", Peer: "host1", PeerPort: "eth1"},
+	}
+
+	if d := cmp.Diff(wantLinks, NodeLinks(configs)); d != "" {
+		t.Errorf("NodeLinks() mismatch (-want +got):\n%s", d)
+	}
+}
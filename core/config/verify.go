@@ -0,0 +1,290 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/srl-labs/containerlab/core/config/transport"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// errConverged stops the subscription once all checks passed.
+var errConverged = errors.New("converged")
+
+// CheckResult is the result of a single verification check.
+type CheckResult struct {
+	Path     string
+	Expected string
+	// Got is the last value received for the path
+	Got    string
+	Passed bool
+}
+
+// VerifyResult is the result of the verification checks of a node.
+type VerifyResult struct {
+	Node    string
+	Checks  []*CheckResult
+	Elapsed time.Duration
+	Err     error
+}
+
+// Passed returns true when all checks of the node passed.
+func (r *VerifyResult) Passed() bool {
+	if r.Err != nil {
+		return false
+	}
+	for _, c := range r.Checks {
+		if !c.Passed {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements stringer interface for VerifyResult.
+func (r *VerifyResult) String() string {
+	if r.Err != nil {
+		return fmt.Sprintf("%s: FAIL %s", r.Node, r.Err)
+	}
+	var s strings.Builder
+	res := "PASS"
+	if !r.Passed() {
+		res = "FAIL"
+	}
+	fmt.Fprintf(&s, "%s: %s (%s)", r.Node, res, r.Elapsed.Round(time.Millisecond))
+	for _, c := range r.Checks {
+		if !c.Passed {
+			fmt.Fprintf(&s, "\n    %s expected %q, got %q", c.Path, c.Expected, c.Got)
+		}
+	}
+	return s.String()
+}
+
+// Verify subscribes to the paths of the node's verification checks via gNMI
+// and waits until all paths report the expected values or the timeout expires.
+// Checks with a show command are verified against the records parsed from the command output,
+// the tests of the node assert on the output of their commands.
+func Verify(ctx context.Context, cs *NodeConfig, timeout time.Duration) *VerifyResult {
+	res := &VerifyResult{Node: cs.TargetNode.ShortName}
+	start := time.Now()
+	defer func() { res.Elapsed = time.Since(start) }()
+
+	all, err := cs.verifyChecks()
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	var checks, showChecks []*clabtypes.VerifyCheck
+	for _, c := range all {
+		if c.Command != "" {
+			showChecks = append(showChecks, c)
+			continue
+		}
+		checks = append(checks, c)
+	}
+
+	if len(showChecks) > 0 {
+		verifyShow(ctx, cs, showChecks, timeout, res)
+		if res.Err != nil {
+			return res
+		}
+		timeout -= time.Since(start)
+	}
+
+	tests, err := cs.configTests()
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	if len(tests) > 0 {
+		testsStart := time.Now()
+		runTests(ctx, cs, tests, res)
+		if res.Err != nil {
+			return res
+		}
+		timeout -= time.Since(testsStart)
+	}
+
+	if len(checks) == 0 {
+		return res
+	}
+
+	paths := make([]*transport.GNMIPath, 0, len(checks))
+	for _, c := range checks {
+		p, err := transport.ParseGNMIPath(c.Path)
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		res.Checks = append(res.Checks, &CheckResult{Path: p.String(), Expected: c.Value})
+		paths = append(paths, p)
+	}
+	pathChecks := newPathChecks(paths, res.Checks)
+
+	tx, err := newGNMITransport(cs)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+
+	if err := tx.Connect(); err != nil {
+		res.Err = err
+		return res
+	}
+	defer tx.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var m sync.Mutex
+	err = tx.Subscribe(ctx, paths, func(u *transport.GNMIUpdate) error {
+		m.Lock()
+		defer m.Unlock()
+
+		pathChecks.update(u)
+
+		for _, c := range res.Checks {
+			if !c.Passed {
+				return nil
+			}
+		}
+		return errConverged
+	})
+
+	switch {
+	case errors.Is(err, errConverged):
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Debugf("%s: verification timed out after %s", res.Node, timeout)
+	case err != nil:
+		res.Err = err
+	}
+
+	return res
+}
+
+// pathChecks are the gNMI checks of a node with the values received for the paths they match.
+type pathChecks struct {
+	paths  []*transport.GNMIPath
+	checks []*CheckResult
+	// values are the values of the target paths matched by the checks
+	values []map[string]string
+}
+
+func newPathChecks(paths []*transport.GNMIPath, checks []*CheckResult) *pathChecks {
+	pc := &pathChecks{paths: paths, checks: checks, values: make([]map[string]string, len(checks))}
+	for i := range pc.values {
+		pc.values[i] = map[string]string{}
+	}
+	return pc
+}
+
+// update records the value of the update in the checks whose path matches the update path.
+// The target returns the paths with the module prefixes and the keys of its schema, and a wildcard
+// path of a check matches several paths: the check passes when all matched paths have the expected value.
+// Got is the value of the first matched path without the expected value.
+func (pc *pathChecks) update(u *transport.GNMIUpdate) {
+	for i, p := range pc.paths {
+		if !u.Path.Matches(p) {
+			continue
+		}
+
+		cr := pc.checks[i]
+		pc.values[i][u.Path.String()] = u.Value
+
+		cr.Got, cr.Passed = u.Value, true
+		for _, tp := range slices.Sorted(maps.Keys(pc.values[i])) {
+			if v := pc.values[i][tp]; v != cr.Expected {
+				cr.Got, cr.Passed = v, false
+				break
+			}
+		}
+	}
+}
+
+// showCheckInterval is the interval between the runs of the show commands of the show checks.
+const showCheckInterval = 2 * time.Second
+
+// verifyShow runs the show commands of the checks until all checks pass or the timeout expires.
+// The output of every command is parsed into records, the value of a check is the field
+// selected by its path. The last output and records are stored in the node's lab dir.
+func verifyShow(ctx context.Context, cs *NodeConfig, checks []*clabtypes.VerifyCheck,
+	timeout time.Duration, res *VerifyResult,
+) {
+	results := make([]*CheckResult, len(checks))
+	templates := map[string]*regexp.Regexp{}
+	for i, c := range checks {
+		re, err := showTemplate(cs.TargetNode.Kind, c.Command, c.Template)
+		if err != nil {
+			res.Err = err
+			return
+		}
+		templates[c.Command] = re
+
+		if _, err := lookupField(nil, c.Path); err != nil {
+			res.Err = err
+			return
+		}
+
+		results[i] = &CheckResult{Path: c.Command + ": " + c.Path, Expected: c.Value}
+		res.Checks = append(res.Checks, results[i])
+	}
+
+	tx, err := newSSHTransport(cs)
+	if err != nil {
+		res.Err = err
+		return
+	}
+
+	if err := tx.ConnectShow(transport.NodeHost(cs.TargetNode)); err != nil {
+		res.Err = err
+		return
+	}
+	defer tx.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		records := map[string][]ShowRecord{}
+		for cmd, re := range templates {
+			out, err := tx.Show(cmd, 10)
+			if err != nil {
+				res.Err = err
+				return
+			}
+			records[cmd] = ParseShow(re, out)
+
+			if err := saveShow(cs, cmd, out, records[cmd]); err != nil {
+				log.Warnf("%s: failed to save the output of %q: %v", res.Node, cmd, err)
+			}
+		}
+
+		passed := true
+		for i, c := range checks {
+			results[i].Got, _ = lookupField(records[c.Command], c.Path)
+			results[i].Passed = results[i].Got == c.Value
+			passed = passed && results[i].Passed
+		}
+
+		if passed {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			log.Debugf("%s: show checks timed out after %s", res.Node, timeout)
+			return
+		case <-time.After(showCheckInterval):
+		}
+	}
+}
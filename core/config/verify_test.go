@@ -0,0 +1,54 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+func TestPathChecksUpdate(t *testing.T) {
+	mustPath := func(s string) *transport.GNMIPath {
+		p, err := transport.ParseGNMIPath(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	paths := []*transport.GNMIPath{
+		mustPath("/interface[name=ethernet-1/1]/oper-state"),
+		mustPath("/interface[name=*]/admin-state"),
+	}
+	checks := []*CheckResult{
+		{Path: paths[0].String(), Expected: "up"},
+		{Path: paths[1].String(), Expected: "enable"},
+	}
+	pc := newPathChecks(paths, checks)
+
+	for _, u := range []struct{ path, value string }{
+		{"/srl_nokia-interfaces:interface[name=ethernet-1/1]/oper-state", "up"},
+		{"/srl_nokia-interfaces:interface[name=ethernet-1/1]/admin-state", "enable"},
+		{"/srl_nokia-interfaces:interface[name=ethernet-1/2]/admin-state", "disable"},
+		{"/srl_nokia-interfaces:interface[name=ethernet-1/3]/admin-state", "enable"},
+	} {
+		pc.update(&transport.GNMIUpdate{Path: mustPath(u.path), Value: u.value})
+	}
+
+	want := []*CheckResult{
+		{Path: paths[0].String(), Expected: "up", Got: "up", Passed: true},
+		// ethernet-1/2 is disabled
+		{Path: paths[1].String(), Expected: "enable", Got: "disable"},
+	}
+	if d := cmp.Diff(want, checks); d != "" {
+		t.Errorf("check results mismatch (-want +got):\n%s", d)
+	}
+
+	pc.update(&transport.GNMIUpdate{
+		Path:  mustPath("/srl_nokia-interfaces:interface[name=ethernet-1/2]/admin-state"),
+		Value: "enable",
+	})
+	if !checks[1].Passed {
+		t.Errorf("want the wildcard check to pass once all interfaces are enabled, got %q", checks[1].Got)
+	}
+}
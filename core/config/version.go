@@ -0,0 +1,105 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/srl-labs/containerlab/core/config/transport"
+)
+
+// HasVersionConstraints returns true when the templates of the node declare
+// the software versions of the node they support.
+func (c *NodeConfig) HasVersionConstraints() bool {
+	return c.TargetNode.Config.GetMinVersion() != "" || c.TargetNode.Config.GetVersion() != ""
+}
+
+// CheckVersion queries the software version of the node and validates it against
+// the min-version and version constraints of the node's config.
+func CheckVersion(cs *NodeConfig) error {
+	if !cs.HasVersionConstraints() {
+		return nil
+	}
+
+	tx, err := newSSHTransport(cs)
+	if err != nil {
+		return err
+	}
+
+	err = tx.ConnectShow(transport.NodeHost(cs.TargetNode))
+	if err != nil {
+		return err
+	}
+	defer tx.Close()
+
+	v, err := tx.Version()
+	if err != nil {
+		return err
+	}
+
+	return checkVersionConstraints(v, cs.TargetNode.Config.GetMinVersion(), cs.TargetNode.Config.GetVersion())
+}
+
+// checkVersionConstraints validates the version against the minimum version and the targeted release.
+func checkVersionConstraints(v, minVersion, version string) error {
+	if minVersion != "" && compareVersions(v, minVersion) < 0 {
+		return fmt.Errorf("node runs version %s, the templates need %s or newer", v, minVersion)
+	}
+
+	if version != "" && !versionMatches(v, version) {
+		return fmt.Errorf("node runs version %s, the templates target %s", v, version)
+	}
+
+	return nil
+}
+
+// versionSegments returns the numeric segments of a version, e.g. [24 3 1] for 24.3.R1 or v24.3.1-220.
+func versionSegments(v string) []int {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	v, _, _ = strings.Cut(v, "-")
+
+	var res []int
+	for _, s := range strings.Split(v, ".") {
+		n, err := strconv.Atoi(strings.TrimLeft(s, "RrVv"))
+		if err != nil {
+			break
+		}
+		res = append(res, n)
+	}
+	return res
+}
+
+// compareVersions compares the numeric segments of the versions, missing segments count as 0.
+func compareVersions(a, b string) int {
+	sa, sb := versionSegments(a), versionSegments(b)
+	for i := 0; i < len(sa) || i < len(sb); i++ {
+		var x, y int
+		if i < len(sa) {
+			x = sa[i]
+		}
+		if i < len(sb) {
+			y = sb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// versionMatches returns true when the version is the release, e.g. 24.3.1 is release 24.3.
+func versionMatches(v, release string) bool {
+	sv, sr := versionSegments(v), versionSegments(release)
+	if len(sr) == 0 || len(sv) < len(sr) {
+		return false
+	}
+	for i := range sr {
+		if sv[i] != sr[i] {
+			return false
+		}
+	}
+	return true
+}
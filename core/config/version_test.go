@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestCheckVersionConstraints(t *testing.T) {
+	tests := map[string]struct {
+		v, minVersion, version string
+		ok                     bool
+	}{
+		"no constraints":       {v: "24.3.1", ok: true},
+		"newer than min":       {v: "24.10.1", minVersion: "24.3", ok: true},
+		"same as min":          {v: "24.3.0", minVersion: "24.3", ok: true},
+		"older than min":       {v: "23.10.4", minVersion: "24.3"},
+		"sros release":         {v: "24.3.R1", minVersion: "23.10.R2", ok: true},
+		"sros older":           {v: "23.7.R1", minVersion: "23.10"},
+		"srl build suffix":     {v: "v24.3.1-220-g8a1f25e", version: "24.3", ok: true},
+		"other release":        {v: "24.7.1", version: "24.3"},
+		"release more precise": {v: "24.3", version: "24.3.1"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkVersionConstraints(tt.v, tt.minVersion, tt.version)
+			if (err == nil) != tt.ok {
+				t.Errorf("checkVersionConstraints(%q, %q, %q) = %v, want ok %v",
+					tt.v, tt.minVersion, tt.version, err, tt.ok)
+			}
+		})
+	}
+}
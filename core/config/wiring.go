@@ -0,0 +1,289 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	clabcore "github.com/srl-labs/containerlab/core"
+	clablinks "github.com/srl-labs/containerlab/links"
+)
+
+// lldpNeighborCommand is the show command listing the LLDP neighbors by kind.
+var lldpNeighborCommand = map[string]string{ //nolint:gochecknoglobals
+	"srl":           "show system lldp neighbor",
+	"nokia_srlinux": "show system lldp neighbor",
+	"vr-sros":       "show system lldp neighbor",
+	"nokia_sros":    "show system lldp neighbor",
+	"nokia_srsim":   "show system lldp neighbor",
+	"srsim":         "show system lldp neighbor",
+}
+
+// lldpNeighborParser parses the output of the LLDP neighbor show command by kind.
+var lldpNeighborParser = map[string]func(string) []*LLDPNeighbor{ //nolint:gochecknoglobals
+	"srl":           parseSRLLLDPNeighbors,
+	"nokia_srlinux": parseSRLLLDPNeighbors,
+	"vr-sros":       parseSROSLLDPNeighbors,
+	"nokia_sros":    parseSROSLLDPNeighbors,
+	"nokia_srsim":   parseSROSLLDPNeighbors,
+	"srsim":         parseSROSLLDPNeighbors,
+}
+
+// srosLLDPNeighborRe matches the neighbor lines of the SR OS LLDP neighbor table:
+// local port, scope, remote chassis ID, index, remote port and remote system name.
+var srosLLDPNeighborRe = regexp.MustCompile(`^(\S+)\s+(NB|NTPMR|NC)\s+(\S+)\s+(\d+)\s+(.*?)\s+(\S+)\s*$`)
+
+// LLDPNeighbor is a neighbor discovered with LLDP on a local port of a node.
+type LLDPNeighbor struct {
+	Port       string
+	SystemName string
+	RemotePort string
+}
+
+// LLDPSupportsKind returns true if the LLDP neighbors of the nodes of the kind can be read.
+func LLDPSupportsKind(kind string) bool {
+	_, ok := lldpNeighborCommand[kind]
+	return ok
+}
+
+// LLDPNeighbors reads the LLDP neighbors of the node with the show command of its kind.
+func LLDPNeighbors(cs *NodeConfig) ([]*LLDPNeighbor, error) {
+	kind := cs.TargetNode.Kind
+	if !LLDPSupportsKind(kind) {
+		return nil, fmt.Errorf("reading the LLDP neighbors is not implemented for kind %s", kind)
+	}
+
+	outs, err := Collect(cs, []string{lldpNeighborCommand[kind]})
+	if err != nil {
+		return nil, err
+	}
+	if outs[0].Err != nil {
+		return nil, outs[0].Err
+	}
+
+	return ParseLLDPNeighbors(kind, outs[0].Output), nil
+}
+
+// ParseLLDPNeighbors parses the output of the LLDP neighbor show command of a node of the kind.
+func ParseLLDPNeighbors(kind, output string) []*LLDPNeighbor {
+	p, ok := lldpNeighborParser[kind]
+	if !ok {
+		return nil
+	}
+
+	return p(output)
+}
+
+// parseSRLLLDPNeighbors parses the SR Linux LLDP neighbor table, locating its columns by the header.
+func parseSRLLLDPNeighbors(output string) []*LLDPNeighbor {
+	var res []*LLDPNeighbor
+	port, sysName, remotePort := -1, -1, -1
+
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			continue
+		}
+
+		cols := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cols {
+			cols[i] = strings.TrimSpace(cols[i])
+		}
+
+		if port < 0 {
+			for i, c := range cols {
+				switch c {
+				case "Name":
+					port = i
+				case "Neighbor System Name":
+					sysName = i
+				case "Neighbor Port":
+					remotePort = i
+				}
+			}
+			continue
+		}
+
+		if sysName < 0 || remotePort < 0 || len(cols) <= max(port, sysName, remotePort) {
+			continue
+		}
+
+		res = append(res, &LLDPNeighbor{
+			Port:       cols[port],
+			SystemName: cols[sysName],
+			RemotePort: cols[remotePort],
+		})
+	}
+
+	return res
+}
+
+// parseSROSLLDPNeighbors parses the SR OS LLDP neighbor table.
+func parseSROSLLDPNeighbors(output string) []*LLDPNeighbor {
+	var res []*LLDPNeighbor
+
+	for _, line := range strings.Split(output, "\n") {
+		m := srosLLDPNeighborRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+
+		remotePort, _, _ := strings.Cut(m[5], ",")
+		res = append(res, &LLDPNeighbor{
+			Port:       m[1],
+			SystemName: m[6],
+			RemotePort: strings.TrimSpace(remotePort),
+		})
+	}
+
+	return res
+}
+
+// Wiring statuses of a link endpoint.
+const (
+	WiringOK         = "ok"
+	WiringDown       = "down"
+	WiringMiscabled  = "miscabled"
+	WiringUnverified = "unverified"
+)
+
+// WiringEndpoint is an endpoint of a topology link between two lab nodes.
+type WiringEndpoint struct {
+	Node  string
+	Kind  string
+	Iface string
+	Alias string
+}
+
+// String returns the endpoint as <node>:<interface>.
+func (e *WiringEndpoint) String() string {
+	if e.Alias != "" {
+		return e.Node + ":" + e.Alias
+	}
+	return e.Node + ":" + e.Iface
+}
+
+// WiringLink is a topology link between two lab nodes.
+type WiringLink struct {
+	A, B *WiringEndpoint
+}
+
+// WiringResult is the wiring verification of a link endpoint: the neighbor discovered
+// on the interface of the endpoint matches the peer endpoint of the link.
+type WiringResult struct {
+	Endpoint *WiringEndpoint
+	Peer     *WiringEndpoint
+	Status   string
+	// Detail is the neighbor seen on a miscabled link or the reason a link is unverified.
+	Detail string
+}
+
+// WiringLinks returns the veth links between the nodes of the lab, in the order of their endpoints.
+func WiringLinks(c *clabcore.CLab) []*WiringLink {
+	var res []*WiringLink
+
+	for _, l := range c.Links {
+		veth, ok := l.(*clablinks.LinkVEth)
+		if !ok || len(veth.GetEndpoints()) != 2 { //nolint: mnd
+			continue
+		}
+
+		var eps []*WiringEndpoint
+		for _, ep := range veth.GetEndpoints() {
+			n, ok := c.Nodes[ep.GetNode().GetShortName()]
+			if !ok {
+				break
+			}
+			eps = append(eps, &WiringEndpoint{
+				Node:  n.GetShortName(),
+				Kind:  n.Config().Kind,
+				Iface: ep.GetIfaceName(),
+				Alias: ep.GetIfaceAlias(),
+			})
+		}
+		if len(eps) != 2 { //nolint: mnd
+			continue
+		}
+
+		res = append(res, &WiringLink{A: eps[0], B: eps[1]})
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].A.String() < res[j].A.String()
+	})
+
+	return res
+}
+
+// VerifyWiring verifies both endpoints of the links against the LLDP neighbors of their nodes.
+// The nodes missing from neighbors have their endpoints unverified, with the reason in nodeErrs.
+func VerifyWiring(links []*WiringLink, neighbors map[string][]*LLDPNeighbor,
+	nodeErrs map[string]error,
+) []*WiringResult {
+	res := make([]*WiringResult, 0, 2*len(links)) //nolint: mnd
+	for _, l := range links {
+		res = append(res,
+			verifyEndpoint(l.A, l.B, neighbors, nodeErrs),
+			verifyEndpoint(l.B, l.A, neighbors, nodeErrs))
+	}
+
+	return res
+}
+
+func verifyEndpoint(ep, peer *WiringEndpoint, neighbors map[string][]*LLDPNeighbor,
+	nodeErrs map[string]error,
+) *WiringResult {
+	r := &WiringResult{Endpoint: ep, Peer: peer}
+
+	nbrs, ok := neighbors[ep.Node]
+	if !ok {
+		r.Status = WiringUnverified
+		r.Detail = "LLDP neighbors not read"
+		if err := nodeErrs[ep.Node]; err != nil {
+			r.Detail = err.Error()
+		}
+		return r
+	}
+
+	var nbr *LLDPNeighbor
+	for _, n := range nbrs {
+		if portMatches(n.Port, ep) {
+			nbr = n
+			break
+		}
+	}
+
+	switch {
+	case nbr == nil && !LLDPSupportsKind(peer.Kind):
+		r.Status = WiringUnverified
+		r.Detail = fmt.Sprintf("no LLDP neighbor, kind %s of the peer may not run LLDP", peer.Kind)
+	case nbr == nil:
+		r.Status = WiringDown
+		r.Detail = "no LLDP neighbor"
+	case strings.EqualFold(nbr.SystemName, peer.Node) && portMatches(nbr.RemotePort, peer):
+		r.Status = WiringOK
+	default:
+		r.Status = WiringMiscabled
+		r.Detail = fmt.Sprintf("neighbor is %s:%s", nbr.SystemName, nbr.RemotePort)
+	}
+
+	return r
+}
+
+// portMatches returns true if the port reported by LLDP is the interface of the endpoint,
+// by its name or alias, e.g. ethernet-1/1 for the e1-1 interface of SR Linux.
+func portMatches(port string, ep *WiringEndpoint) bool {
+	p := normalizePort(port)
+	return p == normalizePort(ep.Iface) || (ep.Alias != "" && p == normalizePort(ep.Alias))
+}
+
+// normalizePort returns the short interface name of the port, e.g. e1-1 for ethernet-1/1.
+func normalizePort(port string) string {
+	p := strings.ToLower(strings.TrimSpace(port))
+	if strings.HasPrefix(p, "ethernet-") {
+		p = "e" + strings.TrimPrefix(p, "ethernet-")
+	}
+
+	return strings.ReplaceAll(p, "/", "-")
+}
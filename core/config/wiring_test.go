@@ -0,0 +1,104 @@
+package config
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseLLDPNeighbors(t *testing.T) {
+	tests := map[string]struct {
+		kind, output string
+		want         []*LLDPNeighbor
+	}{
+		"srl": {
+			kind: "nokia_srlinux",
+			output: `
+  +--------------+-------------------+----------------------+---------------------+------------------------+----------------------+---------------+
+  |     Name     |     Neighbor      | Neighbor System Name | Neighbor Chassis ID | Neighbor First Message | Neighbor Last Update | Neighbor Port |
+  +==============+===================+======================+=====================+========================+======================+===============+
+  | ethernet-1/1 | 1A:B0:00:FF:00:00 | srl2                 | 1A:B0:00:FF:00:00   | 2 hours ago            | now                  | ethernet-1/1  |
+  | ethernet-1/2 | 1A:C4:01:FF:00:00 | srl3                 | 1A:C4:01:FF:00:00   | 2 hours ago            | now                  | ethernet-1/5  |
+  +--------------+-------------------+----------------------+---------------------+------------------------+----------------------+---------------+
+`,
+			want: []*LLDPNeighbor{
+				{Port: "ethernet-1/1", SystemName: "srl2", RemotePort: "ethernet-1/1"},
+				{Port: "ethernet-1/2", SystemName: "srl3", RemotePort: "ethernet-1/5"},
+			},
+		},
+		"sros": {
+			kind: "nokia_sros",
+			output: `
+===============================================================================
+NB = nearest-bridge   NTPMR = nearest-non-tpmr   NC = nearest-customer
+===============================================================================
+Lcl Port      Scope Remote Chassis ID  Index  Remote Port     Remote Sys Name
+-------------------------------------------------------------------------------
+1/1/c1/1      NB    0C:00:D5:D2:E6:00  1      1/1/c1/1, 100*  sr2
+1/1/c2/1      NB    0C:00:AB:12:00:00  2      ethernet-1/1    srl1
+===============================================================================
+`,
+			want: []*LLDPNeighbor{
+				{Port: "1/1/c1/1", SystemName: "sr2", RemotePort: "1/1/c1/1"},
+				{Port: "1/1/c2/1", SystemName: "srl1", RemotePort: "ethernet-1/1"},
+			},
+		},
+		"unsupported kind": {
+			kind:   "linux",
+			output: "anything",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ParseLLDPNeighbors(tt.kind, tt.output)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("ParseLLDPNeighbors() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestVerifyWiring(t *testing.T) {
+	srl1 := &WiringEndpoint{Node: "srl1", Kind: "nokia_srlinux", Iface: "e1-1", Alias: "ethernet-1/1"}
+	srl2 := &WiringEndpoint{Node: "srl2", Kind: "nokia_srlinux", Iface: "e1-1"}
+	srl1b := &WiringEndpoint{Node: "srl1", Kind: "nokia_srlinux", Iface: "e1-2"}
+	srl3 := &WiringEndpoint{Node: "srl3", Kind: "nokia_srlinux", Iface: "e1-1"}
+	srl1c := &WiringEndpoint{Node: "srl1", Kind: "nokia_srlinux", Iface: "e1-3"}
+	host := &WiringEndpoint{Node: "client", Kind: "linux", Iface: "eth1"}
+
+	links := []*WiringLink{
+		{A: srl1, B: srl2},
+		{A: srl1b, B: srl3},
+		{A: srl1c, B: host},
+	}
+	neighbors := map[string][]*LLDPNeighbor{
+		"srl1": {
+			{Port: "ethernet-1/1", SystemName: "srl2", RemotePort: "ethernet-1/1"},
+			{Port: "ethernet-1/2", SystemName: "srl3", RemotePort: "ethernet-1/2"},
+		},
+		"srl2": {
+			{Port: "ethernet-1/1", SystemName: "SRL1", RemotePort: "ethernet-1/1"},
+		},
+	}
+	nodeErrs := map[string]error{"srl3": errors.New("connection refused")}
+
+	want := []string{
+		"srl1:ethernet-1/1 ok ",
+		"srl2:e1-1 ok ",
+		"srl1:e1-2 miscabled neighbor is srl3:ethernet-1/2",
+		"srl3:e1-1 unverified connection refused",
+		"srl1:e1-3 unverified no LLDP neighbor, kind linux of the peer may not run LLDP",
+		"client:eth1 unverified LLDP neighbors not read",
+	}
+
+	var got []string
+	for _, r := range VerifyWiring(links, neighbors, nodeErrs) {
+		got = append(got, r.Endpoint.String()+" "+r.Status+" "+r.Detail)
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("VerifyWiring() mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,369 @@
+// Package console renders the containerlab log records prefixed with the name of the node
+// they relate to, so that the output of the nodes handled concurrently stays readable.
+package console
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// ModePrefixed prefixes every node log record with the colored node name.
+	ModePrefixed = "prefixed"
+	// ModeGrouped prefixes the node log records and holds them back
+	// until the node is done, printing the records of a node together.
+	ModeGrouped = "grouped"
+	// ModePlain leaves the log output as is.
+	ModePlain = "plain"
+
+	// NodeKey is the log key holding the name of the node a log record relates to.
+	NodeKey = "node"
+)
+
+// Modes lists the supported console modes.
+var Modes = []string{ModePrefixed, ModeGrouped, ModePlain}
+
+// palette is the set of ANSI colors the node names are colored with,
+// red is left out not to be confused with errors.
+var palette = []string{"2", "3", "4", "5", "6", "10", "11", "12", "13", "14"}
+
+// Color returns the color of the node name, the same node always gets the same color.
+func Color(node string) lipgloss.Color {
+	h := fnv.New32a()
+	h.Write([]byte(node))
+
+	return lipgloss.Color(palette[h.Sum32()%uint32(len(palette))])
+}
+
+// record is a decoded JSON log record.
+type record struct {
+	time   string
+	level  string
+	prefix string
+	msg    string
+	node   string
+	kvs    []any
+}
+
+// Writer receives the JSON formatted log records and writes them to the underlying
+// writer in the text format, prefixed with the name of the node they relate to.
+// A record relates to a node when it has the node key or when its message
+// starts with the name of a registered node followed by a colon.
+type Writer struct {
+	out  io.Writer
+	mode string
+	re   *lipgloss.Renderer
+
+	m       sync.Mutex
+	partial []byte
+	// nodes maps the registered node names and their aliases to the node names.
+	nodes map[string]string
+	width int
+	// groups holds the rendered lines of the nodes in grouped mode.
+	groups map[string][]string
+	order  []string
+}
+
+// NewWriter returns a Writer rendering the log records to out in the given mode.
+func NewWriter(out io.Writer, mode string) (*Writer, error) {
+	if mode != ModePrefixed && mode != ModeGrouped {
+		return nil, fmt.Errorf("unsupported console mode %q, use one of %s",
+			mode, strings.Join(Modes[:2], ", "))
+	}
+
+	return &Writer{
+		out:    out,
+		mode:   mode,
+		re:     lipgloss.NewRenderer(out),
+		nodes:  map[string]string{},
+		groups: map[string][]string{},
+	}, nil
+}
+
+// RegisterNode makes the records starting with the node name or one of its aliases
+// (e.g. the container name) relate to the node.
+func (w *Writer) RegisterNode(name string, aliases ...string) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.nodes[name] = name
+	for _, a := range aliases {
+		w.nodes[a] = name
+	}
+
+	w.width = max(w.width, len(name))
+}
+
+// Write implements io.Writer, complete lines are rendered as they arrive.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	w.partial = append(w.partial, p...)
+
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			break
+		}
+
+		line := string(w.partial[:i])
+		w.partial = w.partial[i+1:]
+
+		if err := w.handle(line); err != nil {
+			return len(p), err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes out the held back records of the node.
+func (w *Writer) Flush(node string) error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	return w.flush(w.resolve(node))
+}
+
+// FlushAll writes out the held back records of all nodes,
+// in the order the nodes first logged.
+func (w *Writer) FlushAll() error {
+	w.m.Lock()
+	defer w.m.Unlock()
+
+	for _, n := range w.order {
+		if err := w.flush(n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) flush(node string) error {
+	lines, ok := w.groups[node]
+	if !ok {
+		return nil
+	}
+
+	delete(w.groups, node)
+
+	for i, n := range w.order {
+		if n == node {
+			w.order = append(w.order[:i], w.order[i+1:]...)
+			break
+		}
+	}
+
+	_, err := io.WriteString(w.out, strings.Join(lines, ""))
+
+	return err
+}
+
+func (w *Writer) resolve(node string) string {
+	if n, ok := w.nodes[node]; ok {
+		return n
+	}
+
+	return node
+}
+
+func (w *Writer) handle(line string) error {
+	rec, err := decodeRecord(line)
+	if err != nil {
+		// not a log record, pass it through as is
+		_, err = io.WriteString(w.out, line+"\n")
+		return err
+	}
+
+	node := w.nodeOf(rec)
+	out := w.render(node, rec)
+
+	if node == "" || w.mode != ModeGrouped {
+		_, err = io.WriteString(w.out, out)
+		return err
+	}
+
+	if _, ok := w.groups[node]; !ok {
+		w.order = append(w.order, node)
+	}
+
+	w.groups[node] = append(w.groups[node], out)
+
+	return nil
+}
+
+// nodeOf returns the node the record relates to, stripping the node name
+// from the message when the message starts with it.
+func (w *Writer) nodeOf(rec *record) string {
+	if rec.node != "" {
+		return w.resolve(rec.node)
+	}
+
+	name, rest, ok := strings.Cut(rec.msg, ": ")
+	if !ok {
+		return ""
+	}
+
+	node, ok := w.nodes[name]
+	if !ok {
+		return ""
+	}
+
+	rec.msg = rest
+
+	return node
+}
+
+// render renders the record in the text format, each line prefixed with the node name.
+func (w *Writer) render(node string, rec *record) string {
+	var b bytes.Buffer
+
+	l := log.NewWithOptions(&b, log.Options{Level: log.DebugLevel, Prefix: rec.prefix})
+	l.SetColorProfile(w.re.ColorProfile())
+
+	if rec.level == "" {
+		l.Print(rec.msg, rec.kvs...)
+	} else {
+		lvl, err := log.ParseLevel(rec.level)
+		if err != nil {
+			lvl = log.InfoLevel
+		}
+
+		l.Log(lvl, rec.msg, rec.kvs...)
+	}
+
+	var label string
+	if node != "" {
+		label = w.re.NewStyle().Foreground(Color(node)).
+			Render(fmt.Sprintf("%-*s", w.width, node)) + " | "
+	}
+
+	if rec.time != "" {
+		label += rec.time + " "
+	}
+
+	var sb strings.Builder
+	for _, l := range strings.SplitAfter(strings.TrimSuffix(b.String(), "\n"), "\n") {
+		sb.WriteString(label + strings.TrimSuffix(l, "\n") + "\n")
+	}
+
+	return sb.String()
+}
+
+// decodeRecord decodes a JSON log record keeping the order of its key/value pairs.
+func decodeRecord(line string) (*record, error) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return nil, fmt.Errorf("not a log record")
+	}
+
+	rec := &record{}
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		key, _ := t.(string)
+
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+
+		switch key {
+		case log.TimestampKey:
+			rec.time = fmt.Sprint(v)
+		case log.LevelKey:
+			rec.level = fmt.Sprint(v)
+		case log.PrefixKey:
+			rec.prefix = fmt.Sprint(v)
+		case log.MessageKey:
+			rec.msg = fmt.Sprint(v)
+		case NodeKey:
+			rec.node = fmt.Sprint(v)
+		default:
+			rec.kvs = append(rec.kvs, key, v)
+		}
+	}
+
+	return rec, nil
+}
+
+var (
+	stdMu sync.Mutex
+	std   *Writer
+)
+
+// Setup sets the output of the default logger to out in the given mode.
+func Setup(out io.Writer, mode string) error {
+	if !slices.Contains(Modes, mode) {
+		return fmt.Errorf("unsupported log style %q, use one of %s", mode, strings.Join(Modes, ", "))
+	}
+
+	stdMu.Lock()
+	defer stdMu.Unlock()
+
+	if mode == ModePlain {
+		std = nil
+
+		log.SetFormatter(log.TextFormatter)
+		log.SetOutput(out)
+
+		return nil
+	}
+
+	w, err := NewWriter(out, mode)
+	if err != nil {
+		return err
+	}
+
+	std = w
+
+	log.SetFormatter(log.JSONFormatter)
+	log.SetOutput(w)
+
+	return nil
+}
+
+// RegisterNode registers the node and its aliases with the default logger output.
+func RegisterNode(name string, aliases ...string) {
+	if w := current(); w != nil {
+		w.RegisterNode(name, aliases...)
+	}
+}
+
+// Flush writes out the held back records of the node in grouped mode.
+func Flush(node string) {
+	if w := current(); w != nil {
+		_ = w.Flush(node)
+	}
+}
+
+// FlushAll writes out the held back records of all nodes in grouped mode.
+func FlushAll() {
+	if w := current(); w != nil {
+		_ = w.FlushAll()
+	}
+}
+
+func current() *Writer {
+	stdMu.Lock()
+	defer stdMu.Unlock()
+
+	return std
+}
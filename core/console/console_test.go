@@ -0,0 +1,114 @@
+package console
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/charmbracelet/log"
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestLogger(t *testing.T, mode string) (*log.Logger, *Writer, *bytes.Buffer) {
+	t.Helper()
+
+	var out bytes.Buffer
+
+	w, err := NewWriter(&out, mode)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w.RegisterNode("srl1", "clab-lab-srl1")
+	w.RegisterNode("ceos", "clab-lab-ceos")
+
+	l := log.NewWithOptions(w, log.Options{Formatter: log.JSONFormatter})
+
+	return l, w, &out
+}
+
+func TestWriterPrefixed(t *testing.T) {
+	tests := map[string]struct {
+		log  func(l *log.Logger)
+		want string
+	}{
+		"node key": {
+			log:  func(l *log.Logger) { l.Info("Creating container", "node", "srl1", "kind", "srl") },
+			want: "srl1 | INFO Creating container kind=srl\n",
+		},
+		"node alias": {
+			log:  func(l *log.Logger) { l.Warn("Removing", "node", "clab-lab-ceos") },
+			want: "ceos | WARN Removing\n",
+		},
+		"message prefix": {
+			log:  func(l *log.Logger) { l.Errorf("%s: commit failed", "srl1") },
+			want: "srl1 | ERRO commit failed\n",
+		},
+		"unregistered message prefix": {
+			log:  func(l *log.Logger) { l.Info("Note: nothing to do") },
+			want: "INFO Note: nothing to do\n",
+		},
+		"no node": {
+			log:  func(l *log.Logger) { l.Info("Creating lab directory", "path", "/tmp/lab") },
+			want: "INFO Creating lab directory path=/tmp/lab\n",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			l, _, out := newTestLogger(t, ModePrefixed)
+			tc.log(l)
+
+			if d := cmp.Diff(tc.want, out.String()); d != "" {
+				t.Errorf("output mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestWriterGrouped(t *testing.T) {
+	l, w, out := newTestLogger(t, ModeGrouped)
+
+	l.Info("srl1: rendering")
+	l.Info("ceos: rendering")
+	l.Info("Lab deployed")
+	l.Info("srl1: committed")
+
+	if d := cmp.Diff("INFO Lab deployed\n", out.String()); d != "" {
+		t.Errorf("output before flush mismatch (-want +got):\n%s", d)
+	}
+
+	if err := w.Flush("clab-lab-srl1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.FlushAll(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "INFO Lab deployed\n" +
+		"srl1 | INFO rendering\n" +
+		"srl1 | INFO committed\n" +
+		"ceos | INFO rendering\n"
+	if d := cmp.Diff(want, out.String()); d != "" {
+		t.Errorf("output mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestColor(t *testing.T) {
+	if Color("srl1") != Color("srl1") {
+		t.Error("the same node got different colors")
+	}
+
+	for _, n := range []string{"srl1", "srl2", "ceos", "client1"} {
+		c := string(Color(n))
+		if c == "1" || c == "9" {
+			t.Errorf("node %s colored red", n)
+		}
+	}
+}
+
+func TestNewWriterMode(t *testing.T) {
+	if _, err := NewWriter(&bytes.Buffer{}, ModePlain); err == nil {
+		t.Error("expected an error for the plain mode")
+	}
+}
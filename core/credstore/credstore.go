@@ -0,0 +1,337 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package credstore manages the named credentials of the user, stored encrypted in a file.
+// The config runs log into the nodes with the credentials of the profile matching the node
+// by kind, lab and node name instead of the kind credentials, so the topologies don't carry
+// the passwords of the nodes.
+package credstore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	clabutils "github.com/srl-labs/containerlab/utils"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	// DefaultPath is the path of the credentials store of the user.
+	DefaultPath = "~/.clab/credentials.enc"
+
+	// PathEnv is the environment variable with the path of the credentials store.
+	PathEnv = "CLAB_CREDS_FILE"
+	// KeyEnv is the environment variable with the passphrase of the credentials store.
+	// Without the passphrase the store is encrypted with the key of the key file next to it,
+	// created with the store.
+	KeyEnv = "CLAB_CREDS_KEY"
+
+	keyFileSuffix = ".key"
+	storeVersion  = 1
+)
+
+// Profile is a named username and password used by the nodes it matches.
+// The empty match fields match any node.
+type Profile struct {
+	// Kind is the kind of the nodes, as set in the topology
+	Kind string `json:"kind,omitempty"`
+	// Lab is the name of the lab of the nodes
+	Lab string `json:"lab,omitempty"`
+	// Node is a shell pattern of the node names, e.g. leaf*
+	Node string `json:"node,omitempty"`
+
+	Username string `json:"username"`
+	Password string `json:"password,omitempty"`
+	// PasswordEnv is the environment variable with the password, read when the profile is used
+	PasswordEnv string `json:"password-env,omitempty"`
+}
+
+// Matches returns true when the profile matches the node of the kind in the lab.
+func (p *Profile) Matches(lab, node, kind string) bool {
+	if p.Kind != "" && p.Kind != kind {
+		return false
+	}
+	if p.Lab != "" && p.Lab != lab {
+		return false
+	}
+	if p.Node != "" {
+		if ok, _ := path.Match(p.Node, node); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// specificity ranks the matching profiles, a node pattern is more specific than a lab,
+// and a lab than a kind.
+func (p *Profile) specificity() int {
+	s := 0
+	if p.Node != "" {
+		s += 4
+	}
+	if p.Lab != "" {
+		s += 2
+	}
+	if p.Kind != "" {
+		s++
+	}
+
+	return s
+}
+
+// Credentials returns the username and password of the profile.
+func (p *Profile) Credentials() (string, string, error) {
+	if p.PasswordEnv == "" {
+		return p.Username, p.Password, nil
+	}
+
+	pw, ok := os.LookupEnv(p.PasswordEnv)
+	if !ok {
+		return "", "", fmt.Errorf("the environment variable %s with the password is not set", p.PasswordEnv)
+	}
+
+	return p.Username, pw, nil
+}
+
+// Validate checks the profile.
+func (p *Profile) Validate() error {
+	if p.Username == "" {
+		return errors.New("the username is required")
+	}
+	if (p.Password == "") == (p.PasswordEnv == "") {
+		return errors.New("either the password or the environment variable with the password is required")
+	}
+	if _, err := path.Match(p.Node, ""); err != nil {
+		return fmt.Errorf("invalid node pattern %q: %w", p.Node, err)
+	}
+
+	return nil
+}
+
+// Store is the set of the credential profiles of the user, stored encrypted in a file.
+type Store struct {
+	Profiles map[string]*Profile `json:"profiles"`
+
+	path string
+}
+
+// envelope is the encrypted content of the store file.
+type envelope struct {
+	Version int    `json:"version"`
+	Salt    []byte `json:"salt"`
+	Nonce   []byte `json:"nonce"`
+	Data    []byte `json:"data"`
+}
+
+// Path returns the path of the credentials store, set with the CLAB_CREDS_FILE
+// environment variable or the default one.
+func Path() string {
+	if p := os.Getenv(PathEnv); p != "" {
+		return p
+	}
+
+	return DefaultPath
+}
+
+// Load reads and decrypts the credentials store of the file,
+// an empty store is returned when the file doesn't exist.
+func Load(p string) (*Store, error) {
+	s := &Store{
+		Profiles: map[string]*Profile{},
+		path:     clabutils.ExpandHome(p),
+	}
+
+	b, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	env := &envelope{}
+	if err := json.Unmarshal(b, env); err != nil {
+		return nil, fmt.Errorf("invalid credentials store %s: %w", s.path, err)
+	}
+	if env.Version != storeVersion {
+		return nil, fmt.Errorf("unsupported version %d of the credentials store %s", env.Version, s.path)
+	}
+
+	secret, err := s.secret(false)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newCipher(secret, env.Salt)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := gcm.Open(nil, env.Nonce, env.Data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the credentials store %s, check the %s passphrase", s.path, KeyEnv)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("invalid credentials store %s: %w", s.path, err)
+	}
+	if s.Profiles == nil {
+		s.Profiles = map[string]*Profile{}
+	}
+
+	return s, nil
+}
+
+// Save encrypts the store and writes it to its file, only readable by its owner.
+func (s *Store) Save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	secret, err := s.secret(true)
+	if err != nil {
+		return err
+	}
+
+	env := &envelope{
+		Version: storeVersion,
+		Salt:    make([]byte, 16),
+	}
+	if _, err := rand.Read(env.Salt); err != nil {
+		return err
+	}
+
+	gcm, err := newCipher(secret, env.Salt)
+	if err != nil {
+		return err
+	}
+
+	env.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(env.Nonce); err != nil {
+		return err
+	}
+	env.Data = gcm.Seal(nil, env.Nonce, data, nil)
+
+	b, err := json.MarshalIndent(env, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(s.path, b, 0o600)
+}
+
+// secret returns the passphrase of the CLAB_CREDS_KEY environment variable or the content
+// of the key file of the store, the key file is created when create is set.
+func (s *Store) secret(create bool) ([]byte, error) {
+	if k := os.Getenv(KeyEnv); k != "" {
+		return []byte(k), nil
+	}
+
+	keyFile := s.path + keyFileSuffix
+
+	b, err := os.ReadFile(keyFile)
+	switch {
+	case err == nil:
+		return []byte(strings.TrimSpace(string(b))), nil
+	case !errors.Is(err, fs.ErrNotExist):
+		return nil, err
+	case !create:
+		return nil, fmt.Errorf("the key file %s of the credentials store is missing, set the %s passphrase",
+			keyFile, KeyEnv)
+	}
+
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+	secret := hex.EncodeToString(key)
+
+	if err := os.WriteFile(keyFile, []byte(secret+"\n"), 0o600); err != nil {
+		return nil, err
+	}
+
+	return []byte(secret), nil
+}
+
+// newCipher returns the AES-GCM cipher of the key derived from the secret and the salt.
+func newCipher(secret, salt []byte) (cipher.AEAD, error) {
+	key, err := scrypt.Key(secret, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// Set adds the profile or replaces the one with the same name.
+func (s *Store) Set(name string, p *Profile) {
+	s.Profiles[name] = p
+}
+
+// Remove removes the named profile, false when the store has no such profile.
+func (s *Store) Remove(name string) bool {
+	if _, ok := s.Profiles[name]; !ok {
+		return false
+	}
+
+	delete(s.Profiles, name)
+
+	return true
+}
+
+// Names returns the sorted names of the profiles.
+func (s *Store) Names() []string {
+	res := make([]string, 0, len(s.Profiles))
+	for n := range s.Profiles {
+		res = append(res, n)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
+
+// Lookup returns the name and the profile matching the node of the kind in the lab,
+// the most specific profile wins and the profiles as specific are taken by name.
+// The profile is nil when none matches.
+func (s *Store) Lookup(lab, node, kind string) (string, *Profile) {
+	var (
+		name string
+		res  *Profile
+	)
+
+	for _, n := range s.Names() {
+		p := s.Profiles[n]
+		if !p.Matches(lab, node, kind) {
+			continue
+		}
+		if res == nil || p.specificity() > res.specificity() {
+			name, res = n, p
+		}
+	}
+
+	return name, res
+}
@@ -0,0 +1,167 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package credstore
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestStore(t *testing.T) {
+	t.Setenv(KeyEnv, "")
+	path := filepath.Join(t.TempDir(), "clab", "credentials.enc")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file failed: %v", err)
+	}
+
+	s.Set("srl", &Profile{Kind: "nokia_srlinux", Username: "admin", Password: "s3cret"})
+	s.Set("ci", &Profile{Lab: "dc", Username: "ci", PasswordEnv: "CI_PASSWORD"})
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(b), "s3cret") {
+		t.Errorf("the store file contains the plaintext password:\n%s", b)
+	}
+	if _, err := os.Stat(path + keyFileSuffix); err != nil {
+		t.Errorf("the key file is not created: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(s.Profiles, got.Profiles); d != "" {
+		t.Errorf("profiles mismatch (-want +got):\n%s", d)
+	}
+
+	if !got.Remove("ci") || got.Remove("ci") {
+		t.Errorf("Remove() of the ci profile failed")
+	}
+	if d := cmp.Diff([]string{"srl"}, got.Names()); d != "" {
+		t.Errorf("names mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestStorePassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+
+	t.Setenv(KeyEnv, "passphrase")
+	s, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Set("all", &Profile{Username: "admin", Password: "admin"})
+	if err := s.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path + keyFileSuffix); err == nil {
+		t.Errorf("the key file is created with the passphrase set")
+	}
+
+	t.Setenv(KeyEnv, "wrong")
+	if _, err := Load(path); err == nil {
+		t.Errorf("Load() with a wrong passphrase did not fail")
+	}
+
+	t.Setenv(KeyEnv, "")
+	if _, err := Load(path); err == nil {
+		t.Errorf("Load() without the passphrase and the key file did not fail")
+	}
+}
+
+func TestLookup(t *testing.T) {
+	s := &Store{Profiles: map[string]*Profile{
+		"default": {Username: "admin"},
+		"srl":     {Kind: "nokia_srlinux", Username: "srl"},
+		"dc":      {Lab: "dc", Username: "dc"},
+		"dc-srl":  {Lab: "dc", Kind: "nokia_srlinux", Username: "dc-srl"},
+		"leaves":  {Node: "leaf*", Username: "leaf"},
+		"ceos":    {Kind: "arista_ceos", Username: "ceos"},
+		"ceos2":   {Kind: "arista_ceos", Username: "ceos2"},
+	}}
+
+	tests := map[string]struct {
+		lab, node, kind string
+		want            string
+	}{
+		"default":        {lab: "wan", node: "r1", kind: "linux", want: "default"},
+		"kind":           {lab: "wan", node: "r1", kind: "nokia_srlinux", want: "srl"},
+		"lab over kind":  {lab: "dc", node: "r1", kind: "linux", want: "dc"},
+		"lab and kind":   {lab: "dc", node: "spine1", kind: "nokia_srlinux", want: "dc-srl"},
+		"node over lab":  {lab: "dc", node: "leaf1", kind: "nokia_srlinux", want: "leaves"},
+		"tie by name":    {lab: "wan", node: "r1", kind: "arista_ceos", want: "ceos"},
+		"node pattern":   {lab: "wan", node: "leaf12", kind: "linux", want: "leaves"},
+		"pattern prefix": {lab: "wan", node: "myleaf1", kind: "linux", want: "default"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, p := s.Lookup(tt.lab, tt.node, tt.kind)
+			if got != tt.want || p != s.Profiles[tt.want] {
+				t.Errorf("want profile %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	empty := &Store{Profiles: map[string]*Profile{}}
+	if _, p := empty.Lookup("dc", "r1", "linux"); p != nil {
+		t.Errorf("Lookup() in an empty store returned %v", p)
+	}
+}
+
+func TestProfileCredentials(t *testing.T) {
+	t.Setenv("CLAB_TEST_PASSWORD", "from-env")
+
+	tests := map[string]struct {
+		profile  *Profile
+		want     []string
+		wantErr  bool
+		validErr bool
+	}{
+		"password":  {profile: &Profile{Username: "admin", Password: "admin"}, want: []string{"admin", "admin"}},
+		"env":       {profile: &Profile{Username: "ci", PasswordEnv: "CLAB_TEST_PASSWORD"}, want: []string{"ci", "from-env"}},
+		"env unset": {profile: &Profile{Username: "ci", PasswordEnv: "CLAB_TEST_UNSET"}, wantErr: true},
+		"both": {
+			profile:  &Profile{Username: "ci", Password: "x", PasswordEnv: "CLAB_TEST_PASSWORD"},
+			want:     []string{"ci", "from-env"},
+			validErr: true,
+		},
+		"no username": {profile: &Profile{Password: "x"}, want: []string{"", "x"}, validErr: true},
+		"bad pattern": {
+			profile:  &Profile{Node: "leaf[", Username: "admin", Password: "x"},
+			want:     []string{"admin", "x"},
+			validErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if err := tt.profile.Validate(); (err != nil) != tt.validErr {
+				t.Errorf("want validation error %v, got %v", tt.validErr, err)
+			}
+
+			u, pw, err := tt.profile.Credentials()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("want error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil {
+				if d := cmp.Diff(tt.want, []string{u, pw}); d != "" {
+					t.Errorf("credentials mismatch (-want +got):\n%s", d)
+				}
+			}
+		})
+	}
+}
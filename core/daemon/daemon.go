@@ -0,0 +1,343 @@
+// Package daemon reconciles the labs of a directory of topology files,
+// deploying, updating and destroying the labs as the files appear, change and disappear.
+package daemon
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/fsnotify/fsnotify"
+)
+
+// debounceInterval is the time the file events settle for before the labs are reconciled,
+// as editors write a file in several steps.
+const debounceInterval = 2 * time.Second
+
+// reapInterval is the interval between the checks of the expiry of the labs.
+const reapInterval = 30 * time.Second
+
+// topoPatterns match the topology files in the watched directory.
+var topoPatterns = []string{"*.clab.yml", "*.clab.yaml"} //nolint:gochecknoglobals
+
+// DeployFunc deploys the lab of the topology file, or updates the deployed lab,
+// and returns the name of the lab.
+type DeployFunc func(ctx context.Context, topo string) (string, error)
+
+// DestroyFunc destroys the lab by its name.
+type DestroyFunc func(ctx context.Context, lab string) error
+
+// ExpiryFunc returns the expiry times of the deployed labs with a TTL by lab name.
+type ExpiryFunc func(ctx context.Context) (map[string]time.Time, error)
+
+// NotifyFunc notifies the users of the lab expiring at the time, the lab being destroyed
+// when the time is past.
+type NotifyFunc func(ctx context.Context, lab string, expires time.Time)
+
+// Lab is a lab managed by the daemon.
+type Lab struct {
+	Name string
+	// Hash is the hash of the topology file the lab was deployed from.
+	Hash string
+	// Err is the error of the last deployment of the lab.
+	Err error
+}
+
+// Reconciler deploys the labs of the topology files of a directory.
+type Reconciler struct {
+	dir     string
+	deploy  DeployFunc
+	destroy DestroyFunc
+	// labs are the labs managed by the reconciler by their topology file path.
+	labs map[string]*Lab
+
+	expiries   ExpiryFunc
+	notify     NotifyFunc
+	warnBefore time.Duration
+	// warned are the expiry times of the labs their users were warned about by lab name.
+	warned map[string]time.Time
+	// expired are the expiry times of the expired labs their users were notified about by lab name,
+	// the users of a lab failing to be destroyed are notified once per expiry time.
+	expired map[string]time.Time
+	now     func() time.Time
+}
+
+// NewReconciler returns a Reconciler of the directory.
+func NewReconciler(dir string, deploy DeployFunc, destroy DestroyFunc) *Reconciler {
+	return &Reconciler{
+		dir:     dir,
+		deploy:  deploy,
+		destroy: destroy,
+		labs:    map[string]*Lab{},
+		warned:  map[string]time.Time{},
+		expired: map[string]time.Time{},
+		now:     time.Now,
+	}
+}
+
+// WithExpiry makes the reconciler destroy the deployed labs once their TTL expires,
+// all the labs of the host and not only the labs of the directory. The users are notified
+// of the labs expiring within the warning time before and once the labs expire.
+func (r *Reconciler) WithExpiry(expiries ExpiryFunc, notify NotifyFunc, warnBefore time.Duration) *Reconciler {
+	r.expiries = expiries
+	r.notify = notify
+	r.warnBefore = warnBefore
+
+	return r
+}
+
+// Labs returns the labs managed by the reconciler by their topology file path.
+func (r *Reconciler) Labs() map[string]*Lab {
+	return r.labs
+}
+
+// Reconcile deploys the labs of the new topology files, updates the labs of the changed files
+// and destroys the labs of the removed files. The labs are reconciled one at a time,
+// the labs failing to deploy being deployed again when their file changes.
+func (r *Reconciler) Reconcile(ctx context.Context) error {
+	files, err := r.topologyFiles()
+	if err != nil {
+		return err
+	}
+
+	paths := make([]string, 0, len(r.labs))
+	for p := range r.labs {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	for _, p := range paths {
+		if _, ok := files[p]; ok {
+			continue
+		}
+
+		lab := r.labs[p]
+		if lab.Name != "" {
+			log.Info("Topology file removed, destroying the lab", "file", p, "lab", lab.Name)
+			if err := r.destroy(ctx, lab.Name); err != nil {
+				log.Error("Failed to destroy the lab", "lab", lab.Name, "err", err)
+				continue
+			}
+		}
+
+		delete(r.labs, p)
+	}
+
+	for _, p := range sortedKeys(files) {
+		lab, ok := r.labs[p]
+		if ok && lab.Hash == files[p] {
+			continue
+		}
+
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		if ok {
+			log.Info("Topology file changed, updating the lab", "file", p, "lab", lab.Name)
+		} else {
+			log.Info("Topology file found, deploying the lab", "file", p)
+		}
+
+		name, err := r.deploy(ctx, p)
+
+		// the lab name may change with the file, the lab of the previous name is destroyed
+		if ok && lab.Name != "" && name != "" && lab.Name != name {
+			log.Info("Lab renamed, destroying the lab of the previous name", "file", p, "lab", lab.Name)
+			if err := r.destroy(ctx, lab.Name); err != nil {
+				log.Error("Failed to destroy the lab", "lab", lab.Name, "err", err)
+			}
+		}
+
+		if name == "" && ok {
+			name = lab.Name
+		}
+
+		r.labs[p] = &Lab{Name: name, Hash: files[p], Err: err}
+
+		if err != nil {
+			log.Error("Failed to deploy the lab, waiting for the topology file to change", "file", p, "err", err)
+		}
+	}
+
+	return nil
+}
+
+// Reap destroys the labs with an expired TTL and warns about the labs expiring soon.
+// The users are notified once per expiry time, also of the expiry of a lab whose destroy keeps
+// failing and is retried on every reap. The destroyed lab of a topology file of the directory
+// is deployed again when its file changes.
+func (r *Reconciler) Reap(ctx context.Context) error {
+	if r.expiries == nil {
+		return nil
+	}
+
+	expiries, err := r.expiries(ctx)
+	if err != nil {
+		return err
+	}
+
+	now := r.now()
+
+	// the users of the labs destroyed otherwise are notified again when the labs are deployed with a TTL
+	for _, m := range []map[string]time.Time{r.warned, r.expired} {
+		for lab := range m {
+			if _, ok := expiries[lab]; !ok {
+				delete(m, lab)
+			}
+		}
+	}
+
+	for _, lab := range sortedKeys(expiries) {
+		exp := expiries[lab]
+
+		if now.Before(exp) {
+			if exp.Sub(now) <= r.warnBefore && !r.warned[lab].Equal(exp) {
+				r.warned[lab] = exp
+				r.notify(ctx, lab, exp)
+			}
+			continue
+		}
+
+		log.Warn("Lab TTL expired, destroying the lab", "lab", lab, "expired", exp)
+		if !r.expired[lab].Equal(exp) {
+			r.expired[lab] = exp
+			r.notify(ctx, lab, exp)
+		}
+
+		if err := r.destroy(ctx, lab); err != nil {
+			log.Error("Failed to destroy the expired lab", "lab", lab, "err", err)
+			continue
+		}
+
+		delete(r.warned, lab)
+		delete(r.expired, lab)
+
+		for _, l := range r.labs {
+			if l.Name == lab {
+				l.Name = ""
+			}
+		}
+	}
+
+	return nil
+}
+
+// Run reconciles the labs on start, on every change of the topology files of the directory
+// and every interval, until the context is canceled.
+func (r *Reconciler) Run(ctx context.Context, interval time.Duration) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := w.Add(r.dir); err != nil {
+		return err
+	}
+
+	if err := r.Reconcile(ctx); err != nil {
+		return err
+	}
+
+	var tick <-chan time.Time
+	if interval > 0 {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	var reap <-chan time.Time
+	if r.expiries != nil {
+		if err := r.Reap(ctx); err != nil {
+			log.Warn("Failed to check the expiry of the labs", "err", err)
+		}
+
+		ticker := time.NewTicker(reapInterval)
+		defer ticker.Stop()
+		reap = ticker.C
+	}
+
+	debounce := time.NewTimer(debounceInterval)
+	debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			if isTopologyFile(ev.Name) {
+				log.Debug("Topology file event", "file", ev.Name, "op", ev.Op)
+				debounce.Reset(debounceInterval)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			log.Warn("Directory watch error", "dir", r.dir, "err", err)
+		case <-debounce.C:
+			if err := r.Reconcile(ctx); err != nil {
+				return err
+			}
+		case <-tick:
+			if err := r.Reconcile(ctx); err != nil {
+				return err
+			}
+		case <-reap:
+			if err := r.Reap(ctx); err != nil {
+				log.Warn("Failed to check the expiry of the labs", "err", err)
+			}
+		}
+	}
+}
+
+// topologyFiles returns the hashes of the topology files of the directory by their paths.
+func (r *Reconciler) topologyFiles() (map[string]string, error) {
+	res := map[string]string{}
+
+	for _, pattern := range topoPatterns {
+		files, err := filepath.Glob(filepath.Join(r.dir, pattern))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				// the file may be removed while the directory is read
+				log.Debug("Failed to read the topology file", "file", f, "err", err)
+				continue
+			}
+
+			h := sha256.Sum256(b)
+			res[f] = hex.EncodeToString(h[:])
+		}
+	}
+
+	return res, nil
+}
+
+func isTopologyFile(path string) bool {
+	name := filepath.Base(path)
+
+	return strings.HasSuffix(name, ".clab.yml") || strings.HasSuffix(name, ".clab.yaml")
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,209 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// recorder records the deployments and destructions of the reconciler,
+// the lab name being the first line of the topology file.
+type recorder struct {
+	calls []string
+	fail  map[string]bool
+	// failDestroy are the labs failing to be destroyed
+	failDestroy map[string]bool
+}
+
+func (r *recorder) deploy(_ context.Context, topo string) (string, error) {
+	b, err := os.ReadFile(topo)
+	if err != nil {
+		return "", err
+	}
+
+	name, _, _ := strings.Cut(string(b), "\n")
+	r.calls = append(r.calls, "deploy "+name)
+
+	if r.fail[name] {
+		return name, errors.New("deploy failed")
+	}
+
+	return name, nil
+}
+
+func (r *recorder) destroy(_ context.Context, lab string) error {
+	r.calls = append(r.calls, "destroy "+lab)
+
+	if r.failDestroy[lab] {
+		return errors.New("destroy failed")
+	}
+
+	return nil
+}
+
+func TestReconcile(t *testing.T) {
+	dir := t.TempDir()
+	rec := &recorder{fail: map[string]bool{"broken": true}}
+	r := NewReconciler(dir, rec.deploy, rec.destroy)
+
+	write := func(name, content string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	steps := []struct {
+		name   string
+		change func()
+		want   []string
+	}{
+		{
+			name: "new files",
+			change: func() {
+				write("a.clab.yml", "lab-a\n")
+				write("b.clab.yaml", "lab-b\n")
+				write("c.clab.yml", "broken\n")
+				write("notes.yml", "lab-x\n")
+			},
+			want: []string{"deploy lab-a", "deploy lab-b", "deploy broken"},
+		},
+		{
+			name:   "no change",
+			change: func() {},
+		},
+		{
+			name:   "changed file",
+			change: func() { write("a.clab.yml", "lab-a\nnodes\n") },
+			want:   []string{"deploy lab-a"},
+		},
+		{
+			name:   "renamed lab",
+			change: func() { write("b.clab.yaml", "lab-b2\n") },
+			want:   []string{"deploy lab-b2", "destroy lab-b"},
+		},
+		{
+			name:   "fixed file",
+			change: func() { write("c.clab.yml", "lab-c\n") },
+			want:   []string{"deploy lab-c", "destroy broken"},
+		},
+		{
+			name: "removed files",
+			change: func() {
+				os.Remove(filepath.Join(dir, "a.clab.yml"))
+				os.Remove(filepath.Join(dir, "c.clab.yml"))
+			},
+			want: []string{"destroy lab-a", "destroy lab-c"},
+		},
+	}
+
+	for _, s := range steps {
+		rec.calls = nil
+		s.change()
+
+		if err := r.Reconcile(context.Background()); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+
+		if d := cmp.Diff(s.want, rec.calls); d != "" {
+			t.Errorf("%s: calls mismatch (-want +got):\n%s", s.name, d)
+		}
+	}
+
+	labs := map[string]string{}
+	for p, l := range r.Labs() {
+		labs[filepath.Base(p)] = l.Name
+	}
+
+	if d := cmp.Diff(map[string]string{"b.clab.yaml": "lab-b2"}, labs); d != "" {
+		t.Errorf("labs mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestReap(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "dc.clab.yml"), []byte("dc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	expiries := map[string]time.Time{
+		"dc":  start.Add(time.Hour),
+		"ci1": start.Add(5 * time.Minute),
+	}
+
+	rec := &recorder{}
+	notify := func(_ context.Context, lab string, exp time.Time) {
+		rec.calls = append(rec.calls, "notify "+lab+" "+exp.Format(time.Kitchen))
+	}
+	r := NewReconciler(dir, rec.deploy, rec.destroy).WithExpiry(
+		func(context.Context) (map[string]time.Time, error) { return expiries, nil },
+		notify, 10*time.Minute)
+
+	if err := r.Reconcile(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := []struct {
+		name string
+		now  time.Time
+		// expires sets the expiry time of the dc lab
+		expires     time.Time
+		failDestroy bool
+		want        []string
+	}{
+		{name: "warning", now: start, want: []string{"notify ci1 12:05PM"}},
+		{name: "warned once", now: start.Add(time.Minute)},
+		{name: "expired", now: start.Add(5 * time.Minute), want: []string{"notify ci1 12:05PM", "destroy ci1"}},
+		{name: "dc destroy failed", now: start.Add(time.Hour), failDestroy: true,
+			want: []string{"notify dc 1:00PM", "destroy dc"}},
+		{name: "dc destroy retried", now: start.Add(time.Hour + 30*time.Second), failDestroy: true,
+			want: []string{"destroy dc"}},
+		{name: "dc ttl extended", now: start.Add(time.Hour + time.Minute), expires: start.Add(2 * time.Hour)},
+		{name: "dc warning", now: start.Add(115 * time.Minute), want: []string{"notify dc 2:00PM"}},
+		{name: "dc expired", now: start.Add(3 * time.Hour), want: []string{"notify dc 2:00PM", "destroy dc"}},
+	}
+
+	for _, s := range steps {
+		rec.calls = nil
+		rec.failDestroy = map[string]bool{"dc": s.failDestroy}
+		r.now = func() time.Time { return s.now }
+		if !s.expires.IsZero() {
+			expiries["dc"] = s.expires
+		}
+
+		if err := r.Reap(t.Context()); err != nil {
+			t.Fatalf("%s: %v", s.name, err)
+		}
+		if d := cmp.Diff(s.want, rec.calls); d != "" {
+			t.Errorf("%s: calls mismatch (-want +got):\n%s", s.name, d)
+		}
+
+		// the destroyed labs are gone from the host
+		for _, c := range rec.calls {
+			if lab, ok := strings.CutPrefix(c, "destroy "); ok && !rec.failDestroy[lab] {
+				delete(expiries, lab)
+			}
+		}
+	}
+
+	// the expired lab of an unchanged file is not deployed again and not destroyed with its file
+	rec.calls = nil
+	if err := r.Reconcile(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, "dc.clab.yml")); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Reconcile(t.Context()); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.calls) != 0 {
+		t.Errorf("unexpected calls %v", rec.calls)
+	}
+}
@@ -2,32 +2,96 @@ package core
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"path/filepath"
 	"sync"
 	"time"
 
 	"github.com/charmbracelet/log"
 	clabcert "github.com/srl-labs/containerlab/cert"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
 	clabexec "github.com/srl-labs/containerlab/exec"
 	clablinks "github.com/srl-labs/containerlab/links"
 	clabruntime "github.com/srl-labs/containerlab/runtime"
 	clabutils "github.com/srl-labs/containerlab/utils"
 )
 
+// renderedStartupConfigFileName is the file in the node's lab dir
+// holding the startup-config rendered from the config templates.
+const renderedStartupConfigFileName = "rendered-startup-config.cfg"
+
 // Deploy the given topology.
+// The deploy start and result are reported as lab lifecycle events.
+func (c *CLab) Deploy(
+	ctx context.Context,
+	options *DeployOptions,
+) ([]clabruntime.GenericContainer, error) {
+	c.events.Emit(ctx, &clabcoreevents.Event{
+		Type: clabcoreevents.DeployStarted,
+		Lab:  c.Config.Name,
+	})
+
+	containers, err := c.deploy(ctx, options)
+	if err != nil {
+		c.events.Emit(ctx, &clabcoreevents.Event{
+			Type:    clabcoreevents.DeployFailed,
+			Lab:     c.Config.Name,
+			Status:  clabcoreevents.StatusFailed,
+			Message: err.Error(),
+		})
+
+		return nil, err
+	}
+
+	c.events.Emit(ctx, &clabcoreevents.Event{
+		Type:    clabcoreevents.DeployFinished,
+		Lab:     c.Config.Name,
+		Status:  clabcoreevents.StatusOK,
+		Message: fmt.Sprintf("%d containers deployed", len(containers)),
+	})
+
+	return containers, nil
+}
+
 // skipcq: GO-R1005
-func (c *CLab) Deploy( //nolint: funlen
+func (c *CLab) deploy( //nolint: funlen
 	ctx context.Context,
 	options *DeployOptions,
 ) ([]clabruntime.GenericContainer, error) {
 	var err error
 
-	err = c.ResolveLinks()
+	unlock, err := lockDeploy(c.Config.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	// the links are resolved already when the startup-configs were rendered from the topology
+	if len(c.Links) == 0 {
+		err = c.ResolveLinks()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the state is taken before the deployment sets the runtime info and startup-configs of the nodes
+	state, err := c.labState(options.startupConfigs)
 	if err != nil {
 		return nil, err
 	}
 
 	log.Debugf("lab Conf: %+v", c.Config)
+	if options.diff {
+		c.deployDiff, err = c.prepareIncrementalDeploy(ctx, state)
+		if err != nil {
+			return nil, err
+		}
+		if c.deployDiff == nil {
+			log.Warn("The lab has no recorded state, deploying it in full")
+		}
+	}
+
 	if options.reconfigure {
 		_ = c.destroy(ctx, uint(len(c.Nodes)), true)
 		log.Info("Removing directory", "path", c.TopoPaths.TopologyLabDir())
@@ -36,6 +100,10 @@ func (c *CLab) Deploy( //nolint: funlen
 		}
 	}
 
+	if err := c.writeStartupConfigs(options.startupConfigs); err != nil {
+		return nil, err
+	}
+
 	// create management network or use existing one
 	if err := c.CreateNetwork(ctx); err != nil {
 		return nil, err
@@ -50,6 +118,10 @@ func (c *CLab) Deploy( //nolint: funlen
 		return nil, err
 	}
 
+	if _, err := c.hostRoutes(); err != nil {
+		return nil, err
+	}
+
 	if err := c.loadKernelModules(); err != nil {
 		return nil, err
 	}
@@ -143,6 +215,10 @@ func (c *CLab) Deploy( //nolint: funlen
 		nodesWg.Wait()
 	}
 
+	if c.deployDiff != nil {
+		c.finishIncrementalDeploy(ctx, c.deployDiff)
+	}
+
 	execCollection.Log()
 
 	if err := c.GenerateInventories(); err != nil {
@@ -153,6 +229,14 @@ func (c *CLab) Deploy( //nolint: funlen
 		return nil, err
 	}
 
+	if err := c.GenerateFacts(); err != nil {
+		log.Warnf("failed to write the lab facts: %v", err)
+	}
+
+	if err := c.writeLabState(state); err != nil {
+		log.Warnf("failed to record the lab state: %v", err)
+	}
+
 	// generate graph of the lab topology
 	if options.graph {
 		if err = c.GenerateDotGraph(); err != nil {
@@ -165,10 +249,20 @@ func (c *CLab) Deploy( //nolint: funlen
 		return nil, err
 	}
 
-	log.Info("Adding host entries", "path", "/etc/hosts")
-	err = c.appendHostsFileEntries(ctx)
-	if err != nil {
-		log.Errorf("failed to create hosts file: %v", err)
+	// the hosts file of the host is not writable in the rootless mode
+	if clabutils.IsRootless() {
+		log.Info("Rootless mode, skipping host entries", "path", "/etc/hosts")
+	} else {
+		log.Info("Adding host entries", "path", "/etc/hosts")
+		err = c.appendHostsFileEntries(ctx)
+		if err != nil {
+			log.Errorf("failed to create hosts file: %v", err)
+		}
+
+		err = c.addHostRoutes(ctx)
+		if err != nil {
+			log.Errorf("failed to add the host routes: %v", err)
+		}
 	}
 
 	log.Info("Adding SSH config for nodes", "path", c.TopoPaths.SSHConfigPath())
@@ -180,6 +274,32 @@ func (c *CLab) Deploy( //nolint: funlen
 	return containers, nil
 }
 
+// writeStartupConfigs writes the rendered startup-configs to the lab directories of the nodes
+// and makes them the nodes' startup-config, enforced over the config kept from a previous deployment.
+func (c *CLab) writeStartupConfigs(cfgs map[string]string) error {
+	for name, cfg := range cfgs {
+		n, ok := c.scheduledNodes()[name]
+		if !ok {
+			continue
+		}
+		nodeCfg := n.Config()
+
+		clabutils.CreateDirectory(nodeCfg.LabDir, 0o755)
+
+		p := filepath.Join(nodeCfg.LabDir, renderedStartupConfigFileName)
+		err := os.WriteFile(p, []byte(cfg), 0o644) // skipcq: GSC-G306
+		if err != nil {
+			return fmt.Errorf("failed to write the rendered startup-config of %s: %w", name, err)
+		}
+
+		log.Debug("Using the rendered startup-config", "node", name, "file", p)
+		nodeCfg.StartupConfig = p
+		nodeCfg.EnforceStartupConfig = true
+	}
+
+	return nil
+}
+
 // certificateAuthoritySetup sets up the certificate authority parameters.
 func (c *CLab) certificateAuthoritySetup() error {
 	// init the Cert storage and CA
@@ -243,7 +363,7 @@ func (c *CLab) certificateAuthoritySetup() error {
 // is printed after the nodes are created.
 // Nodes interdependencies are created in this function.
 func (c *CLab) createNodes(ctx context.Context, maxWorkers uint, skipPostDeploy bool) (*sync.WaitGroup, *clabexec.ExecCollection, error) {
-	for _, node := range c.Nodes {
+	for _, node := range c.scheduledNodes() {
 		c.dependencyManager.AddNode(node)
 	}
 
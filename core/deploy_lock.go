@@ -0,0 +1,70 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// lockDeploy marks the deployment of the lab as in progress until the returned function is called,
+// so that cleanup leaves the resources of the lab alone. The mark holds the pid of the deploying
+// process, the mark left behind by a crashed deployment is stale and ignored.
+func lockDeploy(lab string) (func(), error) {
+	if pid, ok := deployInProgress(lab); ok {
+		return nil, fmt.Errorf("lab %s is being deployed by process %d", lab, pid)
+	}
+
+	clabutils.CreateDirectory(clabtypes.DeployLocksDir(), 0o755)
+
+	path := clabtypes.DeployLockFileAbsPath(lab)
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())), 0o644); err != nil { // skipcq: GSC-G306
+		return nil, fmt.Errorf("failed to mark the deployment of lab %s: %w", lab, err)
+	}
+
+	return func() {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			log.Warnf("failed to remove the deployment mark of lab %s: %v", lab, err)
+		}
+	}, nil
+}
+
+// deployInProgress returns the pid of the process deploying the lab, false when the lab is not being deployed.
+func deployInProgress(lab string) (int, bool) {
+	b, err := os.ReadFile(clabtypes.DeployLockFileAbsPath(lab))
+	if err != nil {
+		return 0, false
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(b)))
+	if err != nil || !clabutils.ProcessExists(pid) {
+		return 0, false
+	}
+
+	return pid, true
+}
+
+// deploysInProgress returns the names of the labs being deployed.
+func deploysInProgress() []string {
+	files, err := filepath.Glob(clabtypes.DeployLockFileAbsPath("*"))
+	if err != nil {
+		return nil
+	}
+
+	var labs []string
+	for _, f := range files {
+		lab := strings.TrimSuffix(filepath.Base(f), filepath.Ext(f))
+		if _, ok := deployInProgress(lab); ok {
+			labs = append(labs, lab)
+		}
+	}
+
+	return labs
+}
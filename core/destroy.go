@@ -94,6 +94,12 @@ func (c *CLab) Destroy(ctx context.Context, options ...DestroyOption) (err error
 			return err
 		}
 
+		for _, h := range opts.hooks {
+			if err := h(ctx, cc); err != nil {
+				log.Warnf("Error occurred before the %s lab deletion: %v", cc.Config.Name, err)
+			}
+		}
+
 		err = cc.destroy(ctx, opts.maxWorkers, opts.keepMgmtNet)
 		if err != nil {
 			log.Errorf("Error occurred during the %s lab deletion: %v", cc.Config.Name, err)
@@ -246,10 +252,14 @@ func (c *CLab) destroy(ctx context.Context, maxWorkers uint, keepMgmtNet bool) e
 
 	c.deleteToolContainers(ctx)
 
-	log.Info("Removing host entries", "path", "/etc/hosts")
-	err = c.DeleteEntriesFromHostsFile()
-	if err != nil {
-		return fmt.Errorf("error while trying to clean up the hosts file: %w", err)
+	if !clabutils.IsRootless() {
+		log.Info("Removing host entries", "path", "/etc/hosts")
+		err = c.DeleteEntriesFromHostsFile()
+		if err != nil {
+			return fmt.Errorf("error while trying to clean up the hosts file: %w", err)
+		}
+
+		c.deleteHostRoutes()
 	}
 
 	log.Info("Removing SSH config", "path", c.TopoPaths.SSHConfigPath())
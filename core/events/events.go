@@ -0,0 +1,168 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package events emits containerlab lifecycle events as JSON
+// to a webhook URL or a local unix socket.
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// EnvVar is the environment variable that can be used to set the events target.
+const EnvVar = "CLAB_EVENTS_URL"
+
+// emitTimeout limits the time spent delivering a single event.
+const emitTimeout = 5 * time.Second
+
+// Event types.
+const (
+	DeployStarted  = "deploy-started"
+	DeployFinished = "deploy-finished"
+	DeployFailed   = "deploy-failed"
+	NodeFailed     = "node-failed"
+	ConfigResult   = "config-result"
+	ConfigDrift    = "config-drift"
+	ChaosStarted   = "chaos-started"
+	ChaosAction    = "chaos-action"
+	ChaosFinished  = "chaos-finished"
+	LabExpiring    = "lab-expiring"
+	LabExpired     = "lab-expired"
+)
+
+// Event statuses.
+const (
+	StatusOK     = "ok"
+	StatusFailed = "failed"
+)
+
+// Event is a lifecycle event of a lab.
+type Event struct {
+	Type    string    `json:"type"`
+	Time    time.Time `json:"time"`
+	Lab     string    `json:"lab"`
+	Node    string    `json:"node,omitempty"`
+	Action  string    `json:"action,omitempty"`
+	Target  string    `json:"target,omitempty"`
+	Status  string    `json:"status,omitempty"`
+	Message string    `json:"message,omitempty"`
+}
+
+// Emitter delivers events to the configured target.
+// A nil Emitter discards all events.
+type Emitter struct {
+	target string
+	// socket is the path of the unix socket, empty for webhooks.
+	socket string
+	client *http.Client
+}
+
+// NewEmitter returns an Emitter for the target, which is either a http(s) webhook URL
+// or a unix socket in the unix:///path/to/socket form.
+// An empty target returns a nil Emitter.
+func NewEmitter(target string) (*Emitter, error) {
+	if target == "" {
+		return nil, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid events target %q: %w", target, err)
+	}
+
+	e := &Emitter{target: target}
+
+	switch u.Scheme {
+	case "http", "https":
+		e.client = &http.Client{Timeout: emitTimeout}
+	case "unix":
+		e.socket = u.Path
+	default:
+		return nil, fmt.Errorf("unsupported events target %q, expected http(s):// or unix:// URL", target)
+	}
+
+	return e, nil
+}
+
+// Emit delivers the event. Delivery errors are logged and do not affect the lab operation.
+func (e *Emitter) Emit(ctx context.Context, ev *Event) {
+	if e == nil {
+		return
+	}
+
+	if ev.Time.IsZero() {
+		ev.Time = time.Now().UTC()
+	}
+
+	b, err := json.Marshal(ev)
+	if err != nil {
+		log.Warnf("failed to encode %s event: %v", ev.Type, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), emitTimeout)
+	defer cancel()
+
+	if e.socket != "" {
+		err = e.writeSocket(ctx, b)
+	} else {
+		err = e.post(ctx, b)
+	}
+
+	if err != nil {
+		log.Warnf("failed to deliver %s event to %s: %v", ev.Type, e.target, err)
+		return
+	}
+
+	log.Debugf("delivered event %s", b)
+}
+
+// post sends the event to the webhook.
+func (e *Emitter) post(ctx context.Context, b []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.target, bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected response status %s", resp.Status)
+	}
+
+	return nil
+}
+
+// writeSocket writes the event as a single JSON line to the unix socket.
+func (e *Emitter) writeSocket(ctx context.Context, b []byte) error {
+	var d net.Dialer
+
+	conn, err := d.DialContext(ctx, "unix", e.socket)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetWriteDeadline(deadline)
+	}
+
+	_, err = conn.Write(append(b, '\n'))
+
+	return err
+}
@@ -0,0 +1,110 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package events
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestNewEmitter(t *testing.T) {
+	tests := map[string]struct {
+		target string
+		isNil  bool
+		err    bool
+	}{
+		"empty":   {target: "", isNil: true},
+		"webhook": {target: "https://example.com/hook"},
+		"socket":  {target: "unix:///run/clab-events.sock"},
+		"invalid": {target: "tcp://127.0.0.1:8080", err: true},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			e, err := NewEmitter(tc.target)
+			if tc.err {
+				if err == nil {
+					t.Fatalf("expected error for %q", tc.target)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (e == nil) != tc.isNil {
+				t.Errorf("unexpected emitter %v for %q", e, tc.target)
+			}
+		})
+	}
+}
+
+func TestEmitWebhook(t *testing.T) {
+	got := make(chan *Event, 1)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		ev := &Event{}
+		if err := json.NewDecoder(r.Body).Decode(ev); err != nil {
+			t.Error(err)
+		}
+		got <- ev
+	}))
+	defer srv.Close()
+
+	e, err := NewEmitter(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.Emit(context.Background(), &Event{Type: NodeFailed, Lab: "lab", Node: "srl1", Status: StatusFailed})
+
+	ev := <-got
+	if ev.Type != NodeFailed || ev.Lab != "lab" || ev.Node != "srl1" || ev.Time.IsZero() {
+		t.Errorf("unexpected event %+v", ev)
+	}
+}
+
+func TestEmitSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "events.sock")
+
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	got := make(chan *Event, 1)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		ev := &Event{}
+		line, _ := bufio.NewReader(conn).ReadBytes('\n')
+		if err := json.Unmarshal(line, ev); err != nil {
+			t.Error(err)
+		}
+		got <- ev
+	}()
+
+	e, err := NewEmitter("unix://" + sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	e.Emit(context.Background(), &Event{Type: DeployStarted, Lab: "lab"})
+
+	ev := <-got
+	if ev.Type != DeployStarted || ev.Lab != "lab" {
+		t.Errorf("unexpected event %+v", ev)
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/charmbracelet/log"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+// LabExpiry is the expiry time of a deployed lab, set with the TTL of the lab on deploy.
+type LabExpiry struct {
+	Lab     string
+	Expires time.Time
+}
+
+// Expires returns the expiry time of the lab set with its TTL, zero for a lab without a TTL.
+func (c *CLab) Expires() time.Time {
+	return c.expires
+}
+
+// LabExpiries returns the expiry times of the deployed labs with a TTL, sorted by expiry time.
+func (c *CLab) LabExpiries(ctx context.Context) ([]*LabExpiry, error) {
+	containers, err := c.ListContainers(ctx, WithListclabLabelExists())
+	if err != nil {
+		return nil, err
+	}
+
+	return labExpiries(containers), nil
+}
+
+// labExpiries returns the expiry times of the labs of the containers. The nodes updated
+// by an incremental deploy get a later expiry time, the earliest one is the expiry of the lab.
+func labExpiries(containers []clabruntime.GenericContainer) []*LabExpiry {
+	labs := map[string]*LabExpiry{}
+
+	for _, cnt := range containers {
+		v, ok := cnt.Labels[clablabels.Expires]
+		if !ok {
+			continue
+		}
+
+		exp, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			log.Warn("Invalid expiry label", "container", cnt.Names, "value", v)
+			continue
+		}
+
+		lab := cnt.Labels[clablabels.Containerlab]
+		if e, ok := labs[lab]; ok && !exp.Before(e.Expires) {
+			continue
+		}
+
+		labs[lab] = &LabExpiry{Lab: lab, Expires: exp}
+	}
+
+	res := make([]*LabExpiry, 0, len(labs))
+	for _, e := range labs {
+		res = append(res, e)
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if !res[i].Expires.Equal(res[j].Expires) {
+			return res[i].Expires.Before(res[j].Expires)
+		}
+		return res[i].Lab < res[j].Lab
+	})
+
+	return res
+}
@@ -0,0 +1,37 @@
+package core
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+func TestLabExpiries(t *testing.T) {
+	cnt := func(lab, expires string) clabruntime.GenericContainer {
+		labels := map[string]string{clablabels.Containerlab: lab}
+		if expires != "" {
+			labels[clablabels.Expires] = expires
+		}
+		return clabruntime.GenericContainer{Names: []string{"clab-" + lab}, Labels: labels}
+	}
+
+	containers := []clabruntime.GenericContainer{
+		cnt("dc", "2025-01-01T16:00:00Z"),
+		// a node updated by an incremental deploy
+		cnt("dc", "2025-01-01T18:00:00Z"),
+		cnt("ci", "2025-01-01T13:00:00Z"),
+		cnt("static", ""),
+		cnt("broken", "tomorrow"),
+	}
+
+	want := []*LabExpiry{
+		{Lab: "ci", Expires: time.Date(2025, 1, 1, 13, 0, 0, 0, time.UTC)},
+		{Lab: "dc", Expires: time.Date(2025, 1, 1, 16, 0, 0, 0, time.UTC)},
+	}
+	if d := cmp.Diff(want, labExpiries(containers)); d != "" {
+		t.Errorf("expiries mismatch (-want +got):\n%s", d)
+	}
+}
@@ -0,0 +1,63 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// NodeFacts are the runtime facts of a lab node, known once the lab is deployed.
+type NodeFacts struct {
+	LongName             string `json:"long_name"`
+	Fqdn                 string `json:"fqdn,omitempty"`
+	Kind                 string `json:"kind"`
+	ContainerID          string `json:"container_id,omitempty"`
+	MgmtIPv4Address      string `json:"mgmt_ipv4,omitempty"`
+	MgmtIPv4PrefixLength int    `json:"mgmt_ipv4_prefix_length,omitempty"`
+	MgmtIPv4Gateway      string `json:"mgmt_ipv4_gateway,omitempty"`
+	MgmtIPv6Address      string `json:"mgmt_ipv6,omitempty"`
+	MgmtIPv6PrefixLength int    `json:"mgmt_ipv6_prefix_length,omitempty"`
+	MgmtIPv6Gateway      string `json:"mgmt_ipv6_gateway,omitempty"`
+	MacAddress           string `json:"mac_address,omitempty"`
+	// TLSCert and TLSKey are the paths of the certificate and key generated for the node
+	TLSCert string `json:"tls_cert,omitempty"`
+	TLSKey  string `json:"tls_key,omitempty"`
+}
+
+// GenerateFacts writes the runtime facts of the lab nodes to the facts file in the lab directory,
+// so that they can be referenced by the config templates and verification checks.
+func (c *CLab) GenerateFacts() error {
+	facts := make(map[string]*NodeFacts, len(c.Nodes))
+
+	for name, n := range c.Nodes {
+		cfg := n.Config()
+		f := &NodeFacts{
+			LongName:             cfg.LongName,
+			Fqdn:                 cfg.Fqdn,
+			Kind:                 cfg.Kind,
+			ContainerID:          cfg.ContainerID,
+			MgmtIPv4Address:      cfg.MgmtIPv4Address,
+			MgmtIPv4PrefixLength: cfg.MgmtIPv4PrefixLength,
+			MgmtIPv4Gateway:      cfg.MgmtIPv4Gateway,
+			MgmtIPv6Address:      cfg.MgmtIPv6Address,
+			MgmtIPv6PrefixLength: cfg.MgmtIPv6PrefixLength,
+			MgmtIPv6Gateway:      cfg.MgmtIPv6Gateway,
+			MacAddress:           cfg.MacAddress,
+		}
+
+		if p := c.TopoPaths.NodeCertAbsFilename(name); clabutils.FileExists(p) {
+			f.TLSCert = p
+			f.TLSKey = c.TopoPaths.NodeCertKeyAbsFilename(name)
+		}
+
+		facts[name] = f
+	}
+
+	b, err := json.MarshalIndent(facts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.TopoPaths.FactsFileAbsPath(), b, 0o644) // skipcq: GSC-G306
+}
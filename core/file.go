@@ -77,26 +77,14 @@ func (c *CLab) LoadTopologyFromFile(topo, varsFile string) error {
 
 func readTemplateVariables(topo, varsFile string) (interface{}, error) {
 	var templateVars interface{}
-	// variable file is not explicitly set
-	if varsFile == "" {
-		ext := filepath.Ext(topo)
-		for _, vext := range []string{".yaml", ".yml", ".json"} {
-			varsFile = fmt.Sprintf("%s%s%s", topo[0:len(topo)-len(ext)], varFileSuffix, vext)
-			_, err := os.Stat(varsFile)
-			switch {
-			case os.IsNotExist(err):
-				continue
-			case err != nil:
-				return nil, err
-			}
-			// file with current extension found, go read it.
-			goto READFILE
-		}
+
+	varsFile, err := varsFilePath(topo, varsFile)
+	if err != nil || varsFile == "" {
 		// no var file found, assume the topology is not a template
 		// or a template that doesn't require external variables
-		return nil, nil
+		return nil, err
 	}
-READFILE:
+
 	data, err := os.ReadFile(varsFile)
 	if err != nil {
 		return nil, err
@@ -104,3 +92,27 @@ READFILE:
 	err = yaml.Unmarshal(data, &templateVars)
 	return templateVars, err
 }
+
+// varsFilePath returns the path of the variables file of the topology, the explicitly set one
+// or the one named after the topology file with the _vars suffix, empty if none exists.
+func varsFilePath(topo, varsFile string) (string, error) {
+	if varsFile != "" {
+		return varsFile, nil
+	}
+
+	ext := filepath.Ext(topo)
+	for _, vext := range []string{".yaml", ".yml", ".json"} {
+		varsFile = fmt.Sprintf("%s%s%s", topo[0:len(topo)-len(ext)], varFileSuffix, vext)
+		_, err := os.Stat(varsFile)
+		switch {
+		case os.IsNotExist(err):
+			continue
+		case err != nil:
+			return "", err
+		}
+
+		return varsFile, nil
+	}
+
+	return "", nil
+}
@@ -0,0 +1,385 @@
+// Package hostcheck validates the host settings containerlab relies on before a lab is deployed,
+// suggesting the fixes for the failed checks.
+package hostcheck
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	dockerC "github.com/docker/docker/client"
+	"golang.org/x/mod/semver"
+)
+
+// Status of a check.
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+)
+
+const (
+	// minDockerVersion is the oldest docker engine release supported by containerlab.
+	minDockerVersion = "v23.0.0"
+	// the inotify limits below which the labs with many nodes fail to start their processes.
+	minInotifyInstances = 512
+	minInotifyWatches   = 524288
+)
+
+// Result is the result of a host check.
+type Result struct {
+	Check  string `json:"check"`
+	Status Status `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	// Fix is the action fixing a failed check.
+	Fix string `json:"fix,omitempty"`
+}
+
+// Requirements are the host requirements of a lab.
+type Requirements struct {
+	// KernelModules are the kernel modules required by the kinds and links of the lab.
+	KernelModules []string
+	// KVM is true if the lab has VM-based nodes.
+	KVM bool
+	// MemoryGB is the memory in GB the lab nodes require.
+	MemoryGB float64
+	// IPv6 is true if the management network of the lab has an IPv6 subnet.
+	IPv6 bool
+}
+
+// Checker runs the host checks.
+type Checker struct {
+	// root is the directory the /proc, /sys and /dev paths are relative to.
+	root string
+	// dockerVersion returns the version of the docker engine.
+	dockerVersion func(ctx context.Context) (string, error)
+}
+
+// Option configures a Checker.
+type Option func(*Checker)
+
+// WithRoot sets the directory the /proc, /sys and /dev paths are relative to.
+func WithRoot(root string) Option {
+	return func(c *Checker) {
+		c.root = root
+	}
+}
+
+// WithDockerVersion sets the function returning the version of the docker engine,
+// the docker check is skipped without it.
+func WithDockerVersion(f func(ctx context.Context) (string, error)) Option {
+	return func(c *Checker) {
+		c.dockerVersion = f
+	}
+}
+
+// DockerEngineVersion returns the version of the docker engine of the host.
+func DockerEngineVersion(ctx context.Context) (string, error) {
+	client, err := dockerC.NewClientWithOpts(dockerC.FromEnv, dockerC.WithAPIVersionNegotiation())
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	v, err := client.ServerVersion(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	return v.Version, nil
+}
+
+// NewChecker returns a Checker of the host.
+func NewChecker(opts ...Option) *Checker {
+	c := &Checker{root: "/"}
+	for _, o := range opts {
+		o(c)
+	}
+
+	return c
+}
+
+// Run runs the host checks, the checks of the lab requirements only when they are set.
+func (c *Checker) Run(ctx context.Context, req *Requirements) []*Result {
+	if req == nil {
+		req = &Requirements{}
+	}
+
+	var res []*Result
+
+	if c.dockerVersion != nil {
+		res = append(res, c.checkDocker(ctx))
+	}
+
+	for _, m := range req.KernelModules {
+		res = append(res, c.checkKernelModule(m))
+	}
+
+	if req.KVM {
+		res = append(res, c.checkKVM())
+	}
+
+	res = append(res, c.checkMemory(req.MemoryGB), c.checkHugepages())
+	res = append(res, c.checkInotify()...)
+	res = append(res, c.checkIPv4Forwarding())
+
+	if req.IPv6 {
+		res = append(res, c.checkIPv6())
+	}
+
+	return res
+}
+
+func (c *Checker) path(p string) string {
+	return filepath.Join(c.root, p)
+}
+
+func (c *Checker) checkDocker(ctx context.Context) *Result {
+	r := &Result{Check: "docker version"}
+
+	v, err := c.dockerVersion(ctx)
+	if err != nil {
+		r.Status = StatusFail
+		r.Detail = fmt.Sprintf("docker engine is not reachable: %v", err)
+		r.Fix = "install docker and start it: sudo systemctl enable --now docker"
+
+		return r
+	}
+
+	r.Detail = v
+
+	sv := "v" + strings.TrimPrefix(v, "v")
+	if semver.IsValid(sv) && semver.Compare(sv, minDockerVersion) < 0 {
+		r.Status = StatusFail
+		r.Detail = fmt.Sprintf("docker %s is older than %s", v, strings.TrimPrefix(minDockerVersion, "v"))
+		r.Fix = "upgrade docker: https://docs.docker.com/engine/install/"
+
+		return r
+	}
+
+	r.Status = StatusOK
+
+	return r
+}
+
+// checkKernelModule checks the module is loaded or built into the kernel, as listed in /sys/module.
+func (c *Checker) checkKernelModule(name string) *Result {
+	r := &Result{Check: "kernel module " + name}
+
+	if _, err := os.Stat(c.path(filepath.Join("/sys/module", name))); err != nil {
+		r.Status = StatusFail
+		r.Detail = "not loaded"
+		r.Fix = fmt.Sprintf("sudo modprobe %s, and add it to /etc/modules-load.d to load it at boot", name)
+
+		return r
+	}
+
+	r.Status = StatusOK
+	r.Detail = "loaded"
+
+	return r
+}
+
+func (c *Checker) checkKVM() *Result {
+	r := &Result{Check: "/dev/kvm"}
+
+	if _, err := os.Stat(c.path("/dev/kvm")); err != nil {
+		r.Status = StatusFail
+		r.Detail = "the VM-based nodes need KVM, /dev/kvm does not exist"
+		r.Fix = "enable the virtualization in the BIOS or the nested virtualization of the VM, " +
+			"and load the kvm_intel or kvm_amd module"
+
+		return r
+	}
+
+	r.Status = StatusOK
+	r.Detail = "present"
+
+	return r
+}
+
+func (c *Checker) checkMemory(requiredGB float64) *Result {
+	r := &Result{Check: "available memory"}
+
+	mem, err := c.meminfo()
+	if err != nil {
+		r.Status = StatusWarn
+		r.Detail = fmt.Sprintf("failed to read the memory info: %v", err)
+
+		return r
+	}
+
+	availGB := float64(mem["MemAvailable"]) / 1024 / 1024
+
+	r.Detail = fmt.Sprintf("%.1f GB available", availGB)
+	r.Status = StatusOK
+
+	if requiredGB > 0 {
+		r.Detail = fmt.Sprintf("%.1f GB available, the lab requires %.1f GB", availGB, requiredGB)
+		if availGB < requiredGB {
+			r.Status = StatusFail
+			r.Fix = "free memory on the host, or deploy a part of the lab with the --node-filter flag"
+		}
+	}
+
+	return r
+}
+
+func (c *Checker) checkHugepages() *Result {
+	r := &Result{Check: "hugepages", Status: StatusOK}
+
+	mem, err := c.meminfo()
+	if err != nil {
+		r.Status = StatusWarn
+		r.Detail = fmt.Sprintf("failed to read the memory info: %v", err)
+
+		return r
+	}
+
+	total, free := mem["HugePages_Total"], mem["HugePages_Free"]
+	if total == 0 {
+		r.Detail = "not configured"
+
+		return r
+	}
+
+	r.Detail = fmt.Sprintf("%d of %d free, reserving %d MB of memory", free, total,
+		total*mem["Hugepagesize"]/1024)
+
+	if free == 0 {
+		r.Status = StatusWarn
+		r.Fix = "raise vm.nr_hugepages for the nodes using hugepages: sudo sysctl -w vm.nr_hugepages=<pages>"
+	}
+
+	return r
+}
+
+func (c *Checker) checkInotify() []*Result {
+	limits := []struct {
+		name string
+		min  int
+	}{
+		{"max_user_instances", minInotifyInstances},
+		{"max_user_watches", minInotifyWatches},
+	}
+
+	res := make([]*Result, 0, len(limits))
+	for _, l := range limits {
+		r := &Result{Check: "inotify " + l.name}
+
+		v, err := c.sysctl("fs/inotify/" + l.name)
+		switch {
+		case err != nil:
+			r.Status = StatusWarn
+			r.Detail = fmt.Sprintf("failed to read the limit: %v", err)
+		case v < l.min:
+			r.Status = StatusWarn
+			r.Detail = fmt.Sprintf("%d is below %d", v, l.min)
+			r.Fix = fmt.Sprintf("sudo sysctl -w fs.inotify.%s=%d, and persist it in /etc/sysctl.d", l.name, l.min)
+		default:
+			r.Status = StatusOK
+			r.Detail = strconv.Itoa(v)
+		}
+
+		res = append(res, r)
+	}
+
+	return res
+}
+
+func (c *Checker) checkIPv4Forwarding() *Result {
+	r := &Result{Check: "ipv4 forwarding"}
+
+	v, err := c.sysctl("net/ipv4/ip_forward")
+	switch {
+	case err != nil:
+		r.Status = StatusWarn
+		r.Detail = fmt.Sprintf("failed to read the setting: %v", err)
+	case v != 1:
+		r.Status = StatusWarn
+		r.Detail = "disabled, the nodes can't reach the outside of the host"
+		r.Fix = "sudo sysctl -w net.ipv4.ip_forward=1, and persist it in /etc/sysctl.d"
+	default:
+		r.Status = StatusOK
+		r.Detail = "enabled"
+	}
+
+	return r
+}
+
+func (c *Checker) checkIPv6() *Result {
+	r := &Result{Check: "ipv6 forwarding"}
+
+	if v, err := c.sysctl("net/ipv6/conf/all/disable_ipv6"); err != nil || v == 1 {
+		r.Status = StatusFail
+		r.Detail = "IPv6 is disabled, the IPv6 management network can't be created"
+		r.Fix = "sudo sysctl -w net.ipv6.conf.all.disable_ipv6=0, or set an IPv4 only management network"
+
+		return r
+	}
+
+	v, err := c.sysctl("net/ipv6/conf/all/forwarding")
+	switch {
+	case err != nil:
+		r.Status = StatusWarn
+		r.Detail = fmt.Sprintf("failed to read the setting: %v", err)
+	case v != 1:
+		r.Status = StatusWarn
+		r.Detail = "disabled, the nodes can't reach the outside of the host over IPv6"
+		r.Fix = "sudo sysctl -w net.ipv6.conf.all.forwarding=1, and persist it in /etc/sysctl.d"
+	default:
+		r.Status = StatusOK
+		r.Detail = "enabled"
+	}
+
+	return r
+}
+
+// sysctl reads the integer value of the sysctl by its path under /proc/sys.
+func (c *Checker) sysctl(name string) (int, error) {
+	b, err := os.ReadFile(c.path(filepath.Join("/proc/sys", name)))
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.Atoi(strings.TrimSpace(string(b)))
+}
+
+// meminfo reads /proc/meminfo, the values in kB or in pages for the hugepages counters.
+func (c *Checker) meminfo() (map[string]int, error) {
+	f, err := os.Open(c.path("/proc/meminfo"))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	res := map[string]int{}
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		k, v, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+
+		fields := strings.Fields(v)
+		if len(fields) == 0 {
+			continue
+		}
+
+		n, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		res[k] = n
+	}
+
+	return res, scanner.Err()
+}
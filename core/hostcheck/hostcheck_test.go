@@ -0,0 +1,157 @@
+package hostcheck
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// writeHostFiles writes the files under the root directory by their path.
+func writeHostFiles(t *testing.T, root string, files map[string]string) {
+	t.Helper()
+
+	for p, content := range files {
+		p = filepath.Join(root, p)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckerRun(t *testing.T) {
+	meminfo := "MemTotal:       16384000 kB\n" +
+		"MemAvailable:    8388608 kB\n" +
+		"HugePages_Total:       0\n" +
+		"HugePages_Free:        0\n" +
+		"Hugepagesize:       2048 kB\n"
+
+	tests := map[string]struct {
+		files         map[string]string
+		req           *Requirements
+		dockerVersion func(context.Context) (string, error)
+		want          []*Result
+	}{
+		"ready host without a topology": {
+			files: map[string]string{
+				"/proc/meminfo": meminfo,
+				"/proc/sys/fs/inotify/max_user_instances": "1024\n",
+				"/proc/sys/fs/inotify/max_user_watches":   "1048576\n",
+				"/proc/sys/net/ipv4/ip_forward":           "1\n",
+			},
+			dockerVersion: func(context.Context) (string, error) { return "27.5.1", nil },
+			want: []*Result{
+				{Check: "docker version", Status: StatusOK, Detail: "27.5.1"},
+				{Check: "available memory", Status: StatusOK, Detail: "8.0 GB available"},
+				{Check: "hugepages", Status: StatusOK, Detail: "not configured"},
+				{Check: "inotify max_user_instances", Status: StatusOK, Detail: "1024"},
+				{Check: "inotify max_user_watches", Status: StatusOK, Detail: "1048576"},
+				{Check: "ipv4 forwarding", Status: StatusOK, Detail: "enabled"},
+			},
+		},
+		"host missing the lab requirements": {
+			files: map[string]string{
+				"/proc/meminfo": "MemAvailable:    2097152 kB\n" +
+					"HugePages_Total:     512\n" +
+					"HugePages_Free:        0\n" +
+					"Hugepagesize:       2048 kB\n",
+				"/proc/sys/fs/inotify/max_user_instances":  "128\n",
+				"/proc/sys/fs/inotify/max_user_watches":    "1048576\n",
+				"/proc/sys/net/ipv4/ip_forward":            "0\n",
+				"/proc/sys/net/ipv6/conf/all/disable_ipv6": "0\n",
+				"/proc/sys/net/ipv6/conf/all/forwarding":   "1\n",
+				"/sys/module/vxlan/version":                "",
+			},
+			req: &Requirements{
+				KernelModules: []string{"openvswitch", "vxlan"},
+				KVM:           true,
+				MemoryGB:      6,
+				IPv6:          true,
+			},
+			dockerVersion: func(context.Context) (string, error) { return "20.10.24", nil },
+			want: []*Result{
+				{
+					Check: "docker version", Status: StatusFail, Detail: "docker 20.10.24 is older than 23.0.0",
+					Fix: "upgrade docker: https://docs.docker.com/engine/install/",
+				},
+				{
+					Check: "kernel module openvswitch", Status: StatusFail, Detail: "not loaded",
+					Fix: "sudo modprobe openvswitch, and add it to /etc/modules-load.d to load it at boot",
+				},
+				{Check: "kernel module vxlan", Status: StatusOK, Detail: "loaded"},
+				{
+					Check: "/dev/kvm", Status: StatusFail, Detail: "the VM-based nodes need KVM, /dev/kvm does not exist",
+					Fix: "enable the virtualization in the BIOS or the nested virtualization of the VM, " +
+						"and load the kvm_intel or kvm_amd module",
+				},
+				{
+					Check: "available memory", Status: StatusFail, Detail: "2.0 GB available, the lab requires 6.0 GB",
+					Fix: "free memory on the host, or deploy a part of the lab with the --node-filter flag",
+				},
+				{
+					Check: "hugepages", Status: StatusWarn, Detail: "0 of 512 free, reserving 1024 MB of memory",
+					Fix: "raise vm.nr_hugepages for the nodes using hugepages: sudo sysctl -w vm.nr_hugepages=<pages>",
+				},
+				{
+					Check: "inotify max_user_instances", Status: StatusWarn, Detail: "128 is below 512",
+					Fix: "sudo sysctl -w fs.inotify.max_user_instances=512, and persist it in /etc/sysctl.d",
+				},
+				{Check: "inotify max_user_watches", Status: StatusOK, Detail: "1048576"},
+				{
+					Check: "ipv4 forwarding", Status: StatusWarn, Detail: "disabled, the nodes can't reach the outside of the host",
+					Fix: "sudo sysctl -w net.ipv4.ip_forward=1, and persist it in /etc/sysctl.d",
+				},
+				{Check: "ipv6 forwarding", Status: StatusOK, Detail: "enabled"},
+			},
+		},
+		"unreachable docker and disabled ipv6": {
+			files: map[string]string{
+				"/proc/meminfo": meminfo,
+				"/proc/sys/fs/inotify/max_user_instances":  "1024\n",
+				"/proc/sys/fs/inotify/max_user_watches":    "1048576\n",
+				"/proc/sys/net/ipv4/ip_forward":            "1\n",
+				"/proc/sys/net/ipv6/conf/all/disable_ipv6": "1\n",
+			},
+			req: &Requirements{IPv6: true},
+			dockerVersion: func(context.Context) (string, error) {
+				return "", errors.New("connection refused")
+			},
+			want: []*Result{
+				{
+					Check: "docker version", Status: StatusFail, Detail: "docker engine is not reachable: connection refused",
+					Fix: "install docker and start it: sudo systemctl enable --now docker",
+				},
+				{Check: "available memory", Status: StatusOK, Detail: "8.0 GB available"},
+				{Check: "hugepages", Status: StatusOK, Detail: "not configured"},
+				{Check: "inotify max_user_instances", Status: StatusOK, Detail: "1024"},
+				{Check: "inotify max_user_watches", Status: StatusOK, Detail: "1048576"},
+				{Check: "ipv4 forwarding", Status: StatusOK, Detail: "enabled"},
+				{
+					Check: "ipv6 forwarding", Status: StatusFail,
+					Detail: "IPv6 is disabled, the IPv6 management network can't be created",
+					Fix:    "sudo sysctl -w net.ipv6.conf.all.disable_ipv6=0, or set an IPv4 only management network",
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			root := t.TempDir()
+			writeHostFiles(t, root, tt.files)
+
+			c := NewChecker(WithRoot(root), WithDockerVersion(tt.dockerVersion))
+
+			got := c.Run(context.Background(), tt.req)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Run() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	claberrors "github.com/srl-labs/containerlab/errors"
+	"github.com/vishvananda/netlink"
+)
+
+// hostRouteProtocol marks the host routes installed by containerlab,
+// so that only those are removed when the lab is destroyed.
+const hostRouteProtocol = netlink.RouteProtocol(0x63)
+
+// sysctlPath is the directory of the kernel parameters, a variable for the tests.
+var sysctlPath = "/proc/sys"
+
+// hostRoute is a host route resolved from the topology.
+type hostRoute struct {
+	dst *net.IPNet
+	via string
+}
+
+// hostRoutesState records the host routes installed by the lab and the previous values of the
+// kernel parameters changed for them, to remove and restore them when the lab is destroyed.
+type hostRoutesState struct {
+	Routes []*installedRoute `json:"routes"`
+	// Sysctls are the previous values of the changed kernel parameters by name.
+	Sysctls map[string]string `json:"sysctls,omitempty"`
+}
+
+// installedRoute is a host route installed by the lab.
+type installedRoute struct {
+	Dst  string `json:"dst"`
+	Gw   string `json:"gw"`
+	Link string `json:"link"`
+}
+
+// addRoute records the installed route, unless it is recorded already.
+func (s *hostRoutesState) addRoute(r *installedRoute) {
+	for _, ir := range s.Routes {
+		if *ir == *r {
+			return
+		}
+	}
+	s.Routes = append(s.Routes, r)
+}
+
+// setSysctl enables the kernel parameter, recording its previous value when it is changed.
+// A parameter changed by a previous deployment of the lab keeps the value it had before.
+func (s *hostRoutesState) setSysctl(name string) {
+	prev, changed := setSysctl(name)
+	if !changed {
+		return
+	}
+
+	if s.Sysctls == nil {
+		s.Sysctls = map[string]string{}
+	}
+	if _, ok := s.Sysctls[name]; !ok {
+		s.Sysctls[name] = prev
+	}
+}
+
+// restoreSysctls sets the changed kernel parameters back to their previous values.
+func (s *hostRoutesState) restoreSysctls() {
+	for name, v := range s.Sysctls {
+		err := os.WriteFile(filepath.Join(sysctlPath, name), []byte(v), 0o644) // skipcq: GSC-G306
+		if err != nil {
+			log.Warnf("failed to restore %s: %v", name, err)
+			continue
+		}
+
+		log.Debug("Restored kernel parameter", "name", name, "value", v)
+	}
+}
+
+// loadHostRoutesState reads the recorded host routes of the lab, empty if none are recorded.
+func loadHostRoutesState(path string) (*hostRoutesState, error) {
+	s := &hostRoutesState{}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("failed to parse the host routes %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// write records the host routes in the lab directory.
+func (s *hostRoutesState) write(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644) // skipcq: GSC-G306
+}
+
+// hostRoutes returns the host routes of the topology,
+// checking that the prefixes are valid and the gateway nodes are in the topology.
+func (c *CLab) hostRoutes() ([]*hostRoute, error) {
+	routes := make([]*hostRoute, 0, len(c.Config.Mgmt.HostRoutes))
+
+	for _, r := range c.Config.Mgmt.HostRoutes {
+		_, dst, err := net.ParseCIDR(r.Prefix)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid host route prefix %q", claberrors.ErrIncorrectInput, r.Prefix)
+		}
+
+		if _, ok := c.Nodes[r.Via]; !ok {
+			return nil, fmt.Errorf("%w: gateway node %q of the host route to %s is not present in the topology",
+				claberrors.ErrIncorrectInput, r.Via, r.Prefix)
+		}
+
+		routes = append(routes, &hostRoute{dst: dst, via: r.Via})
+	}
+
+	return routes, nil
+}
+
+// addHostRoutes installs the host routes toward the lab prefixes via the management
+// addresses of their gateway nodes and enables forwarding for their address families.
+// The routes and the previous values of the changed kernel parameters are recorded in the lab directory.
+func (c *CLab) addHostRoutes(ctx context.Context) error {
+	routes, err := c.hostRoutes()
+	if err != nil || len(routes) == 0 {
+		return err
+	}
+
+	link, err := netlink.LinkByName(c.Config.Mgmt.Bridge)
+	if err != nil {
+		return fmt.Errorf("failed to find the management bridge %q: %w", c.Config.Mgmt.Bridge, err)
+	}
+
+	path := c.TopoPaths.HostRoutesFileAbsPath()
+	state, err := loadHostRoutesState(path)
+	if err != nil {
+		return err
+	}
+
+	// the routes installed before a failure are recorded to be removed on destroy
+	defer func() {
+		if werr := state.write(path); werr != nil {
+			log.Warnf("failed to record the host routes: %v", werr)
+		}
+	}()
+
+	families := map[string]bool{}
+
+	for _, r := range routes {
+		gw, err := c.mgmtAddress(ctx, r.via, r.dst.IP.To4() != nil)
+		if err != nil {
+			return err
+		}
+
+		err = netlink.RouteReplace(&netlink.Route{
+			LinkIndex: link.Attrs().Index,
+			Dst:       r.dst,
+			Gw:        gw,
+			Protocol:  hostRouteProtocol,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to add the host route to %s via %s: %w", r.dst, r.via, err)
+		}
+
+		state.addRoute(&installedRoute{Dst: r.dst.String(), Gw: gw.String(), Link: link.Attrs().Name})
+
+		log.Info("Added host route", "prefix", r.dst, "node", r.via, "gateway", gw)
+
+		if r.dst.IP.To4() != nil {
+			families["ipv4"] = true
+		} else {
+			families["ipv6"] = true
+		}
+	}
+
+	if families["ipv4"] {
+		state.setSysctl("net/ipv4/ip_forward")
+	}
+	if families["ipv6"] {
+		state.setSysctl("net/ipv6/conf/all/forwarding")
+	}
+
+	if c.Config.Mgmt.ProxyARP {
+		state.setSysctl(filepath.Join("net/ipv4/conf", c.Config.Mgmt.Bridge, "proxy_arp"))
+		state.setSysctl(filepath.Join("net/ipv6/conf", c.Config.Mgmt.Bridge, "proxy_ndp"))
+	}
+
+	return nil
+}
+
+// deleteHostRoutes removes the host routes recorded by addHostRoutes and restores the kernel
+// parameters they changed. Only the routes via the recorded gateways and links are removed,
+// the routes to the same prefixes installed by other labs are left alone.
+func (c *CLab) deleteHostRoutes() {
+	path := c.TopoPaths.HostRoutesFileAbsPath()
+	state, err := loadHostRoutesState(path)
+	if err != nil {
+		log.Warnf("failed to read the host routes: %v", err)
+		return
+	}
+
+	for _, r := range state.Routes {
+		if err := deleteHostRoute(r); err != nil {
+			log.Warnf("failed to delete the host route to %s via %s: %v", r.Dst, r.Gw, err)
+		}
+	}
+
+	state.restoreSysctls()
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Warnf("failed to remove the host routes record: %v", err)
+	}
+}
+
+// deleteHostRoute removes the installed host route, if it is still present.
+func deleteHostRoute(r *installedRoute) error {
+	_, dst, err := net.ParseCIDR(r.Dst)
+	if err != nil {
+		return err
+	}
+	gw := net.ParseIP(r.Gw)
+	if gw == nil {
+		return fmt.Errorf("invalid gateway %q", r.Gw)
+	}
+
+	link, err := netlink.LinkByName(r.Link)
+	if err != nil {
+		// the routes are removed with their link
+		return nil
+	}
+
+	family := netlink.FAMILY_V6
+	if dst.IP.To4() != nil {
+		family = netlink.FAMILY_V4
+	}
+
+	installed, err := netlink.RouteListFiltered(family,
+		&netlink.Route{Dst: dst, Gw: gw, LinkIndex: link.Attrs().Index, Protocol: hostRouteProtocol},
+		netlink.RT_FILTER_DST|netlink.RT_FILTER_GW|netlink.RT_FILTER_OIF|netlink.RT_FILTER_PROTOCOL)
+	if err != nil {
+		return err
+	}
+
+	for i := range installed {
+		if err := netlink.RouteDel(&installed[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// mgmtAddress returns the IPv4 or IPv6 management address of the node.
+func (c *CLab) mgmtAddress(ctx context.Context, node string, v4 bool) (net.IP, error) {
+	containers, err := c.Nodes[node].GetContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for idx := range containers {
+		addr := containers[idx].NetworkSettings.IPv6addr
+		if v4 {
+			addr = containers[idx].NetworkSettings.IPv4addr
+		}
+
+		if ip := net.ParseIP(addr); ip != nil {
+			return ip, nil
+		}
+	}
+
+	family := "IPv6"
+	if v4 {
+		family = "IPv4"
+	}
+
+	return nil, fmt.Errorf("node %s has no %s management address to route to", node, family)
+}
+
+// setSysctl enables the kernel parameter, returning its previous value and whether it changed.
+// Failures are logged as the parameter may be set already by the host configuration.
+func setSysctl(name string) (string, bool) {
+	path := filepath.Join(sysctlPath, name)
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		log.Warnf("failed to read %s: %v", name, err)
+		return "", false
+	}
+
+	prev := strings.TrimSpace(string(b))
+	if prev == "1" {
+		return prev, false
+	}
+
+	err = os.WriteFile(path, []byte("1"), 0o644) // skipcq: GSC-G306
+	if err != nil {
+		log.Warnf("failed to enable %s: %v", name, err)
+		return prev, false
+	}
+
+	return prev, true
+}
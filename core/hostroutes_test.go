@@ -0,0 +1,155 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	claberrors "github.com/srl-labs/containerlab/errors"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestHostRoutes(t *testing.T) {
+	tests := map[string]struct {
+		routes []*clabtypes.HostRoute
+		want   []string
+		err    bool
+	}{
+		"none": {
+			want: []string{},
+		},
+		"routes": {
+			routes: []*clabtypes.HostRoute{
+				{Prefix: "10.0.0.1/32", Via: "srl1"},
+				{Prefix: "2001:db8::1/128", Via: "srl2"},
+				{Prefix: "192.168.1.5/24", Via: "srl1"},
+			},
+			want: []string{"10.0.0.1/32 via srl1", "2001:db8::1/128 via srl2", "192.168.1.0/24 via srl1"},
+		},
+		"invalid prefix": {
+			routes: []*clabtypes.HostRoute{{Prefix: "10.0.0.1", Via: "srl1"}},
+			err:    true,
+		},
+		"unknown node": {
+			routes: []*clabtypes.HostRoute{{Prefix: "10.0.0.1/32", Via: "srl3"}},
+			err:    true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c := &CLab{
+				Config: &Config{Mgmt: &clabtypes.MgmtNet{HostRoutes: tc.routes}},
+				Nodes:  map[string]clabnodes.Node{"srl1": nil, "srl2": nil},
+			}
+
+			routes, err := c.hostRoutes()
+			if tc.err {
+				if !errors.Is(err, claberrors.ErrIncorrectInput) {
+					t.Fatalf("expected an incorrect input error, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{}
+			for _, r := range routes {
+				got = append(got, r.dst.String()+" via "+r.via)
+			}
+			if d := cmp.Diff(tc.want, got); d != "" {
+				t.Errorf("host routes mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestHostRoutesState(t *testing.T) {
+	sysctlPath = t.TempDir()
+	t.Cleanup(func() { sysctlPath = "/proc/sys" })
+
+	params := map[string]string{
+		"net/ipv4/ip_forward":              "0\n",
+		"net/ipv6/conf/all/forwarding":     "1\n",
+		"net/ipv4/conf/br-clab/proxy_arp":  "0\n",
+		"net/ipv6/conf/br-clab/proxy_ndp":  "0\n",
+		"net/ipv4/conf/br-other/proxy_arp": "0\n",
+		"net/ipv6/conf/br-other/proxy_ndp": "0\n",
+	}
+	for name, v := range params {
+		path := filepath.Join(sysctlPath, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(v), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	read := func(name string) string {
+		b, err := os.ReadFile(filepath.Join(sysctlPath, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(b)
+	}
+
+	statePath := filepath.Join(t.TempDir(), "host-routes.json")
+
+	s, err := loadHostRoutesState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := &installedRoute{Dst: "10.0.0.0/24", Gw: "172.20.20.2", Link: "br-clab"}
+	s.addRoute(r)
+	s.addRoute(&installedRoute{Dst: "10.0.0.0/24", Gw: "172.20.20.2", Link: "br-clab"})
+	s.setSysctl("net/ipv4/ip_forward")
+	s.setSysctl("net/ipv6/conf/all/forwarding")
+	s.setSysctl("net/ipv4/conf/br-clab/proxy_arp")
+
+	if err := s.write(statePath); err != nil {
+		t.Fatal(err)
+	}
+
+	// a redeploy of the lab keeps the values from before the first deployment
+	s, err = loadHostRoutesState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.setSysctl("net/ipv4/ip_forward")
+	s.setSysctl("net/ipv6/conf/br-clab/proxy_ndp")
+
+	want := &hostRoutesState{
+		Routes: []*installedRoute{r},
+		Sysctls: map[string]string{
+			"net/ipv4/ip_forward":             "0",
+			"net/ipv4/conf/br-clab/proxy_arp": "0",
+			"net/ipv6/conf/br-clab/proxy_ndp": "0",
+		},
+	}
+	if d := cmp.Diff(want, s); d != "" {
+		t.Errorf("host routes state mismatch (-want +got):\n%s", d)
+	}
+	if got := read("net/ipv4/ip_forward"); got != "1" {
+		t.Errorf("ip_forward = %q, want 1", got)
+	}
+
+	s.restoreSysctls()
+
+	for name := range want.Sysctls {
+		if got := read(name); got != "0" {
+			t.Errorf("%s = %q after restore, want 0", name, got)
+		}
+	}
+	// the parameters not changed by the lab are left alone
+	for _, name := range []string{"net/ipv6/conf/all/forwarding", "net/ipv4/conf/br-other/proxy_arp"} {
+		if got := read(name); got != params[name] {
+			t.Errorf("%s = %q after restore, want %q", name, got, params[name])
+		}
+	}
+}
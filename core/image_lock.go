@@ -0,0 +1,125 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/charmbracelet/log"
+	clabcoreimagelock "github.com/srl-labs/containerlab/core/imagelock"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+// labImage is an image used by the lab nodes.
+type labImage struct {
+	runtime clabruntime.ContainerRuntime
+	nodes   []string
+}
+
+// labImages returns the images of the lab nodes, keyed by their reference in the topology.
+func (c *CLab) labImages(ctx context.Context) map[string]*labImage {
+	images := map[string]*labImage{}
+
+	for name, n := range c.Nodes {
+		for _, img := range n.GetImages(ctx) {
+			if img == "" || n.GetRuntime() == nil {
+				continue
+			}
+
+			li, ok := images[img]
+			if !ok {
+				li = &labImage{runtime: n.GetRuntime()}
+				images[img] = li
+			}
+			li.nodes = append(li.nodes, name)
+		}
+	}
+
+	for _, li := range images {
+		sort.Strings(li.nodes)
+	}
+
+	return images
+}
+
+// LockImages pulls the images of the lab nodes according to their pull policy
+// and returns the lock recording the identity of the local images.
+func (c *CLab) LockImages(ctx context.Context) (*clabcoreimagelock.Lock, error) {
+	if err := c.pullImagesForNodes(ctx); err != nil {
+		return nil, err
+	}
+
+	lock := clabcoreimagelock.New()
+
+	for ref, li := range c.labImages(ctx) {
+		info, err := li.runtime.InspectImage(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect image %s: %w", ref, err)
+		}
+		if info == nil {
+			return nil, fmt.Errorf("image %s is not present locally", ref)
+		}
+
+		lock.Add(ref, info)
+	}
+
+	return lock, nil
+}
+
+// VerifyImages verifies the local images of the lab nodes against the digests they are pinned to
+// and the lock, nil lock verifying the pinned digests only. The results are sorted by image.
+func (c *CLab) VerifyImages(ctx context.Context, lock *clabcoreimagelock.Lock) ([]*clabcoreimagelock.Result, error) {
+	images := c.labImages(ctx)
+
+	res := make([]*clabcoreimagelock.Result, 0, len(images))
+	for ref, li := range images {
+		info, err := li.runtime.InspectImage(ctx, ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to inspect image %s: %w", ref, err)
+		}
+
+		r := lock.Verify(ref, info)
+		r.Nodes = li.nodes
+		res = append(res, r)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Image < res[j].Image })
+
+	return res, nil
+}
+
+// verifyImageLock verifies the pulled images of the lab nodes against the lock file of the lab,
+// failing the deployment on an image not matching the lock.
+func (c *CLab) verifyImageLock(ctx context.Context) error {
+	path := c.TopoPaths.ImageLockFileAbsPath()
+
+	lock, err := clabcoreimagelock.Load(path)
+	if err != nil || lock == nil {
+		return err
+	}
+
+	log.Info("Verifying images against the lock file", "file", path)
+
+	res, err := c.VerifyImages(ctx, lock)
+	if err != nil {
+		return err
+	}
+
+	var failed int
+	for _, r := range res {
+		switch r.Status {
+		case clabcoreimagelock.StatusMismatch, clabcoreimagelock.StatusMissing:
+			log.Error("Image does not match the lock", "image", r.Image, "detail", r.Detail)
+			failed++
+		case clabcoreimagelock.StatusUnlocked:
+			log.Warn("Image is not locked", "image", r.Image)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d images do not match the lock file %s, "+
+			"run 'containerlab image lock' to update it", failed, len(res), path)
+	}
+
+	return nil
+}
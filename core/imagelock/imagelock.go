@@ -0,0 +1,223 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package imagelock pins the images of a lab to the exact image builds, recording their identity
+// in a lock file kept next to the topology file, and verifies the local images against it.
+package imagelock
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	clabutils "github.com/srl-labs/containerlab/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// Status of an image verification.
+type Status string
+
+const (
+	StatusOK       Status = "ok"
+	StatusMismatch Status = "mismatch"
+	StatusMissing  Status = "missing"
+	// StatusUnlocked is the status of an image neither pinned by digest nor recorded in the lock.
+	StatusUnlocked Status = "unlocked"
+)
+
+const lockFileHeader = "# generated by containerlab image lock, do not edit\n"
+
+// Image is the identity of a locked image.
+type Image struct {
+	// ID is the digest of the image configuration, identifying the images built locally as well,
+	// such as the vrnetlab images.
+	ID string `yaml:"id"`
+	// Digest is the manifest digest of the image in its registry, empty for an image not pulled
+	// from a registry. It is the digest to pin the image with in the topology.
+	Digest string `yaml:"digest,omitempty"`
+}
+
+// Lock is the lock of the lab images, keyed by the image references of the topology.
+type Lock struct {
+	Images map[string]*Image `yaml:"images"`
+}
+
+// Result is the result of an image verification.
+type Result struct {
+	Image  string   `json:"image"`
+	Nodes  []string `json:"nodes"`
+	Status Status   `json:"status"`
+	Detail string   `json:"detail,omitempty"`
+}
+
+// New returns an empty Lock.
+func New() *Lock {
+	return &Lock{Images: map[string]*Image{}}
+}
+
+// Load reads the lock file, nil is returned when the file doesn't exist.
+func Load(path string) (*Lock, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	l := New()
+	if err := yaml.UnmarshalStrict(b, l); err != nil {
+		return nil, fmt.Errorf("failed to parse the image lock file %s: %w", path, err)
+	}
+
+	if l.Images == nil {
+		l.Images = map[string]*Image{}
+	}
+
+	return l, nil
+}
+
+// Save writes the lock file.
+func (l *Lock) Save(path string) error {
+	b, err := yaml.Marshal(l)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append([]byte(lockFileHeader), b...), 0o644) // skipcq: GSC-G306
+}
+
+// Add records the identity of the local image of the reference.
+func (l *Lock) Add(ref string, info *clabruntime.ImageInfo) {
+	l.Images[ref] = &Image{
+		ID:     info.ID,
+		Digest: repoDigest(ref, info.RepoDigests),
+	}
+}
+
+// Verify verifies the local image of the reference against the digest it is pinned to and
+// the lock, nil info being an image not present locally and nil lock a lab without a lock file.
+func (l *Lock) Verify(ref string, info *clabruntime.ImageInfo) *Result {
+	r := &Result{Image: ref}
+
+	if info == nil {
+		r.Status = StatusMissing
+		r.Detail = "the image is not present locally"
+
+		return r
+	}
+
+	var details []string
+
+	if d := PinnedDigest(ref); d != "" {
+		if !hasDigest(info.RepoDigests, d) {
+			r.Status = StatusMismatch
+			r.Detail = fmt.Sprintf("the local image does not match the pinned digest %s", d)
+
+			return r
+		}
+
+		details = append(details, "matches the pinned digest")
+	}
+
+	if locked, ok := l.lockedImage(ref); ok {
+		if locked.ID != info.ID {
+			r.Status = StatusMismatch
+			r.Detail = fmt.Sprintf("the local image %s differs from the locked image %s",
+				shortID(info.ID), shortID(locked.ID))
+
+			return r
+		}
+
+		details = append(details, "matches the lock")
+	}
+
+	if len(details) == 0 {
+		r.Status = StatusUnlocked
+		r.Detail = "the image is neither pinned by digest nor locked"
+
+		return r
+	}
+
+	r.Status = StatusOK
+	r.Detail = strings.Join(details, ", ")
+
+	return r
+}
+
+func (l *Lock) lockedImage(ref string) (*Image, bool) {
+	if l == nil {
+		return nil, false
+	}
+
+	img, ok := l.Images[ref]
+
+	return img, ok
+}
+
+// PinnedDigest returns the digest the image reference is pinned to, as in repo@sha256:<digest>,
+// empty for a reference not pinned.
+func PinnedDigest(ref string) string {
+	_, d, _ := strings.Cut(ref, "@")
+	return d
+}
+
+// repository returns the canonical repository of the image reference, without its tag and digest.
+func repository(ref string) string {
+	name, _, _ := strings.Cut(ref, "@")
+	name = clabutils.GetCanonicalImageName(name)
+
+	// the tag follows the last colon after the last slash, a colon before it is a registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name = name[:i]
+	}
+
+	return name
+}
+
+// repoDigest returns the digest of the repo digest of the image reference repository,
+// the digest of the first repo digest if none matches.
+func repoDigest(ref string, repoDigests []string) string {
+	var first string
+
+	for _, rd := range repoDigests {
+		name, d, ok := strings.Cut(rd, "@")
+		if !ok {
+			continue
+		}
+
+		if repository(name) == repository(ref) {
+			return d
+		}
+
+		if first == "" {
+			first = d
+		}
+	}
+
+	return first
+}
+
+func hasDigest(repoDigests []string, digest string) bool {
+	for _, rd := range repoDigests {
+		if strings.HasSuffix(rd, "@"+digest) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// shortID returns the short form of the image ID, as docker shows it.
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 { //nolint: mnd
+		id = id[:12]
+	}
+
+	return id
+}
@@ -0,0 +1,144 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package imagelock
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+const (
+	srlID     = "sha256:9d3f0a7c4e5b1a2c3d4e5f60718293a4b5c6d7e8f90123456789abcdef012345"
+	srlDigest = "sha256:1f2e3d4c5b6a798897a6b5c4d3e2f1a0b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4"
+)
+
+func TestLockRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "srl02.clab.lock.yml")
+
+	l, err := Load(path)
+	if err != nil || l != nil {
+		t.Fatalf("Load() of a missing file = %v, %v, want nil, nil", l, err)
+	}
+
+	l = New()
+	l.Add("ghcr.io/nokia/srlinux:24.10", &clabruntime.ImageInfo{
+		ID: srlID,
+		RepoDigests: []string{
+			"registry.example.com/mirror/srlinux@sha256:0000",
+			"ghcr.io/nokia/srlinux@" + srlDigest,
+		},
+	})
+	l.Add("vrnetlab/nokia_sros:24.7.R1", &clabruntime.ImageInfo{ID: "sha256:abcd"})
+
+	if err := l.Save(path); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	got, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	want := &Lock{Images: map[string]*Image{
+		"ghcr.io/nokia/srlinux:24.10": {ID: srlID, Digest: srlDigest},
+		"vrnetlab/nokia_sros:24.7.R1": {ID: "sha256:abcd"},
+	}}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Load() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	srl := &clabruntime.ImageInfo{
+		ID:          srlID,
+		RepoDigests: []string{"ghcr.io/nokia/srlinux@" + srlDigest},
+	}
+
+	lock := &Lock{Images: map[string]*Image{
+		"ghcr.io/nokia/srlinux:24.10": {ID: srlID, Digest: srlDigest},
+		"vrnetlab/nokia_sros:24.7.R1": {ID: "sha256:abcdef0123456789"},
+	}}
+
+	tests := map[string]struct {
+		lock   *Lock
+		ref    string
+		info   *clabruntime.ImageInfo
+		status Status
+		detail string
+	}{
+		"locked": {
+			lock:   lock,
+			ref:    "ghcr.io/nokia/srlinux:24.10",
+			info:   srl,
+			status: StatusOK,
+			detail: "matches the lock",
+		},
+		"locked image rebuilt": {
+			lock:   lock,
+			ref:    "vrnetlab/nokia_sros:24.7.R1",
+			info:   &clabruntime.ImageInfo{ID: "sha256:fedcba9876543210"},
+			status: StatusMismatch,
+			detail: "the local image fedcba987654 differs from the locked image abcdef012345",
+		},
+		"pinned": {
+			ref:    "ghcr.io/nokia/srlinux@" + srlDigest,
+			info:   srl,
+			status: StatusOK,
+			detail: "matches the pinned digest",
+		},
+		"pinned digest mismatch": {
+			ref:    "ghcr.io/nokia/srlinux:24.10@sha256:0000",
+			info:   srl,
+			status: StatusMismatch,
+			detail: "the local image does not match the pinned digest sha256:0000",
+		},
+		"missing": {
+			lock:   lock,
+			ref:    "ghcr.io/nokia/srlinux:24.10",
+			status: StatusMissing,
+			detail: "the image is not present locally",
+		},
+		"unlocked": {
+			lock:   lock,
+			ref:    "alpine:3",
+			info:   &clabruntime.ImageInfo{ID: "sha256:1234"},
+			status: StatusUnlocked,
+			detail: "the image is neither pinned by digest nor locked",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.lock.Verify(tt.ref, tt.info)
+
+			want := &Result{Image: tt.ref, Status: tt.status, Detail: tt.detail}
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("Verify() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestRepository(t *testing.T) {
+	tests := map[string]string{
+		"alpine":                               "docker.io/library/alpine",
+		"alpine:3":                             "docker.io/library/alpine",
+		"ghcr.io/nokia/srlinux:24.10":          "ghcr.io/nokia/srlinux",
+		"ghcr.io/nokia/srlinux@sha256:1234":    "ghcr.io/nokia/srlinux",
+		"registry.local:5000/vrnetlab/vr-sros": "registry.local:5000/vrnetlab/vr-sros",
+	}
+
+	for ref, want := range tests {
+		t.Run(ref, func(t *testing.T) {
+			if got := repository(ref); got != want {
+				t.Errorf("repository(%q) = %q, want %q", ref, got, want)
+			}
+		})
+	}
+}
@@ -79,7 +79,24 @@ func (c *CLab) GenerateInventories() error {
 		return err
 	}
 
-	return nornirFile.Close()
+	err = nornirFile.Close()
+	if err != nil {
+		return err
+	}
+
+	// generate SuzieQ Inventory
+	suzieqInvFPath := c.TopoPaths.SuzieqInventoryFileAbsPath()
+	suzieqFile, err := os.Create(suzieqInvFPath)
+	if err != nil {
+		return err
+	}
+
+	err = c.generateSuzieqInventory(suzieqFile)
+	if err != nil {
+		return err
+	}
+
+	return suzieqFile.Close()
 }
 
 // generateAnsibleInventory generates and writes ansible inventory file to w.
@@ -275,3 +292,106 @@ func (c *CLab) generateNornirSimpleInventory(w io.Writer) error {
 
 	return err
 }
+
+// SuzieQ Inventory
+// https://suzieq.readthedocs.io/en/latest/inventory/
+
+//go:embed assets/inventory_suzieq.go.tpl
+var suzieqInvT string
+
+// SuzieqNodeLabel is the node label overriding the SuzieQ device type of the node.
+const SuzieqNodeLabel = "suzieq-devtype"
+
+// SuzieqInventoryNode represents a host in the SuzieQ inventory file.
+type SuzieqInventoryNode struct {
+	Address  string
+	Username string
+	Password string
+	DevType  string
+}
+
+// SuzieqInventory represents the data structure used to generate the SuzieQ inventory file.
+// The lab name is used as the name of the source, device and namespace sections.
+type SuzieqInventory struct {
+	Name  string
+	Nodes []*SuzieqInventoryNode
+}
+
+// generateSuzieqInventory generates and writes a SuzieQ native inventory file to w.
+// Only the nodes of kinds with default credentials are added to the inventory.
+func (c *CLab) generateSuzieqInventory(w io.Writer) error {
+	inv := SuzieqInventory{
+		Name: c.Config.Name,
+	}
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		cfg := c.Nodes[name].Config()
+
+		nodeRegEntry := c.Reg.Kind(cfg.Kind)
+		if nodeRegEntry == nil || nodeRegEntry.GetCredentials().GetUsername() == "" {
+			continue
+		}
+
+		n := &SuzieqInventoryNode{
+			Address:  cfg.MgmtIPv4Address,
+			Username: nodeRegEntry.GetCredentials().GetUsername(),
+			Password: nodeRegEntry.GetCredentials().GetPassword(),
+			DevType:  suzieqDevType(cfg.Kind),
+		}
+
+		if n.Address == "" {
+			n.Address = cfg.LongName
+		}
+
+		if v, ok := cfg.Labels[SuzieqNodeLabel]; ok {
+			n.DevType = v
+		}
+
+		inv.Nodes = append(inv.Nodes, n)
+	}
+
+	t, err := template.New("suzieq").Parse(suzieqInvT)
+	if err != nil {
+		return err
+	}
+
+	return t.Execute(w, inv)
+}
+
+// suzieqDevType returns the SuzieQ device type for the kind.
+// An empty device type makes SuzieQ discover the device type on its own.
+func suzieqDevType(kind string) string {
+	switch kind {
+	case "nokia_srlinux", "srl":
+		return "srlinux"
+	case "ceos", "arista_ceos", "arista_veos", "vr-veos", "vr-arista_veos":
+		return "eos"
+	case "cvx", "cumulus_cvx":
+		return "cumulus"
+	case "xrd", "cisco_xrd", "cisco_xrv9k", "vr-xrv9k", "vr-cisco_xrv9k", "cisco_xrv", "vr-xrv", "vr-cisco_xrv":
+		return "iosxr"
+	case "cisco_csr1000v", "vr-csr", "vr-cisco_csr1000v", "cisco_c8000v", "cisco_cat9kv":
+		return "iosxe"
+	case "cisco_iol":
+		return "ios"
+	case "cisco_n9kv", "vr-n9kv", "vr-cisco_n9kv":
+		return "nxos"
+	case "juniper_vmx", "vr-vmx", "vr-juniper_vmx":
+		return "junos-mx"
+	case "juniper_vqfx", "vr-vqfx", "vr-juniper_vqfx":
+		return "junos-qfx"
+	case "juniper_vsrx", "vr-vsrx", "vr-juniper_vsrx":
+		return "junos-es"
+	case "paloalto_panos", "vr-pan", "vr-paloalto_panos":
+		return "panos"
+	case "sonic-vs", "sonic-vm", "dell_sonic":
+		return "sonic"
+	}
+	return ""
+}
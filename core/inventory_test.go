@@ -247,3 +247,53 @@ node3:
 		})
 	}
 }
+
+func TestGenerateSuzieqInventory(t *testing.T) {
+	tests := map[string]struct {
+		got  string
+		want string
+	}{
+		"case1": {
+			got: "test_data/topo8_ansible_groups.yml",
+			want: `sources:
+- name: topo8_ansible_groups
+  hosts:
+  - url: ssh://admin@172.100.100.11:22 devtype=srlinux password=plain:NokiaSrl1!
+  - url: ssh://admin@172.100.100.12:22 devtype=srlinux password=plain:NokiaSrl1!
+  - url: ssh://admin@172.100.100.13:22 devtype=srlinux password=plain:NokiaSrl1!
+
+devices:
+- name: topo8_ansible_groups
+  transport: ssh
+  ignore-known-hosts: true
+
+namespaces:
+- name: topo8_ansible_groups
+  source: topo8_ansible_groups
+  device: topo8_ansible_groups
+`,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			opts := []ClabOption{
+				WithTopoPath(tc.got, ""),
+			}
+			c, err := NewContainerLab(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var s strings.Builder
+			err = c.generateSuzieqInventory(&s)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(tc.want, s.String()); diff != "" {
+				t.Errorf("failed at '%s', diff: (-want +got)\n%s", name, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,357 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/containernetworking/plugins/pkg/ns"
+	clabgit "github.com/srl-labs/containerlab/git"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clablinks "github.com/srl-labs/containerlab/links"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	"github.com/vishvananda/netlink"
+)
+
+// LabState is the state of a deployed lab recorded in the lab directory,
+// compared with the topology file on an incremental deploy.
+type LabState struct {
+	// Nodes are the fingerprints of the node definitions by node name.
+	Nodes map[string]string `json:"nodes"`
+	Links []*LinkState      `json:"links"`
+	// Git is the git revision of the topology file, when it is in a git repository.
+	Git *clabgit.Revision `json:"git,omitempty"`
+}
+
+// LinkState is a link of a deployed lab.
+type LinkState struct {
+	Type string `json:"type"`
+	// Endpoints are the sorted endpoints of the link as <node>:<interface>.
+	Endpoints []string `json:"endpoints"`
+}
+
+// String returns the link as its type and endpoints.
+func (l *LinkState) String() string {
+	return l.Type + " " + strings.Join(l.Endpoints, " <-> ")
+}
+
+// TopologyDiff is the difference between the recorded state of a lab and its topology file.
+type TopologyDiff struct {
+	AddedNodes   []string
+	RemovedNodes []string
+	ChangedNodes []string
+	AddedLinks   []*LinkState
+	RemovedLinks []*LinkState
+}
+
+// Empty returns true if the topology has not changed.
+func (d *TopologyDiff) Empty() bool {
+	return len(d.AddedNodes) == 0 && len(d.RemovedNodes) == 0 && len(d.ChangedNodes) == 0 &&
+		len(d.AddedLinks) == 0 && len(d.RemovedLinks) == 0
+}
+
+// Deployed returns true if the node is created by the incremental deploy, being added or changed.
+func (d *TopologyDiff) Deployed(node string) bool {
+	return slices.Contains(d.AddedNodes, node) || slices.Contains(d.ChangedNodes, node)
+}
+
+// DiffLabState returns the nodes and links added, removed and changed from the recorded to the current state.
+func DiffLabState(recorded, current *LabState) *TopologyDiff {
+	d := &TopologyDiff{}
+
+	for n, fp := range current.Nodes {
+		rfp, ok := recorded.Nodes[n]
+		switch {
+		case !ok:
+			d.AddedNodes = append(d.AddedNodes, n)
+		case rfp != fp:
+			d.ChangedNodes = append(d.ChangedNodes, n)
+		}
+	}
+	for n := range recorded.Nodes {
+		if _, ok := current.Nodes[n]; !ok {
+			d.RemovedNodes = append(d.RemovedNodes, n)
+		}
+	}
+
+	d.AddedLinks = linksDifference(current.Links, recorded.Links)
+	d.RemovedLinks = linksDifference(recorded.Links, current.Links)
+
+	sort.Strings(d.AddedNodes)
+	sort.Strings(d.RemovedNodes)
+	sort.Strings(d.ChangedNodes)
+
+	return d
+}
+
+// linksDifference returns the links of a missing from b.
+func linksDifference(a, b []*LinkState) []*LinkState {
+	keys := make(map[string]struct{}, len(b))
+	for _, l := range b {
+		keys[l.String()] = struct{}{}
+	}
+
+	var res []*LinkState
+	for _, l := range a {
+		if _, ok := keys[l.String()]; !ok {
+			res = append(res, l)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].String() < res[j].String() })
+
+	return res
+}
+
+// loadLabState reads the recorded state of the lab, nil if the lab has no recorded state.
+func loadLabState(path string) (*LabState, error) {
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &LabState{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("failed to parse the lab state %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// writeLabState records the state of the lab in the lab directory.
+func (c *CLab) writeLabState(s *LabState) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(c.TopoPaths.LabStateFileAbsPath(), b, 0o644) // skipcq: GSC-G306
+}
+
+// labState returns the state of the lab from its topology file, the startup-configs rendered
+// from the config templates being part of the definition of the nodes.
+func (c *CLab) labState(startupConfigs map[string]string) (*LabState, error) {
+	s := &LabState{Nodes: make(map[string]string, len(c.Nodes))}
+
+	for name, n := range c.Nodes {
+		fp, err := c.nodeFingerprint(name, n, startupConfigs[name])
+		if err != nil {
+			return nil, fmt.Errorf("failed to fingerprint node %s: %w", name, err)
+		}
+		s.Nodes[name] = fp
+	}
+
+	for _, l := range c.Links {
+		s.Links = append(s.Links, newLinkState(l))
+	}
+
+	sort.Slice(s.Links, func(i, j int) bool { return s.Links[i].String() < s.Links[j].String() })
+
+	s.Git = clabgit.LabRevision(c.TopoPaths.TopologyFilenameAbsPath())
+	if s.Git != nil {
+		log.Debug("Lab git revision", "commit", s.Git, "branch", s.Git.Branch)
+	}
+
+	return s, nil
+}
+
+// newLinkState returns the state of the link.
+func newLinkState(l clablinks.Link) *LinkState {
+	ls := &LinkState{Type: string(l.GetType())}
+	for _, ep := range l.GetEndpoints() {
+		ls.Endpoints = append(ls.Endpoints, ep.GetNode().GetShortName()+":"+ep.GetIfaceName())
+	}
+	sort.Strings(ls.Endpoints)
+
+	return ls
+}
+
+// nodeFingerprint returns the hash of the definition of the node, with the definitions of its kind,
+// group and the defaults it inherits and the content of its startup-config.
+func (c *CLab) nodeFingerprint(name string, n clabnodes.Node, startupConfig string) (string, error) {
+	t := c.Config.Topology
+
+	if startupConfig == "" && n.Config().StartupConfig != "" {
+		b, err := os.ReadFile(n.Config().StartupConfig)
+		if err == nil {
+			startupConfig = string(b)
+		}
+	}
+
+	def := struct {
+		Node          *clabtypes.NodeDefinition `json:"node"`
+		Kind          *clabtypes.NodeDefinition `json:"kind,omitempty"`
+		Group         *clabtypes.NodeDefinition `json:"group,omitempty"`
+		Defaults      *clabtypes.NodeDefinition `json:"defaults,omitempty"`
+		StartupConfig string                    `json:"startup-config,omitempty"`
+	}{
+		Node:          t.Nodes[name],
+		Kind:          t.Kinds[n.Config().Kind],
+		Group:         t.Groups[t.GetNodeGroup(name)],
+		Defaults:      t.Defaults,
+		StartupConfig: startupConfig,
+	}
+
+	b, err := json.Marshal(def)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(b)
+
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// prepareIncrementalDeploy compares the topology with the recorded state of the lab and removes
+// the containers of the removed and changed nodes and the removed links between the kept nodes.
+// The recorded nodes without a container are deployed again.
+// A nil diff is returned when the lab has no recorded state.
+func (c *CLab) prepareIncrementalDeploy(ctx context.Context, current *LabState) (*TopologyDiff, error) {
+	recorded, err := loadLabState(c.TopoPaths.LabStateFileAbsPath())
+	if err != nil || recorded == nil {
+		return nil, err
+	}
+
+	containers, err := c.ListContainers(ctx, WithListLabName(c.Config.Name))
+	if err != nil {
+		return nil, err
+	}
+
+	running := make(map[string]string, len(containers))
+	for _, cnt := range containers {
+		running[cnt.Labels[clablabels.NodeName]] = cnt.Names[0]
+	}
+
+	for n := range recorded.Nodes {
+		if _, ok := running[n]; !ok {
+			log.Debugf("node %s of the recorded lab state has no container", n)
+			delete(recorded.Nodes, n)
+		}
+	}
+
+	d := DiffLabState(recorded, current)
+	log.Info("Deploying the topology changes",
+		"added", d.AddedNodes, "changed", d.ChangedNodes, "removed", d.RemovedNodes,
+		"added-links", len(d.AddedLinks), "removed-links", len(d.RemovedLinks))
+
+	for _, n := range d.RemovedNodes {
+		log.Info("Removing node", "node", n)
+		if err := c.globalRuntime().DeleteContainer(ctx, running[n]); err != nil {
+			return nil, fmt.Errorf("failed to remove node %s: %w", n, err)
+		}
+	}
+
+	for _, n := range d.ChangedNodes {
+		log.Info("Removing changed node", "node", n)
+		if err := c.Nodes[n].Delete(ctx); err != nil {
+			return nil, fmt.Errorf("failed to remove node %s: %w", n, err)
+		}
+	}
+
+	for _, l := range d.RemovedLinks {
+		if err := c.removeStaleLink(ctx, l, d); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// removeStaleLink removes a link of the recorded state from a kept node. Deleting one end of
+// the veth pair deletes the other one, the links of the removed nodes went with their containers.
+func (c *CLab) removeStaleLink(ctx context.Context, l *LinkState, d *TopologyDiff) error {
+	for _, ep := range l.Endpoints {
+		name, iface, _ := strings.Cut(ep, ":")
+		n, ok := c.Nodes[name]
+		if !ok || d.Deployed(name) {
+			continue
+		}
+
+		log.Info("Removing link", "link", l)
+
+		return n.ExecFunction(ctx, func(_ ns.NetNS) error {
+			link, err := netlink.LinkByName(iface)
+			if err != nil {
+				// the interface is already gone
+				return nil //nolint: nilerr
+			}
+			return netlink.LinkDel(link)
+		})
+	}
+
+	return nil
+}
+
+// finishIncrementalDeploy updates the kept nodes with their runtime info and deploys their endpoints
+// on the links created by the incremental deploy, as the kept nodes are not scheduled to deploy
+// their endpoints themselves.
+func (c *CLab) finishIncrementalDeploy(ctx context.Context, d *TopologyDiff) {
+	for name, n := range c.Nodes {
+		if d.Deployed(name) {
+			continue
+		}
+		if err := n.UpdateConfigWithRuntimeInfo(ctx); err != nil {
+			log.Errorf("failed to update node runtime information for node %s: %v", name, err)
+		}
+	}
+
+	added := make(map[string]struct{}, len(d.AddedLinks))
+	for _, l := range d.AddedLinks {
+		added[l.String()] = struct{}{}
+	}
+
+	for _, l := range c.Links {
+		_, affected := added[newLinkState(l).String()]
+		var kept []clablinks.Endpoint
+
+		for _, ep := range l.GetEndpoints() {
+			name := ep.GetNode().GetShortName()
+			switch _, isNode := c.Nodes[name]; {
+			case d.Deployed(name):
+				affected = true
+			case isNode:
+				kept = append(kept, ep)
+			}
+		}
+
+		if !affected {
+			continue
+		}
+
+		for _, ep := range kept {
+			if err := ep.Deploy(ctx); err != nil {
+				log.Errorf("failed deploying endpoint %s: %v", ep, err)
+			}
+		}
+	}
+}
+
+// scheduledNodes returns the nodes created by the deployment, all nodes of the lab unless
+// the deployment is incremental.
+func (c *CLab) scheduledNodes() map[string]clabnodes.Node {
+	if c.deployDiff == nil {
+		return c.Nodes
+	}
+
+	res := make(map[string]clabnodes.Node)
+	for name, n := range c.Nodes {
+		if c.deployDiff.Deployed(name) {
+			res[name] = n
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,45 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestDiffLabState(t *testing.T) {
+	recorded := &LabState{
+		Nodes: map[string]string{"srl1": "a", "srl2": "b", "srl3": "c"},
+		Links: []*LinkState{
+			{Type: "veth", Endpoints: []string{"srl1:e1-1", "srl2:e1-1"}},
+			{Type: "veth", Endpoints: []string{"srl1:e1-2", "srl3:e1-1"}},
+		},
+	}
+	current := &LabState{
+		Nodes: map[string]string{"srl1": "a", "srl2": "changed", "client": "d"},
+		Links: []*LinkState{
+			{Type: "veth", Endpoints: []string{"srl1:e1-1", "srl2:e1-1"}},
+			{Type: "veth", Endpoints: []string{"client:eth1", "srl1:e1-2"}},
+		},
+	}
+
+	want := &TopologyDiff{
+		AddedNodes:   []string{"client"},
+		RemovedNodes: []string{"srl3"},
+		ChangedNodes: []string{"srl2"},
+		AddedLinks:   []*LinkState{{Type: "veth", Endpoints: []string{"client:eth1", "srl1:e1-2"}}},
+		RemovedLinks: []*LinkState{{Type: "veth", Endpoints: []string{"srl1:e1-2", "srl3:e1-1"}}},
+	}
+
+	d := DiffLabState(recorded, current)
+	if diff := cmp.Diff(want, d); diff != "" {
+		t.Errorf("DiffLabState() mismatch (-want +got):\n%s", diff)
+	}
+
+	if !d.Deployed("srl2") || !d.Deployed("client") || d.Deployed("srl1") {
+		t.Errorf("Deployed() does not match the added and changed nodes")
+	}
+
+	if !DiffLabState(current, current).Empty() {
+		t.Errorf("DiffLabState() of the same state is not empty")
+	}
+}
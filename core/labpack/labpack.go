@@ -0,0 +1,334 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package labpack packs a lab, its topology and the files the topology references, into a single
+// gzipped tarball, pushed to and pulled from the container registries as an OCI artifact.
+package labpack
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/empty"
+	"github.com/google/go-containerregistry/pkg/v1/mutate"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/google/go-containerregistry/pkg/v1/static"
+	"github.com/google/go-containerregistry/pkg/v1/types"
+)
+
+const (
+	// OCIScheme prefixes the references of the lab OCI artifacts, e.g. oci://ghcr.io/org/lab:v1.
+	OCIScheme = "oci://"
+	// LayerMediaType is the media type of the layer of a lab OCI artifact holding the lab tarball.
+	LayerMediaType = "application/vnd.containerlab.lab.layer.v1.tar+gzip"
+	// ConfigMediaType is the media type of the config of a lab OCI artifact.
+	ConfigMediaType = "application/vnd.containerlab.lab.config.v1+json"
+
+	// manifestFile is the file of the tarball describing the packed lab.
+	manifestFile = ".clab-pack.json"
+)
+
+// File is a file of a packed lab.
+type File struct {
+	// Path is the path of the file in the pack, relative to the topology file.
+	Path string `json:"path"`
+	// Source is the path of the file on the host, set when packing.
+	Source string `json:"-"`
+}
+
+// Manifest describes a packed lab.
+type Manifest struct {
+	Name string `json:"name"`
+	// Topology is the path of the topology file in the pack.
+	Topology string  `json:"topology"`
+	Files    []*File `json:"files"`
+}
+
+// Write writes the files of the manifest and the manifest to w as a gzipped tarball,
+// the directories being packed with their content.
+func Write(w io.Writer, m *Manifest) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+
+	for _, f := range m.Files {
+		if err := addPath(tw, f.Source, f.Path); err != nil {
+			return err
+		}
+	}
+
+	b, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := tw.WriteHeader(&tar.Header{
+		Name: manifestFile,
+		Mode: 0o644,
+		Size: int64(len(b)),
+	}); err != nil {
+		return err
+	}
+
+	if _, err := tw.Write(b); err != nil {
+		return err
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+
+	return gw.Close()
+}
+
+// addPath adds the file or the directory tree at src to the tarball under dst.
+func addPath(tw *tar.Writer, src, dst string) error {
+	return filepath.Walk(src, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() && !info.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		hdr.Name = filepath.ToSlash(filepath.Join(dst, rel))
+		// the files are owned by the user unpacking the lab
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+
+		if info.IsDir() {
+			return nil
+		}
+
+		f, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+
+		return err
+	})
+}
+
+// Extract extracts the gzipped tarball of a packed lab to the directory and returns its manifest.
+func Extract(r io.Reader, dir string) (*Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("the lab pack is not a gzipped tarball: %w", err)
+	}
+	defer gr.Close()
+
+	var m *Manifest
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := extractPath(dir, hdr.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(p, 0o755); err != nil { // skipcq: GSC-G301
+				return nil, err
+			}
+
+		case tar.TypeReg:
+			if hdr.Name == manifestFile {
+				if m, err = decodeManifest(tr); err != nil {
+					return nil, err
+				}
+
+				continue
+			}
+
+			if err := extractFile(tr, p, hdr.FileInfo().Mode().Perm()); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if m == nil {
+		return nil, fmt.Errorf("the lab pack has no %s manifest", manifestFile)
+	}
+
+	return m, nil
+}
+
+// ReadManifest returns the manifest of the gzipped tarball of a packed lab.
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("the lab pack is not a gzipped tarball: %w", err)
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil, fmt.Errorf("the lab pack has no %s manifest", manifestFile)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if hdr.Name == manifestFile {
+			return decodeManifest(tr)
+		}
+	}
+}
+
+func decodeManifest(r io.Reader) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.NewDecoder(r).Decode(m); err != nil {
+		return nil, fmt.Errorf("failed to parse the lab pack manifest: %w", err)
+	}
+
+	return m, nil
+}
+
+// extractPath returns the path in the directory of a tarball entry,
+// rejecting the entries escaping the directory.
+func extractPath(dir, entry string) (string, error) {
+	clean := path.Clean(entry)
+	if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("the lab pack entry %q is outside of the lab directory", entry)
+	}
+
+	return filepath.Join(dir, filepath.FromSlash(clean)), nil
+}
+
+func extractFile(r io.Reader, p string, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	f, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+
+	return err
+}
+
+// parseReference parses the reference of a lab OCI artifact, with or without the oci:// scheme.
+func parseReference(ref string) (name.Reference, error) {
+	return name.ParseReference(strings.TrimPrefix(ref, OCIScheme))
+}
+
+// Push pushes the lab tarball as an OCI artifact to the registry with the credentials
+// of the docker config and returns the digest of the artifact.
+func Push(ctx context.Context, ref string, tarball []byte, m *Manifest) (string, error) {
+	r, err := parseReference(ref)
+	if err != nil {
+		return "", err
+	}
+
+	img, err := mutate.Append(empty.Image, mutate.Addendum{
+		Layer: static.NewLayer(tarball, LayerMediaType),
+		Annotations: map[string]string{
+			"org.opencontainers.image.title": m.Name + ".tar.gz",
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	img = mutate.MediaType(img, types.OCIManifestSchema1)
+	img = mutate.ConfigMediaType(img, ConfigMediaType)
+
+	if err := remote.Write(r, img, remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain)); err != nil {
+		return "", err
+	}
+
+	d, err := img.Digest()
+	if err != nil {
+		return "", err
+	}
+
+	return d.String(), nil
+}
+
+// Pull pulls the lab OCI artifact from the registry and returns the lab tarball.
+func Pull(ctx context.Context, ref string) ([]byte, error) {
+	r, err := parseReference(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := remote.Image(r, remote.WithContext(ctx),
+		remote.WithAuthFromKeychain(authn.DefaultKeychain))
+	if err != nil {
+		return nil, err
+	}
+
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range layers {
+		mt, err := l.MediaType()
+		if err != nil {
+			return nil, err
+		}
+		if mt != LayerMediaType {
+			continue
+		}
+
+		rc, err := l.Compressed()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+
+		var buf bytes.Buffer
+		if _, err := io.Copy(&buf, rc); err != nil {
+			return nil, err
+		}
+
+		return buf.Bytes(), nil
+	}
+
+	return nil, fmt.Errorf("%s is not a containerlab lab artifact", ref)
+}
@@ -0,0 +1,121 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package labpack
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestWriteExtract(t *testing.T) {
+	src := t.TempDir()
+	files := map[string]string{
+		"srl02.clab.yml":           "name: srl02\n",
+		"configs/srl1.cfg":         "set / system name host-name srl1\n",
+		"templates/srl/bgp.gotmpl": "{{ .bgp }}\n",
+	}
+	for p, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(src, p)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(src, p), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	m := &Manifest{
+		Name:     "srl02",
+		Topology: "srl02.clab.yml",
+		Files: []*File{
+			{Path: "configs/srl1.cfg", Source: filepath.Join(src, "configs/srl1.cfg")},
+			{Path: "srl02.clab.yml", Source: filepath.Join(src, "srl02.clab.yml")},
+			{Path: "templates", Source: filepath.Join(src, "templates")},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, m); err != nil {
+		t.Fatalf("Write() failed: %v", err)
+	}
+
+	want := &Manifest{
+		Name:     "srl02",
+		Topology: "srl02.clab.yml",
+		Files: []*File{
+			{Path: "configs/srl1.cfg"},
+			{Path: "srl02.clab.yml"},
+			{Path: "templates"},
+		},
+	}
+
+	got, err := ReadManifest(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("ReadManifest() failed: %v", err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("ReadManifest() mismatch (-want +got):\n%s", d)
+	}
+
+	dst := filepath.Join(t.TempDir(), "srl02")
+
+	got, err = Extract(bytes.NewReader(buf.Bytes()), dst)
+	if err != nil {
+		t.Fatalf("Extract() failed: %v", err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("Extract() manifest mismatch (-want +got):\n%s", d)
+	}
+
+	for p, content := range files {
+		b, err := os.ReadFile(filepath.Join(dst, p))
+		if err != nil {
+			t.Errorf("extracted file %s: %v", p, err)
+			continue
+		}
+		if string(b) != content {
+			t.Errorf("extracted file %s = %q, want %q", p, b, content)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(dst, manifestFile)); err == nil {
+		t.Errorf("the manifest was extracted")
+	}
+}
+
+func TestExtractRejectsEscapingEntries(t *testing.T) {
+	tests := map[string]string{
+		"parent dir":    "../evil.sh",
+		"nested parent": "configs/../../evil.sh",
+		"absolute":      "/etc/evil.sh",
+	}
+
+	for name, entry := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gw := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gw)
+
+			content := []byte("echo pwned\n")
+			if err := tw.WriteHeader(&tar.Header{Name: entry, Mode: 0o755, Size: int64(len(content))}); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tw.Write(content); err != nil {
+				t.Fatal(err)
+			}
+			tw.Close()
+			gw.Close()
+
+			if _, err := Extract(&buf, t.TempDir()); err == nil {
+				t.Errorf("Extract() of entry %q did not fail", entry)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Package netsvc generates the config of the NTP and DNS service container started for a lab
+// and keeps the address of the running container for the node config templates.
+package netsvc
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	claberrors "github.com/srl-labs/containerlab/errors"
+)
+
+// Services of the container.
+const (
+	ServiceNTP = "ntp"
+	ServiceDNS = "dns"
+)
+
+// Services are the supported services.
+var Services = []string{ServiceNTP, ServiceDNS} //nolint:gochecknoglobals
+
+// Image is the default image of the container, the services missing from the image
+// are installed with apk when the container starts.
+const Image = "alpine:3"
+
+// Config files of the container, mounted in the ConfigDir of the container.
+const (
+	ChronyFile     = "chrony.conf"
+	DnsmasqFile    = "dnsmasq.conf"
+	HostsFile      = "hosts"
+	EntrypointFile = "entrypoint.sh"
+)
+
+// ConfigDir is the directory of the config files in the container.
+const ConfigDir = "/etc/clab"
+
+// Host is a host name resolved by the DNS service.
+type Host struct {
+	Name      string   `json:"name"`
+	Addresses []string `json:"addresses"`
+}
+
+// Server is an NTP and DNS service container started for the lab.
+type Server struct {
+	Container string `json:"container"`
+	// Address is the management IPv4 address of the container
+	Address string `json:"address"`
+	NTP     bool   `json:"ntp"`
+	DNS     bool   `json:"dns"`
+	// Domain is the domain of the lab nodes resolved by the DNS service
+	Domain string `json:"domain"`
+	// Upstream are the DNS servers the other names are forwarded to
+	Upstream []string `json:"upstream,omitempty"`
+	// Clients are the prefixes of the nodes allowed to query the NTP service
+	Clients []string `json:"clients"`
+	Hosts   []*Host  `json:"hosts,omitempty"`
+}
+
+// NewServer returns the server of the services of the lab, its nodes being in the <lab>.clab domain
+// when the domain is empty.
+func NewServer(lab, domain string, services []string) (*Server, error) {
+	s := &Server{Domain: domain}
+	if s.Domain == "" {
+		s.Domain = lab + ".clab"
+	}
+
+	for _, svc := range services {
+		switch svc {
+		case ServiceNTP:
+			s.NTP = true
+		case ServiceDNS:
+			s.DNS = true
+		default:
+			return nil, fmt.Errorf("%w: unknown service %q, supported services are %s",
+				claberrors.ErrIncorrectInput, svc, strings.Join(Services, ", "))
+		}
+	}
+
+	if !s.NTP && !s.DNS {
+		return nil, fmt.Errorf("%w: no service set, supported services are %s",
+			claberrors.ErrIncorrectInput, strings.Join(Services, ", "))
+	}
+
+	return s, nil
+}
+
+// ConfigFiles returns the config files of the container by file name.
+func (s *Server) ConfigFiles() map[string][]byte {
+	files := map[string][]byte{}
+
+	var entry strings.Builder
+	entry.WriteString("#!/bin/sh\n")
+
+	var pkgs, cmds []string
+
+	if s.NTP {
+		var b strings.Builder
+		// the container can't set the clock, chrony serves the clock of the host
+		b.WriteString("local stratum 8\n")
+		for _, c := range s.Clients {
+			fmt.Fprintf(&b, "allow %s\n", c)
+		}
+		files[ChronyFile] = []byte(b.String())
+
+		pkgs = append(pkgs, "chrony")
+		cmds = append(cmds, "chronyd -d -x -f "+ConfigDir+"/"+ChronyFile)
+	}
+
+	if s.DNS {
+		var b strings.Builder
+		b.WriteString("no-resolv\nno-hosts\nexpand-hosts\n")
+		fmt.Fprintf(&b, "addn-hosts=%s/%s\n", ConfigDir, HostsFile)
+		fmt.Fprintf(&b, "domain=%s\nlocal=/%s/\n", s.Domain, s.Domain)
+		for _, u := range s.Upstream {
+			fmt.Fprintf(&b, "server=%s\n", u)
+		}
+		files[DnsmasqFile] = []byte(b.String())
+
+		b.Reset()
+		for _, h := range s.Hosts {
+			for _, a := range h.Addresses {
+				fmt.Fprintf(&b, "%s %s\n", a, h.Name)
+			}
+		}
+		files[HostsFile] = []byte(b.String())
+
+		pkgs = append(pkgs, "dnsmasq")
+		cmds = append(cmds, "dnsmasq -k -C "+ConfigDir+"/"+DnsmasqFile)
+	}
+
+	checks := make([]string, 0, len(pkgs))
+	for _, c := range cmds {
+		checks = append(checks, "command -v "+strings.Fields(c)[0]+" >/dev/null")
+	}
+	fmt.Fprintf(&entry, "%s || apk add --no-cache %s\n", strings.Join(checks, " && "), strings.Join(pkgs, " "))
+
+	// the last service runs in the foreground
+	for _, c := range cmds[:len(cmds)-1] {
+		fmt.Fprintf(&entry, "%s &\n", c)
+	}
+	fmt.Fprintf(&entry, "exec %s\n", cmds[len(cmds)-1])
+
+	files[EntrypointFile] = []byte(entry.String())
+
+	return files
+}
+
+// Vars returns the template variables of the server, the address of a service is empty
+// when the service is not running.
+func (s *Server) Vars() map[string]interface{} {
+	vars := map[string]interface{}{
+		"ntp":    "",
+		"dns":    "",
+		"domain": s.Domain,
+	}
+	if s.NTP {
+		vars["ntp"] = s.Address
+	}
+	if s.DNS {
+		vars["dns"] = s.Address
+	}
+
+	return vars
+}
+
+// AddHost adds the addresses of the host resolved by the DNS service, the empty addresses are skipped.
+func (s *Server) AddHost(name string, addrs ...string) {
+	h := &Host{Name: name}
+	for _, a := range addrs {
+		if a != "" && !slices.Contains(h.Addresses, a) {
+			h.Addresses = append(h.Addresses, a)
+		}
+	}
+
+	if len(h.Addresses) > 0 {
+		s.Hosts = append(s.Hosts, h)
+	}
+}
+
+// LoadServer returns the server stored in the file, nil when the lab has no service container.
+func LoadServer(path string) (*Server, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{}
+	if err := json.Unmarshal(b, s); err != nil {
+		return nil, fmt.Errorf("invalid NTP and DNS service file %s: %w", path, err)
+	}
+
+	return s, nil
+}
+
+// Save stores the server in the file.
+func (s *Server) Save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, b, 0o644) // skipcq: GSC-G306
+}
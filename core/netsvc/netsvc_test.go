@@ -0,0 +1,112 @@
+package netsvc
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	claberrors "github.com/srl-labs/containerlab/errors"
+)
+
+func TestConfigFiles(t *testing.T) {
+	tests := map[string]struct {
+		services []string
+		want     map[string]string
+	}{
+		"ntp and dns": {
+			services: []string{ServiceNTP, ServiceDNS},
+			want: map[string]string{
+				ChronyFile: "local stratum 8\nallow 172.20.20.0/24\nallow 3fff:172:20:20::/64\n",
+				DnsmasqFile: "no-resolv\nno-hosts\nexpand-hosts\naddn-hosts=/etc/clab/hosts\n" +
+					"domain=dc.clab\nlocal=/dc.clab/\nserver=192.0.2.53\n",
+				HostsFile: "172.20.20.2 srl1\n3fff:172:20:20::2 srl1\n172.20.20.3 srl2\n",
+				EntrypointFile: "#!/bin/sh\n" +
+					"command -v chronyd >/dev/null && command -v dnsmasq >/dev/null || apk add --no-cache chrony dnsmasq\n" +
+					"chronyd -d -x -f /etc/clab/chrony.conf &\n" +
+					"exec dnsmasq -k -C /etc/clab/dnsmasq.conf\n",
+			},
+		},
+		"ntp": {
+			services: []string{ServiceNTP},
+			want: map[string]string{
+				ChronyFile: "local stratum 8\nallow 172.20.20.0/24\nallow 3fff:172:20:20::/64\n",
+				EntrypointFile: "#!/bin/sh\n" +
+					"command -v chronyd >/dev/null || apk add --no-cache chrony\n" +
+					"exec chronyd -d -x -f /etc/clab/chrony.conf\n",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			s, err := NewServer("dc", "", tt.services)
+			if err != nil {
+				t.Fatal(err)
+			}
+			s.Clients = []string{"172.20.20.0/24", "3fff:172:20:20::/64"}
+			s.Upstream = []string{"192.0.2.53"}
+			s.AddHost("srl1", "172.20.20.2", "3fff:172:20:20::2")
+			s.AddHost("srl2", "172.20.20.3", "")
+			s.AddHost("srl3", "")
+
+			got := map[string]string{}
+			for f, b := range s.ConfigFiles() {
+				got[f] = string(b)
+			}
+
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("config files mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestNewServer(t *testing.T) {
+	s, err := NewServer("dc", "lab.example.com", []string{ServiceDNS})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.NTP || !s.DNS || s.Domain != "lab.example.com" {
+		t.Errorf("unexpected server %+v", s)
+	}
+
+	for _, svc := range [][]string{{"dhcp"}, nil} {
+		if _, err := NewServer("dc", "", svc); !errors.Is(err, claberrors.ErrIncorrectInput) {
+			t.Errorf("expected an incorrect input error for %v, got %v", svc, err)
+		}
+	}
+}
+
+func TestLoadServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "netsvc.json")
+
+	s, err := LoadServer(path)
+	if err != nil || s != nil {
+		t.Fatalf("expected no server without a server file, got %v, %v", s, err)
+	}
+
+	want := &Server{
+		Container: "clab-dc-netsvc",
+		Address:   "172.20.20.9",
+		NTP:       true,
+		Domain:    "dc.clab",
+		Clients:   []string{"172.20.20.0/24"},
+	}
+	if err := want.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := LoadServer(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("server mismatch (-want +got):\n%s", d)
+	}
+
+	wantVars := map[string]interface{}{"ntp": "172.20.20.9", "dns": "", "domain": "dc.clab"}
+	if d := cmp.Diff(wantVars, got.Vars()); d != "" {
+		t.Errorf("vars mismatch (-want +got):\n%s", d)
+	}
+}
@@ -5,10 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"os/user"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
 	clabcoredependency_manager "github.com/srl-labs/containerlab/core/dependency_manager"
+	clabcoreevents "github.com/srl-labs/containerlab/core/events"
+	clabcorelabpack "github.com/srl-labs/containerlab/core/labpack"
 	clablabels "github.com/srl-labs/containerlab/labels"
 	clabruntime "github.com/srl-labs/containerlab/runtime"
 	clabtypes "github.com/srl-labs/containerlab/types"
@@ -18,7 +21,7 @@ import (
 type ClabOption func(c *CLab) error
 
 // WithLabOwner sets the owner label for all nodes in the lab.
-// Only users in the clab_admins group can set a custom owner.
+// Only root and the users in the clab_admins group can set a custom owner.
 func WithLabOwner(owner string) ClabOption {
 	return func(c *CLab) error {
 		currentUser, err := user.Current()
@@ -27,7 +30,9 @@ func WithLabOwner(owner string) ClabOption {
 			return nil
 		}
 
-		if isClabAdmin, err := clabutils.UserInUnixGroup(currentUser.Username,
+		if currentUser.Uid == "0" {
+			c.customOwner = owner
+		} else if isClabAdmin, err := clabutils.UserInUnixGroup(currentUser.Username,
 			"clab_admins"); err == nil && isClabAdmin {
 			c.customOwner = owner
 		} else if owner != "" {
@@ -47,6 +52,28 @@ func WithTimeout(dur time.Duration) ClabOption {
 	}
 }
 
+// WithTTL sets the time to live of the lab, the lab expiring at the end of the TTL
+// is destroyed by the containerlab daemon.
+func WithTTL(ttl time.Duration) ClabOption {
+	return func(c *CLab) error {
+		if ttl < 0 {
+			return errors.New("negative TTLs are not allowed")
+		}
+		if ttl > 0 {
+			c.expires = time.Now().Add(ttl).UTC().Truncate(time.Second)
+		}
+		return nil
+	}
+}
+
+// WithEventEmitter sets the emitter of the lab lifecycle events.
+func WithEventEmitter(e *clabcoreevents.Emitter) ClabOption {
+	return func(c *CLab) error {
+		c.events = e
+		return nil
+	}
+}
+
 // WithLabName sets the name of the lab
 // to the provided string.
 func WithLabName(n string) ClabOption {
@@ -162,6 +189,11 @@ func WithTopoPath(path, varsFile string) ClabOption {
 // WithTopoBackup creates a backup of the topology file.
 func WithTopoBackup(path string) ClabOption {
 	return func(c *CLab) error {
+		// the lab OCI artifacts are kept by the registry
+		if strings.HasPrefix(path, clabcorelabpack.OCIScheme) {
+			return nil
+		}
+
 		// create a backup file for the topology file
 		backupFPath := c.TopoPaths.TopologyBakFileAbsPath()
 
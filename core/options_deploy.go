@@ -13,6 +13,9 @@ type DeployOptions struct {
 	maxWorkers         uint   // maxWorkers is the maximum number of workers for node creation.
 	exportTemplate     string // exportTemplate is the path to the export template.
 	skipLabDirFileACLs bool   // skip setting the extended File ACL entries on the lab directory.
+	diff               bool   // diff indicates whether to deploy only the changes of the topology.
+	// startupConfigs are the rendered startup-configs of the nodes by node name.
+	startupConfigs map[string]string
 }
 
 // NewDeployOptions creates a new DeployOptions instance with the specified maxWorkers value.
@@ -33,6 +36,18 @@ func (d *DeployOptions) Reconfigure() bool {
 	return d.reconfigure
 }
 
+// SetDiff sets the diff option and returns the updated DeployOptions instance.
+// With diff set, only the nodes and links changed since the recorded state of the lab are deployed.
+func (d *DeployOptions) SetDiff(b bool) *DeployOptions {
+	d.diff = b
+	return d
+}
+
+// Diff returns the diff option value.
+func (d *DeployOptions) Diff() bool {
+	return d.diff
+}
+
 // SetSkipPostDeploy sets the skipPostDeploy option and returns the updated DeployOptions instance.
 func (d *DeployOptions) SetSkipPostDeploy(b bool) *DeployOptions {
 	d.skipPostDeploy = b
@@ -83,6 +98,18 @@ func (d *DeployOptions) ExportTemplate() string {
 	return d.exportTemplate
 }
 
+// SetStartupConfigs sets the rendered startup-configs of the nodes, by node name,
+// written to the lab directory and used as the nodes' startup-config.
+func (d *DeployOptions) SetStartupConfigs(cfgs map[string]string) *DeployOptions {
+	d.startupConfigs = cfgs
+	return d
+}
+
+// StartupConfigs returns the rendered startup-configs of the nodes.
+func (d *DeployOptions) StartupConfigs() map[string]string {
+	return d.startupConfigs
+}
+
 // initWorkerCount calculates the number of workers used for node creation.
 // If maxWorkers is provided, it takes precedence.
 // If maxWorkers is not set, the number of workers is limited by the number of available CPUs
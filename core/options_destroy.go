@@ -1,5 +1,7 @@
 package core
 
+import "context"
+
 // DestroyOption is a type used for functional options for the Clab Destroy method.
 type DestroyOption func(o *DestroyOptions)
 
@@ -12,8 +14,13 @@ type DestroyOptions struct {
 	terminalPrompt bool
 	cleanup        bool
 	nodeFilter     []string
+	hooks          []DestroyHook
 }
 
+// DestroyHook is run for every lab before its nodes are destroyed,
+// e.g. to reverse the config the lab applied to external devices.
+type DestroyHook func(ctx context.Context, c *CLab) error
+
 // NewDestroyOptions returns a new destroy options object.
 func NewDestroyOptions() *DestroyOptions {
 	return &DestroyOptions{}
@@ -70,3 +77,10 @@ func WithDestroyNodeFilter(ss []string) DestroyOption {
 		o.nodeFilter = ss
 	}
 }
+
+// WithDestroyHook adds a hook run before the nodes of a lab are destroyed.
+func WithDestroyHook(h DestroyHook) DestroyOption {
+	return func(o *DestroyOptions) {
+		o.hooks = append(o.hooks, h)
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	clabcorelabpack "github.com/srl-labs/containerlab/core/labpack"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// PackManifest returns the manifest of the lab pack: the topology file, its variables file,
+// the image lock file and the files the nodes reference with relative paths, such as the
+// startup-configs, the licenses, the env files and the bind sources, along with the extra
+// files and directories of include, e.g. the config templates.
+// The absolute paths of the nodes are host paths, which are not packed.
+func (c *CLab) PackManifest(include []string) (*clabcorelabpack.Manifest, error) {
+	topoDir := c.TopoPaths.TopologyFileDir()
+	topo := c.TopoPaths.TopologyFilenameAbsPath()
+
+	m := &clabcorelabpack.Manifest{
+		Name:     c.Config.Name,
+		Topology: c.TopoPaths.TopologyFilenameBase(),
+	}
+
+	files := map[string]string{
+		m.Topology: topo,
+	}
+
+	varsFile, err := varsFilePath(topo, c.TopoPaths.VarsFilenameAbsPath())
+	if err != nil {
+		return nil, err
+	}
+	if varsFile != "" {
+		// the variables file is packed under the name deploy finds it by
+		files[c.TopoPaths.TopologyFilenameWithoutExt()+varFileSuffix+filepath.Ext(varsFile)] = varsFile
+	}
+
+	if lock := c.TopoPaths.ImageLockFileAbsPath(); clabutils.FileExists(lock) {
+		files[filepath.Base(lock)] = lock
+	}
+
+	add := func(p, ref string) error {
+		if p == "" || filepath.IsAbs(p) || strings.HasPrefix(p, "~") {
+			if p != "" {
+				log.Debugf("%s %s is a host path, not packing it", ref, p)
+			}
+
+			return nil
+		}
+
+		rel := filepath.Clean(p)
+		if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("%s %s is outside of the topology directory %s", ref, p, topoDir)
+		}
+
+		src := filepath.Join(topoDir, rel)
+		if _, err := os.Stat(src); err != nil {
+			return fmt.Errorf("%s %s: %w", ref, p, err)
+		}
+
+		files[filepath.ToSlash(rel)] = src
+
+		return nil
+	}
+
+	for name := range c.Config.Topology.Nodes {
+		for _, p := range c.nodePackPaths(name) {
+			if err := add(p.path, fmt.Sprintf("node %q %s", name, p.ref)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, inc := range include {
+		matches, err := filepath.Glob(filepath.Join(topoDir, inc))
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("included path %s matches no files", inc)
+		}
+
+		for _, match := range matches {
+			rel, err := filepath.Rel(topoDir, match)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := add(rel, "included path"); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for p, src := range files {
+		m.Files = append(m.Files, &clabcorelabpack.File{Path: p, Source: src})
+	}
+
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Path < m.Files[j].Path })
+
+	return m, nil
+}
+
+// packPath is a path a node references.
+type packPath struct {
+	// ref names the node setting referencing the path.
+	ref  string
+	path string
+}
+
+// nodePackPaths returns the local paths the node references.
+func (c *CLab) nodePackPaths(name string) []packPath {
+	t := c.Config.Topology
+	r := c.magicVarReplacer(name)
+
+	var paths []packPath
+
+	// the remote and the inline startup-configs are not local files
+	if sc := t.GetNodeStartupConfig(name); !strings.Contains(sc, "://") && !strings.Contains(sc, "\n") {
+		paths = append(paths, packPath{"startup-config", r.Replace(sc)})
+	}
+
+	paths = append(paths, packPath{"license", r.Replace(t.GetNodeLicense(name))})
+
+	for _, f := range t.GetNodeEnvFiles(name) {
+		paths = append(paths, packPath{"env file", r.Replace(f)})
+	}
+
+	binds, err := t.GetNodeBinds(name)
+	if err != nil {
+		log.Warnf("failed to read the binds of node %q: %v", name, err)
+	}
+	for _, bs := range binds {
+		b, err := clabtypes.NewBindFromString(bs)
+		if err != nil {
+			continue
+		}
+
+		paths = append(paths, packPath{"bind", r.Replace(b.Src())})
+	}
+
+	return paths
+}
+
+// Pack writes the lab pack of the manifest as a gzipped tarball.
+func Pack(m *clabcorelabpack.Manifest) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := clabcorelabpack.Write(&buf, m); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// pullPackedTopology pulls the lab OCI artifact, extracts it in the tmp dir
+// and returns the path of its topology file.
+func (c *CLab) pullPackedTopology(ref string) (string, error) {
+	b, err := clabcorelabpack.Pull(context.Background(), ref)
+	if err != nil {
+		return "", fmt.Errorf("failed to pull lab %s: %w", ref, err)
+	}
+
+	dir, err := os.MkdirTemp(c.TopoPaths.ClabTmpDir(), "pack-*")
+	if err != nil {
+		return "", err
+	}
+
+	m, err := clabcorelabpack.Extract(bytes.NewReader(b), dir)
+	if err != nil {
+		return "", err
+	}
+
+	log.Info("Pulled lab", "lab", m.Name, "ref", ref, "dir", dir)
+
+	return filepath.Join(dir, m.Topology), nil
+}
@@ -0,0 +1,124 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestPackManifest(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"pack.clab.yml": `name: pack
+topology:
+  kinds:
+    linux:
+      env-files:
+        - common.env
+  nodes:
+    n1:
+      kind: linux
+      startup-config: configs/__clabNodeName__.cfg
+      binds:
+        - scripts:/scripts
+        - /etc:/host/etc:ro
+        - __clabNodeDir__/data:/data
+    n2:
+      kind: linux
+      startup-config: configs/__clabNodeName__.cfg
+      license: licenses/n2.key
+`,
+		"pack.clab_vars.yml": "bgp: 65000\n",
+		"pack.clab.lock.yml": "images: {}\n",
+		"common.env":         "FOO=bar\n",
+		"configs/n1.cfg":     "n1\n",
+		"configs/n2.cfg":     "n2\n",
+		"licenses/n2.key":    "key\n",
+		"scripts/run.sh":     "#!/bin/sh\n",
+		"templates/srl.tmpl": "{{ .bgp }}\n",
+		"unrelated.txt":      "not packed\n",
+		// the node dir is in the lab dir, a host path not packed
+		"clab-pack/n1/data/state": "state\n",
+	}
+	for p, content := range files {
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, p)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, p), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	c, err := NewContainerLab(WithTopoPath(filepath.Join(dir, "pack.clab.yml"), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := c.PackManifest([]string{"templates"})
+	if err != nil {
+		t.Fatalf("PackManifest() failed: %v", err)
+	}
+
+	var got []string
+	for _, f := range m.Files {
+		got = append(got, f.Path)
+		if f.Source != filepath.Join(dir, f.Path) {
+			t.Errorf("file %s source = %s, want %s", f.Path, f.Source, filepath.Join(dir, f.Path))
+		}
+	}
+
+	want := []string{
+		"common.env",
+		"configs/n1.cfg",
+		"configs/n2.cfg",
+		"licenses/n2.key",
+		"pack.clab.lock.yml",
+		"pack.clab.yml",
+		"pack.clab_vars.yml",
+		"scripts",
+		"templates",
+	}
+
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("PackManifest() files mismatch (-want +got):\n%s", d)
+	}
+
+	if m.Name != "pack" || m.Topology != "pack.clab.yml" {
+		t.Errorf("PackManifest() = name %q, topology %q, want pack, pack.clab.yml", m.Name, m.Topology)
+	}
+}
+
+func TestPackManifestOutsideTopologyDir(t *testing.T) {
+	dir := t.TempDir()
+	labDir := filepath.Join(dir, "lab")
+
+	if err := os.MkdirAll(labDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "n1.cfg"), []byte("n1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	topo := `name: pack
+topology:
+  nodes:
+    n1:
+      kind: linux
+      startup-config: ../n1.cfg
+`
+	if err := os.WriteFile(filepath.Join(labDir, "pack.clab.yml"), []byte(topo), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := NewContainerLab(WithTopoPath(filepath.Join(labDir, "pack.clab.yml"), ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.PackManifest(nil); err == nil {
+		t.Errorf("PackManifest() of a startup-config outside of the topology directory did not fail")
+	}
+}
@@ -0,0 +1,47 @@
+package core
+
+import (
+	"sort"
+
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// KindPreflight is the result of the security preflight of the lab nodes of a kind.
+type KindPreflight struct {
+	Kind  string   `json:"kind"`
+	Nodes []string `json:"nodes"`
+	// Issues are the host settings blocking the nodes of the kind.
+	Issues []string `json:"issues,omitempty"`
+}
+
+// SecurityPreflight checks the host settings against the security profiles of the lab nodes
+// and returns the settings blocking them by kind, sorted by kind.
+func (c *CLab) SecurityPreflight() []*KindPreflight {
+	kinds := map[string]*KindPreflight{}
+
+	for name, n := range c.Nodes {
+		kind := n.Config().Kind
+
+		kp, ok := kinds[kind]
+		if !ok {
+			kp = &KindPreflight{Kind: kind}
+			kinds[kind] = kp
+		}
+		kp.Nodes = append(kp.Nodes, name)
+
+		if sn, ok := n.(clabnodes.SecurityProfileNode); ok {
+			kp.Issues = clabutils.MergeStringSlices(kp.Issues, sn.SecurityPreflight())
+		}
+	}
+
+	res := make([]*KindPreflight, 0, len(kinds))
+	for _, kp := range kinds {
+		sort.Strings(kp.Nodes)
+		res = append(res, kp)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Kind < res[j].Kind })
+
+	return res
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"context"
+
+	clabcorequota "github.com/srl-labs/containerlab/core/quota"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+// CheckQuota returns an error when the lab doesn't fit in the quota of its owner
+// with the other labs of the owner. The nodes of the deployed labs count with the memory they use,
+// the nodes of the lab with the memory they require.
+func (c *CLab) CheckQuota(ctx context.Context, q *clabcorequota.Quotas) error {
+	owner := c.Owner()
+
+	limits := q.For(owner)
+	if limits == nil {
+		return nil
+	}
+
+	containers, err := c.ListContainers(ctx, WithListclabLabelExists())
+	if err != nil {
+		return err
+	}
+
+	owned := ownerNodeContainers(containers, owner, c.Config.Name)
+
+	deployed := clabcorequota.Usage{Nodes: len(owned)}
+	for _, lab := range ContainersResources(ctx, owned) {
+		deployed.Memory += lab.Total.MemoryUsage
+	}
+
+	lab := clabcorequota.Usage{
+		Nodes:  len(c.Nodes),
+		Memory: uint64(c.HostRequirements().MemoryGB * (1 << 30)),
+	}
+
+	return limits.Check(owner, deployed, lab)
+}
+
+// ownerNodeContainers returns the node containers of the owner, the containers of the lab
+// being replaced by its deployment and the tool containers, without a lab directory, excluded.
+func ownerNodeContainers(containers []clabruntime.GenericContainer,
+	owner, lab string,
+) []clabruntime.GenericContainer {
+	var res []clabruntime.GenericContainer
+
+	for _, cnt := range containers {
+		if _, ok := cnt.Labels[clablabels.NodeLabDir]; !ok {
+			continue
+		}
+
+		if cnt.Labels[clablabels.Owner] != owner || cnt.Labels[clablabels.Containerlab] == lab {
+			continue
+		}
+
+		res = append(res, cnt)
+	}
+
+	return res
+}
@@ -0,0 +1,118 @@
+// Package quota limits the resources the labs of a user may take on a shared lab server.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/dustin/go-humanize"
+	"gopkg.in/yaml.v2"
+)
+
+// ErrQuotaExceeded is returned when a lab doesn't fit in the quota of its user.
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Limits are the resources the labs of a user may take, the zero limits are unlimited.
+type Limits struct {
+	// MaxNodes is the number of nodes of the labs of the user.
+	MaxNodes int `yaml:"max-nodes,omitempty"`
+	// MaxMemory is the memory of the nodes of the labs of the user, such as 64GB.
+	MaxMemory string `yaml:"max-memory,omitempty"`
+
+	maxMemory uint64
+}
+
+// Quotas are the limits of the users, the users without their own limits having the default ones.
+type Quotas struct {
+	Default *Limits            `yaml:"default,omitempty"`
+	Users   map[string]*Limits `yaml:"users,omitempty"`
+}
+
+// Usage is the resources taken by labs.
+type Usage struct {
+	Nodes int
+	// Memory is the memory in bytes.
+	Memory uint64
+}
+
+// Add returns the sum of the usages.
+func (u Usage) Add(o Usage) Usage {
+	return Usage{Nodes: u.Nodes + o.Nodes, Memory: u.Memory + o.Memory}
+}
+
+// Load reads the quotas file.
+func Load(path string) (*Quotas, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	q := &Quotas{}
+	if err := yaml.UnmarshalStrict(b, q); err != nil {
+		return nil, fmt.Errorf("failed to parse the quotas file %s: %w", path, err)
+	}
+
+	if err := q.Default.parse(); err != nil {
+		return nil, fmt.Errorf("default quota: %w", err)
+	}
+
+	for u, l := range q.Users {
+		if err := l.parse(); err != nil {
+			return nil, fmt.Errorf("quota of user %s: %w", u, err)
+		}
+	}
+
+	return q, nil
+}
+
+func (l *Limits) parse() error {
+	if l == nil || l.MaxMemory == "" {
+		return nil
+	}
+
+	b, err := humanize.ParseBytes(l.MaxMemory)
+	if err != nil {
+		return fmt.Errorf("invalid max-memory %q: %w", l.MaxMemory, err)
+	}
+
+	l.maxMemory = b
+
+	return nil
+}
+
+// For returns the limits of the user, nil when the user is unlimited.
+func (q *Quotas) For(user string) *Limits {
+	if q == nil {
+		return nil
+	}
+
+	if l, ok := q.Users[user]; ok {
+		return l
+	}
+
+	return q.Default
+}
+
+// Check returns an error when the lab doesn't fit in the limits of the user
+// with the labs the user has deployed.
+func (l *Limits) Check(user string, deployed, lab Usage) error {
+	if l == nil {
+		return nil
+	}
+
+	total := deployed.Add(lab)
+
+	if l.MaxNodes > 0 && total.Nodes > l.MaxNodes {
+		return fmt.Errorf("%w: user %s has %d nodes deployed and the lab has %d nodes, over the quota of %d nodes",
+			ErrQuotaExceeded, user, deployed.Nodes, lab.Nodes, l.MaxNodes)
+	}
+
+	if l.maxMemory > 0 && total.Memory > l.maxMemory {
+		return fmt.Errorf("%w: user %s has %s of memory used and the lab requires %s, over the quota of %s",
+			ErrQuotaExceeded, user, humanize.IBytes(deployed.Memory), humanize.IBytes(lab.Memory),
+			humanize.IBytes(l.maxMemory))
+	}
+
+	return nil
+}
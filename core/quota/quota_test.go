@@ -0,0 +1,113 @@
+package quota
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const quotasFile = `default:
+  max-nodes: 10
+  max-memory: 16GiB
+users:
+  alice:
+    max-nodes: 50
+  admin: {}
+`
+
+func TestLoad(t *testing.T) {
+	tests := map[string]struct {
+		content string
+		wantErr bool
+	}{
+		"valid": {
+			content: quotasFile,
+		},
+		"invalid memory": {
+			content: "default:\n  max-memory: lots\n",
+			wantErr: true,
+		},
+		"unknown field": {
+			content: "default:\n  max-cpus: 4\n",
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(t.TempDir(), "quotas.yml")
+			if err := os.WriteFile(p, []byte(tc.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			_, err := Load(p)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	p := filepath.Join(t.TempDir(), "quotas.yml")
+	if err := os.WriteFile(p, []byte(quotasFile), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	q, err := Load(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		user     string
+		deployed Usage
+		lab      Usage
+		wantErr  bool
+	}{
+		"default within quota": {
+			user:     "bob",
+			deployed: Usage{Nodes: 6, Memory: 8 << 30},
+			lab:      Usage{Nodes: 4, Memory: 8 << 30},
+		},
+		"default over the nodes": {
+			user:     "bob",
+			deployed: Usage{Nodes: 6},
+			lab:      Usage{Nodes: 5},
+			wantErr:  true,
+		},
+		"default over the memory": {
+			user:     "bob",
+			deployed: Usage{Nodes: 1, Memory: 12 << 30},
+			lab:      Usage{Nodes: 1, Memory: 6 << 30},
+			wantErr:  true,
+		},
+		"user quota": {
+			user:     "alice",
+			deployed: Usage{Nodes: 30, Memory: 64 << 30},
+			lab:      Usage{Nodes: 20, Memory: 64 << 30},
+		},
+		"unlimited user": {
+			user: "admin",
+			lab:  Usage{Nodes: 500, Memory: 1 << 40},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := q.For(tc.user).Check(tc.user, tc.deployed, tc.lab)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrQuotaExceeded) {
+				t.Errorf("Check() error = %v, want ErrQuotaExceeded", err)
+			}
+		})
+	}
+
+	var none *Quotas
+	if l := none.For("bob"); l != nil {
+		t.Errorf("expected no limits without quotas, got %+v", l)
+	}
+}
@@ -0,0 +1,36 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+func TestOwnerNodeContainers(t *testing.T) {
+	cnt := func(name, lab, owner string, labDir bool) clabruntime.GenericContainer {
+		labels := map[string]string{clablabels.Containerlab: lab, clablabels.Owner: owner}
+		if labDir {
+			labels[clablabels.NodeLabDir] = "/clab-" + lab + "/" + name
+		}
+		return clabruntime.GenericContainer{Names: []string{name}, Labels: labels}
+	}
+
+	containers := []clabruntime.GenericContainer{
+		cnt("clab-lab1-srl1", "lab1", "alice", true),
+		cnt("clab-lab2-srl1", "lab2", "alice", true),
+		cnt("clab-lab2-srl2", "lab2", "alice", true),
+		cnt("clab-lab3-srl1", "lab3", "bob", true),
+		cnt("clab-lab2-sshx", "lab2", "alice", false),
+	}
+
+	var got []string
+	for _, c := range ownerNodeContainers(containers, "alice", "lab1") {
+		got = append(got, c.Names[0])
+	}
+
+	if d := cmp.Diff([]string{"clab-lab2-srl1", "clab-lab2-srl2"}, got); d != "" {
+		t.Errorf("owner containers mismatch (-want +got):\n%s", d)
+	}
+}
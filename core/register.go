@@ -27,6 +27,7 @@ import (
 	clabnodeslinux "github.com/srl-labs/containerlab/nodes/linux"
 	clabnodesovs "github.com/srl-labs/containerlab/nodes/ovs"
 	clabnodesrare "github.com/srl-labs/containerlab/nodes/rare"
+	clabnodesroute_injector "github.com/srl-labs/containerlab/nodes/route_injector"
 	clabnodessonic "github.com/srl-labs/containerlab/nodes/sonic"
 	clabnodessonic_vm "github.com/srl-labs/containerlab/nodes/sonic_vm"
 	clabnodessrl "github.com/srl-labs/containerlab/nodes/srl"
@@ -70,6 +71,7 @@ func (c *CLab) RegisterNodes() {
 	clabnodeskeysight_ixiacone.Register(c.Reg)
 	clabnodeslinux.Register(c.Reg)
 	clabnodesovs.Register(c.Reg)
+	clabnodesroute_injector.Register(c.Reg)
 	clabnodessonic.Register(c.Reg)
 	clabnodessrl.Register(c.Reg)
 	clabnodessros.Register(c.Reg)
@@ -0,0 +1,103 @@
+package core
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/log"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+// statsWorkers is the number of containers the stats are read from concurrently,
+// reading the stats of a container takes a second.
+const statsWorkers = 16
+
+// NodeResources is the resource usage of a lab node, nil stats for a node not running.
+type NodeResources struct {
+	Name  string                      `json:"name"`
+	Kind  string                      `json:"kind"`
+	State string                      `json:"state"`
+	Stats *clabruntime.ContainerStats `json:"stats,omitempty"`
+}
+
+// LabResources is the resource usage of the nodes of a lab and its totals.
+type LabResources struct {
+	Name  string                      `json:"name"`
+	Nodes []*NodeResources            `json:"nodes"`
+	Total *clabruntime.ContainerStats `json:"total"`
+}
+
+// ContainersResources reads the resource usage of the lab containers and aggregates it by lab,
+// the labs and their nodes sorted by name.
+func ContainersResources(ctx context.Context, containers []clabruntime.GenericContainer) []*LabResources {
+	nodes := make([]*NodeResources, len(containers))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, statsWorkers)
+
+	for i := range containers {
+		cnt := &containers[i]
+		nodes[i] = &NodeResources{
+			Kind:  cnt.Labels[clablabels.NodeKind],
+			State: cnt.State,
+		}
+		if len(cnt.Names) > 0 {
+			nodes[i].Name = strings.TrimPrefix(cnt.Names[0], "/")
+		}
+
+		if cnt.State != "running" || cnt.Runtime == nil {
+			continue
+		}
+
+		wg.Add(1)
+		go func(nr *NodeResources) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			s, err := cnt.Runtime.GetContainerStats(ctx, cnt.ID)
+			if err != nil {
+				log.Warnf("failed to read the stats of container %s: %v", nr.Name, err)
+				return
+			}
+			nr.Stats = s
+		}(nodes[i])
+	}
+	wg.Wait()
+
+	labs := map[string]*LabResources{}
+	for i, nr := range nodes {
+		name := containers[i].Labels[clablabels.Containerlab]
+
+		lr, ok := labs[name]
+		if !ok {
+			lr = &LabResources{Name: name, Total: &clabruntime.ContainerStats{}}
+			labs[name] = lr
+		}
+		lr.Nodes = append(lr.Nodes, nr)
+
+		if nr.Stats == nil {
+			continue
+		}
+
+		lr.Total.CPUPercent += nr.Stats.CPUPercent
+		lr.Total.MemoryUsage += nr.Stats.MemoryUsage
+		lr.Total.DiskUsage += nr.Stats.DiskUsage
+		lr.Total.PIDs += nr.Stats.PIDs
+		// the nodes share the memory of the host, the lab limit is the largest node limit
+		lr.Total.MemoryLimit = max(lr.Total.MemoryLimit, nr.Stats.MemoryLimit)
+	}
+
+	res := make([]*LabResources, 0, len(labs))
+	for _, lr := range labs {
+		sort.Slice(lr.Nodes, func(i, j int) bool { return lr.Nodes[i].Name < lr.Nodes[j].Name })
+		res = append(res, lr)
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+
+	return res
+}
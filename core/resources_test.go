@@ -0,0 +1,75 @@
+package core
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabmocksmockruntime "github.com/srl-labs/containerlab/mocks/mockruntime"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	"go.uber.org/mock/gomock"
+)
+
+func TestContainersResources(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	rt := clabmocksmockruntime.NewMockContainerRuntime(mockCtrl)
+
+	stats := map[string]*clabruntime.ContainerStats{
+		"id-srl":    {CPUPercent: 12.5, MemoryUsage: 2 << 30, MemoryLimit: 16 << 30, DiskUsage: 1 << 20, PIDs: 120},
+		"id-client": {CPUPercent: 0.5, MemoryUsage: 4 << 20, MemoryLimit: 16 << 30, DiskUsage: 4 << 10, PIDs: 2},
+		"id-other":  {CPUPercent: 1, MemoryUsage: 8 << 20, MemoryLimit: 8 << 30, PIDs: 5},
+	}
+
+	rt.EXPECT().GetContainerStats(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ context.Context, id string) (*clabruntime.ContainerStats, error) {
+			return stats[id], nil
+		}).Times(len(stats))
+
+	cnt := func(id, lab, name, kind, state string) clabruntime.GenericContainer {
+		return clabruntime.GenericContainer{
+			ID:      id,
+			Names:   []string{name},
+			State:   state,
+			Labels:  map[string]string{clablabels.Containerlab: lab, clablabels.NodeKind: kind},
+			Runtime: rt,
+		}
+	}
+
+	containers := []clabruntime.GenericContainer{
+		cnt("id-srl", "lab1", "clab-lab1-srl", "nokia_srlinux", "running"),
+		cnt("id-other", "lab0", "clab-lab0-node", "linux", "running"),
+		cnt("id-client", "lab1", "clab-lab1-client", "linux", "running"),
+		cnt("id-stopped", "lab1", "clab-lab1-stopped", "linux", "exited"),
+	}
+
+	want := []*LabResources{
+		{
+			Name: "lab0",
+			Nodes: []*NodeResources{
+				{Name: "clab-lab0-node", Kind: "linux", State: "running", Stats: stats["id-other"]},
+			},
+			Total: &clabruntime.ContainerStats{CPUPercent: 1, MemoryUsage: 8 << 20, MemoryLimit: 8 << 30, PIDs: 5},
+		},
+		{
+			Name: "lab1",
+			Nodes: []*NodeResources{
+				{Name: "clab-lab1-client", Kind: "linux", State: "running", Stats: stats["id-client"]},
+				{Name: "clab-lab1-srl", Kind: "nokia_srlinux", State: "running", Stats: stats["id-srl"]},
+				{Name: "clab-lab1-stopped", Kind: "linux", State: "exited"},
+			},
+			Total: &clabruntime.ContainerStats{
+				CPUPercent:  13,
+				MemoryUsage: 2<<30 + 4<<20,
+				MemoryLimit: 16 << 30,
+				DiskUsage:   1<<20 + 4<<10,
+				PIDs:        122,
+			},
+		},
+	}
+
+	got := ContainersResources(context.Background(), containers)
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("ContainersResources() mismatch (-want +got):\n%s", d)
+	}
+}
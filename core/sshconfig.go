@@ -1,14 +1,23 @@
 package core
 
 import (
+	"bytes"
 	_ "embed"
+	"errors"
+	"net"
 	"os"
 	"path"
+	"slices"
+	"strings"
+	"sync"
 	"text/template"
+	"time"
 
 	"github.com/charmbracelet/log"
 	clabtypes "github.com/srl-labs/containerlab/types"
 	clabutils "github.com/srl-labs/containerlab/utils"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
 	"golang.org/x/mod/semver"
 )
 
@@ -17,37 +26,57 @@ import (
 type SSHConfigTmpl struct {
 	Nodes        []SSHConfigNodeTmpl
 	TopologyName string
+	// KnownHostsFile is the known_hosts file with the host keys of the nodes,
+	// the host keys are not checked when empty.
+	KnownHostsFile string
 }
 
 // SSHConfigNodeTmpl represents values for a single node
 // in the sshconfig template.
 type SSHConfigNodeTmpl struct {
-	Name      string
+	Name string
+	// Aliases are additional host patterns matching the node, e.g. the node's short name.
+	Aliases []string
+	// Address is the management address of the node used as the HostName.
+	Address   string
 	Username  string
+	ProxyJump string
 	SSHConfig *clabtypes.SSHConfig
 }
 
+// hostKeyScanTimeout limits the time spent reading the host key of a node.
+const hostKeyScanTimeout = 5 * time.Second
+
+// errHostKeyRead aborts the SSH handshake once the host key of the node is read.
+var errHostKeyRead = errors.New("host key read")
+
 // sshConfigTemplate is the SSH config template.
 //
 //go:embed assets/ssh_config.go.tpl
 var sshConfigTemplate string
 
-// RemoveSSHConfig removes the lab specific ssh config file.
+// RemoveSSHConfig removes the lab specific ssh config file,
+// its copy in the user's ssh config directory and the known_hosts file of the lab.
 func (c *CLab) RemoveSSHConfig(topoPaths *clabtypes.TopoPaths) error {
-	err := os.Remove(topoPaths.SSHConfigPath())
-	// if there is an error, thats not "Not Exists", then return it
-	if err != nil && !os.IsNotExist(err) {
-		return err
+	for _, p := range []string{
+		topoPaths.SSHConfigPath(), topoPaths.UserSSHConfigPath(),
+		topoPaths.UserKnownHostsPath(),
+	} {
+		err := os.Remove(p)
+		// if there is an error, thats not "Not Exists", then return it
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
 	}
 	return nil
 }
 
 // addSSHConfig adds the lab specific ssh config file.
+// When enabled via settings, the config is also installed in the ~/.ssh/config.d directory.
 func (c *CLab) addSSHConfig() error {
-	sshConfigDir := path.Dir(c.TopoPaths.SSHConfigPath())
-	if !clabutils.FileOrDirExists(sshConfigDir) {
-		log.Debugf("ssh config directory %s does not exist, skipping ssh config generation", sshConfigDir)
-		return nil
+	var sshSettings *clabtypes.SSHConfigSettings
+	if c.Config.Settings != nil {
+		sshSettings = c.Config.Settings.SSHConfig
 	}
 
 	tmpl := &SSHConfigTmpl{
@@ -68,10 +97,20 @@ func (c *CLab) addSSHConfig() error {
 		NodeRegistryEntry := c.Reg.Kind(n.Config().Kind)
 		nodeData := SSHConfigNodeTmpl{
 			Name:      n.Config().LongName,
+			Address:   n.Config().MgmtIPv4Address,
 			Username:  NodeRegistryEntry.GetCredentials().GetUsername(),
+			ProxyJump: sshSettings.GetProxyJump(),
 			SSHConfig: n.GetSSHConfig(),
 		}
 
+		if nodeData.Address == "" {
+			nodeData.Address = n.Config().MgmtIPv6Address
+		}
+
+		if n.Config().ShortName != n.Config().LongName {
+			nodeData.Aliases = append(nodeData.Aliases, n.Config().ShortName)
+		}
+
 		// if we couldn't parse the ssh version we assume we can't use unbound option
 		// or if the version is lower than 8.9
 		// and the node has the PubkeyAuthentication set to unbound
@@ -85,21 +124,151 @@ func (c *CLab) addSSHConfig() error {
 		tmpl.Nodes = append(tmpl.Nodes, nodeData)
 	}
 
-	t, err := template.New("sshconfig").Parse(sshConfigTemplate)
+	content, err := renderSSHConfig(tmpl)
 	if err != nil {
 		return err
 	}
 
-	f, err := os.OpenFile(c.TopoPaths.SSHConfigPath(), os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	sshConfigDir := path.Dir(c.TopoPaths.SSHConfigPath())
+	switch {
+	case clabutils.IsRootless():
+		log.Debugf("rootless mode, skipping ssh config generation in %s", sshConfigDir)
+	case clabutils.FileOrDirExists(sshConfigDir):
+		err = os.WriteFile(c.TopoPaths.SSHConfigPath(), content, 0o644) // skipcq: GSC-G306
+		if err != nil {
+			return err
+		}
+	default:
+		log.Debugf("ssh config directory %s does not exist, skipping ssh config generation", sshConfigDir)
+	}
+
+	if sshSettings.GetUserConfig() {
+		return c.addUserSSHConfig(tmpl)
+	}
+
+	return nil
+}
+
+// renderSSHConfig renders the ssh config of the lab nodes.
+func renderSSHConfig(tmpl *SSHConfigTmpl) ([]byte, error) {
+	t, err := template.New("sshconfig").Parse(sshConfigTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+
+	err = t.Execute(&buf, tmpl)
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// addUserSSHConfig installs the lab ssh config in the ~/.ssh/config.d directory
+// of the user running containerlab, along with the known_hosts file of the lab nodes.
+// The user config checks the host keys against the known_hosts file, the keys of the nodes
+// not accepting SSH connections yet are added on the first login.
+func (c *CLab) addUserSSHConfig(tmpl *SSHConfigTmpl) error {
+	userConfigPath := c.TopoPaths.UserSSHConfigPath()
+	knownHostsPath := c.TopoPaths.UserKnownHostsPath()
+
+	// the directories and files are created by root, they are handed over to the sudo user
+	err := clabutils.CreateUserDirectory(path.Dir(userConfigPath), 0o700)
 	if err != nil {
 		return err
 	}
-	defer f.Close()
 
-	err = t.Execute(f, tmpl)
+	userTmpl := *tmpl
+	userTmpl.KnownHostsFile = knownHostsPath
+
+	content, err := renderSSHConfig(&userTmpl)
 	if err != nil {
 		return err
 	}
 
+	knownHosts := nodeKnownHosts(tmpl.Nodes)
+
+	var knownHostsContent []byte
+	if len(knownHosts) > 0 {
+		knownHostsContent = []byte(strings.Join(knownHosts, "\n") + "\n")
+	}
+
+	for p, b := range map[string][]byte{
+		userConfigPath: content,
+		knownHostsPath: knownHostsContent,
+	} {
+		err = os.WriteFile(p, b, 0o600)
+		if err != nil {
+			return err
+		}
+
+		err = clabutils.SetUIDAndGID(p)
+		if err != nil {
+			log.Warnf("failed to adjust ownership of %s: %v", p, err)
+		}
+	}
+
+	log.Info("Installed SSH config for the user", "path", userConfigPath,
+		"known-hosts", knownHostsPath, "host-keys", len(knownHosts))
+	log.Debugf("make sure ~/.ssh/config contains 'Include config.d/*' for the config to take effect")
+
 	return nil
 }
+
+// nodeKnownHosts returns the known_hosts lines of the host keys of the nodes,
+// matching the node names and the management address. The nodes not accepting SSH connections
+// within hostKeyScanTimeout, e.g. still booting, are left out.
+func nodeKnownHosts(nodes []SSHConfigNodeTmpl) []string {
+	lines := make([]string, len(nodes))
+
+	var wg sync.WaitGroup
+	for i, n := range nodes {
+		if n.Address == "" {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			key, err := readHostKey(net.JoinHostPort(n.Address, "22"), hostKeyScanTimeout)
+			if err != nil {
+				log.Debugf("could not read the host key of %s: %v", n.Name, err)
+				return
+			}
+
+			addrs := append([]string{n.Name, n.Address}, n.Aliases...)
+			lines[i] = knownhosts.Line(addrs, key)
+		}()
+	}
+	wg.Wait()
+
+	return slices.DeleteFunc(lines, func(l string) bool { return l == "" })
+}
+
+// readHostKey returns the host key of the SSH server, read from the SSH handshake.
+func readHostKey(addr string, timeout time.Duration) (ssh.PublicKey, error) {
+	var key ssh.PublicKey
+
+	cfg := &ssh.ClientConfig{
+		User:    "containerlab",
+		Timeout: timeout,
+		HostKeyCallback: func(_ string, _ net.Addr, k ssh.PublicKey) error {
+			key = k
+			return errHostKeyRead
+		},
+	}
+
+	c, err := ssh.Dial("tcp", addr, cfg)
+	if err == nil {
+		c.Close()
+	}
+
+	if key == nil {
+		return nil, err
+	}
+
+	return key, nil
+}
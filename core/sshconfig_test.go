@@ -0,0 +1,113 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+func TestReadHostKey(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := ssh.NewSignerFromKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	go func() {
+		cfg := &ssh.ServerConfig{NoClientAuth: true}
+		cfg.AddHostKey(signer)
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer c.Close()
+				_, _, _, _ = ssh.NewServerConn(c, cfg)
+			}()
+		}
+	}()
+
+	key, err := readHostKey(l.Addr().String(), time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff(signer.PublicKey().Marshal(), key.Marshal()); d != "" {
+		t.Errorf("host key mismatch (-want +got):\n%s", d)
+	}
+
+	// the known_hosts line matches the node names and address
+	line := knownhosts.Line([]string{"clab-srl-srl1", "172.20.20.2", "srl1"}, key)
+	want := "clab-srl-srl1,172.20.20.2,srl1 ssh-ed25519 "
+	if !strings.HasPrefix(line, want) {
+		t.Errorf("want the known_hosts line to start with %q, got %q", want, line)
+	}
+
+	// nothing listens on the closed port
+	l.Close()
+	if _, err := readHostKey(l.Addr().String(), time.Second); err == nil {
+		t.Error("want an error reading the host key of a closed port")
+	}
+}
+
+func TestRenderSSHConfigKnownHosts(t *testing.T) {
+	tmpl := &SSHConfigTmpl{
+		TopologyName: "srl",
+		Nodes: []SSHConfigNodeTmpl{{
+			Name:      "clab-srl-srl1",
+			Address:   "172.20.20.2",
+			Username:  "admin",
+			SSHConfig: &clabtypes.SSHConfig{},
+		}},
+	}
+
+	tests := map[string]struct {
+		knownHosts string
+		want       []string
+	}{
+		"no known hosts": {
+			want: []string{"StrictHostKeyChecking=no", "UserKnownHostsFile=/dev/null"},
+		},
+		"known hosts": {
+			knownHosts: "/home/user/.ssh/clab-srl.known_hosts",
+			want: []string{
+				"StrictHostKeyChecking=accept-new",
+				"UserKnownHostsFile=/home/user/.ssh/clab-srl.known_hosts",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			tm := *tmpl
+			tm.KnownHostsFile = tt.knownHosts
+
+			b, err := renderSSHConfig(&tm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(string(b), "\t"+w+"\n") {
+					t.Errorf("want %q in the ssh config, got:\n%s", w, b)
+				}
+			}
+		})
+	}
+}
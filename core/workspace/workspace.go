@@ -0,0 +1,121 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+// Package workspace manages the lab workspaces of a user: named labs with their
+// topology and vars files, one of them being the current lab used by the commands
+// when no topology is given.
+package workspace
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	clabutils "github.com/srl-labs/containerlab/utils"
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultPath is the path of the lab workspaces file of the user.
+const DefaultPath = "~/.clab/workspaces.yml"
+
+// Workspace is a named lab of the user.
+type Workspace struct {
+	// Topology and Vars are the absolute paths of the topology and its vars files.
+	Topology string `yaml:"topology"`
+	Vars     string `yaml:"vars,omitempty"`
+}
+
+// Registry is the set of the lab workspaces of the user, stored in a file.
+type Registry struct {
+	Current string                `yaml:"current,omitempty"`
+	Labs    map[string]*Workspace `yaml:"labs,omitempty"`
+
+	path string
+}
+
+// Load reads the registry of the lab workspaces from the file,
+// an empty registry is returned when the file doesn't exist.
+func Load(path string) (*Registry, error) {
+	r := &Registry{
+		Labs: map[string]*Workspace{},
+		path: clabutils.ExpandHome(path),
+	}
+
+	b, err := os.ReadFile(r.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := yaml.UnmarshalStrict(b, r); err != nil {
+		return nil, fmt.Errorf("failed to parse the lab workspaces file %s: %w", r.path, err)
+	}
+
+	if r.Labs == nil {
+		r.Labs = map[string]*Workspace{}
+	}
+
+	return r, nil
+}
+
+// Save writes the registry to its file.
+func (r *Registry) Save() error {
+	b, err := yaml.Marshal(r)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	return os.WriteFile(r.path, b, 0o644) // skipcq: GSC-G306
+}
+
+// Set adds the workspace or replaces the one with the same name.
+func (r *Registry) Set(name string, w *Workspace) {
+	r.Labs[name] = w
+}
+
+// Switch makes the named workspace the current one.
+func (r *Registry) Switch(name string) error {
+	if _, ok := r.Labs[name]; !ok {
+		return fmt.Errorf("lab workspace %q not found, add it with its topology using the --topo flag", name)
+	}
+
+	r.Current = name
+
+	return nil
+}
+
+// Remove removes the named workspace, unsetting the current workspace if it is the one removed.
+func (r *Registry) Remove(name string) {
+	delete(r.Labs, name)
+
+	if r.Current == name {
+		r.Current = ""
+	}
+}
+
+// CurrentWorkspace returns the current workspace, nil if none is set.
+func (r *Registry) CurrentWorkspace() *Workspace {
+	return r.Labs[r.Current]
+}
+
+// Names returns the sorted names of the workspaces.
+func (r *Registry) Names() []string {
+	res := make([]string, 0, len(r.Labs))
+	for n := range r.Labs {
+		res = append(res, n)
+	}
+
+	sort.Strings(res)
+
+	return res
+}
@@ -0,0 +1,54 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package workspace
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRegistry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clab", "workspaces.yml")
+
+	r, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() of a missing file failed: %v", err)
+	}
+	if r.CurrentWorkspace() != nil {
+		t.Fatalf("CurrentWorkspace() of an empty registry is not nil")
+	}
+
+	r.Set("dc", &Workspace{Topology: "/labs/dc.clab.yml", Vars: "/labs/dc-vars.yml"})
+	r.Set("wan", &Workspace{Topology: "/labs/wan.clab.yml"})
+
+	if err := r.Switch("campus"); err == nil {
+		t.Errorf("Switch() to an unknown workspace did not fail")
+	}
+	if err := r.Switch("wan"); err != nil {
+		t.Fatalf("Switch() failed: %v", err)
+	}
+	if err := r.Save(); err != nil {
+		t.Fatalf("Save() failed: %v", err)
+	}
+
+	r, err = Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+
+	if d := cmp.Diff([]string{"dc", "wan"}, r.Names()); d != "" {
+		t.Errorf("Names() mismatch (-want +got):\n%s", d)
+	}
+	if d := cmp.Diff(&Workspace{Topology: "/labs/wan.clab.yml"}, r.CurrentWorkspace()); d != "" {
+		t.Errorf("CurrentWorkspace() mismatch (-want +got):\n%s", d)
+	}
+
+	r.Remove("wan")
+	if r.Current != "" || r.CurrentWorkspace() != nil {
+		t.Errorf("Remove() of the current workspace did not unset it")
+	}
+}
@@ -0,0 +1,210 @@
+package ztp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"time"
+)
+
+// This file contains a minimal DHCPv4 (RFC 2131) implementation answering the
+// discover and request messages of the ZTP nodes with their management address
+// and the location of their day-0 files.
+
+// DHCP message types.
+const (
+	dhcpDiscover = 1
+	dhcpOffer    = 2
+	dhcpRequest  = 3
+	dhcpAck      = 5
+)
+
+// DHCP options.
+const (
+	optSubnetMask    = 1
+	optRouter        = 3
+	optHostName      = 12
+	optLeaseTime     = 51
+	optMessageType   = 53
+	optServerID      = 54
+	optVendorClass   = 60
+	optClientID      = 61
+	optTFTPServer    = 66
+	optBootFileName  = 67
+	optPad           = 0
+	optEnd           = 255
+	dhcpHeaderLength = 236
+	bootRequest      = 1
+	bootReply        = 2
+	flagBroadcast    = 0x8000
+)
+
+// dhcpMagic is the magic cookie preceding the DHCP options.
+var dhcpMagic = []byte{99, 130, 83, 99}
+
+// dhcpPacket is a DHCP message.
+type dhcpPacket struct {
+	op      byte
+	xid     uint32
+	flags   uint16
+	ciaddr  net.IP
+	yiaddr  net.IP
+	siaddr  net.IP
+	giaddr  net.IP
+	chaddr  net.HardwareAddr
+	file    string
+	options map[byte][]byte
+}
+
+// parseDHCP decodes a DHCP message.
+func parseDHCP(b []byte) (*dhcpPacket, error) {
+	if len(b) < dhcpHeaderLength+len(dhcpMagic) {
+		return nil, fmt.Errorf("short DHCP message of %d bytes", len(b))
+	}
+	if !bytes.Equal(b[dhcpHeaderLength:dhcpHeaderLength+4], dhcpMagic) {
+		return nil, fmt.Errorf("missing DHCP magic cookie")
+	}
+
+	hlen := int(b[2])
+	if hlen > 16 {
+		return nil, fmt.Errorf("invalid hardware address length %d", hlen)
+	}
+
+	p := &dhcpPacket{
+		op:      b[0],
+		xid:     binary.BigEndian.Uint32(b[4:8]),
+		flags:   binary.BigEndian.Uint16(b[10:12]),
+		ciaddr:  net.IP(append([]byte(nil), b[12:16]...)),
+		yiaddr:  net.IP(append([]byte(nil), b[16:20]...)),
+		siaddr:  net.IP(append([]byte(nil), b[20:24]...)),
+		giaddr:  net.IP(append([]byte(nil), b[24:28]...)),
+		chaddr:  net.HardwareAddr(append([]byte(nil), b[28:28+hlen]...)),
+		file:    string(bytes.TrimRight(b[108:236], "\x00")),
+		options: map[byte][]byte{},
+	}
+
+	opts := b[dhcpHeaderLength+4:]
+	for len(opts) > 0 {
+		code := opts[0]
+		if code == optEnd {
+			break
+		}
+		if code == optPad {
+			opts = opts[1:]
+			continue
+		}
+		if len(opts) < 2 || len(opts) < 2+int(opts[1]) {
+			return nil, fmt.Errorf("truncated DHCP option %d", code)
+		}
+		p.options[code] = append(p.options[code], opts[2:2+opts[1]]...)
+		opts = opts[2+opts[1]:]
+	}
+
+	return p, nil
+}
+
+// marshal encodes the DHCP message, the options are written in the order of their codes.
+func (p *dhcpPacket) marshal() []byte {
+	b := make([]byte, dhcpHeaderLength, dhcpHeaderLength+64)
+	b[0] = p.op
+	b[1] = 1 // ethernet
+	b[2] = byte(len(p.chaddr))
+	binary.BigEndian.PutUint32(b[4:8], p.xid)
+	binary.BigEndian.PutUint16(b[10:12], p.flags)
+	copy(b[12:16], p.ciaddr.To4())
+	copy(b[16:20], p.yiaddr.To4())
+	copy(b[20:24], p.siaddr.To4())
+	copy(b[24:28], p.giaddr.To4())
+	copy(b[28:44], p.chaddr)
+	copy(b[108:236], p.file)
+
+	b = append(b, dhcpMagic...)
+	for code := range 255 {
+		v, ok := p.options[byte(code)]
+		if !ok {
+			continue
+		}
+		// long options are split in several options with the same code (RFC 3396)
+		for len(v) > 255 {
+			b = append(append(b, byte(code), 255), v[:255]...)
+			v = v[255:]
+		}
+		b = append(append(b, byte(code), byte(len(v))), v...)
+	}
+
+	return append(b, optEnd)
+}
+
+// messageType returns the DHCP message type, 0 when not set.
+func (p *dhcpPacket) messageType() byte {
+	if v := p.options[optMessageType]; len(v) == 1 {
+		return v[0]
+	}
+	return 0
+}
+
+// reply returns the reply to the DHCP message of a ZTP node, an offer to a discover
+// and an ack to a request. Nil is returned for the other messages and the unknown clients.
+func (s *Server) reply(req *dhcpPacket) (*dhcpPacket, *Entry) {
+	if req.op != bootRequest {
+		return nil, nil
+	}
+
+	var msgType byte
+	switch req.messageType() {
+	case dhcpDiscover:
+		msgType = dhcpOffer
+	case dhcpRequest:
+		msgType = dhcpAck
+	default:
+		return nil, nil
+	}
+
+	e := s.lookup(req.chaddr, string(req.options[optClientID]), string(req.options[optVendorClass]))
+	if e == nil || e.IP == nil {
+		return nil, nil
+	}
+
+	lease := make([]byte, 4)
+	binary.BigEndian.PutUint32(lease, uint32(s.Lease/time.Second))
+
+	rsp := &dhcpPacket{
+		op:     bootReply,
+		xid:    req.xid,
+		flags:  req.flags,
+		ciaddr: req.ciaddr,
+		yiaddr: e.IP.To4(),
+		siaddr: s.ServerIP.To4(),
+		giaddr: req.giaddr,
+		chaddr: req.chaddr,
+		file:   e.Node + "/" + e.Boot,
+		options: map[byte][]byte{
+			optMessageType:  {msgType},
+			optServerID:     s.ServerIP.To4(),
+			optLeaseTime:    lease,
+			optSubnetMask:   s.Mask,
+			optHostName:     []byte(e.Node),
+			optTFTPServer:   []byte(s.ServerIP.String()),
+			optBootFileName: []byte(s.URL(e, e.Boot)),
+		},
+	}
+
+	if s.Router != nil {
+		rsp.options[optRouter] = s.Router.To4()
+	}
+
+	return rsp, e
+}
+
+// replyAddr returns the address the reply to the message is sent to.
+func replyAddr(req *dhcpPacket) *net.UDPAddr {
+	switch {
+	case !req.giaddr.IsUnspecified():
+		return &net.UDPAddr{IP: req.giaddr, Port: 67}
+	case !req.ciaddr.IsUnspecified():
+		return &net.UDPAddr{IP: req.ciaddr, Port: 68}
+	}
+
+	return &net.UDPAddr{IP: net.IPv4bcast, Port: 68}
+}
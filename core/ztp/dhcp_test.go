@@ -0,0 +1,162 @@
+package ztp
+
+import (
+	"net"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func newTestServer() *Server {
+	mac, _ := net.ParseMAC("aa:c1:ab:00:00:01")
+
+	s := NewServer("br-clab", net.ParseIP("172.20.20.1"), net.CIDRMask(24, 32), []*Entry{
+		{
+			Node:  "srl1",
+			MAC:   mac,
+			IP:    net.ParseIP("172.20.20.2"),
+			Files: map[string][]byte{FileConfig: []byte("set / system name host-name srl1\n")},
+			Boot:  FileConfig,
+		},
+		{
+			Node:   "ceos1",
+			Serial: "JPE12345678",
+			IP:     net.ParseIP("172.20.20.3"),
+			Files:  map[string][]byte{FileScript: []byte("#!/usr/bin/env python\n")},
+			Boot:   FileScript,
+		},
+	})
+	s.Router = s.ServerIP
+
+	return s
+}
+
+func TestDHCPReply(t *testing.T) {
+	s := newTestServer()
+
+	tests := map[string]struct {
+		msgType  byte
+		mac      string
+		clientID string
+		node     string
+		reply    byte
+		boot     string
+	}{
+		"discover by mac": {
+			msgType: dhcpDiscover,
+			mac:     "aa:c1:ab:00:00:01",
+			node:    "srl1",
+			reply:   dhcpOffer,
+			boot:    "http://172.20.20.1:8080/srl1/config",
+		},
+		"request by serial": {
+			msgType:  dhcpRequest,
+			mac:      "00:1c:73:00:00:99",
+			clientID: "\x00JPE12345678",
+			node:     "ceos1",
+			reply:    dhcpAck,
+			boot:     "http://172.20.20.1:8080/ceos1/script",
+		},
+		"unknown client": {
+			msgType: dhcpDiscover,
+			mac:     "00:1c:73:00:00:99",
+		},
+		"release": {
+			msgType: 7,
+			mac:     "aa:c1:ab:00:00:01",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mac, _ := net.ParseMAC(tc.mac)
+			req := &dhcpPacket{
+				op:      bootRequest,
+				xid:     0x1234,
+				flags:   flagBroadcast,
+				ciaddr:  net.IPv4zero,
+				yiaddr:  net.IPv4zero,
+				siaddr:  net.IPv4zero,
+				giaddr:  net.IPv4zero,
+				chaddr:  mac,
+				options: map[byte][]byte{optMessageType: {tc.msgType}},
+			}
+			if tc.clientID != "" {
+				req.options[optClientID] = []byte(tc.clientID)
+			}
+
+			// the request goes through the wire format
+			req, err := parseDHCP(req.marshal())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rsp, e := s.reply(req)
+			if tc.node == "" {
+				if rsp != nil {
+					t.Fatalf("unexpected reply to %s", e.Node)
+				}
+				return
+			}
+			if rsp == nil {
+				t.Fatal("no reply")
+			}
+
+			rsp, err = parseDHCP(rsp.marshal())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := map[string]string{
+				"node":   e.Node,
+				"yiaddr": rsp.yiaddr.String(),
+				"siaddr": rsp.siaddr.String(),
+				"file":   rsp.file,
+				"boot":   string(rsp.options[optBootFileName]),
+				"tftp":   string(rsp.options[optTFTPServer]),
+				"router": net.IP(rsp.options[optRouter]).String(),
+				"mask":   net.IP(rsp.options[optSubnetMask]).String(),
+			}
+			want := map[string]string{
+				"node":   tc.node,
+				"yiaddr": e.IP.String(),
+				"siaddr": "172.20.20.1",
+				"file":   tc.node + "/" + e.Boot,
+				"boot":   tc.boot,
+				"tftp":   "172.20.20.1",
+				"router": "172.20.20.1",
+				"mask":   "255.255.255.0",
+			}
+			if d := cmp.Diff(want, got); d != "" {
+				t.Errorf("reply mismatch (-want +got):\n%s", d)
+			}
+			if rsp.messageType() != tc.reply || rsp.xid != req.xid {
+				t.Errorf("got message type %d xid %x, want %d xid %x", rsp.messageType(), rsp.xid, tc.reply, req.xid)
+			}
+		})
+	}
+}
+
+func TestParseDHCPInvalid(t *testing.T) {
+	if _, err := parseDHCP(make([]byte, 100)); err == nil {
+		t.Error("expected an error for a short message")
+	}
+
+	b := (&dhcpPacket{op: bootRequest, options: map[byte][]byte{optHostName: []byte("srl1")}}).marshal()
+	// truncate the host name option
+	if _, err := parseDHCP(b[:len(b)-3]); err == nil {
+		t.Error("expected an error for a truncated option")
+	}
+}
+
+func TestReplyAddr(t *testing.T) {
+	req := &dhcpPacket{ciaddr: net.IPv4zero, giaddr: net.IPv4zero}
+	if got := replyAddr(req).String(); got != "255.255.255.255:68" {
+		t.Errorf("got %s, want the broadcast address", got)
+	}
+
+	req.giaddr = net.ParseIP("10.0.0.1").To4()
+	if got := replyAddr(req).String(); got != "10.0.0.1:67" {
+		t.Errorf("got %s, want the relay address", got)
+	}
+}
@@ -0,0 +1,175 @@
+package ztp
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"slices"
+	"text/template"
+
+	clabcore "github.com/srl-labs/containerlab/core"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// Node labels enabling ZTP for a node.
+const (
+	// LabelMAC is the MAC address of the management interface the node sends the DHCP messages from
+	LabelMAC = "ztp.mac"
+	// LabelSerial is the serial number the node sends as the DHCP client identifier or vendor class
+	LabelSerial = "ztp.serial"
+	// LabelConfig is the path of the day-0 config template, the startup-config of the node by default
+	LabelConfig = "ztp.config"
+	// LabelScript is the path of the day-0 script template, announced as the boot file when set
+	LabelScript = "ztp.script"
+)
+
+// templateData is the data the day-0 templates are rendered with.
+type templateData struct {
+	Lab  string
+	Name string
+	Kind string
+	// IP is the management address of the node, Prefix the length of the management subnet
+	IP     string
+	Prefix int
+	// Server is the address of the ZTP server, URL the base URL of the files of the node
+	Server string
+	URL    string
+}
+
+// NewLabServer returns the ZTP server of the deployed lab, serving the nodes with ZTP labels
+// on the management bridge of the lab.
+func NewLabServer(ctx context.Context, c *clabcore.CLab, httpPort int) (*Server, error) {
+	bridge := c.Config.Mgmt.Bridge
+
+	v4addrs, _, err := clabutils.LinkIPs(bridge)
+	if err != nil {
+		return nil, err
+	}
+	if len(v4addrs) == 0 {
+		return nil, fmt.Errorf("management bridge %s has no IPv4 address", bridge)
+	}
+
+	s := NewServer(bridge, v4addrs[0].IP, v4addrs[0].Mask, nil)
+	s.Router = v4addrs[0].IP
+	s.HTTPPort = httpPort
+
+	names := make([]string, 0, len(c.Nodes))
+	for name := range c.Nodes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		e, err := s.nodeEntry(ctx, c, name)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if e != nil {
+			s.entries = append(s.entries, e)
+		}
+	}
+
+	if len(s.entries) == 0 {
+		return nil, fmt.Errorf("no node of the lab has the %s or %s label", LabelMAC, LabelSerial)
+	}
+
+	return s, nil
+}
+
+// nodeEntry returns the entry of the node, nil when the node has no ZTP labels.
+func (s *Server) nodeEntry(ctx context.Context, c *clabcore.CLab, name string) (*Entry, error) {
+	n := c.Nodes[name]
+	cfg := n.Config()
+
+	e := &Entry{Node: name, Serial: cfg.Labels[LabelSerial], Files: map[string][]byte{}}
+
+	if v, ok := cfg.Labels[LabelMAC]; ok {
+		mac, err := net.ParseMAC(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s label: %w", LabelMAC, err)
+		}
+		e.MAC = mac
+	}
+
+	if e.MAC == nil && e.Serial == "" {
+		return nil, nil
+	}
+
+	containers, err := n.GetContainers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for idx := range containers {
+		if ip := net.ParseIP(containers[idx].NetworkSettings.IPv4addr); ip != nil {
+			e.IP = ip
+			break
+		}
+	}
+	if e.IP == nil {
+		return nil, fmt.Errorf("no IPv4 management address, is the lab deployed?")
+	}
+
+	prefix, _ := s.Mask.Size()
+	data := &templateData{
+		Lab:    c.Config.Name,
+		Name:   name,
+		Kind:   cfg.Kind,
+		IP:     e.IP.String(),
+		Prefix: prefix,
+		Server: s.ServerIP.String(),
+	}
+	data.URL = s.URL(e, "")
+
+	sources := map[string]string{
+		FileConfig: cfg.Labels[LabelConfig],
+		FileScript: cfg.Labels[LabelScript],
+	}
+	if sources[FileConfig] == "" {
+		sources[FileConfig] = cfg.StartupConfig
+	}
+
+	for file, path := range sources {
+		if path == "" {
+			continue
+		}
+
+		b, err := renderFile(clabutils.ResolvePath(path, c.TopoPaths.TopologyFileDir()), data)
+		if err != nil {
+			return nil, err
+		}
+		e.Files[file] = b
+	}
+
+	switch {
+	case e.Files[FileScript] != nil:
+		e.Boot = FileScript
+	case e.Files[FileConfig] != nil:
+		e.Boot = FileConfig
+	default:
+		return nil, fmt.Errorf("no day-0 config, set the startup-config or the %s label", LabelConfig)
+	}
+
+	return e, nil
+}
+
+// renderFile renders the template file with the data.
+func renderFile(path string, data *templateData) ([]byte, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	t, err := template.New(path).Option("missingkey=error").Parse(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := t.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
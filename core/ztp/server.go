@@ -0,0 +1,240 @@
+// Package ztp serves the day-0 configs and scripts of the lab nodes on the management network,
+// over DHCP, TFTP and HTTP, for the kinds bootstrapping with zero-touch provisioning.
+package ztp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"golang.org/x/sys/unix"
+)
+
+// Day-0 files of a node.
+const (
+	FileConfig = "config"
+	FileScript = "script"
+)
+
+const (
+	// DefaultHTTPPort is the default port of the HTTP server.
+	DefaultHTTPPort = 8080
+	// DefaultLease is the default lease time of the DHCP addresses.
+	DefaultLease = time.Hour
+)
+
+// Entry is a node served by the ZTP server, identified by the MAC address
+// of its management interface or its serial number.
+type Entry struct {
+	Node   string
+	MAC    net.HardwareAddr
+	Serial string
+	// IP is the management address of the node
+	IP net.IP
+	// Files are the day-0 files of the node by name, FileConfig and FileScript
+	Files map[string][]byte
+	// Boot is the name of the file announced as the boot file
+	Boot string
+}
+
+// Server is the ZTP server of the lab management network.
+type Server struct {
+	// Interface is the management bridge the DHCP messages are received on
+	Interface string
+	// ServerIP is the address of the server on the management network
+	ServerIP net.IP
+	Mask     net.IPMask
+	// Router is the default gateway announced to the nodes, none when nil
+	Router   net.IP
+	HTTPPort int
+	Lease    time.Duration
+
+	entries []*Entry
+}
+
+// NewServer returns the ZTP server of the entries.
+func NewServer(iface string, serverIP net.IP, mask net.IPMask, entries []*Entry) *Server {
+	return &Server{
+		Interface: iface,
+		ServerIP:  serverIP,
+		Mask:      mask,
+		HTTPPort:  DefaultHTTPPort,
+		Lease:     DefaultLease,
+		entries:   entries,
+	}
+}
+
+// Entries returns the entries of the server.
+func (s *Server) Entries() []*Entry {
+	return s.entries
+}
+
+// URL returns the HTTP URL of the file of the node.
+func (s *Server) URL(e *Entry, file string) string {
+	host := net.JoinHostPort(s.ServerIP.String(), strconv.Itoa(s.HTTPPort))
+	return fmt.Sprintf("http://%s/%s/%s", host, e.Node, file)
+}
+
+// lookup returns the entry of the client identified by its MAC address,
+// or by its serial number sent as the client identifier or in the vendor class.
+func (s *Server) lookup(mac net.HardwareAddr, clientID, vendorClass string) *Entry {
+	for _, e := range s.entries {
+		if e.MAC != nil && mac.String() == e.MAC.String() {
+			return e
+		}
+	}
+
+	for _, e := range s.entries {
+		if e.Serial == "" {
+			continue
+		}
+		// the client identifier may be prefixed with a type byte
+		if strings.HasSuffix(clientID, e.Serial) || strings.Contains(vendorClass, e.Serial) {
+			return e
+		}
+	}
+
+	return nil
+}
+
+// file returns the entry and the content of a file requested as <node>/<file>.
+func (s *Server) file(name string) (*Entry, []byte) {
+	node, file, ok := strings.Cut(name, "/")
+	if !ok {
+		return nil, nil
+	}
+
+	for _, e := range s.entries {
+		if e.Node != node {
+			continue
+		}
+		if data, ok := e.Files[file]; ok {
+			return e, data
+		}
+	}
+
+	return nil, nil
+}
+
+// ServeHTTP serves the day-0 files of the nodes at /<node>/<file>.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	e, data := s.file(strings.TrimPrefix(r.URL.Path, "/"))
+	if e == nil {
+		log.Warn("HTTP file not found", "path", r.URL.Path, "client", r.RemoteAddr)
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	_, _ = w.Write(data)
+
+	log.Info("Served day-0 file", "node", e.Node, "file", r.URL.Path, "protocol", "http")
+}
+
+// Serve runs the DHCP, TFTP and HTTP servers until the context is done or one of them fails.
+func (s *Server) Serve(ctx context.Context) error {
+	lc := net.ListenConfig{Control: func(_, _ string, c syscall.RawConn) error {
+		var serr error
+		err := c.Control(func(fd uintptr) {
+			serr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_REUSEADDR, 1)
+			if serr == nil {
+				serr = unix.SetsockoptInt(int(fd), unix.SOL_SOCKET, unix.SO_BROADCAST, 1)
+			}
+			if serr == nil {
+				serr = unix.BindToDevice(int(fd), s.Interface)
+			}
+		})
+		if err != nil {
+			return err
+		}
+		return serr
+	}}
+
+	dhcpConn, err := lc.ListenPacket(ctx, "udp4", ":67")
+	if err != nil {
+		return fmt.Errorf("failed to listen for DHCP on %s: %w", s.Interface, err)
+	}
+	defer dhcpConn.Close()
+
+	tftpConn, err := net.ListenPacket("udp4", net.JoinHostPort(s.ServerIP.String(), "69"))
+	if err != nil {
+		return fmt.Errorf("failed to listen for TFTP: %w", err)
+	}
+	defer tftpConn.Close()
+
+	httpSrv := &http.Server{
+		Addr:              net.JoinHostPort(s.ServerIP.String(), strconv.Itoa(s.HTTPPort)),
+		Handler:           s,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 3)
+	go func() { errCh <- s.serveDHCP(dhcpConn) }()
+	go func() { errCh <- s.serveTFTP(tftpConn) }()
+	go func() {
+		err := httpSrv.ListenAndServe()
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		}
+		errCh <- err
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+	}
+
+	_ = httpSrv.Close()
+
+	return err
+}
+
+// serveDHCP answers the DHCP messages received on the connection until it is closed.
+func (s *Server) serveDHCP(conn net.PacketConn) error {
+	buf := make([]byte, 1500)
+
+	for {
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		req, err := parseDHCP(buf[:n])
+		if err != nil {
+			log.Debugf("ignoring an invalid DHCP message: %v", err)
+			continue
+		}
+
+		rsp, e := s.reply(req)
+		if rsp == nil {
+			log.Debug("Ignoring DHCP message of an unknown client", "mac", req.chaddr)
+			continue
+		}
+
+		if _, err := conn.WriteTo(rsp.marshal(), replyAddr(req)); err != nil {
+			log.Warnf("failed to send the DHCP reply to %s: %v", e.Node, err)
+			continue
+		}
+
+		kind := "offer"
+		if rsp.messageType() == dhcpAck {
+			kind = "ack"
+		}
+		log.Info("Sent DHCP "+kind, "node", e.Node, "mac", req.chaddr, "ip", e.IP, "boot", s.URL(e, e.Boot))
+	}
+}
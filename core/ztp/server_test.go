@@ -0,0 +1,123 @@
+package ztp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestServeHTTP(t *testing.T) {
+	srv := httptest.NewServer(newTestServer())
+	defer srv.Close()
+
+	tests := map[string]struct {
+		path   string
+		status int
+		body   string
+	}{
+		"config":        {path: "/srl1/config", status: http.StatusOK, body: "set / system name host-name srl1\n"},
+		"script":        {path: "/ceos1/script", status: http.StatusOK, body: "#!/usr/bin/env python\n"},
+		"missing file":  {path: "/srl1/script", status: http.StatusNotFound},
+		"unknown node":  {path: "/srl9/config", status: http.StatusNotFound},
+		"no file given": {path: "/srl1", status: http.StatusNotFound},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			rsp, err := http.Get(srv.URL + tc.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rsp.Body.Close()
+
+			if rsp.StatusCode != tc.status {
+				t.Fatalf("got status %d, want %d", rsp.StatusCode, tc.status)
+			}
+			if tc.status != http.StatusOK {
+				return
+			}
+
+			b, _ := io.ReadAll(rsp.Body)
+			if string(b) != tc.body {
+				t.Errorf("got body %q, want %q", b, tc.body)
+			}
+		})
+	}
+}
+
+// TestTFTP fetches a file of several blocks from the TFTP server.
+func TestTFTP(t *testing.T) {
+	s := newTestServer()
+	s.ServerIP = net.ParseIP("127.0.0.1")
+	data := bytes.Repeat([]byte("0123456789abcdef"), 64) // two full blocks and an empty one
+	s.entries[0].Files[FileConfig] = data
+
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go s.serveTFTP(conn)
+	defer conn.Close()
+
+	client, err := net.ListenUDP("udp4", &net.UDPAddr{IP: s.ServerIP})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	rrq := binary.BigEndian.AppendUint16(nil, tftpRRQ)
+	rrq = append(rrq, "/srl1/config\x00octet\x00"...)
+	if _, err := client.WriteTo(rrq, conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []byte
+	buf := make([]byte, 1500)
+	for block := uint16(1); ; block++ {
+		_ = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+		n, addr, err := client.ReadFrom(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if op := binary.BigEndian.Uint16(buf); op != tftpData {
+			t.Fatalf("got opcode %d, want data", op)
+		}
+		if b := binary.BigEndian.Uint16(buf[2:]); b != block {
+			t.Fatalf("got block %d, want %d", b, block)
+		}
+		got = append(got, buf[4:n]...)
+
+		ack := binary.BigEndian.AppendUint16(nil, tftpAck)
+		ack = binary.BigEndian.AppendUint16(ack, block)
+		if _, err := client.WriteTo(ack, addr); err != nil {
+			t.Fatal(err)
+		}
+
+		if n-4 < tftpBlockSize {
+			break
+		}
+	}
+
+	if !bytes.Equal(got, data) {
+		t.Errorf("got %d bytes, want %d", len(got), len(data))
+	}
+
+	// a missing file is answered with an error
+	rrq = binary.BigEndian.AppendUint16(nil, tftpRRQ)
+	rrq = append(rrq, "srl1/script\x00octet\x00"...)
+	if _, err := client.WriteTo(rrq, conn.LocalAddr()); err != nil {
+		t.Fatal(err)
+	}
+	_ = client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if _, _, err := client.ReadFrom(buf); err != nil {
+		t.Fatal(err)
+	}
+	if op := binary.BigEndian.Uint16(buf); op != tftpError {
+		t.Errorf("got opcode %d, want error", op)
+	}
+}
@@ -0,0 +1,132 @@
+package ztp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+// This file contains a minimal read-only TFTP (RFC 1350) server
+// for the ZTP nodes fetching their day-0 files with TFTP.
+
+// TFTP opcodes.
+const (
+	tftpRRQ   = 1
+	tftpData  = 3
+	tftpAck   = 4
+	tftpError = 5
+)
+
+const (
+	tftpBlockSize = 512
+	// tftpTimeout is the time waiting for the ack of a block before sending it again.
+	tftpTimeout = 2 * time.Second
+	tftpRetries = 5
+	// tftpErrNotFound is the TFTP error code of a missing file.
+	tftpErrNotFound = 1
+)
+
+// serveTFTP answers the read requests received on the connection until it is closed,
+// each transfer is sent from its own connection as the protocol requires.
+func (s *Server) serveTFTP(conn net.PacketConn) error {
+	buf := make([]byte, 1500)
+
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+
+		name, ok := parseRRQ(buf[:n])
+		if !ok {
+			continue
+		}
+
+		go s.sendTFTP(addr, name)
+	}
+}
+
+// parseRRQ returns the file name of a read request.
+func parseRRQ(b []byte) (string, bool) {
+	if len(b) < 4 || binary.BigEndian.Uint16(b) != tftpRRQ {
+		return "", false
+	}
+
+	name, _, ok := bytes.Cut(b[2:], []byte{0})
+	if !ok || len(name) == 0 {
+		return "", false
+	}
+
+	return strings.TrimPrefix(string(name), "/"), true
+}
+
+// sendTFTP sends the file to the client, block by block waiting for the acks.
+func (s *Server) sendTFTP(addr net.Addr, name string) {
+	conn, err := net.ListenUDP("udp4", &net.UDPAddr{IP: s.ServerIP})
+	if err != nil {
+		log.Warnf("failed to open the TFTP transfer connection: %v", err)
+		return
+	}
+	defer conn.Close()
+
+	e, data := s.file(name)
+	if e == nil {
+		pkt := binary.BigEndian.AppendUint16(nil, tftpError)
+		pkt = binary.BigEndian.AppendUint16(pkt, tftpErrNotFound)
+		pkt = append(append(pkt, "file not found"...), 0)
+		_, _ = conn.WriteTo(pkt, addr)
+
+		log.Warn("TFTP file not found", "file", name, "client", addr)
+		return
+	}
+
+	ack := make([]byte, 4)
+
+	// the last block is shorter than the block size, empty when the size is a multiple of it
+	for block := 1; ; block++ {
+		start := min((block-1)*tftpBlockSize, len(data))
+		end := min(start+tftpBlockSize, len(data))
+
+		pkt := binary.BigEndian.AppendUint16(nil, tftpData)
+		pkt = binary.BigEndian.AppendUint16(pkt, uint16(block))
+		pkt = append(pkt, data[start:end]...)
+
+		acked := false
+		for range tftpRetries {
+			if _, err := conn.WriteTo(pkt, addr); err != nil {
+				log.Warnf("failed to send the TFTP block %d of %s: %v", block, name, err)
+				return
+			}
+
+			_ = conn.SetReadDeadline(time.Now().Add(tftpTimeout))
+			n, _, err := conn.ReadFrom(ack)
+			if err != nil {
+				continue
+			}
+			if n == 4 && binary.BigEndian.Uint16(ack) == tftpAck &&
+				binary.BigEndian.Uint16(ack[2:]) == uint16(block) {
+				acked = true
+				break
+			}
+		}
+
+		if !acked {
+			log.Warn("TFTP transfer timed out", "node", e.Node, "file", name, "block", block)
+			return
+		}
+
+		if end-start < tftpBlockSize {
+			break
+		}
+	}
+
+	log.Info("Served day-0 file", "node", e.Node, "file", name, "protocol", "tftp")
+}
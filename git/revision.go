@@ -0,0 +1,203 @@
+package git
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// shortCommitLen is the length of the abbreviated commit hashes.
+const shortCommitLen = 12
+
+// Revision is the git revision of the files of a lab.
+type Revision struct {
+	Commit string `json:"commit"`
+	Branch string `json:"branch,omitempty"`
+	// Dirty is true when the lab files have changes not committed.
+	Dirty bool `json:"dirty,omitempty"`
+}
+
+// String returns the abbreviated commit of the revision, marked as dirty with uncommitted changes.
+func (r *Revision) String() string {
+	if r == nil {
+		return ""
+	}
+
+	s := r.Commit
+	if len(s) > shortCommitLen {
+		s = s[:shortCommitLen]
+	}
+
+	if r.Dirty {
+		s += "-dirty"
+	}
+
+	return s
+}
+
+// openRepo opens the git repository the path is in and returns it with its root directory.
+func openRepo(path string) (*gogit.Repository, string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	r, err := gogit.PlainOpenWithOptions(abs, &gogit.PlainOpenOptions{DetectDotGit: true})
+	if err != nil {
+		return nil, "", err
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		return nil, "", err
+	}
+
+	return r, wt.Filesystem.Root(), nil
+}
+
+// LabRevision returns the revision of the git repository the first path is in, the revision
+// being dirty when one of the paths has uncommitted changes. Nil is returned when the path
+// is not in a git repository or the repository has no commit.
+func LabRevision(paths ...string) *Revision {
+	if len(paths) == 0 {
+		return nil
+	}
+
+	r, root, err := openRepo(paths[0])
+	if err != nil {
+		return nil
+	}
+
+	head, err := r.Head()
+	if err != nil {
+		return nil
+	}
+
+	rev := &Revision{Commit: head.Hash().String()}
+	if head.Name().IsBranch() {
+		rev.Branch = head.Name().Short()
+	}
+
+	wt, err := r.Worktree()
+	if err != nil {
+		return rev
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return rev
+	}
+
+	var prefixes []string
+	for _, p := range paths {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			continue
+		}
+
+		rel, err := filepath.Rel(root, abs)
+		if err != nil || strings.HasPrefix(rel, "..") {
+			continue
+		}
+
+		prefixes = append(prefixes, filepath.ToSlash(rel))
+	}
+
+	for f, s := range status {
+		if s.Worktree == gogit.Unmodified && s.Staging == gogit.Unmodified {
+			continue
+		}
+
+		for _, p := range prefixes {
+			if p == "." || f == p || strings.HasPrefix(f, p+"/") {
+				rev.Dirty = true
+				return rev
+			}
+		}
+	}
+
+	return rev
+}
+
+// RepoRoot returns the root directory of the git repository the path is in.
+func RepoRoot(path string) (string, error) {
+	_, root, err := openRepo(path)
+	if errors.Is(err, gogit.ErrRepositoryNotExists) {
+		return "", fmt.Errorf("%s is not in a git repository", path)
+	}
+
+	return root, err
+}
+
+// ExtractRevision writes the files of the git repository the path is in, at the revision ref,
+// to the directory and returns the root directory of the repository.
+// The ref is a commit, a branch, a tag or a revision expression, such as HEAD~2.
+func ExtractRevision(path, ref, dir string) (string, error) {
+	r, root, err := openRepo(path)
+	if err != nil {
+		if errors.Is(err, gogit.ErrRepositoryNotExists) {
+			return "", fmt.Errorf("%s is not in a git repository", path)
+		}
+
+		return "", err
+	}
+
+	h, err := r.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve revision %q: %w", ref, err)
+	}
+
+	commit, err := r.CommitObject(*h)
+	if err != nil {
+		return "", err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return "", err
+	}
+
+	err = tree.Files().ForEach(func(f *object.File) error {
+		if !f.Mode.IsFile() {
+			return nil
+		}
+
+		return writeFile(f, filepath.Join(dir, filepath.FromSlash(f.Name)))
+	})
+
+	return root, err
+}
+
+func writeFile(f *object.File, p string) error {
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil { // skipcq: GSC-G301
+		return err
+	}
+
+	mode, err := f.Mode.ToOSFileMode()
+	if err != nil {
+		return err
+	}
+
+	rd, err := f.Reader()
+	if err != nil {
+		return err
+	}
+	defer rd.Close()
+
+	w, err := os.OpenFile(p, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode.Perm())
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	_, err = io.Copy(w, rd)
+
+	return err
+}
@@ -0,0 +1,137 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/google/go-cmp/cmp"
+)
+
+// commitFiles writes the files to the worktree of the repository and commits them.
+func commitFiles(t *testing.T, r *gogit.Repository, root string, files map[string]string) {
+	t.Helper()
+
+	wt, err := r.Worktree()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, content := range files {
+		p := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := wt.Add(name); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = wt.Commit("update", &gogit.CommitOptions{
+		Author: &object.Signature{Name: "clab", Email: "clab@example.com", When: time.Now()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLabRevision(t *testing.T) {
+	root := t.TempDir()
+
+	r, err := gogit.PlainInit(root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	topo := filepath.Join(root, "lab", "lab.clab.yml")
+	templates := filepath.Join(root, "templates")
+
+	if rev := LabRevision(topo); rev != nil {
+		t.Errorf("expected no revision without a commit, got %+v", rev)
+	}
+
+	commitFiles(t, r, root, map[string]string{
+		"lab/lab.clab.yml":         "name: lab\n",
+		"templates/base__srl.tmpl": "set / system name host-name {{ .clab_node }}\n",
+		"docs/README.md":           "lab\n",
+	})
+
+	head, err := r.Head()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rev := LabRevision(topo, templates)
+	want := &Revision{Commit: head.Hash().String(), Branch: "master"}
+	if d := cmp.Diff(want, rev); d != "" {
+		t.Errorf("revision mismatch (-want +got):\n%s", d)
+	}
+
+	// the changes of the files other than the lab files don't make the revision dirty
+	if err := os.WriteFile(filepath.Join(root, "docs", "README.md"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if rev := LabRevision(topo, templates); rev.Dirty {
+		t.Error("expected a clean revision with changes outside of the lab files")
+	}
+
+	if err := os.WriteFile(filepath.Join(templates, "base__srl.tmpl"), []byte("changed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	rev = LabRevision(topo, templates)
+	if !rev.Dirty {
+		t.Error("expected a dirty revision with changed templates")
+	}
+	if got, want := rev.String(), head.Hash().String()[:shortCommitLen]+"-dirty"; got != want {
+		t.Errorf("got revision %q, want %q", got, want)
+	}
+
+	if rev := LabRevision(filepath.Join(t.TempDir(), "lab.clab.yml")); rev != nil {
+		t.Errorf("expected no revision outside of a repository, got %+v", rev)
+	}
+}
+
+func TestExtractRevision(t *testing.T) {
+	root := t.TempDir()
+
+	r, err := gogit.PlainInit(root, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	commitFiles(t, r, root, map[string]string{"lab/lab.clab.yml": "name: v1\n"})
+	commitFiles(t, r, root, map[string]string{"lab/lab.clab.yml": "name: v2\n"})
+
+	dir := t.TempDir()
+
+	got, err := ExtractRevision(filepath.Join(root, "lab", "lab.clab.yml"), "HEAD~1", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if gotRoot, _ := filepath.EvalSymlinks(got); gotRoot != wantRoot {
+		t.Errorf("got repository root %q, want %q", got, root)
+	}
+
+	b, err := os.ReadFile(filepath.Join(dir, "lab", "lab.clab.yml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d := cmp.Diff("name: v1\n", string(b)); d != "" {
+		t.Errorf("extracted file mismatch (-want +got):\n%s", d)
+	}
+
+	if _, err := ExtractRevision(filepath.Join(root, "lab"), "no-such-ref", t.TempDir()); err == nil {
+		t.Error("expected an error for an unknown revision")
+	}
+}
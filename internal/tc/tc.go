@@ -156,3 +156,133 @@ func Impairments(tcnl *tc.Tc) ([]tc.Object, error) {
 
 	return qdiscs, nil
 }
+
+// shapingHandle is the major handle of the tbf qdisc shaping the egress rate of an interface.
+const shapingHandle = 0x2
+
+// defaultShapingBurst is the minimal burst of the tbf qdisc in bytes,
+// larger than the MTU of the containerlab links.
+const defaultShapingBurst = 32 * 1024
+
+// SetShaping sets or changes the egress rate shaping of the interface with a tbf qdisc.
+// The rate is provided in kbit, the burst in bytes, defaulting to 10ms of the rate,
+// and the latency is the maximum time a packet waits in the queue.
+// The tbf qdisc is attached under the netem impairments of the interface when they are set.
+func SetShaping(tcnl *tc.Tc, link *net.Interface, rate uint64, burst uint32, latency time.Duration) (*tc.Object, error) {
+	err := tcnl.SetOption(netlink.ExtendedAcknowledge, true)
+	if err != nil {
+		return nil, fmt.Errorf("could not set option ExtendedAcknowledge: %v", err)
+	}
+
+	// convert to bytes
+	byteRate := rate * 1000 / 8
+	if byteRate == 0 || byteRate > math.MaxUint32 {
+		return nil, fmt.Errorf("rate must be between 1 and %d kbit", uint64(math.MaxUint32)*8/1000)
+	}
+
+	if burst == 0 {
+		burst = uint32(max(byteRate/100, defaultShapingBurst))
+	}
+	limit := uint32(byteRate*uint64(latency)/uint64(time.Second)) + burst
+
+	parent, err := shapingParent(tcnl, link)
+	if err != nil {
+		return nil, err
+	}
+
+	qdisc := tc.Object{
+		Msg: tc.Msg{
+			Family:  unix.AF_UNSPEC,
+			Ifindex: uint32(link.Index),
+			Handle:  core.BuildHandle(shapingHandle, 0x0),
+			Parent:  parent,
+			Info:    0,
+		},
+		Attribute: tc.Attribute{
+			Kind: "tbf",
+			Tbf: &tc.Tbf{
+				Parms: &tc.TbfQopt{
+					Rate:   tc.RateSpec{Rate: uint32(byteRate)},
+					Limit:  limit,
+					Buffer: core.XmitTime(byteRate, burst),
+				},
+				Burst: &burst,
+			},
+		},
+	}
+
+	err = tcnl.Qdisc().Replace(&qdisc)
+	if err != nil {
+		return nil, err
+	}
+
+	shaping, err := Shaping(tcnl, link)
+	if err != nil {
+		return nil, err
+	}
+	if shaping == nil {
+		return nil, fmt.Errorf("could not find shaping qdisc for interface %q", link.Name)
+	}
+
+	return shaping, nil
+}
+
+// shapingParent returns the parent of the tbf qdisc of the interface,
+// the class of the netem qdisc set as root qdisc, or the root.
+func shapingParent(tcnl *tc.Tc, link *net.Interface) (uint32, error) {
+	qdiscs, err := tcnl.Qdisc().Get()
+	if err != nil {
+		return 0, fmt.Errorf("could not get all qdiscs: %v", err)
+	}
+
+	for idx := range qdiscs {
+		q := &qdiscs[idx]
+		if q.Ifindex == uint32(link.Index) && q.Parent == tc.HandleRoot && q.Kind == "netem" {
+			return q.Handle + 1, nil
+		}
+	}
+
+	return tc.HandleRoot, nil
+}
+
+// Shaping returns the tbf qdisc shaping the interface, nil when the interface is not shaped.
+func Shaping(tcnl *tc.Tc, link *net.Interface) (*tc.Object, error) {
+	qdiscs, err := tcnl.Qdisc().Get()
+	if err != nil {
+		return nil, fmt.Errorf("could not get all qdiscs: %v", err)
+	}
+
+	for idx := range qdiscs {
+		if qdiscs[idx].Ifindex == uint32(link.Index) && qdiscs[idx].Kind == "tbf" &&
+			qdiscs[idx].Handle == core.BuildHandle(shapingHandle, 0x0) {
+			return &qdiscs[idx], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// DeleteShaping deletes the rate shaping from the given interface.
+// Deleting the shaping of an interface that is not shaped is not an error.
+func DeleteShaping(tcnl *tc.Tc, link *net.Interface) error {
+	shaping, err := Shaping(tcnl, link)
+	if err != nil || shaping == nil {
+		return err
+	}
+
+	qdisc := tc.Object{
+		Msg: tc.Msg{
+			Family:  unix.AF_UNSPEC,
+			Ifindex: uint32(link.Index),
+			Handle:  shaping.Handle,
+			Parent:  shaping.Parent,
+			Info:    0,
+		},
+		Attribute: tc.Attribute{
+			Kind: "tbf",
+			Tbf:  shaping.Tbf,
+		},
+	}
+
+	return tcnl.Qdisc().Delete(&qdisc)
+}
@@ -12,5 +12,6 @@ const (
 	TopoFile      = "clab-topo-file"
 	NodeMgmtNetBr = "clab-mgmt-net-bridge"
 	Owner         = "clab-owner"
+	Expires       = "clab-expires"
 	ToolType      = "tool-type"
 )
@@ -0,0 +1,66 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"time"
+
+	"github.com/containernetworking/plugins/pkg/ns"
+	gotc "github.com/florianl/go-tc"
+	clabinternaltc "github.com/srl-labs/containerlab/internal/tc"
+	"github.com/vishvananda/netlink"
+)
+
+// LinkShaping is the egress rate shaping of the interfaces of a link.
+type LinkShaping struct {
+	// Rate is the rate limit in kbit.
+	Rate uint64
+	// Burst is the size of the bucket in bytes, 0 for the default burst of the rate.
+	Burst uint32
+	// Latency is the maximum time a packet waits to be sent.
+	Latency time.Duration
+}
+
+// ShapeLink sets or changes the egress rate shaping of the interfaces of both endpoints
+// of the deployed veth link while the lab runs. A nil shaping removes the shaping of the link.
+func ShapeLink(ctx context.Context, l *LinkVEth, s *LinkShaping) error {
+	for _, ep := range l.GetEndpoints() {
+		err := ep.GetNode().ExecFunction(ctx, func(netns ns.NetNS) error {
+			return withEndpointTC(netns, ep, func(tcnl *gotc.Tc, iface *net.Interface) error {
+				if s == nil {
+					return clabinternaltc.DeleteShaping(tcnl, iface)
+				}
+
+				_, err := clabinternaltc.SetShaping(tcnl, iface, s.Rate, s.Burst, s.Latency)
+				return err
+			})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to shape endpoint %s: %w", ep, err)
+		}
+	}
+
+	return nil
+}
+
+// withEndpointTC runs f with a tc client of the network namespace and the interface of the endpoint.
+func withEndpointTC(netns ns.NetNS, ep Endpoint, f func(*gotc.Tc, *net.Interface) error) error {
+	link, err := netlink.LinkByName(SanitizeInterfaceName(ep.GetIfaceName()))
+	if err != nil {
+		return err
+	}
+
+	iface, err := net.InterfaceByName(link.Attrs().Name)
+	if err != nil {
+		return err
+	}
+
+	tcnl, err := clabinternaltc.NewTC(int(netns.Fd()))
+	if err != nil {
+		return err
+	}
+	defer tcnl.Close()
+
+	return f(tcnl, iface)
+}
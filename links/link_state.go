@@ -0,0 +1,152 @@
+package links
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+)
+
+// LinkState is the administrative state a link is set to at runtime.
+type LinkState string
+
+const (
+	LinkStateUp   LinkState = "up"
+	LinkStateDown LinkState = "down"
+)
+
+// FindLink returns the veth link of the links identified by ref, either one of its endpoints
+// as <node>:<interface> or both its endpoints as <node>:<interface>,<node>:<interface>.
+// The interface of an endpoint can be given by its name or alias.
+func FindLink(links map[int]Link, ref string) (*LinkVEth, error) {
+	var eps [][2]string
+	for _, e := range strings.Split(ref, ",") {
+		node, iface, ok := strings.Cut(strings.TrimSpace(e), ":")
+		if !ok || node == "" || iface == "" {
+			return nil, fmt.Errorf("invalid link %q, expected <node>:<interface>[,<node>:<interface>]", ref)
+		}
+		eps = append(eps, [2]string{node, iface})
+	}
+	if len(eps) > 2 {
+		return nil, fmt.Errorf("invalid link %q, a link has at most two endpoints", ref)
+	}
+
+	// iterate over the links in a stable order
+	ids := make([]int, 0, len(links))
+	for id := range links {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		if !linkHasEndpoints(links[id], eps) {
+			continue
+		}
+
+		l, ok := links[id].(*LinkVEth)
+		if !ok {
+			return nil, fmt.Errorf("link %q of type %s can not be set up or down", ref, links[id].GetType())
+		}
+
+		return l, nil
+	}
+
+	return nil, fmt.Errorf("link %q not found in the topology", ref)
+}
+
+// linkHasEndpoints returns true if every node and interface pair of eps is an endpoint of the link.
+func linkHasEndpoints(l Link, eps [][2]string) bool {
+	for _, e := range eps {
+		found := false
+		for _, ep := range l.GetEndpoints() {
+			if ep.GetNode().GetShortName() == e[0] &&
+				(ep.GetIfaceName() == e[1] || ep.GetIfaceAlias() == e[1]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// SetLinkState sets the interfaces of all endpoints of the deployed veth link up or down
+// in the network namespaces of their nodes.
+func SetLinkState(ctx context.Context, l *LinkVEth, state LinkState) error {
+	for _, ep := range l.GetEndpoints() {
+		err := ep.GetNode().ExecFunction(ctx, func(_ ns.NetNS) error {
+			link, err := netlink.LinkByName(SanitizeInterfaceName(ep.GetIfaceName()))
+			if err != nil {
+				return err
+			}
+
+			switch state {
+			case LinkStateUp:
+				return netlink.LinkSetUp(link)
+			case LinkStateDown:
+				return netlink.LinkSetDown(link)
+			default:
+				return fmt.Errorf("unknown link state %q", state)
+			}
+		})
+		if err != nil {
+			return fmt.Errorf("failed to set endpoint %s %s: %w", ep, state, err)
+		}
+	}
+
+	return nil
+}
+
+// FlapLink sets the link down and up count times, the link stays down for the interval
+// and up for the interval between the flaps. The link is set back up when the context
+// is canceled while it is down.
+func FlapLink(ctx context.Context, l *LinkVEth, count int, interval time.Duration) error {
+	eps := l.GetEndpoints()
+
+	for i := 1; i <= count; i++ {
+		if i > 1 {
+			if err := sleepCtx(ctx, interval); err != nil {
+				return err
+			}
+		}
+
+		if err := SetLinkState(ctx, l, LinkStateDown); err != nil {
+			return err
+		}
+		log.Infof("Link %s ▪┄┄▪ %s down (flap %d/%d)", eps[0], eps[1], i, count)
+
+		sleepErr := sleepCtx(ctx, interval)
+
+		if err := SetLinkState(context.WithoutCancel(ctx), l, LinkStateUp); err != nil {
+			return err
+		}
+		log.Infof("Link %s ▪┄┄▪ %s up (flap %d/%d)", eps[0], eps[1], i, count)
+
+		if sleepErr != nil {
+			return sleepErr
+		}
+	}
+
+	return nil
+}
+
+// sleepCtx waits for d or until the context is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
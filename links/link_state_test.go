@@ -0,0 +1,68 @@
+package links
+
+import (
+	"testing"
+)
+
+func TestFindLink(t *testing.T) {
+	fn1 := newFakeNode("node1")
+	fn2 := newFakeNode("node2")
+	fn3 := newFakeNode("node3")
+
+	newLink := func(a *fakeNode, aIf, aAlias string, b *fakeNode, bIf string) *LinkVEth {
+		l := NewLinkVEth()
+		l.Endpoints = []Endpoint{
+			&EndpointVeth{EndpointGeneric: EndpointGeneric{Node: a, IfaceName: aIf, IfaceAlias: aAlias, Link: l}},
+			&EndpointVeth{EndpointGeneric: EndpointGeneric{Node: b, IfaceName: bIf, Link: l}},
+		}
+		return l
+	}
+
+	links := map[int]Link{
+		0: newLink(fn1, "e1-1", "ethernet-1/1", fn2, "eth1"),
+		1: newLink(fn1, "e1-2", "ethernet-1/2", fn3, "eth1"),
+	}
+
+	tests := map[string]struct {
+		ref     string
+		want    Link
+		wantErr bool
+	}{
+		"one endpoint": {
+			ref:  "node2:eth1",
+			want: links[0],
+		},
+		"endpoint alias": {
+			ref:  "node1:ethernet-1/2",
+			want: links[1],
+		},
+		"both endpoints": {
+			ref:  "node3:eth1, node1:e1-2",
+			want: links[1],
+		},
+		"endpoints of different links": {
+			ref:     "node2:eth1,node1:e1-2",
+			wantErr: true,
+		},
+		"unknown endpoint": {
+			ref:     "node4:eth1",
+			wantErr: true,
+		},
+		"invalid ref": {
+			ref:     "node1",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := FindLink(links, tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FindLink() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("FindLink() got link %v, want %v", got.GetEndpoints(), tt.want.GetEndpoints())
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/charmbracelet/fang"
 	clabcmd "github.com/srl-labs/containerlab/cmd"
+	clabcoreconsole "github.com/srl-labs/containerlab/core/console"
 )
 
 func main() {
@@ -23,10 +24,13 @@ func main() {
 
 	err = fang.Execute(ctx, root, fang.WithoutVersion())
 
+	// print the node log records still held back in the grouped log style
+	clabcoreconsole.FlushAll()
+
 	// ensure cancel is *always* called (os.Exit bypasses)
 	cancel()
 
 	if err != nil {
-		os.Exit(1)
+		os.Exit(clabcmd.ExitCode(err))
 	}
 }
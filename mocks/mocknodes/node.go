@@ -556,3 +556,41 @@ func (mr *MockNodeMockRecorder) WithRuntime(arg0 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithRuntime", reflect.TypeOf((*MockNode)(nil).WithRuntime), arg0)
 }
+
+// MockConsoleNode is a mock of ConsoleNode interface.
+type MockConsoleNode struct {
+	ctrl     *gomock.Controller
+	recorder *MockConsoleNodeMockRecorder
+	isgomock struct{}
+}
+
+// MockConsoleNodeMockRecorder is the mock recorder for MockConsoleNode.
+type MockConsoleNodeMockRecorder struct {
+	mock *MockConsoleNode
+}
+
+// NewMockConsoleNode creates a new mock instance.
+func NewMockConsoleNode(ctrl *gomock.Controller) *MockConsoleNode {
+	mock := &MockConsoleNode{ctrl: ctrl}
+	mock.recorder = &MockConsoleNodeMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockConsoleNode) EXPECT() *MockConsoleNodeMockRecorder {
+	return m.recorder
+}
+
+// ConsoleCmd mocks base method.
+func (m *MockConsoleNode) ConsoleCmd() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsoleCmd")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// ConsoleCmd indicates an expected call of ConsoleCmd.
+func (mr *MockConsoleNodeMockRecorder) ConsoleCmd() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsoleCmd", reflect.TypeOf((*MockConsoleNode)(nil).ConsoleCmd))
+}
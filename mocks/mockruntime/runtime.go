@@ -11,6 +11,8 @@ package mockruntime
 
 import (
 	context "context"
+	io "io"
+	fs "io/fs"
 	reflect "reflect"
 
 	exec "github.com/srl-labs/containerlab/exec"
@@ -72,6 +74,34 @@ func (mr *MockContainerRuntimeMockRecorder) Config() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Config", reflect.TypeOf((*MockContainerRuntime)(nil).Config))
 }
 
+// CopyFromContainer mocks base method.
+func (m *MockContainerRuntime) CopyFromContainer(ctx context.Context, cID, path string, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyFromContainer", ctx, cID, path, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyFromContainer indicates an expected call of CopyFromContainer.
+func (mr *MockContainerRuntimeMockRecorder) CopyFromContainer(ctx, cID, path, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyFromContainer", reflect.TypeOf((*MockContainerRuntime)(nil).CopyFromContainer), ctx, cID, path, w)
+}
+
+// CopyToContainer mocks base method.
+func (m *MockContainerRuntime) CopyToContainer(ctx context.Context, cID, path string, r io.Reader, size int64, mode fs.FileMode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CopyToContainer", ctx, cID, path, r, size, mode)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CopyToContainer indicates an expected call of CopyToContainer.
+func (mr *MockContainerRuntimeMockRecorder) CopyToContainer(ctx, cID, path, r, size, mode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CopyToContainer", reflect.TypeOf((*MockContainerRuntime)(nil).CopyToContainer), ctx, cID, path, r, size, mode)
+}
+
 // CreateContainer mocks base method.
 func (m *MockContainerRuntime) CreateContainer(arg0 context.Context, arg1 *types.NodeConfig) (string, error) {
 	m.ctrl.T.Helper()
@@ -158,6 +188,21 @@ func (mr *MockContainerRuntimeMockRecorder) ExecNotWait(ctx, cID, execCmd any) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecNotWait", reflect.TypeOf((*MockContainerRuntime)(nil).ExecNotWait), ctx, cID, execCmd)
 }
 
+// GetContainerStats mocks base method.
+func (m *MockContainerRuntime) GetContainerStats(ctx context.Context, cID string) (*runtime.ContainerStats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContainerStats", ctx, cID)
+	ret0, _ := ret[0].(*runtime.ContainerStats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContainerStats indicates an expected call of GetContainerStats.
+func (mr *MockContainerRuntimeMockRecorder) GetContainerStats(ctx, cID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContainerStats", reflect.TypeOf((*MockContainerRuntime)(nil).GetContainerStats), ctx, cID)
+}
+
 // GetContainerStatus mocks base method.
 func (m *MockContainerRuntime) GetContainerStatus(ctx context.Context, cID string) runtime.ContainerStatus {
 	m.ctrl.T.Helper()
@@ -263,6 +308,21 @@ func (mr *MockContainerRuntimeMockRecorder) Init(arg0 ...any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Init", reflect.TypeOf((*MockContainerRuntime)(nil).Init), arg0...)
 }
 
+// InspectImage mocks base method.
+func (m *MockContainerRuntime) InspectImage(ctx context.Context, imageName string) (*runtime.ImageInfo, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectImage", ctx, imageName)
+	ret0, _ := ret[0].(*runtime.ImageInfo)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectImage indicates an expected call of InspectImage.
+func (mr *MockContainerRuntimeMockRecorder) InspectImage(ctx, imageName any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectImage", reflect.TypeOf((*MockContainerRuntime)(nil).InspectImage), ctx, imageName)
+}
+
 // IsHealthy mocks base method.
 func (m *MockContainerRuntime) IsHealthy(ctx context.Context, cID string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -321,6 +381,21 @@ func (mr *MockContainerRuntimeMockRecorder) PauseContainer(arg0, arg1 any) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseContainer", reflect.TypeOf((*MockContainerRuntime)(nil).PauseContainer), arg0, arg1)
 }
 
+// PruneNets mocks base method.
+func (m *MockContainerRuntime) PruneNets(ctx context.Context, dryRun bool) ([]*runtime.OrphanedResource, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneNets", ctx, dryRun)
+	ret0, _ := ret[0].([]*runtime.OrphanedResource)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneNets indicates an expected call of PruneNets.
+func (mr *MockContainerRuntimeMockRecorder) PruneNets(ctx, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneNets", reflect.TypeOf((*MockContainerRuntime)(nil).PruneNets), ctx, dryRun)
+}
+
 // PullImage mocks base method.
 func (m *MockContainerRuntime) PullImage(arg0 context.Context, arg1 string, arg2 types.PullPolicyValue) error {
 	m.ctrl.T.Helper()
@@ -364,6 +439,20 @@ func (mr *MockContainerRuntimeMockRecorder) StopContainer(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopContainer", reflect.TypeOf((*MockContainerRuntime)(nil).StopContainer), arg0, arg1)
 }
 
+// StreamContainerLogs mocks base method.
+func (m *MockContainerRuntime) StreamContainerLogs(ctx context.Context, cID string, follow bool, w io.Writer) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamContainerLogs", ctx, cID, follow, w)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamContainerLogs indicates an expected call of StreamContainerLogs.
+func (mr *MockContainerRuntimeMockRecorder) StreamContainerLogs(ctx, cID, follow, w any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamContainerLogs", reflect.TypeOf((*MockContainerRuntime)(nil).StreamContainerLogs), ctx, cID, follow, w)
+}
+
 // UnpauseContainer mocks base method.
 func (m *MockContainerRuntime) UnpauseContainer(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -94,7 +94,7 @@ func (n *sixwind_vsr) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOpti
 
 	// Containers are run in privileged mode so it should not matter now
 	// If it changes, add the capabilities to run 6WIND VSR
-	n.Cfg.CapAdd = append(n.Cfg.CapAdd,
+	n.SecurityProfile.CapAdd = append(n.SecurityProfile.CapAdd,
 		"NET_ADMIN",
 		"SYS_ADMIN",
 		"NET_BROADCAST",
@@ -108,7 +108,7 @@ func (n *sixwind_vsr) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOpti
 	)
 
 	// Devices to be mapped to the container
-	n.Cfg.Devices = append(n.Cfg.Devices,
+	n.SecurityProfile.Devices = append(n.SecurityProfile.Devices,
 		"/dev/net/tun",
 		"/dev/vhost-net",
 		"/dev/ppp",
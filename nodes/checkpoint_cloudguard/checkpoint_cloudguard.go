@@ -35,6 +35,7 @@ func (n *CheckpointCloudguard) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
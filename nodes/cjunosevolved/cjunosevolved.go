@@ -14,11 +14,12 @@ import (
 )
 
 var (
-	kindnames          = []string{"cjunosevolved", "juniper_cjunosevolved"}
-	defaultCredentials = clabnodes.NewCredentials("admin", "admin@123")
-	InterfaceRegexp    = regexp.MustCompile(`et-0/0/(?P<port>\d+)$`)
-	InterfaceOffset    = -3
-	InterfaceHelp      = "(et-0/0/X (where X >= 0) or ethX (where X >= 4)"
+	kindnames             = []string{"cjunosevolved", "juniper_cjunosevolved"}
+	defaultCredentials    = clabnodes.NewCredentials("admin", "admin@123")
+	InterfaceRegexp       = regexp.MustCompile(`et-0/0/(?P<port>\d+)$`)
+	InterfaceOffset       = -3
+	InterfaceNativeFormat = "et-0/0/%d"
+	InterfaceHelp         = "(et-0/0/X (where X >= 0) or ethX (where X >= 4)"
 )
 
 const (
@@ -53,6 +54,7 @@ func (n *cjunosevolved) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOp
 
 	// cjunosevolved requires KVM support.
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 	n.HostRequirements.MinVCPU = 4
 	n.HostRequirements.MinVCPUFailAction = clabtypes.FailBehaviourError
 	n.HostRequirements.MinAvailMemoryGb = 8
@@ -68,6 +70,7 @@ func (n *cjunosevolved) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOp
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
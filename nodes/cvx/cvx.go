@@ -75,6 +75,8 @@ func (c *cvx) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) erro
 }
 
 func (c *cvx) Deploy(ctx context.Context, _ *clabnodes.DeployParams) error {
+	c.ApplySecurityProfile()
+
 	// CreateContainer is no-op in case of ignite runtime
 	cID, err := c.Runtime.CreateContainer(ctx, c.Cfg)
 	if err != nil {
@@ -35,6 +35,8 @@ type DefaultNode struct {
 	Mgmt             *clabtypes.MgmtNet
 	Runtime          clabruntime.ContainerRuntime
 	HostRequirements *clabtypes.HostRequirements
+	// SecurityProfile is the security settings the kind requires, applied at container create.
+	SecurityProfile *clabtypes.SecurityProfile
 	// SSHConfig is the SSH client configuration that a clab node requires.
 	SSHConfig *clabtypes.SSHConfig
 	// Indicates that the node should not start without no license file defined
@@ -49,6 +51,9 @@ type DefaultNode struct {
 	InterfaceMappedPrefix string
 	InterfaceOffset       int
 	InterfaceHelp         string
+	// InterfaceNativeFormat is the format of the NOS-native interface name with the port number,
+	// used to map the interface names referenced with the mapped prefix, e.g. eth1, to their NOS-native names.
+	InterfaceNativeFormat string
 	FirstDataIfIndex      int
 	// State of the node
 	state      clabnodesstate.NodeState
@@ -61,6 +66,7 @@ type DefaultNode struct {
 func NewDefaultNode(n NodeOverwrites) *DefaultNode {
 	dn := &DefaultNode{
 		HostRequirements: clabtypes.NewHostRequirements(),
+		SecurityProfile:  clabtypes.NewSecurityProfile(),
 		OverwriteNode:    n,
 		LicensePolicy:    clabtypes.LicensePolicyNone,
 		SSHConfig:        clabtypes.NewSSHConfig(),
@@ -103,6 +109,10 @@ func (d *DefaultNode) CheckDeploymentConditions(ctx context.Context) error {
 		return err
 	}
 
+	for _, issue := range d.SecurityPreflight() {
+		log.Warn("Host setting may block the node", "node", d.Cfg.ShortName, "kind", d.Cfg.Kind, "issue", issue)
+	}
+
 	err = d.OverwriteNode.VerifyStartupConfig(d.Cfg.LabDir)
 	if err != nil {
 		return err
@@ -134,10 +144,26 @@ func (d *DefaultNode) PullImage(ctx context.Context) error {
 	return nil
 }
 
+// GetHostRequirements returns the host requirements of the node's kind.
+func (d *DefaultNode) GetHostRequirements() *clabtypes.HostRequirements {
+	return d.HostRequirements
+}
+
 func (d *DefaultNode) VerifyHostRequirements() error {
 	return d.HostRequirements.Verify(d.Cfg.Kind, d.Cfg.ShortName)
 }
 
+// ApplySecurityProfile applies the security profile of the kind to the node config,
+// to be called before the container of the node is created.
+func (d *DefaultNode) ApplySecurityProfile() {
+	d.SecurityProfile.Apply(d.Cfg)
+}
+
+// SecurityPreflight returns the host settings blocking the node with the security profile of its kind.
+func (d *DefaultNode) SecurityPreflight() []string {
+	return d.SecurityProfile.Preflight(d.Cfg.SecurityOpts)
+}
+
 func (d *DefaultNode) Deploy(ctx context.Context, _ *DeployParams) error {
 	// Set the "CLAB_INTFS" variable to the number of interfaces (endpoints) a node has.
 	// This env var does not count in the eth0 interface that is automatically created by the container runtime.
@@ -145,6 +171,8 @@ func (d *DefaultNode) Deploy(ctx context.Context, _ *DeployParams) error {
 	// have been added to the container namespace.
 	d.Config().Env[clabtypes.CLAB_ENV_INTFS] = strconv.Itoa(len(d.GetEndpoints()))
 
+	d.ApplySecurityProfile()
+
 	// create the container
 	cID, err := d.Runtime.CreateContainer(ctx, d.Cfg)
 	if err != nil {
@@ -328,6 +356,27 @@ func (d *DefaultNode) GetMappedInterfaceName(ifName string) (string, error) {
 	return mappedIfName, nil
 }
 
+// GetNativeInterfaceName returns the NOS-native interface name for the mapped interface name,
+// which is the reverse of GetMappedInterfaceName.
+// The name is returned unchanged when the kind has no native interface format or the name is not a data interface.
+func (d *DefaultNode) GetNativeInterfaceName(ifName string) (string, error) {
+	if d.InterfaceNativeFormat == "" || !strings.HasPrefix(ifName, d.InterfaceMappedPrefix) {
+		return ifName, nil
+	}
+
+	ifIndex, err := strconv.Atoi(strings.TrimPrefix(ifName, d.InterfaceMappedPrefix))
+	if err != nil || ifIndex < d.FirstDataIfIndex {
+		return ifName, nil
+	}
+
+	port := ifIndex - d.FirstDataIfIndex + d.InterfaceOffset
+	if port < 0 {
+		return ifName, nil
+	}
+
+	return fmt.Sprintf(d.InterfaceNativeFormat, port), nil
+}
+
 // VerifyStartupConfig verifies that startup config files exists on disks.
 func (d *DefaultNode) VerifyStartupConfig(topoDir string) error {
 	cfg := d.Config().StartupConfig
@@ -400,6 +449,7 @@ type NodeOverwrites interface {
 	CheckInterfaceName() error
 	CalculateInterfaceIndex(ifName string) (int, error)
 	GetMappedInterfaceName(ifName string) (string, error)
+	GetNativeInterfaceName(ifName string) (string, error)
 	VerifyHostRequirements() error
 	PullImage(ctx context.Context) error
 	GetImages(ctx context.Context) map[string]string
@@ -636,6 +686,17 @@ func (d *DefaultNode) AddEndpoint(e clablinks.Endpoint) error {
 		log.Debugf("Interface Mapping: Mapping interface %q (ifAlias) to %q (ifName)", endpointName, mappedName)
 		e.SetIfaceName(mappedName)
 		e.SetIfaceAlias(endpointName)
+	} else {
+		// endpoints referenced with the mapped names get the NOS-native name as an alias,
+		// so that both naming conventions result in the same endpoint
+		nativeName, err := d.OverwriteNode.GetNativeInterfaceName(endpointName)
+		if err != nil {
+			return fmt.Errorf("%q interface name %q could not be mapped: %w", d.Cfg.ShortName, endpointName, err)
+		}
+		if nativeName != endpointName {
+			log.Debugf("Interface Mapping: Mapping interface %q (ifName) to %q (ifAlias)", endpointName, nativeName)
+			e.SetIfaceAlias(nativeName)
+		}
 	}
 	d.Endpoints = append(d.Endpoints, e)
 
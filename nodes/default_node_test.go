@@ -370,3 +370,66 @@ func TestInterfacesAliases(t *testing.T) { // skipcq: GO-R1005
 		})
 	}
 }
+
+func TestNativeInterfaceName(t *testing.T) {
+	tests := map[string]struct {
+		node   *DefaultNode
+		ifName string
+		want   string
+	}{
+		"no-native-format": {
+			node:   &DefaultNode{InterfaceMappedPrefix: "eth"},
+			ifName: "eth1",
+			want:   "eth1",
+		},
+		"zero-offset": {
+			node: &DefaultNode{
+				InterfaceMappedPrefix: "eth",
+				InterfaceNativeFormat: "ge-0/0/%d",
+				FirstDataIfIndex:      1,
+			},
+			ifName: "eth1",
+			want:   "ge-0/0/0",
+		},
+		"offset": {
+			node: &DefaultNode{
+				InterfaceMappedPrefix: "eth",
+				InterfaceNativeFormat: "GigabitEthernet%d",
+				InterfaceOffset:       2,
+				FirstDataIfIndex:      1,
+			},
+			ifName: "eth3",
+			want:   "GigabitEthernet4",
+		},
+		"management-interface": {
+			node: &DefaultNode{
+				InterfaceMappedPrefix: "eth",
+				InterfaceNativeFormat: "Ethernet1/%d",
+				InterfaceOffset:       1,
+				FirstDataIfIndex:      1,
+			},
+			ifName: "eth0",
+			want:   "eth0",
+		},
+		"other-interface": {
+			node: &DefaultNode{
+				InterfaceMappedPrefix: "eth",
+				InterfaceNativeFormat: "Ethernet1/%d",
+			},
+			ifName: "lo1",
+			want:   "lo1",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tc.node.GetNativeInterfaceName(tc.ifName)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("got native interface name %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -54,6 +54,7 @@ func (n *dell_sonic) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOptio
 	n.DefaultNode = *clabnodes.NewDefaultNode(n)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
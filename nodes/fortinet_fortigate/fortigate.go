@@ -18,9 +18,10 @@ var (
 	kindnames          = []string{"fortinet_fortigate"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`port(?P<port>\d+)$`)
-	InterfaceOffset = 2
-	InterfaceHelp   = "portX (where X >= 2) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`port(?P<port>\d+)$`)
+	InterfaceOffset       = 2
+	InterfaceNativeFormat = "port%d"
+	InterfaceHelp         = "portX (where X >= 2) or ethX (where X >= 1)"
 )
 
 const (
@@ -54,6 +55,7 @@ func (n *fortigate) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -85,6 +87,7 @@ func (n *fortigate) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
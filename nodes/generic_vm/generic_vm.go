@@ -44,6 +44,7 @@ func (n *genericVM) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption
 	n.DefaultNode = *clabnodes.NewDefaultNode(n)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -48,6 +48,7 @@ func (n *huawei_vrp) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOptio
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
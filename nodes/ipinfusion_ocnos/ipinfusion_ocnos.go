@@ -46,6 +46,7 @@ func (n *IPInfusionOcNOS) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.Node
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -73,6 +73,8 @@ func (n *linux) Deploy(ctx context.Context, _ *clabnodes.DeployParams) error {
 	// such that the internal VM can be started with these interfaces assigned.
 	n.Config().Env[clabtypes.CLAB_ENV_INTFS] = strconv.Itoa(len(n.GetEndpoints()))
 
+	n.ApplySecurityProfile()
+
 	cID, err := n.Runtime.CreateContainer(ctx, n.Cfg)
 	if err != nil {
 		return err
@@ -127,6 +127,32 @@ type Node interface {
 	GetHostsEntries(ctx context.Context) (clabtypes.HostEntries, error)
 }
 
+// ConsoleNode is implemented by nodes that provide access to a serial console,
+// e.g. VM-based nodes launched by vrnetlab.
+type ConsoleNode interface {
+	// ConsoleCmd returns the command to execute inside the node's container
+	// to attach to the serial console.
+	ConsoleCmd() []string
+}
+
+// SecurityProfileNode is implemented by nodes having the security profile of their kind.
+type SecurityProfileNode interface {
+	// SecurityPreflight returns the host settings blocking the node, empty if none.
+	SecurityPreflight() []string
+}
+
+// FileCopyNode is implemented by nodes whose files are not in the filesystem of their container,
+// e.g. VM-based nodes, the files of these nodes are copied over SFTP to the filesystem of the NOS.
+type FileCopyNode interface {
+	// FileCopyPath returns the path on the filesystem of the NOS of a file path given for the node.
+	FileCopyPath(p string) string
+}
+
+// HostRequirementsNode is implemented by nodes having the host requirements of their kind.
+type HostRequirementsNode interface {
+	GetHostRequirements() *clabtypes.HostRequirements
+}
+
 type NodeOption func(Node)
 
 func WithMgmtNet(mgmt *clabtypes.MgmtNet) NodeOption {
@@ -0,0 +1,22 @@
+# BIRD configuration of the {{ .ShortName }} route injector
+# Generated by clab
+
+router id {{ .Extras.RouteInjector.GetRouterID }};
+
+protocol device {
+}
+
+# static routes of the injected prefixes, generated from the routes of the node
+include "/etc/bird/routes.conf";
+{{ range $i, $p := .Extras.RouteInjector.Peers }}
+protocol bgp peer{{ $i }} {
+  description "{{ $p.Interface }}";
+  local as {{ $.Extras.RouteInjector.ASN }};
+  neighbor {{ $p.Neighbor }} as {{ $p.ASN }};
+  {{ $p.Family }} {
+    import none;
+    export all;
+    next hop self;
+  };
+}
+{{ end -}}
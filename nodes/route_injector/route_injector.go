@@ -0,0 +1,172 @@
+// Copyright 2025
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package route_injector
+
+import (
+	"context"
+	_ "embed"
+	"fmt"
+	"net/netip"
+	"os"
+	"path/filepath"
+
+	"github.com/charmbracelet/log"
+	"github.com/containernetworking/plugins/pkg/ns"
+	"github.com/vishvananda/netlink"
+
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabtypes "github.com/srl-labs/containerlab/types"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+const (
+	generateable     = true
+	generateIfFormat = "eth%d"
+
+	birdCfgDstPath   = "/etc/bird/bird.conf"
+	routesCfgDstPath = "/etc/bird/routes.conf"
+)
+
+var (
+	kindNames = []string{"route-injector"}
+
+	// birdConfigTpl is the template of the BIRD config of the injector,
+	// used when the node has no startup-config
+	//go:embed bird.conf.go.tpl
+	birdConfigTpl string
+)
+
+// Register registers the node in the NodeRegistry.
+func Register(r *clabnodes.NodeRegistry) {
+	generateNodeAttributes := clabnodes.NewGenerateNodeAttributes(generateable, generateIfFormat)
+	nrea := clabnodes.NewNodeRegistryEntryAttributes(nil, generateNodeAttributes, nil)
+
+	r.Register(kindNames, func() clabnodes.Node {
+		return new(routeInjector)
+	}, nrea)
+}
+
+// routeInjector is a BIRD container advertising synthetic routes over BGP to the lab nodes.
+type routeInjector struct {
+	clabnodes.DefaultNode
+	// Path of the BIRD config file
+	birdCfgSrcPath string
+	// Path of the static routes of the injected prefixes
+	routesCfgSrcPath string
+}
+
+func (n *routeInjector) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) error {
+	// Init DefaultNode
+	n.DefaultNode = *clabnodes.NewDefaultNode(n)
+	n.Cfg = cfg
+
+	if err := checkExtras(cfg); err != nil {
+		return err
+	}
+
+	// BIRD sets the routes of the injected prefixes in the kernel
+	n.Cfg.CapAdd = append(n.Cfg.CapAdd, "NET_ADMIN", "NET_RAW")
+
+	n.birdCfgSrcPath = filepath.Join(n.Cfg.LabDir, "bird.conf")
+	n.Cfg.ResStartupConfig = n.birdCfgSrcPath
+	n.Cfg.Binds = append(n.Cfg.Binds, fmt.Sprint(n.birdCfgSrcPath, ":", birdCfgDstPath))
+
+	n.routesCfgSrcPath = filepath.Join(n.Cfg.LabDir, "routes.conf")
+	n.Cfg.Binds = append(n.Cfg.Binds, fmt.Sprint(n.routesCfgSrcPath, ":", routesCfgDstPath))
+
+	for _, o := range opts {
+		o(n)
+	}
+
+	return nil
+}
+
+// checkExtras checks the route-injector options of the node.
+func checkExtras(cfg *clabtypes.NodeConfig) error {
+	if cfg.Extras == nil || cfg.Extras.RouteInjector == nil {
+		return fmt.Errorf("node %s: route-injector extras are required", cfg.ShortName)
+	}
+
+	ri := cfg.Extras.RouteInjector
+	if ri.ASN == 0 {
+		return fmt.Errorf("node %s: the asn of the route injector is required", cfg.ShortName)
+	}
+	if len(ri.Peers) == 0 {
+		return fmt.Errorf("node %s: the route injector has no peers", cfg.ShortName)
+	}
+
+	for _, p := range ri.Peers {
+		if _, err := netip.ParsePrefix(p.Address); err != nil {
+			return fmt.Errorf("node %s: invalid address %q of the peer %s, expected an address with the prefix length",
+				cfg.ShortName, p.Address, p.Neighbor)
+		}
+		if _, err := netip.ParseAddr(p.Neighbor); err != nil {
+			return fmt.Errorf("node %s: invalid neighbor address %q", cfg.ShortName, p.Neighbor)
+		}
+		if p.Interface == "" || p.ASN == 0 {
+			return fmt.Errorf("node %s: the interface and the asn of the peer %s are required",
+				cfg.ShortName, p.Neighbor)
+		}
+	}
+
+	if ri.GetRouterID() == "" {
+		return fmt.Errorf("node %s: the route injector has no IPv4 peer address, set its router-id", cfg.ShortName)
+	}
+
+	return nil
+}
+
+func (n *routeInjector) PreDeploy(_ context.Context, _ *clabnodes.PreDeployParams) error {
+	clabutils.CreateDirectory(n.Cfg.LabDir, 0o777)
+
+	// the routes are generated on every deploy to follow the changes of the topology
+	routes, count, err := routesConfig(n.Cfg.Extras.RouteInjector.Routes)
+	if err != nil {
+		return fmt.Errorf("node %s: %w", n.Cfg.ShortName, err)
+	}
+
+	if err := os.WriteFile(n.routesCfgSrcPath, []byte(routes), 0o644); err != nil { // skipcq: GSC-G306
+		return err
+	}
+
+	log.Debug("Generated injected routes", "node", n.Cfg.ShortName, "prefixes", count)
+
+	// use the startup config template provided by a user
+	birdCfgTpl := birdConfigTpl
+	if n.Cfg.StartupConfig != "" {
+		c, err := os.ReadFile(n.Cfg.StartupConfig)
+		if err != nil {
+			return err
+		}
+		birdCfgTpl = string(c)
+	}
+
+	return n.GenerateConfig(n.Cfg.ResStartupConfig, birdCfgTpl)
+}
+
+// PostDeploy sets the addresses of the peers on the interfaces of the injector.
+func (n *routeInjector) PostDeploy(ctx context.Context, _ *clabnodes.PostDeployParams) error {
+	return n.ExecFunction(ctx, func(ns.NetNS) error {
+		for _, p := range n.Cfg.Extras.RouteInjector.Peers {
+			link, err := netlink.LinkByName(p.Interface)
+			if err != nil {
+				return fmt.Errorf("node %s: interface %s of the peer %s not found: %w",
+					n.Cfg.ShortName, p.Interface, p.Neighbor, err)
+			}
+
+			addr, err := netlink.ParseAddr(p.Address)
+			if err != nil {
+				return err
+			}
+
+			if err := netlink.AddrReplace(link, addr); err != nil {
+				return fmt.Errorf("node %s: failed to set the address %s on %s: %w",
+					n.Cfg.ShortName, p.Address, p.Interface, err)
+			}
+		}
+
+		return nil
+	})
+}
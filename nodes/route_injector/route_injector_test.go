@@ -0,0 +1,189 @@
+package route_injector
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabnodes "github.com/srl-labs/containerlab/nodes"
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+func TestNextPrefix(t *testing.T) {
+	tests := map[string]struct {
+		prefix string
+		want   string
+		ok     bool
+	}{
+		"ipv4 /24":        {prefix: "100.0.0.0/24", want: "100.0.1.0/24", ok: true},
+		"ipv4 carry":      {prefix: "100.0.255.0/24", want: "100.1.0.0/24", ok: true},
+		"ipv4 /20":        {prefix: "10.0.240.0/20", want: "10.1.0.0/20", ok: true},
+		"ipv4 last":       {prefix: "255.255.255.0/24", ok: false},
+		"ipv6 /48":        {prefix: "2a00:0:ffff::/48", want: "2a00:1::/48", ok: true},
+		"default route":   {prefix: "0.0.0.0/0", ok: false},
+		"ipv4 host route": {prefix: "192.0.2.255/32", want: "192.0.3.0/32", ok: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, ok := nextPrefix(netip.MustParsePrefix(tt.prefix))
+			if ok != tt.ok {
+				t.Fatalf("want ok %v, got %v", tt.ok, ok)
+			}
+			if ok && got.String() != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRoutesConfig(t *testing.T) {
+	routes := []*clabtypes.RouteInjectorRoutes{
+		{Prefix: "100.0.0.1/24", Count: 3, ASPath: []uint32{174, 3356}, Communities: []string{"65000:100"}},
+		{Prefix: "2a00::/48"},
+	}
+
+	got, count, err := routesConfig(routes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `# injected prefixes, generated by clab
+
+protocol static injected4 {
+  ipv4;
+  route 100.0.0.0/24 blackhole { bgp_path.prepend(3356); bgp_path.prepend(174); bgp_community.add((65000,100)); };
+  route 100.0.1.0/24 blackhole { bgp_path.prepend(3356); bgp_path.prepend(174); bgp_community.add((65000,100)); };
+  route 100.0.2.0/24 blackhole { bgp_path.prepend(3356); bgp_path.prepend(174); bgp_community.add((65000,100)); };
+}
+
+protocol static injected6 {
+  ipv6;
+  route 2a00::/48 blackhole;
+}
+`
+	if d := cmp.Diff(want, got); d != "" {
+		t.Errorf("routes mismatch (-want +got):\n%s", d)
+	}
+	if count != 4 {
+		t.Errorf("want 4 prefixes, got %d", count)
+	}
+
+	for name, r := range map[string]*clabtypes.RouteInjectorRoutes{
+		"invalid prefix":    {Prefix: "100.0.0.0"},
+		"invalid community": {Prefix: "100.0.0.0/24", Communities: []string{"65000:100000"}},
+		"overflow":          {Prefix: "255.255.255.0/24", Count: 2},
+		"too many":          {Prefix: "100.0.0.0/32", Count: maxRoutes + 1},
+	} {
+		if _, _, err := routesConfig([]*clabtypes.RouteInjectorRoutes{r}); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestPreDeploy(t *testing.T) {
+	labDir := t.TempDir()
+
+	cfg := &clabtypes.NodeConfig{
+		ShortName: "inet",
+		LabDir:    labDir,
+		Extras: &clabtypes.Extras{
+			RouteInjector: &clabtypes.RouteInjectorExtras{
+				ASN: 65000,
+				Peers: []*clabtypes.RouteInjectorPeer{
+					{Interface: "eth1", Address: "2001:db8::/127", Neighbor: "2001:db8::1", ASN: 65001},
+					{Interface: "eth1", Address: "10.0.0.0/31", Neighbor: "10.0.0.1", ASN: 65001},
+				},
+				Routes: []*clabtypes.RouteInjectorRoutes{{Prefix: "100.0.0.0/24"}},
+			},
+		},
+	}
+
+	n := new(routeInjector)
+	if err := n.Init(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := n.PreDeploy(t.Context(), &clabnodes.PreDeployParams{}); err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := os.ReadFile(filepath.Join(labDir, "bird.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		"router id 10.0.0.0;",
+		"protocol bgp peer0 {",
+		"  neighbor 2001:db8::1 as 65001;\n  ipv6 {",
+		"  neighbor 10.0.0.1 as 65001;\n  ipv4 {",
+		`include "/etc/bird/routes.conf";`,
+	} {
+		if !strings.Contains(string(b), s) {
+			t.Errorf("bird config does not contain %q:\n%s", s, b)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(labDir, "routes.conf")); err != nil {
+		t.Errorf("routes not generated: %v", err)
+	}
+}
+
+func TestCheckExtras(t *testing.T) {
+	peer := func() *clabtypes.RouteInjectorPeer {
+		return &clabtypes.RouteInjectorPeer{Interface: "eth1", Address: "10.0.0.0/31", Neighbor: "10.0.0.1", ASN: 65001}
+	}
+
+	tests := map[string]struct {
+		extras  *clabtypes.Extras
+		wantErr bool
+	}{
+		"valid": {
+			extras: &clabtypes.Extras{RouteInjector: &clabtypes.RouteInjectorExtras{
+				ASN: 65000, Peers: []*clabtypes.RouteInjectorPeer{peer()},
+			}},
+		},
+		"no extras": {wantErr: true},
+		"no asn": {
+			extras: &clabtypes.Extras{RouteInjector: &clabtypes.RouteInjectorExtras{
+				Peers: []*clabtypes.RouteInjectorPeer{peer()},
+			}},
+			wantErr: true,
+		},
+		"no peers": {
+			extras:  &clabtypes.Extras{RouteInjector: &clabtypes.RouteInjectorExtras{ASN: 65000}},
+			wantErr: true,
+		},
+		"address without length": {
+			extras: &clabtypes.Extras{RouteInjector: &clabtypes.RouteInjectorExtras{
+				ASN: 65000,
+				Peers: []*clabtypes.RouteInjectorPeer{
+					{Interface: "eth1", Address: "10.0.0.0", Neighbor: "10.0.0.1", ASN: 65001},
+				},
+			}},
+			wantErr: true,
+		},
+		"no router id": {
+			extras: &clabtypes.Extras{RouteInjector: &clabtypes.RouteInjectorExtras{
+				ASN: 65000,
+				Peers: []*clabtypes.RouteInjectorPeer{
+					{Interface: "eth1", Address: "2001:db8::/127", Neighbor: "2001:db8::1", ASN: 65001},
+				},
+			}},
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := checkExtras(&clabtypes.NodeConfig{ShortName: "inet", Extras: tt.extras})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("want error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package route_injector
+
+import (
+	"fmt"
+	"net/netip"
+	"strconv"
+	"strings"
+
+	clabtypes "github.com/srl-labs/containerlab/types"
+)
+
+// maxRoutes is the maximum number of prefixes advertised by an injector.
+const maxRoutes = 2_000_000
+
+// routesConfig returns the BIRD static protocols of the prefixes of the routes,
+// one protocol per address family, and the number of prefixes.
+func routesConfig(routes []*clabtypes.RouteInjectorRoutes) (string, int, error) {
+	var v4, v6 strings.Builder
+	total := 0
+
+	for _, r := range routes {
+		pfx, err := netip.ParsePrefix(r.Prefix)
+		if err != nil {
+			return "", 0, fmt.Errorf("invalid prefix %q of the injected routes: %w", r.Prefix, err)
+		}
+		pfx = pfx.Masked()
+
+		count := r.Count
+		if count == 0 {
+			count = 1
+		}
+		if count < 0 || total+count > maxRoutes {
+			return "", 0, fmt.Errorf("invalid count %d of the routes from %s, an injector advertises up to %d prefixes",
+				r.Count, pfx, maxRoutes)
+		}
+
+		attrs, err := routeAttributes(r)
+		if err != nil {
+			return "", 0, err
+		}
+
+		b := &v4
+		if pfx.Addr().Is6() {
+			b = &v6
+		}
+
+		for i := range count {
+			if i > 0 {
+				var ok bool
+				if pfx, ok = nextPrefix(pfx); !ok {
+					return "", 0, fmt.Errorf("the %d routes from %s overflow the address space", count, r.Prefix)
+				}
+			}
+			fmt.Fprintf(b, "  route %s blackhole%s;\n", pfx, attrs)
+		}
+
+		total += count
+	}
+
+	var s strings.Builder
+	s.WriteString("# injected prefixes, generated by clab\n")
+	fmt.Fprintf(&s, "\nprotocol static injected4 {\n  ipv4;\n%s}\n", v4.String())
+	fmt.Fprintf(&s, "\nprotocol static injected6 {\n  ipv6;\n%s}\n", v6.String())
+
+	return s.String(), total, nil
+}
+
+// routeAttributes returns the BIRD filter block setting the BGP attributes of the routes,
+// empty when the routes have no attributes.
+func routeAttributes(r *clabtypes.RouteInjectorRoutes) (string, error) {
+	var cmds []string
+
+	// the AS numbers are prepended in the reverse order to keep the order of the as-path
+	for i := len(r.ASPath) - 1; i >= 0; i-- {
+		cmds = append(cmds, fmt.Sprintf("bgp_path.prepend(%d);", r.ASPath[i]))
+	}
+
+	for _, c := range r.Communities {
+		asn, val, ok := strings.Cut(c, ":")
+		_, errA := strconv.ParseUint(asn, 10, 16)
+		_, errV := strconv.ParseUint(val, 10, 16)
+		if !ok || errA != nil || errV != nil {
+			return "", fmt.Errorf("invalid community %q of the routes from %s, expected asn:value", c, r.Prefix)
+		}
+		cmds = append(cmds, fmt.Sprintf("bgp_community.add((%s,%s));", asn, val))
+	}
+
+	if len(cmds) == 0 {
+		return "", nil
+	}
+
+	return " { " + strings.Join(cmds, " ") + " }", nil
+}
+
+// nextPrefix returns the prefix of the same length following the prefix,
+// false when the prefix is the last one of the address space.
+func nextPrefix(p netip.Prefix) (netip.Prefix, bool) {
+	if p.Bits() == 0 {
+		return netip.Prefix{}, false
+	}
+
+	b := p.Addr().AsSlice()
+
+	// add one at the last bit of the prefix, carrying to the previous bytes
+	bit := p.Bits() - 1
+	idx := bit / 8
+	inc := 1 << (7 - bit%8)
+
+	for ; idx >= 0; idx-- {
+		sum := int(b[idx]) + inc
+		b[idx] = byte(sum)
+		if sum <= 0xff {
+			break
+		}
+		inc = 1
+	}
+	if idx < 0 {
+		return netip.Prefix{}, false
+	}
+
+	addr, _ := netip.AddrFromSlice(b)
+
+	return netip.PrefixFrom(addr, p.Bits()), true
+}
@@ -32,6 +32,8 @@ import (
 
 const (
 	SRLinuxDefaultType = "ixr-d2l" // default srl node type
+	// srlMaxBreakoutChannels is the maximum number of channels of a breakout port.
+	srlMaxBreakoutChannels = 8
 
 	readyTimeout = time.Minute * 5 // max wait time for node to boot
 
@@ -118,6 +120,38 @@ var (
 		"ixr-x3b":    "7250IXRX3b.yml",
 	}
 
+	// srlTypePorts is the number of front panel ports of the fixed form factor node types.
+	srlTypePorts = map[string]int{
+		"ixrd1":      52,
+		"ixr-d1":     52,
+		"ixrd2":      56,
+		"ixr-d2":     56,
+		"ixrd2l":     58,
+		"ixr-d2l":    58,
+		"ixrd3":      34,
+		"ixr-d3":     34,
+		"ixrd3l":     34,
+		"ixr-d3l":    34,
+		"ixrd4":      36,
+		"ixr-d4":     36,
+		"ixrd5":      34,
+		"ixr-d5":     34,
+		"ixrh2":      128,
+		"ixr-h2":     128,
+		"ixrh3":      34,
+		"ixr-h3":     34,
+		"ixrh4":      66,
+		"ixr-h4":     66,
+		"ixrh432d":   34,
+		"ixr-h4-32d": 34,
+		"ixrh532d":   34,
+		"ixr-h5-32d": 34,
+		"ixrh564d":   66,
+		"ixr-h5-64d": 66,
+		"ixrh564o":   66,
+		"ixr-h5-64o": 66,
+	}
+
 	srlEnv = map[string]string{"SRLINUX": "1"}
 
 	//go:embed topology/*
@@ -139,6 +173,8 @@ var (
 
 	InterfaceRegexp = regexp.MustCompile(`ethernet-(?P<linecard>\d+)/(?P<port>\d+)(?:/(?P<channel>\d+))?`)
 	InterfaceHelp   = "ethernet-L/P, ethernet-L/P/C or eL-P, eL-P-C (where L, P, C >= 1)"
+	// MappedInterfaceRegexp matches the interface names in the eL-P and eL-P-C form.
+	MappedInterfaceRegexp = regexp.MustCompile(`^e(?P<linecard>\d+)-(?P<port>\d+)(?:-(?P<channel>\d+))?$`)
 )
 
 // Register registers the node in the NodeRegistry.
@@ -872,9 +908,22 @@ func (n *srl) GetMappedInterfaceName(ifName string) (string, error) {
 }
 
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
+// GetNativeInterfaceName returns the SR Linux interface name for the mapped interface name,
+// e.g. e1-2 -> ethernet-1/2 and e1-2-3 -> ethernet-1/2/3. Other interfaces are returned unchanged.
+func (*srl) GetNativeInterfaceName(ifName string) (string, error) {
+	captureGroups, err := clabutils.GetRegexpCaptureGroups(MappedInterfaceRegexp, ifName)
+	if err != nil {
+		return ifName, nil //nolint: nilerr
+	}
+
+	if channel := captureGroups["channel"]; channel != "" {
+		return fmt.Sprintf("ethernet-%s/%s/%s", captureGroups["linecard"], captureGroups["port"], channel), nil
+	}
+
+	return fmt.Sprintf("ethernet-%s/%s", captureGroups["linecard"], captureGroups["port"]), nil
+}
+
 func (n *srl) CheckInterfaceName() error {
-	// allow ethernetX-X-X, eX-X-X and mgmt0 interface names
-	ifRe := regexp.MustCompile(`(:?e|ethernet)\d+-\d+(-\d+)?|mgmt0`)
 	nm := strings.ToLower(n.Cfg.NetworkMode)
 
 	err := n.CheckInterfaceOverlap()
@@ -883,12 +932,57 @@ func (n *srl) CheckInterfaceName() error {
 	}
 
 	for _, e := range n.Endpoints {
-		if !ifRe.MatchString(e.GetIfaceName()) {
+		if e.GetIfaceName() == "mgmt0" {
+			if nm != "none" {
+				return fmt.Errorf("mgmt0 interface name is not allowed for %s node when network mode is not set to none", n.Cfg.ShortName)
+			}
+			continue
+		}
+
+		// allow eX-X-X interface names, the ethernet-X/X/X aliases are already mapped to them
+		if !MappedInterfaceRegexp.MatchString(e.GetIfaceName()) {
 			return fmt.Errorf("nokia sr linux interface name %q doesn't match the required pattern: %s", e.GetIfaceName(), n.InterfaceHelp)
 		}
 
-		if e.GetIfaceName() == "mgmt0" && nm != "none" {
-			return fmt.Errorf("mgmt0 interface name is not allowed for %s node when network mode is not set to none", n.Cfg.ShortName)
+		err := n.checkInterfacePort(e)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkInterfacePort checks that the interface references a port that exists on the node type.
+// Only the fixed form factor types with a known number of ports are checked.
+func (n *srl) checkInterfacePort(e clablinks.Endpoint) error {
+	ports, found := srlTypePorts[n.Cfg.NodeType]
+	if !found {
+		return nil
+	}
+
+	captureGroups, err := clabutils.GetRegexpCaptureGroups(MappedInterfaceRegexp, e.GetIfaceName())
+	if err != nil {
+		return err
+	}
+
+	linecard, _ := strconv.Atoi(captureGroups["linecard"])
+	if linecard != 1 {
+		return fmt.Errorf("%s: interface %q references linecard %d, but node type %s has a single linecard",
+			n.Cfg.ShortName, e.GetIfaceDisplayName(), linecard, n.Cfg.NodeType)
+	}
+
+	port, _ := strconv.Atoi(captureGroups["port"])
+	if port < 1 || port > ports {
+		return fmt.Errorf("%s: interface %q references port %d, but node type %s has ports 1-%d",
+			n.Cfg.ShortName, e.GetIfaceDisplayName(), port, n.Cfg.NodeType, ports)
+	}
+
+	if captureGroups["channel"] != "" {
+		channel, _ := strconv.Atoi(captureGroups["channel"])
+		if channel < 1 || channel > srlMaxBreakoutChannels {
+			return fmt.Errorf("%s: interface %q references breakout channel %d, but at most %d channels are supported",
+				n.Cfg.ShortName, e.GetIfaceDisplayName(), channel, srlMaxBreakoutChannels)
 		}
 	}
 
@@ -110,6 +110,71 @@ func TestSRLInterfaceParsing(t *testing.T) {
 			checkErrContains: InterfaceHelp,
 			resultEps:        []string{},
 		},
+		"port-out-of-range": {
+			endpoints: []*clablinks.EndpointVeth{
+				{
+					EndpointGeneric: clablinks.EndpointGeneric{
+						IfaceName: "e1-34",
+					},
+				},
+				{
+					EndpointGeneric: clablinks.EndpointGeneric{
+						IfaceName: "ethernet-1/35",
+					},
+				},
+			},
+			node: &srl{
+				DefaultNode: clabnodes.DefaultNode{
+					Cfg: &clabtypes.NodeConfig{
+						ShortName: "srl",
+						NodeType:  "ixr-d3l",
+					},
+					InterfaceRegexp: InterfaceRegexp,
+				},
+			},
+			checkErrContains: `"ethernet-1/35" references port 35`,
+			resultEps:        []string{},
+		},
+		"linecard-out-of-range": {
+			endpoints: []*clablinks.EndpointVeth{
+				{
+					EndpointGeneric: clablinks.EndpointGeneric{
+						IfaceName: "e2-1",
+					},
+				},
+			},
+			node: &srl{
+				DefaultNode: clabnodes.DefaultNode{
+					Cfg: &clabtypes.NodeConfig{
+						ShortName: "srl",
+						NodeType:  "ixr-d2l",
+					},
+					InterfaceRegexp: InterfaceRegexp,
+				},
+			},
+			checkErrContains: "has a single linecard",
+			resultEps:        []string{},
+		},
+		"breakout-channel-out-of-range": {
+			endpoints: []*clablinks.EndpointVeth{
+				{
+					EndpointGeneric: clablinks.EndpointGeneric{
+						IfaceName: "ethernet-1/1/9",
+					},
+				},
+			},
+			node: &srl{
+				DefaultNode: clabnodes.DefaultNode{
+					Cfg: &clabtypes.NodeConfig{
+						ShortName: "srl",
+						NodeType:  "ixr-h4",
+					},
+					InterfaceRegexp: InterfaceRegexp,
+				},
+			},
+			checkErrContains: "breakout channel 9",
+			resultEps:        []string{},
+		},
 	}
 
 	for name, tc := range tests {
@@ -150,3 +215,24 @@ func TestSRLInterfaceParsing(t *testing.T) {
 		})
 	}
 }
+
+func TestSRLNativeInterfaceName(t *testing.T) {
+	tests := map[string]string{
+		"e1-1":         "ethernet-1/1",
+		"e2-10":        "ethernet-2/10",
+		"e1-3-2":       "ethernet-1/3/2",
+		"mgmt0":        "mgmt0",
+		"ethernet-1/1": "ethernet-1/1",
+	}
+
+	n := &srl{}
+	for ifName, want := range tests {
+		got, err := n.GetNativeInterfaceName(ifName)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", ifName, err)
+		}
+		if got != want {
+			t.Errorf("got native interface name %q for %q, want %q", got, ifName, want)
+		}
+	}
+}
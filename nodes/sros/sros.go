@@ -213,6 +213,11 @@ func (n *sros) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) err
 }
 
 // Pre Deploy func for SR-SIM kind.
+// FileCopyPath returns the path of the file on the SR OS filesystem, copied over SFTP to the node.
+func (*sros) FileCopyPath(p string) string {
+	return clabnodes.SROSFilePath(p)
+}
+
 func (n *sros) PreDeploy(_ context.Context, params *clabnodes.PreDeployParams) error {
 	log.Debug("Running pre-deploy")
 	// store the preDeployParams
@@ -997,6 +1002,27 @@ func (n *sros) GetMappedInterfaceName(ifName string) (string, error) {
 	}
 }
 
+// GetNativeInterfaceName returns the SR OS interface name for the mapped interface name,
+// e.g. e1-2-c3-4 -> 1/2/c3/4. Management interfaces are returned unchanged.
+func (*sros) GetNativeInterfaceName(ifName string) (string, error) {
+	captureGroups, err := clabutils.GetRegexpCaptureGroups(MappedInterfaceRegexp, ifName)
+	if err != nil || captureGroups["port"] == "" {
+		return ifName, nil //nolint: nilerr
+	}
+
+	parts := []string{captureGroups["card"]}
+	if xiom := captureGroups["xiom"]; xiom != "" {
+		parts = append(parts, "x"+xiom)
+	}
+	parts = append(parts, captureGroups["mda"])
+	if connector := captureGroups["connector"]; connector != "" {
+		parts = append(parts, "c"+connector)
+	}
+	parts = append(parts, captureGroups["port"])
+
+	return strings.Join(parts, "/"), nil
+}
+
 // CheckInterfaceName checks if a name of the interface referenced in the topology file correct.
 func (n *sros) CheckInterfaceName() error {
 	nm := strings.ToLower(n.Cfg.NetworkMode)
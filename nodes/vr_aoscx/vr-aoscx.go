@@ -14,9 +14,10 @@ var (
 	kindNames          = []string{"aruba_aoscx", "vr-aoscx", "vr-aruba_aoscx"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`1/1/(?P<port>\d+)`)
-	InterfaceOffset = 1
-	InterfaceHelp   = "1/1/X (where X >= 1) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`1/1/(?P<port>\d+)`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "1/1/%d"
+	InterfaceHelp         = "1/1/X (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -45,6 +46,7 @@ func (n *vrAosCX) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption)
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -73,6 +75,7 @@ func (n *vrAosCX) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption)
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
@@ -18,9 +18,10 @@ var (
 	kindNames          = []string{"cisco_c8000v"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?(?P<port>\d+)$`)
-	InterfaceOffset = 2
-	InterfaceHelp   = "GiX or GigabitEthernetX (where X >= 2) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?(?P<port>\d+)$`)
+	InterfaceOffset       = 2
+	InterfaceNativeFormat = "GigabitEthernet%d"
+	InterfaceHelp         = "GiX or GigabitEthernetX (where X >= 2) or ethX (where X >= 1)"
 )
 
 const (
@@ -53,6 +54,7 @@ func (n *vrC8000v) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption)
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -81,6 +83,7 @@ func (n *vrC8000v) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption)
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
@@ -18,9 +18,10 @@ var (
 	kindNames          = []string{"cisco_cat9kv"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?1/0/(?P<port>\d+)$`)
-	InterfaceOffset = 1
-	InterfaceHelp   = "Gi1/0/X or GigabitEthernet1/0/X (where X >= 1) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?1/0/(?P<port>\d+)$`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "GigabitEthernet1/0/%d"
+	InterfaceHelp         = "Gi1/0/X or GigabitEthernet1/0/X (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -53,6 +54,7 @@ func (n *vrCat9kv) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption)
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -84,6 +86,7 @@ func (n *vrCat9kv) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption)
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
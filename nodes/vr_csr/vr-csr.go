@@ -18,9 +18,10 @@ var (
 	kindnames          = []string{"cisco_csr1000v", "vr-csr", "vr-cisco_csr1000v"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?(?P<port>\d+)$`)
-	InterfaceOffset = 2
-	InterfaceHelp   = "GiX or GigabitEthernetX (where X >= 2) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?(?P<port>\d+)$`)
+	InterfaceOffset       = 2
+	InterfaceNativeFormat = "GigabitEthernet%d"
+	InterfaceHelp         = "GiX or GigabitEthernetX (where X >= 2) or ethX (where X >= 1)"
 )
 
 const (
@@ -49,6 +50,7 @@ func (n *vrCsr) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -77,6 +79,7 @@ func (n *vrCsr) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
@@ -18,12 +18,13 @@ import (
 )
 
 var (
-	kindNames          = []string{"freebsd"}
-	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
-	saveCmd            = "sh -c \"/backup.sh -u $USERNAME -p $PASSWORD backup\""
-	InterfaceRegexp    = regexp.MustCompile(`vtnet(?P<port>\d+)`)
-	InterfaceOffset    = 1
-	InterfaceHelp      = "vtnetX (where X >= 1) or ethX (where X >= 1)"
+	kindNames             = []string{"freebsd"}
+	defaultCredentials    = clabnodes.NewCredentials("admin", "admin")
+	saveCmd               = "sh -c \"/backup.sh -u $USERNAME -p $PASSWORD backup\""
+	InterfaceRegexp       = regexp.MustCompile(`vtnet(?P<port>\d+)`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "vtnet%d"
+	InterfaceHelp         = "vtnetX (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -52,6 +53,7 @@ func (n *vrFreeBSD) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, "")
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -80,6 +82,7 @@ func (n *vrFreeBSD) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
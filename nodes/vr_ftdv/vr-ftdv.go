@@ -17,9 +17,10 @@ var (
 	kindNames          = []string{"cisco_ftdv"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "Admin@123")
 
-	InterfaceRegexp = regexp.MustCompile(`(?:GigabitEthernet|Gi)\s?0/(?P<port>\d+)`)
-	InterfaceOffset = 0
-	InterfaceHelp   = "GigabitEthernet0/X or Gi0/X (where X >= 0) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`(?:GigabitEthernet|Gi)\s?0/(?P<port>\d+)`)
+	InterfaceOffset       = 0
+	InterfaceNativeFormat = "GigabitEthernet0/%d"
+	InterfaceHelp         = "GigabitEthernet0/X or Gi0/X (where X >= 0) or ethX (where X >= 1)"
 )
 
 const (
@@ -52,6 +53,7 @@ func (n *vrFtdv) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -77,6 +79,7 @@ func (n *vrFtdv) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
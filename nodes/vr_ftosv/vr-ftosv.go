@@ -47,6 +47,7 @@ func (n *vrFtosv) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption)
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, n.ScrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
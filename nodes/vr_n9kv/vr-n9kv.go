@@ -19,9 +19,10 @@ var (
 	kindnames          = []string{"cisco_n9kv", "vr-n9kv", "vr-cisco_n9kv"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`(?:Ethernet|Et)\s?1/(?P<port>\d+)`)
-	InterfaceOffset = 1
-	InterfaceHelp   = "Ethernet1/X or Et1/X (where X >= 1) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`(?:Ethernet|Et)\s?1/(?P<port>\d+)`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "Ethernet1/%d"
+	InterfaceHelp         = "Ethernet1/X or Et1/X (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -52,6 +53,7 @@ func (n *vrN9kv) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -80,6 +82,7 @@ func (n *vrN9kv) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/charmbracelet/log"
 	clablinks "github.com/srl-labs/containerlab/links"
@@ -16,6 +18,12 @@ import (
 
 var VMInterfaceRegexp = regexp.MustCompile(`eth[1-9]\d*$`) // skipcq: GO-C4007
 
+// srosFlashRegexp matches the SR OS file paths on a compact flash, e.g. cf3:/config.cfg.
+var srosFlashRegexp = regexp.MustCompile(`^cf\d:`)
+
+// VRConsolePort is the TCP port vrnetlab exposes the VM serial console on inside the container.
+const VRConsolePort = 5000
+
 type VRNode struct {
 	DefaultNode
 	ScrapliPlatformName string
@@ -46,6 +54,20 @@ func (n *VRNode) Init(cfg *clabtypes.NodeConfig, opts ...NodeOption) error {
 	return nil
 }
 
+// FileCopyPath returns the path of the file in the VM, the files of the VM-based nodes are copied over SFTP.
+func (*VRNode) FileCopyPath(p string) string {
+	return p
+}
+
+// SROSFilePath returns the path of a file on the SR OS filesystem, the paths without
+// a compact flash prefix, e.g. cf1:, are on the cf3: flash of the node.
+func SROSFilePath(p string) string {
+	if srosFlashRegexp.MatchString(p) {
+		return p
+	}
+	return "cf3:/" + strings.TrimPrefix(p, "/")
+}
+
 // PreDeploy default function: create lab directory, generate certificates, generate startup config file.
 func (n *VRNode) PreDeploy(_ context.Context, params *PreDeployParams) error {
 	clabutils.CreateDirectory(n.Cfg.LabDir, 0o777)
@@ -94,6 +116,11 @@ func (vr *VRNode) CheckInterfaceName() error {
 	return nil
 }
 
+// ConsoleCmd returns the command to attach to the serial console of the VM.
+func (*VRNode) ConsoleCmd() []string {
+	return []string{"telnet", "127.0.0.1", strconv.Itoa(VRConsolePort)}
+}
+
 func (n *VRNode) SaveConfig(_ context.Context) error {
 	config, err := clabnetconf.GetConfig(n.Cfg.LongName,
 		n.Credentials.GetUsername(),
@@ -110,3 +110,22 @@ func TestVMInterfaceAliases(t *testing.T) { // skipcq: GO-R1005
 		})
 	}
 }
+
+func TestSROSFilePath(t *testing.T) {
+	tests := map[string]struct {
+		path string
+		want string
+	}{
+		"relative": {path: "scripts/run.py", want: "cf3:/scripts/run.py"},
+		"absolute": {path: "/scripts/run.py", want: "cf3:/scripts/run.py"},
+		"flash":    {path: "cf1:/dump.log", want: "cf1:/dump.log"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := SROSFilePath(tt.path); got != tt.want {
+				t.Errorf("SROSFilePath(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
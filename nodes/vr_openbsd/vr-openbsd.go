@@ -22,9 +22,10 @@ var (
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 	saveCmd            = "sh -c \"/backup.sh -u $USERNAME -p $PASSWORD backup\""
 
-	InterfaceRegexp = regexp.MustCompile(`vio(?P<port>\d+)`)
-	InterfaceOffset = 1
-	InterfaceHelp   = "vioX (where X >= 1) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`vio(?P<port>\d+)`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "vio%d"
+	InterfaceHelp         = "vioX (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -53,6 +54,7 @@ func (n *vrOpenBSD) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, "")
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -81,6 +83,7 @@ func (n *vrOpenBSD) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
@@ -18,9 +18,10 @@ var (
 	kindnames          = []string{"paloalto_panos", "vr-pan", "vr-paloalto_panos"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "Admin@123")
 
-	InterfaceRegexp = regexp.MustCompile(`Ethernet1/(?P<port>\d+)`)
-	InterfaceOffset = 1
-	InterfaceHelp   = "Ethernet1/1 (where X >= 1) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`Ethernet1/(?P<port>\d+)`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "Ethernet1/%d"
+	InterfaceHelp         = "Ethernet1/1 (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -49,6 +50,7 @@ func (n *vrPan) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -79,6 +81,7 @@ func (n *vrPan) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
@@ -18,9 +18,10 @@ var (
 	kindnames          = []string{"mikrotik_ros", "vr-ros", "vr-mikrotik_ros"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`ether(?P<port>\d+)`)
-	InterfaceOffset = 2
-	InterfaceHelp   = "etherX (where X >= 2) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`ether(?P<port>\d+)`)
+	InterfaceOffset       = 2
+	InterfaceNativeFormat = "ether%d"
+	InterfaceHelp         = "etherX (where X >= 2) or ethX (where X >= 1)"
 )
 
 const (
@@ -54,6 +55,7 @@ func (n *vrRos) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 	n.StartupCfgFName = startupCfgFName
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -80,6 +82,7 @@ func (n *vrRos) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
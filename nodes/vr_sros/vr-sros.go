@@ -35,9 +35,10 @@ var (
 	kindNames          = []string{"nokia_sros", "vr-sros", "vr-nokia_sros"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`1/1/(?P<port>\d+)`)
-	InterfaceOffset = 1
-	InterfaceHelp   = "1/1/X (where X >= 1) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`1/1/(?P<port>\d+)`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "1/1/%d"
+	InterfaceHelp         = "1/1/X (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -82,6 +83,7 @@ func (s *vrSROS) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 	s.VRNode = *clabnodes.NewVRNode(s, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	s.HostRequirements.VirtRequired = true
+	s.SecurityProfile.KVM = true
 	s.LicensePolicy = clabtypes.LicensePolicyWarn
 	// SR OS requires unbound pubkey authentication mode until this is
 	// gets fixed in later SR OS release.
@@ -117,11 +119,17 @@ func (s *vrSROS) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 
 	s.InterfaceRegexp = InterfaceRegexp
 	s.InterfaceOffset = InterfaceOffset
+	s.InterfaceNativeFormat = InterfaceNativeFormat
 	s.InterfaceHelp = InterfaceHelp
 
 	return nil
 }
 
+// FileCopyPath returns the path of the file on the SR OS filesystem.
+func (*vrSROS) FileCopyPath(p string) string {
+	return clabnodes.SROSFilePath(p)
+}
+
 func (s *vrSROS) PreDeploy(_ context.Context, params *clabnodes.PreDeployParams) error {
 	clabutils.CreateDirectory(s.Cfg.LabDir, 0o777)
 	_, err := s.LoadOrGenerateCertificate(params.Cert, params.TopologyName)
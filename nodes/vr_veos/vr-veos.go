@@ -18,9 +18,10 @@ var (
 	kindNames          = []string{"arista_veos", "vr-veos", "vr-arista_veos"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin")
 
-	InterfaceRegexp = regexp.MustCompile(`(?:Et|Ethernet)1/(?P<port>\d+)`)
-	InterfaceOffset = 1
-	InterfaceHelp   = "Et1/X or Ethernet1/X (where X >= 1) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`(?:Et|Ethernet)1/(?P<port>\d+)`)
+	InterfaceOffset       = 1
+	InterfaceNativeFormat = "Ethernet1/%d"
+	InterfaceHelp         = "Et1/X or Ethernet1/X (where X >= 1) or ethX (where X >= 1)"
 )
 
 const (
@@ -55,6 +56,7 @@ func (n *vrVEOS) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -83,6 +85,7 @@ func (n *vrVEOS) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) e
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
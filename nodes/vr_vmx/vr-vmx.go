@@ -18,9 +18,10 @@ var (
 	kindNames          = []string{"juniper_vmx", "vr-vmx", "vr-juniper_vmx"}
 	defaultCredentials = clabnodes.NewCredentials("admin", "admin@123")
 
-	InterfaceRegexp = regexp.MustCompile(`(?:et|xe|ge)-0/0/(?P<port>\d+)$`)
-	InterfaceOffset = 0
-	InterfaceHelp   = "(et|xe|ge)-0/0/X (where X >= 0) or ethX (where X >= 1)"
+	InterfaceRegexp       = regexp.MustCompile(`(?:et|xe|ge)-0/0/(?P<port>\d+)$`)
+	InterfaceOffset       = 0
+	InterfaceNativeFormat = "ge-0/0/%d"
+	InterfaceHelp         = "(et|xe|ge)-0/0/X (where X >= 0) or ethX (where X >= 1)"
 )
 
 const (
@@ -55,6 +56,7 @@ func (n *vrVMX) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -78,6 +80,7 @@ func (n *vrVMX) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
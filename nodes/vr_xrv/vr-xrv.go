@@ -15,11 +15,12 @@ import (
 )
 
 var (
-	kindnames          = []string{"cisco_xrv", "vr-xrv", "vr-cisco_xrv"}
-	defaultCredentials = clabnodes.NewCredentials("clab", "clab@123")
-	InterfaceRegexp    = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?0/0/0/(?P<port>\d+)$`)
-	InterfaceOffset    = 0
-	InterfaceHelp      = "GigabitEthernet0/0/0/X or Gi0/0/0/X (where X >= 0) or ethX (where X >= 1)"
+	kindnames             = []string{"cisco_xrv", "vr-xrv", "vr-cisco_xrv"}
+	defaultCredentials    = clabnodes.NewCredentials("clab", "clab@123")
+	InterfaceRegexp       = regexp.MustCompile(`(?:Gi|GigabitEthernet)\s?0/0/0/(?P<port>\d+)$`)
+	InterfaceOffset       = 0
+	InterfaceNativeFormat = "GigabitEthernet0/0/0/%d"
+	InterfaceHelp         = "GigabitEthernet0/0/0/X or Gi0/0/0/X (where X >= 0) or ethX (where X >= 1)"
 )
 
 const (
@@ -50,6 +51,7 @@ func (n *vrXRV) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 	n.VRNode = *clabnodes.NewVRNode(n, defaultCredentials, scrapliPlatformName)
 	// set virtualization requirement
 	n.HostRequirements.VirtRequired = true
+	n.SecurityProfile.KVM = true
 
 	n.Cfg = cfg
 	for _, o := range opts {
@@ -78,6 +80,7 @@ func (n *vrXRV) Init(cfg *clabtypes.NodeConfig, opts ...clabnodes.NodeOption) er
 
 	n.InterfaceRegexp = InterfaceRegexp
 	n.InterfaceOffset = InterfaceOffset
+	n.InterfaceNativeFormat = InterfaceNativeFormat
 	n.InterfaceHelp = InterfaceHelp
 
 	return nil
@@ -0,0 +1,83 @@
+package runtime
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+// FileArchive returns a tar archive of the file at the absolute path p with the content read from r,
+// size being the length of the content. The archive holds the parent directories of the file,
+// so that it can be extracted at the root of a container creating the missing directories.
+func FileArchive(p string, r io.Reader, size int64, mode fs.FileMode) io.Reader {
+	pr, pw := io.Pipe()
+
+	go func() {
+		tw := tar.NewWriter(pw)
+
+		name := strings.TrimPrefix(path.Clean("/"+p), "/")
+
+		dirs := strings.Split(path.Dir(name), "/")
+		for i := range dirs {
+			if dirs[i] == "." {
+				break
+			}
+			err := tw.WriteHeader(&tar.Header{
+				Typeflag: tar.TypeDir,
+				Name:     strings.Join(dirs[:i+1], "/") + "/",
+				Mode:     0o755,
+			})
+			if err != nil {
+				pw.CloseWithError(err)
+				return
+			}
+		}
+
+		err := tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     int64(mode.Perm()),
+			Size:     size,
+		})
+		if err == nil {
+			_, err = io.Copy(tw, r)
+		}
+		if err == nil {
+			err = tw.Close()
+		}
+
+		pw.CloseWithError(err)
+	}()
+
+	return pr
+}
+
+// ExtractFile writes the content of the file of the tar archive of a container file to w,
+// the archive of a directory is an error.
+func ExtractFile(r io.Reader, w io.Writer) error {
+	tr := tar.NewReader(r)
+
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("no file found in the archive")
+		}
+		if err != nil {
+			return err
+		}
+
+		if h.Typeflag == tar.TypeDir {
+			return fmt.Errorf("%s is a directory", strings.TrimSuffix(h.Name, "/"))
+		}
+		if h.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		_, err = io.Copy(w, tr)
+
+		return err
+	}
+}
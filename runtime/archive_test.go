@@ -0,0 +1,59 @@
+package runtime
+
+import (
+	"archive/tar"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFileArchive(t *testing.T) {
+	a := FileArchive("/opt/scripts/run.sh", strings.NewReader("echo hi\n"), 8, 0o755)
+
+	b, err := io.ReadAll(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	tr := tar.NewReader(bytes.NewReader(b))
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, h.Name)
+	}
+
+	if d := cmp.Diff([]string{"opt/", "opt/scripts/", "opt/scripts/run.sh"}, names); d != "" {
+		t.Errorf("archive entries mismatch (-want +got):\n%s", d)
+	}
+
+	// the runtimes archive the file of a container without its parent directories
+	var out bytes.Buffer
+	if err := ExtractFile(FileArchive("run.sh", strings.NewReader("echo hi\n"), 8, 0o755), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "echo hi\n" {
+		t.Errorf("unexpected extracted content %q", out.String())
+	}
+}
+
+func TestExtractFileDir(t *testing.T) {
+	var b bytes.Buffer
+	tw := tar.NewWriter(&b)
+	if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: "cores/", Mode: 0o755}); err != nil {
+		t.Fatal(err)
+	}
+	tw.Close()
+
+	if err := ExtractFile(&b, io.Discard); err == nil {
+		t.Error("expected an error for a directory")
+	}
+}
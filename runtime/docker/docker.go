@@ -9,6 +9,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"path"
 	"strconv"
@@ -48,6 +50,7 @@ const (
 	defaultDockerNetwork = "bridge"
 
 	natUnprotectedValue         = "nat-unprotected"
+	routedValue                 = "routed"
 	bridgeGatewayModeIPv4Option = "com.docker.network.bridge.gateway_mode_ipv4"
 	bridgeGatewayModeIPv6Option = "com.docker.network.bridge.gateway_mode_ipv6"
 )
@@ -172,6 +175,8 @@ func (d *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 	log.Debugf("Checking if docker network %q exists", d.mgmt.Network)
 	netResource, err := d.Client.NetworkInspect(nctx, d.mgmt.Network, networkapi.InspectOptions{})
 	switch {
+	case dockerC.IsErrNotFound(err) && d.mgmt.Existing:
+		return fmt.Errorf("management network %q does not exist", d.mgmt.Network)
 	case dockerC.IsErrNotFound(err):
 		bridgeName, err = d.createMgmtBridge(nctx, bridgeName)
 		if err != nil {
@@ -179,6 +184,10 @@ func (d *DockerRuntime) CreateNet(ctx context.Context) (err error) {
 		}
 	case err == nil:
 		log.Debugf("network %q was found. Reusing it...", d.mgmt.Network)
+		if d.mgmt.Mode != "" && d.mgmt.Mode != clabtypes.MgmtModeNAT {
+			log.Warnf("management network %q exists, its settings are kept instead of the %s mode",
+				d.mgmt.Network, d.mgmt.Mode)
+		}
 		if len(netResource.ID) < 12 {
 			return fmt.Errorf("could not get bridge ID")
 		}
@@ -231,6 +240,8 @@ func (d *DockerRuntime) createMgmtBridge( //nolint: funlen
 	var ipamConfig []networkapi.IPAMConfig
 
 	var v4gw, v6gw string
+	// the bridge is created with the network unless the user set an existing one
+	bridgeExists := false
 	// check if IPv4/6 addr are assigned to a mgmt bridge
 	if d.mgmt.Bridge != "" {
 		v4gw, v6gw, err = clabutils.FirstLinkIPs(d.mgmt.Bridge)
@@ -241,6 +252,7 @@ func (d *DockerRuntime) createMgmtBridge( //nolint: funlen
 				return "", err
 			}
 		}
+		bridgeExists = err == nil
 		log.Debugf("bridge %q has ipv4 addr of %q and ipv6 addr of %q", d.mgmt.Bridge, v4gw, v6gw)
 	}
 
@@ -296,12 +308,28 @@ func (d *DockerRuntime) createMgmtBridge( //nolint: funlen
 		netwOpts["com.docker.network.bridge.name"] = bridgeName
 	}
 
-	// nat-unprotected mode is needed starting in Docker release 28 to access all ports without exposing them explicitly
-	// see https://github.com/srl-labs/containerlab/issues/2638
-	if semver.Compare(d.version, "v28.0.0") > 0 {
-		log.Debug("Using Docker version 28 or later, enabling NAT unprotected mode on bridge")
-		netwOpts[bridgeGatewayModeIPv4Option] = natUnprotectedValue
-		netwOpts[bridgeGatewayModeIPv6Option] = natUnprotectedValue
+	internal := false
+
+	switch d.mgmt.Mode {
+	case clabtypes.MgmtModeRouted:
+		// the routed gateway mode is available starting in Docker release 27
+		if semver.Compare(d.version, "v27.0.0") < 0 {
+			return "", fmt.Errorf("routed management network requires Docker 27 or later, found %s", d.version)
+		}
+		log.Info("Management network is routed, the nodes are reachable with a route to its subnets via this host",
+			"IPv4 subnet", d.mgmt.IPv4Subnet, "IPv6 subnet", ipv6_subnet)
+		netwOpts[bridgeGatewayModeIPv4Option] = routedValue
+		netwOpts[bridgeGatewayModeIPv6Option] = routedValue
+	case clabtypes.MgmtModeIsolated:
+		internal = true
+	default:
+		// nat-unprotected mode is needed starting in Docker release 28 to access all ports without exposing them explicitly
+		// see https://github.com/srl-labs/containerlab/issues/2638
+		if semver.Compare(d.version, "v28.0.0") > 0 {
+			log.Debug("Using Docker version 28 or later, enabling NAT unprotected mode on bridge")
+			netwOpts[bridgeGatewayModeIPv4Option] = natUnprotectedValue
+			netwOpts[bridgeGatewayModeIPv6Option] = natUnprotectedValue
+		}
 	}
 
 	// Merge in bridge network driver options from topology file
@@ -314,7 +342,7 @@ func (d *DockerRuntime) createMgmtBridge( //nolint: funlen
 		Driver:     "bridge",
 		EnableIPv6: clabutils.Pointer(enableIPv6),
 		IPAM:       ipam,
-		Internal:   false,
+		Internal:   internal,
 		Attachable: false,
 		Labels: map[string]string{
 			"containerlab": "",
@@ -335,6 +363,11 @@ func (d *DockerRuntime) createMgmtBridge( //nolint: funlen
 	if bridgeName == "" {
 		bridgeName = "br-" + netCreateResponse.ID[:12]
 	}
+
+	if !bridgeExists {
+		markMgmtBridge(bridgeName)
+	}
+
 	return bridgeName, nil
 }
 
@@ -407,7 +440,7 @@ func (d *DockerRuntime) postCreateNetActions() (err error) {
 // DeleteNet deletes a docker bridge.
 func (d *DockerRuntime) DeleteNet(ctx context.Context) (err error) {
 	network := d.mgmt.Network
-	if network == "bridge" || d.config.KeepMgmtNet {
+	if network == "bridge" || d.mgmt.Existing || d.config.KeepMgmtNet {
 		log.Debugf("Skipping deletion of %q network", network)
 		return nil
 	}
@@ -571,6 +604,10 @@ func (d *DockerRuntime) CreateContainer( //nolint: funlen
 		containerHostConfig.CapAdd = append(containerHostConfig.CapAdd, node.CapAdd...)
 	}
 
+	if len(node.SecurityOpts) > 0 {
+		containerHostConfig.SecurityOpt = append(containerHostConfig.SecurityOpt, node.SecurityOpts...)
+	}
+
 	if err := d.processNetworkMode(ctx, containerNetworkingConfig, containerHostConfig, containerConfig, node); err != nil {
 		return "", err
 	}
@@ -678,6 +715,23 @@ func (d *DockerRuntime) PullImage(ctx context.Context, imageName string, pullPol
 	return reader.Close()
 }
 
+// InspectImage returns the identity of a local image, nil if the image is not present locally.
+func (d *DockerRuntime) InspectImage(ctx context.Context, imageName string) (*clabruntime.ImageInfo, error) {
+	img, _, err := d.Client.ImageInspectWithRaw(ctx, clabutils.GetCanonicalImageName(imageName))
+	if err != nil {
+		if dockerC.IsErrNotFound(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return &clabruntime.ImageInfo{
+		ID:          img.ID,
+		RepoDigests: img.RepoDigests,
+	}, nil
+}
+
 // StartContainer starts a docker container.
 func (d *DockerRuntime) StartContainer(ctx context.Context, cID string, node clabruntime.Node) (interface{}, error) {
 	nctx, cancel := context.WithTimeout(ctx, d.config.Timeout)
@@ -1168,6 +1222,56 @@ func (d *DockerRuntime) WriteToStdinNoWait(ctx context.Context, cID string, data
 	return stdin.Conn.Close()
 }
 
+// StreamContainerLogs writes the stdout/stderr logs of a container to w.
+func (d *DockerRuntime) StreamContainerLogs(ctx context.Context, cID string, follow bool, w io.Writer) error {
+	inspect, err := d.Client.ContainerInspect(ctx, cID)
+	if err != nil {
+		return fmt.Errorf("container %q cannot be found: %w", cID, err)
+	}
+
+	rc, err := d.Client.ContainerLogs(ctx, cID, container.LogsOptions{
+		ShowStdout: true,
+		ShowStderr: true,
+		Follow:     follow,
+	})
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	// containers without a tty have their stdout and stderr streams multiplexed
+	if inspect.Config != nil && inspect.Config.Tty {
+		_, err = io.Copy(w, rc)
+	} else {
+		_, err = stdcopy.StdCopy(w, w, rc)
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+
+	return err
+}
+
+// CopyToContainer writes the content read from r to the file at the path in the container.
+func (d *DockerRuntime) CopyToContainer(ctx context.Context, cID, path string, r io.Reader, size int64,
+	mode fs.FileMode,
+) error {
+	return d.Client.CopyToContainer(ctx, cID, "/", clabruntime.FileArchive(path, r, size, mode),
+		container.CopyToContainerOptions{})
+}
+
+// CopyFromContainer writes the content of the file at the path in the container to w.
+func (d *DockerRuntime) CopyFromContainer(ctx context.Context, cID, path string, w io.Writer) error {
+	rc, _, err := d.Client.CopyFromContainer(ctx, cID, path)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	return clabruntime.ExtractFile(rc, w)
+}
+
 func (d *DockerRuntime) CheckConnection(ctx context.Context) error {
 	_, err := d.Client.Ping(ctx)
 	if err != nil {
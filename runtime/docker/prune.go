@@ -0,0 +1,109 @@
+package docker
+
+import (
+	"context"
+
+	"github.com/charmbracelet/log"
+	networkapi "github.com/docker/docker/api/types/network"
+	clablabels "github.com/srl-labs/containerlab/labels"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+	"github.com/vishvananda/netlink"
+)
+
+// mgmtBridgeAlias is the interface alias marking the bridges created with the management networks
+// of containerlab, the bridges carry no labels.
+const mgmtBridgeAlias = clablabels.Containerlab
+
+// markMgmtBridge sets the alias of the bridge created with a management network.
+func markMgmtBridge(name string) {
+	l, err := netlink.LinkByName(name)
+	if err == nil {
+		err = netlink.LinkSetAlias(l, mgmtBridgeAlias)
+	}
+	if err != nil {
+		log.Warnf("failed to mark the management bridge %s: %v", name, err)
+	}
+}
+
+// PruneNets returns the networks labeled by containerlab with no containers attached and the
+// bridges marked by containerlab whose network no longer exists, removing them unless dryRun is set.
+func (d *DockerRuntime) PruneNets(ctx context.Context, dryRun bool) ([]*clabruntime.OrphanedResource, error) {
+	nets, err := d.Client.NetworkList(ctx, networkapi.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var res []*clabruntime.OrphanedResource
+
+	// bridges of the existing networks
+	bridges := make(map[string]struct{}, len(nets))
+
+	for _, n := range nets {
+		if len(n.ID) >= 12 { //nolint: mnd
+			bridges["br-"+n.ID[:12]] = struct{}{}
+		}
+		if name := n.Options["com.docker.network.bridge.name"]; name != "" {
+			bridges[name] = struct{}{}
+		}
+
+		if _, ok := n.Labels[clablabels.Containerlab]; !ok {
+			continue
+		}
+
+		// the network list doesn't report the attached containers
+		nres, err := d.Client.NetworkInspect(ctx, n.ID, networkapi.InspectOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if len(nres.Containers) > 0 {
+			continue
+		}
+
+		res = append(res, &clabruntime.OrphanedResource{
+			Type:   "network",
+			Name:   n.Name,
+			Reason: "no containers attached",
+		})
+
+		if dryRun {
+			continue
+		}
+
+		log.Info("Removing network", "name", n.Name)
+		if err := d.Client.NetworkRemove(ctx, n.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	links, err := netlink.LinkList()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, l := range links {
+		name := l.Attrs().Name
+		if l.Type() != "bridge" || l.Attrs().Alias != mgmtBridgeAlias {
+			continue
+		}
+		if _, ok := bridges[name]; ok {
+			continue
+		}
+
+		res = append(res, &clabruntime.OrphanedResource{
+			Type:   "bridge",
+			Name:   name,
+			Reason: "docker network not found",
+		})
+
+		if dryRun {
+			continue
+		}
+
+		log.Info("Removing bridge", "name", name)
+		if err := netlink.LinkDel(l); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
@@ -0,0 +1,68 @@
+package docker
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/docker/docker/api/types/container"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+// GetContainerStats returns the resource usage of a running container. The CPU usage is sampled
+// over the second the docker daemon waits between two reads, as `docker stats --no-stream` does.
+func (d *DockerRuntime) GetContainerStats(ctx context.Context, cID string) (*clabruntime.ContainerStats, error) {
+	resp, err := d.Client.ContainerStats(ctx, cID, false)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var s container.StatsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return nil, err
+	}
+
+	res := statsFromResponse(&s)
+
+	cnt, _, err := d.Client.ContainerInspectWithRaw(ctx, cID, true)
+	if err != nil {
+		return nil, err
+	}
+	if cnt.SizeRw != nil {
+		res.DiskUsage = *cnt.SizeRw
+	}
+
+	return res, nil
+}
+
+// statsFromResponse computes the resource usage of a container from its docker stats.
+func statsFromResponse(s *container.StatsResponse) *clabruntime.ContainerStats {
+	res := &clabruntime.ContainerStats{
+		MemoryUsage: s.MemoryStats.Usage,
+		MemoryLimit: s.MemoryStats.Limit,
+		PIDs:        s.PidsStats.Current,
+	}
+
+	cpuDelta := float64(s.CPUStats.CPUUsage.TotalUsage) - float64(s.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(s.CPUStats.SystemUsage) - float64(s.PreCPUStats.SystemUsage)
+
+	cpus := float64(s.CPUStats.OnlineCPUs)
+	if cpus == 0 {
+		cpus = float64(len(s.CPUStats.CPUUsage.PercpuUsage))
+	}
+
+	if cpuDelta > 0 && systemDelta > 0 {
+		res.CPUPercent = cpuDelta / systemDelta * cpus * 100 //nolint: mnd
+	}
+
+	// the page cache is excluded from the memory usage as docker does,
+	// under the cgroup v1 and v2 stat names
+	for _, k := range []string{"total_inactive_file", "inactive_file"} {
+		if v, ok := s.MemoryStats.Stats[k]; ok && v < res.MemoryUsage {
+			res.MemoryUsage -= v
+			break
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,90 @@
+package docker
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/google/go-cmp/cmp"
+	clabruntime "github.com/srl-labs/containerlab/runtime"
+)
+
+func TestStatsFromResponse(t *testing.T) {
+	tests := map[string]struct {
+		stats *container.StatsResponse
+		want  *clabruntime.ContainerStats
+	}{
+		"cgroup v2": {
+			stats: &container.StatsResponse{Stats: container.Stats{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 3_000_000},
+					SystemUsage: 20_000_000,
+					OnlineCPUs:  4,
+				},
+				PreCPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 1_000_000},
+					SystemUsage: 10_000_000,
+				},
+				MemoryStats: container.MemoryStats{
+					Usage: 300 << 20,
+					Limit: 1 << 30,
+					Stats: map[string]uint64{"inactive_file": 100 << 20},
+				},
+				PidsStats: container.PidsStats{Current: 12},
+			}},
+			want: &clabruntime.ContainerStats{
+				CPUPercent:  80,
+				MemoryUsage: 200 << 20,
+				MemoryLimit: 1 << 30,
+				PIDs:        12,
+			},
+		},
+		"cgroup v1 per cpu usage": {
+			stats: &container.StatsResponse{Stats: container.Stats{
+				CPUStats: container.CPUStats{
+					CPUUsage: container.CPUUsage{
+						TotalUsage:  2_000_000,
+						PercpuUsage: []uint64{1_000_000, 1_000_000},
+					},
+					SystemUsage: 20_000_000,
+				},
+				PreCPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 1_000_000},
+					SystemUsage: 10_000_000,
+				},
+				MemoryStats: container.MemoryStats{
+					Usage: 300 << 20,
+					Limit: 1 << 30,
+					Stats: map[string]uint64{"total_inactive_file": 50 << 20},
+				},
+			}},
+			want: &clabruntime.ContainerStats{
+				CPUPercent:  20,
+				MemoryUsage: 250 << 20,
+				MemoryLimit: 1 << 30,
+			},
+		},
+		"no previous sample": {
+			stats: &container.StatsResponse{Stats: container.Stats{
+				CPUStats: container.CPUStats{
+					CPUUsage:    container.CPUUsage{TotalUsage: 2_000_000},
+					SystemUsage: 20_000_000,
+					OnlineCPUs:  2,
+				},
+				MemoryStats: container.MemoryStats{Usage: 10 << 20},
+			}},
+			want: &clabruntime.ContainerStats{
+				CPUPercent:  20,
+				MemoryUsage: 10 << 20,
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := statsFromResponse(tt.stats)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("statsFromResponse() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
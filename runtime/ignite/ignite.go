@@ -3,6 +3,8 @@ package ignite
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"strings"
 	"time"
@@ -139,6 +141,10 @@ func (c *IgniteRuntime) DeleteNet(ctx context.Context) error {
 	return c.ctrRuntime.DeleteNet(ctx)
 }
 
+func (c *IgniteRuntime) PruneNets(ctx context.Context, dryRun bool) ([]*clabruntime.OrphanedResource, error) {
+	return c.ctrRuntime.PruneNets(ctx, dryRun)
+}
+
 // PullImage pulls the provided image name if it does not exist.
 // Ignite does ignore the pullPolicy though.
 func (*IgniteRuntime) PullImage(_ context.Context, imageName string, _ clabtypes.PullPolicyValue) error {
@@ -480,6 +486,18 @@ func (*IgniteRuntime) WriteToStdinNoWait(_ context.Context, cID string, data []b
 	return nil
 }
 
+func (*IgniteRuntime) StreamContainerLogs(_ context.Context, _ string, _ bool, _ io.Writer) error {
+	return fmt.Errorf("StreamContainerLogs() is unimplemented for ignite runtime")
+}
+
+func (*IgniteRuntime) CopyToContainer(_ context.Context, _, _ string, _ io.Reader, _ int64, _ fs.FileMode) error {
+	return fmt.Errorf("CopyToContainer() is unimplemented for ignite runtime")
+}
+
+func (*IgniteRuntime) CopyFromContainer(_ context.Context, _, _ string, _ io.Writer) error {
+	return fmt.Errorf("CopyFromContainer() is unimplemented for ignite runtime")
+}
+
 func (*IgniteRuntime) CheckConnection(_ context.Context) error {
 	// For now, we only check that KVM path exists and assume Ignite works otherwise
 	if _, err := os.Stat(kvmPath); err != nil {
@@ -496,3 +514,11 @@ func (*IgniteRuntime) GetRuntimeSocket() (string, error) {
 func (*IgniteRuntime) GetCooCBindMounts() clabtypes.Binds {
 	return nil
 }
+
+func (c *IgniteRuntime) InspectImage(ctx context.Context, imageName string) (*clabruntime.ImageInfo, error) {
+	return c.ctrRuntime.InspectImage(ctx, imageName)
+}
+
+func (c *IgniteRuntime) GetContainerStats(ctx context.Context, cID string) (*clabruntime.ContainerStats, error) {
+	return c.ctrRuntime.GetContainerStats(ctx, cID)
+}
@@ -6,8 +6,11 @@ package podman
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -97,6 +100,9 @@ func (r *PodmanRuntime) CreateNet(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if !b && r.mgmt.Existing {
+		return fmt.Errorf("management network %q does not exist", r.mgmt.Network)
+	}
 	// Create if the network doesn't exist
 	if !b {
 		netopts, err := r.netOpts(ctx)
@@ -121,11 +127,17 @@ func (r *PodmanRuntime) CreateNet(ctx context.Context) error {
 	return err
 }
 
+// PruneNets is not implemented for podman, the networks are left to `podman network prune`.
+func (*PodmanRuntime) PruneNets(_ context.Context, _ bool) ([]*runtime.OrphanedResource, error) {
+	log.Debug("pruning the networks is not implemented for podman")
+	return nil, nil
+}
+
 // DeleteNet deletes a clab mgmt bridge.
 func (r *PodmanRuntime) DeleteNet(ctx context.Context) error {
 	// Skip if "keep mgmt" is set
 	log.Debugf("Method DeleteNet was called with runtime inputs %+v and net settings %+v", r, r.mgmt)
-	if r.config.KeepMgmtNet {
+	if r.config.KeepMgmtNet || r.mgmt.Existing {
 		return nil
 	}
 	ctx, err := r.connect(ctx)
@@ -178,6 +190,31 @@ func (r *PodmanRuntime) PullImage(ctx context.Context, image string, pullPolicy
 	return err
 }
 
+// InspectImage returns the identity of a local image, nil if the image is not present locally.
+func (r *PodmanRuntime) InspectImage(ctx context.Context, imageName string) (*runtime.ImageInfo, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	canonicalImage := utils.GetCanonicalImageName(imageName)
+
+	ex, err := images.Exists(ctx, canonicalImage, &images.ExistsOptions{})
+	if err != nil || !ex {
+		return nil, err
+	}
+
+	img, err := images.GetImage(ctx, canonicalImage, &images.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// podman reports the bare image ID, docker prefixes it with the digest algorithm
+	return &runtime.ImageInfo{
+		ID:          "sha256:" + strings.TrimPrefix(img.ID, "sha256:"),
+		RepoDigests: img.RepoDigests,
+	}, nil
+}
+
 // CreateContainer creates a container, but does not start it.
 func (r *PodmanRuntime) CreateContainer(ctx context.Context, cfg *types.NodeConfig) (string, error) {
 	ctx, err := r.connect(ctx)
@@ -396,6 +433,37 @@ func (r *PodmanRuntime) GetContainerStatus(ctx context.Context, cID string) runt
 	return runtime.Stopped
 }
 
+// GetContainerStats returns the resource usage of a running container,
+// the disk usage of its writable layer is not reported.
+func (r *PodmanRuntime) GetContainerStats(ctx context.Context, cID string) (*runtime.ContainerStats, error) {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := containers.Stats(ctx, []string{cID}, new(containers.StatsOptions).WithStream(false))
+	if err != nil {
+		return nil, err
+	}
+
+	report := <-reports
+	if report.Error != nil {
+		return nil, report.Error
+	}
+	if len(report.Stats) == 0 {
+		return nil, fmt.Errorf("no stats reported for container %s", cID)
+	}
+
+	s := report.Stats[0]
+
+	return &runtime.ContainerStats{
+		CPUPercent:  s.CPU,
+		MemoryUsage: s.MemUsage,
+		MemoryLimit: s.MemLimit,
+		PIDs:        s.PIDs,
+	}, nil
+}
+
 // IsHealthy returns true is the container is reported as being healthy, false otherwise.
 func (r *PodmanRuntime) IsHealthy(ctx context.Context, cID string) (bool, error) {
 	ctx, err := r.connect(ctx)
@@ -414,6 +482,90 @@ func (*PodmanRuntime) WriteToStdinNoWait(ctx context.Context, cID string, data [
 	return nil
 }
 
+// StreamContainerLogs writes the stdout/stderr logs of a container to w.
+func (r *PodmanRuntime) StreamContainerLogs(ctx context.Context, cID string, follow bool, w io.Writer) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	stdoutCh := make(chan string)
+	stderrCh := make(chan string)
+	errCh := make(chan error, 1)
+
+	go func() {
+		opts := new(containers.LogOptions).WithStdout(true).WithStderr(true).WithFollow(follow)
+		errCh <- containers.Logs(ctx, cID, opts, stdoutCh, stderrCh)
+	}()
+
+	for {
+		select {
+		// the frames end with the newline of the log line
+		case l := <-stdoutCh:
+			fmt.Fprint(w, l)
+		case l := <-stderrCh:
+			fmt.Fprint(w, l)
+		case err := <-errCh:
+			return err
+		case <-ctx.Done():
+			// the logs request ends with the context, the frames read until then are dropped
+			// so that the reader blocked on sending them returns
+			go func() {
+				for {
+					select {
+					case <-stdoutCh:
+					case <-stderrCh:
+					case <-errCh:
+						return
+					}
+				}
+			}()
+			return nil
+		}
+	}
+}
+
+// CopyToContainer writes the content read from r to the file at the path in the container.
+func (r *PodmanRuntime) CopyToContainer(ctx context.Context, cID, path string, rd io.Reader, size int64,
+	mode fs.FileMode,
+) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	copyFn, err := containers.CopyFromArchive(ctx, cID, "/", runtime.FileArchive(path, rd, size, mode))
+	if err != nil {
+		return err
+	}
+
+	return copyFn()
+}
+
+// CopyFromContainer writes the content of the file at the path in the container to w.
+func (r *PodmanRuntime) CopyFromContainer(ctx context.Context, cID, path string, w io.Writer) error {
+	ctx, err := r.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	pr, pw := io.Pipe()
+
+	copyFn, err := containers.CopyToArchive(ctx, cID, path, pw)
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		pw.CloseWithError(copyFn())
+	}()
+
+	err = runtime.ExtractFile(pr, w)
+	pr.Close()
+
+	return err
+}
+
 func (r *PodmanRuntime) CheckConnection(ctx context.Context) error {
 	_, err := r.connect(ctx)
 	if err != nil {
@@ -424,6 +576,10 @@ func (r *PodmanRuntime) CheckConnection(ctx context.Context) error {
 }
 
 func (r *PodmanRuntime) GetRuntimeSocket() (string, error) {
+	if utils.IsRootless() {
+		return utils.RootlessPodmanSocket(), nil
+	}
+
 	socket := "/run/podman/podman.sock"
 
 	// For rootless podman, check if XDG_RUNTIME_DIR is set
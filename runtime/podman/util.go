@@ -39,6 +39,9 @@ func (*podmanWriterCloser) Close() error {
 }
 
 func (*PodmanRuntime) connect(ctx context.Context) (context.Context, error) {
+	if utils.IsRootless() {
+		return bindings.NewConnection(ctx, "unix://"+utils.RootlessPodmanSocket())
+	}
 	return bindings.NewConnection(ctx, "unix://run/podman/podman.sock")
 }
 
@@ -100,6 +103,20 @@ func (r *PodmanRuntime) createContainerSpec(ctx context.Context, cfg *types.Node
 	specSecurityConfig := specgen.ContainerSecurityConfig{
 		Privileged: utils.Pointer(true),
 		User:       cfg.User,
+		CapAdd:     cfg.CapAdd,
+	}
+	for _, o := range cfg.SecurityOpts {
+		key, val, _ := strings.Cut(o, "=")
+		switch key {
+		case "label":
+			specSecurityConfig.SelinuxOpts = append(specSecurityConfig.SelinuxOpts, val)
+		case "apparmor":
+			specSecurityConfig.ApparmorProfile = val
+		case "seccomp":
+			specSecurityConfig.SeccompProfilePath = val
+		default:
+			log.Warnf("Unsupported security option %q for node %q", o, cfg.ShortName)
+		}
 	}
 	// Going with the defaults for cgroups
 	specCgroupConfig := specgen.ContainerCgroupConfig{
@@ -462,6 +479,13 @@ func (r *PodmanRuntime) netOpts(_ context.Context) (netTypes.Network, error) {
 		log.Debugf("Added v6 subnet info to the net definion: \n%v, \n%v\n", subnets, v6subnet)
 	}
 
+	switch r.mgmt.Mode {
+	case types.MgmtModeRouted:
+		return netTypes.Network{}, fmt.Errorf("routed management network is not supported by the podman runtime")
+	case types.MgmtModeIsolated:
+		internal = true
+	}
+
 	// add custom mtu if defined
 	if r.mgmt.MTU != 0 {
 		options["mtu"] = strconv.Itoa(r.mgmt.MTU)
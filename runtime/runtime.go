@@ -7,6 +7,8 @@ package runtime
 import (
 	"context"
 	"fmt"
+	"io"
+	"io/fs"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -30,8 +32,13 @@ type ContainerRuntime interface {
 	CreateNet(context.Context) error
 	// Delete container (bridge) network
 	DeleteNet(context.Context) error
+	// PruneNets returns the management networks created by containerlab with no containers attached
+	// and the bridges left behind by removed networks, removing them unless dryRun is set
+	PruneNets(ctx context.Context, dryRun bool) ([]*OrphanedResource, error)
 	// Pull container image if not present
 	PullImage(context.Context, string, clabtypes.PullPolicyValue) error
+	// InspectImage returns the identity of a local image, nil if the image is not present locally
+	InspectImage(ctx context.Context, imageName string) (*ImageInfo, error)
 	// CreateContainer creates a container, but does not start it
 	CreateContainer(context.Context, *clabtypes.NodeConfig) (string, error)
 	// Start pre-created container by its name. Returns an extra interface that can be used to receive signals
@@ -60,10 +67,20 @@ type ContainerRuntime interface {
 	GetHostsPath(context.Context, string) (string, error)
 	// GetContainerStatus retrieves the ContainerStatus of the named container
 	GetContainerStatus(ctx context.Context, cID string) ContainerStatus
+	// GetContainerStats returns the resource usage of a running container
+	GetContainerStats(ctx context.Context, cID string) (*ContainerStats, error)
 	// IsHealthy returns true is the container is reported as being healthy, false otherwise
 	IsHealthy(ctx context.Context, cID string) (bool, error)
 	// Immediately write to the stdin of a container, returns error
 	WriteToStdinNoWait(ctx context.Context, cID string, data []byte) error
+	// StreamContainerLogs writes the logs of a container to w, when follow is set
+	// the logs are streamed until the context is cancelled or the container stops
+	StreamContainerLogs(ctx context.Context, cID string, follow bool, w io.Writer) error
+	// CopyToContainer writes the size bytes read from r to the file at the absolute path in the container,
+	// creating its parent directories
+	CopyToContainer(ctx context.Context, cID, path string, r io.Reader, size int64, mode fs.FileMode) error
+	// CopyFromContainer writes the content of the file at the path in the container to w
+	CopyFromContainer(ctx context.Context, cID, path string, w io.Writer) error
 	// CheckConnectivity returns an error if it cannot connect to the runtime, nil otherwise
 	CheckConnection(ctx context.Context) error
 	// GetRuntimeSocket returns the path to the control socket
@@ -74,6 +91,36 @@ type ContainerRuntime interface {
 
 type ContainerStatus string
 
+// OrphanedResource is a resource left behind by a lab, e.g. by a crashed deployment.
+type OrphanedResource struct {
+	// Type is the resource type, e.g. container, veth, bridge or network.
+	Type   string
+	Name   string
+	Reason string
+}
+
+// ContainerStats is the resource usage of a container.
+type ContainerStats struct {
+	// CPUPercent is the CPU usage in percent of a single CPU, it exceeds 100 using several CPUs.
+	CPUPercent float64 `json:"cpu-percent"`
+	// MemoryUsage is the memory used by the container in bytes, the page cache excluded.
+	MemoryUsage uint64 `json:"memory-usage"`
+	// MemoryLimit is the memory limit of the container in bytes, the memory of the host without a limit.
+	MemoryLimit uint64 `json:"memory-limit"`
+	// DiskUsage is the size in bytes of the files written by the container to its writable layer.
+	DiskUsage int64  `json:"disk-usage"`
+	PIDs      uint64 `json:"pids"`
+}
+
+// ImageInfo is the identity of a local container image.
+type ImageInfo struct {
+	// ID is the digest of the image configuration, identifying the images built locally as well.
+	ID string
+	// RepoDigests are the manifest digests of the image in the registries it was pulled from
+	// or pushed to, in the repo@sha256:<digest> format.
+	RepoDigests []string
+}
+
 const (
 	NotFound = "NotFound"
 	Running  = "Running"
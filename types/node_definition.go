@@ -21,6 +21,7 @@ type NodeDefinition struct {
 	EnforceStartupConfig  *bool             `yaml:"enforce-startup-config,omitempty"`
 	SuppressStartupConfig *bool             `yaml:"suppress-startup-config,omitempty"`
 	AutoRemove            *bool             `yaml:"auto-remove,omitempty"`
+	Maintenance           *bool             `yaml:"maintenance,omitempty"`
 	RestartPolicy         string            `yaml:"restart-policy,omitempty"`
 	Config                *ConfigDispatcher `yaml:"config,omitempty"`
 	Image                 string            `yaml:"image,omitempty"`
@@ -37,6 +38,8 @@ type NodeDefinition struct {
 	Devices []string `yaml:"devices,omitempty"`
 	// List of capabilities to add for the container
 	CapAdd []string `yaml:"cap-add,omitempty"`
+	// List of security options for the container
+	SecurityOpts []string `yaml:"security-opts,omitempty"`
 	// Set the shared memory size allocated to the container
 	ShmSize string `yaml:"shm-size,omitempty"`
 	// list of port bindings
@@ -167,6 +170,13 @@ func (n *NodeDefinition) GetAutoRemove() *bool {
 	return n.AutoRemove
 }
 
+func (n *NodeDefinition) GetMaintenance() *bool {
+	if n == nil {
+		return nil
+	}
+	return n.Maintenance
+}
+
 func (n *NodeDefinition) GetRestartPolicy() string {
 	if n == nil {
 		return ""
@@ -244,6 +254,13 @@ func (n *NodeDefinition) GetCapAdd() []string {
 	return n.CapAdd
 }
 
+func (n *NodeDefinition) GetSecurityOpts() []string {
+	if n == nil {
+		return nil
+	}
+	return n.SecurityOpts
+}
+
 func (n *NodeDefinition) GetComponents() []*Component {
 	if n == nil {
 		return nil
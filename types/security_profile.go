@@ -0,0 +1,155 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+// host files reporting the state of the Linux security modules.
+var (
+	selinuxEnforceFile   = "/sys/fs/selinux/enforce"                 //nolint:gochecknoglobals
+	apparmorEnabledFile  = "/sys/module/apparmor/parameters/enabled" //nolint:gochecknoglobals
+	apparmorProfilesFile = "/sys/kernel/security/apparmor/profiles"  //nolint:gochecknoglobals
+	kvmDevice            = "/dev/kvm"                                //nolint:gochecknoglobals
+)
+
+// SecurityProfile is the security settings the containers of a kind require:
+// the capabilities, the host devices and the security options, such as the seccomp profile,
+// the AppArmor profile or the SELinux labels.
+// The profile is set by the kind and applied at container create on top of the settings of the node.
+type SecurityProfile struct {
+	CapAdd []string `json:"cap-add,omitempty"`
+	// Devices are the host devices mapped in the container.
+	Devices []string `json:"devices,omitempty"`
+	// SecurityOpts are the security options in the docker format, e.g. apparmor=unconfined.
+	SecurityOpts []string `json:"security-opts,omitempty"`
+	// KVM indicates that the kind runs a VM needing the /dev/kvm device of the host.
+	// The device is checked by the preflight only, the containers run privileged.
+	KVM bool `json:"kvm,omitempty"`
+}
+
+// NewSecurityProfile is the constructor for new SecurityProfile structs.
+func NewSecurityProfile() *SecurityProfile {
+	return &SecurityProfile{}
+}
+
+// Apply merges the profile into the node config, the settings of the node being kept.
+func (p *SecurityProfile) Apply(cfg *NodeConfig) {
+	cfg.CapAdd = clabutils.MergeStringSlices(p.CapAdd, cfg.CapAdd)
+	cfg.Devices = clabutils.MergeStringSlices(p.Devices, cfg.Devices)
+	cfg.SecurityOpts = clabutils.MergeStringSlices(p.SecurityOpts, cfg.SecurityOpts)
+}
+
+// Preflight returns the host settings blocking the containers of a node with the profile
+// and its security options.
+func (p *SecurityProfile) Preflight(securityOpts []string) []string {
+	var issues []string
+
+	devices := p.Devices
+	if p.KVM {
+		devices = clabutils.MergeStringSlices([]string{kvmDevice}, devices)
+	}
+
+	for _, d := range devices {
+		if issue := checkDevice(d); issue != "" {
+			issues = append(issues, issue)
+		}
+	}
+
+	for _, o := range clabutils.MergeStringSlices(p.SecurityOpts, securityOpts) {
+		if issue := checkSecurityOpt(o); issue != "" {
+			issues = append(issues, issue)
+		}
+	}
+
+	return issues
+}
+
+// checkDevice returns why the host device can't be mapped in a container, empty if it can.
+// The device path may have the path in the container and the permissions appended, as in docker.
+func checkDevice(device string) string {
+	path, _, _ := strings.Cut(device, ":")
+
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	switch {
+	case os.IsNotExist(err):
+		return fmt.Sprintf("device %s does not exist on the host", path)
+	case os.IsPermission(err):
+		return fmt.Sprintf("device %s is not accessible: %v", path, err)
+	case err != nil:
+		// the device exists but can't be opened right now, e.g. a busy tty
+		return ""
+	}
+
+	f.Close()
+
+	return ""
+}
+
+// checkSecurityOpt returns why the security option can't be applied on the host, empty if it can.
+func checkSecurityOpt(opt string) string {
+	key, val, _ := strings.Cut(opt, "=")
+	if val == "unconfined" {
+		return ""
+	}
+
+	switch key {
+	case "seccomp":
+		if !clabutils.FileExists(val) {
+			return fmt.Sprintf("seccomp profile %s does not exist", val)
+		}
+	case "apparmor":
+		if !AppArmorEnabled() {
+			return fmt.Sprintf("AppArmor profile %s is required, but AppArmor is not enabled on the host", val)
+		}
+		if !apparmorProfileLoaded(val) {
+			return fmt.Sprintf("AppArmor profile %s is not loaded on the host", val)
+		}
+	case "label":
+		if val != "disable" && SELinuxMode() == "disabled" {
+			return fmt.Sprintf("SELinux label %s is required, but SELinux is disabled on the host", val)
+		}
+	}
+
+	return ""
+}
+
+// SELinuxMode returns the mode of SELinux on the host: enforcing, permissive or disabled.
+func SELinuxMode() string {
+	b, err := os.ReadFile(selinuxEnforceFile)
+	if err != nil {
+		return "disabled"
+	}
+
+	if strings.TrimSpace(string(b)) == "1" {
+		return "enforcing"
+	}
+
+	return "permissive"
+}
+
+// AppArmorEnabled returns true if AppArmor is enabled on the host.
+func AppArmorEnabled() bool {
+	b, err := os.ReadFile(apparmorEnabledFile)
+	return err == nil && strings.TrimSpace(string(b)) == "Y"
+}
+
+// apparmorProfileLoaded returns true if the AppArmor profile is loaded,
+// the profiles being listed as `<name> (<mode>)`.
+func apparmorProfileLoaded(name string) bool {
+	b, err := os.ReadFile(apparmorProfilesFile)
+	if err != nil {
+		return false
+	}
+
+	for _, l := range strings.Split(string(b), "\n") {
+		if n, _, _ := strings.Cut(l, " ("); n == name {
+			return true
+		}
+	}
+
+	return false
+}
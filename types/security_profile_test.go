@@ -0,0 +1,135 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	clabutils "github.com/srl-labs/containerlab/utils"
+)
+
+func TestSecurityProfileApply(t *testing.T) {
+	p := &SecurityProfile{
+		CapAdd:       []string{"NET_ADMIN", "SYS_ADMIN"},
+		Devices:      []string{"/dev/net/tun"},
+		SecurityOpts: []string{"apparmor=unconfined"},
+	}
+	cfg := &NodeConfig{
+		CapAdd:       []string{"SYS_ADMIN", "IPC_LOCK"},
+		SecurityOpts: []string{"label=disable"},
+	}
+
+	p.Apply(cfg)
+
+	want := &NodeConfig{
+		CapAdd:       []string{"NET_ADMIN", "SYS_ADMIN", "IPC_LOCK"},
+		Devices:      []string{"/dev/net/tun"},
+		SecurityOpts: []string{"apparmor=unconfined", "label=disable"},
+	}
+	if d := cmp.Diff(want, cfg); d != "" {
+		t.Errorf("Apply() mismatch (-want +got):\n%s", d)
+	}
+}
+
+func TestSecurityProfilePreflight(t *testing.T) {
+	dir := t.TempDir()
+
+	seccomp := filepath.Join(dir, "seccomp.json")
+	device := filepath.Join(dir, "device")
+	for _, f := range []string{seccomp, device} {
+		if err := os.WriteFile(f, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	apparmorEnabled := filepath.Join(dir, "apparmor-enabled")
+	apparmorProfiles := filepath.Join(dir, "apparmor-profiles")
+	if err := os.WriteFile(apparmorEnabled, []byte("Y\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(apparmorProfiles, []byte("clab-node (enforce)\ndocker-default (enforce)\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	origEnforce, origEnabled, origProfiles, origKVM := selinuxEnforceFile, apparmorEnabledFile, apparmorProfilesFile, kvmDevice
+	t.Cleanup(func() {
+		selinuxEnforceFile, apparmorEnabledFile, apparmorProfilesFile, kvmDevice = origEnforce, origEnabled, origProfiles, origKVM
+	})
+	selinuxEnforceFile = filepath.Join(dir, "no-selinux")
+	apparmorEnabledFile = apparmorEnabled
+	apparmorProfilesFile = apparmorProfiles
+	kvmDevice = filepath.Join(dir, "kvm")
+
+	tests := map[string]struct {
+		profile      *SecurityProfile
+		securityOpts []string
+		want         []string
+	}{
+		"no requirements": {
+			profile: NewSecurityProfile(),
+		},
+		"present device and loaded profiles": {
+			profile: &SecurityProfile{
+				Devices:      []string{device + ":/dev/device:rwm"},
+				SecurityOpts: []string{"apparmor=clab-node", "seccomp=" + seccomp},
+			},
+			securityOpts: []string{"label=disable", "seccomp=unconfined"},
+		},
+		"missing kvm device": {
+			profile: &SecurityProfile{KVM: true},
+			want:    []string{"device " + kvmDevice + " does not exist on the host"},
+		},
+		"missing profiles": {
+			profile: &SecurityProfile{
+				SecurityOpts: []string{"apparmor=missing"},
+			},
+			securityOpts: []string{"seccomp=/nonexistent.json", "label=type:spc_t"},
+			want: []string{
+				"AppArmor profile missing is not loaded on the host",
+				"seccomp profile /nonexistent.json does not exist",
+				"SELinux label type:spc_t is required, but SELinux is disabled on the host",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := tt.profile.Preflight(tt.securityOpts)
+			if d := cmp.Diff(tt.want, got); d != "" {
+				t.Errorf("Preflight() mismatch (-want +got):\n%s", d)
+			}
+		})
+	}
+}
+
+func TestSELinuxMode(t *testing.T) {
+	dir := t.TempDir()
+
+	orig := selinuxEnforceFile
+	t.Cleanup(func() { selinuxEnforceFile = orig })
+
+	tests := map[string]struct {
+		content *string
+		want    string
+	}{
+		"disabled":   {content: nil, want: "disabled"},
+		"enforcing":  {content: clabutils.Pointer("1"), want: "enforcing"},
+		"permissive": {content: clabutils.Pointer("0\n"), want: "permissive"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			selinuxEnforceFile = filepath.Join(dir, name)
+			if tt.content != nil {
+				if err := os.WriteFile(selinuxEnforceFile, []byte(*tt.content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if got := SELinuxMode(); got != tt.want {
+				t.Errorf("SELinuxMode() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -5,6 +5,7 @@ import "time"
 // Settings is the structure for global containerlab settings.
 type Settings struct {
 	CertificateAuthority *CertificateAuthority `yaml:"certificate-authority"`
+	SSHConfig            *SSHConfigSettings    `yaml:"ssh-config"`
 }
 
 // CertificateAuthority is the structure for global containerlab certificate authority settings.
@@ -20,3 +21,28 @@ type CertificateAuthority struct {
 	// when containerlab is in charge of the CA generation.
 	ValidityDuration time.Duration `yaml:"validity-duration"`
 }
+
+// SSHConfigSettings is the structure for the settings of the generated SSH client config.
+type SSHConfigSettings struct {
+	// ProxyJump is the jump host (user@host:port) the SSH client uses to reach the lab nodes.
+	ProxyJump string `yaml:"proxy-jump"`
+	// UserConfig enables the installation of the lab SSH config
+	// into the ~/.ssh/config.d directory of the user running containerlab.
+	UserConfig bool `yaml:"user-config"`
+}
+
+// GetProxyJump returns the configured proxy jump host.
+func (s *SSHConfigSettings) GetProxyJump() string {
+	if s == nil {
+		return ""
+	}
+	return s.ProxyJump
+}
+
+// GetUserConfig returns true if the ssh config should be installed for the user.
+func (s *SSHConfigSettings) GetUserConfig() bool {
+	if s == nil {
+		return false
+	}
+	return s.UserConfig
+}
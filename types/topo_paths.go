@@ -13,17 +13,28 @@ import (
 const (
 	ansibleInventoryFileName      = "ansible-inventory.yml"
 	nornirSimpleInventoryFileName = "nornir-simple-inventory.yml"
+	suzieqInventoryFileName       = "suzieq-inventory.yml"
 	topologyExportDatFileName     = "topology-data.json"
+	factsFileName                 = "facts.json"
+	labStateFileName              = "lab-state.json"
+	hostRoutesFileName            = "host-routes.json"
+	aaaServerFileName             = "aaa-server.json"
+	netsvcFileName                = "netsvc.json"
+	imageLockFileSuffix           = ".lock.yml"
 	authzKeysFileName             = "authorized_keys"
 	tlsDir                        = ".tls"
 	caDir                         = "ca"
 	graph                         = "graph"
 	labDirPrefix                  = "clab-"
 	backupDirName                 = "bak"
+	deployLocksDirName            = "deploy"
+	deployLockFileSuffix          = ".pid"
 	CertFileSuffix                = ".pem"
 	KeyFileSuffix                 = ".key"
 	CSRFileSuffix                 = ".csr"
 	sshConfigFilePathTmpl         = "/etc/ssh/ssh_config.d/clab-%s.conf"
+	userSSHConfigFilePathTmpl     = "~/.ssh/config.d/clab-%s.conf"
+	userKnownHostsFilePathTmpl    = "~/.ssh/clab-%s.known_hosts"
 )
 
 // clabTmpDir is the directory where clab stores temporary and/or downloaded files.
@@ -144,6 +155,18 @@ func (t *TopoPaths) SSHConfigPath() string {
 	return fmt.Sprintf(sshConfigFilePathTmpl, t.topoName)
 }
 
+// UserSSHConfigPath returns the topology dependent ssh config file name
+// in the ssh config directory of the user running containerlab.
+func (t *TopoPaths) UserSSHConfigPath() string {
+	return clabutils.ExpandHome(fmt.Sprintf(userSSHConfigFilePathTmpl, t.topoName))
+}
+
+// UserKnownHostsPath returns the topology dependent known_hosts file name
+// in the ssh directory of the user running containerlab.
+func (t *TopoPaths) UserKnownHostsPath() string {
+	return clabutils.ExpandHome(fmt.Sprintf(userKnownHostsFilePathTmpl, t.topoName))
+}
+
 // TLSBaseDir returns the path of the TLS directory structure.
 func (t *TopoPaths) TLSBaseDir() string {
 	return filepath.Join(t.labDir, tlsDir)
@@ -184,6 +207,32 @@ func (t *TopoPaths) TopoExportFile() string {
 	return filepath.Join(t.labDir, topologyExportDatFileName)
 }
 
+// FactsFileAbsPath returns the path of the file with the runtime facts of the lab nodes.
+func (t *TopoPaths) FactsFileAbsPath() string {
+	return filepath.Join(t.labDir, factsFileName)
+}
+
+// LabStateFileAbsPath returns the path of the file with the recorded state of the deployed lab.
+func (t *TopoPaths) LabStateFileAbsPath() string {
+	return filepath.Join(t.labDir, labStateFileName)
+}
+
+// HostRoutesFileAbsPath returns the path of the file with the host routes installed by the lab
+// and the previous values of the kernel parameters changed for them.
+func (t *TopoPaths) HostRoutesFileAbsPath() string {
+	return filepath.Join(t.labDir, hostRoutesFileName)
+}
+
+// AAAServerFileAbsPath returns the path of the file with the AAA server started with tools aaa.
+func (t *TopoPaths) AAAServerFileAbsPath() string {
+	return filepath.Join(t.labDir, aaaServerFileName)
+}
+
+// NetSvcFileAbsPath returns the path of the file with the NTP and DNS service container started with tools netsvc.
+func (t *TopoPaths) NetSvcFileAbsPath() string {
+	return filepath.Join(t.labDir, netsvcFileName)
+}
+
 // AnsibleInventoryFileAbsPath returns the absolute path to the ansible-inventory file.
 func (t *TopoPaths) AnsibleInventoryFileAbsPath() string {
 	return filepath.Join(t.labDir, ansibleInventoryFileName)
@@ -194,6 +243,11 @@ func (t *TopoPaths) NornirSimpleInventoryFileAbsPath() string {
 	return filepath.Join(t.labDir, nornirSimpleInventoryFileName)
 }
 
+// SuzieqInventoryFileAbsPath returns the absolute path to the SuzieQ inventory file.
+func (t *TopoPaths) SuzieqInventoryFileAbsPath() string {
+	return filepath.Join(t.labDir, suzieqInventoryFileName)
+}
+
 // TopologyFilenameAbsPath returns the absolute path to the topology file.
 func (t *TopoPaths) TopologyFilenameAbsPath() string {
 	return t.topoFile
@@ -214,6 +268,16 @@ func (*TopoPaths) ClabTmpDir() string {
 	return clabTmpDir
 }
 
+// DeployLocksDir returns the directory of the files marking the lab deployments in progress.
+func DeployLocksDir() string {
+	return filepath.Join(clabTmpDir, deployLocksDirName)
+}
+
+// DeployLockFileAbsPath returns the path of the file marking the deployment of the lab in progress.
+func DeployLockFileAbsPath(lab string) string {
+	return filepath.Join(DeployLocksDir(), lab+deployLockFileSuffix)
+}
+
 // ClabBakDir returns the absolute path to the directory where clab stores backup files.
 // Creates the directory if it does not exist.
 func (t *TopoPaths) ClabBakDir() string {
@@ -266,6 +330,12 @@ func (t *TopoPaths) TopologyFileDir() string {
 	return filepath.Dir(t.topoFile)
 }
 
+// ImageLockFileAbsPath returns the path of the image lock file of the lab,
+// kept next to the topology file to be versioned with it.
+func (t *TopoPaths) ImageLockFileAbsPath() string {
+	return filepath.Join(t.TopologyFileDir(), t.TopologyFilenameWithoutExt()+imageLockFileSuffix)
+}
+
 // TopologyLabDir returns the lab directory.
 func (t *TopoPaths) TopologyLabDir() string {
 	return t.labDir
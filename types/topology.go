@@ -15,6 +15,8 @@ type Topology struct {
 	Nodes    map[string]*NodeDefinition  `yaml:"nodes,omitempty"`
 	Links    []*clablinks.LinkDefinition `yaml:"links,omitempty"`
 	Groups   map[string]*NodeDefinition  `yaml:"groups,omitempty"`
+	// External are the devices outside of the lab, configured in the config phase only
+	External map[string]*ExternalNode `yaml:"external,omitempty"`
 }
 
 // NewTopology creates a new Topology instance with initialized fields.
@@ -199,8 +201,42 @@ func (t *Topology) GetNodeConfigDispatcher(name string) *ConfigDispatcher {
 			t.GetGroup(t.GetNodeGroup(name)).GetConfigDispatcher().GetVars(),
 			ndef.GetConfigDispatcher().GetVars())
 
+		var verify []*VerifyCheck
+		var tests []*ConfigTest
+		// the most specific templates list, version constraints and expect scripts are used
+		var templates []string
+		var minVersion, version string
+		var expect *ExpectScripts
+		for _, cd := range []*ConfigDispatcher{
+			t.Defaults.GetConfigDispatcher(),
+			t.GetKind(t.GetNodeKind(name)).GetConfigDispatcher(),
+			t.GetGroup(t.GetNodeGroup(name)).GetConfigDispatcher(),
+			ndef.GetConfigDispatcher(),
+		} {
+			verify = append(verify, cd.GetVerify()...)
+			tests = append(tests, cd.GetTests()...)
+			if len(cd.GetTemplates()) > 0 {
+				templates = cd.GetTemplates()
+			}
+			if cd.GetMinVersion() != "" {
+				minVersion = cd.GetMinVersion()
+			}
+			if cd.GetVersion() != "" {
+				version = cd.GetVersion()
+			}
+			if cd.GetExpect() != nil {
+				expect = cd.GetExpect()
+			}
+		}
+
 		return &ConfigDispatcher{
-			Vars: vars,
+			Vars:       vars,
+			Verify:     verify,
+			Tests:      tests,
+			Templates:  templates,
+			MinVersion: minVersion,
+			Version:    version,
+			Expect:     expect,
 		}
 	}
 
@@ -229,6 +265,17 @@ func (t *Topology) GetNodeCapAdd(name string) []string {
 	return nil
 }
 
+func (t *Topology) GetNodeSecurityOpts(name string) []string {
+	if ndef, ok := t.Nodes[name]; ok {
+		return clabutils.MergeStringSlices(
+			clabutils.MergeStringSlices(t.GetDefaults().GetSecurityOpts(),
+				t.GetKind(t.GetNodeKind(name)).GetSecurityOpts(),
+				t.GetGroup(t.GetNodeGroup(name)).GetSecurityOpts()),
+			ndef.GetSecurityOpts())
+	}
+	return nil
+}
+
 func (t *Topology) GetNodeShmSize(name string) string {
 	if ndef, ok := t.Nodes[name]; ok {
 		if v := ndef.GetNodeShmSize(); v != "" {
@@ -343,6 +390,24 @@ func (t *Topology) GetNodeAutoRemove(name string) bool {
 	return false
 }
 
+func (t *Topology) GetNodeMaintenance(name string) bool {
+	if ndef, ok := t.Nodes[name]; ok {
+		if v := ndef.GetMaintenance(); v != nil {
+			return *v
+		}
+		if v := t.GetGroup(t.GetNodeGroup(name)).GetMaintenance(); v != nil {
+			return *v
+		}
+		if v := t.GetKind(t.GetNodeKind(name)).GetMaintenance(); v != nil {
+			return *v
+		}
+	}
+	if v := t.GetDefaults().GetMaintenance(); v != nil {
+		return *v
+	}
+	return false
+}
+
 func (t *Topology) GetRestartPolicy(name string) string {
 	if ndef, ok := t.Nodes[name]; ok {
 		if l := ndef.GetRestartPolicy(); l != "" {
@@ -824,6 +824,76 @@ func TestGetNodeAutoRemove(t *testing.T) {
 	}
 }
 
+func TestGetNodeMaintenance(t *testing.T) {
+	tests := map[string]struct {
+		topo *Topology
+		want bool
+	}{
+		"not set": {
+			topo: &Topology{
+				Nodes: map[string]*NodeDefinition{"node1": {Kind: "nokia_srlinux"}},
+			},
+		},
+		"node": {
+			topo: &Topology{
+				Nodes: map[string]*NodeDefinition{
+					"node1": {Kind: "nokia_srlinux", Maintenance: clabutils.Pointer(true)},
+				},
+			},
+			want: true,
+		},
+		"group": {
+			topo: &Topology{
+				Groups: map[string]*NodeDefinition{"spines": {Maintenance: clabutils.Pointer(true)}},
+				Nodes: map[string]*NodeDefinition{
+					"node1": {Kind: "nokia_srlinux", Group: "spines"},
+				},
+			},
+			want: true,
+		},
+		"kind": {
+			topo: &Topology{
+				Kinds: map[string]*NodeDefinition{"nokia_srlinux": {Maintenance: clabutils.Pointer(true)}},
+				Nodes: map[string]*NodeDefinition{"node1": {Kind: "nokia_srlinux"}},
+			},
+			want: true,
+		},
+		"defaults": {
+			topo: &Topology{
+				Defaults: &NodeDefinition{Maintenance: clabutils.Pointer(true)},
+				Nodes:    map[string]*NodeDefinition{"node1": {Kind: "nokia_srlinux"}},
+			},
+			want: true,
+		},
+		"node overrides kind and defaults": {
+			topo: &Topology{
+				Defaults: &NodeDefinition{Maintenance: clabutils.Pointer(true)},
+				Kinds:    map[string]*NodeDefinition{"nokia_srlinux": {Maintenance: clabutils.Pointer(true)}},
+				Nodes: map[string]*NodeDefinition{
+					"node1": {Kind: "nokia_srlinux", Maintenance: clabutils.Pointer(false)},
+				},
+			},
+		},
+		"group overrides kind": {
+			topo: &Topology{
+				Groups: map[string]*NodeDefinition{"spines": {Maintenance: clabutils.Pointer(false)}},
+				Kinds:  map[string]*NodeDefinition{"nokia_srlinux": {Maintenance: clabutils.Pointer(true)}},
+				Nodes: map[string]*NodeDefinition{
+					"node1": {Kind: "nokia_srlinux", Group: "spines"},
+				},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.topo.GetNodeMaintenance("node1"); got != tt.want {
+				t.Errorf("GetNodeMaintenance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestGetNodeDNS(t *testing.T) {
 	for name, item := range topologyTestSet {
 		t.Logf("%q test item", name)
@@ -852,3 +922,30 @@ func TestGetNodeCertificateConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestGetNodeConfigDispatcherTemplates(t *testing.T) {
+	topo := &Topology{
+		Defaults: &NodeDefinition{Config: &ConfigDispatcher{Templates: []string{"base"}}},
+		Kinds: map[string]*NodeDefinition{
+			"nokia_srlinux": {Config: &ConfigDispatcher{Templates: []string{"base", "ifaces"}}},
+		},
+		Nodes: map[string]*NodeDefinition{
+			"leaf1": {Kind: "nokia_srlinux"},
+			"rs1":   {Kind: "nokia_srlinux", Config: &ConfigDispatcher{Templates: []string{"route-server"}}},
+			"host1": {Kind: "linux"},
+		},
+	}
+
+	tests := map[string][]string{
+		"leaf1": {"base", "ifaces"},
+		"rs1":   {"route-server"},
+		"host1": {"base"},
+	}
+
+	for node, want := range tests {
+		got := topo.GetNodeConfigDispatcher(node).GetTemplates()
+		if d := cmp.Diff(want, got); d != "" {
+			t.Errorf("templates of %s mismatch (-want +got):\n%s", node, d)
+		}
+	}
+}
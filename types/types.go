@@ -6,6 +6,7 @@ package types
 
 import (
 	"fmt"
+	"net/netip"
 	"strings"
 	"time"
 
@@ -43,6 +44,20 @@ func (e *Endpoint) String() string {
 	return fmt.Sprintf("%s:%s", e.Node.ShortName, e.EndpointName)
 }
 
+// Modes of the management network.
+const (
+	// MgmtModeNAT masquerades the traffic of the nodes leaving the host, the default.
+	MgmtModeNAT = "nat"
+	// MgmtModeRouted routes the management subnet without NAT,
+	// the hosts outside reach the nodes with a route to the subnet via the containerlab host.
+	MgmtModeRouted = "routed"
+	// MgmtModeIsolated keeps the nodes off the networks outside of the host.
+	MgmtModeIsolated = "isolated"
+)
+
+// MgmtModes lists the supported management network modes.
+var MgmtModes = []string{MgmtModeNAT, MgmtModeRouted, MgmtModeIsolated}
+
 // MgmtNet struct defines the management network options.
 type MgmtNet struct {
 	Network string `yaml:"network,omitempty" json:"network,omitempty"` // container runtime network name
@@ -57,6 +72,22 @@ type MgmtNet struct {
 	MTU            int               `yaml:"mtu,omitempty" json:"mtu,omitempty"`
 	ExternalAccess *bool             `yaml:"external-access,omitempty" json:"external-access,omitempty"`
 	DriverOpts     map[string]string `yaml:"driver-opts,omitempty" json:"driver-opts,omitempty"`
+	// Mode is the management network mode, one of MgmtModes
+	Mode string `yaml:"mode,omitempty" json:"mode,omitempty"`
+	// Existing attaches the nodes to an existing runtime network, it is neither created nor removed
+	Existing bool `yaml:"existing,omitempty" json:"existing,omitempty"`
+	// HostRoutes are the routes installed on the host toward the lab prefixes
+	HostRoutes []*HostRoute `yaml:"host-routes,omitempty" json:"host-routes,omitempty"`
+	// ProxyARP enables proxy ARP and NDP on the management bridge
+	ProxyARP bool `yaml:"proxy-arp,omitempty" json:"proxy-arp,omitempty"`
+}
+
+// HostRoute is a route installed on the host toward a lab prefix,
+// e.g. the loopbacks of the nodes, via the management address of the gateway node.
+type HostRoute struct {
+	Prefix string `yaml:"prefix" json:"prefix"`
+	// Via is the name of the gateway node
+	Via string `yaml:"via" json:"via"`
 }
 
 // Interface compliance.
@@ -118,6 +149,8 @@ type NodeConfig struct {
 	// when set to true will auto-remove a stopped/failed container
 	AutoRemove    bool   `json:"auto-remove,omitempty"`
 	RestartPolicy string `json:"restart-policy,omitempty"`
+	// when set to true the node is in maintenance, it keeps running but is left out of the config runs
+	Maintenance bool `json:"maintenance,omitempty"`
 	// path to config file that is actually mounted to the container and is a result of templation
 	ResStartupConfig string            `json:"startup-config-abs-path,omitempty"`
 	Config           *ConfigDispatcher `json:"config,omitempty"`
@@ -139,6 +172,8 @@ type NodeConfig struct {
 	Devices []string `json:"devices,omitempty"`
 	// Capabilities required by the container (if not run in privileged mode)
 	CapAdd []string `json:"cap-add,omitempty"`
+	// Security options of the container, e.g. the seccomp and AppArmor profiles or SELinux labels
+	SecurityOpts []string `json:"security-opts,omitempty"`
 	// Size of the shared memory allocated to the container
 	ShmSize string `json:"shm-size,omitempty"`
 	// PortBindings define the bindings between the container ports and host ports
@@ -219,6 +254,7 @@ func (n *NodeConfig) Copy() *NodeConfig {
 	copyConfig.Binds = clabutils.CopySlice(n.Binds)
 	copyConfig.Devices = clabutils.CopySlice(n.Devices)
 	copyConfig.CapAdd = clabutils.CopySlice(n.CapAdd)
+	copyConfig.SecurityOpts = clabutils.CopySlice(n.SecurityOpts)
 	copyConfig.Aliases = clabutils.CopySlice(n.Aliases)
 	copyConfig.ExtraHosts = clabutils.CopySlice(n.ExtraHosts)
 	copyConfig.ResultingPortBindings = clabutils.CopyObjectSlice(n.ResultingPortBindings)
@@ -247,6 +283,39 @@ type GenericFilter struct {
 // after they started.
 type ConfigDispatcher struct {
 	Vars map[string]interface{} `yaml:"vars,omitempty"`
+	// Verify is a list of state checks performed after the configuration is applied
+	Verify []*VerifyCheck `yaml:"verify,omitempty"`
+	// Tests is a list of command output assertions performed after the configuration is applied
+	Tests []*ConfigTest `yaml:"tests,omitempty"`
+	// Templates is the list of template names rendered for the node,
+	// overrides the list of templates set for the config run
+	Templates []string `yaml:"templates,omitempty"`
+	// MinVersion is the oldest software version of the node the templates support, e.g. 24.3
+	MinVersion string `yaml:"min-version,omitempty"`
+	// Version is the software release the templates target, e.g. 24.3 matches 24.3.1 and 24.3.2
+	Version string `yaml:"version,omitempty"`
+	// Expect are the expect scripts run in the SSH session writing the config,
+	// for the bootstrap flows of the images the SSH kind doesn't handle
+	Expect *ExpectScripts `yaml:"expect,omitempty"`
+}
+
+// ExpectScripts are the expect scripts run in the SSH session writing the config of a node.
+type ExpectScripts struct {
+	// Before is run after the login, before the config is written
+	Before []*ExpectStep `yaml:"before,omitempty"`
+	// After is run after the config is committed
+	After []*ExpectStep `yaml:"after,omitempty"`
+}
+
+// ExpectStep is a step of an expect script, the line sent to the node
+// and the output of the node awaited before the next step.
+type ExpectStep struct {
+	// Send is the line sent to the node, nothing is sent when empty
+	Send string `yaml:"send,omitempty"`
+	// Expect is the regexp the output of the node must match, the step doesn't wait when empty
+	Expect string `yaml:"expect,omitempty"`
+	// Timeout is the time to wait for the expected output, e.g. 30s, 10s by default
+	Timeout string `yaml:"timeout,omitempty"`
 }
 
 func (cd *ConfigDispatcher) GetVars() map[string]interface{} {
@@ -256,6 +325,106 @@ func (cd *ConfigDispatcher) GetVars() map[string]interface{} {
 	return cd.Vars
 }
 
+func (cd *ConfigDispatcher) GetVerify() []*VerifyCheck {
+	if cd == nil {
+		return nil
+	}
+	return cd.Verify
+}
+
+func (cd *ConfigDispatcher) GetTests() []*ConfigTest {
+	if cd == nil {
+		return nil
+	}
+	return cd.Tests
+}
+
+func (cd *ConfigDispatcher) GetMinVersion() string {
+	if cd == nil {
+		return ""
+	}
+	return cd.MinVersion
+}
+
+func (cd *ConfigDispatcher) GetVersion() string {
+	if cd == nil {
+		return ""
+	}
+	return cd.Version
+}
+
+func (cd *ConfigDispatcher) GetTemplates() []string {
+	if cd == nil {
+		return nil
+	}
+	return cd.Templates
+}
+
+func (cd *ConfigDispatcher) GetExpect() *ExpectScripts {
+	if cd == nil {
+		return nil
+	}
+	return cd.Expect
+}
+
+// ExternalNode is a device outside of the lab, e.g. a physical switch the lab connects to.
+// No container is created for it, it only takes part in the config phase.
+type ExternalNode struct {
+	Kind string `yaml:"kind,omitempty"`
+	// Address is the management address or host name the device is reached at
+	Address string `yaml:"address,omitempty"`
+	// Username and Password default to the credentials of the kind
+	Username string            `yaml:"username,omitempty"`
+	Password string            `yaml:"password,omitempty"`
+	Labels   map[string]string `yaml:"labels,omitempty"`
+	Config   *ConfigDispatcher `yaml:"config,omitempty"`
+}
+
+// VerifyCheck is a state check, the value of the gNMI path should converge
+// to the expected value.
+// Checks with a show command verify a field of the records parsed from the command output instead.
+type VerifyCheck struct {
+	// Path is the gNMI path of a leaf, e.g. /interface[name=ethernet-1/1]/oper-state.
+	// For show checks it is the field of a record, e.g. oper_state[port=ethernet-1/1]
+	Path string `yaml:"path"`
+	// Value is the expected value of the leaf
+	Value string `yaml:"value"`
+	// Command is the show command whose output is parsed into records
+	Command string `yaml:"command,omitempty"`
+	// Template is a regexp with named groups parsing every line of the command output
+	// into a record, defaults to the built-in template of the kind for the command
+	Template string `yaml:"template,omitempty"`
+}
+
+// ConfigTest is an assertion on the output of a command run on the node after the configuration is applied.
+type ConfigTest struct {
+	// Name of the test in the reports, defaults to the command or the getter
+	Name string `yaml:"name,omitempty"`
+	// Command is the show command run on the node
+	Command string `yaml:"command,omitempty"`
+	// Getter is the NAPALM getter run instead of the command, e.g. get_bgp_neighbors,
+	// its result is asserted as JSON output
+	Getter string `yaml:"getter,omitempty"`
+	// Expect is the assertion on the command output
+	Expect *TestExpect `yaml:"expect"`
+	// Retries is the number of times the command is run again until the assertion holds
+	Retries int `yaml:"retries,omitempty"`
+	// Interval is the time between the retries, default: 1s
+	Interval time.Duration `yaml:"interval,omitempty"`
+}
+
+// TestExpect is the assertion of a test, all the set fields must hold.
+type TestExpect struct {
+	// Contains is a string the output contains
+	Contains string `yaml:"contains,omitempty"`
+	// Regex is a regexp matching the output
+	Regex string `yaml:"regex,omitempty"`
+	// JSONPath selects a value of the JSON output, e.g. interfaces[0].oper-state
+	JSONPath string `yaml:"json-path,omitempty"`
+	// Value is the expected value selected by JSONPath, any value is accepted when empty
+	Value string `yaml:"value,omitempty"`
+}
+
 // Extras contains extra node parameters which are not entitled to be part of a generic node config.
 type Extras struct {
 	// Nokia SR Linux agents. As of now just the agents spec files can be provided here
@@ -266,6 +435,8 @@ type Extras struct {
 	CeosCopyToFlash []string `yaml:"ceos-copy-to-flash,omitempty"`
 	// k8s-kind node specific options
 	K8sKind *K8sKindExtras `yaml:"k8s_kind,omitempty"`
+	// route-injector node specific options
+	RouteInjector *RouteInjectorExtras `yaml:"route-injector,omitempty"`
 }
 
 func (e *Extras) Copy() *Extras {
@@ -286,6 +457,7 @@ func (e *Extras) Copy() *Extras {
 		MysocketProxy:   e.MysocketProxy,
 		CeosCopyToFlash: ceosCopyToFlashCopy,
 		K8sKind:         k8sKindCopy,
+		RouteInjector:   e.RouteInjector.Copy(),
 	}
 }
 
@@ -313,6 +485,86 @@ func (k *K8sKindDeployExtras) Copy() *K8sKindDeployExtras {
 	return &cp
 }
 
+// RouteInjectorExtras represents the route-injector-specific extra options,
+// the BGP sessions of the injector and the routes advertised over them.
+type RouteInjectorExtras struct {
+	ASN uint32 `yaml:"asn"`
+	// RouterID defaults to the first IPv4 address of the peers
+	RouterID string                 `yaml:"router-id,omitempty"`
+	Peers    []*RouteInjectorPeer   `yaml:"peers,omitempty"`
+	Routes   []*RouteInjectorRoutes `yaml:"routes,omitempty"`
+}
+
+// GetRouterID returns the router ID of the injector,
+// the first IPv4 address of the peers when not set.
+func (r *RouteInjectorExtras) GetRouterID() string {
+	if r.RouterID != "" {
+		return r.RouterID
+	}
+
+	for _, p := range r.Peers {
+		if pfx, err := netip.ParsePrefix(p.Address); err == nil && pfx.Addr().Is4() {
+			return pfx.Addr().String()
+		}
+	}
+
+	return ""
+}
+
+func (r *RouteInjectorExtras) Copy() *RouteInjectorExtras {
+	if r == nil {
+		return nil
+	}
+
+	cp := &RouteInjectorExtras{
+		ASN:      r.ASN,
+		RouterID: r.RouterID,
+	}
+	for _, p := range r.Peers {
+		pc := *p
+		cp.Peers = append(cp.Peers, &pc)
+	}
+	for _, rt := range r.Routes {
+		rc := *rt
+		rc.ASPath = append([]uint32(nil), rt.ASPath...)
+		rc.Communities = append([]string(nil), rt.Communities...)
+		cp.Routes = append(cp.Routes, &rc)
+	}
+
+	return cp
+}
+
+// RouteInjectorPeer is a BGP session of the route injector with a lab node.
+type RouteInjectorPeer struct {
+	// Interface is the interface of the injector toward the peer
+	Interface string `yaml:"interface"`
+	// Address is the address with the prefix length set on the interface, e.g. 10.0.0.0/31
+	Address string `yaml:"address"`
+	// Neighbor is the address of the peer
+	Neighbor string `yaml:"neighbor"`
+	ASN      uint32 `yaml:"asn"`
+}
+
+// Family returns the address family of the session, ipv4 or ipv6.
+func (p *RouteInjectorPeer) Family() string {
+	if a, err := netip.ParseAddr(p.Neighbor); err == nil && a.Is6() {
+		return "ipv6"
+	}
+	return "ipv4"
+}
+
+// RouteInjectorRoutes is a block of consecutive prefixes advertised by the route injector.
+type RouteInjectorRoutes struct {
+	// Prefix is the first prefix of the block
+	Prefix string `yaml:"prefix"`
+	// Count is the number of prefixes of the block, 1 when not set
+	Count int `yaml:"count,omitempty"`
+	// ASPath is prepended to the AS path of the prefixes
+	ASPath []uint32 `yaml:"as-path,omitempty"`
+	// Communities are added to the prefixes, e.g. 65000:100
+	Communities []string `yaml:"communities,omitempty"`
+}
+
 // ContainerDetails contains information that is commonly outputted to tables or graphs.
 type ContainerDetails struct {
 	LabName     string `json:"lab_name,omitempty"`
@@ -342,6 +594,12 @@ type ContainerInterfaceDetails struct {
 	InterfaceMTU   int    `json:"mtu"`
 	InterfaceType  string `json:"type"`
 	InterfaceState string `json:"state"`
+	// PeerNode and PeerInterface are the far end of the link of the interface in the lab topology
+	PeerNode      string `json:"peer_node,omitempty"`
+	PeerInterface string `json:"peer_interface,omitempty"`
+	// Addresses are the IP addresses assigned to the interface by the lab,
+	// the management addresses and the link IPs of the config engine
+	Addresses []string `json:"addresses,omitempty"`
 }
 
 // ContainerInterfaces contains information about a container's network interfaces.
@@ -23,6 +23,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/minio/minio-go/v7"
@@ -320,6 +321,29 @@ func CreateDirectory(path string, perm os.FileMode) {
 	}
 }
 
+// CreateUserDirectory creates the directory and its missing parents with the permissions,
+// the created directories are handed over to the real user when containerlab runs with sudo.
+func CreateUserDirectory(path string, perm os.FileMode) error {
+	// the topmost missing directory holds all the created directories
+	var top string
+	for p := filepath.Clean(path); !FileOrDirExists(p); p = filepath.Dir(p) {
+		top = p
+		if p == filepath.Dir(p) {
+			break
+		}
+	}
+
+	if err := os.MkdirAll(path, perm); err != nil {
+		return err
+	}
+
+	if top == "" {
+		return nil
+	}
+
+	return SetUIDAndGID(top)
+}
+
 func ReadFileContent(file string) ([]byte, error) {
 	// try to read and return file content, or return an error
 	b, err := os.ReadFile(file)
@@ -385,6 +409,29 @@ func lookupUserHomeDirViaGetent(userId string) string {
 	return parts[5]
 }
 
+// FileOwner returns the name of the user owning the file, the user id when the user is unknown.
+func FileOwner(path string) (string, error) {
+	fi, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return "", fmt.Errorf("failed to read the owner of %s", path)
+	}
+
+	uid := strconv.FormatUint(uint64(st.Uid), 10)
+
+	u, err := user.LookupId(uid)
+	if err != nil {
+		log.Debugf("error while looking up user by id using os/user.LookupId %v: %v", uid, err)
+		return uid, nil
+	}
+
+	return u.Username, nil
+}
+
 // ResolvePath resolves a string path by expanding `~` to home dir
 // or resolving a relative path by joining it with the base path.
 // When resolving `~` the function uses the home dir of a sudo user, so that -E sudo
@@ -32,6 +32,11 @@ func BridgeByName(name string) (*netlink.Bridge, error) {
 // LinkContainerNS creates a symlink for containers network namespace
 // so that it can be managed by iproute2 utility.
 func LinkContainerNS(nspath, containerName string) error {
+	// the netns directory is not writable in the rootless mode
+	if IsRootless() {
+		return nil
+	}
+
 	CreateDirectory("/run/netns/", 0o755)
 	dst := "/run/netns/" + containerName
 	if _, err := os.Lstat(dst); err == nil {
@@ -1,6 +1,9 @@
 package utils
 
-import "syscall"
+import (
+	"errors"
+	"syscall"
+)
 
 // PauseProcessGroup sends the SIGSTOP signal to a process group, causing all
 // the processes within the group to be Paused e.g. SRL runs multilpe processes, if the
@@ -15,3 +18,12 @@ func PauseProcessGroup(pgid int) error {
 func UnpauseProcessGroup(pgid int) error {
 	return syscall.Kill(-pgid, syscall.SIGCONT)
 }
+
+// ProcessExists returns true if a process with the given PID exists.
+func ProcessExists(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	err := syscall.Kill(pid, 0)
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,90 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/charmbracelet/log"
+)
+
+const (
+	// RootlessEnv enables the rootless mode when set to true.
+	RootlessEnv = "CLAB_ROOTLESS"
+	// rootlessNamespaceEnv is set for the containerlab process re-executed
+	// in the namespaces of rootless podman.
+	rootlessNamespaceEnv = "CLAB_ROOTLESS_NAMESPACE"
+)
+
+// rootless is the rootless mode of the process, set with SetRootless.
+var rootless bool
+
+// SetRootless sets the rootless mode of the process.
+func SetRootless(b bool) {
+	rootless = b
+}
+
+// IsRootless returns true if containerlab runs in the rootless mode.
+func IsRootless() bool {
+	return rootless
+}
+
+// RootlessFromEnv returns true if the rootless mode is enabled with the CLAB_ROOTLESS env.
+func RootlessFromEnv() bool {
+	b, _ := strconv.ParseBool(os.Getenv(RootlessEnv))
+	return b
+}
+
+// InRootlessNamespace returns true if containerlab runs in the user and network
+// namespaces of rootless podman.
+func InRootlessNamespace() bool {
+	return os.Getenv(rootlessNamespaceEnv) == "1"
+}
+
+// RootlessPodmanSocket returns the path of the podman socket of the user.
+func RootlessPodmanSocket() string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = fmt.Sprintf("/run/user/%d", os.Getuid())
+	}
+
+	return filepath.Join(dir, "podman", "podman.sock")
+}
+
+// ReexecInRootlessNamespace re-executes containerlab with its arguments in the user namespace
+// and the rootless network namespace of podman with `podman unshare --rootless-netns`.
+// In these namespaces the user owns the network namespaces of the containers and of the management
+// network, so that the veth links are created and moved without root. The user namespace is set
+// up by the newuidmap and newgidmap helpers podman relies on.
+// The rootless mode and the runtime are passed to the re-executed process explicitly,
+// replacing the values inherited from the environment.
+// On success it does not return.
+func ReexecInRootlessNamespace(runtime string) error {
+	podman, err := exec.LookPath("podman")
+	if err != nil {
+		return fmt.Errorf("the rootless mode requires podman: %w", err)
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	args := append([]string{"podman", "unshare", "--rootless-netns", self}, os.Args[1:]...)
+	var env []string
+	for _, e := range os.Environ() {
+		k, _, _ := strings.Cut(e, "=")
+		if k != RootlessEnv && k != rootlessNamespaceEnv && k != "CLAB_RUNTIME" {
+			env = append(env, e)
+		}
+	}
+	env = append(env, RootlessEnv+"=true", rootlessNamespaceEnv+"=1", "CLAB_RUNTIME="+runtime)
+
+	log.Debug("Entering the rootless podman namespaces", "cmd", args)
+
+	return syscall.Exec(podman, args, env) // skipcq: GSC-G204
+}
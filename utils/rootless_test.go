@@ -0,0 +1,60 @@
+// Copyright 2020 Nokia
+// Licensed under the BSD 3-Clause License.
+// SPDX-License-Identifier: BSD-3-Clause
+
+package utils
+
+import (
+	"fmt"
+	"os"
+	"testing"
+)
+
+func TestRootlessFromEnv(t *testing.T) {
+	tests := map[string]struct {
+		env  string
+		want bool
+	}{
+		"unset": {env: "", want: false},
+		"true":  {env: "true", want: true},
+		"one":   {env: "1", want: true},
+		"false": {env: "false", want: false},
+		"bogus": {env: "yes please", want: false},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(RootlessEnv, tt.env)
+
+			if got := RootlessFromEnv(); got != tt.want {
+				t.Errorf("RootlessFromEnv() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootlessPodmanSocket(t *testing.T) {
+	tests := map[string]struct {
+		runtimeDir string
+		want       string
+	}{
+		"xdg runtime dir": {
+			runtimeDir: "/run/user/1000",
+			want:       "/run/user/1000/podman/podman.sock",
+		},
+		"no xdg runtime dir": {
+			runtimeDir: "",
+			want:       fmt.Sprintf("/run/user/%d/podman/podman.sock", os.Getuid()),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("XDG_RUNTIME_DIR", tt.runtimeDir)
+
+			if got := RootlessPodmanSocket(); got != tt.want {
+				t.Errorf("RootlessPodmanSocket() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -17,6 +17,11 @@ const (
 )
 
 func CheckAndGetRootPrivs() error {
+	if IsRootless() {
+		log.Debug("Rootless mode, skipping root privilege escalation")
+		return nil
+	}
+
 	_, euid, suid := unix.Getresuid()
 	if euid != 0 && suid != 0 {
 		return fmt.Errorf("this containerlab command requires root privileges or root via SUID to run, effective UID: %v SUID: %v", euid, suid)